@@ -37,6 +37,10 @@ func init() {
 		EthereumCommand,
 		MaintainerCommand,
 		MaintainerCliCommand,
+		DebugCommand,
+		SignerCommand,
+		StorageCommand,
+		ExitCommand,
 	)
 }
 
@@ -0,0 +1,381 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/sortition"
+	"github.com/keep-network/keep-core/pkg/storage"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tbtcpg"
+)
+
+const misbehaviorEvidenceFileFlagName = "file"
+
+// DebugCommand contains the definition of tools allowing to inspect the
+// local state of the node.
+var DebugCommand = &cobra.Command{
+	Use:              "debug",
+	Short:            "Debug Tools",
+	Long:             "The tool exposes commands allowing to inspect the local state of the node.",
+	TraverseChildren: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			config.DebugCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+}
+
+var proposalStateCommand = cobra.Command{
+	Use:              "proposal-state",
+	Short:            "print proposal generation state",
+	Long:             proposalStateCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storage, err := storage.Initialize(
+			clientConfig.Storage,
+			clientConfig.Ethereum.KeyFilePassword,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot initialize storage: [%w]", err)
+		}
+
+		tbtcDataPersistence, err := storage.InitializeWorkPersistence("tbtc")
+		if err != nil {
+			return fmt.Errorf(
+				"cannot initialize tbtc data persistence: [%w]",
+				err,
+			)
+		}
+
+		proposalStateStore := tbtcpg.NewProposalStateStore(
+			tbtcDataPersistence,
+			tbtcpg.DefaultProposalStateCooldown,
+		)
+
+		if err := printProposalStateTable(
+			proposalStateStore.Items(),
+		); err != nil {
+			return fmt.Errorf("failed to print proposal state table: %v", err)
+		}
+
+		return nil
+	},
+}
+
+var proposalStateCommandDescription = "Prints deposits and redemption " +
+	"requests the node included in recent coordination proposals, along " +
+	"with the known outcome of their processing. Items with the pending " +
+	"outcome are not proposed again by the node until their cooldown period " +
+	"elapses."
+
+var misbehaviorEvidenceCommand = cobra.Command{
+	Use:              "misbehavior-evidence",
+	Short:            "export misbehavior evidence",
+	Long:             misbehaviorEvidenceCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString(misbehaviorEvidenceFileFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find file flag: %v", err)
+		}
+
+		storage, err := storage.Initialize(
+			clientConfig.Storage,
+			clientConfig.Ethereum.KeyFilePassword,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot initialize storage: [%w]", err)
+		}
+
+		tbtcDataPersistence, err := storage.InitializeWorkPersistence("tbtc")
+		if err != nil {
+			return fmt.Errorf(
+				"cannot initialize tbtc data persistence: [%w]",
+				err,
+			)
+		}
+
+		export, err := tbtc.ExportMisbehaviorEvidence(tbtcDataPersistence)
+		if err != nil {
+			return fmt.Errorf("cannot export misbehavior evidence: [%w]", err)
+		}
+
+		if file == "" {
+			fmt.Println(string(export))
+			return nil
+		}
+
+		if err := os.WriteFile(file, export, 0600); err != nil {
+			return fmt.Errorf("cannot write export to file: [%w]", err)
+		}
+
+		logger.Infof("misbehavior evidence exported to file [%s]", file)
+
+		return nil
+	},
+}
+
+var misbehaviorEvidenceCommandDescription = "Exports evidence of " +
+	"misbehavior of other group members observed by the node during DKG, " +
+	"DKG result publication, and signing. Each entry holds the accused " +
+	"member and operator, the reason, the marshaled protocol message " +
+	"proving the misbehavior, and the network envelope carrying the " +
+	"message signed by the accused member, if available. The node keeps " +
+	"the 1000 newest entries observed within the last 90 days. The " +
+	"evidence is exported as JSON to the given file or printed to the " +
+	"standard output."
+
+var walletsCommand = cobra.Command{
+	Use:              "wallets",
+	Short:            "list wallets the node holds signers for",
+	Long:             walletsCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		storage, err := storage.Initialize(
+			clientConfig.Storage,
+			clientConfig.Ethereum.KeyFilePassword,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot initialize storage: [%w]", err)
+		}
+
+		tbtcKeyStorePersistence, err := storage.InitializeKeyStorePersistence(
+			"tbtc",
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot initialize tbtc keystore persistence: [%w]",
+				err,
+			)
+		}
+
+		tbtcDataPersistence, err := storage.InitializeWorkPersistence("tbtc")
+		if err != nil {
+			return fmt.Errorf(
+				"cannot initialize tbtc data persistence: [%w]",
+				err,
+			)
+		}
+
+		_, tbtcChain, _, _, _, err := ethereum.Connect(
+			ctx,
+			clientConfig.Ethereum,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"could not connect to Ethereum chain: [%v]",
+				err,
+			)
+		}
+
+		wallets, err := tbtc.ListWallets(
+			tbtcKeyStorePersistence,
+			tbtcDataPersistence,
+			tbtcChain,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to list wallets: %v", err)
+		}
+
+		if err := printWalletsTable(wallets); err != nil {
+			return fmt.Errorf("failed to print wallets table: %v", err)
+		}
+
+		return nil
+	},
+}
+
+var walletsCommandDescription = "Lists wallets the node holds signers " +
+	"for, along with indexes of signing group members controlled by the " +
+	"node, the on-chain state of the wallet, the last action executed by " +
+	"the wallet, and the location of the wallet key material relative to " +
+	"the tbtc key store directory."
+
+var sortitionStatusCommand = cobra.Command{
+	Use:              "sortition-status",
+	Short:            "print operator status in sortition pools",
+	Long:             sortitionStatusCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		beaconChain, tbtcChain, _, _, _, err := ethereum.Connect(
+			ctx,
+			clientConfig.Ethereum,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"could not connect to Ethereum chain: [%v]",
+				err,
+			)
+		}
+
+		applications := []struct {
+			name  string
+			chain sortition.Chain
+		}{
+			{"beacon", beaconChain},
+			{"tbtc", tbtcChain},
+		}
+
+		names := make([]string, 0, len(applications))
+		statuses := make(map[string]*sortition.OperatorStatus)
+		for _, application := range applications {
+			status, err := sortition.GetOperatorStatus(
+				application.chain,
+				sortition.NewBetaOperatorPolicy(application.chain, logger),
+			)
+			if err != nil {
+				return fmt.Errorf(
+					"could not get operator status in [%s] sortition pool: [%w]",
+					application.name,
+					err,
+				)
+			}
+
+			names = append(names, application.name)
+			statuses[application.name] = status
+		}
+
+		if err := printSortitionStatusTable(
+			names,
+			statuses,
+		); err != nil {
+			return fmt.Errorf("failed to print sortition status table: %v", err)
+		}
+
+		return nil
+	},
+}
+
+var sortitionStatusCommandDescription = "Prints the status of the " +
+	"operator in the sortition pools of the random beacon and tBTC as read " +
+	"from the chain: whether the operator is registered for a staking " +
+	"provider, is in the pool, has the weight up to date with the eligible " +
+	"stake, and is eligible for rewards, along with the current weight and " +
+	"the next action of the node's pool monitoring. Actions other than " +
+	"registering the operator and authorizing the stake are taken by the " +
+	"running node. A running node additionally holds off joining the tBTC " +
+	"pool until its pre-parameters pool is filled. The status observed by " +
+	"the running node is exposed by the sortition_pools diagnostics source."
+
+func printSortitionStatusTable(
+	applications []string,
+	statuses map[string]*sortition.OperatorStatus,
+) error {
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "application\tregistered\tstaking provider\tin pool\tup to date\teligible for rewards\tweight\teligible stake\tnext action\t\n")
+
+	for _, application := range applications {
+		status := statuses[application]
+
+		stakingProvider, weight, eligibleStake := "", "", ""
+		if status.IsRegistered {
+			stakingProvider = status.StakingProvider.String()
+			weight = status.Weight.String()
+			eligibleStake = status.EligibleStake.String()
+		}
+
+		fmt.Fprintf(w, "%s\t%v\t%s\t%v\t%v\t%v\t%s\t%s\t%s\t\n",
+			application,
+			status.IsRegistered,
+			stakingProvider,
+			status.IsInPool,
+			status.IsUpToDate,
+			status.IsEligibleForRewards,
+			weight,
+			eligibleStake,
+			status.NextAction,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush the writer: %v", err)
+	}
+
+	return nil
+}
+
+func printProposalStateTable(items []*tbtcpg.ProposedItem) error {
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "type\twallet\tkey\tproposed at\toutcome\tupdated at\t\n")
+
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t0x%s\t0x%s\t%s\t%s\t%s\t\n",
+			item.Type,
+			item.WalletPublicKeyHash,
+			item.Key,
+			item.ProposedAt.UTC().Format("2006-01-02 15:04:05"),
+			item.Outcome,
+			item.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush the writer: %v", err)
+	}
+
+	return nil
+}
+
+func printWalletsTable(wallets []*tbtc.WalletInfo) error {
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "wallet\tmembers\tstate\tlast action\tlast action completed at\tlast action error\tkey material\t\n")
+
+	for _, wallet := range wallets {
+		lastAction, lastActionCompletedAt, lastActionError := "", "", ""
+		if wallet.LastAction != nil {
+			lastAction = wallet.LastAction.Type
+			lastActionCompletedAt = wallet.LastAction.CompletedAt.UTC().Format("2006-01-02 15:04:05")
+			lastActionError = wallet.LastAction.Error
+		}
+
+		fmt.Fprintf(w, "0x%x\t%v\t%s\t%s\t%s\t%s\t%s\t\n",
+			wallet.WalletPublicKeyHash,
+			wallet.MembersIndexes,
+			wallet.State,
+			lastAction,
+			lastActionCompletedAt,
+			lastActionError,
+			wallet.KeyMaterialLocation,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush the writer: %v", err)
+	}
+
+	return nil
+}
+
+func init() {
+	initFlags(
+		DebugCommand,
+		&configFilePath,
+		clientConfig,
+		config.DebugCategories...,
+	)
+
+	misbehaviorEvidenceCommand.Flags().String(
+		misbehaviorEvidenceFileFlagName,
+		"",
+		"path of the file the evidence is exported to",
+	)
+
+	DebugCommand.AddCommand(&proposalStateCommand)
+	DebugCommand.AddCommand(&misbehaviorEvidenceCommand)
+	DebugCommand.AddCommand(&walletsCommand)
+	DebugCommand.AddCommand(&sortitionStatusCommand)
+}
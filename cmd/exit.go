@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/admin"
+)
+
+const (
+	exitStatusOnlyFlagName    = "status"
+	exitPollIntervalFlagName  = "poll-interval"
+	defaultExitPollInterval   = 10 * time.Second
+	maxExitStatusPollFailures = 3
+)
+
+// ExitCommand contains the definition of the command coordinating the
+// operator exit from sortition pools of the running node.
+var ExitCommand = &cobra.Command{
+	Use:              "exit",
+	Short:            "Gracefully exits the operator from sortition pools",
+	Long:             exitCommandDescription,
+	TraverseChildren: true,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			config.ExitCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if clientConfig.Admin.Port == 0 {
+			return fmt.Errorf(
+				"admin API is not configured; the exit requires the " +
+					"admin.port of the running node",
+			)
+		}
+
+		statusOnly, err := cmd.Flags().GetBool(exitStatusOnlyFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find status flag: %v", err)
+		}
+
+		pollInterval, err := cmd.Flags().GetDuration(exitPollIntervalFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find poll interval flag: %v", err)
+		}
+
+		client := admin.NewClient(clientConfig.Admin.Port)
+
+		if statusOnly {
+			status, err := client.OperatorExitStatus()
+			if err != nil {
+				return fmt.Errorf("could not get exit status: [%w]", err)
+			}
+
+			printExitStatus(os.Stdout, status)
+			return nil
+		}
+
+		return exitOperator(client, pollInterval, os.Stdout)
+	},
+}
+
+var exitCommandDescription = "Coordinates a clean exit of the operator " +
+	"from sortition pools of the node running on the same machine, using " +
+	"its admin API. Once the exit is requested, the node stops joining " +
+	"sortition pools and new distributed key generations, while signing " +
+	"for existing wallets and groups continues. When in-flight protocols " +
+	"complete, the node takes the steps of the exit it is able to take on " +
+	"its own: it updates the operator status in the pools after the " +
+	"authorizer requests the authorization decrease and approves the " +
+	"decrease once its delay passes. The command reports the progress until " +
+	"the operator exits pools of all applications. The exit cannot be " +
+	"cancelled without restarting the node. The exit request is kept in " +
+	"the node's memory only, so it has to be repeated if the node restarts " +
+	"before the exit completes. Keep in mind that DKGs the " +
+	"operator is selected for before leaving the pools and does not join " +
+	"may make the operator ineligible for rewards."
+
+// exitOperator requests the operator exit and reports its progress until it is
+// completed. The progress is reported only when it changes.
+func exitOperator(
+	client *admin.Client,
+	pollInterval time.Duration,
+	output io.Writer,
+) error {
+	status, err := client.RequestOperatorExit()
+	if err != nil {
+		return fmt.Errorf("could not request operator exit: [%w]", err)
+	}
+
+	fmt.Fprintf(output, "operator exit requested at [%s]\n", status.RequestedAt)
+
+	lastReport := ""
+	failures := 0
+
+	for {
+		report := exitStatusReport(status)
+		if report != lastReport {
+			fmt.Fprint(output, report)
+			lastReport = report
+		}
+
+		if status.Completed {
+			fmt.Fprintln(output, "operator exit completed")
+			return nil
+		}
+
+		time.Sleep(pollInterval)
+
+		current, err := client.OperatorExitStatus()
+		if err != nil {
+			failures++
+			if failures >= maxExitStatusPollFailures {
+				return fmt.Errorf("could not get exit status: [%w]", err)
+			}
+
+			fmt.Fprintf(output, "could not get exit status: [%v]\n", err)
+			continue
+		}
+
+		failures = 0
+		status = current
+	}
+}
+
+func printExitStatus(output io.Writer, status *admin.OperatorExitStatus) {
+	if !status.Requested {
+		fmt.Fprintln(output, "operator exit not requested")
+		return
+	}
+
+	fmt.Fprintf(output, "operator exit requested at [%s]\n", status.RequestedAt)
+	fmt.Fprint(output, exitStatusReport(status))
+
+	if status.Completed {
+		fmt.Fprintln(output, "operator exit completed")
+	}
+}
+
+// exitStatusReport returns the exit progress of all applications, one line
+// per application.
+func exitStatusReport(status *admin.OperatorExitStatus) string {
+	var report strings.Builder
+
+	for _, application := range status.Applications {
+		step := application.Step
+		if step == "" {
+			step = "pending status check"
+		}
+
+		fmt.Fprintf(&report, "[%s] %s", application.Application, step)
+
+		if application.ExecutingProtocols {
+			report.WriteString("; executing protocols")
+		}
+		if application.InPool {
+			report.WriteString("; in pool")
+		}
+		if application.PendingAuthorizationDecrease != "" {
+			fmt.Fprintf(
+				&report,
+				"; pending authorization decrease [%s]",
+				application.PendingAuthorizationDecrease,
+			)
+		}
+		if application.RemainingDecreaseDelay != "" {
+			fmt.Fprintf(
+				&report,
+				"; remaining delay [%s]",
+				application.RemainingDecreaseDelay,
+			)
+		}
+		if application.Error != "" {
+			fmt.Fprintf(&report, "; error: [%s]", application.Error)
+		}
+
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
+
+func init() {
+	initFlags(
+		ExitCommand,
+		&configFilePath,
+		clientConfig,
+		config.ExitCategories...,
+	)
+
+	ExitCommand.Flags().Bool(
+		exitStatusOnlyFlagName,
+		false,
+		"print the exit status without requesting the exit",
+	)
+	ExitCommand.Flags().Duration(
+		exitPollIntervalFlagName,
+		defaultExitPollInterval,
+		"interval of exit status checks",
+	)
+}
@@ -12,11 +12,16 @@ import (
 	"github.com/keep-network/keep-common/pkg/rate"
 	"github.com/keep-network/keep-core/config"
 	"github.com/keep-network/keep-core/config/network"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/failover"
+	"github.com/keep-network/keep-core/pkg/bitcoin/mempoolspace"
 	chainEthereum "github.com/keep-network/keep-core/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
+	"github.com/keep-network/keep-core/pkg/firewall"
 	"github.com/keep-network/keep-core/pkg/maintainer/spv"
 	"github.com/keep-network/keep-core/pkg/net/libp2p"
+	"github.com/keep-network/keep-core/pkg/storage"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
@@ -39,9 +44,15 @@ func initFlags(
 		case config.Ethereum:
 			initEthereumFlags(cmd, cfg)
 		case config.BitcoinElectrum:
+			initBitcoinNetworkFlags(cmd, cfg)
 			initBitcoinElectrumFlags(cmd, cfg)
+			initBitcoinCoreFlags(cmd, cfg)
+			initBitcoinFailoverFlags(cmd, cfg)
+			initBitcoinMempoolSpaceFlags(cmd, cfg)
+			initBitcoinProxyFlags(cmd, cfg)
 		case config.Network:
 			initNetworkFlags(cmd, cfg)
+			initFirewallFlags(cmd, cfg)
 		case config.Storage:
 			initStorageFlags(cmd, cfg)
 		case config.ClientInfo:
@@ -52,6 +63,10 @@ func initFlags(
 			initMaintainerFlags(cmd, cfg)
 		case config.Developer:
 			initDeveloperFlags(cmd)
+		case config.Sortition:
+			initSortitionFlags(cmd, cfg)
+		case config.Admin:
+			initAdminFlags(cmd, cfg)
 		}
 	}
 
@@ -155,6 +170,17 @@ func initEthereumFlags(cmd *cobra.Command, cfg *config.Config) {
 	)
 }
 
+// Initialize flags for Bitcoin network configuration.
+func initBitcoinNetworkFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.NetworkName,
+		"bitcoin.network",
+		"",
+		"Bitcoin network to use instead of the one resolved from the client "+
+			"network. The testnet client can use `testnet4` instead of `testnet`.",
+	)
+}
+
 // Initialize flags for Bitcoin electrum configuration.
 func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 	cmd.Flags().StringVar(
@@ -198,6 +224,143 @@ func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 		electrum.DefaultKeepAliveInterval,
 		"Interval for connection keep alive requests.",
 	)
+
+	cmd.Flags().IntVar(
+		&cfg.Bitcoin.Electrum.ConnectionPoolSize,
+		"bitcoin.electrum.connectionPoolSize",
+		electrum.DefaultConnectionPoolSize,
+		"Number of connections maintained with the Electrum server.",
+	)
+}
+
+// Initialize flags for Bitcoin Core configuration.
+func initBitcoinCoreFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.URL,
+		"bitcoin.bitcoind.url",
+		"",
+		"URL to the Bitcoin Core RPC server in format: `scheme://hostname:port`. "+
+			"If set, it is used instead of the Electrum server, which is still "+
+			"required to look up transactions by address. The node must run "+
+			"with txindex enabled.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.Username,
+		"bitcoin.bitcoind.username",
+		"",
+		"Username used to authenticate Bitcoin Core RPC requests.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.Password,
+		"bitcoin.bitcoind.password",
+		"",
+		"Password used to authenticate Bitcoin Core RPC requests.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Bitcoind.RequestTimeout,
+		"bitcoin.bitcoind.requestTimeout",
+		bitcoind.DefaultRequestTimeout,
+		"Timeout for a single attempt of Bitcoin Core RPC request.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Bitcoind.RequestRetryTimeout,
+		"bitcoin.bitcoind.requestRetryTimeout",
+		bitcoind.DefaultRequestRetryTimeout,
+		"Timeout for Bitcoin Core RPC request retries.",
+	)
+}
+
+// Initialize flags for Bitcoin backends failover and fee estimation
+// configuration.
+func initBitcoinFailoverFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringSliceVar(
+		&cfg.Bitcoin.FailoverElectrumURLs,
+		"bitcoin.failoverElectrumURLs",
+		[]string{},
+		"URLs of Electrum servers used as failover backends of the primary "+
+			"Bitcoin backend.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Failover.HealthCheckInterval,
+		"bitcoin.failover.healthCheckInterval",
+		failover.DefaultHealthCheckInterval,
+		"Interval of Bitcoin backends health checks.",
+	)
+
+	cmd.Flags().UintVar(
+		&cfg.Bitcoin.Failover.MaxBlockHeightLag,
+		"bitcoin.failover.maxBlockHeightLag",
+		failover.DefaultMaxBlockHeightLag,
+		"Number of blocks a Bitcoin backend can lag behind the best known "+
+			"block height before it is considered unhealthy.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.Bitcoin.Failover.CrossValidation,
+		"bitcoin.failover.crossValidation",
+		false,
+		"Require transactions, transaction confirmations, block headers, "+
+			"and Merkle proofs to be confirmed by two Bitcoin backends.",
+	)
+
+	cmd.Flags().Int64Var(
+		&cfg.Bitcoin.FeeEstimation.MinSatPerVByteFee,
+		"bitcoin.feeEstimation.minSatPerVByteFee",
+		1,
+		"Minimum sat/vbyte fee used for Bitcoin transactions. Lower estimates "+
+			"are replaced by this value. Zero disables the bound.",
+	)
+
+	cmd.Flags().Int64Var(
+		&cfg.Bitcoin.FeeEstimation.MaxSatPerVByteFee,
+		"bitcoin.feeEstimation.maxSatPerVByteFee",
+		500,
+		"Maximum sat/vbyte fee used for Bitcoin transactions. Higher estimates "+
+			"are replaced by this value. Zero disables the bound.",
+	)
+}
+
+// Initialize flags for mempool.space API configuration.
+func initBitcoinMempoolSpaceFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.MempoolSpace.URL,
+		"bitcoin.mempoolSpace.url",
+		"",
+		"URL to the mempool.space REST API, e.g. `https://mempool.space/api`. "+
+			"If set, the API is used as an additional fee source and to "+
+			"check whether transactions are in the mempool.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.MempoolSpace.RequestTimeout,
+		"bitcoin.mempoolSpace.requestTimeout",
+		mempoolspace.DefaultRequestTimeout,
+		"Timeout for a single attempt of mempool.space API request.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.MempoolSpace.RequestRetryTimeout,
+		"bitcoin.mempoolSpace.requestRetryTimeout",
+		mempoolspace.DefaultRequestRetryTimeout,
+		"Timeout for mempool.space API request retries.",
+	)
+}
+
+// Initialize flags for Bitcoin backends proxy configuration.
+func initBitcoinProxyFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.ProxyURL,
+		"bitcoin.proxyURL",
+		"",
+		"URL of the SOCKS5 proxy connections to Bitcoin backends are routed "+
+			"through, e.g. `socks5://127.0.0.1:9050` for a local Tor daemon. "+
+			"Host names, including .onion addresses, are resolved by the proxy.",
+	)
 }
 
 // Initialize flags for Network configuration.
@@ -231,12 +394,263 @@ func initNetworkFlags(cmd *cobra.Command, cfg *config.Config) {
 		"Overwrites the default Keep client address announced in the network. Should be used for NAT or when more advanced firewall rules are applied.",
 	)
 
+	cmd.Flags().StringSliceVar(
+		&cfg.LibP2P.DNSSeeds,
+		"network.dnsSeeds",
+		[]string{},
+		"Domains with dnsaddr TXT records listing bootstrap peers. Peers resolved from DNS seeds are used along with the configured peers.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.LibP2P.BootstrapRefreshPeriod,
+		"network.bootstrapRefreshPeriod",
+		libp2p.DefaultBootstrapRefreshPeriod,
+		"Period at which bootstrap peers are resolved again from DNS seeds.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.LibP2P.PrivateNetworkKeyFile,
+		"network.privateNetworkKeyFile",
+		"",
+		"Path to the pre-shared key file of a private network. The node connects only with nodes sharing the same key. Joins the public network if empty.",
+	)
+
 	cmd.Flags().IntVar(
 		&cfg.LibP2P.DisseminationTime,
 		"network.disseminationTime",
 		0,
 		"Specifies courtesy message dissemination time in seconds for topics the node is not subscribed to. Should be used only on selected bootstrap nodes. (0 = none)",
 	)
+
+	cmd.Flags().IntVar(
+		&cfg.LibP2P.CompressionThreshold,
+		"network.compressionThreshold",
+		0,
+		"Minimum size in bytes of a broadcast message payload to be compressed before sending. Should be enabled only once all peers in the network support compressed messages. (0 = none)",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.LibP2P.SeenMessages.TTL,
+		"network.seenMessages.ttl",
+		libp2p.DefaultSeenMessagesTTL,
+		"Time-to-live of the broadcast channel seen messages cache entries used to suppress duplicate messages.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.LibP2P.SeenMessages.Strategy,
+		"network.seenMessages.strategy",
+		libp2p.SeenMessagesStrategyLastSeen,
+		"Expiration strategy of the broadcast channel seen messages cache entries: first-seen or last-seen.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.LibP2P.SeenMessages.CacheSize,
+		"network.seenMessages.cacheSize",
+		0,
+		"Maximum number of messages remembered by a broadcast channel message handler to filter out retransmissions. (0 = unlimited)",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.LibP2P.RateLimit.Enabled,
+		"network.rateLimit.enabled",
+		false,
+		"Enables per-sender rate limiting of broadcast channel messages.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.RateLimit.MessagesPerSecond,
+		"network.rateLimit.messagesPerSecond",
+		libp2p.DefaultRateLimitMessagesPerSecond,
+		"Sustained number of messages per second a single sender can publish to a broadcast channel.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.LibP2P.RateLimit.Burst,
+		"network.rateLimit.burst",
+		libp2p.DefaultRateLimitBurst,
+		"Maximum number of messages a single sender can publish to a broadcast channel at once.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.LibP2P.RateLimit.DisconnectThreshold,
+		"network.rateLimit.disconnectThreshold",
+		libp2p.DefaultRateLimitDisconnectThreshold,
+		"Number of messages over the budget after which the sender is disconnected (0 = never).",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.LibP2P.ConnectionGater.AllowedPeers,
+		"network.connectionGater.allowedPeers",
+		[]string{},
+		"Peer IDs always allowed to connect, even if denied by other rules.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.LibP2P.ConnectionGater.DeniedPeers,
+		"network.connectionGater.deniedPeers",
+		[]string{},
+		"Peer IDs not allowed to connect.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.LibP2P.ConnectionGater.AllowedIPs,
+		"network.connectionGater.allowedIPs",
+		[]string{},
+		"IP addresses or CIDR ranges always allowed to connect, even if denied by other rules.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.LibP2P.ConnectionGater.DeniedIPs,
+		"network.connectionGater.deniedIPs",
+		[]string{},
+		"IP addresses or CIDR ranges not allowed to connect.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.LibP2P.PeerScoring.Enabled,
+		"network.peerScoring.enabled",
+		false,
+		"Enable gossipsub peer scoring for broadcast channels. Replaces the default floodsub router with gossipsub.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.PeerScoring.TopicWeight,
+		"network.peerScoring.topicWeight",
+		libp2p.DefaultPeerScoringTopicWeight,
+		"Weight of the peer score of every broadcast channel topic.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.PeerScoring.InvalidMessagePenaltyWeight,
+		"network.peerScoring.invalidMessagePenaltyWeight",
+		libp2p.DefaultPeerScoringInvalidMessagePenaltyWeight,
+		"Weight of the penalty for delivering invalid broadcast messages. Must not be positive.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.LibP2P.PeerScoring.InvalidMessagePenaltyDecay,
+		"network.peerScoring.invalidMessagePenaltyDecay",
+		libp2p.DefaultPeerScoringInvalidMessagePenaltyDecay,
+		"Time after which the penalty for delivering invalid broadcast messages decays to zero.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.PeerScoring.IPColocationWeight,
+		"network.peerScoring.ipColocationWeight",
+		libp2p.DefaultPeerScoringIPColocationWeight,
+		"Weight of the penalty for peers sharing the same IP address. Must not be positive.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.LibP2P.PeerScoring.IPColocationThreshold,
+		"network.peerScoring.ipColocationThreshold",
+		libp2p.DefaultPeerScoringIPColocationThreshold,
+		"Number of peers that can share the same IP address without being penalized.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.PeerScoring.GossipThreshold,
+		"network.peerScoring.gossipThreshold",
+		libp2p.DefaultPeerScoringGossipThreshold,
+		"Peer score below which gossip is neither emitted to nor accepted from the peer.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.PeerScoring.PublishThreshold,
+		"network.peerScoring.publishThreshold",
+		libp2p.DefaultPeerScoringPublishThreshold,
+		"Peer score below which own messages are not published to the peer.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.LibP2P.PeerScoring.GraylistThreshold,
+		"network.peerScoring.graylistThreshold",
+		libp2p.DefaultPeerScoringGraylistThreshold,
+		"Peer score below which all messages from the peer are ignored.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.LibP2P.NAT.Reachability,
+		"network.nat.reachability",
+		libp2p.ReachabilityAuto,
+		"Reachability of the node: auto, public, or private. Auto detects reachability with AutoNAT.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.LibP2P.NAT.PortMapping,
+		"network.nat.portMapping",
+		false,
+		"Enable port mapping on the NAT device with UPnP and NAT-PMP.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.LibP2P.NAT.HolePunching,
+		"network.nat.holePunching",
+		false,
+		"Enable hole punching to upgrade relayed connections to direct ones.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.LibP2P.NAT.AutoNATService,
+		"network.nat.autoNATService",
+		false,
+		"Enable serving AutoNAT reachability checks of other peers. Should be enabled only on publicly reachable nodes.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.LibP2P.NAT.RelayService,
+		"network.nat.relayService",
+		false,
+		"Enable the circuit relay v2 service. Should be used only on selected publicly reachable bootstrap nodes.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.LibP2P.NAT.Relays,
+		"network.nat.relays",
+		[]string{},
+		"Addresses of circuit relay v2 servers used when the node is not publicly reachable.",
+	)
+}
+
+// Initialize flags for Firewall configuration.
+func initFirewallFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().BoolVar(
+		&cfg.Firewall.MinimumAuthorization,
+		"firewall.minimumAuthorization",
+		false,
+		"Require peers to have at least the minimum stake authorization in any of the applications.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Firewall.PositiveCachePeriod,
+		"firewall.positiveCachePeriod",
+		firewall.PositiveIsRecognizedCachePeriod,
+		"Time period successful peer validation results are cached for before the peer is validated against the chain again.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Firewall.NegativeCachePeriod,
+		"firewall.negativeCachePeriod",
+		firewall.NegativeIsRecognizedCachePeriod,
+		"Time period failed peer validation results are cached for.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Firewall.GracePeriod,
+		"firewall.gracePeriod",
+		0,
+		"Time period a peer that stopped passing validation is still accepted for. (0 = none)",
+	)
+}
+
+// Initialize flags for Admin configuration.
+func initAdminFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().IntVar(
+		&cfg.Admin.Port,
+		"admin.port",
+		0,
+		"Admin API HTTP server listening port. The server listens on the loopback interface only. (0 = disabled)",
+	)
 }
 
 // Initialize flags for Storage configuration.
@@ -247,6 +661,27 @@ func initStorageFlags(cmd *cobra.Command, cfg *config.Config) {
 		"",
 		"Location to store the Keep client key shares and other sensitive data.",
 	)
+
+	cmd.Flags().StringVar(
+		&cfg.Storage.Backup.Dir,
+		"storage.backup.dir",
+		"",
+		"Local directory to store the key store backups in. Remote storage URLs are not supported; use a mounted volume of an external storage instead. Backups are disabled if empty.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Storage.Backup.Interval,
+		"storage.backup.interval",
+		storage.DefaultBackupInterval,
+		"Time between consecutive key store backups.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.Storage.Backup.Retention,
+		"storage.backup.retention",
+		storage.DefaultBackupRetention,
+		"Number of the most recent key store backups to keep. (0 = all)",
+	)
 }
 
 // Initialize flags for ClientInfo configuration.
@@ -299,7 +734,14 @@ func initTbtcFlags(cmd *cobra.Command, cfg *config.Config) {
 		&cfg.Tbtc.PreParamsGenerationConcurrency,
 		"tbtc.preParamsGenerationConcurrency",
 		tbtc.DefaultPreParamsGenerationConcurrency,
-		"tECDSA pre-parameters generation concurrency.",
+		"tECDSA pre-parameters generation maximum concurrency. The actual concurrency adapts to the system load.",
+	)
+
+	cmd.Flags().Float64Var(
+		&cfg.Tbtc.PreParamsGenerationLoadThreshold,
+		"tbtc.preParamsGenerationLoadThreshold",
+		tbtc.DefaultPreParamsGenerationLoadThreshold,
+		"CPU utilization of other processes, as a fraction of the total CPU capacity, at which tECDSA pre-parameters generation is stopped.",
 	)
 
 	cmd.Flags().IntVar(
@@ -310,6 +752,66 @@ func initTbtcFlags(cmd *cobra.Command, cfg *config.Config) {
 	)
 }
 
+// Initialize flags for sortition pool monitoring configuration.
+func initSortitionFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().BoolVar(
+		&cfg.Sortition.RegistrationBootstrap,
+		"sortition.registrationBootstrap",
+		false,
+		"Report missing operator registration steps instead of failing if the operator is not registered.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Sortition.StakingProvider,
+		"sortition.stakingProvider",
+		"",
+		"Staking provider the operator is going to be registered for. Defaults to the operator address.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.Sortition.AutoRegisterOperator,
+		"sortition.autoRegisterOperator",
+		false,
+		"Register the operator for itself as the staking provider in the registration bootstrap mode.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Sortition.RewardsIneligibilityWebhook,
+		"sortition.rewardsIneligibilityWebhook",
+		"",
+		"URL the alert is posted to when the operator becomes ineligible for rewards.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Sortition.RewardsIneligibilityCommand,
+		"sortition.rewardsIneligibilityCommand",
+		"",
+		"Shell command executed with the alert on the standard input when the operator becomes ineligible for rewards.",
+	)
+
+	flag.WeiVarFlag(
+		cmd.Flags(),
+		&cfg.Sortition.MinimumJoinStake,
+		"sortition.minimumJoinStake",
+		*commonEthereum.WrapWei(big.NewInt(0)),
+		"The minimum eligible stake of the staking provider the client joins the sortition pools with.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.Sortition.MaintenanceWindows,
+		"sortition.maintenanceWindows",
+		[]string{},
+		"Recurring UTC time windows in the [Day ]HH:MM-HH:MM format during which the client does not join or update the sortition pools.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.Sortition.DryRun,
+		"sortition.dryRun",
+		false,
+		"Log sortition pool transactions with their estimated gas instead of submitting them.",
+	)
+}
+
 // Initialize flags for Maintainer configuration.
 func initMaintainerFlags(command *cobra.Command, cfg *config.Config) {
 	command.Flags().BoolVar(
@@ -340,6 +842,14 @@ func initMaintainerFlags(command *cobra.Command, cfg *config.Config) {
 		"Number of blocks to look back for past wallet-related events.",
 	)
 
+	command.Flags().Uint64Var(
+		&cfg.Maintainer.Spv.RescanFromBlock,
+		"spv.rescanFromBlock",
+		0,
+		"Block from which past wallet-related events should be searched for "+
+			"in the first round, ignoring the persisted checkpoints.",
+	)
+
 	command.Flags().IntVar(
 		&cfg.Maintainer.Spv.TransactionLimit,
 		"spv.transactionLimit",
@@ -348,6 +858,49 @@ func initMaintainerFlags(command *cobra.Command, cfg *config.Config) {
 			"transactions for a public key hash.",
 	)
 
+	command.Flags().IntVar(
+		&cfg.Maintainer.Spv.ProofWorkers,
+		"spv.proofWorkers",
+		spv.DefaultProofWorkers,
+		"The maximum number of transaction proofs assembled and submitted "+
+			"concurrently.",
+	)
+
+	command.Flags().StringSliceVar(
+		&cfg.Maintainer.Spv.FallbackElectrumURLs,
+		"spv.fallbackElectrumURLs",
+		[]string{},
+		"URLs of Electrum servers used to assemble transaction proofs when "+
+			"the primary Bitcoin backend fails.",
+	)
+
+	flag.WeiVarFlag(
+		command.Flags(),
+		&cfg.Maintainer.Spv.GasTipCap,
+		"spv.gasTipCap",
+		commonEthereum.Wei{},
+		"The maximum priority fee per gas for proof submissions. "+
+			"Estimated if not set.",
+	)
+
+	flag.WeiVarFlag(
+		command.Flags(),
+		&cfg.Maintainer.Spv.GasFeeCap,
+		"spv.gasFeeCap",
+		commonEthereum.Wei{},
+		"The maximum total fee per gas for proof submissions. "+
+			"Estimated if not set.",
+	)
+
+	flag.WeiVarFlag(
+		command.Flags(),
+		&cfg.Maintainer.Spv.MaxDailyGasSpend,
+		"spv.maxDailyGasSpend",
+		commonEthereum.Wei{},
+		"The cap on the total fees of proof submissions within the last "+
+			"24 hours. Not capped if not set.",
+	)
+
 	command.Flags().DurationVar(
 		&cfg.Maintainer.Spv.RestartBackoffTime,
 		"spv.restartBackoffTime",
@@ -22,6 +22,8 @@ import (
 	ethereumEcdsa "github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen"
 	ethereumTbtc "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen"
 	ethereumThreshold "github.com/keep-network/keep-core/pkg/chain/ethereum/threshold/gen"
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/net/libp2p"
 )
 
 var cmdFlagsTests = map[string]struct {
@@ -80,6 +82,12 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: big.NewInt(1250000000000000000),
 		defaultValue:          big.NewInt(500000000000000000),
 	},
+	"bitcoin.network": {
+		readValueFunc: func(c *config.Config) interface{} { return c.Bitcoin.NetworkName },
+		flagName:      "--bitcoin.network",
+		flagValue:     "mainnet",
+		defaultValue:  "",
+	},
 	"bitcoin.electrum.url": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Electrum.URL },
 		flagName:              "--bitcoin.electrum.url",
@@ -156,6 +164,27 @@ var cmdFlagsTests = map[string]struct {
 		},
 		defaultValue: []string{},
 	},
+	"network.dnsSeeds": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.DNSSeeds },
+		flagName:              "--network.dnsSeeds",
+		flagValue:             "bootstrap.example.com,seed.example.org",
+		expectedValueFromFlag: []string{"bootstrap.example.com", "seed.example.org"},
+		defaultValue:          []string{},
+	},
+	"network.bootstrapRefreshPeriod": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.BootstrapRefreshPeriod },
+		flagName:              "--network.bootstrapRefreshPeriod",
+		flagValue:             "5m",
+		expectedValueFromFlag: 5 * time.Minute,
+		defaultValue:          libp2p.DefaultBootstrapRefreshPeriod,
+	},
+	"network.privateNetworkKeyFile": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PrivateNetworkKeyFile },
+		flagName:              "--network.privateNetworkKeyFile",
+		flagValue:             "./flagged/swarm.key",
+		expectedValueFromFlag: "./flagged/swarm.key",
+		defaultValue:          "",
+	},
 	"network.disseminationTime": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.DisseminationTime },
 		flagName:              "--network.disseminationTime",
@@ -163,12 +192,259 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 486,
 		defaultValue:          0,
 	},
+	"network.compressionThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.CompressionThreshold },
+		flagName:              "--network.compressionThreshold",
+		flagValue:             "4096",
+		expectedValueFromFlag: 4096,
+		defaultValue:          0,
+	},
+	"network.seenMessages.ttl": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.SeenMessages.TTL },
+		flagName:              "--network.seenMessages.ttl",
+		flagValue:             "2m",
+		expectedValueFromFlag: 2 * time.Minute,
+		defaultValue:          libp2p.DefaultSeenMessagesTTL,
+	},
+	"network.seenMessages.strategy": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.SeenMessages.Strategy },
+		flagName:              "--network.seenMessages.strategy",
+		flagValue:             "first-seen",
+		expectedValueFromFlag: libp2p.SeenMessagesStrategyFirstSeen,
+		defaultValue:          libp2p.SeenMessagesStrategyLastSeen,
+	},
+	"network.rateLimit.enabled": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.RateLimit.Enabled },
+		flagName:              "--network.rateLimit.enabled",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"network.rateLimit.messagesPerSecond": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.RateLimit.MessagesPerSecond },
+		flagName:              "--network.rateLimit.messagesPerSecond",
+		flagValue:             "12.5",
+		expectedValueFromFlag: 12.5,
+		defaultValue:          libp2p.DefaultRateLimitMessagesPerSecond,
+	},
+	"network.rateLimit.burst": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.RateLimit.Burst },
+		flagName:              "--network.rateLimit.burst",
+		flagValue:             "120",
+		expectedValueFromFlag: 120,
+		defaultValue:          libp2p.DefaultRateLimitBurst,
+	},
+	"network.rateLimit.disconnectThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.RateLimit.DisconnectThreshold },
+		flagName:              "--network.rateLimit.disconnectThreshold",
+		flagValue:             "0",
+		expectedValueFromFlag: 0,
+		defaultValue:          libp2p.DefaultRateLimitDisconnectThreshold,
+	},
+	"network.connectionGater.allowedPeers": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.ConnectionGater.AllowedPeers },
+		flagName:              "--network.connectionGater.allowedPeers",
+		flagValue:             "16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX",
+		expectedValueFromFlag: []string{"16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX"},
+		defaultValue:          []string{},
+	},
+	"network.connectionGater.deniedPeers": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.ConnectionGater.DeniedPeers },
+		flagName:              "--network.connectionGater.deniedPeers",
+		flagValue:             "16Uiu2HAm3eJtyFKAttzJ85NLMromHuRg4yyum3CREMf6CHBBV6KY",
+		expectedValueFromFlag: []string{"16Uiu2HAm3eJtyFKAttzJ85NLMromHuRg4yyum3CREMf6CHBBV6KY"},
+		defaultValue:          []string{},
+	},
+	"network.connectionGater.allowedIPs": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.ConnectionGater.AllowedIPs },
+		flagName:              "--network.connectionGater.allowedIPs",
+		flagValue:             "10.0.0.1",
+		expectedValueFromFlag: []string{"10.0.0.1"},
+		defaultValue:          []string{},
+	},
+	"network.connectionGater.deniedIPs": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.ConnectionGater.DeniedIPs },
+		flagName:              "--network.connectionGater.deniedIPs",
+		flagValue:             "10.0.0.0/8,192.168.1.1",
+		expectedValueFromFlag: []string{"10.0.0.0/8", "192.168.1.1"},
+		defaultValue:          []string{},
+	},
+	"network.seenMessages.cacheSize": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.SeenMessages.CacheSize },
+		flagName:              "--network.seenMessages.cacheSize",
+		flagValue:             "10000",
+		expectedValueFromFlag: 10000,
+		defaultValue:          0,
+	},
+	"network.peerScoring.enabled": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.Enabled },
+		flagName:              "--network.peerScoring.enabled",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"network.peerScoring.topicWeight": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.TopicWeight },
+		flagName:              "--network.peerScoring.topicWeight",
+		flagValue:             "0.5",
+		expectedValueFromFlag: 0.5,
+		defaultValue:          libp2p.DefaultPeerScoringTopicWeight,
+	},
+	"network.peerScoring.invalidMessagePenaltyWeight": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.InvalidMessagePenaltyWeight },
+		flagName:              "--network.peerScoring.invalidMessagePenaltyWeight",
+		flagValue:             "-50",
+		expectedValueFromFlag: -50.0,
+		defaultValue:          libp2p.DefaultPeerScoringInvalidMessagePenaltyWeight,
+	},
+	"network.peerScoring.invalidMessagePenaltyDecay": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.InvalidMessagePenaltyDecay },
+		flagName:              "--network.peerScoring.invalidMessagePenaltyDecay",
+		flagValue:             "20m",
+		expectedValueFromFlag: 20 * time.Minute,
+		defaultValue:          libp2p.DefaultPeerScoringInvalidMessagePenaltyDecay,
+	},
+	"network.peerScoring.ipColocationWeight": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.IPColocationWeight },
+		flagName:              "--network.peerScoring.ipColocationWeight",
+		flagValue:             "-5",
+		expectedValueFromFlag: -5.0,
+		defaultValue:          libp2p.DefaultPeerScoringIPColocationWeight,
+	},
+	"network.peerScoring.ipColocationThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.IPColocationThreshold },
+		flagName:              "--network.peerScoring.ipColocationThreshold",
+		flagValue:             "4",
+		expectedValueFromFlag: 4,
+		defaultValue:          libp2p.DefaultPeerScoringIPColocationThreshold,
+	},
+	"network.peerScoring.gossipThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.GossipThreshold },
+		flagName:              "--network.peerScoring.gossipThreshold",
+		flagValue:             "-100",
+		expectedValueFromFlag: -100.0,
+		defaultValue:          libp2p.DefaultPeerScoringGossipThreshold,
+	},
+	"network.peerScoring.publishThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.PublishThreshold },
+		flagName:              "--network.peerScoring.publishThreshold",
+		flagValue:             "-200",
+		expectedValueFromFlag: -200.0,
+		defaultValue:          libp2p.DefaultPeerScoringPublishThreshold,
+	},
+	"network.peerScoring.graylistThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.PeerScoring.GraylistThreshold },
+		flagName:              "--network.peerScoring.graylistThreshold",
+		flagValue:             "-300",
+		expectedValueFromFlag: -300.0,
+		defaultValue:          libp2p.DefaultPeerScoringGraylistThreshold,
+	},
+	"network.nat.reachability": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.NAT.Reachability },
+		flagName:              "--network.nat.reachability",
+		flagValue:             "private",
+		expectedValueFromFlag: libp2p.ReachabilityPrivate,
+		defaultValue:          libp2p.ReachabilityAuto,
+	},
+	"network.nat.portMapping": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.NAT.PortMapping },
+		flagName:              "--network.nat.portMapping",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"network.nat.holePunching": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.NAT.HolePunching },
+		flagName:              "--network.nat.holePunching",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"network.nat.autoNATService": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.NAT.AutoNATService },
+		flagName:              "--network.nat.autoNATService",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"network.nat.relayService": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.NAT.RelayService },
+		flagName:              "--network.nat.relayService",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"network.nat.relays": {
+		readValueFunc: func(c *config.Config) interface{} { return c.LibP2P.NAT.Relays },
+		flagName:      "--network.nat.relays",
+		flagValue:     "/ip4/80.70.69.15/tcp/3919/ipfs/16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX",
+		expectedValueFromFlag: []string{
+			"/ip4/80.70.69.15/tcp/3919/ipfs/16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX",
+		},
+		defaultValue: []string{},
+	},
+	"firewall.minimumAuthorization": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Firewall.MinimumAuthorization },
+		flagName:              "--firewall.minimumAuthorization",
+		flagValue:             "true",
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"firewall.positiveCachePeriod": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Firewall.PositiveCachePeriod },
+		flagName:              "--firewall.positiveCachePeriod",
+		flagValue:             "2h",
+		expectedValueFromFlag: 2 * time.Hour,
+		defaultValue:          firewall.PositiveIsRecognizedCachePeriod,
+	},
+	"firewall.negativeCachePeriod": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Firewall.NegativeCachePeriod },
+		flagName:              "--firewall.negativeCachePeriod",
+		flagValue:             "15m",
+		expectedValueFromFlag: 15 * time.Minute,
+		defaultValue:          firewall.NegativeIsRecognizedCachePeriod,
+	},
+	"firewall.gracePeriod": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Firewall.GracePeriod },
+		flagName:              "--firewall.gracePeriod",
+		flagValue:             "30m",
+		expectedValueFromFlag: 30 * time.Minute,
+		defaultValue:          time.Duration(0),
+	},
+	"admin.port": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Admin.Port },
+		flagName:              "--admin.port",
+		flagValue:             "9701",
+		expectedValueFromFlag: 9701,
+		defaultValue:          0,
+	},
 	"storage.dir": {
 		readValueFunc: func(c *config.Config) interface{} { return c.Storage.Dir },
 		flagName:      "--storage.dir",
 		flagValue:     "./flagged/location/dude",
 		defaultValue:  "",
 	},
+	"storage.backup.dir": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Storage.Backup.Dir },
+		flagName:              "--storage.backup.dir",
+		flagValue:             "./flagged/backup/location",
+		expectedValueFromFlag: "./flagged/backup/location",
+		defaultValue:          "",
+	},
+	"storage.backup.interval": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Storage.Backup.Interval },
+		flagName:              "--storage.backup.interval",
+		flagValue:             "12h",
+		expectedValueFromFlag: 12 * time.Hour,
+		defaultValue:          24 * time.Hour,
+	},
+	"storage.backup.retention": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Storage.Backup.Retention },
+		flagName:              "--storage.backup.retention",
+		flagValue:             "14",
+		expectedValueFromFlag: 14,
+		defaultValue:          7,
+	},
 	"clientInfo.port": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.ClientInfo.Port },
 		flagName:              "--clientInfo.port",
@@ -218,6 +494,13 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 2,
 		defaultValue:          1,
 	},
+	"tbtc.preParamsGenerationLoadThreshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.PreParamsGenerationLoadThreshold },
+		flagName:              "--tbtc.preParamsGenerationLoadThreshold",
+		flagValue:             "0.75",
+		expectedValueFromFlag: 0.75,
+		defaultValue:          0.9,
+	},
 	"tbtc.keyGenerationConcurrency": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.KeyGenerationConcurrency },
 		flagName:              "--tbtc.keyGenerationConcurrency",
@@ -225,6 +508,62 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 101,
 		defaultValue:          runtime.GOMAXPROCS(0),
 	},
+	"sortition.registrationBootstrap": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.RegistrationBootstrap },
+		flagName:              "--sortition.registrationBootstrap",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"sortition.stakingProvider": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.StakingProvider },
+		flagName:              "--sortition.stakingProvider",
+		flagValue:             "0x80C63B577DC79B2432357BECC5b431dfb8E181DD",
+		expectedValueFromFlag: "0x80C63B577DC79B2432357BECC5b431dfb8E181DD",
+		defaultValue:          "",
+	},
+	"sortition.autoRegisterOperator": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.AutoRegisterOperator },
+		flagName:              "--sortition.autoRegisterOperator",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"sortition.rewardsIneligibilityWebhook": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.RewardsIneligibilityWebhook },
+		flagName:              "--sortition.rewardsIneligibilityWebhook",
+		flagValue:             "https://hooks.example.com/keep",
+		expectedValueFromFlag: "https://hooks.example.com/keep",
+		defaultValue:          "",
+	},
+	"sortition.rewardsIneligibilityCommand": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.RewardsIneligibilityCommand },
+		flagName:              "--sortition.rewardsIneligibilityCommand",
+		flagValue:             "mail operator@example.com",
+		expectedValueFromFlag: "mail operator@example.com",
+		defaultValue:          "",
+	},
+	"sortition.minimumJoinStake": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.MinimumJoinStake.Int },
+		flagName:              "--sortition.minimumJoinStake",
+		flagValue:             "40000 ether",
+		expectedValueFromFlag: new(big.Int).Mul(big.NewInt(40000), big.NewInt(1000000000000000000)),
+		defaultValue:          big.NewInt(0),
+	},
+	"sortition.maintenanceWindows": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.MaintenanceWindows },
+		flagName:              "--sortition.maintenanceWindows",
+		flagValue:             "02:00-04:00,Sat 22:00-02:00",
+		expectedValueFromFlag: []string{"02:00-04:00", "Sat 22:00-02:00"},
+		defaultValue:          []string{},
+	},
+	"sortition.dryRun": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Sortition.DryRun },
+		flagName:              "--sortition.dryRun",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
 	"maintainer.bitcoinDifficulty": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.BitcoinDifficulty.Enabled },
 		flagName:              "--bitcoinDifficulty",
@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-common/pkg/persistence"
 	"github.com/keep-network/keep-core/build"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
+	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/failover"
+	"github.com/keep-network/keep-core/pkg/bitcoin/headercache"
+	"github.com/keep-network/keep-core/pkg/bitcoin/mempoolspace"
 	chainEthereum "github.com/keep-network/keep-core/pkg/chain/ethereum"
 )
 
@@ -112,3 +120,98 @@ func buildContractAddresses(lineLength int, prefix, suffix string, ethereumConfi
 	}
 	return firstLine + buildMultiLine(lineLength, prefix, suffix, "", entries)
 }
+
+// connectBitcoinChain connects to the Bitcoin chain using the configured
+// backend. The Bitcoin Core node is used if its URL is set, the Electrum server
+// is used otherwise. Bitcoin Core does not index transactions by address so
+// such lookups are routed to the Electrum server, which has to be configured
+// alongside the Bitcoin Core node. If failover Electrum servers are configured,
+// all backends are wrapped by a failover chain. Sat/vbyte fees are estimated
+// using the median of estimates of all backends, bounded according to the fee
+// estimation config. If the mempool.space API is configured, it is used as an
+// additional fee source and to check whether transactions are in the mempool.
+// If the given persistence handle is not nil, block headers are cached using
+// it.
+func connectBitcoinChain(
+	ctx context.Context,
+	headersPersistence persistence.BasicHandle,
+) (bitcoin.Chain, error) {
+	var primaryBtcChain bitcoin.Chain
+	var err error
+	if clientConfig.Bitcoin.Bitcoind.URL != "" {
+		var addressIndex bitcoin.Chain
+		addressIndex, err = electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not connect Electrum server used as address index: [%w]",
+				err,
+			)
+		}
+
+		primaryBtcChain, err = bitcoind.Connect(
+			ctx,
+			clientConfig.Bitcoin.Bitcoind,
+			bitcoind.WithAddressIndex(addressIndex),
+		)
+	} else {
+		primaryBtcChain, err = electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	btcChains := append(
+		[]bitcoin.Chain{primaryBtcChain},
+		connectFallbackElectrums(
+			ctx,
+			clientConfig.Bitcoin.FailoverElectrumURLs,
+		)...,
+	)
+
+	btcChain := primaryBtcChain
+	if len(btcChains) > 1 {
+		btcChain, err = failover.New(
+			ctx,
+			clientConfig.Bitcoin.Failover,
+			btcChains...,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if headersPersistence != nil {
+		btcChain = headercache.New(btcChain, headersPersistence)
+	}
+
+	feeSources := make([]bitcoin.SatPerVByteFeeSource, len(btcChains))
+	for i, chain := range btcChains {
+		feeSources[i] = chain
+	}
+
+	var mempoolSpaceClient *mempoolspace.Client
+	if clientConfig.Bitcoin.MempoolSpace.URL != "" {
+		mempoolSpaceClient, err = mempoolspace.NewClient(
+			ctx,
+			clientConfig.Bitcoin.MempoolSpace,
+		)
+		if err != nil {
+			return nil, err
+		}
+		feeSources = append(feeSources, mempoolSpaceClient)
+	}
+
+	feeEstimator := bitcoin.NewTransactionFeeEstimator(feeSources...).
+		WithBounds(
+			clientConfig.Bitcoin.FeeEstimation.MinSatPerVByteFee,
+			clientConfig.Bitcoin.FeeEstimation.MaxSatPerVByteFee,
+		)
+
+	btcChain = bitcoin.NewFeeEstimatingChain(btcChain, feeEstimator)
+
+	if mempoolSpaceClient != nil {
+		btcChain = bitcoin.NewMempoolCheckingChain(btcChain, mempoolSpaceClient)
+	}
+
+	return btcChain, nil
+}
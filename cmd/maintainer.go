@@ -6,10 +6,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/keep-network/keep-core/build"
 	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/maintainer"
+	"github.com/keep-network/keep-core/pkg/storage"
 )
 
 // MaintainerCommand contains the definition of the maintainer command-line
@@ -44,11 +48,34 @@ func init() {
 func maintainers(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+	storage, err := storage.Initialize(
+		clientConfig.Storage,
+		clientConfig.Ethereum.KeyFilePassword,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot initialize storage: [%w]", err)
+	}
+
+	bitcoinDataPersistence, err := storage.InitializeWorkPersistence(
+		"bitcoin",
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot initialize bitcoin data persistence: [%w]",
+			err,
+		)
+	}
+
+	btcChain, err := connectBitcoinChain(ctx, bitcoinDataPersistence)
 	if err != nil {
-		return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+		return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
 	}
 
+	fallbackBtcChains := connectFallbackElectrums(
+		ctx,
+		clientConfig.Maintainer.Spv.FallbackElectrumURLs,
+	)
+
 	btcDiffChain, err := ethereum.ConnectBitcoinDifficulty(
 		ctx,
 		clientConfig.Ethereum,
@@ -72,14 +99,72 @@ func maintainers(cmd *cobra.Command, args []string) error {
 		)
 	}
 
+	maintainerPersistence, err := storage.InitializeWorkPersistence(
+		"maintainer",
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot initialize maintainer persistence: [%w]",
+			err,
+		)
+	}
+
+	clientInfoRegistry, isConfigured := clientinfo.Initialize(
+		ctx,
+		clientConfig.ClientInfo.Port,
+	)
+	if isConfigured {
+		clientInfoRegistry.ObserveBtcConnectivity(
+			btcChain,
+			clientConfig.ClientInfo.BitcoinMetricsTick,
+		)
+
+		clientInfoRegistry.RegisterMetricClientInfo(build.Version)
+	} else {
+		logger.Infof("client info endpoint not configured")
+	}
+
 	maintainer.Initialize(
 		ctx,
 		clientConfig.Maintainer,
+		clientConfig.Bitcoin.Network,
 		btcChain,
+		fallbackBtcChains,
 		btcDiffChain,
 		tbtcChain,
+		maintainerPersistence,
+		clientInfoRegistry,
 	)
 
 	<-ctx.Done()
 	return fmt.Errorf("unexpected context cancellation")
 }
+
+// connectFallbackElectrums connects to the Electrum servers with the given
+// URLs. All other connection properties are taken from the primary Electrum
+// config. Servers that cannot be connected to are logged and skipped.
+func connectFallbackElectrums(
+	ctx context.Context,
+	urls []string,
+) []bitcoin.Chain {
+	btcChains := make([]bitcoin.Chain, 0, len(urls))
+
+	for _, url := range urls {
+		electrumConfig := clientConfig.Bitcoin.Electrum
+		electrumConfig.URL = url
+
+		btcChain, err := electrum.Connect(ctx, electrumConfig)
+		if err != nil {
+			logger.Warnf(
+				"could not connect to fallback Electrum server [%s]: [%v]",
+				url,
+				err,
+			)
+			continue
+		}
+
+		btcChains = append(btcChains, btcChain)
+	}
+
+	return btcChains
+}
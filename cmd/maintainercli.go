@@ -11,8 +11,9 @@ import (
 	"github.com/keep-network/keep-core/config"
 	"github.com/keep-network/keep-core/internal/hexutils"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
-	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/maintainer"
+	"github.com/keep-network/keep-core/pkg/maintainer/btcdiff"
 	"github.com/keep-network/keep-core/pkg/maintainer/spv"
 	"github.com/keep-network/keep-core/pkg/tbtcpg"
 )
@@ -32,6 +33,10 @@ var (
 	// submitRedemptionProofCommand:
 	transactionHashFlagName = "transaction-hash"
 	confirmationsFlagName   = "confirmations"
+
+	// backfillRelayCommand:
+	toEpochFlagName      = "to-epoch"
+	disableProxyFlagName = "disable-proxy"
 )
 
 // MaintainerCliCommand contains the definition of tools associated with maintainers
@@ -86,9 +91,9 @@ var listDepositsCommand = cobra.Command{
 			)
 		}
 
-		btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+		btcChain, err := connectBitcoinChain(ctx, nil)
 		if err != nil {
-			return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
 		}
 
 		var walletPublicKeyHash [20]byte
@@ -180,9 +185,9 @@ var estimateDepositsSweepFeeCommand = cobra.Command{
 			)
 		}
 
-		btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+		btcChain, err := connectBitcoinChain(ctx, nil)
 		if err != nil {
-			return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
 		}
 
 		fees, err := tbtcpg.EstimateDepositsSweepFee(
@@ -297,9 +302,9 @@ var submitDepositSweepProofCommand = cobra.Command{
 			)
 		}
 
-		btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+		btcChain, err := connectBitcoinChain(ctx, nil)
 		if err != nil {
-			return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
 		}
 
 		transactionHashFlag, err := cmd.Flags().GetString(transactionHashFlagName)
@@ -350,9 +355,10 @@ var submitDepositSweepProofCommand = cobra.Command{
 			transactionHashFlag,
 		)
 
-		if err = spv.SubmitDepositSweepProof(
+		if _, err = spv.SubmitDepositSweepProof(
 			transactionHash,
 			requiredConfirmations,
+			nil,
 			btcChain,
 			tbtcChain,
 		); err != nil {
@@ -387,9 +393,9 @@ var submitRedemptionProofCommand = cobra.Command{
 			)
 		}
 
-		btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+		btcChain, err := connectBitcoinChain(ctx, nil)
 		if err != nil {
-			return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
 		}
 
 		transactionHashFlag, err := cmd.Flags().GetString(transactionHashFlagName)
@@ -440,9 +446,10 @@ var submitRedemptionProofCommand = cobra.Command{
 			transactionHashFlag,
 		)
 
-		if err = spv.SubmitRedemptionProof(
+		if _, err = spv.SubmitRedemptionProof(
 			transactionHash,
 			requiredConfirmations,
+			nil,
 			btcChain,
 			tbtcChain,
 		); err != nil {
@@ -458,6 +465,166 @@ var submitRedemptionProofCommand = cobra.Command{
 	},
 }
 
+var auditRelayCommand = cobra.Command{
+	Use:              "audit-relay",
+	Short:            "audit light relay",
+	Long:             auditRelayCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		btcDiffChain, err := ethereum.ConnectBitcoinDifficulty(
+			ctx,
+			clientConfig.Ethereum,
+			maintainer.Config{},
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"could not connect to Bitcoin difficulty chain: [%v]",
+				err,
+			)
+		}
+
+		btcChain, err := connectBitcoinChain(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
+		}
+
+		audit, err := btcdiff.AuditRelay(btcChain, btcDiffChain)
+		if err != nil {
+			return fmt.Errorf("failed to audit relay: [%v]", err)
+		}
+
+		if err := printRelayAuditTable(audit); err != nil {
+			return fmt.Errorf("failed to print relay audit table: [%v]", err)
+		}
+
+		if !audit.DifficultiesMatch() {
+			return fmt.Errorf(
+				"relay epoch difficulties do not match the Bitcoin chain",
+			)
+		}
+
+		return nil
+	},
+}
+
+// printRelayAuditTable prints the relay audit to the standard output.
+func printRelayAuditTable(audit *btcdiff.RelayAudit) error {
+	writer := tabwriter.NewWriter(
+		os.Stdout,
+		2,
+		4,
+		1,
+		' ',
+		tabwriter.AlignRight,
+	)
+
+	_, err := fmt.Fprintf(
+		writer,
+		"current epoch\t%v\t\n"+
+			"latest provable epoch\t%v\t\n"+
+			"epochs behind\t%v\t\n"+
+			"current epoch difficulty (relay)\t%v\t\n"+
+			"current epoch difficulty (bitcoin)\t%v\t\n"+
+			"previous epoch difficulty (relay)\t%v\t\n"+
+			"previous epoch difficulty (bitcoin)\t%v\t\n"+
+			"difficulties match\t%v\t\n",
+		audit.CurrentEpoch,
+		audit.LatestProvableEpoch,
+		audit.EpochsBehind(),
+		audit.CurrentEpochDifficulty,
+		audit.ExpectedCurrentEpochDifficulty,
+		audit.PreviousEpochDifficulty,
+		audit.ExpectedPreviousEpochDifficulty,
+		audit.DifficultiesMatch(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush the writer: %v", err)
+	}
+
+	return nil
+}
+
+var auditRelayCommandDescription = "Audits the LightRelay contract against " +
+	"the Bitcoin chain. Prints the latest epoch proven to the relay, the " +
+	"number of epochs the relay lags behind the Bitcoin chain, and compares " +
+	"the current and previous epoch difficulties stored in the relay with " +
+	"the difficulties of the Bitcoin chain. Returns an error if the " +
+	"difficulties do not match"
+
+var backfillRelayCommand = cobra.Command{
+	Use:              "backfill-relay",
+	Short:            "backfill light relay",
+	Long:             backfillRelayCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		toEpoch, err := cmd.Flags().GetUint64(toEpochFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find to epoch flag: [%v]", err)
+		}
+
+		disableProxy, err := cmd.Flags().GetBool(disableProxyFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find disable proxy flag: [%v]", err)
+		}
+
+		btcDiffConfig := btcdiff.Config{DisableProxy: disableProxy}
+
+		btcDiffChain, err := ethereum.ConnectBitcoinDifficulty(
+			ctx,
+			clientConfig.Ethereum,
+			maintainer.Config{BitcoinDifficulty: btcDiffConfig},
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"could not connect to Bitcoin difficulty chain: [%v]",
+				err,
+			)
+		}
+
+		btcChain, err := connectBitcoinChain(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
+		}
+
+		provenEpochs, err := btcdiff.BackfillRelay(
+			ctx,
+			btcDiffConfig,
+			btcChain,
+			btcDiffChain,
+			toEpoch,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to backfill relay after proving [%d] epochs; "+
+					"run the command again to resume: [%v]",
+				provenEpochs,
+				err,
+			)
+		}
+
+		logger.Infof("successfully backfilled relay with [%d] epochs", provenEpochs)
+
+		return nil
+	},
+}
+
+var backfillRelayCommandDescription = "Proves epochs missing in the " +
+	"LightRelay contract, in order, until the relay is up-to-date with the " +
+	"Bitcoin chain. The --to-epoch flag can be used to stop once the given " +
+	"epoch is proven. The progress is read from the relay before each epoch " +
+	"is proven so an interrupted backfill can be resumed by running the " +
+	"command again. By default, epochs are submitted via the " +
+	"LightRelayMaintainerProxy contract to be reimbursed; the " +
+	"--disable-proxy flag can be used to submit them directly to the relay"
+
 func init() {
 	initFlags(
 		MaintainerCliCommand,
@@ -549,6 +716,26 @@ func init() {
 	)
 
 	MaintainerCliCommand.AddCommand(&submitRedemptionProofCommand)
+
+	// Audit Relay Subcommand.
+	MaintainerCliCommand.AddCommand(&auditRelayCommand)
+
+	// Backfill Relay Subcommand.
+	backfillRelayCommand.Flags().Uint64(
+		toEpochFlagName,
+		0,
+		"(optional) epoch the relay should be backfilled up to; if not "+
+			"provided, the relay is brought up-to-date with the Bitcoin chain.",
+	)
+
+	backfillRelayCommand.Flags().Bool(
+		disableProxyFlagName,
+		false,
+		"submit epochs directly to the relay instead of via the "+
+			"maintainer proxy; the submissions are not reimbursed then.",
+	)
+
+	MaintainerCliCommand.AddCommand(&backfillRelayCommand)
 }
 
 func newWalletPublicKeyHash(str string) ([20]byte, error) {
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/storage"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+const (
+	signersFileFlagName = "file"
+
+	// signersPassphraseEnvVariable is the environment variable the signers
+	// export passphrase is read from. The passphrase is prompted for if the
+	// variable is not set.
+	signersPassphraseEnvVariable = "KEEP_SIGNERS_PASSPHRASE"
+)
+
+// SignerCommand contains the definition of tools allowing to migrate wallet
+// signers between nodes.
+var SignerCommand = &cobra.Command{
+	Use:              "signer",
+	Short:            "Signer Migration Tools",
+	Long:             "The tool exposes commands allowing to migrate wallet signers between nodes.",
+	TraverseChildren: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			config.SignerCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+}
+
+var exportSignersCommand = cobra.Command{
+	Use:              "export",
+	Short:            "export wallet signers",
+	Long:             exportSignersCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wallet, err := cmd.Flags().GetString(walletFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find wallet flag: %v", err)
+		}
+
+		walletPublicKeyHash, err := newWalletPublicKeyHash(wallet)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to extract wallet public key hash: %v",
+				err,
+			)
+		}
+
+		file, err := cmd.Flags().GetString(signersFileFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find file flag: %v", err)
+		}
+
+		if _, err := os.Stat(file); err == nil {
+			return fmt.Errorf("file [%s] already exists", file)
+		}
+
+		passphrase, err := readSignersPassphrase(true)
+		if err != nil {
+			return fmt.Errorf("cannot read passphrase: [%w]", err)
+		}
+
+		keyStorePersistence, err := initializeTbtcKeyStorePersistence()
+		if err != nil {
+			return err
+		}
+
+		export, archive, err := tbtc.ExportSigners(
+			keyStorePersistence,
+			walletPublicKeyHash,
+			passphrase,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot export signers: [%w]", err)
+		}
+
+		// Signers are archived only once the export is durably written so
+		// the key material is never left only in the archive.
+		if err := writeSignersExport(file, export); err != nil {
+			return fmt.Errorf(
+				"cannot write export to file; signers were not "+
+					"archived: [%w]",
+				err,
+			)
+		}
+
+		if err := archive(); err != nil {
+			return fmt.Errorf(
+				"signers exported to file [%s] but could not be archived "+
+					"in the key store; the node must not be started until "+
+					"they are archived: [%w]",
+				file,
+				err,
+			)
+		}
+
+		logger.Infof(
+			"signers of wallet [0x%x] exported to file [%s]",
+			walletPublicKeyHash,
+			file,
+		)
+
+		return nil
+	},
+}
+
+// writeSignersExport writes the export to a new file and flushes it to the
+// disk. The file must not exist.
+func writeSignersExport(file string, export []byte) error {
+	exportFile, err := os.OpenFile(
+		file,
+		os.O_WRONLY|os.O_CREATE|os.O_EXCL,
+		0600,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exportFile.Write(export); err != nil {
+		exportFile.Close()
+		return err
+	}
+
+	if err := exportFile.Sync(); err != nil {
+		exportFile.Close()
+		return err
+	}
+
+	return exportFile.Close()
+}
+
+var exportSignersCommandDescription = "Exports all signers of the given " +
+	"wallet held by the node to the given file, encrypted with a " +
+	"passphrase. The passphrase is read from the " +
+	signersPassphraseEnvVariable + " environment variable or prompted " +
+	"for. Exported signers are archived in the node's key store so the " +
+	"node no longer uses them. The node must be stopped during the export."
+
+var importSignersCommand = cobra.Command{
+	Use:              "import",
+	Short:            "import wallet signers",
+	Long:             importSignersCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString(signersFileFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find file flag: %v", err)
+		}
+
+		export, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("cannot read file [%s]: [%w]", file, err)
+		}
+
+		passphrase, err := readSignersPassphrase(false)
+		if err != nil {
+			return fmt.Errorf("cannot read passphrase: [%w]", err)
+		}
+
+		key, err := ethutil.DecryptKeyFile(
+			clientConfig.Ethereum.Account.KeyFile,
+			clientConfig.Ethereum.Account.KeyFilePassword,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot decrypt operator key file: [%w]", err)
+		}
+
+		keyStorePersistence, err := initializeTbtcKeyStorePersistence()
+		if err != nil {
+			return err
+		}
+
+		walletPublicKeyHash, count, err := tbtc.ImportSigners(
+			keyStorePersistence,
+			chain.Address(key.Address.Hex()),
+			export,
+			passphrase,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot import signers: [%w]", err)
+		}
+
+		logger.Infof(
+			"imported [%d] signers of wallet [0x%x]",
+			count,
+			walletPublicKeyHash,
+		)
+
+		return nil
+	},
+}
+
+var importSignersCommandDescription = "Imports wallet signers from the " +
+	"given file created by the export command on another node. The " +
+	"passphrase is read from the " + signersPassphraseEnvVariable +
+	" environment variable or prompted for. The import is refused if the " +
+	"node already holds signers of the wallet or if the signers do not " +
+	"belong to the node's operator."
+
+func initializeTbtcKeyStorePersistence() (persistence.ProtectedHandle, error) {
+	storage, err := storage.Initialize(
+		clientConfig.Storage,
+		clientConfig.Ethereum.KeyFilePassword,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot initialize tbtc keystore persistence: [%w]",
+			err,
+		)
+	}
+
+	return keyStorePersistence, nil
+}
+
+// readSignersPassphrase reads the signers export passphrase from the
+// environment variable or prompts for it. The prompted passphrase must be
+// confirmed if requested.
+func readSignersPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(signersPassphraseEnvVariable); passphrase != "" {
+		return passphrase, nil
+	}
+
+	passphrase, err := promptPassphrase("Enter signers passphrase: ")
+	if err != nil {
+		return "", err
+	}
+
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("passphrase must not be empty")
+	}
+
+	if confirm {
+		confirmation, err := promptPassphrase("Confirm signers passphrase: ")
+		if err != nil {
+			return "", err
+		}
+
+		if confirmation != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	fmt.Print(prompt)
+	bytePassphrase, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Print("\n")
+	if err != nil {
+		return "", fmt.Errorf("unable to read passphrase: [%w]", err)
+	}
+
+	return strings.TrimSpace(string(bytePassphrase)), nil
+}
+
+func init() {
+	initFlags(
+		SignerCommand,
+		&configFilePath,
+		clientConfig,
+		config.SignerCategories...,
+	)
+
+	exportSignersCommand.Flags().String(
+		walletFlagName,
+		"",
+		"wallet public key hash",
+	)
+	exportSignersCommand.Flags().String(
+		signersFileFlagName,
+		"",
+		"path of the file the signers are exported to",
+	)
+	if err := exportSignersCommand.MarkFlagRequired(
+		walletFlagName,
+	); err != nil {
+		logger.Fatalf("failed to mark flag required: [%v]", err)
+	}
+	if err := exportSignersCommand.MarkFlagRequired(
+		signersFileFlagName,
+	); err != nil {
+		logger.Fatalf("failed to mark flag required: [%v]", err)
+	}
+
+	importSignersCommand.Flags().String(
+		signersFileFlagName,
+		"",
+		"path of the file the signers are imported from",
+	)
+	if err := importSignersCommand.MarkFlagRequired(
+		signersFileFlagName,
+	); err != nil {
+		logger.Fatalf("failed to mark flag required: [%v]", err)
+	}
+
+	SignerCommand.AddCommand(&exportSignersCommand)
+	SignerCommand.AddCommand(&importSignersCommand)
+}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"fmt"
 	"github.com/keep-network/keep-core/pkg/tbtcpg"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/keep-network/keep-common/pkg/persistence"
 	"github.com/keep-network/keep-core/build"
-	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	"github.com/keep-network/keep-core/pkg/operator"
 	"github.com/keep-network/keep-core/pkg/storage"
 
 	"github.com/spf13/cobra"
 
 	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/admin"
 	"github.com/keep-network/keep-core/pkg/beacon"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum"
@@ -23,7 +29,9 @@ import (
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/net/libp2p"
 	"github.com/keep-network/keep-core/pkg/net/retransmission"
+	"github.com/keep-network/keep-core/pkg/sortition"
 	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
 )
 
 // StartCommand contains the definition of the start command-line subcommand.
@@ -61,7 +69,21 @@ Environment variables:
 
 // start starts a node
 func start(cmd *cobra.Command) error {
-	ctx := context.Background()
+	// The context ends once the client is asked to terminate so that data
+	// buffered by the client can be saved before it exits.
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	// Pools buffering generated parameters, flushed on shutdown.
+	var tbtcPreParamsPool tbtc.PreParamsPool
+
+	// Requests of an immediate key store backup. Buffered so that a request
+	// made while a backup is in progress is not lost.
+	backupRequests := make(chan struct{}, 1)
 
 	beaconChain, tbtcChain, blockCounter, signing, operatorPrivateKey, err :=
 		ethereum.Connect(ctx, clientConfig.Ethereum)
@@ -72,6 +94,7 @@ func start(cmd *cobra.Command) error {
 	netProvider, err := initializeNetwork(
 		ctx,
 		[]firewall.Application{beaconChain, tbtcChain},
+		[]firewall.AuthorizationSource{beaconChain, tbtcChain},
 		operatorPrivateKey,
 		blockCounter,
 	)
@@ -79,6 +102,16 @@ func start(cmd *cobra.Command) error {
 		return fmt.Errorf("cannot initialize network: [%v]", err)
 	}
 
+	adminServer := admin.Initialize(ctx, clientConfig.Admin, netProvider)
+	if adminServer != nil {
+		logger.Infof(
+			"enabled admin API on port [%v]",
+			clientConfig.Admin.Port,
+		)
+	} else {
+		logger.Infof("admin API not configured")
+	}
+
 	clientInfoRegistry := initializeClientInfo(
 		ctx,
 		clientConfig,
@@ -91,20 +124,25 @@ func start(cmd *cobra.Command) error {
 	// Skip initialization for bootstrap nodes as they are only used for network
 	// discovery.
 	if !isBootstrap() {
-		btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
-		if err != nil {
-			return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
-		}
-
 		beaconKeyStorePersistence,
 			tbtcKeyStorePersistence,
 			tbtcDataPersistence,
-			err := initializePersistence()
+			bitcoinDataPersistence,
+			err := initializePersistence(ctx, backupRequests)
 		if err != nil {
 			return fmt.Errorf("cannot initialize persistence: [%w]", err)
 		}
 
-		scheduler := generator.StartScheduler()
+		btcChain, err := connectBitcoinChain(ctx, bitcoinDataPersistence)
+		if err != nil {
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
+		}
+
+		scheduler := generator.StartScheduler(
+			generator.WithLoadThreshold(
+				clientConfig.Tbtc.PreParamsGenerationLoadThreshold,
+			),
+		)
 
 		clientInfoRegistry.ObserveBtcConnectivity(
 			btcChain,
@@ -113,22 +151,84 @@ func start(cmd *cobra.Command) error {
 
 		clientInfoRegistry.RegisterBtcChainInfoSource(btcChain)
 
+		sortitionOptions, err := sortitionMonitorOptions(signing)
+		if err != nil {
+			return fmt.Errorf(
+				"error configuring sortition pool monitoring: [%v]",
+				err,
+			)
+		}
+
+		// Operator status in sortition pools of all applications is
+		// monitored in one loop.
+		sortitionPoolsMonitor := sortition.NewPoolsMonitor(
+			sortition.DefaultStatusCheckTick,
+			sortitionOptions...,
+		)
+
+		if adminServer != nil {
+			adminServer.RegisterOperatorExit(sortitionPoolsMonitor)
+		}
+
 		err = beacon.Initialize(
 			ctx,
 			beaconChain,
 			netProvider,
 			beaconKeyStorePersistence,
 			scheduler,
+			sortitionPoolsMonitor,
+			clientInfoRegistry,
 		)
 		if err != nil {
 			return fmt.Errorf("error initializing beacon: [%v]", err)
 		}
 
+		proposalStateStore := tbtcpg.NewProposalStateStore(
+			tbtcDataPersistence,
+			tbtcpg.DefaultProposalStateCooldown,
+		)
+		if err := proposalStateStore.Prune(
+			time.Now().Add(-tbtcpg.DefaultProposalStateRetention),
+		); err != nil {
+			logger.Warnf("cannot prune proposal state: [%v]", err)
+		}
+
 		proposalGenerator := tbtcpg.NewProposalGenerator(
 			tbtcChain,
 			btcChain,
+			tbtcpg.WithProposalStateStore(proposalStateStore),
 		)
 
+		tbtcOptions := []tbtc.InitializeOption{
+			tbtc.WithPreParamsPoolHandler(func(pool tbtc.PreParamsPool) {
+				tbtcPreParamsPool = pool
+			}),
+		}
+		if adminServer != nil {
+			tbtcOptions = append(
+				tbtcOptions,
+				tbtc.WithPreParamsPoolHandler(func(pool tbtc.PreParamsPool) {
+					adminServer.RegisterParameterPool("tbtc-pre-params", pool)
+				}),
+			)
+		}
+		if clientConfig.Storage.Backup.Dir != "" {
+			// Back up the key share of a new wallet right away instead of
+			// waiting for the next periodic backup.
+			tbtcOptions = append(
+				tbtcOptions,
+				tbtc.WithSignerRegisteredHandler(
+					func(event *tbtc.SignerRegisteredEvent) {
+						select {
+						case backupRequests <- struct{}{}:
+						default:
+							// A backup is already requested.
+						}
+					},
+				),
+			)
+		}
+
 		err = tbtc.Initialize(
 			ctx,
 			tbtcChain,
@@ -137,13 +237,24 @@ func start(cmd *cobra.Command) error {
 			tbtcKeyStorePersistence,
 			tbtcDataPersistence,
 			scheduler,
+			sortitionPoolsMonitor,
 			proposalGenerator,
 			clientConfig.Tbtc,
 			clientInfoRegistry,
+			tbtcOptions...,
 		)
 		if err != nil {
 			return fmt.Errorf("error initializing TBTC: [%v]", err)
 		}
+
+		sortitionPoolsMonitor.Start(ctx)
+
+		if clientInfoRegistry != nil {
+			clientInfoRegistry.RegisterSortitionPoolsSource(
+				sortitionPoolsMonitor,
+			)
+			clientInfoRegistry.ObserveSortitionPools(sortitionPoolsMonitor)
+		}
 	}
 
 	nodeHeader(
@@ -154,6 +265,13 @@ func start(cmd *cobra.Command) error {
 	)
 
 	<-ctx.Done()
+
+	if tbtcPreParamsPool != nil {
+		if err := tbtcPreParamsPool.Flush(); err != nil {
+			logger.Errorf("cannot flush tbtc pre-parameters pool: [%v]", err)
+		}
+	}
+
 	return fmt.Errorf("shutting down the node because its context has ended")
 }
 
@@ -164,6 +282,7 @@ func isBootstrap() bool {
 func initializeNetwork(
 	ctx context.Context,
 	applications []firewall.Application,
+	authorizationSources []firewall.AuthorizationSource,
 	operatorPrivateKey *operator.PrivateKey,
 	blockCounter chain.BlockCounter,
 ) (net.Provider, error) {
@@ -177,9 +296,27 @@ func initializeNetwork(
 		)
 	}
 
+	firewallOptions := []firewall.PolicyOption{
+		firewall.WithCachePeriods(
+			clientConfig.Firewall.PositiveCachePeriod,
+			clientConfig.Firewall.NegativeCachePeriod,
+		),
+		firewall.WithGracePeriod(clientConfig.Firewall.GracePeriod),
+	}
+
+	if clientConfig.Firewall.MinimumAuthorization {
+		firewallOptions = append(
+			firewallOptions,
+			firewall.WithRequirements(
+				firewall.MinimumAuthorization(authorizationSources...),
+			),
+		)
+	}
+
 	firewall := firewall.AnyApplicationPolicy(
 		applications,
 		firewall.NewAllowList(bootstrapPeersPublicKeys),
+		firewallOptions...,
 	)
 
 	netProvider, err := libp2p.Connect(
@@ -196,6 +333,123 @@ func initializeNetwork(
 	return netProvider, nil
 }
 
+// sortitionMonitorOptions returns options of the sortition pools monitor
+// resulting from the client configuration.
+func sortitionMonitorOptions(
+	signing chain.Signing,
+) ([]sortition.MonitorOption, error) {
+	sortitionConfig := clientConfig.Sortition
+
+	options := make([]sortition.MonitorOption, 0)
+
+	if sortitionConfig.RegistrationBootstrap {
+		operator := signing.Address()
+
+		stakingProvider := operator
+		if sortitionConfig.StakingProvider != "" {
+			stakingProvider = chain.Address(
+				common.HexToAddress(sortitionConfig.StakingProvider).Hex(),
+			)
+		}
+
+		logger.Infof(
+			"operator registration bootstrap mode enabled for staking "+
+				"provider [%s]",
+			stakingProvider,
+		)
+
+		options = append(
+			options,
+			sortition.WithRegistrationBootstrap(
+				operator,
+				stakingProvider,
+				sortitionConfig.AutoRegisterOperator,
+			),
+		)
+	}
+
+	if sortitionConfig.DryRun {
+		logger.Warnf(
+			"sortition pool monitoring dry-run mode enabled; " +
+				"transactions will not be submitted",
+		)
+
+		options = append(options, sortition.WithDryRun())
+	}
+
+	alertHooks := make([]sortition.AlertHook, 0)
+	if sortitionConfig.RewardsIneligibilityWebhook != "" {
+		alertHooks = append(
+			alertHooks,
+			sortition.NewWebhookAlertHook(
+				sortitionConfig.RewardsIneligibilityWebhook,
+			),
+		)
+	}
+	if sortitionConfig.RewardsIneligibilityCommand != "" {
+		alertHooks = append(
+			alertHooks,
+			sortition.NewCommandAlertHook(
+				sortitionConfig.RewardsIneligibilityCommand,
+			),
+		)
+	}
+
+	if len(alertHooks) > 0 {
+		logger.Infof(
+			"[%d] rewards ineligibility alert hooks configured",
+			len(alertHooks),
+		)
+
+		options = append(
+			options,
+			sortition.WithRewardsIneligibilityAlertHooks(alertHooks...),
+		)
+	}
+
+	policies := make([]sortition.TransactionPolicy, 0)
+	minimumJoinStake := sortitionConfig.MinimumJoinStake.Int
+	if minimumJoinStake != nil && minimumJoinStake.Sign() > 0 {
+		policies = append(
+			policies,
+			sortition.NewMinimumStakePolicy(minimumJoinStake),
+		)
+	}
+	if len(sortitionConfig.MaintenanceWindows) > 0 {
+		windows := make([]*sortition.MaintenanceWindow, 0)
+		for _, text := range sortitionConfig.MaintenanceWindows {
+			window, err := sortition.ParseMaintenanceWindow(text)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"could not parse maintenance window: [%w]",
+					err,
+				)
+			}
+
+			windows = append(windows, window)
+		}
+
+		policies = append(
+			policies,
+			sortition.NewMaintenanceWindowPolicy(windows...),
+		)
+	}
+
+	if len(policies) > 0 {
+		logger.Infof(
+			"[%d] sortition pool transaction policies configured",
+			len(policies),
+		)
+
+		options = append(
+			options,
+			sortition.WithTransactionPolicies(policies...),
+		)
+	}
+
+	return options, nil
+}
+
 func initializeClientInfo(
 	ctx context.Context,
 	config *config.Config,
@@ -220,6 +474,21 @@ func initializeClientInfo(
 		config.ClientInfo.NetworkMetricsTick,
 	)
 
+	registry.ObserveBroadcastCompression(
+		netProvider,
+		config.ClientInfo.NetworkMetricsTick,
+	)
+
+	registry.ObserveBroadcastDuplicates(
+		netProvider,
+		config.ClientInfo.NetworkMetricsTick,
+	)
+
+	registry.ObserveNetworkStats(
+		netProvider,
+		config.ClientInfo.NetworkMetricsTick,
+	)
+
 	registry.ObserveEthConnectivity(
 		blockCounter,
 		config.ClientInfo.EthereumMetricsTick,
@@ -229,6 +498,8 @@ func initializeClientInfo(
 
 	registry.RegisterConnectedPeersSource(netProvider, signing)
 
+	registry.RegisterBroadcastChannelsSource(netProvider)
+
 	registry.RegisterClientInfoSource(
 		netProvider,
 		signing,
@@ -246,10 +517,14 @@ func initializeClientInfo(
 	return registry
 }
 
-func initializePersistence() (
+func initializePersistence(
+	ctx context.Context,
+	backupRequests <-chan struct{},
+) (
 	beaconKeyStorePersistence persistence.ProtectedHandle,
 	tbtcKeyStorePersistence persistence.ProtectedHandle,
 	tbtcDataPersistence persistence.BasicHandle,
+	bitcoinDataPersistence persistence.BasicHandle,
 	err error,
 ) {
 	storage, err := storage.Initialize(
@@ -257,14 +532,28 @@ func initializePersistence() (
 		clientConfig.Ethereum.KeyFilePassword,
 	)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+		return nil, nil, nil, nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+	}
+
+	keyRotationInProgress, err := storage.KeyRotationInProgress()
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf(
+			"cannot check storage key rotation: [%w]",
+			err,
+		)
+	}
+	if keyRotationInProgress {
+		return nil, nil, nil, nil, fmt.Errorf(
+			"storage key rotation is in progress; complete it with the " +
+				"storage reencrypt --in-place command before starting the node",
+		)
 	}
 
 	beaconKeyStorePersistence, err = storage.InitializeKeyStorePersistence(
 		"beacon",
 	)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf(
+		return nil, nil, nil, nil, fmt.Errorf(
 			"cannot initialize beacon keystore persistence: [%w]",
 			err,
 		)
@@ -274,7 +563,7 @@ func initializePersistence() (
 		"tbtc",
 	)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf(
+		return nil, nil, nil, nil, fmt.Errorf(
 			"cannot initialize tbtc keystore persistence: [%w]",
 			err,
 		)
@@ -282,11 +571,86 @@ func initializePersistence() (
 
 	tbtcDataPersistence, err = storage.InitializeWorkPersistence("tbtc")
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf(
+		return nil, nil, nil, nil, fmt.Errorf(
 			"cannot initialize tbtc data persistence: [%w]",
 			err,
 		)
 	}
 
+	if err := encryptPlaintextPreParams(&storage); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	bitcoinDataPersistence, err = storage.InitializeWorkPersistence("bitcoin")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf(
+			"cannot initialize bitcoin data persistence: [%w]",
+			err,
+		)
+	}
+
+	if clientConfig.Storage.Backup.Dir != "" {
+		err = startStorageBackups(
+			ctx,
+			&storage,
+			clientConfig.Storage.Backup,
+			backupRequests,
+		)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf(
+				"cannot start key store backups: [%w]",
+				err,
+			)
+		}
+	}
+
 	return
 }
+
+// startStorageBackups starts periodic backups of the given storage's key store
+// according to the given configuration. Additional backups are made upon
+// requests received from the given channel.
+func startStorageBackups(
+	ctx context.Context,
+	nodeStorage *storage.Storage,
+	config storage.BackupConfig,
+	backupRequests <-chan struct{},
+) error {
+	backupTarget, err := storage.NewDirBackupTarget(config.Dir)
+	if err != nil {
+		return fmt.Errorf("cannot initialize backup target: [%w]", err)
+	}
+
+	go nodeStorage.RunBackups(
+		ctx,
+		backupTarget,
+		config.Interval,
+		config.Retention,
+		backupRequests,
+	)
+
+	return nil
+}
+
+// encryptPlaintextPreParams encrypts tbtc pre-parameters persisted without
+// encryption by older client versions.
+func encryptPlaintextPreParams(storage *storage.Storage) error {
+	encryptedCount, err := storage.EncryptPlaintextWorkData(
+		filepath.Join("tbtc", dkg.PreParamsDirName),
+		dkg.IsPlaintextPreParams,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot encrypt plaintext tbtc pre-parameters: [%w]",
+			err,
+		)
+	}
+	if encryptedCount > 0 {
+		logger.Infof(
+			"encrypted [%d] plaintext tbtc pre-parameters",
+			encryptedCount,
+		)
+	}
+
+	return nil
+}
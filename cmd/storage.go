@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/storage"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// newStoragePasswordEnvVariable is the environment variable the new storage
+// password is read from. The password is prompted for if the variable is not
+// set.
+const newStoragePasswordEnvVariable = "KEEP_NEW_ETHEREUM_PASSWORD"
+
+// StorageCommand contains the definition of tools allowing to maintain
+// the node's storage.
+var StorageCommand = &cobra.Command{
+	Use:              "storage",
+	Short:            "Storage Tools",
+	Long:             "The tool exposes commands allowing to maintain the node's storage.",
+	TraverseChildren: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			config.StorageCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+}
+
+// inPlaceFlagName is the name of the flag making the storage re-encrypted
+// in place.
+const inPlaceFlagName = "in-place"
+
+var reEncryptStorageCommand = cobra.Command{
+	Use:              "reencrypt",
+	Short:            "re-encrypt storage with a new password",
+	Long:             reEncryptStorageCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inPlace, err := cmd.Flags().GetBool(inPlaceFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find in-place flag: %v", err)
+		}
+
+		newPassword, err := readNewStoragePassword()
+		if err != nil {
+			return fmt.Errorf("cannot read new password: [%w]", err)
+		}
+
+		if err := validateNewStoragePassword(newPassword); err != nil {
+			return err
+		}
+
+		storage, err := storage.Initialize(
+			clientConfig.Storage,
+			clientConfig.Ethereum.KeyFilePassword,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot initialize storage: [%w]", err)
+		}
+
+		// Plaintext pre-parameters decrypt with neither password and
+		// would block the re-encryption.
+		if err := encryptPlaintextPreParams(&storage); err != nil {
+			return err
+		}
+
+		if inPlace {
+			if err := storage.RotateKey(newPassword); err != nil {
+				return fmt.Errorf(
+					"cannot re-encrypt storage in place; run the command "+
+						"again to resume: [%w]",
+					err,
+				)
+			}
+
+			logger.Infof(
+				"storage re-encrypted with the new password; start the " +
+					"node with the new password",
+			)
+
+			return nil
+		}
+
+		backupDirs, err := storage.ReEncrypt(newPassword)
+		if err != nil {
+			return fmt.Errorf("cannot re-encrypt storage: [%w]", err)
+		}
+
+		logger.Infof(
+			"storage re-encrypted with the new password; start the node " +
+				"with the new password and remove the backup directories " +
+				"once the node is confirmed to work correctly",
+		)
+		for _, backupDir := range backupDirs {
+			logger.Infof("backup directory: [%s]", backupDir)
+		}
+
+		return nil
+	},
+}
+
+var reEncryptStorageCommandDescription = "Re-encrypts all data persisted " +
+	"in the node's keystore and work directories, including wallet key " +
+	"shares, DKG data, and pre-parameters, with a new password. The new " +
+	"password is read from the " + newStoragePasswordEnvVariable +
+	" environment variable or prompted for. As the node decrypts the " +
+	"storage with the operator key file password, the key file must be " +
+	"re-encrypted with the new password beforehand. All files are " +
+	"checked first; if any of them decrypts with neither password, they " +
+	"are listed and nothing is changed. Quarantined entries are kept as " +
+	"they are. The node must be stopped during the re-encryption.\n\n" +
+	"By default, data are re-encrypted into staging directories and " +
+	"verified before they atomically replace the original directories, " +
+	"which are kept as backups. With the --" + inPlaceFlagName + " flag, " +
+	"files are re-encrypted in place one by one, so no additional disk " +
+	"space is needed. Progress is then tracked in a journal kept in the " +
+	"storage directory; if the re-encryption is interrupted, running the " +
+	"command again with the same passwords resumes it and the node " +
+	"refuses to start until it is completed."
+
+var migrateStorageCommand = cobra.Command{
+	Use:              "migrate",
+	Short:            "migrate wallet storage to the current format",
+	Long:             migrateStorageCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyStorePersistence, err := initializeTbtcKeyStorePersistence()
+		if err != nil {
+			return err
+		}
+
+		fromVersion, err := tbtc.MigrateWalletStorage(keyStorePersistence)
+		if err != nil {
+			return fmt.Errorf("cannot migrate wallet storage: [%w]", err)
+		}
+
+		if fromVersion == tbtc.CurrentWalletStorageSchemaVersion {
+			logger.Infof(
+				"wallet storage is already in the current schema "+
+					"version [%v]",
+				fromVersion,
+			)
+			return nil
+		}
+
+		logger.Infof(
+			"wallet storage migrated from schema version [%v] to [%v]",
+			fromVersion,
+			tbtc.CurrentWalletStorageSchemaVersion,
+		)
+
+		return nil
+	},
+}
+
+var migrateStorageCommandDescription = "Upgrades the wallet data kept in " +
+	"the node's keystore directory to the current storage schema version. " +
+	"The node runs the migration automatically on start; the command " +
+	"allows to run it beforehand. An interrupted migration is resumed " +
+	"by running the command again. The node must be stopped during " +
+	"the migration."
+
+var backupStorageCommand = cobra.Command{
+	Use:              "backup",
+	Short:            "back up the key store",
+	Long:             backupStorageCommandDescription,
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodeStorage, backupTarget, err := initializeBackupStorage()
+		if err != nil {
+			return err
+		}
+
+		name, err := nodeStorage.Backup(backupTarget)
+		if err != nil {
+			return fmt.Errorf("cannot back up key store: [%w]", err)
+		}
+
+		logger.Infof("key store backed up as [%s]", name)
+
+		return nil
+	},
+}
+
+var backupStorageCommandDescription = "Backs up the node's keystore " +
+	"directory to the backup directory configured with the " +
+	"storage.backup.dir property. The backed up data stay encrypted. " +
+	"The backup is verified once stored. Use it to take a backup " +
+	"on demand; the node takes backups periodically while running " +
+	"if the backup directory is configured."
+
+var restoreStorageCommand = cobra.Command{
+	Use:              "restore",
+	Short:            "restore the key store from a backup",
+	Long:             restoreStorageCommandDescription,
+	TraverseChildren: true,
+	Args:             cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodeStorage, backupTarget, err := initializeBackupStorage()
+		if err != nil {
+			return err
+		}
+
+		replacedDir, err := nodeStorage.RestoreBackup(backupTarget, args[0])
+		if err != nil {
+			return fmt.Errorf("cannot restore key store: [%w]", err)
+		}
+
+		logger.Infof(
+			"key store restored from backup [%s]; the replaced key store "+
+				"was moved to [%s] and can be removed once the node is "+
+				"confirmed to work correctly",
+			args[0],
+			replacedDir,
+		)
+
+		return nil
+	},
+}
+
+var restoreStorageCommandDescription = "Restores the node's keystore " +
+	"directory from the given backup kept in the backup directory " +
+	"configured with the storage.backup.dir property. The backup is " +
+	"extracted to a staging directory and verified to decrypt with " +
+	"the current password before it atomically replaces the keystore " +
+	"directory. The replaced keystore directory is kept. The node must " +
+	"be stopped during the restore.\n\n" +
+	"Usage: storage restore <backup-name>"
+
+// initializeBackupStorage initializes the storage and the key store backup
+// target configured for the node.
+func initializeBackupStorage() (*storage.Storage, storage.BackupTarget, error) {
+	if clientConfig.Storage.Backup.Dir == "" {
+		return nil, nil, fmt.Errorf("backup directory is not configured")
+	}
+
+	nodeStorage, err := storage.Initialize(
+		clientConfig.Storage,
+		clientConfig.Ethereum.KeyFilePassword,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+	}
+
+	backupTarget, err := storage.NewDirBackupTarget(
+		clientConfig.Storage.Backup.Dir,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot initialize backup target: [%w]",
+			err,
+		)
+	}
+
+	return &nodeStorage, backupTarget, nil
+}
+
+// validateNewStoragePassword checks whether the given new storage password
+// differs from the current one and decrypts the operator key file. The node
+// uses the operator key file password to decrypt the storage so the key file
+// must be re-encrypted with the new password before the storage is, for the
+// node to be able to start afterwards.
+func validateNewStoragePassword(newPassword string) error {
+	if newPassword == clientConfig.Ethereum.KeyFilePassword {
+		return fmt.Errorf("new password must differ from the current one")
+	}
+
+	if _, err := ethutil.DecryptKeyFile(
+		clientConfig.Ethereum.Account.KeyFile,
+		newPassword,
+	); err != nil {
+		return fmt.Errorf(
+			"cannot decrypt operator key file with the new password; "+
+				"the key file must be re-encrypted with the new "+
+				"password first: [%w]",
+			err,
+		)
+	}
+
+	return nil
+}
+
+// readNewStoragePassword reads the new storage password from the environment
+// variable or prompts for it and its confirmation.
+func readNewStoragePassword() (string, error) {
+	if password := os.Getenv(newStoragePasswordEnvVariable); password != "" {
+		return password, nil
+	}
+
+	password, err := promptPassphrase("Enter new password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if len(password) == 0 {
+		return "", fmt.Errorf("password must not be empty")
+	}
+
+	confirmation, err := promptPassphrase("Confirm new password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if confirmation != password {
+		return "", fmt.Errorf("passwords do not match")
+	}
+
+	return password, nil
+}
+
+func init() {
+	initFlags(
+		StorageCommand,
+		&configFilePath,
+		clientConfig,
+		config.StorageCategories...,
+	)
+
+	reEncryptStorageCommand.Flags().Bool(
+		inPlaceFlagName,
+		false,
+		"re-encrypt files in place instead of using staging directories",
+	)
+
+	StorageCommand.AddCommand(&reEncryptStorageCommand)
+	StorageCommand.AddCommand(&migrateStorageCommand)
+	StorageCommand.AddCommand(&backupStorageCommand)
+	StorageCommand.AddCommand(&restoreStorageCommand)
+}
@@ -0,0 +1,21 @@
+package config
+
+// resolveBitcoinProxy sets the proxy URL of Bitcoin backends that do not
+// configure their own proxy to the common Bitcoin proxy URL.
+func (c *Config) resolveBitcoinProxy() {
+	if c.Bitcoin.ProxyURL == "" {
+		return
+	}
+
+	if c.Bitcoin.Electrum.ProxyURL == "" {
+		c.Bitcoin.Electrum.ProxyURL = c.Bitcoin.ProxyURL
+	}
+
+	if c.Bitcoin.Bitcoind.ProxyURL == "" {
+		c.Bitcoin.Bitcoind.ProxyURL = c.Bitcoin.ProxyURL
+	}
+
+	if c.Bitcoin.MempoolSpace.ProxyURL == "" {
+		c.Bitcoin.MempoolSpace.ProxyURL = c.Bitcoin.ProxyURL
+	}
+}
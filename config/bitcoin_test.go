@@ -0,0 +1,156 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/keep-network/keep-core/config/network"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestResolveBitcoinProxy(t *testing.T) {
+	var tests = map[string]struct {
+		configure      func(cfg *BitcoinConfig)
+		expectedConfig func(cfg *BitcoinConfig)
+	}{
+		"no proxy configured": {
+			configure:      func(cfg *BitcoinConfig) {},
+			expectedConfig: func(cfg *BitcoinConfig) {},
+		},
+		"common proxy configured": {
+			configure: func(cfg *BitcoinConfig) {
+				cfg.ProxyURL = "socks5://127.0.0.1:9050"
+			},
+			expectedConfig: func(cfg *BitcoinConfig) {
+				cfg.ProxyURL = "socks5://127.0.0.1:9050"
+				cfg.Electrum.ProxyURL = "socks5://127.0.0.1:9050"
+				cfg.Bitcoind.ProxyURL = "socks5://127.0.0.1:9050"
+				cfg.MempoolSpace.ProxyURL = "socks5://127.0.0.1:9050"
+			},
+		},
+		"backend proxy configured": {
+			configure: func(cfg *BitcoinConfig) {
+				cfg.ProxyURL = "socks5://127.0.0.1:9050"
+				cfg.Electrum.ProxyURL = "socks5://127.0.0.1:9150"
+			},
+			expectedConfig: func(cfg *BitcoinConfig) {
+				cfg.ProxyURL = "socks5://127.0.0.1:9050"
+				cfg.Electrum.ProxyURL = "socks5://127.0.0.1:9150"
+				cfg.Bitcoind.ProxyURL = "socks5://127.0.0.1:9050"
+				cfg.MempoolSpace.ProxyURL = "socks5://127.0.0.1:9050"
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &Config{}
+			test.configure(&cfg.Bitcoin)
+
+			expectedConfig := BitcoinConfig{}
+			test.expectedConfig(&expectedConfig)
+
+			cfg.resolveBitcoinProxy()
+
+			if diff := deep.Equal(cfg.Bitcoin, expectedConfig); diff != nil {
+				t.Errorf("compare failed: %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_BitcoindWithoutElectrum(t *testing.T) {
+	cfg := &Config{}
+	cfg.Bitcoin.Bitcoind.URL = "http://127.0.0.1:8332"
+
+	err := validateConfig(cfg, BitcoinElectrum)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	expectedError := "1 error occurred:\n" +
+		"\t* missing value for bitcoin.electrum.url; Electrum server is " +
+		"required alongside bitcoin.bitcoind.url to look up transactions by " +
+		"address; see bitcoin section in configuration\n\n"
+	if err.Error() != expectedError {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedError,
+			err,
+		)
+	}
+
+	cfg.Bitcoin.Electrum.URL = "tcp://127.0.0.1:50001"
+	if err := validateConfig(cfg, BitcoinElectrum); err != nil {
+		t.Errorf("unexpected error: [%v]", err)
+	}
+}
+
+func TestResolveBitcoinNetwork(t *testing.T) {
+	var tests = map[string]struct {
+		clientNetwork   network.Type
+		networkName     string
+		expectedNetwork bitcoin.Network
+		expectedError   string
+	}{
+		"network not selected": {
+			clientNetwork:   network.Testnet,
+			networkName:     "",
+			expectedNetwork: bitcoin.Testnet,
+		},
+		"testnet4 on testnet client": {
+			clientNetwork:   network.Testnet,
+			networkName:     "testnet4",
+			expectedNetwork: bitcoin.Testnet4,
+		},
+		"network matching client network": {
+			clientNetwork:   network.Mainnet,
+			networkName:     "mainnet",
+			expectedNetwork: bitcoin.Mainnet,
+		},
+		"testnet4 on mainnet client": {
+			clientNetwork:   network.Mainnet,
+			networkName:     "testnet4",
+			expectedNetwork: bitcoin.Mainnet,
+			expectedError: "[testnet4] Bitcoin network cannot be used with " +
+				"[mainnet] client network",
+		},
+		"unknown network": {
+			clientNetwork:   network.Testnet,
+			networkName:     "signet",
+			expectedNetwork: bitcoin.Testnet,
+			expectedError:   "unknown Bitcoin network [signet]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Bitcoin.Network = test.clientNetwork.Bitcoin()
+			cfg.Bitcoin.NetworkName = test.networkName
+
+			err := cfg.resolveBitcoinNetwork(test.clientNetwork)
+
+			actualError := ""
+			if err != nil {
+				actualError = err.Error()
+			}
+			if actualError != test.expectedError {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					actualError,
+				)
+			}
+
+			if cfg.Bitcoin.Network != test.expectedNetwork {
+				t.Errorf(
+					"unexpected network\nexpected: %v\nactual:   %v\n",
+					test.expectedNetwork,
+					cfg.Bitcoin.Network,
+				)
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ const (
 	Tbtc
 	Maintainer
 	Developer
+	Sortition
+	Admin
 )
 
 // StartCmdCategories are categories needed for the start command.
@@ -24,15 +26,46 @@ var StartCmdCategories = []Category{
 	ClientInfo,
 	Tbtc,
 	Developer,
+	Sortition,
+	Admin,
 }
 
 // MaintainerCategories are categories needed for the maintainer command.
 var MaintainerCategories = []Category{
 	Ethereum,
 	BitcoinElectrum,
+	Storage,
+	ClientInfo,
 	Maintainer,
 }
 
+// DebugCategories are categories needed for the debug command.
+var DebugCategories = []Category{
+	General,
+	Ethereum,
+	Storage,
+}
+
+// SignerCategories are categories needed for the signer command.
+var SignerCategories = []Category{
+	General,
+	Ethereum,
+	Storage,
+}
+
+// StorageCategories are categories needed for the storage command.
+var StorageCategories = []Category{
+	General,
+	Ethereum,
+	Storage,
+}
+
+// ExitCategories are categories needed for the exit command.
+var ExitCategories = []Category{
+	General,
+	Admin,
+}
+
 // AllCategories are all available categories.
 var AllCategories = []Category{
 	General,
@@ -44,4 +77,6 @@ var AllCategories = []Category{
 	Tbtc,
 	Maintainer,
 	Developer,
+	Sortition,
+	Admin,
 }
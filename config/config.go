@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"math/rand"
+	"net/url"
 	"os"
 	"strings"
 	"syscall"
@@ -11,6 +12,7 @@ import (
 	"github.com/keep-network/keep-core/config/network"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/go-multierror"
 	"github.com/ipfs/go-log"
 	"github.com/mitchellh/mapstructure"
@@ -19,10 +21,16 @@ import (
 	"golang.org/x/term"
 
 	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/admin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/failover"
+	"github.com/keep-network/keep-core/pkg/bitcoin/mempoolspace"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
+	"github.com/keep-network/keep-core/pkg/firewall"
 	"github.com/keep-network/keep-core/pkg/maintainer"
 	"github.com/keep-network/keep-core/pkg/net/libp2p"
+	"github.com/keep-network/keep-core/pkg/sortition"
 	"github.com/keep-network/keep-core/pkg/storage"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
@@ -48,17 +56,63 @@ type Config struct {
 	Ethereum   commonEthereum.Config
 	Bitcoin    BitcoinConfig
 	LibP2P     libp2p.Config `mapstructure:"network"`
+	Firewall   firewall.Config
+	Admin      admin.Config
 	Storage    storage.Config
 	ClientInfo clientinfo.Config
 	Maintainer maintainer.Config
 	Tbtc       tbtc.Config
+	Sortition  sortition.Config
 }
 
 // BitcoinConfig defines the configuration for Bitcoin.
 type BitcoinConfig struct {
-	bitcoin.Network
+	// Network is the Bitcoin network resolved from the client network or
+	// from NetworkName if it is set.
+	bitcoin.Network `mapstructure:"-"`
+	// NetworkName selects the Bitcoin network explicitly, e.g. `testnet4`.
+	// It must correspond to the client network. The testnet client may run
+	// against either `testnet` or `testnet4`. If empty, the Bitcoin network
+	// is resolved from the client network.
+	NetworkName string `mapstructure:"network"`
 	// Electrum defines the configuration for the Electrum client.
 	Electrum electrum.Config
+	// Bitcoind defines the configuration for the Bitcoin Core client. If its
+	// URL is set, the Bitcoin Core node is used instead of the Electrum
+	// server. Bitcoin Core does not index addresses so lookups of
+	// transactions by public key hash are routed to the Electrum server,
+	// which has to be configured as well.
+	Bitcoind bitcoind.Config
+	// FailoverElectrumURLs are URLs of Electrum servers used as failover
+	// backends of the primary Bitcoin backend. All other connection
+	// properties are taken from the Electrum config.
+	FailoverElectrumURLs []string
+	// Failover defines the configuration of the failover between the primary
+	// Bitcoin backend and the failover Electrum servers. It is used only if
+	// failover Electrum servers are set.
+	Failover failover.Config
+	// FeeEstimation defines the configuration of transaction fee estimation.
+	FeeEstimation FeeEstimationConfig
+	// MempoolSpace defines the configuration of the mempool.space API client.
+	// If its URL is set, the API is used as an additional fee source and to
+	// verify whether broadcasted transactions landed in the mempool.
+	MempoolSpace mempoolspace.Config
+	// ProxyURL is the URL of the SOCKS5 proxy connections to all Bitcoin
+	// backends are routed through, e.g. `socks5://127.0.0.1:9050` for a local
+	// Tor daemon. It is used by backends that do not configure their own
+	// proxy. If empty, the connections are established directly.
+	ProxyURL string
+}
+
+// FeeEstimationConfig defines the configuration of Bitcoin transaction fee
+// estimation.
+type FeeEstimationConfig struct {
+	// MinSatPerVByteFee is the minimum sat/vbyte fee used for transactions.
+	// Lower estimates are replaced by this value. Zero disables the bound.
+	MinSatPerVByteFee int64
+	// MaxSatPerVByteFee is the maximum sat/vbyte fee used for transactions.
+	// Higher estimates are replaced by this value. Zero disables the bound.
+	MaxSatPerVByteFee int64
 }
 
 // Bind the flags to the viper configuration. Viper reads configuration from
@@ -107,6 +161,43 @@ func (c *Config) resolveNetworks(flagSet *pflag.FlagSet) (network.Type, error) {
 	return clientNetwork, err
 }
 
+// resolveBitcoinNetwork overrides the Bitcoin network resolved from the
+// client network with the one selected explicitly in the config, if any.
+func (c *Config) resolveBitcoinNetwork(clientNetwork network.Type) error {
+	if c.Bitcoin.NetworkName == "" {
+		return nil
+	}
+
+	bitcoinNetwork, err := bitcoin.ParseNetwork(c.Bitcoin.NetworkName)
+	if err != nil {
+		return err
+	}
+
+	// Testnet4 is the only Bitcoin network that is not resolved from any
+	// client network. It may replace testnet on the testnet client.
+	isCompatible := bitcoinNetwork == clientNetwork.Bitcoin() ||
+		(bitcoinNetwork == bitcoin.Testnet4 && clientNetwork == network.Testnet)
+	if !isCompatible {
+		return fmt.Errorf(
+			"[%v] Bitcoin network cannot be used with [%v] client network",
+			bitcoinNetwork,
+			clientNetwork,
+		)
+	}
+
+	if bitcoinNetwork != c.Bitcoin.Network {
+		logger.Infof(
+			"using [%v] Bitcoin network instead of [%v]",
+			bitcoinNetwork,
+			c.Bitcoin.Network,
+		)
+	}
+
+	c.Bitcoin.Network = bitcoinNetwork
+
+	return nil
+}
+
 // ReadConfig reads in the configuration file at `configFilePath` and flags defined in
 // the `flagSet`.
 func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categories ...Category) error {
@@ -150,6 +241,12 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 		return fmt.Errorf("failed to resolve peers: %w", err)
 	}
 
+	// Resolve Bitcoin network.
+	err = c.resolveBitcoinNetwork(clientNetwork)
+	if err != nil {
+		return fmt.Errorf("failed to resolve Bitcoin network: %w", err)
+	}
+
 	// Resolve Electrum server.
 	// #nosec G404 (insecure random number source (rand))
 	// Picking up an Electrum server does not require secure randomness.
@@ -158,6 +255,12 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 		return fmt.Errorf("failed to resolve Electrum: %w", err)
 	}
 
+	// Resolve Bitcoin backends proxy.
+	c.resolveBitcoinProxy()
+
+	// Resolve SPV maintainer defaults.
+	c.resolveSpvMaintainer(clientNetwork)
+
 	// Validate configuration.
 	if err := validateConfig(c, categories...); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -210,9 +313,17 @@ func validateConfig(config *Config, categories ...Category) error {
 				))
 			}
 		case BitcoinElectrum:
-			if config.Bitcoin.Electrum.URL == "" {
+			if config.Bitcoin.Electrum.URL == "" &&
+				config.Bitcoin.Bitcoind.URL == "" {
 				result = multierror.Append(result, fmt.Errorf(
-					"missing value for bitcoin.electrum.url; see bitcoin electrum section in configuration",
+					"missing value for bitcoin.electrum.url or bitcoin.bitcoind.url; see bitcoin section in configuration",
+				))
+			}
+
+			if config.Bitcoin.Bitcoind.URL != "" &&
+				config.Bitcoin.Electrum.URL == "" {
+				result = multierror.Append(result, fmt.Errorf(
+					"missing value for bitcoin.electrum.url; Electrum server is required alongside bitcoin.bitcoind.url to look up transactions by address; see bitcoin section in configuration",
 				))
 			}
 		case Network:
@@ -227,6 +338,93 @@ func validateConfig(config *Config, categories ...Category) error {
 					"missing value for storage.dir; see storage section in configuration",
 				))
 			}
+
+			if strings.Contains(config.Storage.Backup.Dir, "://") {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for storage.backup.dir; must be a local "+
+						"directory, remote storage URLs are not supported",
+				))
+			}
+
+			if config.Storage.Backup.Dir != "" &&
+				config.Storage.Backup.Interval <= 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for storage.backup.interval; must be positive",
+				))
+			}
+		case Maintainer:
+			if config.Maintainer.Spv.TransactionLimit < 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for maintainer.spv.transactionLimit; must not be negative",
+				))
+			}
+
+			if config.Maintainer.Spv.ProofWorkers < 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for maintainer.spv.proofWorkers; must not be negative",
+				))
+			}
+
+			gasTipCap := config.Maintainer.Spv.GasTipCap.Int
+			gasFeeCap := config.Maintainer.Spv.GasFeeCap.Int
+			if gasTipCap != nil && gasFeeCap != nil && gasFeeCap.Sign() > 0 &&
+				gasTipCap.Cmp(gasFeeCap) > 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for maintainer.spv.gasTipCap; must not be greater than maintainer.spv.gasFeeCap",
+				))
+			}
+
+			if config.Maintainer.Spv.RestartBackoffTime < 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for maintainer.spv.restartBackoffTime; must not be negative",
+				))
+			}
+
+			if config.Maintainer.Spv.IdleBackoffTime < 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for maintainer.spv.idleBackoffTime; must not be negative",
+				))
+			}
+
+			for name, networkConfig := range config.Maintainer.Spv.Networks {
+				if !isClientNetworkName(name) {
+					result = multierror.Append(result, fmt.Errorf(
+						"invalid network [%s] in maintainer.spv.networks",
+						name,
+					))
+				}
+
+				if networkConfig.TransactionLimit < 0 {
+					result = multierror.Append(result, fmt.Errorf(
+						"invalid value for maintainer.spv.networks.%s.transactionLimit; must not be negative",
+						name,
+					))
+				}
+			}
+		case Sortition:
+			if config.Sortition.StakingProvider != "" &&
+				!common.IsHexAddress(config.Sortition.StakingProvider) {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for sortition.stakingProvider; must be an address",
+				))
+			}
+			if webhook := config.Sortition.RewardsIneligibilityWebhook; webhook != "" {
+				if parsed, err := url.ParseRequestURI(webhook); err != nil ||
+					(parsed.Scheme != "http" && parsed.Scheme != "https") {
+					result = multierror.Append(result, fmt.Errorf(
+						"invalid value for sortition.rewardsIneligibilityWebhook; "+
+							"must be an HTTP or HTTPS URL",
+					))
+				}
+			}
+			for _, window := range config.Sortition.MaintenanceWindows {
+				if _, err := sortition.ParseMaintenanceWindow(window); err != nil {
+					result = multierror.Append(result, fmt.Errorf(
+						"invalid value for sortition.maintenanceWindows: [%w]",
+						err,
+					))
+				}
+			}
 		}
 	}
 
@@ -18,6 +18,7 @@ import (
 	ethereumEcdsa "github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen"
 	ethereumTbtc "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen"
 	ethereumThreshold "github.com/keep-network/keep-core/pkg/chain/ethereum/threshold/gen"
+	"github.com/keep-network/keep-core/pkg/maintainer/spv"
 )
 
 func TestReadConfigFromFile(t *testing.T) {
@@ -179,10 +180,104 @@ func TestReadConfigFromFile(t *testing.T) {
 				"/ip4/80.70.60.50/tcp/3919",
 			},
 		},
+		"Network.DNSSeeds": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.DNSSeeds },
+			expectedValue: []string{"bootstrap.example.com"},
+		},
+		"Network.BootstrapRefreshPeriod": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.BootstrapRefreshPeriod },
+			expectedValue: 12 * time.Minute,
+		},
+		"Network.PrivateNetworkKeyFile": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.PrivateNetworkKeyFile },
+			expectedValue: "/my/secure/swarm.key",
+		},
 		"Network.DisseminationTime": {
 			readValueFunc: func(c *Config) interface{} { return c.LibP2P.DisseminationTime },
 			expectedValue: 76,
 		},
+		"Network.CompressionThreshold": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.CompressionThreshold },
+			expectedValue: 2048,
+		},
+		"Network.SeenMessages.TTL": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.SeenMessages.TTL },
+			expectedValue: 3 * time.Minute,
+		},
+		"Network.SeenMessages.Strategy": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.SeenMessages.Strategy },
+			expectedValue: "first-seen",
+		},
+		"Network.RateLimit.Enabled": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.RateLimit.Enabled },
+			expectedValue: true,
+		},
+		"Network.RateLimit.MessagesPerSecond": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.RateLimit.MessagesPerSecond },
+			expectedValue: 20.5,
+		},
+		"Network.RateLimit.Burst": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.RateLimit.Burst },
+			expectedValue: 200,
+		},
+		"Network.RateLimit.DisconnectThreshold": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.RateLimit.DisconnectThreshold },
+			expectedValue: 40,
+		},
+		"Network.ConnectionGater.AllowedPeers": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.ConnectionGater.AllowedPeers },
+			expectedValue: []string{"16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX"},
+		},
+		"Network.ConnectionGater.DeniedPeers": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.ConnectionGater.DeniedPeers },
+			expectedValue: []string{"16Uiu2HAm3eJtyFKAttzJ85NLMromHuRg4yyum3CREMf6CHBBV6KY"},
+		},
+		"Network.ConnectionGater.AllowedIPs": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.ConnectionGater.AllowedIPs },
+			expectedValue: []string{"10.0.0.1"},
+		},
+		"Network.ConnectionGater.DeniedIPs": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.ConnectionGater.DeniedIPs },
+			expectedValue: []string{"10.0.0.0/8", "192.168.1.1"},
+		},
+		"Network.SeenMessages.CacheSize": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.SeenMessages.CacheSize },
+			expectedValue: 20000,
+		},
+		"Network.PeerScoring.Enabled": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.PeerScoring.Enabled },
+			expectedValue: true,
+		},
+		"Network.PeerScoring.InvalidMessagePenaltyDecay": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.PeerScoring.InvalidMessagePenaltyDecay },
+			expectedValue: 15 * time.Minute,
+		},
+		"Network.NAT.Reachability": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.NAT.Reachability },
+			expectedValue: "private",
+		},
+		"Network.NAT.HolePunching": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.NAT.HolePunching },
+			expectedValue: true,
+		},
+		"Network.NAT.Relays": {
+			readValueFunc: func(c *Config) interface{} { return c.LibP2P.NAT.Relays },
+			expectedValue: []string{
+				"/ip4/80.70.60.50/tcp/3919/ipfs/16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX",
+			},
+		},
+		"Firewall.MinimumAuthorization": {
+			readValueFunc: func(c *Config) interface{} { return c.Firewall.MinimumAuthorization },
+			expectedValue: true,
+		},
+		"Firewall.GracePeriod": {
+			readValueFunc: func(c *Config) interface{} { return c.Firewall.GracePeriod },
+			expectedValue: 45 * time.Minute,
+		},
+		"Admin.Port": {
+			readValueFunc: func(c *Config) interface{} { return c.Admin.Port },
+			expectedValue: 9701,
+		},
 		"Storage.Dir": {
 			readValueFunc: func(c *Config) interface{} { return c.Storage.Dir },
 			expectedValue: "/my/secure/location",
@@ -215,10 +310,27 @@ func TestReadConfigFromFile(t *testing.T) {
 			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.HistoryDepth },
 			expectedValue: uint64(25000),
 		},
+		"Maintainer.Spv.RescanFromBlock": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.RescanFromBlock },
+			expectedValue: uint64(16000000),
+		},
 		"Maintainer.Spv.TransactionLimit": {
 			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.TransactionLimit },
 			expectedValue: 80,
 		},
+		"Maintainer.Spv.Networks": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.Networks },
+			expectedValue: map[string]spv.NetworkConfig{
+				"testnet": {HistoryDepth: 40000, TransactionLimit: 120},
+			},
+		},
+		"Maintainer.Spv.FallbackElectrumURLs": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.FallbackElectrumURLs },
+			expectedValue: []string{
+				"tcp://fallback-1.electrum:50001",
+				"tcp://fallback-2.electrum:50001",
+			},
+		},
 		"Maintainer.Spv.RestartBackoffTime": {
 			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.RestartBackoffTime },
 			expectedValue: 2 * time.Hour,
@@ -39,9 +39,12 @@ func (c *Config) resolveElectrum(rng *rand.Rand) error {
 		return nil
 	}
 
-	// For unknown and regtest networks we don't expect the Electrum configs to be
-	// embedded in the client. The user should configure it in the config file.
-	if network == bitcoin.Regtest || network == bitcoin.Unknown {
+	// For unknown, regtest, and testnet4 networks we don't expect the Electrum
+	// configs to be embedded in the client. The user should configure it in
+	// the config file.
+	if network == bitcoin.Regtest ||
+		network == bitcoin.Testnet4 ||
+		network == bitcoin.Unknown {
 		logger.Warnf(
 			"Electrum configs were not configured for [%s] network; "+
 				"see bitcoin section in configuration",
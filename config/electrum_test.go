@@ -46,6 +46,14 @@ func TestResolveElectrum(t *testing.T) {
 				},
 			},
 		},
+		bitcoin.Testnet4: {
+			expectedConfig: []electrum.Config{
+				{
+					URL:               "",
+					KeepAliveInterval: 0,
+				},
+			},
+		},
 		bitcoin.Unknown: {
 			expectedConfig: []electrum.Config{
 				{
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"github.com/keep-network/keep-core/config/network"
+	"github.com/keep-network/keep-core/pkg/maintainer/spv"
+)
+
+// resolveSpvMaintainer applies SPV maintainer properties configured for the
+// given client network and sets the default values of properties that were
+// not configured. The latter is the case when the configuration is read
+// without command-line flags whose defaults would be used otherwise.
+func (c *Config) resolveSpvMaintainer(clientNetwork network.Type) {
+	if networkConfig, ok := c.Maintainer.Spv.Networks[clientNetwork.String()]; ok {
+		if networkConfig.HistoryDepth != 0 {
+			c.Maintainer.Spv.HistoryDepth = networkConfig.HistoryDepth
+		}
+
+		if networkConfig.TransactionLimit != 0 {
+			c.Maintainer.Spv.TransactionLimit = networkConfig.TransactionLimit
+		}
+	}
+
+	if c.Maintainer.Spv.HistoryDepth == 0 {
+		c.Maintainer.Spv.HistoryDepth = spv.DefaultHistoryDepth
+	}
+
+	if c.Maintainer.Spv.TransactionLimit == 0 {
+		c.Maintainer.Spv.TransactionLimit = spv.DefaultTransactionLimit
+	}
+
+	if c.Maintainer.Spv.ProofWorkers == 0 {
+		c.Maintainer.Spv.ProofWorkers = spv.DefaultProofWorkers
+	}
+
+	if c.Maintainer.Spv.RestartBackoffTime == 0 {
+		c.Maintainer.Spv.RestartBackoffTime = spv.DefaultRestartBackoffTime
+	}
+
+	if c.Maintainer.Spv.IdleBackoffTime == 0 {
+		c.Maintainer.Spv.IdleBackoffTime = spv.DefaultIdleBackOffTime
+	}
+}
+
+// isClientNetworkName returns true if the given name is the name of a known
+// client network.
+func isClientNetworkName(name string) bool {
+	for _, clientNetwork := range []network.Type{
+		network.Mainnet,
+		network.Testnet,
+		network.Developer,
+	} {
+		if name == clientNetwork.String() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/keep-network/keep-common/pkg/chain/ethereum"
+
+	"github.com/keep-network/keep-core/config/network"
+	"github.com/keep-network/keep-core/pkg/maintainer/spv"
+)
+
+func TestResolveSpvMaintainer(t *testing.T) {
+	var tests = map[string]struct {
+		config         spv.Config
+		expectedConfig spv.Config
+	}{
+		"nothing configured": {
+			config: spv.Config{},
+			expectedConfig: spv.Config{
+				HistoryDepth:       spv.DefaultHistoryDepth,
+				TransactionLimit:   spv.DefaultTransactionLimit,
+				ProofWorkers:       spv.DefaultProofWorkers,
+				RestartBackoffTime: spv.DefaultRestartBackoffTime,
+				IdleBackoffTime:    spv.DefaultIdleBackOffTime,
+			},
+		},
+		"everything configured": {
+			config: spv.Config{
+				Enabled:            true,
+				HistoryDepth:       1000,
+				RescanFromBlock:    500,
+				TransactionLimit:   100,
+				ProofWorkers:       8,
+				RestartBackoffTime: time.Minute,
+				IdleBackoffTime:    time.Second,
+			},
+			expectedConfig: spv.Config{
+				Enabled:            true,
+				HistoryDepth:       1000,
+				RescanFromBlock:    500,
+				TransactionLimit:   100,
+				ProofWorkers:       8,
+				RestartBackoffTime: time.Minute,
+				IdleBackoffTime:    time.Second,
+			},
+		},
+		"client network configured": {
+			config: spv.Config{
+				HistoryDepth:     1000,
+				TransactionLimit: 100,
+				Networks: map[string]spv.NetworkConfig{
+					"testnet": {HistoryDepth: 2000, TransactionLimit: 200},
+					"mainnet": {HistoryDepth: 3000},
+				},
+			},
+			expectedConfig: spv.Config{
+				HistoryDepth:       3000,
+				TransactionLimit:   100,
+				ProofWorkers:       spv.DefaultProofWorkers,
+				RestartBackoffTime: spv.DefaultRestartBackoffTime,
+				IdleBackoffTime:    spv.DefaultIdleBackOffTime,
+				Networks: map[string]spv.NetworkConfig{
+					"testnet": {HistoryDepth: 2000, TransactionLimit: 200},
+					"mainnet": {HistoryDepth: 3000},
+				},
+			},
+		},
+		"other network configured": {
+			config: spv.Config{
+				Networks: map[string]spv.NetworkConfig{
+					"testnet": {HistoryDepth: 2000, TransactionLimit: 200},
+				},
+			},
+			expectedConfig: spv.Config{
+				HistoryDepth:       spv.DefaultHistoryDepth,
+				TransactionLimit:   spv.DefaultTransactionLimit,
+				ProofWorkers:       spv.DefaultProofWorkers,
+				RestartBackoffTime: spv.DefaultRestartBackoffTime,
+				IdleBackoffTime:    spv.DefaultIdleBackOffTime,
+				Networks: map[string]spv.NetworkConfig{
+					"testnet": {HistoryDepth: 2000, TransactionLimit: 200},
+				},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Maintainer.Spv = test.config
+
+			cfg.resolveSpvMaintainer(network.Mainnet)
+
+			if diff := deep.Equal(cfg.Maintainer.Spv, test.expectedConfig); diff != nil {
+				t.Errorf("compare failed: %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_Maintainer(t *testing.T) {
+	cfg := &Config{}
+	cfg.Maintainer.Spv.TransactionLimit = -1
+	cfg.Maintainer.Spv.GasTipCap = *ethereum.WrapWei(big.NewInt(20))
+	cfg.Maintainer.Spv.GasFeeCap = *ethereum.WrapWei(big.NewInt(10))
+	cfg.Maintainer.Spv.IdleBackoffTime = -time.Second
+	cfg.Maintainer.Spv.Networks = map[string]spv.NetworkConfig{
+		"regtest": {TransactionLimit: -1},
+	}
+
+	err := validateConfig(cfg, Maintainer)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	expectedError := "5 errors occurred:\n" +
+		"\t* invalid value for maintainer.spv.transactionLimit; must not be negative\n" +
+		"\t* invalid value for maintainer.spv.gasTipCap; must not be greater than maintainer.spv.gasFeeCap\n" +
+		"\t* invalid value for maintainer.spv.idleBackoffTime; must not be negative\n" +
+		"\t* invalid network [regtest] in maintainer.spv.networks\n" +
+		"\t* invalid value for maintainer.spv.networks.regtest.transactionLimit; must not be negative\n\n"
+	if err.Error() != expectedError {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedError,
+			err,
+		)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateConfig_StorageBackupInterval(t *testing.T) {
+	var tests = map[string]struct {
+		backupDir      string
+		backupInterval time.Duration
+		expectedError  string
+	}{
+		"backups disabled": {
+			backupDir:      "",
+			backupInterval: 0,
+		},
+		"positive interval": {
+			backupDir:      "/backup",
+			backupInterval: time.Hour,
+		},
+		"zero interval": {
+			backupDir:      "/backup",
+			backupInterval: 0,
+			expectedError: "1 error occurred:\n" +
+				"\t* invalid value for storage.backup.interval; " +
+				"must be positive\n\n",
+		},
+		"remote storage url": {
+			backupDir:      "s3://bucket/backup",
+			backupInterval: time.Hour,
+			expectedError: "1 error occurred:\n" +
+				"\t* invalid value for storage.backup.dir; must be a local " +
+				"directory, remote storage URLs are not supported\n\n",
+		},
+		"negative interval": {
+			backupDir:      "/backup",
+			backupInterval: -time.Hour,
+			expectedError: "1 error occurred:\n" +
+				"\t* invalid value for storage.backup.interval; " +
+				"must be positive\n\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Storage.Dir = "/storage"
+			cfg.Storage.Backup.Dir = test.backupDir
+			cfg.Storage.Backup.Interval = test.backupInterval
+
+			err := validateConfig(cfg, Storage)
+
+			actualError := ""
+			if err != nil {
+				actualError = err.Error()
+			}
+
+			if actualError != test.expectedError {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					actualError,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+// Package admin provides the admin API allowing operators to change selected
+// client settings and request the operator exit at runtime.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/sortition"
+)
+
+var logger = log.Logger("keep-admin")
+
+const readHeaderTimeout = 2 * time.Second
+
+// Config stores configuration for the admin API.
+type Config struct {
+	// Port is the admin API HTTP server listening port. The server listens
+	// on the loopback interface only as the API is not authenticated.
+	// The admin API is disabled if the port is zero.
+	Port int
+}
+
+// Server is the admin API server. Client components register their handlers
+// in the server once they are initialized.
+type Server struct {
+	mux *http.ServeMux
+}
+
+// Initialize sets up the admin API server listening on the loopback
+// interface. The server is closed when the context is done. It returns nil
+// if the admin API is not configured.
+func Initialize(
+	ctx context.Context,
+	config Config,
+	netProvider net.Provider,
+) *Server {
+	if config.Port == 0 {
+		return nil
+	}
+
+	adminServer := &Server{
+		mux: newHandler(netProvider),
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf("127.0.0.1:%d", config.Port),
+		Handler:           adminServer.mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			logger.Errorf("admin API server error: [%v]", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Close(); err != nil {
+			logger.Errorf("could not close admin API server: [%v]", err)
+		}
+	}()
+
+	return adminServer
+}
+
+func newHandler(netProvider net.Provider) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	if gater, ok := netProvider.(net.ConnectionGater); ok {
+		mux.HandleFunc("/connection-gater", connectionGaterHandler(gater))
+	} else {
+		logger.Infof(
+			"network provider [%v] does not support connection gating",
+			netProvider.Type(),
+		)
+	}
+
+	return mux
+}
+
+// RegisterParameterPool exposes the parameter pool with the given name under
+// the /parameter-pools/<name> path.
+func (s *Server) RegisterParameterPool(name string, pool ParameterPool) {
+	s.mux.HandleFunc("/parameter-pools/"+name, parameterPoolHandler(pool))
+}
+
+// RegisterOperatorExit exposes the operator exit under the /operator-exit
+// path.
+func (s *Server) RegisterOperatorExit(exit OperatorExit) {
+	s.mux.HandleFunc("/operator-exit", operatorExitHandler(exit))
+}
+
+// connectionGaterRules describes data structure of connection gater rules.
+type connectionGaterRules struct {
+	AllowedPeers []string `json:"allowed_peers"`
+	DeniedPeers  []string `json:"denied_peers"`
+	AllowedIPs   []string `json:"allowed_ips"`
+	DeniedIPs    []string `json:"denied_ips"`
+}
+
+// connectionGaterHandler returns the current connection gater rules on GET
+// requests and replaces them with the rules from the request body on PUT
+// requests.
+func connectionGaterHandler(gater net.ConnectionGater) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var rules connectionGaterRules
+
+			decoder := json.NewDecoder(request.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&rules); err != nil {
+				http.Error(
+					response,
+					fmt.Sprintf("could not decode rules: [%v]", err),
+					http.StatusBadRequest,
+				)
+				return
+			}
+
+			err := gater.SetConnectionGaterRules(net.ConnectionGaterRules{
+				AllowedPeers: rules.AllowedPeers,
+				DeniedPeers:  rules.DeniedPeers,
+				AllowedIPs:   rules.AllowedIPs,
+				DeniedIPs:    rules.DeniedIPs,
+			})
+			if err != nil {
+				http.Error(
+					response,
+					fmt.Sprintf("could not set rules: [%v]", err),
+					http.StatusBadRequest,
+				)
+				return
+			}
+		default:
+			methodNotAllowed(response)
+			return
+		}
+
+		rules := gater.ConnectionGaterRules()
+
+		writeJSON(response, connectionGaterRules{
+			AllowedPeers: nonNil(rules.AllowedPeers),
+			DeniedPeers:  nonNil(rules.DeniedPeers),
+			AllowedIPs:   nonNil(rules.AllowedIPs),
+			DeniedIPs:    nonNil(rules.DeniedIPs),
+		})
+	}
+}
+
+// ParameterPool is a pool of generated parameters with the target size
+// adjustable at runtime.
+type ParameterPool interface {
+	// ParametersCount returns the number of parameters in the pool.
+	ParametersCount() int
+	// TargetSize returns the number of parameters the pool is filled up to.
+	TargetSize() int
+	// SetTargetSize changes the number of parameters the pool is filled
+	// up to.
+	SetTargetSize(targetSize int) error
+}
+
+// parameterPoolState describes data structure of the parameter pool state.
+type parameterPoolState struct {
+	Count      int `json:"count"`
+	TargetSize int `json:"target_size"`
+}
+
+// parameterPoolTargetSize describes data structure of the parameter pool
+// target size update.
+type parameterPoolTargetSize struct {
+	TargetSize int `json:"target_size"`
+}
+
+// parameterPoolHandler returns the current state of the parameter pool on
+// GET requests and changes the pool target size to the one from the request
+// body on PUT requests.
+func parameterPoolHandler(pool ParameterPool) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var update parameterPoolTargetSize
+
+			decoder := json.NewDecoder(request.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&update); err != nil {
+				http.Error(
+					response,
+					fmt.Sprintf("could not decode target size: [%v]", err),
+					http.StatusBadRequest,
+				)
+				return
+			}
+
+			if err := pool.SetTargetSize(update.TargetSize); err != nil {
+				http.Error(
+					response,
+					fmt.Sprintf("could not set target size: [%v]", err),
+					http.StatusBadRequest,
+				)
+				return
+			}
+		default:
+			methodNotAllowed(response)
+			return
+		}
+
+		writeJSON(response, parameterPoolState{
+			Count:      pool.ParametersCount(),
+			TargetSize: pool.TargetSize(),
+		})
+	}
+}
+
+// OperatorExit coordinates the operator exit from sortition pools.
+type OperatorExit interface {
+	// RequestExit starts the operator exit. Requesting the exit again has
+	// no effect.
+	RequestExit()
+	// ExitStatus returns the progress of the operator exit.
+	ExitStatus() *sortition.ExitStatus
+}
+
+// OperatorExitStatus describes data structure of the operator exit status.
+type OperatorExitStatus struct {
+	Requested    bool                    `json:"requested"`
+	RequestedAt  string                  `json:"requested_at,omitempty"`
+	Completed    bool                    `json:"completed"`
+	Applications []ApplicationExitStatus `json:"applications"`
+}
+
+// ApplicationExitStatus describes data structure of the operator exit status
+// in the sortition pool of a single application.
+type ApplicationExitStatus struct {
+	Application                  string `json:"application"`
+	Step                         string `json:"step"`
+	ExecutingProtocols           bool   `json:"executing_protocols"`
+	InPool                       bool   `json:"in_pool"`
+	PendingAuthorizationDecrease string `json:"pending_authorization_decrease,omitempty"`
+	RemainingDecreaseDelay       string `json:"remaining_decrease_delay,omitempty"`
+	Error                        string `json:"error,omitempty"`
+}
+
+// operatorExitRequest describes data structure of the operator exit request.
+type operatorExitRequest struct {
+	Requested bool `json:"requested"`
+}
+
+// operatorExitHandler returns the operator exit status on GET requests and
+// requests the operator exit on PUT requests. Once requested, the exit
+// cannot be cancelled.
+func operatorExitHandler(exit OperatorExit) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var exitRequest operatorExitRequest
+
+			decoder := json.NewDecoder(request.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&exitRequest); err != nil {
+				http.Error(
+					response,
+					fmt.Sprintf("could not decode exit request: [%v]", err),
+					http.StatusBadRequest,
+				)
+				return
+			}
+
+			if !exitRequest.Requested {
+				http.Error(
+					response,
+					"operator exit cannot be cancelled",
+					http.StatusBadRequest,
+				)
+				return
+			}
+
+			exit.RequestExit()
+		default:
+			methodNotAllowed(response)
+			return
+		}
+
+		writeJSON(response, toOperatorExitStatus(exit.ExitStatus()))
+	}
+}
+
+func toOperatorExitStatus(status *sortition.ExitStatus) OperatorExitStatus {
+	exitStatus := OperatorExitStatus{
+		Requested:    status.IsRequested,
+		Completed:    status.IsCompleted,
+		Applications: make([]ApplicationExitStatus, len(status.Applications)),
+	}
+
+	if status.IsRequested {
+		exitStatus.RequestedAt = status.RequestedAt.Format(time.RFC3339)
+	}
+
+	for i, application := range status.Applications {
+		applicationStatus := ApplicationExitStatus{
+			Application:        application.Application,
+			Step:               string(application.Step),
+			ExecutingProtocols: application.IsExecutingProtocols,
+			InPool:             application.IsInPool,
+		}
+
+		if application.PendingAuthorizationDecrease != nil {
+			applicationStatus.PendingAuthorizationDecrease =
+				application.PendingAuthorizationDecrease.String()
+		}
+		if application.RemainingDecreaseDelay > 0 {
+			applicationStatus.RemainingDecreaseDelay =
+				application.RemainingDecreaseDelay.String()
+		}
+		if application.Err != nil {
+			applicationStatus.Error = application.Err.Error()
+		}
+
+		exitStatus.Applications[i] = applicationStatus
+	}
+
+	return exitStatus
+}
+
+// methodNotAllowed responds to requests with methods other than GET and PUT.
+func methodNotAllowed(response http.ResponseWriter) {
+	response.Header().Set(
+		"Allow",
+		fmt.Sprintf("%s, %s", http.MethodGet, http.MethodPut),
+	)
+	http.Error(
+		response,
+		"method not allowed",
+		http.StatusMethodNotAllowed,
+	)
+}
+
+func writeJSON(response http.ResponseWriter, value interface{}) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		logger.Errorf("error on serializing response to JSON: [%v]", err)
+		http.Error(
+			response,
+			"could not serialize response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	if _, err := response.Write(bytes); err != nil {
+		logger.Errorf("could not write response: [%v]", err)
+	}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
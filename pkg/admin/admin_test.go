@@ -0,0 +1,386 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/local"
+	"github.com/keep-network/keep-core/pkg/sortition"
+)
+
+func TestConnectionGaterHandler(t *testing.T) {
+	var tests = map[string]struct {
+		method           string
+		body             string
+		setRulesError    error
+		expectedStatus   int
+		expectedResponse string
+		expectedRules    net.ConnectionGaterRules
+	}{
+		"get rules": {
+			method:           http.MethodGet,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"allowed_peers":[],"denied_peers":["peer-1"],"allowed_ips":[],"denied_ips":[]}`,
+			expectedRules: net.ConnectionGaterRules{
+				DeniedPeers: []string{"peer-1"},
+			},
+		},
+		"replace rules": {
+			method:           http.MethodPut,
+			body:             `{"denied_ips":["10.0.0.0/8"]}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"allowed_peers":[],"denied_peers":[],"allowed_ips":[],"denied_ips":["10.0.0.0/8"]}`,
+			expectedRules: net.ConnectionGaterRules{
+				DeniedIPs: []string{"10.0.0.0/8"},
+			},
+		},
+		"malformed body": {
+			method:           http.MethodPut,
+			body:             `{"denied":["10.0.0.0/8"]}`,
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: "could not decode rules: [json: unknown field \"denied\"]\n",
+			expectedRules: net.ConnectionGaterRules{
+				DeniedPeers: []string{"peer-1"},
+			},
+		},
+		"invalid rules": {
+			method:           http.MethodPut,
+			body:             `{"denied_ips":["invalid"]}`,
+			setRulesError:    fmt.Errorf("invalid denied IPs"),
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: "could not set rules: [invalid denied IPs]\n",
+			expectedRules: net.ConnectionGaterRules{
+				DeniedPeers: []string{"peer-1"},
+			},
+		},
+		"unsupported method": {
+			method:           http.MethodPost,
+			expectedStatus:   http.StatusMethodNotAllowed,
+			expectedResponse: "method not allowed\n",
+			expectedRules: net.ConnectionGaterRules{
+				DeniedPeers: []string{"peer-1"},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			gater := &mockConnectionGater{
+				rules: net.ConnectionGaterRules{
+					DeniedPeers: []string{"peer-1"},
+				},
+				setRulesError: test.setRulesError,
+			}
+
+			request := httptest.NewRequest(
+				test.method,
+				"/connection-gater",
+				strings.NewReader(test.body),
+			)
+			recorder := httptest.NewRecorder()
+
+			connectionGaterHandler(gater)(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf(
+					"unexpected status\nexpected: [%v]\nactual:   [%v]",
+					test.expectedStatus,
+					recorder.Code,
+				)
+			}
+
+			response, err := io.ReadAll(recorder.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(response) != test.expectedResponse {
+				t.Errorf(
+					"unexpected response\nexpected: [%v]\nactual:   [%v]",
+					test.expectedResponse,
+					string(response),
+				)
+			}
+
+			if !reflect.DeepEqual(test.expectedRules, gater.rules) {
+				t.Errorf(
+					"unexpected rules\nexpected: [%+v]\nactual:   [%+v]",
+					test.expectedRules,
+					gater.rules,
+				)
+			}
+		})
+	}
+}
+
+func TestNewHandler_ConnectionGatingNotSupported(t *testing.T) {
+	handler := newHandler(local.Connect())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(
+		recorder,
+		httptest.NewRequest(http.MethodGet, "/connection-gater", nil),
+	)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf(
+			"unexpected status\nexpected: [%v]\nactual:   [%v]",
+			http.StatusNotFound,
+			recorder.Code,
+		)
+	}
+}
+
+func TestParameterPoolHandler(t *testing.T) {
+	var tests = map[string]struct {
+		method             string
+		body               string
+		expectedStatus     int
+		expectedResponse   string
+		expectedTargetSize int
+	}{
+		"get state": {
+			method:             http.MethodGet,
+			expectedStatus:     http.StatusOK,
+			expectedResponse:   `{"count":3,"target_size":10}`,
+			expectedTargetSize: 10,
+		},
+		"change target size": {
+			method:             http.MethodPut,
+			body:               `{"target_size":20}`,
+			expectedStatus:     http.StatusOK,
+			expectedResponse:   `{"count":3,"target_size":20}`,
+			expectedTargetSize: 20,
+		},
+		"malformed body": {
+			method:             http.MethodPut,
+			body:               `{"size":20}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedResponse:   "could not decode target size: [json: unknown field \"size\"]\n",
+			expectedTargetSize: 10,
+		},
+		"invalid target size": {
+			method:             http.MethodPut,
+			body:               `{"target_size":0}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedResponse:   "could not set target size: [invalid target size]\n",
+			expectedTargetSize: 10,
+		},
+		"unsupported method": {
+			method:             http.MethodDelete,
+			expectedStatus:     http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+			expectedTargetSize: 10,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			pool := &mockParameterPool{count: 3, targetSize: 10}
+
+			server := &Server{mux: newHandler(local.Connect())}
+			server.RegisterParameterPool("test-pool", pool)
+
+			request := httptest.NewRequest(
+				test.method,
+				"/parameter-pools/test-pool",
+				strings.NewReader(test.body),
+			)
+			recorder := httptest.NewRecorder()
+
+			server.mux.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf(
+					"unexpected status\nexpected: [%v]\nactual:   [%v]",
+					test.expectedStatus,
+					recorder.Code,
+				)
+			}
+
+			response, err := io.ReadAll(recorder.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(response) != test.expectedResponse {
+				t.Errorf(
+					"unexpected response\nexpected: [%v]\nactual:   [%v]",
+					test.expectedResponse,
+					string(response),
+				)
+			}
+
+			if pool.targetSize != test.expectedTargetSize {
+				t.Errorf(
+					"unexpected target size\nexpected: [%v]\nactual:   [%v]",
+					test.expectedTargetSize,
+					pool.targetSize,
+				)
+			}
+		})
+	}
+}
+
+func TestOperatorExitHandler(t *testing.T) {
+	var tests = map[string]struct {
+		method            string
+		body              string
+		expectedStatus    int
+		expectedResponse  string
+		expectedRequested bool
+	}{
+		"get status": {
+			method:           http.MethodGet,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"requested":false,"completed":false,"applications":[{"application":"tbtc","step":"","executing_protocols":false,"in_pool":true}]}`,
+		},
+		"request exit": {
+			method:            http.MethodPut,
+			body:              `{"requested":true}`,
+			expectedStatus:    http.StatusOK,
+			expectedResponse:  `{"requested":true,"requested_at":"2024-06-01T03:00:00Z","completed":false,"applications":[{"application":"tbtc","step":"wait for authorization decrease delay","executing_protocols":false,"in_pool":true,"pending_authorization_decrease":"100","remaining_decrease_delay":"1h0m0s"}]}`,
+			expectedRequested: true,
+		},
+		"cancel exit": {
+			method:           http.MethodPut,
+			body:             `{"requested":false}`,
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: "operator exit cannot be cancelled\n",
+		},
+		"malformed body": {
+			method:           http.MethodPut,
+			body:             `{"exit":true}`,
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: "could not decode exit request: [json: unknown field \"exit\"]\n",
+		},
+		"unsupported method": {
+			method:           http.MethodPost,
+			expectedStatus:   http.StatusMethodNotAllowed,
+			expectedResponse: "method not allowed\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			exit := &mockOperatorExit{}
+
+			server := &Server{mux: newHandler(local.Connect())}
+			server.RegisterOperatorExit(exit)
+
+			request := httptest.NewRequest(
+				test.method,
+				"/operator-exit",
+				strings.NewReader(test.body),
+			)
+			recorder := httptest.NewRecorder()
+
+			server.mux.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf(
+					"unexpected status\nexpected: [%v]\nactual:   [%v]",
+					test.expectedStatus,
+					recorder.Code,
+				)
+			}
+
+			response, err := io.ReadAll(recorder.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(response) != test.expectedResponse {
+				t.Errorf(
+					"unexpected response\nexpected: [%v]\nactual:   [%v]",
+					test.expectedResponse,
+					string(response),
+				)
+			}
+
+			if exit.requested != test.expectedRequested {
+				t.Errorf(
+					"unexpected exit request\nexpected: [%v]\nactual:   [%v]",
+					test.expectedRequested,
+					exit.requested,
+				)
+			}
+		})
+	}
+}
+
+type mockConnectionGater struct {
+	rules         net.ConnectionGaterRules
+	setRulesError error
+}
+
+func (mcg *mockConnectionGater) ConnectionGaterRules() net.ConnectionGaterRules {
+	return mcg.rules
+}
+
+func (mcg *mockConnectionGater) SetConnectionGaterRules(
+	rules net.ConnectionGaterRules,
+) error {
+	if mcg.setRulesError != nil {
+		return mcg.setRulesError
+	}
+
+	mcg.rules = rules
+	return nil
+}
+
+type mockParameterPool struct {
+	count      int
+	targetSize int
+}
+
+func (mpp *mockParameterPool) ParametersCount() int {
+	return mpp.count
+}
+
+func (mpp *mockParameterPool) TargetSize() int {
+	return mpp.targetSize
+}
+
+func (mpp *mockParameterPool) SetTargetSize(targetSize int) error {
+	if targetSize <= 0 {
+		return fmt.Errorf("invalid target size")
+	}
+
+	mpp.targetSize = targetSize
+	return nil
+}
+
+type mockOperatorExit struct {
+	requested bool
+}
+
+func (moe *mockOperatorExit) RequestExit() {
+	moe.requested = true
+}
+
+func (moe *mockOperatorExit) ExitStatus() *sortition.ExitStatus {
+	status := &sortition.ExitStatus{
+		IsRequested: moe.requested,
+		Applications: []*sortition.ApplicationExitStatus{
+			{Application: "tbtc", IsInPool: true},
+		},
+	}
+
+	if moe.requested {
+		status.RequestedAt = time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC)
+		status.Applications[0].Step = sortition.ExitStepWaitForDecreaseDelay
+		status.Applications[0].PendingAuthorizationDecrease = big.NewInt(100)
+		status.Applications[0].RemainingDecreaseDelay = time.Hour
+	}
+
+	return status
+}
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const clientRequestTimeout = 10 * time.Second
+
+// Client is a client of the admin API of the node running on the same
+// machine.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client of the admin API listening on the given port of
+// the loopback interface.
+func NewClient(port int) *Client {
+	return &Client{
+		baseURL:    fmt.Sprintf("http://127.0.0.1:%d", port),
+		httpClient: &http.Client{Timeout: clientRequestTimeout},
+	}
+}
+
+// RequestOperatorExit requests the operator exit and returns its status.
+func (c *Client) RequestOperatorExit() (*OperatorExitStatus, error) {
+	body, err := json.Marshal(operatorExitRequest{Requested: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not encode exit request: [%w]", err)
+	}
+
+	status := &OperatorExitStatus{}
+	if err := c.do(http.MethodPut, "/operator-exit", body, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// OperatorExitStatus returns the operator exit status.
+func (c *Client) OperatorExitStatus() (*OperatorExitStatus, error) {
+	status := &OperatorExitStatus{}
+	if err := c.do(http.MethodGet, "/operator-exit", nil, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// do executes the request with the given method and body and decodes the
+// JSON response into the result.
+func (c *Client) do(
+	method string,
+	path string,
+	body []byte,
+	result interface{},
+) error {
+	request, err := http.NewRequest(
+		method,
+		c.baseURL+path,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create request: [%w]", err)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("admin API request failed: [%w]", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: [%w]", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"unexpected admin API response status [%s]: [%s]",
+			response.Status,
+			strings.TrimSpace(string(responseBody)),
+		)
+	}
+
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		return fmt.Errorf("could not decode response: [%w]", err)
+	}
+
+	return nil
+}
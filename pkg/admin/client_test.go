@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/net/local"
+)
+
+func TestClient_OperatorExit(t *testing.T) {
+	exit := &mockOperatorExit{}
+
+	server := &Server{mux: newHandler(local.Connect())}
+	server.RegisterOperatorExit(exit)
+
+	httpServer := httptest.NewServer(server.mux)
+	defer httpServer.Close()
+
+	client := NewClient(0)
+	client.baseURL = httpServer.URL
+
+	status, err := client.OperatorExitStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Requested {
+		t.Error("expected the exit not to be requested")
+	}
+
+	status, err = client.RequestOperatorExit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Requested || !exit.requested {
+		t.Error("expected the exit to be requested")
+	}
+	if step := status.Applications[0].Step; step != "wait for authorization decrease delay" {
+		t.Errorf("unexpected step: [%v]", step)
+	}
+}
+
+func TestClient_UnexpectedStatus(t *testing.T) {
+	// The operator exit is not registered by the server.
+	server := &Server{mux: newHandler(local.Connect())}
+
+	httpServer := httptest.NewServer(server.mux)
+	defer httpServer.Close()
+
+	client := NewClient(0)
+	client.baseURL = httpServer.URL
+
+	_, err := client.OperatorExitStatus()
+
+	expectedError := "unexpected admin API response status [404 Not Found]: [404 page not found]"
+	if err == nil || !strings.Contains(err.Error(), expectedError) {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	beaconchain "github.com/keep-network/keep-core/pkg/beacon/chain"
 	"github.com/keep-network/keep-core/pkg/beacon/event"
 	"github.com/keep-network/keep-core/pkg/beacon/registry"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/net"
 )
 
@@ -33,6 +34,8 @@ func Initialize(
 	netProvider net.Provider,
 	persistence persistence.ProtectedHandle,
 	scheduler *generator.Scheduler,
+	sortitionPoolsMonitor *sortition.PoolsMonitor,
+	clientInfo *clientinfo.Registry,
 ) error {
 	groupRegistry := registry.NewGroupRegistry(logger, beaconChain, persistence)
 	groupRegistry.LoadExistingGroups()
@@ -44,11 +47,10 @@ func Initialize(
 		scheduler,
 	)
 
-	err := sortition.MonitorPool(
-		ctx,
+	_, err := sortitionPoolsMonitor.Register(
+		"beacon",
 		logger,
 		beaconChain,
-		sortition.DefaultStatusCheckTick,
 		sortition.NewBetaOperatorPolicy(beaconChain, logger),
 	)
 	if err != nil {
@@ -58,12 +60,39 @@ func Initialize(
 		)
 	}
 
+	err = sortitionPoolsMonitor.TrackProtocols("beacon", node.protocolLatch)
+	if err != nil {
+		return fmt.Errorf("could not track beacon protocols: [%v]", err)
+	}
+
+	blockCounter, err := beaconChain.BlockCounter()
+	if err != nil {
+		return fmt.Errorf("could not get block counter: [%v]", err)
+	}
+
+	relayRequests := newRelayRequestTracker(
+		blockCounter,
+		beaconChain.GetConfig().RelayEntryTimeout,
+		node.IsInGroup,
+	)
+
+	if clientInfo != nil {
+		clientInfo.RegisterApplicationSource(
+			"beacon_relay_requests",
+			relayRequests.info,
+		)
+	}
+
 	eventDeduplicator := event.NewDeduplicator(beaconChain)
 
 	node.ResumeSigningIfEligible()
 
+	go node.monitorGroupLifecycle(ctx, groupLifecycleCheckTick)
+
 	_ = beaconChain.OnRelayEntryRequested(func(request *event.RelayEntryRequested) {
 		onConfirmed := func() {
+			relayRequests.add(request)
+
 			if node.IsInGroup(request.GroupPublicKey) {
 				go func() {
 					shouldProcess, err := eventDeduplicator.NotifyRelayEntryStarted(
@@ -128,6 +157,10 @@ func Initialize(
 		)
 	})
 
+	_ = beaconChain.OnRelayEntrySubmitted(func(entry *event.RelayEntrySubmitted) {
+		relayRequests.onEntrySubmitted(entry.BlockNumber)
+	})
+
 	_ = beaconChain.OnDKGStarted(func(event *event.DKGStarted) {
 		go func() {
 			if ok := eventDeduplicator.NotifyDKGStarted(
@@ -142,6 +175,16 @@ func Initialize(
 				return
 			}
 
+			if sortitionPoolsMonitor.IsExitRequested() {
+				logger.Warnf(
+					"operator exit requested; not joining DKG with "+
+						"seed [0x%x] started at block [%v]",
+					event.Seed,
+					event.BlockNumber,
+				)
+				return
+			}
+
 			logger.Infof(
 				"DKG started with seed [0x%x] at block [%v]",
 				event.Seed,
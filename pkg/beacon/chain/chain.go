@@ -70,8 +70,8 @@ type GroupRegistrationInterface interface {
 	// IsStaleGroup checks if a group with the given public key is considered
 	// as stale on-chain. Group is considered as stale if it is expired and when
 	// its expiration time and potentially executed operation timeout are both
-	// in the past. Stale group is never selected by the chain to any new
-	// operation.
+	// in the past, or if it was terminated. Stale group is never selected by
+	// the chain to any new operation.
 	IsStaleGroup(groupPublicKey []byte) (bool, error)
 }
 
@@ -134,6 +134,11 @@ type Interface interface {
 type Config struct {
 	// GroupSize is the size of a group in the random beacon.
 	GroupSize int
+	// GroupQuorum is the minimum number of active participants behaving
+	// according to the protocol needed to generate a group in the random
+	// beacon. This value is smaller than the GroupSize and bigger than the
+	// HonestThreshold.
+	GroupQuorum int
 	// HonestThreshold is the minimum number of active participants behaving
 	// according to the protocol needed to generate a new relay entry.
 	HonestThreshold int
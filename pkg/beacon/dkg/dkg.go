@@ -231,15 +231,15 @@ func waitForDkgResultEvent(
 //
 // Example:
 // selectedOperators: [member1, member2, member3, member4, member5]
-// operatingGroupMembersIDs: [5, 1, 3]
-// groupOperators: [member1, member3, member5]
+// operatingGroupMembersIDs: [5, 1, 3, 4]
+// groupOperators: [member1, member3, member4, member5]
 func resolveGroupOperators(
 	selectedOperators []chain.Address,
 	operatingGroupMembersIDs []group.MemberIndex,
 	beaconConfig *beaconchain.Config,
 ) ([]chain.Address, error) {
 	if len(selectedOperators) != beaconConfig.GroupSize ||
-		len(operatingGroupMembersIDs) < beaconConfig.HonestThreshold {
+		len(operatingGroupMembersIDs) < beaconConfig.GroupQuorum {
 		return nil, fmt.Errorf("invalid input parameters")
 	}
 
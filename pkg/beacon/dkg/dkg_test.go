@@ -163,6 +163,7 @@ func TestDecideMemberFate_Timeout(t *testing.T) {
 func TestResolveGroupOperators(t *testing.T) {
 	beaconConfig := &beaconchain.Config{
 		GroupSize:       5,
+		GroupQuorum:     4,
 		HonestThreshold: 3,
 	}
 
@@ -190,10 +191,15 @@ func TestResolveGroupOperators(t *testing.T) {
 			operatingGroupMembersIDs: []group.MemberIndex{5, 4, 3, 2, 1},
 			expectedGroupOperators:   selectedOperators,
 		},
-		"honest majority of selected operators are operating": {
+		"group quorum of selected operators are operating": {
+			selectedOperators:        selectedOperators,
+			operatingGroupMembersIDs: []group.MemberIndex{5, 1, 3, 4},
+			expectedGroupOperators:   []chain.Address{"0xAA", "0xCC", "0xDD", "0xEE"},
+		},
+		"honest majority but less than group quorum of selected operators are operating": {
 			selectedOperators:        selectedOperators,
 			operatingGroupMembersIDs: []group.MemberIndex{5, 1, 3},
-			expectedGroupOperators:   []chain.Address{"0xAA", "0xCC", "0xEE"},
+			expectedError:            fmt.Errorf("invalid input parameters"),
 		},
 		"less than honest majority of selected operators are operating": {
 			selectedOperators:        selectedOperators,
@@ -60,15 +60,14 @@ func (sm *SubmittingMember) SubmitDKGResult(
 ) error {
 	config := chainRelay.GetConfig()
 
-	// Chain rejects the result if it has less than 25% safety margin.
-	// If there are not enough signatures to preserve the margin, it does not
-	// make sense to submit the result.
-	signatureThreshold := config.HonestThreshold + (config.GroupSize-config.HonestThreshold)/2
-	if len(signatures) < signatureThreshold {
+	// Chain rejects the result if it is not supported by the group quorum.
+	// If there are not enough signatures, it does not make sense to submit
+	// the result.
+	if len(signatures) < config.GroupQuorum {
 		return fmt.Errorf(
-			"could not submit result with [%v] signatures for signature threshold [%v]",
+			"could not submit result with [%v] signatures for group quorum [%v]",
 			len(signatures),
-			signatureThreshold,
+			config.GroupQuorum,
 		)
 	}
 
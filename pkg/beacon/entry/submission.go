@@ -10,6 +10,15 @@ import (
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 )
 
+const (
+	// maxRelayEntrySubmissionRetries is the number of times a failed relay
+	// entry submission is retried while the entry is still in progress.
+	maxRelayEntrySubmissionRetries = 3
+	// relayEntrySubmissionRetryBlocks is the number of blocks after which
+	// a failed relay entry submission is retried.
+	relayEntrySubmissionRetryBlocks = 1
+)
+
 type relayEntrySubmitter struct {
 	logger       log.StandardLogger
 	chain        beaconchain.Interface
@@ -23,7 +32,8 @@ type relayEntrySubmitter struct {
 // Group member with index 1 tries to submit as the first one, group member 2
 // tries to submit after a few blocks if member 1 did not submit and so on.
 // Relay entry submit process starts at block height defined by startBlockheight
-// parameter.
+// parameter. A failed submission is retried a few times, one block apart, as
+// long as the entry is still in progress.
 func (res *relayEntrySubmitter) submitRelayEntry(
 	newEntry []byte,
 	groupPublicKey []byte,
@@ -47,6 +57,8 @@ func (res *relayEntrySubmitter) submitRelayEntry(
 		return fmt.Errorf("wait for eligibility failure: [%v]", err)
 	}
 
+	retries := 0
+
 	for {
 		select {
 		case blockNumber := <-eligibleToSubmitWaiter:
@@ -84,12 +96,34 @@ func (res *relayEntrySubmitter) submitRelayEntry(
 					return nil
 				}
 
-				res.logger.Errorf(
-					"[member:%v] could not submit relay entry: [%v]",
+				if retries >= maxRelayEntrySubmissionRetries {
+					res.logger.Errorf(
+						"[member:%v] could not submit relay entry: [%v]",
+						res.index,
+						err,
+					)
+					return err
+				}
+
+				retries++
+				retryBlock := blockNumber + relayEntrySubmissionRetryBlocks
+
+				res.logger.Warnf(
+					"[member:%v] could not submit relay entry; "+
+						"retrying at block [%v]: [%v]",
 					res.index,
+					retryBlock,
 					err,
 				)
-				return err
+
+				eligibleToSubmitWaiter, err = res.blockCounter.BlockHeightWaiter(
+					retryBlock,
+				)
+				if err != nil {
+					return fmt.Errorf("block height waiter failure: [%v]", err)
+				}
+
+				continue
 			}
 
 			res.logger.Infof(
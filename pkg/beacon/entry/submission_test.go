@@ -1,6 +1,16 @@
 package entry
 
-import "testing"
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+
+	beaconchain "github.com/keep-network/keep-core/pkg/beacon/chain"
+	"github.com/keep-network/keep-core/pkg/chain/local_v1"
+)
 
 func TestCalculateSubmissionQueueIndex(t *testing.T) {
 	groupSize := uint64(64)
@@ -45,3 +55,111 @@ func TestCalculateSubmissionQueueIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestSubmitRelayEntry_Retries(t *testing.T) {
+	var tests = map[string]struct {
+		failures            int
+		expectedSubmissions int
+		expectedError       error
+	}{
+		"submitted at first attempt": {
+			failures:            0,
+			expectedSubmissions: 1,
+		},
+		"submitted after retries": {
+			failures:            maxRelayEntrySubmissionRetries,
+			expectedSubmissions: maxRelayEntrySubmissionRetries + 1,
+		},
+		"retries exhausted": {
+			failures:            maxRelayEntrySubmissionRetries + 1,
+			expectedSubmissions: maxRelayEntrySubmissionRetries + 1,
+			expectedError:       fmt.Errorf("execution reverted"),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			blockCounter, err := local_v1.BlockCounter(10 * time.Millisecond)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			relayEntrySubmittedChannel := make(chan uint64, 1)
+
+			chain := &failingSubmissionChain{
+				failures:  test.failures,
+				submitted: relayEntrySubmittedChannel,
+			}
+
+			submitter := &relayEntrySubmitter{
+				logger:       log.Logger("test"),
+				chain:        chain,
+				blockCounter: blockCounter,
+				index:        1,
+			}
+
+			startBlock, err := blockCounter.CurrentBlock()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = submitter.submitRelayEntry(
+				[]byte{0x01},
+				[]byte{0x02},
+				startBlock,
+				relayEntrySubmittedChannel,
+				make(chan uint64),
+			)
+			if fmt.Sprintf("%v", test.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+
+			if chain.submissions != test.expectedSubmissions {
+				t.Errorf(
+					"unexpected number of submissions\nexpected: %v\nactual:   %v\n",
+					test.expectedSubmissions,
+					chain.submissions,
+				)
+			}
+		})
+	}
+}
+
+// failingSubmissionChain is a chain failing the given number of relay entry
+// submissions before the entry gets submitted.
+type failingSubmissionChain struct {
+	beaconchain.Interface
+
+	mutex       sync.Mutex
+	failures    int
+	submissions int
+	submitted   chan uint64
+}
+
+func (fsc *failingSubmissionChain) GetConfig() *beaconchain.Config {
+	return &beaconchain.Config{
+		GroupSize:                  1,
+		ResultPublicationBlockStep: 1,
+	}
+}
+
+func (fsc *failingSubmissionChain) SubmitRelayEntry(entry []byte) error {
+	fsc.mutex.Lock()
+	defer fsc.mutex.Unlock()
+
+	fsc.submissions++
+	if fsc.submissions <= fsc.failures {
+		return fmt.Errorf("execution reverted")
+	}
+
+	fsc.submitted <- uint64(fsc.submissions)
+	return nil
+}
+
+func (fsc *failingSubmissionChain) IsEntryInProgress() (bool, error) {
+	return true, nil
+}
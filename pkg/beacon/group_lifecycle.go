@@ -0,0 +1,33 @@
+package beacon
+
+import (
+	"context"
+	"time"
+)
+
+// groupLifecycleCheckTick determines how often the node checks the on-chain
+// state of its groups to archive the ones that expired or were terminated.
+// Group lifetime is counted in weeks so there is no need to check them more
+// often.
+const groupLifecycleCheckTick = time.Hour
+
+// monitorGroupLifecycle periodically archives groups that became stale
+// on-chain, i.e. expired or were terminated. Such groups are never selected
+// for a relay entry again so the node stops participating in relay entries
+// for them and their key material is moved to the archive of the key store.
+// The first check is made immediately. The function blocks until the given
+// context is done.
+func (n *node) monitorGroupLifecycle(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		n.groupRegistry.UnregisterStaleGroups(nil)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"math/rand"
 
 	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
 	"go.uber.org/zap"
@@ -14,6 +15,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/beacon/entry"
 	"github.com/keep-network/keep-core/pkg/beacon/event"
 	"github.com/keep-network/keep-core/pkg/beacon/registry"
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
@@ -257,12 +259,32 @@ func (n *node) ResumeSigningIfEligible() {
 	}
 }
 
+// maxRelayEntryTimeoutReportDelayBlocks is the maximum number of blocks the
+// node waits after the relay entry timeout before reporting it. Nodes
+// monitoring the same relay entry wait for a random number of blocks so that
+// they do not race for the report transaction and waste gas on transactions
+// that revert.
+const maxRelayEntryTimeoutReportDelayBlocks = 10
+
 // MonitorRelayEntry is listetning to the chain for a new relay entry.
 // When a processing group which is supposed to deliver a relay entry does not
 // fulfill its work, then this node notifies the chain about it. In the case of
 // delivering a relay entry by a processing group, this node does nothing.
+// The timeout is reported after a random delay of up to
+// maxRelayEntryTimeoutReportDelayBlocks blocks and only if it has not been
+// reported by another node in the meantime.
 func (n *node) MonitorRelayEntry(
 	relayRequestBlockNumber uint64,
+) {
+	n.monitorRelayEntry(
+		relayRequestBlockNumber,
+		uint64(rand.Int63n(maxRelayEntryTimeoutReportDelayBlocks+1)),
+	)
+}
+
+func (n *node) monitorRelayEntry(
+	relayRequestBlockNumber uint64,
+	reportDelayBlocks uint64,
 ) {
 	logger.Infof("monitoring chain for a new relay entry")
 
@@ -296,13 +318,16 @@ func (n *node) MonitorRelayEntry(
 			subscription.Unsubscribe()
 			close(onEntrySubmittedChannel)
 			logger.Warnf(
-				"relay entry was not submitted on time, reporting timeout at block [%v]",
+				"relay entry was not submitted on time; timeout block [%v]; "+
+					"reporting timeout after [%v] blocks",
 				blockNumber,
+				reportDelayBlocks,
+			)
+			n.reportRelayEntryTimeout(
+				blockCounter,
+				relayRequestBlockNumber,
+				blockNumber+reportDelayBlocks,
 			)
-			err = n.beaconChain.ReportRelayEntryTimeout()
-			if err != nil {
-				logger.Errorf("could not report a relay entry timeout: [%v]", err)
-			}
 			return
 		case entry := <-onEntrySubmittedChannel:
 			logger.Infof(
@@ -314,6 +339,88 @@ func (n *node) MonitorRelayEntry(
 	}
 }
 
+// reportRelayEntryTimeout waits for the report block and reports the timeout
+// of the relay entry requested at the given block if the timeout is still
+// eligible for reporting, i.e. the relay request is still in progress and no
+// other node reported the timeout so far.
+func (n *node) reportRelayEntryTimeout(
+	blockCounter chain.BlockCounter,
+	relayRequestBlockNumber uint64,
+	reportBlock uint64,
+) {
+	err := blockCounter.WaitForBlockHeight(reportBlock)
+	if err != nil {
+		logger.Errorf(
+			"failed to wait for relay entry timeout report block [%v]: [%v]",
+			reportBlock,
+			err,
+		)
+		return
+	}
+
+	isEligible, err := n.isRelayEntryTimeoutEligible(relayRequestBlockNumber)
+	if err != nil {
+		logger.Errorf(
+			"could not check if relay entry timeout is eligible for "+
+				"reporting: [%v]",
+			err,
+		)
+		return
+	}
+
+	if !isEligible {
+		logger.Infof(
+			"relay entry timeout for request from block [%v] is no longer "+
+				"eligible for reporting; the request is no longer in "+
+				"progress as its entry was submitted or its timeout "+
+				"was already reported",
+			relayRequestBlockNumber,
+		)
+		return
+	}
+
+	logger.Infof(
+		"reporting relay entry timeout for request from block [%v] at block [%v]",
+		relayRequestBlockNumber,
+		reportBlock,
+	)
+
+	err = n.beaconChain.ReportRelayEntryTimeout()
+	if err != nil {
+		logger.Errorf("could not report a relay entry timeout: [%v]", err)
+	}
+}
+
+// isRelayEntryTimeoutEligible checks if the timeout of the relay entry
+// requested at the given block can be reported. Once the timeout is reported,
+// the relay request is no longer in progress or a new relay request is
+// already in progress.
+func (n *node) isRelayEntryTimeoutEligible(
+	relayRequestBlockNumber uint64,
+) (bool, error) {
+	isEntryInProgress, err := n.beaconChain.IsEntryInProgress()
+	if err != nil {
+		return false, fmt.Errorf(
+			"could not check if relay entry is in progress: [%v]",
+			err,
+		)
+	}
+
+	if !isEntryInProgress {
+		return false, nil
+	}
+
+	currentRequestStartBlock, err := n.beaconChain.CurrentRequestStartBlock()
+	if err != nil {
+		return false, fmt.Errorf(
+			"could not get current request start block: [%v]",
+			err,
+		)
+	}
+
+	return currentRequestStartBlock.Uint64() == relayRequestBlockNumber, nil
+}
+
 // GenerateRelayEntry is triggered for a new relay request and checks if this
 // client is one of the group members selected to create a new relay entry.
 // If it is, this client enters the threshold signature creation process and,
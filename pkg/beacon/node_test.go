@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"testing"
 
+	beaconchain "github.com/keep-network/keep-core/pkg/beacon/chain"
 	"github.com/keep-network/keep-core/pkg/chain/local_v1"
 )
 
@@ -63,49 +64,107 @@ func TestMonitorRelayEntryOnChain_EntrySubmitted(t *testing.T) {
 }
 
 func TestMonitorRelayEntryOnChain_EntryNotSubmitted(t *testing.T) {
-	localChain := local_v1.Connect(5, 3)
-
-	node := &node{
-		beaconChain: localChain,
+	var tests = map[string]struct {
+		reportDelayBlocks    uint64
+		isEntryInProgress    bool
+		requestStartBlockGap uint64
+		expectedReports      int
+	}{
+		"timeout reported with no delay": {
+			reportDelayBlocks: 0,
+			isEntryInProgress: true,
+			expectedReports:   1,
+		},
+		"timeout reported with delay": {
+			reportDelayBlocks: 3,
+			isEntryInProgress: true,
+			expectedReports:   1,
+		},
+		"timeout already reported": {
+			reportDelayBlocks: 3,
+			isEntryInProgress: false,
+			expectedReports:   0,
+		},
+		"new relay request in progress": {
+			reportDelayBlocks:    3,
+			isEntryInProgress:    true,
+			requestStartBlockGap: 2,
+			expectedReports:      0,
+		},
 	}
 
-	blockCounter, err := node.beaconChain.BlockCounter()
-	if err != nil {
-		fmt.Printf("failed to setup a block counter: [%v]", err)
+	for testName, test := range tests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			localChain := local_v1.Connect(5, 3)
+
+			blockCounter, err := localChain.BlockCounter()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			startBlockHeight, err := blockCounter.CurrentBlock()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			node := &node{
+				beaconChain: &relayRequestChain{
+					Interface:         localChain,
+					isEntryInProgress: test.isEntryInProgress,
+					requestStartBlock: startBlockHeight + test.requestStartBlockGap,
+				},
+			}
+
+			go node.monitorRelayEntry(startBlockHeight, test.reportDelayBlocks)
+
+			relayEntryTimeoutFromStart := startBlockHeight + relayEntryTimeout
+			reportBlock := relayEntryTimeoutFromStart + test.reportDelayBlocks
+
+			// we want to exceed the report block to check whether a relay
+			// entry timeout was reported. 5 is an arbitrary number to exceed
+			// the report block.
+			err = blockCounter.WaitForBlockHeight(reportBlock + 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			timeoutsReport := localChain.GetRelayEntryTimeoutReports()
+			numberOfReports := len(timeoutsReport)
+
+			if numberOfReports != test.expectedReports {
+				t.Fatalf(
+					"Number of timeout reports does not match\nexpected: [%v]\nactual:   [%v]",
+					test.expectedReports,
+					numberOfReports,
+				)
+			}
+
+			if numberOfReports > 0 && timeoutsReport[0] != reportBlock {
+				t.Fatalf(
+					"Timeout reporting must happen only after a report delay\nexpected: [%v]\nactual:   [%v]",
+					reportBlock,
+					timeoutsReport[0],
+				)
+			}
+		})
 	}
+}
 
-	startBlockHeight, err := blockCounter.CurrentBlock()
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	go node.MonitorRelayEntry(startBlockHeight)
-
-	relayEntryTimeoutFromStart := startBlockHeight + relayEntryTimeout
-
-	// we want to exceed the relay entry timeout to report that a relay entry
-	// was not submitted. 5 is an arbitrary number to exceed relayEntryTimeout.
-	err = blockCounter.WaitForBlockHeight(relayEntryTimeoutFromStart + 5)
-	if err != nil {
-		t.Fatal(err)
-	}
+// relayRequestChain is a local chain with a relay request in progress.
+type relayRequestChain struct {
+	beaconchain.Interface
 
-	timeoutsReport := localChain.GetRelayEntryTimeoutReports()
-	numberOfReports := len(timeoutsReport)
+	isEntryInProgress bool
+	requestStartBlock uint64
+}
 
-	if numberOfReports != 1 {
-		t.Fatalf(
-			"Number of timeout reports does not match\nexpected: [%v]\nactual:   [%v]",
-			1,
-			numberOfReports,
-		)
-	}
+func (rrc *relayRequestChain) IsEntryInProgress() (bool, error) {
+	return rrc.isEntryInProgress, nil
+}
 
-	if timeoutsReport[0] != relayEntryTimeoutFromStart {
-		t.Fatalf(
-			"Timeout reporting must happen only after a relay entry timeout\nexpected: [%v]\nactual:   [%v]",
-			relayEntryTimeoutFromStart,
-			timeoutsReport[0],
-		)
-	}
+func (rrc *relayRequestChain) CurrentRequestStartBlock() (*big.Int, error) {
+	return new(big.Int).SetUint64(rrc.requestStartBlock), nil
 }
@@ -89,7 +89,8 @@ func (g *Groups) GetGroup(groupPublicKey []byte) []*Membership {
 // after the group expiration. This guarantees the group will not be selected to
 // a new operation and it cannot have an ongoing operation for which it could be
 // selected before it expired. Such a group can be safely removed from the registry
-// and archived in the underlying storage.
+// and archived in the underlying storage. The latest group is not checked;
+// nil latest group public key makes all the groups checked.
 func (g *Groups) UnregisterStaleGroups(latestGroupPublicKey []byte) {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
@@ -191,6 +191,39 @@ func TestUnregisterStaleGroupsSkipLastGroupCheck(t *testing.T) {
 	}
 }
 
+func TestUnregisterStaleGroupsCheckAllGroups(t *testing.T) {
+	mockChain := &mockGroupRegistrationInterface{
+		groupsToRemove:       [][]byte{},
+		groupsCheckedIfStale: make(map[string]bool),
+	}
+
+	gr := NewGroupRegistry(&testutils.MockLogger{}, mockChain, persistenceMock)
+
+	gr.RegisterGroup(signer1, channelName1)
+	gr.RegisterGroup(signer2, channelName1)
+
+	mockChain.markAsStale(signer2.GroupPublicKeyBytes())
+
+	gr.UnregisterStaleGroups(nil)
+
+	group1PublicKeyString := groupKeyToString(signer1.GroupPublicKeyBytes())
+	if mockChain.groupsCheckedIfStale[group1PublicKeyString] != true {
+		t.Fatalf("IsStaleGroup() was expected to be called for the first group")
+	}
+
+	group2PublicKeyString := groupKeyToString(signer2.GroupPublicKeyBytes())
+	if mockChain.groupsCheckedIfStale[group2PublicKeyString] != true {
+		t.Fatalf("IsStaleGroup() was expected to be called for the second group")
+	}
+
+	if gr.GetGroup(signer1.GroupPublicKeyBytes()) == nil {
+		t.Fatalf("Expecting a group, but nil was returned instead")
+	}
+	if gr.GetGroup(signer2.GroupPublicKeyBytes()) != nil {
+		t.Fatalf("Group2 was expected to be unregistered, but is still present")
+	}
+}
+
 type mockGroupRegistrationInterface struct {
 	groupsToRemove       [][]byte
 	groupsCheckedIfStale map[string]bool
@@ -0,0 +1,163 @@
+package beacon
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/keep-network/keep-core/pkg/beacon/event"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+// RelayRequestInfo describes a pending relay entry request.
+type RelayRequestInfo struct {
+	// PreviousEntry is the previous relay entry the requested entry is
+	// generated from.
+	PreviousEntry string `json:"previous_entry"`
+	// GroupPublicKey is the public key of the group assigned to the request.
+	GroupPublicKey string `json:"group_public_key"`
+	// IsGroupMember is true if the node is a member of the assigned group,
+	// i.e. the node owes the relay entry to the chain.
+	IsGroupMember bool `json:"is_group_member"`
+	// RequestBlock is the block the entry was requested at.
+	RequestBlock uint64 `json:"request_block"`
+	// TimeoutBlock is the block the request times out at.
+	TimeoutBlock uint64 `json:"timeout_block"`
+	// RemainingBlocks is the number of blocks remaining before the request
+	// times out.
+	RemainingBlocks uint64 `json:"remaining_blocks"`
+}
+
+// relayRequestTracker tracks relay entry requests observed by the node that
+// are pending, i.e. their entries have been neither submitted nor timed out.
+// Requests are tracked in memory only, based on the chain events delivered
+// to the node.
+type relayRequestTracker struct {
+	blockCounter chain.BlockCounter
+	timeout      uint64
+	isInGroup    func(groupPublicKey []byte) bool
+
+	mutex sync.Mutex
+	// requests are ordered by the request block.
+	requests []*event.RelayEntryRequested
+	// lastSubmissionBlock is the block of the latest relay entry submission
+	// processed by the tracker. Events may be delivered more than once so
+	// submissions at or before that block and requests made before that
+	// block were already processed.
+	lastSubmissionBlock uint64
+}
+
+func newRelayRequestTracker(
+	blockCounter chain.BlockCounter,
+	timeout uint64,
+	isInGroup func(groupPublicKey []byte) bool,
+) *relayRequestTracker {
+	return &relayRequestTracker{
+		blockCounter: blockCounter,
+		timeout:      timeout,
+		isInGroup:    isInGroup,
+		requests:     make([]*event.RelayEntryRequested, 0),
+	}
+}
+
+// add starts tracking the relay entry request. Requests already tracked or
+// already fulfilled are ignored as events may be delivered more than once.
+// Requests that timed out before the given request was made are no longer
+// tracked.
+func (rrt *relayRequestTracker) add(request *event.RelayEntryRequested) {
+	rrt.mutex.Lock()
+	defer rrt.mutex.Unlock()
+
+	if request.BlockNumber < rrt.lastSubmissionBlock {
+		return
+	}
+
+	rrt.prune(request.BlockNumber)
+
+	position := len(rrt.requests)
+	for i, tracked := range rrt.requests {
+		if tracked.BlockNumber == request.BlockNumber &&
+			bytes.Equal(tracked.PreviousEntry, request.PreviousEntry) {
+			return
+		}
+
+		if tracked.BlockNumber > request.BlockNumber && i < position {
+			position = i
+		}
+	}
+
+	rrt.requests = append(rrt.requests, nil)
+	copy(rrt.requests[position+1:], rrt.requests[position:])
+	rrt.requests[position] = request
+}
+
+// onEntrySubmitted stops tracking the request fulfilled by the relay entry
+// submitted at the given block. Requests are processed one at a time so
+// the entry fulfils the earliest request made before the block.
+func (rrt *relayRequestTracker) onEntrySubmitted(blockNumber uint64) {
+	rrt.mutex.Lock()
+	defer rrt.mutex.Unlock()
+
+	if blockNumber <= rrt.lastSubmissionBlock {
+		return
+	}
+	rrt.lastSubmissionBlock = blockNumber
+
+	if len(rrt.requests) > 0 && rrt.requests[0].BlockNumber <= blockNumber {
+		rrt.requests = rrt.requests[1:]
+	}
+}
+
+// prune stops tracking requests that timed out at the given block. Must be
+// called with the mutex held.
+func (rrt *relayRequestTracker) prune(currentBlock uint64) {
+	requests := rrt.requests[:0]
+	for _, request := range rrt.requests {
+		if currentBlock < request.BlockNumber+rrt.timeout {
+			requests = append(requests, request)
+		}
+	}
+
+	rrt.requests = requests
+}
+
+// pending returns requests pending at the given block. Requests that timed
+// out are no longer tracked.
+func (rrt *relayRequestTracker) pending(currentBlock uint64) []*RelayRequestInfo {
+	rrt.mutex.Lock()
+	defer rrt.mutex.Unlock()
+
+	rrt.prune(currentBlock)
+
+	result := make([]*RelayRequestInfo, 0, len(rrt.requests))
+
+	for _, request := range rrt.requests {
+		timeoutBlock := request.BlockNumber + rrt.timeout
+
+		result = append(result, &RelayRequestInfo{
+			PreviousEntry:   fmt.Sprintf("0x%x", request.PreviousEntry),
+			GroupPublicKey:  fmt.Sprintf("0x%x", request.GroupPublicKey),
+			IsGroupMember:   rrt.isInGroup(request.GroupPublicKey),
+			RequestBlock:    request.BlockNumber,
+			TimeoutBlock:    timeoutBlock,
+			RemainingBlocks: timeoutBlock - currentBlock,
+		})
+	}
+
+	return result
+}
+
+// info returns relay entry requests pending at the current block.
+func (rrt *relayRequestTracker) info() clientinfo.ApplicationInfo {
+	currentBlock, err := rrt.blockCounter.CurrentBlock()
+	if err != nil {
+		logger.Errorf("failed to get current block: [%v]", err)
+		return clientinfo.ApplicationInfo{}
+	}
+
+	return clientinfo.ApplicationInfo{
+		"current_block":  currentBlock,
+		"relay_requests": rrt.pending(currentBlock),
+	}
+}
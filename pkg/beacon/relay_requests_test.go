@@ -0,0 +1,180 @@
+package beacon
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/beacon/event"
+)
+
+func TestRelayRequestTracker_Pending(t *testing.T) {
+	memberGroup := []byte{0x01}
+	otherGroup := []byte{0x02}
+
+	tracker := newRelayRequestTracker(
+		nil,
+		10,
+		func(groupPublicKey []byte) bool {
+			return bytes.Equal(groupPublicKey, memberGroup)
+		},
+	)
+
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry:  []byte{0xbb},
+		GroupPublicKey: otherGroup,
+		BlockNumber:    105,
+	})
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry:  []byte{0xaa},
+		GroupPublicKey: memberGroup,
+		BlockNumber:    100,
+	})
+	// Duplicated event is ignored.
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry:  []byte{0xaa},
+		GroupPublicKey: memberGroup,
+		BlockNumber:    100,
+	})
+
+	expected := []*RelayRequestInfo{
+		{
+			PreviousEntry:   "0xaa",
+			GroupPublicKey:  "0x01",
+			IsGroupMember:   true,
+			RequestBlock:    100,
+			TimeoutBlock:    110,
+			RemainingBlocks: 6,
+		},
+		{
+			PreviousEntry:   "0xbb",
+			GroupPublicKey:  "0x02",
+			IsGroupMember:   false,
+			RequestBlock:    105,
+			TimeoutBlock:    115,
+			RemainingBlocks: 11,
+		},
+	}
+
+	pending := tracker.pending(104)
+	if !reflect.DeepEqual(expected, pending) {
+		t.Errorf(
+			"unexpected pending requests\nexpected: %+v\nactual:   %+v",
+			expected,
+			pending,
+		)
+	}
+
+	// The first request times out.
+	pending = tracker.pending(110)
+	testutils.AssertIntsEqual(t, "pending requests", 1, len(pending))
+	testutils.AssertStringsEqual(
+		t,
+		"previous entry",
+		"0xbb",
+		pending[0].PreviousEntry,
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"remaining blocks",
+		5,
+		int(pending[0].RemainingBlocks),
+	)
+}
+
+func TestRelayRequestTracker_OnEntrySubmitted(t *testing.T) {
+	tracker := newRelayRequestTracker(
+		nil,
+		10,
+		func(groupPublicKey []byte) bool { return false },
+	)
+
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xaa},
+		BlockNumber:   100,
+	})
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xbb},
+		BlockNumber:   105,
+	})
+
+	// Entry submitted before any of the requests does not fulfil them.
+	tracker.onEntrySubmitted(99)
+	testutils.AssertIntsEqual(
+		t,
+		"pending requests",
+		2,
+		len(tracker.pending(104)),
+	)
+
+	tracker.onEntrySubmitted(103)
+
+	pending := tracker.pending(104)
+	testutils.AssertIntsEqual(t, "pending requests", 1, len(pending))
+	testutils.AssertStringsEqual(
+		t,
+		"previous entry",
+		"0xbb",
+		pending[0].PreviousEntry,
+	)
+}
+
+func TestRelayRequestTracker_DuplicatedEvents(t *testing.T) {
+	tracker := newRelayRequestTracker(
+		nil,
+		10,
+		func(groupPublicKey []byte) bool { return false },
+	)
+
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xaa},
+		BlockNumber:   100,
+	})
+	tracker.onEntrySubmitted(103)
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xbb},
+		BlockNumber:   105,
+	})
+
+	// Redelivered events of the fulfilled request must not affect the
+	// pending one.
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xaa},
+		BlockNumber:   100,
+	})
+	tracker.onEntrySubmitted(103)
+
+	pending := tracker.pending(106)
+	testutils.AssertIntsEqual(t, "pending requests", 1, len(pending))
+	testutils.AssertStringsEqual(
+		t,
+		"previous entry",
+		"0xbb",
+		pending[0].PreviousEntry,
+	)
+}
+
+func TestRelayRequestTracker_PruneOnAdd(t *testing.T) {
+	tracker := newRelayRequestTracker(
+		nil,
+		10,
+		func(groupPublicKey []byte) bool { return false },
+	)
+
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xaa},
+		BlockNumber:   100,
+	})
+	tracker.add(&event.RelayEntryRequested{
+		PreviousEntry: []byte{0xbb},
+		BlockNumber:   120,
+	})
+
+	testutils.AssertIntsEqual(
+		t,
+		"tracked requests",
+		1,
+		len(tracker.requests),
+	)
+}
@@ -0,0 +1,72 @@
+package bitcoin
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// EncodeAddress encodes the given standard output script as an address of
+// the given network. Only P2PKH, P2WPKH, P2SH, and P2WSH scripts are
+// supported.
+func EncodeAddress(script Script, network Network) (string, error) {
+	params, err := network.chainParams()
+	if err != nil {
+		return "", err
+	}
+
+	switch GetScriptType(script) {
+	case NonStandardScript:
+		return "", fmt.Errorf("non-standard script")
+	case P2TRScript:
+		return "", fmt.Errorf("taproot addresses are not supported")
+	}
+
+	_, addresses, _, err := txscript.ExtractPkScriptAddrs(script, params)
+	if err != nil {
+		return "", fmt.Errorf("cannot extract address: [%w]", err)
+	}
+
+	if len(addresses) != 1 {
+		return "", fmt.Errorf(
+			"unexpected number of addresses: [%v]",
+			len(addresses),
+		)
+	}
+
+	return addresses[0].EncodeAddress(), nil
+}
+
+// DecodeAddress decodes the given address of the given network into the
+// output script paying to it. Only P2PKH, P2WPKH, P2SH, and P2WSH addresses
+// are supported.
+func DecodeAddress(address string, network Network) (Script, error) {
+	params, err := network.chainParams()
+	if err != nil {
+		return nil, err
+	}
+
+	decodedAddress, err := btcutil.DecodeAddress(address, params)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode address: [%w]", err)
+	}
+
+	if !decodedAddress.IsForNet(params) {
+		return nil, fmt.Errorf(
+			"address is not for [%v] network",
+			network,
+		)
+	}
+
+	script, err := txscript.PayToAddrScript(decodedAddress)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build output script: [%w]", err)
+	}
+
+	if GetScriptType(script) == NonStandardScript {
+		return nil, fmt.Errorf("unsupported address type")
+	}
+
+	return script, nil
+}
@@ -0,0 +1,113 @@
+package bitcoin
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestEncodeDecodeAddress(t *testing.T) {
+	fromHex := func(hexString string) []byte {
+		bytes, err := hex.DecodeString(hexString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes
+	}
+
+	p2pkhScript := fromHex("76a914751e76e8199196d454941c45d1b3a323f1433bd688ac")
+	p2wpkhScript := fromHex("0014751e76e8199196d454941c45d1b3a323f1433bd6")
+	p2wshScript := fromHex(
+		"00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+	)
+
+	var tests = map[string]struct {
+		script          Script
+		network         Network
+		expectedAddress string
+	}{
+		"P2PKH mainnet": {
+			script:          p2pkhScript,
+			network:         Mainnet,
+			expectedAddress: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		},
+		"P2PKH testnet": {
+			script:          p2pkhScript,
+			network:         Testnet,
+			expectedAddress: "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+		},
+		"P2PKH testnet4": {
+			script:          p2pkhScript,
+			network:         Testnet4,
+			expectedAddress: "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+		},
+		"P2WPKH mainnet": {
+			script:          p2wpkhScript,
+			network:         Mainnet,
+			expectedAddress: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		"P2WPKH testnet4": {
+			script:          p2wpkhScript,
+			network:         Testnet4,
+			expectedAddress: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+		},
+		"P2WPKH regtest": {
+			script:          p2wpkhScript,
+			network:         Regtest,
+			expectedAddress: "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080",
+		},
+		"P2WSH testnet": {
+			script:          p2wshScript,
+			network:         Testnet,
+			expectedAddress: "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			address, err := EncodeAddress(test.script, test.network)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"address",
+				test.expectedAddress,
+				address,
+			)
+
+			script, err := DecodeAddress(address, test.network)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBytesEqual(t, test.script, script)
+		})
+	}
+}
+
+func TestDecodeAddress_WrongNetwork(t *testing.T) {
+	_, err := DecodeAddress(
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		Regtest,
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestEncodeAddress_UnknownNetwork(t *testing.T) {
+	script, err := hex.DecodeString(
+		"0014751e76e8199196d454941c45d1b3a323f1433bd6",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = EncodeAddress(script, Unknown)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -9,6 +9,8 @@ package bitcoin
 
 import (
 	"bytes"
+	"fmt"
+
 	"github.com/btcsuite/btcd/wire"
 )
 
@@ -89,8 +91,21 @@ const (
 	Mainnet
 	Testnet
 	Regtest
+	Testnet4
 )
 
 func (n Network) String() string {
-	return []string{"unknown", "mainnet", "testnet", "regtest"}[n]
+	return []string{"unknown", "mainnet", "testnet", "regtest", "testnet4"}[n]
+}
+
+// ParseNetwork returns the Bitcoin network with the given name, as returned
+// by the String function.
+func ParseNetwork(name string) (Network, error) {
+	for _, network := range []Network{Mainnet, Testnet, Regtest, Testnet4} {
+		if network.String() == name {
+			return network, nil
+		}
+	}
+
+	return Unknown, fmt.Errorf("unknown Bitcoin network [%s]", name)
 }
@@ -120,3 +120,25 @@ func TestWriteCompactSizeUint(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNetwork(t *testing.T) {
+	for _, network := range []Network{Mainnet, Testnet, Regtest, Testnet4} {
+		parsed, err := ParseNetwork(network.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if parsed != network {
+			t.Errorf(
+				"unexpected network\nexpected: %v\nactual:   %v",
+				network,
+				parsed,
+			)
+		}
+	}
+
+	_, err := ParseNetwork("unknown")
+	if err == nil {
+		t.Errorf("expected error for unknown network")
+	}
+}
@@ -0,0 +1,852 @@
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/wrappers"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/proxy"
+)
+
+var logger = log.Logger("keep-bitcoind")
+
+// rpcInWarmupErrorCode is the code of the error returned by Bitcoin Core
+// while the node is still starting up.
+const rpcInWarmupErrorCode = -28
+
+// errAddressIndexUnsupported is returned by functions that require an index
+// of transactions by address. Bitcoin Core does not maintain such an index.
+var errAddressIndexUnsupported = fmt.Errorf(
+	"address history queries are not supported by the Bitcoin Core backend",
+)
+
+// AddressIndex is a handle for lookups of transactions by address. Bitcoin
+// Core does not index transactions by address so these lookups are routed to
+// a backend maintaining such an index, e.g. an Electrum server.
+type AddressIndex interface {
+	GetTransactionsForPublicKeyHash(
+		publicKeyHash [20]byte,
+		limit int,
+	) ([]*bitcoin.Transaction, error)
+	GetTransactionsPageForPublicKeyHash(
+		publicKeyHash [20]byte,
+		query *bitcoin.TransactionHistoryQuery,
+	) (*bitcoin.TransactionHistoryPage, error)
+	GetTxHashesForPublicKeyHash(publicKeyHash [20]byte) ([]bitcoin.Hash, error)
+	GetMempoolForPublicKeyHash(
+		publicKeyHash [20]byte,
+	) ([]*bitcoin.Transaction, error)
+	GetMempoolUtxosForPublicKeyHash(
+		publicKeyHash [20]byte,
+	) ([]*bitcoin.UnspentTransactionOutput, error)
+}
+
+// Option is a functional option of the Bitcoin Core connection.
+type Option func(c *Connection)
+
+// WithAddressIndex routes lookups of transactions by address to the given
+// address index. Without it, such lookups are not supported.
+func WithAddressIndex(addressIndex AddressIndex) Option {
+	return func(c *Connection) {
+		c.addressIndex = addressIndex
+	}
+}
+
+// Connection is a handle for interactions with Bitcoin Core node.
+type Connection struct {
+	parentCtx    context.Context
+	client       *http.Client
+	config       Config
+	requestID    uint64
+	addressIndex AddressIndex
+}
+
+// Connect initializes handle with provided Config. The Bitcoin Core node
+// must run with the transaction index enabled (`txindex=1`) so that
+// arbitrary transactions can be retrieved.
+func Connect(
+	parentCtx context.Context,
+	config Config,
+	options ...Option,
+) (bitcoin.Chain, error) {
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if config.RequestRetryTimeout == 0 {
+		config.RequestRetryTimeout = DefaultRequestRetryTimeout
+	}
+
+	client, err := proxy.NewHTTPClient(config.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up proxy: [%w]", err)
+	}
+
+	c := &Connection{
+		parentCtx: parentCtx,
+		client:    client,
+		config:    config,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if err := c.verifyServer(); err != nil {
+		return nil, fmt.Errorf("failed to verify bitcoin core node: [%w]", err)
+	}
+
+	return c, nil
+}
+
+// GetTransaction gets the transaction with the given transaction hash.
+// If the transaction with the given hash was not found on the chain,
+// this function returns an error.
+func (c *Connection) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	rawTransaction, err := requestWithRetry[string](
+		c,
+		"getrawtransaction",
+		txID,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get raw transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	transactionBytes, err := hex.DecodeString(rawTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode a hex string: [%w]", err)
+	}
+
+	result := new(bitcoin.Transaction)
+	if err := result.Deserialize(transactionBytes); err != nil {
+		return nil, fmt.Errorf("failed to deserialize a transaction: [%w]", err)
+	}
+
+	return result, nil
+}
+
+// GetTransactionConfirmations gets the number of confirmations for the
+// transaction with the given transaction hash. If the transaction with the
+// given hash was not found on the chain, this function returns an error.
+func (c *Connection) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	type verboseTransaction struct {
+		// Confirmations is not set for transactions living in the mempool.
+		Confirmations uint `json:"confirmations"`
+	}
+
+	transaction, err := requestWithRetry[verboseTransaction](
+		c,
+		"getrawtransaction",
+		txID,
+		true,
+	)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to get transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	return transaction.Confirmations, nil
+}
+
+// BroadcastTransaction broadcasts the given transaction over the
+// network of the Bitcoin chain nodes. If the broadcast action could not be
+// done, this function returns an error. This function does not give any
+// guarantees regarding transaction mining. The transaction may be mined or
+// rejected eventually.
+func (c *Connection) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	rawTx := hex.EncodeToString(transaction.Serialize())
+
+	logger.Debugf("broadcasting transaction [%s]", rawTx)
+
+	txID, err := requestWithRetry[string](c, "sendrawtransaction", rawTx)
+	if err != nil {
+		return fmt.Errorf("failed to broadcast the transaction: [%w]", err)
+	}
+
+	logger.Infof("transaction broadcast successful: [%s]", txID)
+
+	return nil
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip). If the
+// latest block was not determined, this function returns an error.
+func (c *Connection) GetLatestBlockHeight() (uint, error) {
+	blockHeight, err := requestWithRetry[uint](c, "getblockcount")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block count: [%w]", err)
+	}
+
+	return blockHeight, nil
+}
+
+// GetBlockHeader gets the block header for the given block height. If the
+// block with the given height was not found on the chain, this function
+// returns an error.
+func (c *Connection) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	rawBlockHeader, err := requestWithRetry[string](
+		c,
+		"getblockheader",
+		blockHash,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header: [%w]", err)
+	}
+
+	blockHeaderBytes, err := hex.DecodeString(rawBlockHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode a hex string: [%w]", err)
+	}
+
+	if len(blockHeaderBytes) != bitcoin.BlockHeaderByteLength {
+		return nil, fmt.Errorf(
+			"wrong block header length: [%v]",
+			len(blockHeaderBytes),
+		)
+	}
+
+	var serializedBlockHeader [bitcoin.BlockHeaderByteLength]byte
+	copy(serializedBlockHeader[:], blockHeaderBytes)
+
+	blockHeader := new(bitcoin.BlockHeader)
+	blockHeader.Deserialize(serializedBlockHeader)
+
+	return blockHeader, nil
+}
+
+// GetTransactionMerkleProof gets the Merkle proof for a given transaction.
+// The transaction's hash and the block the transaction was included in the
+// blockchain need to be provided.
+func (c *Connection) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	blockTransactions, err := c.getBlockTransactions(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	position := -1
+	for i, blockTransaction := range blockTransactions {
+		if blockTransaction == transactionHash {
+			position = i
+			break
+		}
+	}
+
+	if position < 0 {
+		return nil, fmt.Errorf(
+			"transaction [%s] not found in block [%v]",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			blockHeight,
+		)
+	}
+
+	merkleBranch := computeMerkleBranch(blockTransactions, position)
+
+	// The Merkle nodes are expected to be in the same format as returned by
+	// Electrum servers, i.e. hex strings in the reversed byte order.
+	merkleNodes := make([]string, len(merkleBranch))
+	for i, node := range merkleBranch {
+		merkleNodes[i] = node.Hex(bitcoin.ReversedByteOrder)
+	}
+
+	return &bitcoin.TransactionMerkleProof{
+		BlockHeight: blockHeight,
+		MerkleNodes: merkleNodes,
+		Position:    uint(position),
+	}, nil
+}
+
+// GetTransactionsForPublicKeyHash is routed to the address index as Bitcoin
+// Core does not index transactions by address. This function returns an error
+// if the address index is not set.
+func (c *Connection) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	if c.addressIndex == nil {
+		return nil, errAddressIndexUnsupported
+	}
+
+	return c.addressIndex.GetTransactionsForPublicKeyHash(publicKeyHash, limit)
+}
+
+// GetTransactionsPageForPublicKeyHash is routed to the address index as
+// Bitcoin Core does not index transactions by address. This function returns
+// an error if the address index is not set.
+func (c *Connection) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	if c.addressIndex == nil {
+		return nil, errAddressIndexUnsupported
+	}
+
+	return c.addressIndex.GetTransactionsPageForPublicKeyHash(
+		publicKeyHash,
+		query,
+	)
+}
+
+// GetTxHashesForPublicKeyHash is routed to the address index as Bitcoin Core
+// does not index transactions by address. This function returns an error if
+// the address index is not set.
+func (c *Connection) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	if c.addressIndex == nil {
+		return nil, errAddressIndexUnsupported
+	}
+
+	return c.addressIndex.GetTxHashesForPublicKeyHash(publicKeyHash)
+}
+
+// GetMempoolForPublicKeyHash is routed to the address index as Bitcoin Core
+// does not index transactions by address. This function returns an error if
+// the address index is not set.
+func (c *Connection) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	if c.addressIndex == nil {
+		return nil, errAddressIndexUnsupported
+	}
+
+	return c.addressIndex.GetMempoolForPublicKeyHash(publicKeyHash)
+}
+
+// GetUtxosForPublicKeyHash gets unspent outputs of confirmed transactions that
+// are controlled by the given public key hash (either a P2PKH or P2WPKH script).
+// The returned UTXOs are ordered by block height in the ascending order, i.e.
+// the latest UTXO is at the end of the list. The returned list does not contain
+// unspent outputs of unconfirmed transactions living in the mempool at the
+// moment of request. Outputs used as inputs of confirmed or mempool
+// transactions are not returned as well because they are no longer UTXOs.
+// The UTXO set of the node is scanned on each call so this function may be
+// time-consuming.
+func (c *Connection) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build P2PKH script: [%v]", err)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build P2WPKH script: [%v]", err)
+	}
+
+	type scanResult struct {
+		Success  bool `json:"success"`
+		Unspents []struct {
+			TxID   string      `json:"txid"`
+			Vout   uint32      `json:"vout"`
+			Amount json.Number `json:"amount"`
+			Height uint        `json:"height"`
+		} `json:"unspents"`
+	}
+
+	result, err := requestWithRetry[scanResult](
+		c,
+		"scantxoutset",
+		"start",
+		[]string{
+			fmt.Sprintf("raw(%s)", hex.EncodeToString(p2pkh)),
+			fmt.Sprintf("raw(%s)", hex.EncodeToString(p2wpkh)),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan UTXO set: [%w]", err)
+	}
+
+	if !result.Success {
+		return nil, fmt.Errorf("UTXO set scan did not succeed")
+	}
+
+	sort.SliceStable(result.Unspents, func(i, j int) bool {
+		return result.Unspents[i].Height < result.Unspents[j].Height
+	})
+
+	utxos := make([]*bitcoin.UnspentTransactionOutput, 0)
+
+	for _, unspent := range result.Unspents {
+		// The UTXO set scan does not take the mempool into account. Skip
+		// outputs that are already spent by mempool transactions.
+		output, err := requestWithRetry[json.RawMessage](
+			c,
+			"gettxout",
+			unspent.TxID,
+			unspent.Vout,
+			true,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get output [%s:%v]: [%w]",
+				unspent.TxID,
+				unspent.Vout,
+				err,
+			)
+		}
+
+		if isNullResult(output) {
+			continue
+		}
+
+		transactionHash, err := bitcoin.NewHashFromString(
+			unspent.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				unspent.TxID,
+				err,
+			)
+		}
+
+		value, err := convertBtcToSatoshi(unspent.Amount)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse amount [%s]: [%v]",
+				unspent.Amount,
+				err,
+			)
+		}
+
+		utxos = append(utxos, &bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: transactionHash,
+				OutputIndex:     unspent.Vout,
+			},
+			Value: value,
+		})
+	}
+
+	return utxos, nil
+}
+
+// GetMempoolUtxosForPublicKeyHash is routed to the address index as Bitcoin
+// Core does not index transactions by address. This function returns an error
+// if the address index is not set.
+func (c *Connection) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	if c.addressIndex == nil {
+		return nil, errAddressIndexUnsupported
+	}
+
+	return c.addressIndex.GetMempoolUtxosForPublicKeyHash(publicKeyHash)
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks.
+func (c *Connection) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	type feeEstimate struct {
+		// FeeRate is the fee rate in BTC/kvB. It is not set if the node does
+		// not have enough information to make an estimate.
+		FeeRate *float64 `json:"feerate"`
+		Errors  []string `json:"errors"`
+	}
+
+	estimate, err := requestWithRetry[feeEstimate](
+		c,
+		"estimatesmartfee",
+		blocks,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate fee: [%w]", err)
+	}
+
+	if estimate.FeeRate == nil {
+		return 0, fmt.Errorf(
+			"node does not have enough information to make an estimate: [%s]",
+			strings.Join(estimate.Errors, "; "),
+		)
+	}
+
+	return convertBtcKbToSatVByte(*estimate.FeeRate), nil
+}
+
+// GetCoinbaseTxHash gets the hash of the coinbase transaction for the given
+// block height.
+func (c *Connection) GetCoinbaseTxHash(blockHeight uint) (bitcoin.Hash, error) {
+	blockTransactions, err := c.getBlockTransactions(blockHeight)
+	if err != nil {
+		return bitcoin.Hash{}, fmt.Errorf(
+			"failed to get coinbase tx hash for block height [%v]: [%v]",
+			blockHeight,
+			err,
+		)
+	}
+
+	if len(blockTransactions) == 0 {
+		return bitcoin.Hash{}, fmt.Errorf(
+			"block [%v] has no transactions",
+			blockHeight,
+		)
+	}
+
+	return blockTransactions[0], nil
+}
+
+// getBlockHash gets the hash of the block with the given height, as
+// a hex string in the reversed byte order.
+func (c *Connection) getBlockHash(blockHeight uint) (string, error) {
+	blockHash, err := requestWithRetry[string](c, "getblockhash", blockHeight)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to get hash of block [%v]: [%w]",
+			blockHeight,
+			err,
+		)
+	}
+
+	return blockHash, nil
+}
+
+// getBlockTransactions gets hashes of all transactions of the block with
+// the given height, in the order they are included in the block.
+func (c *Connection) getBlockTransactions(
+	blockHeight uint,
+) ([]bitcoin.Hash, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	type block struct {
+		Tx []string `json:"tx"`
+	}
+
+	result, err := requestWithRetry[block](c, "getblock", blockHash, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: [%w]", err)
+	}
+
+	transactionHashes := make([]bitcoin.Hash, len(result.Tx))
+	for i, txID := range result.Tx {
+		transactionHash, err := bitcoin.NewHashFromString(
+			txID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse hash [%s]: [%v]", txID, err)
+		}
+
+		transactionHashes[i] = transactionHash
+	}
+
+	return transactionHashes, nil
+}
+
+func (c *Connection) verifyServer() error {
+	type networkInfo struct {
+		Version    int    `json:"version"`
+		Subversion string `json:"subversion"`
+	}
+
+	info, err := requestWithRetry[networkInfo](c, "getnetworkinfo")
+	if err != nil {
+		return fmt.Errorf("failed to get network info: [%w]", err)
+	}
+
+	logger.Infof(
+		"connected to bitcoin core node [version: [%v], subversion: [%s]]",
+		info.Version,
+		info.Subversion,
+	)
+
+	type indexInfo struct {
+		Synced bool `json:"synced"`
+	}
+
+	indexes, err := requestWithRetry[map[string]indexInfo](
+		c,
+		"getindexinfo",
+		"txindex",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get index info: [%w]", err)
+	}
+
+	txIndex, ok := indexes["txindex"]
+	if !ok {
+		return fmt.Errorf(
+			"bitcoin core node [%s] does not have the transaction index "+
+				"enabled; please run it with txindex=1",
+			c.config.URL,
+		)
+	}
+
+	if !txIndex.Synced {
+		logger.Warnf(
+			"transaction index of bitcoin core node [%s] is not synced yet",
+			c.config.URL,
+		)
+	}
+
+	return nil
+}
+
+// rpcRequest is a Bitcoin Core JSON-RPC request.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcResponse is a Bitcoin Core JSON-RPC response.
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rpcError is an error returned by the Bitcoin Core node in response to an
+// RPC request.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("RPC error [%v]: %s", e.Code, e.Message)
+}
+
+// call executes a single RPC request and unmarshals its result to the given
+// result.
+func (c *Connection) call(
+	ctx context.Context,
+	method string,
+	params []interface{},
+	result interface{},
+) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	requestBody, err := json.Marshal(&rpcRequest{
+		JSONRPC: "1.0",
+		ID:      atomic.AddUint64(&c.requestID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot marshal request: [%w]", err)
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.config.URL,
+		bytes.NewReader(requestBody),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create request: [%w]", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if c.config.Username != "" || c.config.Password != "" {
+		request.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("request unauthorized; verify RPC credentials")
+	}
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read response: [%w]", err)
+	}
+
+	// Bitcoin Core responds with non-200 status codes in case of RPC errors
+	// but the body still contains the JSON-RPC response with error details.
+	var rpcResult rpcResponse
+	if err := json.Unmarshal(responseBody, &rpcResult); err != nil {
+		return fmt.Errorf(
+			"cannot unmarshal response with status [%s]: [%w]",
+			response.Status,
+			err,
+		)
+	}
+
+	if rpcResult.Error != nil {
+		return rpcResult.Error
+	}
+
+	if err := json.Unmarshal(rpcResult.Result, result); err != nil {
+		return fmt.Errorf("cannot unmarshal result: [%w]", err)
+	}
+
+	return nil
+}
+
+func requestWithRetry[K interface{}](
+	c *Connection,
+	method string,
+	params ...interface{},
+) (K, error) {
+	startTime := time.Now()
+	logger.Debugf("starting [%s] request to Bitcoin Core node", method)
+
+	var result K
+	var permanentErr error
+
+	err := wrappers.DoWithDefaultRetry(
+		c.parentCtx,
+		c.config.RequestRetryTimeout,
+		func(ctx context.Context) error {
+			requestCtx, requestCancel := context.WithTimeout(
+				ctx,
+				c.config.RequestTimeout,
+			)
+			defer requestCancel()
+
+			var r K
+			err := c.call(requestCtx, method, params, &r)
+			if err != nil {
+				// Errors returned by the node, e.g. about a missing
+				// transaction, will not go away on retry. The only
+				// exception is the error returned while the node is
+				// warming up.
+				var rpcErr *rpcError
+				if errors.As(err, &rpcErr) &&
+					rpcErr.Code != rpcInWarmupErrorCode {
+					permanentErr = err
+					return nil
+				}
+
+				return fmt.Errorf("request failed: [%w]", err)
+			}
+
+			result = r
+			return nil
+		},
+	)
+	if err == nil {
+		err = permanentErr
+	}
+
+	solveRequestOutcome := func(err error) string {
+		if err != nil {
+			return fmt.Sprintf("error: [%v]", err)
+		}
+		return "success"
+	}
+
+	logger.Debugf(
+		"[%s] request to Bitcoin Core node completed with [%s] after [%s]",
+		method,
+		solveRequestOutcome(err),
+		time.Since(startTime),
+	)
+
+	return result, err
+}
+
+// computeMerkleBranch computes the Merkle branch of the transaction at the
+// given position among the given hashes of all transactions of a block. The
+// returned hashes are the ones the transaction hash is paired with,
+// recursively, in order to trace up to the Merkle root of the block,
+// deepest pairing first.
+func computeMerkleBranch(
+	transactionHashes []bitcoin.Hash,
+	position int,
+) []bitcoin.Hash {
+	branch := make([]bitcoin.Hash, 0)
+
+	level := make([]bitcoin.Hash, len(transactionHashes))
+	copy(level, transactionHashes)
+
+	for len(level) > 1 {
+		// If a level has an odd number of hashes, the last hash is paired
+		// with itself.
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		branch = append(branch, level[position^1])
+
+		nextLevel := make([]bitcoin.Hash, len(level)/2)
+		for i := range nextLevel {
+			pair := make([]byte, 0, 2*bitcoin.HashByteLength)
+			pair = append(pair, level[2*i][:]...)
+			pair = append(pair, level[2*i+1][:]...)
+			nextLevel[i] = bitcoin.ComputeHash(pair)
+		}
+
+		level = nextLevel
+		position /= 2
+	}
+
+	return branch
+}
+
+func convertBtcKbToSatVByte(btcPerKbFee float64) int64 {
+	// To convert from BTC/KB to sat/vbyte, we need to multiply by 1e8/1e3.
+	satPerVByte := (1e8 / 1e3) * btcPerKbFee
+	// Make sure the minimum returned sat/vbyte fee is always 1.
+	satPerVByte = math.Max(satPerVByte, 1)
+	// Round the returned fee to be an integer.
+	return int64(math.Round(satPerVByte))
+}
+
+// convertBtcToSatoshi converts the given BTC amount to satoshis.
+func convertBtcToSatoshi(btcAmount json.Number) (int64, error) {
+	amount, err := btcAmount.Float64()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(amount * 1e8)), nil
+}
+
+// isNullResult returns true if the given raw RPC result is JSON null.
+func isNullResult(result json.RawMessage) bool {
+	return len(result) == 0 || string(result) == "null"
+}
@@ -0,0 +1,437 @@
+package bitcoind
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// rawTransaction is a raw testnet transaction served by the fake Bitcoin Core
+// node.
+const rawTransaction = "0100000000010110a15e879b7e8b07df62772579a64bf2b409409bbcc8bc2c7f6e39" +
+	"31dc615e920100000000ffffffff02042900000000000017a9143ec459d0f3c29286" +
+	"ae5df5fcc421e2786024277e87b4121600000000001600148db50eb52063ea9d98b3" +
+	"eac91489a90f738986f6024830450221009740ad12d2e74c00ccb4741d533d2ecd69" +
+	"02289144c4626508afb61eed790c97022006e67179e8e2a63dc4f1ab758867d8bbfe" +
+	"0a2b67682be6dadfa8e07d3b7ba04d012103989d253b17a6a0f41838b84ff0d20e88" +
+	"98f9d7b1a98f2564da4cc29dcf8581d900000000"
+
+func TestConnection(t *testing.T) {
+	transactionBytes, err := hex.DecodeString(rawTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transaction := new(bitcoin.Transaction)
+	if err := transaction.Deserialize(transactionBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	txID := transaction.Hash().Hex(bitcoin.ReversedByteOrder)
+
+	blockTransactions := []bitcoin.Hash{
+		{1},
+		transaction.Hash(),
+		{3},
+	}
+
+	blockTxIDs := make([]string, len(blockTransactions))
+	for i, blockTransaction := range blockTransactions {
+		blockTxIDs[i] = blockTransaction.Hex(bitcoin.ReversedByteOrder)
+	}
+
+	blockHeader := &bitcoin.BlockHeader{
+		Version:                 536870912,
+		PreviousBlockHeaderHash: bitcoin.Hash{4},
+		MerkleRootHash:          bitcoin.Hash{5},
+		Time:                    1686000000,
+		Bits:                    0x1c7fff80,
+		Nonce:                   12345,
+	}
+	serializedBlockHeader := blockHeader.Serialize()
+
+	server := newFakeServer(t, map[string]func(params []json.RawMessage) (interface{}, *rpcError){
+		"getnetworkinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{
+				"version":    250000,
+				"subversion": "/Satoshi:25.0.0/",
+			}, nil
+		},
+		"getindexinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{
+				"txindex": map[string]interface{}{"synced": true},
+			}, nil
+		},
+		"getblockcount": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return 790300, nil
+		},
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			var requestedTxID string
+			var verbose bool
+			mustUnmarshal(t, params[0], &requestedTxID)
+			mustUnmarshal(t, params[1], &verbose)
+
+			if requestedTxID != txID {
+				return nil, &rpcError{
+					Code:    -5,
+					Message: "No such mempool or blockchain transaction",
+				}
+			}
+
+			if verbose {
+				return map[string]interface{}{"confirmations": 6}, nil
+			}
+
+			return rawTransaction, nil
+		},
+		"getblockhash": func(params []json.RawMessage) (interface{}, *rpcError) {
+			var height uint
+			mustUnmarshal(t, params[0], &height)
+			return fmt.Sprintf("%064x", height), nil
+		},
+		"getblockheader": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return hex.EncodeToString(serializedBlockHeader[:]), nil
+		},
+		"getblock": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"tx": blockTxIDs}, nil
+		},
+		"estimatesmartfee": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"feerate": 0.0012351, "blocks": 6}, nil
+		},
+	})
+	defer server.Close()
+
+	btcChain, err := Connect(context.Background(), Config{
+		URL:                 server.URL,
+		Username:            "user",
+		Password:            "password",
+		RequestRetryTimeout: 1 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latestBlockHeight, err := btcChain.GetLatestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "latest block height", 790300, uint64(latestBlockHeight))
+
+	actualTransaction, err := btcChain.GetTransaction(transaction.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(transaction, actualTransaction) {
+		t.Errorf("unexpected transaction")
+	}
+
+	_, err = btcChain.GetTransaction(bitcoin.Hash{9})
+	if err == nil {
+		t.Errorf("expected error for missing transaction")
+	}
+
+	confirmations, err := btcChain.GetTransactionConfirmations(transaction.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 6, uint64(confirmations))
+
+	actualBlockHeader, err := btcChain.GetBlockHeader(790290)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(blockHeader, actualBlockHeader) {
+		t.Errorf(
+			"unexpected block header\nexpected: %v\nactual:   %v",
+			blockHeader,
+			actualBlockHeader,
+		)
+	}
+
+	merkleProof, err := btcChain.GetTransactionMerkleProof(
+		transaction.Hash(),
+		790290,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMerkleProof := &bitcoin.TransactionMerkleProof{
+		BlockHeight: 790290,
+		MerkleNodes: []string{
+			blockTxIDs[0],
+			hashPair(blockTransactions[2], blockTransactions[2]).Hex(
+				bitcoin.ReversedByteOrder,
+			),
+		},
+		Position: 1,
+	}
+	if !reflect.DeepEqual(expectedMerkleProof, merkleProof) {
+		t.Errorf(
+			"unexpected merkle proof\nexpected: %v\nactual:   %v",
+			expectedMerkleProof,
+			merkleProof,
+		)
+	}
+
+	coinbaseTxHash, err := btcChain.GetCoinbaseTxHash(790290)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBytesEqual(t, blockTransactions[0][:], coinbaseTxHash[:])
+
+	fee, err := btcChain.EstimateSatPerVByteFee(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "sat/vbyte fee", 124, int(fee))
+
+	_, err = btcChain.GetTransactionsForPublicKeyHash([20]byte{}, 5)
+	if err != errAddressIndexUnsupported {
+		t.Errorf("unexpected error: [%v]", err)
+	}
+}
+
+func TestConnect_TxIndexDisabled(t *testing.T) {
+	server := newFakeServer(t, map[string]func(params []json.RawMessage) (interface{}, *rpcError){
+		"getnetworkinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"version": 250000}, nil
+		},
+		"getindexinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{}, nil
+		},
+	})
+	defer server.Close()
+
+	_, err := Connect(context.Background(), Config{
+		URL:                 server.URL,
+		RequestRetryTimeout: 1 * time.Second,
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestConnect_WithAddressIndex(t *testing.T) {
+	server := newFakeServer(t, map[string]func(params []json.RawMessage) (interface{}, *rpcError){
+		"getnetworkinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"version": 250000}, nil
+		},
+		"getindexinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{
+				"txindex": map[string]interface{}{"synced": true},
+			}, nil
+		},
+	})
+	defer server.Close()
+
+	addressIndex := &localAddressIndex{
+		txHashes: []bitcoin.Hash{{1}, {2}},
+	}
+
+	btcChain, err := Connect(
+		context.Background(),
+		Config{URL: server.URL},
+		WithAddressIndex(addressIndex),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKeyHash := [20]byte{3}
+
+	txHashes, err := btcChain.GetTxHashesForPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(addressIndex.txHashes, txHashes) {
+		t.Errorf(
+			"unexpected transaction hashes\nexpected: %v\nactual:   %v",
+			addressIndex.txHashes,
+			txHashes,
+		)
+	}
+	testutils.AssertBytesEqual(
+		t,
+		publicKeyHash[:],
+		addressIndex.lastPublicKeyHash[:],
+	)
+}
+
+// localAddressIndex is an AddressIndex returning preset transaction hashes.
+type localAddressIndex struct {
+	txHashes          []bitcoin.Hash
+	lastPublicKeyHash [20]byte
+}
+
+func (lai *localAddressIndex) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	panic("not implemented")
+}
+
+func (lai *localAddressIndex) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	panic("not implemented")
+}
+
+func (lai *localAddressIndex) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	lai.lastPublicKeyHash = publicKeyHash
+	return lai.txHashes, nil
+}
+
+func (lai *localAddressIndex) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	panic("not implemented")
+}
+
+func (lai *localAddressIndex) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	panic("not implemented")
+}
+
+func TestComputeMerkleBranch(t *testing.T) {
+	for transactionsCount := 1; transactionsCount <= 7; transactionsCount++ {
+		transactionHashes := make([]bitcoin.Hash, transactionsCount)
+		for i := range transactionHashes {
+			transactionHashes[i] = bitcoin.Hash{byte(i + 1)}
+		}
+
+		expectedRoot := computeMerkleRoot(transactionHashes)
+
+		for position := range transactionHashes {
+			branch := computeMerkleBranch(transactionHashes, position)
+
+			root := transactionHashes[position]
+			index := position
+			for _, node := range branch {
+				if index%2 == 0 {
+					root = hashPair(root, node)
+				} else {
+					root = hashPair(node, root)
+				}
+				index /= 2
+			}
+
+			testutils.AssertBytesEqual(t, expectedRoot[:], root[:])
+		}
+	}
+}
+
+func TestConvertBtcKbToSatVByte(t *testing.T) {
+	var tests = map[string]struct {
+		btcPerKbFee            float64
+		expectedSatPerVByteFee int64
+	}{
+		"BTC/KB is 0": {
+			btcPerKbFee:            0,
+			expectedSatPerVByteFee: 1,
+		},
+		"BTC/KB is 0.00002": {
+			btcPerKbFee:            0.00002,
+			expectedSatPerVByteFee: 2,
+		},
+		"BTC/KB is 0.0012349": {
+			btcPerKbFee:            0.0012349,
+			expectedSatPerVByteFee: 123,
+		},
+		"BTC/KB is 0.0012351": {
+			btcPerKbFee:            0.0012351,
+			expectedSatPerVByteFee: 124,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			satPerVByteFee := convertBtcKbToSatVByte(test.btcPerKbFee)
+
+			testutils.AssertIntsEqual(
+				t,
+				"sat/vbyte fee",
+				int(test.expectedSatPerVByteFee),
+				int(satPerVByteFee),
+			)
+		})
+	}
+}
+
+func newFakeServer(
+	t *testing.T,
+	handlers map[string]func(params []json.RawMessage) (interface{}, *rpcError),
+) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				ID     uint64            `json:"id"`
+				Method string            `json:"method"`
+				Params []json.RawMessage `json:"params"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("cannot decode request: [%v]", err)
+				return
+			}
+
+			response := map[string]interface{}{"id": request.ID}
+
+			handler, ok := handlers[request.Method]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				response["error"] = &rpcError{
+					Code:    -32601,
+					Message: "Method not found",
+				}
+			} else if result, rpcErr := handler(request.Params); rpcErr != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				response["error"] = rpcErr
+			} else {
+				response["result"] = result
+			}
+
+			if err := json.NewEncoder(w).Encode(response); err != nil {
+				t.Errorf("cannot encode response: [%v]", err)
+			}
+		},
+	))
+}
+
+func mustUnmarshal(t *testing.T, data json.RawMessage, value interface{}) {
+	if err := json.Unmarshal(data, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hashPair(left, right bitcoin.Hash) bitcoin.Hash {
+	return bitcoin.ComputeHash(append(left[:], right[:]...))
+}
+
+func computeMerkleRoot(hashes []bitcoin.Hash) bitcoin.Hash {
+	if len(hashes) == 1 {
+		return hashes[0]
+	}
+
+	nextLevel := make([]bitcoin.Hash, 0)
+	for i := 0; i < len(hashes); i += 2 {
+		if i+1 < len(hashes) {
+			nextLevel = append(nextLevel, hashPair(hashes[i], hashes[i+1]))
+		} else {
+			nextLevel = append(nextLevel, hashPair(hashes[i], hashes[i]))
+		}
+	}
+
+	return computeMerkleRoot(nextLevel)
+}
@@ -0,0 +1,31 @@
+package bitcoind
+
+import "time"
+
+const (
+	// DefaultRequestTimeout is a default timeout used for a single attempt of
+	// Bitcoin Core RPC request.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultRequestRetryTimeout is a default timeout used for Bitcoin Core
+	// RPC request retries.
+	DefaultRequestRetryTimeout = 2 * time.Minute
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// URL to the Bitcoin Core RPC server in format: `scheme://hostname:port`.
+	// If empty, the Bitcoin Core backend is not used.
+	URL string
+	// Username used to authenticate RPC requests.
+	Username string
+	// Password used to authenticate RPC requests.
+	Password string
+	// Timeout for a single attempt of Bitcoin Core RPC request.
+	RequestTimeout time.Duration
+	// Timeout for Bitcoin Core RPC request retries.
+	RequestRetryTimeout time.Duration
+	// URL of the SOCKS5 proxy the RPC requests are routed through, e.g.
+	// `socks5://127.0.0.1:9050`. Host names, including .onion addresses, are
+	// resolved by the proxy. If empty, the requests are sent directly.
+	ProxyURL string
+}
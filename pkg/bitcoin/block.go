@@ -101,11 +101,8 @@ func (bh *BlockHeader) Deserialize(rawBlockHeader [BlockHeaderByteLength]byte) {
 // block header serialization format:
 // [Version][PreviousBlockHeaderHash][MerkleRootHash][Time][Bits][Nonce].
 func (bh *BlockHeader) Hash() Hash {
-	// TODO: Implementation of the Hash function that consists of the following:
-	//       1. Call bh.Serialize() to get the serialized block hash.
-	//       2. Compute the double SHA-256 over the serialized  block hash.
-	//       3. Construct the Hash instance appropriately.
-	return Hash{}
+	serializedHeader := bh.Serialize()
+	return ComputeHash(serializedHeader[:])
 }
 
 // Target calculates the difficulty target of a block header. A Bitcoin block
@@ -133,3 +130,20 @@ func (bh *BlockHeader) Difficulty() *big.Int {
 
 	return difficulty
 }
+
+// IsMinDifficulty returns true if the block header has the lowest possible
+// difficulty allowed on the given network and the network allows mining
+// such blocks regardless of the difficulty of the current epoch. Difficulty
+// of such blocks must not be compared with the difficulty of their epoch.
+func (bh *BlockHeader) IsMinDifficulty(network Network) bool {
+	if !network.AllowsMinDifficultyBlocks() {
+		return false
+	}
+
+	params, err := network.chainParams()
+	if err != nil {
+		return false
+	}
+
+	return bh.Bits == params.PowLimitBits
+}
@@ -107,6 +107,42 @@ func TestBlockHeaderDeserialize(t *testing.T) {
 	}
 }
 
+func TestBlockHeaderHash(t *testing.T) {
+	// Test data comes from a Bitcoin testnet block:
+	// https://live.blockcypher.com/btc-testnet/block/000000000000002af10911b8db32ed34dc6ea6515f84af5f7b82973c9a839e6d/
+	previousBlockHeaderHash, err := NewHashFromString(
+		"000000000066450030efdf72f233ed2495547a32295deea1e2f3a16b1e50a3a5",
+		ReversedByteOrder,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merkleRootHash, err := NewHashFromString(
+		"1251774996b446f85462d5433f7a3e384ac1569072e617ab31e86da31c247de2",
+		ReversedByteOrder,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blockHeader := BlockHeader{
+		Version:                 536870916,
+		PreviousBlockHeaderHash: previousBlockHeaderHash,
+		MerkleRootHash:          merkleRootHash,
+		Time:                    1641914003,
+		Bits:                    436256810,
+		Nonce:                   778087099,
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"block header hash",
+		"000000000000002af10911b8db32ed34dc6ea6515f84af5f7b82973c9a839e6d",
+		blockHeader.Hash().Hex(ReversedByteOrder),
+	)
+}
+
 func TestBlockHeaderTarget(t *testing.T) {
 	// Test data comes from a Bitcoin testnet block:
 	// https://live.blockcypher.com/btc-testnet/block/000000000000002af10911b8db32ed34dc6ea6515f84af5f7b82973c9a839e6d/
@@ -226,3 +262,55 @@ func TestBlockHeaderDifficulty_LowestDifficulty(t *testing.T) {
 		actualDifficulty,
 	)
 }
+
+func TestBlockHeaderIsMinDifficulty(t *testing.T) {
+	var tests = map[string]struct {
+		bits     uint32
+		network  Network
+		expected bool
+	}{
+		"lowest difficulty on mainnet": {
+			bits:     0x1d00ffff,
+			network:  Mainnet,
+			expected: false,
+		},
+		"lowest difficulty on testnet": {
+			bits:     0x1d00ffff,
+			network:  Testnet,
+			expected: true,
+		},
+		"lowest difficulty on testnet4": {
+			bits:     0x1d00ffff,
+			network:  Testnet4,
+			expected: true,
+		},
+		"epoch difficulty on testnet4": {
+			bits:     0x1a00ffff,
+			network:  Testnet4,
+			expected: false,
+		},
+		"lowest difficulty on regtest": {
+			bits:     0x207fffff,
+			network:  Regtest,
+			expected: true,
+		},
+		"lowest difficulty on unknown network": {
+			bits:     0x1d00ffff,
+			network:  Unknown,
+			expected: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			blockHeader := BlockHeader{Bits: test.bits}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"min difficulty",
+				test.expected,
+				blockHeader.IsMinDifficulty(test.network),
+			)
+		})
+	}
+}
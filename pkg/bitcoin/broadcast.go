@@ -0,0 +1,73 @@
+package bitcoin
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// BroadcastResult is the result of broadcasting a transaction to a single
+// backend.
+type BroadcastResult struct {
+	// Backend identifies the backend the transaction was broadcast to.
+	Backend string
+	// Err is the error returned by the backend. It is nil if the backend
+	// accepted the transaction.
+	Err error
+}
+
+// Accepted returns true if the backend accepted the transaction.
+func (br *BroadcastResult) Accepted() bool {
+	return br.Err == nil
+}
+
+// MultiBroadcaster is an optional interface implemented by Chain
+// implementations backed by multiple backends.
+type MultiBroadcaster interface {
+	// BroadcastAll broadcasts the given transaction to all backends
+	// concurrently and returns the result of each backend.
+	BroadcastAll(transaction *Transaction) []*BroadcastResult
+}
+
+// BroadcastAll broadcasts the given transaction to all backends of the
+// given chain concurrently if the chain implements MultiBroadcaster.
+// Otherwise, the transaction is broadcast using the chain's
+// BroadcastTransaction. The result of each backend is returned. An error is
+// returned only if no backend accepted the transaction.
+func BroadcastAll(
+	chain Chain,
+	transaction *Transaction,
+) ([]*BroadcastResult, error) {
+	var results []*BroadcastResult
+	if broadcaster, ok := chain.(MultiBroadcaster); ok {
+		results = broadcaster.BroadcastAll(transaction)
+	} else {
+		results = []*BroadcastResult{
+			{
+				Backend: "default",
+				Err:     chain.BroadcastTransaction(transaction),
+			},
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no backends to broadcast the transaction to")
+	}
+
+	var errors error
+	for _, result := range results {
+		if result.Accepted() {
+			return results, nil
+		}
+
+		errors = multierror.Append(
+			errors,
+			fmt.Errorf("backend [%s]: [%w]", result.Backend, result.Err),
+		)
+	}
+
+	return results, fmt.Errorf(
+		"no backend accepted the transaction: [%w]",
+		errors,
+	)
+}
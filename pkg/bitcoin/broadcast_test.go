@@ -0,0 +1,99 @@
+package bitcoin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestBroadcastAll(t *testing.T) {
+	var tests = map[string]struct {
+		chain            Chain
+		expectedAccepted []bool
+		expectedError    bool
+	}{
+		"single backend accepting": {
+			chain:            &broadcastingChain{},
+			expectedAccepted: []bool{true},
+		},
+		"single backend rejecting": {
+			chain:            &broadcastingChain{err: fmt.Errorf("rejected")},
+			expectedAccepted: []bool{false},
+			expectedError:    true,
+		},
+		"multiple backends with one accepting": {
+			chain: &multiBroadcastingChain{
+				errs: []error{fmt.Errorf("rejected"), nil},
+			},
+			expectedAccepted: []bool{false, true},
+		},
+		"multiple backends with none accepting": {
+			chain: &multiBroadcastingChain{
+				errs: []error{fmt.Errorf("rejected"), fmt.Errorf("rejected")},
+			},
+			expectedAccepted: []bool{false, false},
+			expectedError:    true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			results, err := BroadcastAll(test.chain, &Transaction{})
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error",
+				test.expectedError,
+				err != nil,
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"results count",
+				len(test.expectedAccepted),
+				len(results),
+			)
+
+			for i, result := range results {
+				testutils.AssertBoolsEqual(
+					t,
+					fmt.Sprintf("result %d accepted", i),
+					test.expectedAccepted[i],
+					result.Accepted(),
+				)
+			}
+		})
+	}
+}
+
+type broadcastingChain struct {
+	Chain
+
+	err error
+}
+
+func (bc *broadcastingChain) BroadcastTransaction(
+	transaction *Transaction,
+) error {
+	return bc.err
+}
+
+type multiBroadcastingChain struct {
+	Chain
+
+	errs []error
+}
+
+func (mbc *multiBroadcastingChain) BroadcastAll(
+	transaction *Transaction,
+) []*BroadcastResult {
+	results := make([]*BroadcastResult, len(mbc.errs))
+	for i, err := range mbc.errs {
+		results[i] = &BroadcastResult{
+			Backend: fmt.Sprintf("%d", i),
+			Err:     err,
+		}
+	}
+	return results
+}
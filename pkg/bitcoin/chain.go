@@ -1,5 +1,10 @@
 package bitcoin
 
+import (
+	"context"
+	"fmt"
+)
+
 // Chain defines an interface meant to be used for interaction with the
 // Bitcoin chain.
 type Chain interface {
@@ -52,6 +57,19 @@ type Chain interface {
 		limit int,
 	) ([]*Transaction, error)
 
+	// GetTransactionsPageForPublicKeyHash gets a page of confirmed
+	// transactions that pays the given public key hash using either a P2PKH
+	// or P2WPKH script. Pages are walked from the latest transaction
+	// backwards, using the cursor returned along with the previous page.
+	// Transactions can be filtered by the number of their confirmations.
+	// See TransactionHistoryQuery for details. This way, long transaction
+	// histories can be walked deterministically without fetching them
+	// at once.
+	GetTransactionsPageForPublicKeyHash(
+		publicKeyHash [20]byte,
+		query *TransactionHistoryQuery,
+	) (*TransactionHistoryPage, error)
+
 	// GetTxHashesForPublicKeyHash gets hashes of confirmed transactions that pays
 	// the given public key hash using either a P2PKH or P2WPKH script. The returned
 	// transactions hashes are ordered by block height in the ascending order, i.e.
@@ -96,3 +114,114 @@ type Chain interface {
 	// block height.
 	GetCoinbaseTxHash(blockHeight uint) (Hash, error)
 }
+
+// BlockHeadersGetter is an optional interface implemented by Chain
+// implementations able to fetch multiple consecutive block headers with
+// a single request.
+type BlockHeadersGetter interface {
+	// GetBlockHeaders gets block headers for the given number of consecutive
+	// blocks starting at the given block height. The returned headers are
+	// ordered by block height in the ascending order. If any of the blocks was
+	// not found on the chain, this function returns an error.
+	GetBlockHeaders(startBlockHeight uint, count uint) ([]*BlockHeader, error)
+}
+
+// MempoolChecker is an optional interface implemented by components able to
+// tell whether a transaction is in the mempool.
+type MempoolChecker interface {
+	// IsTransactionInMempool returns true if the transaction with the given
+	// hash is in the mempool, i.e. it is known but not yet confirmed.
+	IsTransactionInMempool(transactionHash Hash) (bool, error)
+}
+
+// mempoolCheckingChain is a Chain that implements the MempoolChecker
+// interface using a separate mempool checker.
+type mempoolCheckingChain struct {
+	Chain
+
+	mempoolChecker MempoolChecker
+}
+
+// NewMempoolCheckingChain returns a Chain delegating all calls to the given
+// chain and implementing the MempoolChecker interface using the given
+// mempool checker.
+func NewMempoolCheckingChain(chain Chain, mempoolChecker MempoolChecker) Chain {
+	return &mempoolCheckingChain{
+		Chain:          chain,
+		mempoolChecker: mempoolChecker,
+	}
+}
+
+func (mcc *mempoolCheckingChain) IsTransactionInMempool(
+	transactionHash Hash,
+) (bool, error) {
+	return mcc.mempoolChecker.IsTransactionInMempool(transactionHash)
+}
+
+func (mcc *mempoolCheckingChain) GetBlockHeaders(
+	startBlockHeight uint,
+	count uint,
+) ([]*BlockHeader, error) {
+	return GetBlockHeaders(mcc.Chain, startBlockHeight, count)
+}
+
+func (mcc *mempoolCheckingChain) WatchTransaction(
+	ctx context.Context,
+	transaction *Transaction,
+) (<-chan uint, error) {
+	return WatchTransaction(ctx, mcc.Chain, transaction)
+}
+
+func (mcc *mempoolCheckingChain) BroadcastAll(
+	transaction *Transaction,
+) []*BroadcastResult {
+	results, _ := BroadcastAll(mcc.Chain, transaction)
+	return results
+}
+
+func (mcc *mempoolCheckingChain) WatchReorgs(
+	ctx context.Context,
+) <-chan *ReorgEvent {
+	return WatchReorgs(ctx, mcc.Chain)
+}
+
+// GetBlockHeaders gets block headers for the given number of consecutive
+// blocks starting at the given block height. The headers are fetched with
+// a single call if the given chain implements BlockHeadersGetter. Otherwise,
+// each header is fetched separately.
+func GetBlockHeaders(
+	chain Chain,
+	startBlockHeight uint,
+	count uint,
+) ([]*BlockHeader, error) {
+	if headersGetter, ok := chain.(BlockHeadersGetter); ok {
+		blockHeaders, err := headersGetter.GetBlockHeaders(
+			startBlockHeight,
+			count,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if uint(len(blockHeaders)) != count {
+			return nil, fmt.Errorf(
+				"unexpected number of block headers; expected [%v], got [%v]",
+				count,
+				len(blockHeaders),
+			)
+		}
+
+		return blockHeaders, nil
+	}
+
+	blockHeaders := make([]*BlockHeader, count)
+	for i := range blockHeaders {
+		blockHeader, err := chain.GetBlockHeader(startBlockHeight + uint(i))
+		if err != nil {
+			return nil, err
+		}
+		blockHeaders[i] = blockHeader
+	}
+
+	return blockHeaders, nil
+}
@@ -120,6 +120,13 @@ func (lc *localChain) GetTransactionsForPublicKeyHash(
 	panic("not implemented")
 }
 
+func (lc *localChain) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *TransactionHistoryQuery,
+) (*TransactionHistoryPage, error) {
+	panic("not implemented")
+}
+
 func (lc *localChain) GetTxHashesForPublicKeyHash(
 	publicKeyHash [20]byte,
 ) ([]Hash, error) {
@@ -3,6 +3,7 @@ package electrum
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/btcsuite/btcd/v2/wire"
 	"github.com/checksum0/go-electrum/electrum"
@@ -18,6 +19,44 @@ func convertBlockHeader(electrumResult *electrum.GetBlockHeaderResult) (*bitcoin
 		return nil, err
 	}
 
+	return deserializeBlockHeader(headerBytes)
+}
+
+// convertBlockHeaders transforms a chunk of concatenated block headers returned
+// from Electrum protocol to the format expected by the bitcoin.Chain interface.
+func convertBlockHeaders(
+	electrumResult *electrum.GetBlockHeadersResult,
+) ([]*bitcoin.BlockHeader, error) {
+	headersBytes, err := hex.DecodeString(electrumResult.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(headersBytes) != int(electrumResult.Count)*blockHeaderLength {
+		return nil, fmt.Errorf(
+			"unexpected length of [%d] block headers: [%d]",
+			electrumResult.Count,
+			len(headersBytes),
+		)
+	}
+
+	result := make([]*bitcoin.BlockHeader, electrumResult.Count)
+	for i := range result {
+		result[i], err = deserializeBlockHeader(
+			headersBytes[i*blockHeaderLength : (i+1)*blockHeaderLength],
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// blockHeaderLength is the length of a serialized block header in bytes.
+const blockHeaderLength = 80
+
+func deserializeBlockHeader(headerBytes []byte) (*bitcoin.BlockHeader, error) {
 	buf := bytes.NewBuffer(headerBytes)
 
 	var b wire.BlockHeader
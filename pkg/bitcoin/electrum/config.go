@@ -18,6 +18,9 @@ const (
 	// DefaultKeepAliveInterval is a default interval used for Electrum server
 	// connection keep alive requests.
 	DefaultKeepAliveInterval = 5 * time.Minute
+	// DefaultConnectionPoolSize is a default number of connections maintained
+	// with the Electrum server.
+	DefaultConnectionPoolSize = 3
 )
 
 // Config holds configurable properties.
@@ -36,4 +39,13 @@ type Config struct {
 	// An Electrum server may disconnect clients that have not sent any requests
 	// for roughly 10 minutes.
 	KeepAliveInterval time.Duration
+	// Number of connections maintained with the Electrum server. Requests
+	// sent over the same connection are serialized so the pool size determines
+	// how many requests can be executed concurrently.
+	ConnectionPoolSize int
+	// URL of the SOCKS5 proxy the connections are routed through, e.g.
+	// `socks5://127.0.0.1:9050`. Host names, including .onion addresses, are
+	// resolved by the proxy. Only the `tcp` and `ssl` schemes are supported
+	// with a proxy. If empty, the connections are established directly.
+	ProxyURL string
 }
@@ -3,12 +3,15 @@ package electrum
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/hex"
 	"fmt"
 	"math"
+	"net/url"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/checksum0/go-electrum/electrum"
@@ -18,6 +21,7 @@ import (
 
 	"github.com/keep-network/keep-common/pkg/wrappers"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/proxy"
 	"github.com/keep-network/keep-core/pkg/internal/byteutils"
 )
 
@@ -26,12 +30,35 @@ var (
 	logger                    = log.Logger("keep-electrum")
 )
 
-// Connection is a handle for interactions with Electrum server.
+// Connection is a handle for interactions with Electrum server. It maintains
+// a pool of connections to the server so independent requests are executed
+// concurrently.
 type Connection struct {
-	parentCtx   context.Context
-	client      *electrum.Client
-	clientMutex *sync.Mutex
-	config      Config
+	parentCtx context.Context
+	pool      []*pooledClient
+	// nextPooledClient is the index of the pooled client tried first by the
+	// next request. It rotates so the load is spread across the pool.
+	nextPooledClient uint64
+	config           Config
+
+	// dialURL is the URL clients connect to. It differs from the configured
+	// URL if the connections are routed through a proxy.
+	dialURL string
+	// tlsConfig is the TLS config of clients connecting using the `ssl`
+	// scheme. It is nil if the default config is used.
+	tlsConfig *tls.Config
+
+	// subscriptions is the hub of push notifications used to watch
+	// transactions. It is created lazily on the first watch request.
+	subscriptionsOnce sync.Once
+	subscriptions     *subscriptionHub
+}
+
+// pooledClient is a single connection of the connection pool. Requests sent
+// over the same connection are serialized.
+type pooledClient struct {
+	mutex  sync.Mutex
+	client *electrum.Client
 }
 
 // Connect initializes handle with provided Config.
@@ -51,11 +78,25 @@ func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
 	if config.KeepAliveInterval == 0 {
 		config.KeepAliveInterval = DefaultKeepAliveInterval
 	}
+	if config.ConnectionPoolSize == 0 {
+		config.ConnectionPoolSize = DefaultConnectionPoolSize
+	}
 
 	c := &Connection{
-		parentCtx:   parentCtx,
-		config:      config,
-		clientMutex: &sync.Mutex{},
+		parentCtx: parentCtx,
+		pool:      make([]*pooledClient, config.ConnectionPoolSize),
+		config:    config,
+		dialURL:   config.URL,
+	}
+
+	if config.ProxyURL != "" {
+		if err := c.setUpProxy(); err != nil {
+			return nil, fmt.Errorf("failed to set up proxy: [%w]", err)
+		}
+	}
+
+	for i := range c.pool {
+		c.pool[i] = &pooledClient{}
 	}
 
 	if err := c.electrumConnect(); err != nil {
@@ -66,11 +107,9 @@ func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
 		return nil, fmt.Errorf("failed to verify electrum server: [%w]", err)
 	}
 
-	// Keep the connection alive and check the connection health.
+	// Keep the connections alive and check the connections health.
 	go c.keepAlive()
 
-	// TODO: Add reconnects on lost connection.
-
 	return c, nil
 }
 
@@ -311,6 +350,58 @@ func (c *Connection) GetBlockHeader(
 	return blockHeader, nil
 }
 
+// GetBlockHeaders gets block headers for the given number of consecutive
+// blocks starting at the given block height. The headers are fetched in as
+// few requests as the Electrum server allows. If any of the blocks was not
+// found on the chain, this function returns an error.
+func (c *Connection) GetBlockHeaders(
+	startBlockHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	blockHeaders := make([]*bitcoin.BlockHeader, 0, count)
+
+	for uint(len(blockHeaders)) < count {
+		chunkStartBlockHeight := startBlockHeight + uint(len(blockHeaders))
+		chunkCount := count - uint(len(blockHeaders))
+
+		getBlockHeadersResult, err := requestWithRetry(
+			c,
+			func(
+				ctx context.Context,
+				client *electrum.Client,
+			) (*electrum.GetBlockHeadersResult, error) {
+				return client.GetBlockHeaders(
+					ctx,
+					uint32(chunkStartBlockHeight),
+					uint32(chunkCount),
+				)
+			},
+			"GetBlockHeaders",
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block headers: [%w]", err)
+		}
+
+		// The server returns fewer headers than requested if the requested
+		// count exceeds its limit or the chain tip.
+		if getBlockHeadersResult.Count == 0 {
+			return nil, fmt.Errorf(
+				"block headers starting at block [%d] not found",
+				chunkStartBlockHeight,
+			)
+		}
+
+		chunk, err := convertBlockHeaders(getBlockHeadersResult)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert block headers: %w", err)
+		}
+
+		blockHeaders = append(blockHeaders, chunk...)
+	}
+
+	return blockHeaders, nil
+}
+
 // GetTransactionMerkleProof gets the Merkle proof for a given transaction.
 // The transaction's hash and the block the transaction was included in the
 // blockchain need to be provided.
@@ -389,6 +480,79 @@ func (c *Connection) GetTransactionsForPublicKeyHash(
 func (c *Connection) GetTxHashesForPublicKeyHash(
 	publicKeyHash [20]byte,
 ) ([]bitcoin.Hash, error) {
+	items, err := c.getConfirmedPublicKeyHashHistory(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	txHashes := make([]bitcoin.Hash, len(items))
+	for i, item := range items {
+		txHashes[i] = item.txHash
+	}
+
+	return txHashes, nil
+}
+
+// GetTransactionsPageForPublicKeyHash gets a page of confirmed transactions
+// that pays the given public key hash using either a P2PKH or P2WPKH script.
+// Pages are walked from the latest transaction backwards, using the cursor
+// returned along with the previous page. Transactions can be filtered by the
+// number of their confirmations. Only transactions of the returned page are
+// fetched.
+func (c *Connection) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	items, err := c.getConfirmedPublicKeyHashHistory(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlockHeight, err := c.GetLatestBlockHeight()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get latest block height: [%v]", err)
+	}
+
+	history := make([]*bitcoin.TransactionHistoryItem, len(items))
+	for i, item := range items {
+		history[i] = &bitcoin.TransactionHistoryItem{
+			TransactionHash: item.txHash,
+			BlockHeight:     uint(item.blockHeight),
+		}
+	}
+
+	selected, nextCursor, err := bitcoin.SelectTransactionHistoryPage(
+		history,
+		latestBlockHeight,
+		query,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select history page: [%v]", err)
+	}
+
+	transactions := make([]*bitcoin.Transaction, len(selected))
+	for i, item := range selected {
+		transaction, err := c.GetTransaction(item.TransactionHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get transaction: [%v]", err)
+		}
+
+		transactions[i] = transaction
+	}
+
+	return &bitcoin.TransactionHistoryPage{
+		Transactions: transactions,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+// getConfirmedPublicKeyHashHistory returns a history of confirmed
+// transactions that pays the given public key hash using either a P2PKH or
+// P2WPKH script. The returned list is sorted by the block height in the
+// ascending order.
+func (c *Connection) getConfirmedPublicKeyHashHistory(
+	publicKeyHash [20]byte,
+) ([]*scriptHistoryItem, error) {
 	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -434,12 +598,7 @@ func (c *Connection) GetTxHashesForPublicKeyHash(
 		},
 	)
 
-	txHashes := make([]bitcoin.Hash, len(items))
-	for i, item := range items {
-		txHashes[i] = item.txHash
-	}
-
-	return txHashes, nil
+	return items, nil
 }
 
 type scriptHistoryItem struct {
@@ -949,23 +1108,102 @@ func convertBtcKbToSatVByte(btcPerKbFee float32) int64 {
 	return int64(math.Round(satPerVByte))
 }
 
+// electrumConnect establishes all connections of the connection pool.
+// Connections are established concurrently.
 func (c *Connection) electrumConnect() error {
-	var client *electrum.Client
-	var err error
+	logger.Debugf(
+		"establishing [%d] connections to electrum server...",
+		len(c.pool),
+	)
 
-	logger.Debug("establishing connection to electrum server...")
-	client, err = connectWithRetry(
-		c,
-		func(ctx context.Context) (*electrum.Client, error) {
-			return electrum.NewClient(ctx, c.config.URL, nil)
-		},
+	errs := make([]error, len(c.pool))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(c.pool))
+
+	for i, pc := range c.pool {
+		go func(i int, pc *pooledClient) {
+			defer wg.Done()
+
+			pc.mutex.Lock()
+			defer pc.mutex.Unlock()
+
+			errs[i] = c.connectPooledClient(pc)
+		}(i, pc)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setUpProxy sets up forwarding of the connections to the Electrum server
+// through the configured proxy. The go-electrum client cannot use a custom
+// dialer so it connects to a local forwarder instead.
+func (c *Connection) setUpProxy() error {
+	serverURL, err := url.Parse(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("cannot parse server URL: [%w]", err)
+	}
+
+	if serverURL.Scheme != "tcp" && serverURL.Scheme != "ssl" {
+		return fmt.Errorf(
+			"unsupported scheme [%s]; only tcp and ssl are supported "+
+				"with a proxy",
+			serverURL.Scheme,
+		)
+	}
+
+	localAddress, err := proxy.Forward(
+		c.parentCtx,
+		c.config.ProxyURL,
+		serverURL.Host,
+	)
+	if err != nil {
+		return err
+	}
+
+	if serverURL.Scheme == "ssl" {
+		// The server certificate must be verified against the server's
+		// host name instead of the local forwarder's address.
+		c.tlsConfig = &tls.Config{ServerName: serverURL.Hostname()}
+	}
+
+	c.dialURL = fmt.Sprintf("%s://%s", serverURL.Scheme, localAddress)
+
+	logger.Infof(
+		"connections to electrum server [%s] are routed through proxy",
+		serverURL.Host,
 	)
 
-	if err == nil {
-		c.client = client
+	return nil
+}
+
+// newClient connects a new client to the Electrum server.
+func (c *Connection) newClient(ctx context.Context) (*electrum.Client, error) {
+	return electrum.NewClient(ctx, c.dialURL, c.tlsConfig)
+}
+
+// connectPooledClient establishes a connection of the given pooled client.
+// Must be called with the pooled client's mutex held.
+func (c *Connection) connectPooledClient(pc *pooledClient) error {
+	client, err := connectWithRetry(
+		c,
+		c.newClient,
+	)
+	if err != nil {
+		return err
 	}
 
-	return err
+	pc.client = client
+
+	return nil
 }
 
 func (c *Connection) verifyServer() error {
@@ -1014,26 +1252,37 @@ func (c *Connection) keepAlive() {
 	for {
 		select {
 		case <-ticker.C:
-			_, err := requestWithRetry(
-				c,
-				func(ctx context.Context, client *electrum.Client) (interface{}, error) {
-					return nil, client.Ping(ctx)
-				},
-				"Ping",
-			)
-			if err != nil {
-				logger.Errorf(
-					"failed to ping the electrum server; "+
-						"please verify health of the electrum server: [%v]",
-					err,
+			// Ping every pooled connection as the server may disconnect
+			// each idle connection separately.
+			for _, pc := range c.pool {
+				_, err := pooledRequestWithRetry(
+					c,
+					func() *pooledClient {
+						pc.mutex.Lock()
+						return pc
+					},
+					func(ctx context.Context, client *electrum.Client) (interface{}, error) {
+						return nil, client.Ping(ctx)
+					},
+					"Ping",
 				)
-			} else {
-				// Adjust ticker starting at the time of the latest successful ping.
-				ticker = time.NewTicker(c.config.KeepAliveInterval)
+				if err != nil {
+					logger.Errorf(
+						"failed to ping the electrum server; "+
+							"please verify health of the electrum server: [%v]",
+						err,
+					)
+				}
 			}
 		case <-c.parentCtx.Done():
 			ticker.Stop()
-			c.client.Shutdown()
+			for _, pc := range c.pool {
+				pc.mutex.Lock()
+				if pc.client != nil {
+					pc.client.Shutdown()
+				}
+				pc.mutex.Unlock()
+			}
 			return
 		}
 	}
@@ -1070,6 +1319,23 @@ func requestWithRetry[K interface{}](
 	c *Connection,
 	requestFn func(ctx context.Context, client *electrum.Client) (K, error),
 	requestName string,
+) (K, error) {
+	return pooledRequestWithRetry(
+		c,
+		c.acquirePooledClient,
+		requestFn,
+		requestName,
+	)
+}
+
+// pooledRequestWithRetry executes the given request with retries. Before each
+// attempt, a pooled client is acquired using the given function. The acquired
+// client is locked by the function and is released once the attempt completes.
+func pooledRequestWithRetry[K interface{}](
+	c *Connection,
+	acquireFn func() *pooledClient,
+	requestFn func(ctx context.Context, client *electrum.Client) (K, error),
+	requestName string,
 ) (K, error) {
 	startTime := time.Now()
 	logger.Debugf("starting [%s] request to Electrum server", requestName)
@@ -1080,17 +1346,17 @@ func requestWithRetry[K interface{}](
 		c.parentCtx,
 		c.config.RequestRetryTimeout,
 		func(ctx context.Context) error {
-			if err := c.reconnectIfShutdown(); err != nil {
+			pc := acquireFn()
+			defer pc.mutex.Unlock()
+
+			if err := c.reconnectIfShutdown(pc); err != nil {
 				return err
 			}
 
 			requestCtx, requestCancel := context.WithTimeout(ctx, c.config.RequestTimeout)
 			defer requestCancel()
 
-			c.clientMutex.Lock()
-			r, err := requestFn(requestCtx, c.client)
-			c.clientMutex.Unlock()
-
+			r, err := requestFn(requestCtx, pc.client)
 			if err != nil {
 				return fmt.Errorf("request failed: [%w]", err)
 			}
@@ -1115,14 +1381,32 @@ func requestWithRetry[K interface{}](
 	return result, err
 }
 
-func (c *Connection) reconnectIfShutdown() error {
-	c.clientMutex.Lock()
-	defer c.clientMutex.Unlock()
+// acquirePooledClient acquires a pooled client for a request. An idle client
+// is preferred. If all clients are busy, the function waits for the client
+// determined by rotation. The returned client is locked and must be released
+// by unlocking its mutex.
+func (c *Connection) acquirePooledClient() *pooledClient {
+	start := atomic.AddUint64(&c.nextPooledClient, 1)
+
+	for i := range c.pool {
+		pc := c.pool[(start+uint64(i))%uint64(len(c.pool))]
+		if pc.mutex.TryLock() {
+			return pc
+		}
+	}
+
+	pc := c.pool[start%uint64(len(c.pool))]
+	pc.mutex.Lock()
+
+	return pc
+}
 
-	isClientShutdown := c.client.IsShutdown()
-	if isClientShutdown {
+// reconnectIfShutdown reconnects the given pooled client if its connection
+// is down. Must be called with the pooled client's mutex held.
+func (c *Connection) reconnectIfShutdown(pc *pooledClient) error {
+	if pc.client == nil || pc.client.IsShutdown() {
 		logger.Warn("connection to electrum server is down; reconnecting...")
-		err := c.electrumConnect()
+		err := c.connectPooledClient(pc)
 		if err != nil {
 			return fmt.Errorf("failed to reconnect to electrum server: [%w]", err)
 		}
@@ -1,9 +1,14 @@
 package electrum
 
 import (
+	"encoding/hex"
+	"reflect"
 	"testing"
 
+	"github.com/checksum0/go-electrum/electrum"
+
 	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 )
 
 func TestConvertBtcKbToSatVByte(t *testing.T) {
@@ -62,3 +67,147 @@ func TestConvertBtcKbToSatVByte(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertBlockHeaders(t *testing.T) {
+	blockHeaders := []*bitcoin.BlockHeader{
+		{
+			Version:                 536870916,
+			PreviousBlockHeaderHash: bitcoin.Hash{1},
+			MerkleRootHash:          bitcoin.Hash{2},
+			Time:                    1641914003,
+			Bits:                    436256810,
+			Nonce:                   778087099,
+		},
+		{
+			Version:                 536870912,
+			PreviousBlockHeaderHash: bitcoin.Hash{3},
+			MerkleRootHash:          bitcoin.Hash{4},
+			Time:                    1641914603,
+			Bits:                    436256810,
+			Nonce:                   12345,
+		},
+	}
+
+	var headersHex string
+	for _, blockHeader := range blockHeaders {
+		serializedBlockHeader := blockHeader.Serialize()
+		headersHex += hex.EncodeToString(serializedBlockHeader[:])
+	}
+
+	actualBlockHeaders, err := convertBlockHeaders(
+		&electrum.GetBlockHeadersResult{
+			Count:   uint32(len(blockHeaders)),
+			Headers: headersHex,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(blockHeaders, actualBlockHeaders) {
+		t.Errorf(
+			"unexpected block headers\nexpected: %v\nactual:   %v",
+			blockHeaders,
+			actualBlockHeaders,
+		)
+	}
+
+	_, err = convertBlockHeaders(
+		&electrum.GetBlockHeadersResult{
+			Count:   uint32(len(blockHeaders) + 1),
+			Headers: headersHex,
+		},
+	)
+	if err == nil {
+		t.Errorf("expected error for mismatched headers count")
+	}
+}
+
+func TestConnection_AcquirePooledClient(t *testing.T) {
+	c := &Connection{
+		pool: []*pooledClient{{}, {}, {}},
+	}
+
+	acquired := make(map[*pooledClient]bool)
+	for range c.pool {
+		acquired[c.acquirePooledClient()] = true
+	}
+
+	// Idle clients are preferred so each acquisition should return
+	// a different client.
+	testutils.AssertIntsEqual(
+		t,
+		"distinct acquired clients",
+		len(c.pool),
+		len(acquired),
+	)
+
+	released := c.pool[1]
+	released.mutex.Unlock()
+
+	if acquiredClient := c.acquirePooledClient(); acquiredClient != released {
+		t.Errorf("expected the only idle client to be acquired")
+	}
+}
+
+func TestComputeScriptHash(t *testing.T) {
+	// Example from the Electrum protocol documentation: P2PKH script of the
+	// 1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa address.
+	script, err := hex.DecodeString(
+		"76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"script hash",
+		"8b01df4e368ea28f8dc0423bcf7a4923e3a12d307c875e47a0cfbf90b5c39161",
+		computeScriptHash(script),
+	)
+}
+
+func TestSubscriptionHub_Notify(t *testing.T) {
+	hub := &subscriptionHub{listeners: make(map[*hubListener]struct{})}
+
+	newListener := func(scriptHash string) *hubListener {
+		listener := &hubListener{
+			scriptHash:    scriptHash,
+			notifications: make(chan struct{}, 1),
+		}
+		hub.listeners[listener] = struct{}{}
+		return listener
+	}
+
+	listener1 := newListener("01")
+	listener2 := newListener("02")
+
+	isNotified := func(listener *hubListener) bool {
+		select {
+		case <-listener.notifications:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// Notify the first listener twice; notifications should be coalesced.
+	for i := 0; i < 2; i++ {
+		hub.notify(func(listener *hubListener) bool {
+			return listener.scriptHash == "01"
+		})
+	}
+
+	testutils.AssertBoolsEqual(t, "listener 1 notified", true, isNotified(listener1))
+	testutils.AssertBoolsEqual(t, "listener 1 notified again", false, isNotified(listener1))
+	testutils.AssertBoolsEqual(t, "listener 2 notified", false, isNotified(listener2))
+
+	// Notify all listeners, e.g. about a new block.
+	hub.notify(func(listener *hubListener) bool {
+		return true
+	})
+
+	testutils.AssertBoolsEqual(t, "listener 1 notified", true, isNotified(listener1))
+	testutils.AssertBoolsEqual(t, "listener 2 notified", true, isNotified(listener2))
+}
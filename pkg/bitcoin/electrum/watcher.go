@@ -0,0 +1,419 @@
+package electrum
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/checksum0/go-electrum/electrum"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/byteutils"
+)
+
+// watchPollInterval is the interval of fallback checks of watched
+// transactions. The checks make sure no state change is missed, for example,
+// when notifications are lost while the subscription connection is being
+// re-established.
+const watchPollInterval = 1 * time.Minute
+
+// subscriptionHub maintains a dedicated connection to the Electrum server,
+// subscribed to block headers and script hashes of watched transactions.
+// Subscriptions are bound to the connection so they cannot be served by the
+// connection pool. Notifications are fanned out to registered listeners.
+type subscriptionHub struct {
+	connection *Connection
+
+	// connectMutex guards establishing the subscription connection.
+	connectMutex sync.Mutex
+	// requestMutex serializes requests sent over the subscription
+	// connection.
+	requestMutex sync.Mutex
+
+	mutex                  sync.Mutex
+	client                 *electrum.Client
+	scriptHashSubscription *electrum.ScripthashSubscription
+	listeners              map[*hubListener]struct{}
+}
+
+// hubListener is notified about new blocks and status changes of the script
+// hash it listens to.
+type hubListener struct {
+	scriptHash    string
+	notifications chan struct{}
+}
+
+func newSubscriptionHub(connection *Connection) *subscriptionHub {
+	hub := &subscriptionHub{
+		connection: connection,
+		listeners:  make(map[*hubListener]struct{}),
+	}
+
+	go hub.monitorConnection()
+
+	return hub
+}
+
+// monitorConnection re-establishes the subscription connection along with
+// all subscriptions if the connection is down.
+func (sh *subscriptionHub) monitorConnection() {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sh.reconnectIfShutdown()
+		case <-sh.connection.parentCtx.Done():
+			sh.mutex.Lock()
+			if sh.client != nil {
+				sh.client.Shutdown()
+			}
+			sh.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// reconnectIfShutdown re-establishes the subscription connection if it has
+// been shut down.
+func (sh *subscriptionHub) reconnectIfShutdown() {
+	sh.connectMutex.Lock()
+	defer sh.connectMutex.Unlock()
+
+	sh.mutex.Lock()
+	isDown := sh.client != nil && sh.client.IsShutdown()
+	sh.mutex.Unlock()
+
+	if !isDown {
+		return
+	}
+
+	logger.Warn(
+		"subscription connection to electrum server is down; " +
+			"reconnecting...",
+	)
+
+	if err := sh.connect(); err != nil {
+		logger.Errorf(
+			"failed to reconnect subscription connection: [%v]",
+			err,
+		)
+		return
+	}
+
+	logger.Info("reconnected subscription connection")
+}
+
+// connectIfNeeded establishes the subscription connection if it has not
+// been established yet.
+func (sh *subscriptionHub) connectIfNeeded() error {
+	sh.connectMutex.Lock()
+	defer sh.connectMutex.Unlock()
+
+	sh.mutex.Lock()
+	isConnected := sh.client != nil
+	sh.mutex.Unlock()
+
+	if isConnected {
+		return nil
+	}
+
+	return sh.connect()
+}
+
+// connect establishes the subscription connection, subscribes to block
+// headers and to script hashes of all registered listeners.
+func (sh *subscriptionHub) connect() error {
+	client, err := connectWithRetry(sh.connection, sh.connection.newClient)
+	if err != nil {
+		return fmt.Errorf("cannot connect: [%w]", err)
+	}
+
+	headersChan, err := subscriptionRequest(
+		sh,
+		func(ctx context.Context) (<-chan *electrum.SubscribeHeadersResult, error) {
+			return client.SubscribeHeaders(ctx)
+		},
+	)
+	if err != nil {
+		client.Shutdown()
+		return fmt.Errorf("cannot subscribe to headers: [%w]", err)
+	}
+
+	scriptHashSubscription, scriptHashChan := client.SubscribeScripthash()
+
+	go func() {
+		for range headersChan {
+			sh.notify(func(listener *hubListener) bool {
+				return true
+			})
+		}
+	}()
+
+	go func() {
+		for notification := range scriptHashChan {
+			scriptHash := notification.Params[0]
+			sh.notify(func(listener *hubListener) bool {
+				return listener.scriptHash == scriptHash
+			})
+		}
+	}()
+
+	sh.mutex.Lock()
+	sh.client = client
+	sh.scriptHashSubscription = scriptHashSubscription
+	scriptHashes := make(map[string]bool)
+	for listener := range sh.listeners {
+		scriptHashes[listener.scriptHash] = true
+	}
+	sh.mutex.Unlock()
+
+	for scriptHash := range scriptHashes {
+		if err := sh.subscribeScriptHash(
+			scriptHashSubscription,
+			scriptHash,
+		); err != nil {
+			logger.Errorf(
+				"cannot resubscribe to script hash [%s]: [%v]",
+				scriptHash,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// notify notifies all listeners matching the given filter. Notifications
+// are coalesced, i.e. a listener that has not consumed the previous
+// notification yet is not notified again.
+func (sh *subscriptionHub) notify(filter func(listener *hubListener) bool) {
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	for listener := range sh.listeners {
+		if !filter(listener) {
+			continue
+		}
+
+		select {
+		case listener.notifications <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// addListener registers a listener of the given script hash and subscribes
+// to the script hash. The subscription connection is established if needed.
+func (sh *subscriptionHub) addListener(scriptHash string) (*hubListener, error) {
+	if err := sh.connectIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	listener := &hubListener{
+		scriptHash:    scriptHash,
+		notifications: make(chan struct{}, 1),
+	}
+
+	sh.mutex.Lock()
+	sh.listeners[listener] = struct{}{}
+	scriptHashSubscription := sh.scriptHashSubscription
+	sh.mutex.Unlock()
+
+	// The subscription must not be done while holding the mutex as the
+	// subscription may push a notification synchronously.
+	if err := sh.subscribeScriptHash(
+		scriptHashSubscription,
+		scriptHash,
+	); err != nil {
+		sh.removeListener(listener)
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// removeListener unregisters the given listener. The script hash is
+// unsubscribed if no other listener listens to it.
+func (sh *subscriptionHub) removeListener(listener *hubListener) {
+	sh.mutex.Lock()
+	delete(sh.listeners, listener)
+	for other := range sh.listeners {
+		if other.scriptHash == listener.scriptHash {
+			sh.mutex.Unlock()
+			return
+		}
+	}
+	scriptHashSubscription := sh.scriptHashSubscription
+	sh.mutex.Unlock()
+
+	// Electrum protocol does not support unsubscribing so the script hash
+	// is only removed from the local filter. The removal must not be done
+	// while holding the mutex as the subscription holds its own lock while
+	// pushing notifications.
+	_ = scriptHashSubscription.Remove(listener.scriptHash)
+}
+
+func (sh *subscriptionHub) subscribeScriptHash(
+	scriptHashSubscription *electrum.ScripthashSubscription,
+	scriptHash string,
+) error {
+	_, err := subscriptionRequest(
+		sh,
+		func(ctx context.Context) (interface{}, error) {
+			return nil, scriptHashSubscription.Add(ctx, scriptHash)
+		},
+	)
+	return err
+}
+
+// subscriptionRequest executes the given request over the subscription
+// connection with a timeout.
+func subscriptionRequest[K interface{}](
+	sh *subscriptionHub,
+	requestFn func(ctx context.Context) (K, error),
+) (K, error) {
+	sh.requestMutex.Lock()
+	defer sh.requestMutex.Unlock()
+
+	ctx, cancelCtx := context.WithTimeout(
+		sh.connection.parentCtx,
+		sh.connection.config.RequestTimeout,
+	)
+	defer cancelCtx()
+
+	return requestFn(ctx)
+}
+
+// WatchTransaction watches the given transaction until the given context
+// is done. The returned channel receives the number of the transaction's
+// confirmations each time it changes, that is, when the transaction
+// appears in the mempool (zero confirmations) and each time it gains
+// a confirmation. The channel is closed once the context is done.
+//
+// The transaction state is checked each time the status of the script hash
+// of the transaction's first output changes or a new block is mined, based
+// on notifications pushed by the Electrum server.
+func (c *Connection) WatchTransaction(
+	ctx context.Context,
+	transaction *bitcoin.Transaction,
+) (<-chan uint, error) {
+	if len(transaction.Outputs) == 0 {
+		return nil, fmt.Errorf("transaction has no outputs")
+	}
+
+	scriptHash := computeScriptHash(transaction.Outputs[0].PublicKeyScript)
+	txID := transaction.Hash().Hex(bitcoin.ReversedByteOrder)
+
+	c.subscriptionsOnce.Do(func() {
+		c.subscriptions = newSubscriptionHub(c)
+	})
+
+	listener, err := c.subscriptions.addListener(scriptHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot subscribe to script hash: [%w]", err)
+	}
+
+	confirmationsChan := make(chan uint, 1)
+
+	go func() {
+		defer close(confirmationsChan)
+		defer c.subscriptions.removeListener(listener)
+
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+
+		lastConfirmations := -1
+
+		for {
+			confirmations, known, err := c.getScriptTransactionConfirmations(
+				scriptHash,
+				txID,
+			)
+			if err != nil {
+				logger.Warnf(
+					"cannot check confirmations of watched transaction [%s]: [%v]",
+					txID,
+					err,
+				)
+			} else if known && int(confirmations) != lastConfirmations {
+				lastConfirmations = int(confirmations)
+
+				select {
+				case confirmationsChan <- confirmations:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-listener.notifications:
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return confirmationsChan, nil
+}
+
+// getScriptTransactionConfirmations gets the number of confirmations of the
+// transaction with the given ID, based on the history of the given script
+// hash. Returns false if the transaction is not in the history.
+func (c *Connection) getScriptTransactionConfirmations(
+	scriptHash string,
+	txID string,
+) (uint, bool, error) {
+	history, err := requestWithRetry(
+		c,
+		func(
+			ctx context.Context,
+			client *electrum.Client,
+		) ([]*electrum.GetMempoolResult, error) {
+			return client.GetHistory(ctx, scriptHash)
+		},
+		"GetHistory",
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get history: [%w]", err)
+	}
+
+	for _, transaction := range history {
+		if transaction.Hash != txID {
+			continue
+		}
+
+		// Mempool transactions have the height of `0` or `-1`.
+		if transaction.Height <= 0 {
+			return 0, true, nil
+		}
+
+		latestBlockHeight, err := c.GetLatestBlockHeight()
+		if err != nil {
+			return 0, false, fmt.Errorf(
+				"failed to get the latest block height: [%w]",
+				err,
+			)
+		}
+
+		if latestBlockHeight < uint(transaction.Height) {
+			return 0, true, nil
+		}
+
+		return latestBlockHeight - uint(transaction.Height) + 1, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// computeScriptHash computes the script hash in the form expected by the
+// Electrum protocol.
+func computeScriptHash(script []byte) string {
+	scriptHash := sha256.Sum256(script)
+	return hex.EncodeToString(byteutils.Reverse(scriptHash[:]))
+}
@@ -1,7 +1,10 @@
 package bitcoin
 
 import (
+	"context"
 	"fmt"
+	"sort"
+
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
@@ -17,6 +20,11 @@ var signaturePlaceholder = make([]byte, 72)
 // Compressed public keys have always 33 bytes.
 var publicKeyPlaceholder = make([]byte, 33)
 
+// Schnorr signatures used to spend taproot outputs have 64 bytes if the
+// default sighash type is used and 65 bytes otherwise. For fee estimation
+// purposes, we take the greatest possible value.
+var schnorrSignaturePlaceholder = make([]byte, 65)
+
 // TransactionSizeEstimator is a component allowing to estimate the size
 // of a Bitcoin transaction of the provided shape, without constructing it.
 type TransactionSizeEstimator struct {
@@ -127,6 +135,34 @@ func (tse *TransactionSizeEstimator) AddScriptHashInputs(
 	return tse
 }
 
+// AddTaprootKeyPathInputs adds the provided count of P2TR inputs spent using
+// the key path to the estimation. If the estimator already errored out during
+// previous actions, this method does nothing.
+func (tse *TransactionSizeEstimator) AddTaprootKeyPathInputs(
+	count int,
+) *TransactionSizeEstimator {
+	if tse.err != nil {
+		return tse
+	}
+
+	// The key path spend witness consists of the signature only.
+	witness := wire.TxWitness{
+		schnorrSignaturePlaceholder,
+	}
+
+	for i := 0; i < count; i++ {
+		tse.internal.AddTxIn(
+			wire.NewTxIn(
+				wire.NewOutPoint((*chainhash.Hash)(&[32]byte{}), 0),
+				nil,
+				witness,
+			),
+		)
+	}
+
+	return tse
+}
+
 // AddPublicKeyHashOutputs adds the provided count of P2WPKH (isWitness is true)
 // or P2PKH (isWitness is false) outputs to the estimation. If the estimator
 // already errored out during previous actions, this method does nothing.
@@ -201,6 +237,31 @@ func (tse *TransactionSizeEstimator) AddScriptHashOutputs(
 	return tse
 }
 
+// AddTaprootOutputs adds the provided count of P2TR outputs to the
+// estimation. If the estimator already errored out during previous actions,
+// this method does nothing.
+func (tse *TransactionSizeEstimator) AddTaprootOutputs(
+	count int,
+) *TransactionSizeEstimator {
+	if tse.err != nil {
+		return tse
+	}
+
+	scriptPlaceholder, err := PayToTaproot([32]byte{})
+	if err != nil {
+		tse.err = err
+		return tse
+	}
+
+	for i := 0; i < count; i++ {
+		tse.internal.AddTxOut(
+			wire.NewTxOut(0, scriptPlaceholder),
+		)
+	}
+
+	return tse
+}
+
 // VirtualSize returns the virtual size of the transaction whose shape was
 // provided to the estimator. If any errors occurred while building the
 // transaction shape, the first error will be returned.
@@ -218,14 +279,93 @@ func (tse *TransactionSizeEstimator) VirtualSize() (int64, error) {
 	return mempool.GetTxVirtualSize(btcutil.NewTx(tse.internal.MsgTx)), nil
 }
 
+// SatPerVByteFeeSource is a source of sat/vbyte fee estimates. Every Chain
+// is a fee source.
+type SatPerVByteFeeSource interface {
+	// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+	// transaction to be confirmed within the given number of blocks.
+	EstimateSatPerVByteFee(blocks uint32) (int64, error)
+}
+
 // TransactionFeeEstimator is a component allowing to estimate the total fee
-// for the given transaction virtual size.
+// for the given transaction virtual size. The estimator combines sat/vbyte
+// fee estimates of all its sources using a median so a single wild estimate
+// does not affect the result. The combined estimate can be additionally
+// bounded.
 type TransactionFeeEstimator struct {
-	chain Chain
+	sources []SatPerVByteFeeSource
+
+	minSatPerVByteFee int64
+	maxSatPerVByteFee int64
 }
 
-func NewTransactionFeeEstimator(chain Chain) *TransactionFeeEstimator {
-	return &TransactionFeeEstimator{chain: chain}
+// NewTransactionFeeEstimator creates a new fee estimator using the given
+// sat/vbyte fee sources.
+func NewTransactionFeeEstimator(
+	sources ...SatPerVByteFeeSource,
+) *TransactionFeeEstimator {
+	return &TransactionFeeEstimator{sources: sources}
+}
+
+// WithBounds sets the bounds of the sat/vbyte fee estimate. Estimates below
+// the minimum or above the maximum are replaced by the respective bound.
+// A zero bound is not enforced.
+func (tfe *TransactionFeeEstimator) WithBounds(
+	minSatPerVByteFee int64,
+	maxSatPerVByteFee int64,
+) *TransactionFeeEstimator {
+	tfe.minSatPerVByteFee = minSatPerVByteFee
+	tfe.maxSatPerVByteFee = maxSatPerVByteFee
+	return tfe
+}
+
+// EstimateSatPerVByteFee returns the median of sat/vbyte fee estimates of all
+// sources, for a transaction to be confirmed within the given number of
+// blocks. Sources that fail are skipped; an error is returned only if all of
+// them fail. The median is bounded according to the estimator's bounds.
+func (tfe *TransactionFeeEstimator) EstimateSatPerVByteFee(
+	blocks uint32,
+) (int64, error) {
+	estimates := make([]int64, 0, len(tfe.sources))
+	errs := make([]error, 0)
+
+	for _, source := range tfe.sources {
+		estimate, err := source.EstimateSatPerVByteFee(blocks)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		estimates = append(estimates, estimate)
+	}
+
+	if len(estimates) == 0 {
+		return 0, fmt.Errorf(
+			"no sat/vbyte fee estimates available; errors: %v",
+			errs,
+		)
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i] < estimates[j]
+	})
+
+	middle := len(estimates) / 2
+	satPerVByteFee := estimates[middle]
+	if len(estimates)%2 == 0 {
+		// Round up the mean of the two middle estimates.
+		satPerVByteFee = (estimates[middle-1] + estimates[middle] + 1) / 2
+	}
+
+	if tfe.minSatPerVByteFee > 0 && satPerVByteFee < tfe.minSatPerVByteFee {
+		satPerVByteFee = tfe.minSatPerVByteFee
+	}
+
+	if tfe.maxSatPerVByteFee > 0 && satPerVByteFee > tfe.maxSatPerVByteFee {
+		satPerVByteFee = tfe.maxSatPerVByteFee
+	}
+
+	return satPerVByteFee, nil
 }
 
 // EstimateFee estimates the total fee for the given transaction virtual size,
@@ -242,7 +382,7 @@ func (tfe *TransactionFeeEstimator) EstimateFee(
 		resolvedBlocks = blocks[0]
 	}
 
-	satPerVByteFee, err := tfe.chain.EstimateSatPerVByteFee(resolvedBlocks)
+	satPerVByteFee, err := tfe.EstimateSatPerVByteFee(resolvedBlocks)
 	if err != nil {
 		return 0, fmt.Errorf("cannot get estimated sat/vbyte fee: [%v]", err)
 	}
@@ -256,3 +396,58 @@ func (tfe *TransactionFeeEstimator) EstimateFee(
 
 	return fee, nil
 }
+
+// feeEstimatingChain is a Chain that estimates sat/vbyte fees using a fee
+// estimator instead of the wrapped chain.
+type feeEstimatingChain struct {
+	Chain
+
+	estimator *TransactionFeeEstimator
+}
+
+// NewFeeEstimatingChain returns a Chain delegating all calls to the given
+// chain, except sat/vbyte fee estimation, which is delegated to the given
+// fee estimator. This way, components estimating fees using the chain
+// benefit from the estimator's sources and bounds.
+func NewFeeEstimatingChain(
+	chain Chain,
+	estimator *TransactionFeeEstimator,
+) Chain {
+	return &feeEstimatingChain{
+		Chain:     chain,
+		estimator: estimator,
+	}
+}
+
+func (fec *feeEstimatingChain) EstimateSatPerVByteFee(
+	blocks uint32,
+) (int64, error) {
+	return fec.estimator.EstimateSatPerVByteFee(blocks)
+}
+
+func (fec *feeEstimatingChain) GetBlockHeaders(
+	startBlockHeight uint,
+	count uint,
+) ([]*BlockHeader, error) {
+	return GetBlockHeaders(fec.Chain, startBlockHeight, count)
+}
+
+func (fec *feeEstimatingChain) WatchTransaction(
+	ctx context.Context,
+	transaction *Transaction,
+) (<-chan uint, error) {
+	return WatchTransaction(ctx, fec.Chain, transaction)
+}
+
+func (fec *feeEstimatingChain) BroadcastAll(
+	transaction *Transaction,
+) []*BroadcastResult {
+	results, _ := BroadcastAll(fec.Chain, transaction)
+	return results
+}
+
+func (fec *feeEstimatingChain) WatchReorgs(
+	ctx context.Context,
+) <-chan *ReorgEvent {
+	return WatchReorgs(ctx, fec.Chain)
+}
@@ -1,6 +1,7 @@
 package bitcoin
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -63,6 +64,29 @@ func TestTransactionSizeEstimator_VirtualSize(t *testing.T) {
 				AddScriptHashOutputs(1, true),
 			expectedVirtualSize: 250,
 		},
+		// A key path spend has 57.5 vbytes if the signature uses the default
+		// sighash type. The estimator assumes a 65-byte signature so 0.25
+		// vbyte is added.
+		"1 P2TR key path input and 1 P2TR output": {
+			estimator: NewTransactionSizeEstimator().
+				AddTaprootKeyPathInputs(1).
+				AddTaprootOutputs(1),
+			expectedVirtualSize: 112,
+		},
+		"1 P2TR key path input and 2 outputs (1 P2WPKH, 1 P2TR)": {
+			estimator: NewTransactionSizeEstimator().
+				AddTaprootKeyPathInputs(1).
+				AddPublicKeyHashOutputs(1, true).
+				AddTaprootOutputs(1),
+			expectedVirtualSize: 143,
+		},
+		"2 P2TR key path inputs and 1 P2WPKH input and 1 P2TR output": {
+			estimator: NewTransactionSizeEstimator().
+				AddTaprootKeyPathInputs(2).
+				AddPublicKeyHashInputs(1, true).
+				AddTaprootOutputs(1),
+			expectedVirtualSize: 237,
+		},
 	}
 
 	for testName, test := range tests {
@@ -106,3 +130,110 @@ func TestTransactionFeeEstimator_EstimateFee(t *testing.T) {
 		int(fee),
 	)
 }
+
+func TestTransactionFeeEstimator_EstimateSatPerVByteFee(t *testing.T) {
+	var tests = map[string]struct {
+		sourceFees        []int64
+		minSatPerVByteFee int64
+		maxSatPerVByteFee int64
+		expectedFee       int64
+		expectedError     bool
+	}{
+		"single source": {
+			sourceFees:  []int64{50},
+			expectedFee: 50,
+		},
+		"odd number of sources": {
+			sourceFees:  []int64{40, 5000, 30},
+			expectedFee: 40,
+		},
+		"even number of sources": {
+			sourceFees:  []int64{30, 41, 5000, 10},
+			expectedFee: 36,
+		},
+		"failing sources are skipped": {
+			sourceFees:  []int64{-1, 30, -1, 40, 5000},
+			expectedFee: 40,
+		},
+		"estimate below minimum": {
+			sourceFees:        []int64{2, 3},
+			minSatPerVByteFee: 5,
+			maxSatPerVByteFee: 100,
+			expectedFee:       5,
+		},
+		"estimate above maximum": {
+			sourceFees:        []int64{5000},
+			minSatPerVByteFee: 5,
+			maxSatPerVByteFee: 100,
+			expectedFee:       100,
+		},
+		"all sources fail": {
+			sourceFees:    []int64{-1, -1},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			sources := make([]SatPerVByteFeeSource, len(test.sourceFees))
+			for i, sourceFee := range test.sourceFees {
+				sourceFee := sourceFee
+				// A negative fee denotes a failing source.
+				sources[i] = feeSourceFunc(
+					func(blocks uint32) (int64, error) {
+						if sourceFee < 0 {
+							return 0, fmt.Errorf("source unavailable")
+						}
+						return sourceFee, nil
+					},
+				)
+			}
+
+			estimator := NewTransactionFeeEstimator(sources...).WithBounds(
+				test.minSatPerVByteFee,
+				test.maxSatPerVByteFee,
+			)
+
+			fee, err := estimator.EstimateSatPerVByteFee(1)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"sat/vbyte fee",
+				int(test.expectedFee),
+				int(fee),
+			)
+		})
+	}
+}
+
+func TestFeeEstimatingChain(t *testing.T) {
+	chain := newLocalChain()
+	chain.setSatPerVByteFee(5000)
+
+	feeEstimatingChain := NewFeeEstimatingChain(
+		chain,
+		NewTransactionFeeEstimator(chain).WithBounds(1, 100),
+	)
+
+	fee, err := feeEstimatingChain.EstimateSatPerVByteFee(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "sat/vbyte fee", 100, int(fee))
+}
+
+type feeSourceFunc func(blocks uint32) (int64, error)
+
+func (fsf feeSourceFunc) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	return fsf(blocks)
+}
@@ -0,0 +1,28 @@
+package failover
+
+import "time"
+
+const (
+	// DefaultHealthCheckInterval is a default interval of backends health
+	// checks.
+	DefaultHealthCheckInterval = 30 * time.Second
+	// DefaultMaxBlockHeightLag is a default number of blocks a backend can
+	// lag behind the best known block height before it is considered
+	// unhealthy.
+	DefaultMaxBlockHeightLag = 2
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// Interval of backends health checks.
+	HealthCheckInterval time.Duration
+	// Number of blocks a backend can lag behind the best known block height
+	// before it is considered unhealthy.
+	MaxBlockHeightLag uint
+	// Determines whether critical reads must be confirmed by two backends
+	// before their results are returned. The critical reads are: transactions,
+	// transaction confirmations, block headers, and transaction Merkle proofs.
+	// Reads fail if backends disagree. Transaction confirmations may differ
+	// by up to MaxBlockHeightLag.
+	CrossValidation bool
+}
@@ -0,0 +1,690 @@
+package failover
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+var logger = log.Logger("keep-bitcoin-failover")
+
+// backend is a single Bitcoin chain backend wrapped by the failover chain.
+type backend struct {
+	index int
+	chain bitcoin.Chain
+
+	mutex   sync.RWMutex
+	healthy bool
+}
+
+func (b *backend) isHealthy() bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.healthy
+}
+
+func (b *backend) setHealthy(healthy bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.healthy != healthy {
+		if healthy {
+			logger.Infof("backend [%d] became healthy", b.index)
+		} else {
+			logger.Warnf("backend [%d] became unhealthy", b.index)
+		}
+	}
+
+	b.healthy = healthy
+}
+
+// Chain is a bitcoin.Chain implementation wrapping multiple backends. Each
+// request is sent to the first healthy backend and falls over to subsequent
+// backends if it fails. Backends are health-checked periodically. Optionally,
+// critical reads are cross-validated, i.e. results of two backends must agree
+// before they are returned.
+type Chain struct {
+	config   Config
+	backends []*backend
+}
+
+// New creates a failover chain wrapping the given backends. Backends are
+// preferred in the order they are given. The health checks are run until the
+// given context is done.
+func New(
+	ctx context.Context,
+	config Config,
+	chains ...bitcoin.Chain,
+) (*Chain, error) {
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("at least one backend is required")
+	}
+
+	if config.HealthCheckInterval == 0 {
+		config.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if config.MaxBlockHeightLag == 0 {
+		config.MaxBlockHeightLag = DefaultMaxBlockHeightLag
+	}
+
+	if config.CrossValidation && len(chains) < 2 {
+		return nil, fmt.Errorf(
+			"cross-validation requires at least two backends; got [%d]",
+			len(chains),
+		)
+	}
+
+	backends := make([]*backend, len(chains))
+	for i, chain := range chains {
+		backends[i] = &backend{
+			index:   i,
+			chain:   chain,
+			healthy: true,
+		}
+	}
+
+	c := &Chain{
+		config:   config,
+		backends: backends,
+	}
+
+	c.checkHealth()
+
+	go func() {
+		ticker := time.NewTicker(config.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.checkHealth()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+// checkHealth checks the health of all backends. A backend is considered
+// healthy if it returns the latest block height that does not lag behind the
+// best block height returned by all backends by more than the configured
+// number of blocks.
+func (c *Chain) checkHealth() {
+	blockHeights := make([]uint, len(c.backends))
+	errors := make([]error, len(c.backends))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(c.backends))
+
+	for i, b := range c.backends {
+		go func(i int, b *backend) {
+			defer wg.Done()
+			blockHeights[i], errors[i] = b.chain.GetLatestBlockHeight()
+		}(i, b)
+	}
+
+	wg.Wait()
+
+	bestBlockHeight := uint(0)
+	for i := range c.backends {
+		if errors[i] == nil && blockHeights[i] > bestBlockHeight {
+			bestBlockHeight = blockHeights[i]
+		}
+	}
+
+	for i, b := range c.backends {
+		if errors[i] != nil {
+			logger.Warnf(
+				"health check of backend [%d] failed: [%v]",
+				b.index,
+				errors[i],
+			)
+			b.setHealthy(false)
+			continue
+		}
+
+		if blockHeights[i]+c.config.MaxBlockHeightLag < bestBlockHeight {
+			logger.Warnf(
+				"backend [%d] lags behind; its block height is [%d] "+
+					"while the best known block height is [%d]",
+				b.index,
+				blockHeights[i],
+				bestBlockHeight,
+			)
+			b.setHealthy(false)
+			continue
+		}
+
+		b.setHealthy(true)
+	}
+}
+
+// orderedBackends returns healthy backends followed by unhealthy ones. The
+// unhealthy backends are still returned as the last resort, in case all
+// healthy backends fail or there are no healthy backends at all.
+func (c *Chain) orderedBackends() []*backend {
+	healthy := make([]*backend, 0, len(c.backends))
+	unhealthy := make([]*backend, 0)
+
+	for _, b := range c.backends {
+		if b.isHealthy() {
+			healthy = append(healthy, b)
+		} else {
+			unhealthy = append(unhealthy, b)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// request executes the given request against subsequent backends until one
+// of them succeeds.
+func request[K interface{}](
+	c *Chain,
+	requestName string,
+	requestFn func(chain bitcoin.Chain) (K, error),
+) (K, error) {
+	var errs error
+
+	for _, b := range c.orderedBackends() {
+		result, err := requestFn(b.chain)
+		if err != nil {
+			logger.Warnf(
+				"[%s] request to backend [%d] failed: [%v]",
+				requestName,
+				b.index,
+				err,
+			)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		return result, nil
+	}
+
+	var zero K
+	return zero, fmt.Errorf(
+		"[%s] request failed for all backends: [%v]",
+		requestName,
+		errs,
+	)
+}
+
+// crossValidatedRequest executes the given request and, if cross-validation
+// is enabled, makes sure results of two backends match using the given
+// reconcile function. The reconcile function returns the result that should
+// be returned to the caller and false if the results do not match. If
+// cross-validation is disabled, it behaves like request.
+func crossValidatedRequest[K interface{}](
+	c *Chain,
+	requestName string,
+	requestFn func(chain bitcoin.Chain) (K, error),
+	reconcileFn func(first K, second K) (K, bool),
+) (K, error) {
+	if !c.config.CrossValidation {
+		return request(c, requestName, requestFn)
+	}
+
+	var zero K
+	var errs error
+
+	results := make([]K, 0, 2)
+	resultBackends := make([]*backend, 0, 2)
+
+	for _, b := range c.orderedBackends() {
+		result, err := requestFn(b.chain)
+		if err != nil {
+			logger.Warnf(
+				"[%s] request to backend [%d] failed: [%v]",
+				requestName,
+				b.index,
+				err,
+			)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		results = append(results, result)
+		resultBackends = append(resultBackends, b)
+
+		if len(results) == 2 {
+			break
+		}
+	}
+
+	if len(results) < 2 {
+		return zero, fmt.Errorf(
+			"[%s] request could not be cross-validated; "+
+				"[%d] backends returned result: [%v]",
+			requestName,
+			len(results),
+			errs,
+		)
+	}
+
+	result, ok := reconcileFn(results[0], results[1])
+	if !ok {
+		return zero, fmt.Errorf(
+			"[%s] request results of backends [%d] and [%d] do not match: "+
+				"[%+v] vs [%+v]",
+			requestName,
+			resultBackends[0].index,
+			resultBackends[1].index,
+			results[0],
+			results[1],
+		)
+	}
+
+	return result, nil
+}
+
+// reconcileTransactions makes sure both transactions have the same hash and
+// serialized bytes, including witness data.
+func reconcileTransactions(
+	first *bitcoin.Transaction,
+	second *bitcoin.Transaction,
+) (*bitcoin.Transaction, bool) {
+	return first, first.Hash() == second.Hash() &&
+		bytes.Equal(first.Serialize(), second.Serialize())
+}
+
+// reconcileConfirmations makes sure numbers of confirmations of a
+// transaction differ by no more than the maximum block height lag, as
+// backends within the lag may legitimately see a different chain tip. The
+// lower number of confirmations is returned to stay on the safe side.
+func (c *Chain) reconcileConfirmations(first uint, second uint) (uint, bool) {
+	if first > second {
+		first, second = second, first
+	}
+
+	return first, second-first <= c.config.MaxBlockHeightLag
+}
+
+// reconcileBlockHeaders makes sure both lists contain headers of the same
+// blocks.
+func reconcileBlockHeaders(
+	first []*bitcoin.BlockHeader,
+	second []*bitcoin.BlockHeader,
+) ([]*bitcoin.BlockHeader, bool) {
+	if len(first) != len(second) {
+		return first, false
+	}
+
+	for i := range first {
+		if first[i].Hash() != second[i].Hash() {
+			return first, false
+		}
+	}
+
+	return first, true
+}
+
+// reconcileMerkleProofs makes sure both proofs prove the transaction at the
+// same position of the same block with the same Merkle nodes.
+func reconcileMerkleProofs(
+	first *bitcoin.TransactionMerkleProof,
+	second *bitcoin.TransactionMerkleProof,
+) (*bitcoin.TransactionMerkleProof, bool) {
+	if first.BlockHeight != second.BlockHeight ||
+		first.Position != second.Position ||
+		len(first.MerkleNodes) != len(second.MerkleNodes) {
+		return first, false
+	}
+
+	for i := range first.MerkleNodes {
+		if first.MerkleNodes[i] != second.MerkleNodes[i] {
+			return first, false
+		}
+	}
+
+	return first, true
+}
+
+// GetTransaction gets the transaction with the given transaction hash.
+// If the transaction with the given hash was not found on the chain,
+// this function returns an error. Transactions not matching the given hash
+// are rejected. The result is cross-validated if cross-validation is
+// enabled.
+func (c *Chain) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	return crossValidatedRequest(
+		c,
+		"GetTransaction",
+		func(chain bitcoin.Chain) (*bitcoin.Transaction, error) {
+			transaction, err := chain.GetTransaction(transactionHash)
+			if err != nil {
+				return nil, err
+			}
+
+			if transaction.Hash() != transactionHash {
+				return nil, fmt.Errorf(
+					"returned transaction [%s] does not match "+
+						"requested transaction [%s]",
+					transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+					transactionHash.Hex(bitcoin.ReversedByteOrder),
+				)
+			}
+
+			return transaction, nil
+		},
+		reconcileTransactions,
+	)
+}
+
+// WatchTransaction watches the given transaction using the first backend
+// able to start watching. See bitcoin.TransactionWatcher for details.
+func (c *Chain) WatchTransaction(
+	ctx context.Context,
+	transaction *bitcoin.Transaction,
+) (<-chan uint, error) {
+	return request(
+		c,
+		"WatchTransaction",
+		func(chain bitcoin.Chain) (<-chan uint, error) {
+			return bitcoin.WatchTransaction(ctx, chain, transaction)
+		},
+	)
+}
+
+// GetTransactionConfirmations gets the number of confirmations for the
+// transaction with the given transaction hash. If the transaction with the
+// given hash was not found on the chain, this function returns an error.
+// The result is cross-validated if cross-validation is enabled.
+func (c *Chain) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	return crossValidatedRequest(
+		c,
+		"GetTransactionConfirmations",
+		func(chain bitcoin.Chain) (uint, error) {
+			return chain.GetTransactionConfirmations(transactionHash)
+		},
+		c.reconcileConfirmations,
+	)
+}
+
+// BroadcastTransaction broadcasts the given transaction over the
+// network of the Bitcoin chain nodes. If the broadcast action could not be
+// done, this function returns an error. This function does not give any
+// guarantees regarding transaction mining. The transaction may be mined or
+// rejected eventually.
+func (c *Chain) BroadcastTransaction(transaction *bitcoin.Transaction) error {
+	_, err := request(
+		c,
+		"BroadcastTransaction",
+		func(chain bitcoin.Chain) (interface{}, error) {
+			return nil, chain.BroadcastTransaction(transaction)
+		},
+	)
+	return err
+}
+
+// BroadcastAll broadcasts the given transaction to all backends
+// concurrently, regardless of their health, to maximize the propagation
+// speed. The result of each backend is returned. Backends are identified by
+// their indexes.
+func (c *Chain) BroadcastAll(
+	transaction *bitcoin.Transaction,
+) []*bitcoin.BroadcastResult {
+	results := make([]*bitcoin.BroadcastResult, len(c.backends))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(c.backends))
+
+	for i, b := range c.backends {
+		go func(i int, b *backend) {
+			defer wg.Done()
+
+			err := b.chain.BroadcastTransaction(transaction)
+			if err != nil {
+				logger.Warnf(
+					"backend [%d] failed to broadcast transaction: [%v]",
+					b.index,
+					err,
+				)
+			}
+
+			results[i] = &bitcoin.BroadcastResult{
+				Backend: strconv.Itoa(b.index),
+				Err:     err,
+			}
+		}(i, b)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// WatchReorgs watches all backends for reorganizations separately, until the
+// given context is done. Each backend is polled for recent block hashes
+// every bitcoin.ReorgDetectionPollInterval, regardless of its health, so a
+// reorganization is noticed even if it is observed by a single backend.
+// Emitted events identify backends by their indexes.
+func (c *Chain) WatchReorgs(ctx context.Context) <-chan *bitcoin.ReorgEvent {
+	detectors := make([]*bitcoin.ReorgDetector, len(c.backends))
+	for i, b := range c.backends {
+		detectors[i] = bitcoin.NewReorgDetector(
+			strconv.Itoa(b.index),
+			b.chain,
+			bitcoin.ReorgDetectionDepth,
+		)
+	}
+
+	return bitcoin.RunReorgDetectors(
+		ctx,
+		bitcoin.ReorgDetectionPollInterval,
+		detectors...,
+	)
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip). If the
+// latest block was not determined, this function returns an error.
+func (c *Chain) GetLatestBlockHeight() (uint, error) {
+	return request(
+		c,
+		"GetLatestBlockHeight",
+		func(chain bitcoin.Chain) (uint, error) {
+			return chain.GetLatestBlockHeight()
+		},
+	)
+}
+
+// GetBlockHeader gets the block header for the given block height. If the
+// block with the given height was not found on the chain, this function
+// returns an error. The result is cross-validated if cross-validation is
+// enabled.
+func (c *Chain) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	return crossValidatedRequest(
+		c,
+		"GetBlockHeader",
+		func(chain bitcoin.Chain) (*bitcoin.BlockHeader, error) {
+			return chain.GetBlockHeader(blockHeight)
+		},
+		func(
+			first *bitcoin.BlockHeader,
+			second *bitcoin.BlockHeader,
+		) (*bitcoin.BlockHeader, bool) {
+			return first, first.Hash() == second.Hash()
+		},
+	)
+}
+
+// GetBlockHeaders gets block headers for the given number of consecutive
+// blocks starting at the given block height. Backends not implementing
+// bitcoin.BlockHeadersGetter are asked for each block header separately.
+// The result is cross-validated if cross-validation is enabled.
+func (c *Chain) GetBlockHeaders(
+	startBlockHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	return crossValidatedRequest(
+		c,
+		"GetBlockHeaders",
+		func(chain bitcoin.Chain) ([]*bitcoin.BlockHeader, error) {
+			return bitcoin.GetBlockHeaders(chain, startBlockHeight, count)
+		},
+		reconcileBlockHeaders,
+	)
+}
+
+// GetTransactionMerkleProof gets the Merkle proof for a given transaction.
+// The transaction's hash and the block the transaction was included in the
+// blockchain need to be provided. The result is cross-validated if
+// cross-validation is enabled.
+func (c *Chain) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	return crossValidatedRequest(
+		c,
+		"GetTransactionMerkleProof",
+		func(chain bitcoin.Chain) (*bitcoin.TransactionMerkleProof, error) {
+			return chain.GetTransactionMerkleProof(transactionHash, blockHeight)
+		},
+		reconcileMerkleProofs,
+	)
+}
+
+// GetTransactionsForPublicKeyHash gets the confirmed transactions that pays the
+// given public key hash using either a P2PKH or P2WPKH script. The returned
+// transactions are ordered by block height in the ascending order, i.e.
+// the latest transaction is at the end of the list. The returned list does
+// not contain unconfirmed transactions living in the mempool at the moment
+// of request. The returned transactions list can be limited using the
+// `limit` parameter.
+func (c *Chain) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	return request(
+		c,
+		"GetTransactionsForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.Transaction, error) {
+			return chain.GetTransactionsForPublicKeyHash(publicKeyHash, limit)
+		},
+	)
+}
+
+// GetTransactionsPageForPublicKeyHash gets a page of confirmed transactions
+// that pays the given public key hash using either a P2PKH or P2WPKH script.
+// See bitcoin.TransactionHistoryQuery for details. Note that cursors are
+// resolved by transaction hashes so pages can be served by different
+// backends.
+func (c *Chain) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	return request(
+		c,
+		"GetTransactionsPageForPublicKeyHash",
+		func(chain bitcoin.Chain) (*bitcoin.TransactionHistoryPage, error) {
+			return chain.GetTransactionsPageForPublicKeyHash(
+				publicKeyHash,
+				query,
+			)
+		},
+	)
+}
+
+// GetTxHashesForPublicKeyHash gets hashes of confirmed transactions that pays
+// the given public key hash using either a P2PKH or P2WPKH script. The returned
+// transactions hashes are ordered by block height in the ascending order, i.e.
+// the latest transaction hash is at the end of the list. The returned list does
+// not contain unconfirmed transactions hashes living in the mempool at the
+// moment of request.
+func (c *Chain) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	return request(
+		c,
+		"GetTxHashesForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]bitcoin.Hash, error) {
+			return chain.GetTxHashesForPublicKeyHash(publicKeyHash)
+		},
+	)
+}
+
+// GetMempoolForPublicKeyHash gets the unconfirmed mempool transactions
+// that pays the given public key hash using either a P2PKH or P2WPKH script.
+// The returned transactions are in an indefinite order.
+func (c *Chain) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	return request(
+		c,
+		"GetMempoolForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.Transaction, error) {
+			return chain.GetMempoolForPublicKeyHash(publicKeyHash)
+		},
+	)
+}
+
+// GetUtxosForPublicKeyHash gets unspent outputs of confirmed transactions that
+// are controlled by the given public key hash (either a P2PKH or P2WPKH script).
+// The returned UTXOs are ordered by block height in the ascending order, i.e.
+// the latest UTXO is at the end of the list.
+func (c *Chain) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return request(
+		c,
+		"GetUtxosForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.UnspentTransactionOutput, error) {
+			return chain.GetUtxosForPublicKeyHash(publicKeyHash)
+		},
+	)
+}
+
+// GetMempoolUtxosForPublicKeyHash gets unspent outputs of unconfirmed
+// transactions that are controlled by the given public key hash (either a
+// P2PKH or P2WPKH script). The returned UTXOs are in an indefinite order.
+func (c *Chain) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return request(
+		c,
+		"GetMempoolUtxosForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.UnspentTransactionOutput, error) {
+			return chain.GetMempoolUtxosForPublicKeyHash(publicKeyHash)
+		},
+	)
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks.
+func (c *Chain) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	return request(
+		c,
+		"EstimateSatPerVByteFee",
+		func(chain bitcoin.Chain) (int64, error) {
+			return chain.EstimateSatPerVByteFee(blocks)
+		},
+	)
+}
+
+// GetCoinbaseTxHash gets the hash of the coinbase transaction for the given
+// block height.
+func (c *Chain) GetCoinbaseTxHash(blockHeight uint) (bitcoin.Hash, error) {
+	return request(
+		c,
+		"GetCoinbaseTxHash",
+		func(chain bitcoin.Chain) (bitcoin.Hash, error) {
+			return chain.GetCoinbaseTxHash(blockHeight)
+		},
+	)
+}
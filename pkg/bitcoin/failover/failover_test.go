@@ -0,0 +1,387 @@
+package failover
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestChain_Failover(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	primary := newStubChain(100, 6)
+	secondary := newStubChain(100, 7)
+
+	chain, err := New(ctx, Config{}, primary, secondary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirmations, err := chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 6, uint64(confirmations))
+
+	primary.setError(fmt.Errorf("unavailable"))
+
+	confirmations, err = chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 7, uint64(confirmations))
+
+	secondary.setError(fmt.Errorf("unavailable"))
+
+	_, err = chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestChain_HealthCheck(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	primary := newStubChain(100, 6)
+	secondary := newStubChain(100, 7)
+	tertiary := newStubChain(100, 8)
+
+	chain, err := New(ctx, Config{}, primary, secondary, tertiary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHealthy := func(expected ...bool) {
+		for i, b := range chain.backends {
+			testutils.AssertBoolsEqual(
+				t,
+				fmt.Sprintf("backend [%d] health", i),
+				expected[i],
+				b.isHealthy(),
+			)
+		}
+	}
+
+	assertHealthy(true, true, true)
+
+	// The primary backend is down and the secondary lags behind.
+	primary.setError(fmt.Errorf("unavailable"))
+	secondary.setBlockHeight(97)
+	tertiary.setBlockHeight(100)
+	chain.checkHealth()
+
+	assertHealthy(false, false, true)
+
+	// Unhealthy backends are queried last so the tertiary backend answers
+	// first even though the secondary backend works.
+	confirmations, err := chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 8, uint64(confirmations))
+
+	// The secondary backend catches up within the allowed lag.
+	secondary.setBlockHeight(98)
+	chain.checkHealth()
+
+	assertHealthy(false, true, true)
+}
+
+func TestChain_CrossValidation(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	_, err := New(
+		ctx,
+		Config{CrossValidation: true},
+		newStubChain(100, 6),
+	)
+	if err == nil {
+		t.Fatal("expected error for a single backend")
+	}
+
+	primary := newStubChain(100, 6)
+	secondary := newStubChain(100, 6)
+	tertiary := newStubChain(100, 6)
+
+	chain, err := New(
+		ctx,
+		Config{CrossValidation: true},
+		primary,
+		secondary,
+		tertiary,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirmations, err := chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 6, uint64(confirmations))
+
+	// A failing backend is replaced by the next one.
+	primary.setError(fmt.Errorf("unavailable"))
+
+	confirmations, err = chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 6, uint64(confirmations))
+
+	// Confirmations differing within the maximum block height lag are
+	// accepted and the lower number is returned.
+	tertiary.setConfirmations(4)
+
+	confirmations, err = chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "confirmations", 4, uint64(confirmations))
+
+	// Disagreeing backends make the read fail.
+	tertiary.setConfirmations(3)
+
+	_, err = chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err == nil {
+		t.Fatal("expected error for mismatched results")
+	}
+
+	// Non-critical reads are not cross-validated.
+	blockHeight, err := chain.GetLatestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertUintsEqual(t, "block height", 100, uint64(blockHeight))
+
+	// Too few working backends make the read fail.
+	tertiary.setConfirmations(6)
+	secondary.setError(fmt.Errorf("unavailable"))
+
+	_, err = chain.GetTransactionConfirmations(bitcoin.Hash{})
+	if err == nil {
+		t.Fatal("expected error for a single working backend")
+	}
+}
+
+func TestChain_GetTransaction(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	transaction := &bitcoin.Transaction{Version: 1, Locktime: 1}
+	otherTransaction := &bitcoin.Transaction{Version: 1, Locktime: 2}
+
+	primary := newStubChain(100, 6)
+	secondary := newStubChain(100, 6)
+	tertiary := newStubChain(100, 6)
+
+	primary.setTransaction(transaction)
+	secondary.setTransaction(transaction)
+	tertiary.setTransaction(transaction)
+
+	chain, err := New(
+		ctx,
+		Config{CrossValidation: true},
+		primary,
+		secondary,
+		tertiary,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := chain.GetTransaction(transaction.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBytesEqual(
+		t,
+		transaction.Serialize(),
+		result.Serialize(),
+	)
+
+	// A transaction not matching the requested hash is rejected so the
+	// next backend is used.
+	primary.setTransaction(otherTransaction)
+
+	result, err = chain.GetTransaction(transaction.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBytesEqual(
+		t,
+		transaction.Serialize(),
+		result.Serialize(),
+	)
+
+	// Too few backends returning the requested transaction make the read
+	// fail.
+	secondary.setTransaction(otherTransaction)
+
+	_, err = chain.GetTransaction(transaction.Hash())
+	if err == nil {
+		t.Fatal("expected error for a single matching backend")
+	}
+}
+
+func TestChain_BroadcastAll(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	primary := newStubChain(100, 6)
+	secondary := newStubChain(100, 7)
+	tertiary := newStubChain(100, 8)
+
+	chain, err := New(ctx, Config{}, primary, secondary, tertiary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondary.setError(fmt.Errorf("rejected"))
+
+	results, err := bitcoin.BroadcastAll(chain, &bitcoin.Transaction{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "results count", 3, len(results))
+
+	expectedAccepted := []bool{true, false, true}
+	for i, result := range results {
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("backend of result %d", i),
+			fmt.Sprintf("%d", i),
+			result.Backend,
+		)
+		testutils.AssertBoolsEqual(
+			t,
+			fmt.Sprintf("result %d accepted", i),
+			expectedAccepted[i],
+			result.Accepted(),
+		)
+	}
+
+	primary.setError(fmt.Errorf("rejected"))
+	tertiary.setError(fmt.Errorf("rejected"))
+
+	_, err = bitcoin.BroadcastAll(chain, &bitcoin.Transaction{})
+	if err == nil {
+		t.Fatal("expected error if no backend accepted the transaction")
+	}
+}
+
+// stubChain is a bitcoin.Chain stub implementing only the functions used by
+// the tests.
+type stubChain struct {
+	bitcoin.Chain
+
+	mutex         sync.Mutex
+	blockHeight   uint
+	confirmations uint
+	transaction   *bitcoin.Transaction
+	err           error
+}
+
+func newStubChain(blockHeight uint, confirmations uint) *stubChain {
+	return &stubChain{
+		blockHeight:   blockHeight,
+		confirmations: confirmations,
+	}
+}
+
+func (sc *stubChain) setBlockHeight(blockHeight uint) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.blockHeight = blockHeight
+}
+
+func (sc *stubChain) setConfirmations(confirmations uint) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.confirmations = confirmations
+}
+
+func (sc *stubChain) setTransaction(transaction *bitcoin.Transaction) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.transaction = transaction
+}
+
+func (sc *stubChain) setError(err error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.err = err
+}
+
+func (sc *stubChain) GetLatestBlockHeight() (uint, error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	return sc.blockHeight, sc.err
+}
+
+func (sc *stubChain) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	return sc.transaction, sc.err
+}
+
+func (sc *stubChain) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	return sc.confirmations, sc.err
+}
+
+func (sc *stubChain) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	return sc.err
+}
+
+func TestReconcileTransactions(t *testing.T) {
+	newTransaction := func(witness []byte) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Inputs: []*bitcoin.TransactionInput{
+				{
+					Outpoint: &bitcoin.TransactionOutpoint{
+						TransactionHash: bitcoin.Hash{1},
+					},
+					Witness: [][]byte{witness},
+				},
+			},
+			Outputs: []*bitcoin.TransactionOutput{
+				{Value: 1000, PublicKeyScript: []byte{0x51}},
+			},
+		}
+	}
+
+	_, ok := reconcileTransactions(newTransaction([]byte{1}), newTransaction([]byte{1}))
+	testutils.AssertBoolsEqual(t, "same transactions", true, ok)
+
+	// Transactions with the same hash but different witness data do not
+	// match.
+	_, ok = reconcileTransactions(newTransaction([]byte{1}), newTransaction([]byte{2}))
+	testutils.AssertBoolsEqual(t, "different witnesses", false, ok)
+}
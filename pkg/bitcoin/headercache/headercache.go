@@ -0,0 +1,449 @@
+// Package headercache provides a bitcoin.Chain wrapper caching block headers
+// on disk so the same headers are not downloaded over and over again, e.g.
+// for each assembled SPV proof.
+package headercache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+var logger = log.Logger("keep-bitcoin-headercache")
+
+const (
+	// headersDirectory is the name of the directory the cache keeps its
+	// entries in.
+	headersDirectory = "headers"
+
+	// FinalityDepth is the number of confirmations a block must have for its
+	// header to be cached. Headers of shallower blocks may still be
+	// reorganized so they are always fetched from the wrapped chain.
+	FinalityDepth = 6
+
+	// tipRefreshInterval is the maximum age of the latest block height used
+	// to determine whether the fetched header is deep enough to be cached.
+	// A stale height only makes the cache more conservative.
+	tipRefreshInterval = 1 * time.Minute
+
+	// maxCachedMerkleProofs is the maximum number of transaction Merkle
+	// proofs kept in memory.
+	maxCachedMerkleProofs = 10000
+)
+
+// Chain is a bitcoin.Chain implementation caching block headers fetched from
+// the wrapped chain. Only headers of blocks deep enough not to be reorganized
+// are cached and persisted. Each header fetched from the wrapped chain is
+// checked against cached headers of the same and adjacent heights. Cached
+// headers that do not match or do not link to the fetched one are considered
+// reorganized and invalidated. Transaction Merkle proofs of deep enough blocks
+// are cached in memory and invalidated along with the headers of their blocks.
+type Chain struct {
+	bitcoin.Chain
+
+	persistence persistence.BasicHandle
+
+	mutex   sync.Mutex
+	headers map[uint]*bitcoin.BlockHeader
+
+	merkleProofsMutex sync.Mutex
+	merkleProofs      map[merkleProofKey]*bitcoin.TransactionMerkleProof
+
+	tipMutex     sync.Mutex
+	tip          uint
+	tipUpdatedAt time.Time
+}
+
+// New creates a header-caching chain wrapping the given chain. The cache is
+// backed by the given persistence handle and all headers persisted so far are
+// loaded. Headers that cannot be read are logged and skipped.
+func New(chain bitcoin.Chain, persistence persistence.BasicHandle) *Chain {
+	c := &Chain{
+		Chain:       chain,
+		persistence: persistence,
+		headers:     make(map[uint]*bitcoin.BlockHeader),
+		merkleProofs: make(
+			map[merkleProofKey]*bitcoin.TransactionMerkleProof,
+		),
+	}
+
+	c.load()
+
+	return c
+}
+
+func (c *Chain) load() {
+	persistenceutil.ReadAll(
+		c.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != headersDirectory {
+				return
+			}
+
+			height, err := strconv.ParseUint(descriptor.Name(), 10, 64)
+			if err != nil {
+				logger.Errorf(
+					"could not parse block height from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read block header from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			if len(content) != bitcoin.BlockHeaderByteLength {
+				logger.Errorf(
+					"could not parse block header from file [%s]: "+
+						"wrong content length [%v]",
+					descriptor.Name(),
+					len(content),
+				)
+				return
+			}
+
+			var rawHeader [bitcoin.BlockHeaderByteLength]byte
+			copy(rawHeader[:], content)
+
+			header := &bitcoin.BlockHeader{}
+			header.Deserialize(rawHeader)
+
+			c.headers[uint(height)] = header
+		},
+		func(err error) {
+			logger.Errorf("could not load block headers from disk: [%v]", err)
+		},
+	)
+
+	logger.Infof("loaded [%d] cached block headers", len(c.headers))
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip) from the
+// wrapped chain.
+func (c *Chain) GetLatestBlockHeight() (uint, error) {
+	tip, err := c.Chain.GetLatestBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	c.setTip(tip)
+
+	return tip, nil
+}
+
+// GetBlockHeader gets the block header for the given block height. The
+// header is served from the cache if it is there. Otherwise, it is fetched
+// from the wrapped chain and cached if the block is deep enough.
+func (c *Chain) GetBlockHeader(blockHeight uint) (*bitcoin.BlockHeader, error) {
+	if header, ok := c.getCached(blockHeight); ok {
+		return header, nil
+	}
+
+	header, err := c.Chain.GetBlockHeader(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(blockHeight, header, c.getTip())
+
+	return header, nil
+}
+
+// GetBlockHeaders gets block headers for the given number of consecutive
+// blocks starting at the given block height. Headers that cannot be served
+// from the cache are fetched from the wrapped chain in consecutive ranges
+// and cached.
+func (c *Chain) GetBlockHeaders(
+	startBlockHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	headers := make([]*bitcoin.BlockHeader, count)
+
+	for i := uint(0); i < count; {
+		if header, ok := c.getCached(startBlockHeight + i); ok {
+			headers[i] = header
+			i++
+			continue
+		}
+
+		// Find the end of the range of headers missing in the cache.
+		j := i + 1
+		for ; j < count; j++ {
+			if _, ok := c.getCached(startBlockHeight + j); ok {
+				break
+			}
+		}
+
+		fetchedHeaders, err := bitcoin.GetBlockHeaders(
+			c.Chain,
+			startBlockHeight+i,
+			j-i,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tip := c.getTip()
+		for k, header := range fetchedHeaders {
+			height := startBlockHeight + i + uint(k)
+			c.store(height, header, tip)
+			headers[i+uint(k)] = header
+		}
+
+		i = j
+	}
+
+	return headers, nil
+}
+
+// merkleProofKey identifies a cached transaction Merkle proof.
+type merkleProofKey struct {
+	transactionHash bitcoin.Hash
+	blockHeight     uint
+}
+
+// GetTransactionMerkleProof gets the Merkle proof for the given transaction
+// included in the block of the given height. The proof is served from the
+// cache if it is there. Otherwise, it is fetched from the wrapped chain and
+// cached if the block is deep enough.
+func (c *Chain) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	key := merkleProofKey{transactionHash, blockHeight}
+
+	c.merkleProofsMutex.Lock()
+	merkleProof, ok := c.merkleProofs[key]
+	c.merkleProofsMutex.Unlock()
+
+	if ok {
+		return merkleProof, nil
+	}
+
+	merkleProof, err := c.Chain.GetTransactionMerkleProof(
+		transactionHash,
+		blockHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if tip := c.getTip(); isDeepEnough(blockHeight, tip) {
+		c.merkleProofsMutex.Lock()
+		if len(c.merkleProofs) >= maxCachedMerkleProofs {
+			// Evict an arbitrary proof to make room for the new one.
+			for evictedKey := range c.merkleProofs {
+				delete(c.merkleProofs, evictedKey)
+				break
+			}
+		}
+		c.merkleProofs[key] = merkleProof
+		c.merkleProofsMutex.Unlock()
+	}
+
+	return merkleProof, nil
+}
+
+// WatchTransaction watches the given transaction using the wrapped chain.
+func (c *Chain) WatchTransaction(
+	ctx context.Context,
+	transaction *bitcoin.Transaction,
+) (<-chan uint, error) {
+	return bitcoin.WatchTransaction(ctx, c.Chain, transaction)
+}
+
+// BroadcastAll broadcasts the given transaction using the wrapped chain.
+func (c *Chain) BroadcastAll(
+	transaction *bitcoin.Transaction,
+) []*bitcoin.BroadcastResult {
+	results, _ := bitcoin.BroadcastAll(c.Chain, transaction)
+	return results
+}
+
+// WatchReorgs watches the wrapped chain for reorganizations. Recent block
+// headers are never cached so reorganizations are detected using the wrapped
+// chain directly.
+func (c *Chain) WatchReorgs(ctx context.Context) <-chan *bitcoin.ReorgEvent {
+	return bitcoin.WatchReorgs(ctx, c.Chain)
+}
+
+// getCached returns the cached header of the given block height.
+func (c *Chain) getCached(blockHeight uint) (*bitcoin.BlockHeader, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	header, ok := c.headers[blockHeight]
+	return header, ok
+}
+
+// store caches the given header fetched from the wrapped chain if the block
+// has at least FinalityDepth confirmations according to the given tip. Cached
+// headers that conflict with the given one are invalidated regardless.
+func (c *Chain) store(
+	blockHeight uint,
+	header *bitcoin.BlockHeader,
+	tip uint,
+) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	headerHash := header.Hash()
+
+	if cached, ok := c.headers[blockHeight]; ok {
+		if cached.Hash() == headerHash {
+			return
+		}
+
+		// The cached header was reorganized, along with all headers above.
+		c.invalidateFrom(blockHeight)
+	}
+
+	if next, ok := c.headers[blockHeight+1]; ok &&
+		next.PreviousBlockHeaderHash != headerHash {
+		c.invalidateFrom(blockHeight + 1)
+	}
+
+	if blockHeight > 0 {
+		if previous, ok := c.headers[blockHeight-1]; ok &&
+			previous.Hash() != header.PreviousBlockHeaderHash {
+			// The previous header was reorganized. It is not known how deep
+			// the reorganization was so all consecutive cached headers below
+			// are invalidated as well.
+			c.invalidateDownFrom(blockHeight - 1)
+		}
+	}
+
+	if !isDeepEnough(blockHeight, tip) {
+		return
+	}
+
+	rawHeader := header.Serialize()
+	if err := c.persistence.Save(
+		rawHeader[:],
+		headersDirectory,
+		strconv.FormatUint(uint64(blockHeight), 10),
+	); err != nil {
+		logger.Errorf(
+			"cannot save block header [%d]: [%v]",
+			blockHeight,
+			err,
+		)
+		return
+	}
+
+	c.headers[blockHeight] = header
+}
+
+// invalidateFrom removes cached headers of the given and all higher block
+// heights. Must be called with the mutex held.
+func (c *Chain) invalidateFrom(blockHeight uint) {
+	logger.Warnf(
+		"block reorganization detected; invalidating cached block headers "+
+			"from height [%d]",
+		blockHeight,
+	)
+
+	for height := range c.headers {
+		if height >= blockHeight {
+			c.invalidate(height)
+		}
+	}
+}
+
+// invalidateDownFrom removes cached headers of the given block height and all
+// consecutive lower block heights. Must be called with the mutex held.
+func (c *Chain) invalidateDownFrom(blockHeight uint) {
+	logger.Warnf(
+		"block reorganization detected; invalidating cached block headers "+
+			"down from height [%d]",
+		blockHeight,
+	)
+
+	for height := blockHeight; ; height-- {
+		if _, ok := c.headers[height]; !ok {
+			return
+		}
+
+		c.invalidate(height)
+
+		if height == 0 {
+			return
+		}
+	}
+}
+
+// invalidate removes the cached header and Merkle proofs of the given block
+// height. Must be called with the mutex held.
+func (c *Chain) invalidate(blockHeight uint) {
+	delete(c.headers, blockHeight)
+
+	c.merkleProofsMutex.Lock()
+	for key := range c.merkleProofs {
+		if key.blockHeight == blockHeight {
+			delete(c.merkleProofs, key)
+		}
+	}
+	c.merkleProofsMutex.Unlock()
+
+	if err := c.persistence.Delete(
+		headersDirectory,
+		strconv.FormatUint(uint64(blockHeight), 10),
+	); err != nil {
+		logger.Errorf(
+			"cannot delete block header [%d]: [%v]",
+			blockHeight,
+			err,
+		)
+	}
+}
+
+// isDeepEnough returns true if the block of the given height has at least
+// FinalityDepth confirmations according to the given tip.
+func isDeepEnough(blockHeight uint, tip uint) bool {
+	return tip >= blockHeight && tip-blockHeight+1 >= FinalityDepth
+}
+
+// getTip returns the latest block height. The height is refreshed if it is
+// older than tipRefreshInterval. Zero is returned if the height cannot be
+// determined, in which case no fetched header is cached.
+func (c *Chain) getTip() uint {
+	c.tipMutex.Lock()
+	isFresh := time.Since(c.tipUpdatedAt) < tipRefreshInterval
+	tip := c.tip
+	c.tipMutex.Unlock()
+
+	if isFresh {
+		return tip
+	}
+
+	tip, err := c.GetLatestBlockHeight()
+	if err != nil {
+		logger.Warnf("cannot get the latest block height: [%v]", err)
+		return 0
+	}
+
+	return tip
+}
+
+func (c *Chain) setTip(tip uint) {
+	c.tipMutex.Lock()
+	defer c.tipMutex.Unlock()
+
+	c.tip = tip
+	c.tipUpdatedAt = time.Now()
+}
@@ -0,0 +1,328 @@
+package headercache
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestChain_GetBlockHeader(t *testing.T) {
+	backend := newStubChain(100)
+	handle := newMockPersistenceHandle()
+
+	chain := New(backend, handle)
+
+	// Block 90 is deep enough to be cached.
+	for i := 0; i < 3; i++ {
+		header, err := chain.GetBlockHeader(90)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertHeader(t, backend.headers[90], header)
+	}
+	testutils.AssertIntsEqual(t, "fetches of block 90", 1, backend.fetches[90])
+
+	// Block 98 is too shallow to be cached.
+	for i := 0; i < 3; i++ {
+		if _, err := chain.GetBlockHeader(98); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testutils.AssertIntsEqual(t, "fetches of block 98", 3, backend.fetches[98])
+
+	// The cache should be loaded from the persistence.
+	reloadedChain := New(backend, handle)
+	header, err := reloadedChain.GetBlockHeader(90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHeader(t, backend.headers[90], header)
+	testutils.AssertIntsEqual(t, "fetches of block 90", 1, backend.fetches[90])
+}
+
+func TestChain_GetBlockHeaders(t *testing.T) {
+	backend := newStubChain(100)
+	chain := New(backend, newMockPersistenceHandle())
+
+	if _, err := chain.GetBlockHeader(85); err != nil {
+		t.Fatal(err)
+	}
+
+	headers, err := chain.GetBlockHeaders(83, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "headers count", 5, len(headers))
+	for i, header := range headers {
+		assertHeader(t, backend.headers[83+uint(i)], header)
+	}
+
+	expectedFetches := map[uint]int{83: 1, 84: 1, 85: 1, 86: 1, 87: 1}
+	for height, expected := range expectedFetches {
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("fetches of block %d", height),
+			expected,
+			backend.fetches[height],
+		)
+	}
+
+	// All headers are cached now.
+	if _, err := chain.GetBlockHeaders(83, 5); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "fetches of block 84", 1, backend.fetches[84])
+}
+
+func TestChain_Reorganization(t *testing.T) {
+	backend := newStubChain(100)
+	chain := New(backend, newMockPersistenceHandle())
+
+	// Cache headers 90-94.
+	if _, err := chain.GetBlockHeaders(90, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reorganize blocks starting from 93.
+	backend.reorganize(93, 110)
+	if _, err := chain.GetLatestBlockHeight(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Block 95 does not link to the cached block 94 so all consecutive
+	// cached headers below should be invalidated.
+	header, err := chain.GetBlockHeader(95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHeader(t, backend.headers[95], header)
+
+	for height := uint(90); height <= 94; height++ {
+		chain.mutex.Lock()
+		_, ok := chain.headers[height]
+		chain.mutex.Unlock()
+
+		testutils.AssertBoolsEqual(
+			t,
+			fmt.Sprintf("block %d cached", height),
+			false,
+			ok,
+		)
+	}
+
+	header, err = chain.GetBlockHeader(93)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHeader(t, backend.headers[93], header)
+}
+
+func TestChain_GetTransactionMerkleProof(t *testing.T) {
+	backend := newStubChain(100)
+	chain := New(backend, newMockPersistenceHandle())
+
+	transactionHash := bitcoin.Hash{1}
+
+	for _, blockHeight := range []uint{90, 98} {
+		for i := 0; i < 3; i++ {
+			merkleProof, err := chain.GetTransactionMerkleProof(
+				transactionHash,
+				blockHeight,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"block height",
+				uint64(blockHeight),
+				uint64(merkleProof.BlockHeight),
+			)
+		}
+	}
+
+	// The proof from the deep block should be cached while the proof from
+	// the shallow block should be fetched each time.
+	testutils.AssertIntsEqual(t, "fetches of proof 90", 1, backend.merkleProofFetches[90])
+	testutils.AssertIntsEqual(t, "fetches of proof 98", 3, backend.merkleProofFetches[98])
+
+	// Invalidating the block should invalidate the proof as well.
+	chain.mutex.Lock()
+	chain.invalidate(90)
+	chain.mutex.Unlock()
+
+	if _, err := chain.GetTransactionMerkleProof(transactionHash, 90); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "fetches of proof 90", 2, backend.merkleProofFetches[90])
+}
+
+func assertHeader(t *testing.T, expected, actual *bitcoin.BlockHeader) {
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf(
+			"unexpected block header\nexpected: %+v\nactual:   %+v",
+			expected,
+			actual,
+		)
+	}
+}
+
+type stubChain struct {
+	bitcoin.Chain
+
+	mutex   sync.Mutex
+	tip     uint
+	headers map[uint]*bitcoin.BlockHeader
+	fetches map[uint]int
+
+	merkleProofFetches map[uint]int
+}
+
+func newStubChain(tip uint) *stubChain {
+	sc := &stubChain{
+		headers: make(map[uint]*bitcoin.BlockHeader),
+		fetches: make(map[uint]int),
+
+		merkleProofFetches: make(map[uint]int),
+	}
+	sc.reorganize(1, tip)
+	return sc
+}
+
+// reorganize replaces headers from the given height with new headers up to
+// the given tip.
+func (sc *stubChain) reorganize(fromHeight uint, tip uint) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	for height := fromHeight; height <= tip; height++ {
+		header := &bitcoin.BlockHeader{
+			Version: 1,
+			Nonce:   uint32(tip),
+			Time:    uint32(height),
+		}
+		if previous, ok := sc.headers[height-1]; ok {
+			header.PreviousBlockHeaderHash = previous.Hash()
+		}
+		sc.headers[height] = header
+	}
+	sc.tip = tip
+}
+
+func (sc *stubChain) GetLatestBlockHeight() (uint, error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	return sc.tip, nil
+}
+
+func (sc *stubChain) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.fetches[blockHeight]++
+
+	header, ok := sc.headers[blockHeight]
+	if !ok {
+		return nil, fmt.Errorf("block header not found")
+	}
+
+	return header, nil
+}
+
+func (sc *stubChain) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.merkleProofFetches[blockHeight]++
+
+	return &bitcoin.TransactionMerkleProof{BlockHeight: blockHeight}, nil
+}
+
+type mockPersistenceHandle struct {
+	mutex sync.Mutex
+	data  map[string]*mockDescriptor
+}
+
+func newMockPersistenceHandle() *mockPersistenceHandle {
+	return &mockPersistenceHandle{
+		data: make(map[string]*mockDescriptor),
+	}
+}
+
+func (mph *mockPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	mph.data[directory+"/"+name] = &mockDescriptor{
+		name:      name,
+		directory: directory,
+		content:   data,
+	}
+
+	return nil
+}
+
+func (mph *mockPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	outputData := make(chan persistence.DataDescriptor, len(mph.data))
+	outputErrors := make(chan error)
+
+	for _, descriptor := range mph.data {
+		outputData <- descriptor
+	}
+
+	close(outputData)
+	close(outputErrors)
+
+	return outputData, outputErrors
+}
+
+func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	delete(mph.data, directory+"/"+name)
+
+	return nil
+}
+
+type mockDescriptor struct {
+	name      string
+	directory string
+	content   []byte
+}
+
+func (md *mockDescriptor) Name() string {
+	return md.name
+}
+
+func (md *mockDescriptor) Directory() string {
+	return md.directory
+}
+
+func (md *mockDescriptor) Content() ([]byte, error) {
+	return md.content, nil
+}
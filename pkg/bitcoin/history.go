@@ -0,0 +1,136 @@
+package bitcoin
+
+import (
+	"fmt"
+)
+
+// TransactionHistoryCursor points to a confirmed transaction in the history
+// of a public key hash. It is used to resume walking the history from the
+// given transaction.
+type TransactionHistoryCursor struct {
+	// TransactionHash is the hash of the transaction the cursor points to.
+	TransactionHash Hash
+	// BlockHeight is the height of the block the transaction was included in.
+	BlockHeight uint
+}
+
+// TransactionHistoryQuery describes a single page of the confirmed
+// transaction history of a public key hash. The history is walked backwards,
+// i.e. the first page contains the latest transactions and each subsequent
+// page contains older ones.
+type TransactionHistoryQuery struct {
+	// PageSize is the maximum number of transactions returned in the page.
+	// It must be greater than zero.
+	PageSize int
+	// MinConfirmations filters out transactions having fewer confirmations.
+	MinConfirmations uint
+	// MaxConfirmations filters out transactions having more confirmations.
+	// Zero means no upper bound.
+	MaxConfirmations uint
+	// Cursor is the cursor returned along with the previous page. Only
+	// transactions older than the transaction the cursor points to are
+	// returned. Nil means the walk starts from the latest transaction.
+	Cursor *TransactionHistoryCursor
+}
+
+// TransactionHistoryPage is a single page of the confirmed transaction
+// history of a public key hash.
+type TransactionHistoryPage struct {
+	// Transactions are the transactions of the page, ordered by block height
+	// in the ascending order, i.e. the latest transaction is at the end of
+	// the list.
+	Transactions []*Transaction
+	// NextCursor is the cursor that should be used to query the next page.
+	// It is nil if there are no more transactions matching the query.
+	NextCursor *TransactionHistoryCursor
+}
+
+// TransactionHistoryItem is a confirmed transaction hash along with the
+// height of the block the transaction was included in.
+type TransactionHistoryItem struct {
+	TransactionHash Hash
+	BlockHeight     uint
+}
+
+// SelectTransactionHistoryPage selects items of the page described by the
+// given query from the given history, ordered by block height in the
+// ascending order. Confirmations of items are determined using the given
+// latest block height. The selected items keep the history order. Along
+// with them, the cursor pointing to the next page is returned; it is nil if
+// there are no more items matching the query. This function is meant to be
+// used by Chain implementations to serve paginated queries in a consistent
+// way.
+func SelectTransactionHistoryPage(
+	history []*TransactionHistoryItem,
+	latestBlockHeight uint,
+	query *TransactionHistoryQuery,
+) ([]*TransactionHistoryItem, *TransactionHistoryCursor, error) {
+	if query.PageSize <= 0 {
+		return nil, nil, fmt.Errorf(
+			"page size must be greater than zero; got [%d]",
+			query.PageSize,
+		)
+	}
+
+	if query.MaxConfirmations != 0 &&
+		query.MaxConfirmations < query.MinConfirmations {
+		return nil, nil, fmt.Errorf(
+			"max confirmations [%d] lower than min confirmations [%d]",
+			query.MaxConfirmations,
+			query.MinConfirmations,
+		)
+	}
+
+	// The cursor is resolved against the unfiltered history so the walk
+	// stays consistent even if the cursor's transaction no longer matches
+	// the confirmation filters.
+	end := len(history)
+	if query.Cursor != nil {
+		end = -1
+		for i, item := range history {
+			if item.TransactionHash == query.Cursor.TransactionHash {
+				end = i
+				break
+			}
+		}
+
+		if end == -1 {
+			return nil, nil, fmt.Errorf(
+				"cursor transaction [%s] not found in the history; "+
+					"the history could have been reorganized",
+				query.Cursor.TransactionHash.Hex(ReversedByteOrder),
+			)
+		}
+	}
+
+	matching := make([]*TransactionHistoryItem, 0)
+	for _, item := range history[:end] {
+		if item.BlockHeight > latestBlockHeight {
+			continue
+		}
+
+		confirmations := latestBlockHeight - item.BlockHeight + 1
+
+		if confirmations < query.MinConfirmations {
+			continue
+		}
+
+		if query.MaxConfirmations != 0 &&
+			confirmations > query.MaxConfirmations {
+			continue
+		}
+
+		matching = append(matching, item)
+	}
+
+	if len(matching) <= query.PageSize {
+		return matching, nil, nil
+	}
+
+	selected := matching[len(matching)-query.PageSize:]
+
+	return selected, &TransactionHistoryCursor{
+		TransactionHash: selected[0].TransactionHash,
+		BlockHeight:     selected[0].BlockHeight,
+	}, nil
+}
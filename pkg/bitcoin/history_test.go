@@ -0,0 +1,140 @@
+package bitcoin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectTransactionHistoryPage(t *testing.T) {
+	// Ten transactions included in blocks 91-100.
+	history := make([]*TransactionHistoryItem, 10)
+	for i := range history {
+		history[i] = &TransactionHistoryItem{
+			TransactionHash: Hash{byte(i + 1)},
+			BlockHeight:     uint(91 + i),
+		}
+	}
+
+	latestBlockHeight := uint(100)
+
+	cursorAt := func(index int) *TransactionHistoryCursor {
+		return &TransactionHistoryCursor{
+			TransactionHash: history[index].TransactionHash,
+			BlockHeight:     history[index].BlockHeight,
+		}
+	}
+
+	var tests = map[string]struct {
+		query              *TransactionHistoryQuery
+		expectedItems      []*TransactionHistoryItem
+		expectedNextCursor *TransactionHistoryCursor
+		expectedErr        bool
+	}{
+		"first page": {
+			query:              &TransactionHistoryQuery{PageSize: 3},
+			expectedItems:      history[7:],
+			expectedNextCursor: cursorAt(7),
+		},
+		"middle page": {
+			query: &TransactionHistoryQuery{
+				PageSize: 3,
+				Cursor:   cursorAt(7),
+			},
+			expectedItems:      history[4:7],
+			expectedNextCursor: cursorAt(4),
+		},
+		"last page": {
+			query: &TransactionHistoryQuery{
+				PageSize: 3,
+				Cursor:   cursorAt(1),
+			},
+			expectedItems: history[:1],
+		},
+		"page covering whole history": {
+			query:         &TransactionHistoryQuery{PageSize: 10},
+			expectedItems: history,
+		},
+		"min confirmations": {
+			query: &TransactionHistoryQuery{
+				PageSize:         3,
+				MinConfirmations: 6,
+			},
+			// Block 95 has 6 confirmations.
+			expectedItems:      history[2:5],
+			expectedNextCursor: cursorAt(2),
+		},
+		"max confirmations": {
+			query: &TransactionHistoryQuery{
+				PageSize:         3,
+				MaxConfirmations: 4,
+				Cursor:           cursorAt(8),
+			},
+			// Block 97 has 4 confirmations.
+			expectedItems: history[6:8],
+		},
+		"cursor not matching confirmation filters": {
+			query: &TransactionHistoryQuery{
+				PageSize:         2,
+				MinConfirmations: 8,
+				Cursor:           cursorAt(5),
+			},
+			// Block 93 has 8 confirmations.
+			expectedItems:      history[1:3],
+			expectedNextCursor: cursorAt(1),
+		},
+		"unknown cursor": {
+			query: &TransactionHistoryQuery{
+				PageSize: 3,
+				Cursor:   &TransactionHistoryCursor{TransactionHash: Hash{11}},
+			},
+			expectedErr: true,
+		},
+		"zero page size": {
+			query:       &TransactionHistoryQuery{},
+			expectedErr: true,
+		},
+		"max confirmations lower than min confirmations": {
+			query: &TransactionHistoryQuery{
+				PageSize:         3,
+				MinConfirmations: 5,
+				MaxConfirmations: 4,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			items, nextCursor, err := SelectTransactionHistoryPage(
+				history,
+				latestBlockHeight,
+				test.query,
+			)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(test.expectedItems, items) {
+				t.Errorf(
+					"unexpected items\nexpected: %v\nactual:   %v",
+					test.expectedItems,
+					items,
+				)
+			}
+
+			if !reflect.DeepEqual(test.expectedNextCursor, nextCursor) {
+				t.Errorf(
+					"unexpected next cursor\nexpected: %+v\nactual:   %+v",
+					test.expectedNextCursor,
+					nextCursor,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package mempoolspace
+
+import "time"
+
+const (
+	// DefaultRequestTimeout is a default timeout used for a single attempt of
+	// mempool.space API request.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultRequestRetryTimeout is a default timeout used for mempool.space
+	// API request retries.
+	DefaultRequestRetryTimeout = 1 * time.Minute
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// URL to the mempool.space REST API, e.g. `https://mempool.space/api`.
+	// If empty, the mempool.space API is not used.
+	URL string
+	// Timeout for a single attempt of mempool.space API request.
+	RequestTimeout time.Duration
+	// Timeout for mempool.space API request retries.
+	RequestRetryTimeout time.Duration
+	// URL of the SOCKS5 proxy the API requests are routed through, e.g.
+	// `socks5://127.0.0.1:9050`. Host names, including .onion addresses, are
+	// resolved by the proxy. If empty, the requests are sent directly.
+	ProxyURL string
+}
@@ -0,0 +1,267 @@
+package mempoolspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/wrappers"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/proxy"
+)
+
+var logger = log.Logger("keep-mempoolspace")
+
+// errNotFound is returned when the mempool.space API responds with 404.
+var errNotFound = fmt.Errorf("not found")
+
+// Client is a client of the mempool.space REST API. It can be used as
+// a sat/vbyte fee source and to query the state of the mempool.
+type Client struct {
+	parentCtx context.Context
+	client    *http.Client
+	config    Config
+}
+
+// NewClient creates a new mempool.space API client with the provided Config.
+func NewClient(parentCtx context.Context, config Config) (*Client, error) {
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if config.RequestRetryTimeout == 0 {
+		config.RequestRetryTimeout = DefaultRequestRetryTimeout
+	}
+
+	config.URL = strings.TrimSuffix(config.URL, "/")
+
+	client, err := proxy.NewHTTPClient(config.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up proxy: [%w]", err)
+	}
+
+	return &Client{
+		parentCtx: parentCtx,
+		client:    client,
+		config:    config,
+	}, nil
+}
+
+// recommendedFees holds sat/vbyte fees recommended by mempool.space for
+// different confirmation targets.
+type recommendedFees struct {
+	FastestFee  int64 `json:"fastestFee"`
+	HalfHourFee int64 `json:"halfHourFee"`
+	HourFee     int64 `json:"hourFee"`
+	EconomyFee  int64 `json:"economyFee"`
+	MinimumFee  int64 `json:"minimumFee"`
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks. The
+// estimate is one of the fees recommended by mempool.space: the fastest fee
+// for the next block, the half-hour fee for up to 3 blocks, the hour fee for
+// up to 6 blocks, and the economy fee otherwise.
+func (c *Client) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	fees, err := requestWithRetry[recommendedFees](c, "/v1/fees/recommended")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recommended fees: [%w]", err)
+	}
+
+	var satPerVByteFee int64
+	switch {
+	case blocks <= 1:
+		satPerVByteFee = fees.FastestFee
+	case blocks <= 3:
+		satPerVByteFee = fees.HalfHourFee
+	case blocks <= 6:
+		satPerVByteFee = fees.HourFee
+	default:
+		satPerVByteFee = fees.EconomyFee
+	}
+
+	// Make sure the minimum returned sat/vbyte fee is always 1.
+	if satPerVByteFee < 1 {
+		satPerVByteFee = 1
+	}
+
+	return satPerVByteFee, nil
+}
+
+// transactionStatus holds the confirmation status of a transaction.
+type transactionStatus struct {
+	Confirmed   bool `json:"confirmed"`
+	BlockHeight uint `json:"block_height"`
+}
+
+// IsTransactionInMempool returns true if the transaction with the given
+// hash is in the mempool, i.e. it is known but not yet confirmed. Returns
+// false if the transaction is confirmed or not known at all.
+func (c *Client) IsTransactionInMempool(
+	transactionHash bitcoin.Hash,
+) (bool, error) {
+	status, err := requestWithRetry[transactionStatus](
+		c,
+		fmt.Sprintf(
+			"/tx/%s/status",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+		),
+	)
+	if errors.Is(err, errNotFound) {
+		// The transaction is not known.
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get transaction status: [%w]", err)
+	}
+
+	return !status.Confirmed, nil
+}
+
+// FeeHistogramBin is a single bin of the mempool fee histogram.
+type FeeHistogramBin struct {
+	// SatPerVByteFee is the sat/vbyte fee of the bin.
+	SatPerVByteFee float64
+	// VirtualSize is the total virtual size of mempool transactions paying
+	// the bin's fee.
+	VirtualSize int64
+}
+
+// GetFeeHistogram returns the fee histogram of the mempool. The bins are
+// ordered by the sat/vbyte fee in the descending order.
+func (c *Client) GetFeeHistogram() ([]FeeHistogramBin, error) {
+	type mempoolInfo struct {
+		FeeHistogram [][2]float64 `json:"fee_histogram"`
+	}
+
+	info, err := requestWithRetry[mempoolInfo](c, "/mempool")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mempool info: [%w]", err)
+	}
+
+	histogram := make([]FeeHistogramBin, len(info.FeeHistogram))
+	for i, bin := range info.FeeHistogram {
+		histogram[i] = FeeHistogramBin{
+			SatPerVByteFee: bin[0],
+			VirtualSize:    int64(bin[1]),
+		}
+	}
+
+	return histogram, nil
+}
+
+// get executes a GET request to the given API path and unmarshals the JSON
+// response to the given result. An error is returned if the API responds with
+// a non-2xx status. For 404, the returned error wraps errNotFound.
+func (c *Client) get(
+	ctx context.Context,
+	path string,
+	result interface{},
+) error {
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.config.URL+path,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create request: [%w]", err)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read response: [%w]", err)
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return fmt.Errorf(
+			"unexpected response status [%s]: [%w]",
+			response.Status,
+			errNotFound,
+		)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected response status [%s]: [%s]",
+			response.Status,
+			strings.TrimSpace(string(responseBody)),
+		)
+	}
+
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		return fmt.Errorf("cannot unmarshal response: [%w]", err)
+	}
+
+	return nil
+}
+
+// requestWithRetry executes a GET request to the given API path, retrying
+// it on failures. A 404 response is not retried; errNotFound is returned
+// right away instead.
+func requestWithRetry[K interface{}](c *Client, path string) (K, error) {
+	startTime := time.Now()
+	logger.Debugf("starting [%s] request to mempool.space API", path)
+
+	var result K
+	notFound := false
+
+	err := wrappers.DoWithDefaultRetry(
+		c.parentCtx,
+		c.config.RequestRetryTimeout,
+		func(ctx context.Context) error {
+			requestCtx, requestCancel := context.WithTimeout(
+				ctx,
+				c.config.RequestTimeout,
+			)
+			defer requestCancel()
+
+			var r K
+			if err := c.get(requestCtx, path, &r); err != nil {
+				if errors.Is(err, errNotFound) {
+					// Retrying does not help if the resource is not
+					// known.
+					notFound = true
+					return nil
+				}
+
+				return fmt.Errorf("request failed: [%w]", err)
+			}
+
+			result = r
+			return nil
+		},
+	)
+
+	if err == nil && notFound {
+		err = errNotFound
+	}
+
+	solveRequestOutcome := func(err error) string {
+		if err != nil {
+			return fmt.Sprintf("error: [%v]", err)
+		}
+		return "success"
+	}
+
+	logger.Debugf(
+		"[%s] request to mempool.space API completed with [%s] after [%s]",
+		path,
+		solveRequestOutcome(err),
+		time.Since(startTime),
+	)
+
+	return result, err
+}
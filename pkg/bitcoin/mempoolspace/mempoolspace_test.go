@@ -0,0 +1,196 @@
+package mempoolspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestClient_EstimateSatPerVByteFee(t *testing.T) {
+	server := newFakeServer(t, map[string]string{
+		"/api/v1/fees/recommended": `{"fastestFee":41,"halfHourFee":30,` +
+			`"hourFee":22,"economyFee":0,"minimumFee":1}`,
+	})
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	var tests = map[string]struct {
+		blocks      uint32
+		expectedFee int64
+	}{
+		"next block": {
+			blocks:      1,
+			expectedFee: 41,
+		},
+		"within 3 blocks": {
+			blocks:      3,
+			expectedFee: 30,
+		},
+		"within 6 blocks": {
+			blocks:      4,
+			expectedFee: 22,
+		},
+		"more than 6 blocks": {
+			blocks:      144,
+			expectedFee: 1,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			fee, err := client.EstimateSatPerVByteFee(test.blocks)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"sat/vbyte fee",
+				int(test.expectedFee),
+				int(fee),
+			)
+		})
+	}
+}
+
+func TestClient_IsTransactionInMempool(t *testing.T) {
+	mempoolTxHash := bitcoin.Hash{1}
+	confirmedTxHash := bitcoin.Hash{2}
+	unknownTxHash := bitcoin.Hash{3}
+
+	server := newFakeServer(t, map[string]string{
+		statusPath(mempoolTxHash):   `{"confirmed":false}`,
+		statusPath(confirmedTxHash): `{"confirmed":true,"block_height":800000}`,
+	})
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	var tests = map[string]struct {
+		transactionHash   bitcoin.Hash
+		expectedInMempool bool
+	}{
+		"transaction in mempool": {
+			transactionHash:   mempoolTxHash,
+			expectedInMempool: true,
+		},
+		"confirmed transaction": {
+			transactionHash:   confirmedTxHash,
+			expectedInMempool: false,
+		},
+		"unknown transaction": {
+			transactionHash:   unknownTxHash,
+			expectedInMempool: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			inMempool, err := client.IsTransactionInMempool(test.transactionHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"in mempool",
+				test.expectedInMempool,
+				inMempool,
+			)
+		})
+	}
+}
+
+func TestClient_GetFeeHistogram(t *testing.T) {
+	server := newFakeServer(t, map[string]string{
+		"/api/mempool": `{"count":3,"vsize":1500,"total_fee":25000,` +
+			`"fee_histogram":[[53.5,500],[20,1000]]}`,
+	})
+	defer server.Close()
+
+	histogram, err := newTestClient(t, server).GetFeeHistogram()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHistogram := []FeeHistogramBin{
+		{SatPerVByteFee: 53.5, VirtualSize: 500},
+		{SatPerVByteFee: 20, VirtualSize: 1000},
+	}
+	if !reflect.DeepEqual(expectedHistogram, histogram) {
+		t.Errorf(
+			"unexpected histogram\nexpected: %v\nactual:   %v",
+			expectedHistogram,
+			histogram,
+		)
+	}
+}
+
+func TestClient_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer server.Close()
+
+	_, err := newTestClient(t, server).EstimateSatPerVByteFee(1)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_NotFound(t *testing.T) {
+	server := newFakeServer(t, map[string]string{})
+	defer server.Close()
+
+	_, err := newTestClient(t, server).EstimateSatPerVByteFee(1)
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+}
+
+func newFakeServer(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			response, ok := responses[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, "Transaction not found")
+				return
+			}
+
+			if _, err := fmt.Fprint(w, response); err != nil {
+				t.Errorf("cannot write response: [%v]", err)
+			}
+		},
+	))
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	client, err := NewClient(context.Background(), Config{
+		URL:                 server.URL + "/api/",
+		RequestRetryTimeout: 1 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func statusPath(transactionHash bitcoin.Hash) string {
+	return fmt.Sprintf(
+		"/api/tx/%s/status",
+		transactionHash.Hex(bitcoin.ReversedByteOrder),
+	)
+}
@@ -0,0 +1,38 @@
+package bitcoin
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// chainParams returns the btcd chain parameters of the given network.
+// Testnet4 has no dedicated parameters in btcd. However, it shares the
+// address encoding and the proof-of-work limit with testnet3 so testnet3
+// parameters are used for it.
+func (n Network) chainParams() (*chaincfg.Params, error) {
+	switch n {
+	case Mainnet:
+		return &chaincfg.MainNetParams, nil
+	case Testnet, Testnet4:
+		return &chaincfg.TestNet3Params, nil
+	case Regtest:
+		return &chaincfg.RegressionNetParams, nil
+	default:
+		return nil, fmt.Errorf("unsupported network [%v]", n)
+	}
+}
+
+// AllowsMinDifficultyBlocks returns true if the given network allows mining
+// blocks with the lowest possible difficulty regardless of the difficulty of
+// the current epoch. On testnet and testnet4, such a block can be mined if
+// no block has been mined for 20 minutes. On regtest, all blocks are mined
+// with the lowest possible difficulty.
+func (n Network) AllowsMinDifficultyBlocks() bool {
+	switch n {
+	case Testnet, Testnet4, Regtest:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,614 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtMagic is the magic prefix of each serialized PSBT: the `psbt` string
+// followed by the 0xff separator.
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// maxPsbtFieldByteLength is the maximum byte length of a single PSBT key or
+// value. It protects against allocating huge amounts of memory while
+// deserializing malformed PSBTs.
+const maxPsbtFieldByteLength = 4_000_000
+
+// PSBT key types, as defined by BIP-0174. Key types not listed here are
+// preserved as unknown key-value pairs.
+const (
+	psbtGlobalUnsignedTransaction = 0x00
+	psbtGlobalVersion             = 0xfb
+
+	psbtInputNonWitnessUtxo     = 0x00
+	psbtInputWitnessUtxo        = 0x01
+	psbtInputPartialSignature   = 0x02
+	psbtInputSighashType        = 0x03
+	psbtInputRedeemScript       = 0x04
+	psbtInputWitnessScript      = 0x05
+	psbtInputFinalScriptSig     = 0x07
+	psbtInputFinalScriptWitness = 0x08
+
+	psbtOutputRedeemScript  = 0x00
+	psbtOutputWitnessScript = 0x01
+)
+
+// Psbt represents a Partially Signed Bitcoin Transaction in version 0 of the
+// format defined by BIP-0174. It is used to exchange unsigned transactions
+// with external tools, e.g. hardware wallets. For reference, see:
+// https://github.com/bitcoin/bips/blob/master/bip-0174.mediawiki
+type Psbt struct {
+	// UnsignedTransaction is the transaction being signed. All its inputs
+	// must have empty signature scripts and witnesses.
+	UnsignedTransaction *Transaction
+	// Inputs holds signing data of the transaction inputs. The input with
+	// the given index corresponds to the transaction input with the same
+	// index.
+	Inputs []*PsbtInput
+	// Outputs holds data of the transaction outputs. The output with the
+	// given index corresponds to the transaction output with the same index.
+	Outputs []*PsbtOutput
+	// Unknowns holds global key-value pairs not interpreted by this package,
+	// e.g. extended public keys. They are preserved as is.
+	Unknowns []*PsbtKeyValue
+}
+
+// PsbtInput holds signing data of a single PSBT input.
+type PsbtInput struct {
+	// NonWitnessUtxo is the whole transaction holding the UTXO pointed by the
+	// input. It is required for non-witness inputs.
+	NonWitnessUtxo *Transaction
+	// WitnessUtxo is the UTXO pointed by the input. It is used for witness
+	// inputs.
+	WitnessUtxo *TransactionOutput
+	// PartialSignatures holds signatures already made for the input.
+	PartialSignatures []*PsbtPartialSignature
+	// SighashType is the sighash type that must be used to sign the input.
+	// Zero means the sighash type is not set.
+	SighashType uint32
+	// RedeemScript is the plain-text redeem script of a P2SH input.
+	RedeemScript Script
+	// WitnessScript is the plain-text witness script of a P2WSH input.
+	WitnessScript Script
+	// FinalScriptSig is the complete signature script of a finalized input.
+	FinalScriptSig []byte
+	// FinalScriptWitness is the complete witness of a finalized input.
+	FinalScriptWitness [][]byte
+	// Unknowns holds key-value pairs of the input not interpreted by this
+	// package, e.g. BIP-0032 derivation paths. They are preserved as is.
+	Unknowns []*PsbtKeyValue
+}
+
+// PsbtOutput holds data of a single PSBT output.
+type PsbtOutput struct {
+	// RedeemScript is the plain-text redeem script of a P2SH output.
+	RedeemScript Script
+	// WitnessScript is the plain-text witness script of a P2WSH output.
+	WitnessScript Script
+	// Unknowns holds key-value pairs of the output not interpreted by this
+	// package, e.g. BIP-0032 derivation paths. They are preserved as is.
+	Unknowns []*PsbtKeyValue
+}
+
+// PsbtPartialSignature is a signature made for a PSBT input.
+type PsbtPartialSignature struct {
+	// PublicKey is the serialized public key the signature corresponds to.
+	PublicKey []byte
+	// Signature is the DER-encoded signature followed by the sighash type
+	// byte.
+	Signature []byte
+}
+
+// PsbtKeyValue is a raw PSBT key-value pair. The first byte of the key
+// denotes the key type.
+type PsbtKeyValue struct {
+	Key   []byte
+	Value []byte
+}
+
+// Serialize serializes the PSBT to a byte array using the binary format
+// defined by BIP-0174.
+func (p *Psbt) Serialize() ([]byte, error) {
+	if p.UnsignedTransaction == nil {
+		return nil, fmt.Errorf("unsigned transaction is not set")
+	}
+
+	if err := validatePsbtUnsignedTransaction(p.UnsignedTransaction); err != nil {
+		return nil, err
+	}
+
+	if len(p.Inputs) != len(p.UnsignedTransaction.Inputs) {
+		return nil, fmt.Errorf("wrong inputs count")
+	}
+
+	if len(p.Outputs) != len(p.UnsignedTransaction.Outputs) {
+		return nil, fmt.Errorf("wrong outputs count")
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.Write(psbtMagic)
+
+	// Global map.
+	writePsbtKeyValue(
+		buffer,
+		[]byte{psbtGlobalUnsignedTransaction},
+		p.UnsignedTransaction.Serialize(Standard),
+	)
+	writePsbtUnknowns(buffer, p.Unknowns)
+	buffer.WriteByte(0x00)
+
+	for _, input := range p.Inputs {
+		if input == nil {
+			input = &PsbtInput{}
+		}
+
+		if input.NonWitnessUtxo != nil {
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtInputNonWitnessUtxo},
+				input.NonWitnessUtxo.Serialize(),
+			)
+		}
+
+		if input.WitnessUtxo != nil {
+			value, err := serializePsbtWitnessUtxo(input.WitnessUtxo)
+			if err != nil {
+				return nil, err
+			}
+
+			writePsbtKeyValue(buffer, []byte{psbtInputWitnessUtxo}, value)
+		}
+
+		for _, partialSignature := range input.PartialSignatures {
+			writePsbtKeyValue(
+				buffer,
+				append(
+					[]byte{psbtInputPartialSignature},
+					partialSignature.PublicKey...,
+				),
+				partialSignature.Signature,
+			)
+		}
+
+		if input.SighashType != 0 {
+			value := make([]byte, 4)
+			binary.LittleEndian.PutUint32(value, input.SighashType)
+			writePsbtKeyValue(buffer, []byte{psbtInputSighashType}, value)
+		}
+
+		if len(input.RedeemScript) > 0 {
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtInputRedeemScript},
+				input.RedeemScript,
+			)
+		}
+
+		if len(input.WitnessScript) > 0 {
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtInputWitnessScript},
+				input.WitnessScript,
+			)
+		}
+
+		if len(input.FinalScriptSig) > 0 {
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtInputFinalScriptSig},
+				input.FinalScriptSig,
+			)
+		}
+
+		if len(input.FinalScriptWitness) > 0 {
+			value := new(bytes.Buffer)
+			if err := wire.WriteVarInt(
+				value,
+				0,
+				uint64(len(input.FinalScriptWitness)),
+			); err != nil {
+				return nil, err
+			}
+			for _, item := range input.FinalScriptWitness {
+				if err := wire.WriteVarBytes(value, 0, item); err != nil {
+					return nil, err
+				}
+			}
+
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtInputFinalScriptWitness},
+				value.Bytes(),
+			)
+		}
+
+		writePsbtUnknowns(buffer, input.Unknowns)
+		buffer.WriteByte(0x00)
+	}
+
+	for _, output := range p.Outputs {
+		if output == nil {
+			output = &PsbtOutput{}
+		}
+
+		if len(output.RedeemScript) > 0 {
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtOutputRedeemScript},
+				output.RedeemScript,
+			)
+		}
+
+		if len(output.WitnessScript) > 0 {
+			writePsbtKeyValue(
+				buffer,
+				[]byte{psbtOutputWitnessScript},
+				output.WitnessScript,
+			)
+		}
+
+		writePsbtUnknowns(buffer, output.Unknowns)
+		buffer.WriteByte(0x00)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// SerializeBase64 serializes the PSBT to the base64 encoding commonly used
+// to exchange PSBTs as text, e.g. by Bitcoin Core.
+func (p *Psbt) SerializeBase64() (string, error) {
+	serialized, err := p.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(serialized), nil
+}
+
+// Deserialize deserializes the given byte array in the binary format defined
+// by BIP-0174 to a Psbt.
+func (p *Psbt) Deserialize(data []byte) error {
+	if !bytes.HasPrefix(data, psbtMagic) {
+		return fmt.Errorf("invalid magic bytes")
+	}
+
+	reader := bytes.NewReader(data[len(psbtMagic):])
+
+	var unsignedTransaction *Transaction
+	var globalUnknowns []*PsbtKeyValue
+
+	err := readPsbtMap(reader, func(key []byte, value []byte) error {
+		switch key[0] {
+		case psbtGlobalUnsignedTransaction:
+			if len(key) != 1 {
+				return fmt.Errorf("invalid unsigned transaction key")
+			}
+
+			unsignedTransaction = new(Transaction)
+			if err := unsignedTransaction.Deserialize(value); err != nil {
+				return fmt.Errorf(
+					"cannot deserialize unsigned transaction: [%w]",
+					err,
+				)
+			}
+
+			return validatePsbtUnsignedTransaction(unsignedTransaction)
+		case psbtGlobalVersion:
+			if len(value) != 4 || binary.LittleEndian.Uint32(value) != 0 {
+				return fmt.Errorf("unsupported PSBT version")
+			}
+		}
+
+		globalUnknowns = append(globalUnknowns, &PsbtKeyValue{key, value})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot read global map: [%w]", err)
+	}
+
+	if unsignedTransaction == nil {
+		return fmt.Errorf("unsigned transaction is missing")
+	}
+
+	inputs := make([]*PsbtInput, len(unsignedTransaction.Inputs))
+	for i := range inputs {
+		input, err := readPsbtInput(
+			reader,
+			unsignedTransaction.Inputs[i].Outpoint,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot read input [%v]: [%w]", i, err)
+		}
+
+		inputs[i] = input
+	}
+
+	outputs := make([]*PsbtOutput, len(unsignedTransaction.Outputs))
+	for i := range outputs {
+		output, err := readPsbtOutput(reader)
+		if err != nil {
+			return fmt.Errorf("cannot read output [%v]: [%w]", i, err)
+		}
+
+		outputs[i] = output
+	}
+
+	if reader.Len() != 0 {
+		return fmt.Errorf("unexpected trailing data")
+	}
+
+	p.UnsignedTransaction = unsignedTransaction
+	p.Inputs = inputs
+	p.Outputs = outputs
+	p.Unknowns = globalUnknowns
+
+	return nil
+}
+
+// DeserializeBase64 deserializes the given base64-encoded PSBT to a Psbt.
+func (p *Psbt) DeserializeBase64(data string) error {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("cannot decode base64: [%w]", err)
+	}
+
+	return p.Deserialize(decoded)
+}
+
+func readPsbtInput(
+	reader *bytes.Reader,
+	outpoint *TransactionOutpoint,
+) (*PsbtInput, error) {
+	input := &PsbtInput{}
+
+	err := readPsbtMap(reader, func(key []byte, value []byte) error {
+		keyType := key[0]
+
+		switch keyType {
+		case psbtInputNonWitnessUtxo,
+			psbtInputWitnessUtxo,
+			psbtInputSighashType,
+			psbtInputRedeemScript,
+			psbtInputWitnessScript,
+			psbtInputFinalScriptSig,
+			psbtInputFinalScriptWitness:
+			if len(key) != 1 {
+				return fmt.Errorf("invalid key of type [%v]", keyType)
+			}
+		}
+
+		switch keyType {
+		case psbtInputNonWitnessUtxo:
+			transaction := new(Transaction)
+			if err := transaction.Deserialize(value); err != nil {
+				return fmt.Errorf(
+					"cannot deserialize non-witness UTXO: [%w]",
+					err,
+				)
+			}
+
+			if transaction.Hash() != outpoint.TransactionHash {
+				return fmt.Errorf(
+					"non-witness UTXO does not match the input outpoint",
+				)
+			}
+
+			input.NonWitnessUtxo = transaction
+		case psbtInputWitnessUtxo:
+			output, err := deserializePsbtWitnessUtxo(value)
+			if err != nil {
+				return err
+			}
+
+			input.WitnessUtxo = output
+		case psbtInputPartialSignature:
+			input.PartialSignatures = append(
+				input.PartialSignatures,
+				&PsbtPartialSignature{
+					PublicKey: key[1:],
+					Signature: value,
+				},
+			)
+		case psbtInputSighashType:
+			if len(value) != 4 {
+				return fmt.Errorf("invalid sighash type")
+			}
+
+			input.SighashType = binary.LittleEndian.Uint32(value)
+		case psbtInputRedeemScript:
+			input.RedeemScript = value
+		case psbtInputWitnessScript:
+			input.WitnessScript = value
+		case psbtInputFinalScriptSig:
+			input.FinalScriptSig = value
+		case psbtInputFinalScriptWitness:
+			witness, err := deserializePsbtWitness(value)
+			if err != nil {
+				return err
+			}
+
+			input.FinalScriptWitness = witness
+		default:
+			input.Unknowns = append(input.Unknowns, &PsbtKeyValue{key, value})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
+
+func readPsbtOutput(reader *bytes.Reader) (*PsbtOutput, error) {
+	output := &PsbtOutput{}
+
+	err := readPsbtMap(reader, func(key []byte, value []byte) error {
+		switch key[0] {
+		case psbtOutputRedeemScript:
+			if len(key) != 1 {
+				return fmt.Errorf("invalid redeem script key")
+			}
+
+			output.RedeemScript = value
+		case psbtOutputWitnessScript:
+			if len(key) != 1 {
+				return fmt.Errorf("invalid witness script key")
+			}
+
+			output.WitnessScript = value
+		default:
+			output.Unknowns = append(output.Unknowns, &PsbtKeyValue{key, value})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// readPsbtMap reads key-value pairs of a single PSBT map and passes them to
+// the given handler until the map separator is reached. Duplicated keys are
+// rejected.
+func readPsbtMap(
+	reader *bytes.Reader,
+	handler func(key []byte, value []byte) error,
+) error {
+	seenKeys := make(map[string]bool)
+
+	for {
+		key, err := wire.ReadVarBytes(
+			reader,
+			0,
+			maxPsbtFieldByteLength,
+			"psbt key",
+		)
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("unexpected end of data")
+			}
+			return fmt.Errorf("cannot read key: [%w]", err)
+		}
+
+		// An empty key is the map separator.
+		if len(key) == 0 {
+			return nil
+		}
+
+		if seenKeys[string(key)] {
+			return fmt.Errorf("duplicated key of type [%v]", key[0])
+		}
+		seenKeys[string(key)] = true
+
+		value, err := wire.ReadVarBytes(
+			reader,
+			0,
+			maxPsbtFieldByteLength,
+			"psbt value",
+		)
+		if err != nil {
+			return fmt.Errorf("cannot read value: [%w]", err)
+		}
+
+		if err := handler(key, value); err != nil {
+			return err
+		}
+	}
+}
+
+func writePsbtKeyValue(buffer *bytes.Buffer, key []byte, value []byte) {
+	// Writing to a bytes.Buffer never fails.
+	_ = wire.WriteVarBytes(buffer, 0, key)
+	_ = wire.WriteVarBytes(buffer, 0, value)
+}
+
+func writePsbtUnknowns(buffer *bytes.Buffer, unknowns []*PsbtKeyValue) {
+	for _, unknown := range unknowns {
+		writePsbtKeyValue(buffer, unknown.Key, unknown.Value)
+	}
+}
+
+// validatePsbtUnsignedTransaction makes sure the given transaction does not
+// contain any signature data, as required by BIP-0174.
+func validatePsbtUnsignedTransaction(transaction *Transaction) error {
+	for i, input := range transaction.Inputs {
+		if len(input.SignatureScript) > 0 || len(input.Witness) > 0 {
+			return fmt.Errorf(
+				"input [%v] of the unsigned transaction is signed",
+				i,
+			)
+		}
+	}
+
+	return nil
+}
+
+func serializePsbtWitnessUtxo(output *TransactionOutput) ([]byte, error) {
+	script, err := output.PublicKeyScript.ToVarLenData()
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize witness UTXO: [%w]", err)
+	}
+
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, uint64(output.Value))
+
+	return append(value, script...), nil
+}
+
+func deserializePsbtWitnessUtxo(data []byte) (*TransactionOutput, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("invalid witness UTXO")
+	}
+
+	script, err := NewScriptFromVarLenData(data[8:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness UTXO script: [%w]", err)
+	}
+
+	return &TransactionOutput{
+		Value:           int64(binary.LittleEndian.Uint64(data[:8])),
+		PublicKeyScript: script,
+	}, nil
+}
+
+func deserializePsbtWitness(data []byte) ([][]byte, error) {
+	reader := bytes.NewReader(data)
+
+	count, err := wire.ReadVarInt(reader, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read witness items count: [%w]", err)
+	}
+
+	// Each witness item takes at least one byte.
+	if count > uint64(len(data)) {
+		return nil, fmt.Errorf("invalid witness items count")
+	}
+
+	witness := make([][]byte, count)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(
+			reader,
+			0,
+			maxPsbtFieldByteLength,
+			"witness item",
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read witness item: [%w]", err)
+		}
+	}
+
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("unexpected trailing witness data")
+	}
+
+	return witness, nil
+}
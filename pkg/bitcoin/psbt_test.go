@@ -0,0 +1,274 @@
+package bitcoin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+// PSBT with one P2PKH input holding the non-witness UTXO and two outputs,
+// based on the valid test vectors of BIP-0174.
+const psbtTestVectorHex = "70736274ff0100750200000001268171371edff285e937adeea4b37b78000c0566cbb3ad64641713ca42171bf60000000000feffffff02d3dff505000000001976a914d0c59903c5bac2868760e90fd521a4665aa7652088ac00e1f5050000000017a9143545e6e33b832c47050f24d3eeb93c9c03948bc787b32e1300000100fda5010100000000010289a3c71eab4d20e0371bbba4cc698fa295c9463afa2e397f8533ccb62f9567e50100000017160014be18d152a9b012039daf3da7de4f53349eecb985ffffffff86f8aa43a71dff1448893a530a7237ef6b4608bbb2dd2d0171e63aec6a4890b40100000017160014fe3e9ef1a745e974d902c4355943abcb34bd5353ffffffff0200c2eb0b000000001976a91485cff1097fd9e008bb34af709c62197b38978a4888ac72fef84e2c00000017a914339725ba21efd62ac753a9bcd067d6c7a6a39d05870247304402202712be22e0270f394f568311dc7ca9a68970b8025fdd3b240229f07f8a5f3a240220018b38d7dcd314e734c9276bd6fb40f673325bc4baa144c800d2f2f02db2765c012103d2e15674941bad4a996372cb87e1856d3652606d98562fe39c5e9e7e413f210502483045022100d12b852d85dcd961d2f5f4ab660654df6eedcc794c0c33ce5cc309ffb5fce58d022067338a8e0e1725c197fb1a88af59f51e44e4255b20167c8684031c05d1f2592a01210223b72beef0965d10be0778efecd61fcac6f79a4ea169393380734464f84f2ab300000000000000"
+
+func TestPsbt_Deserialize(t *testing.T) {
+	data := hexToSlice(t, psbtTestVectorHex)
+
+	psbt := &Psbt{}
+	if err := psbt.Deserialize(data); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"inputs count",
+		1,
+		len(psbt.Inputs),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"outputs count",
+		2,
+		len(psbt.Outputs),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"non-witness UTXO hash",
+		psbt.UnsignedTransaction.Inputs[0].Outpoint.TransactionHash.Hex(InternalByteOrder),
+		psbt.Inputs[0].NonWitnessUtxo.Hash().Hex(InternalByteOrder),
+	)
+
+	serialized, err := psbt.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertBytesEqual(t, data, serialized)
+}
+
+func TestPsbt_SerializeDeserialize(t *testing.T) {
+	unsignedTransaction := transactionFrom(
+		t,
+		"0100000001e19612be756bf7e740b47bec0e24845089ace48c78d473cb34949b3007c4a2c80000000000ffffffff0108840000000000001600148db50eb52063ea9d98b3eac91489a90f738986f600000000",
+	)
+
+	psbt := &Psbt{
+		UnsignedTransaction: unsignedTransaction,
+		Inputs: []*PsbtInput{
+			{
+				WitnessUtxo: &TransactionOutput{
+					Value:           35400,
+					PublicKeyScript: hexToSlice(t, "00148db50eb52063ea9d98b3eac91489a90f738986f6"),
+				},
+				PartialSignatures: []*PsbtPartialSignature{
+					{
+						PublicKey: hexToSlice(t, "03989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d9"),
+						Signature: hexToSlice(t, "3044022072109558ed0ad905e3853df8a987bb1353c0b3935b30c568763820c711600657022051ebcb9f03897f9c508d66d1c587cd81d888994e3b0bf819a9ef3b2df934328c01"),
+					},
+				},
+				SighashType:   1,
+				WitnessScript: hexToSlice(t, "76a9148db50eb52063ea9d98b3eac91489a90f738986f688ac"),
+				FinalScriptWitness: [][]byte{
+					hexToSlice(t, "01"),
+					hexToSlice(t, "0203"),
+				},
+				Unknowns: []*PsbtKeyValue{
+					{Key: hexToSlice(t, "06aa"), Value: hexToSlice(t, "bb")},
+				},
+			},
+		},
+		Outputs: []*PsbtOutput{
+			{
+				RedeemScript: hexToSlice(t, "0014e257eccafbc07c381642ce6e7e55120fb077fbed"),
+				Unknowns: []*PsbtKeyValue{
+					{Key: hexToSlice(t, "02cc"), Value: hexToSlice(t, "dd")},
+				},
+			},
+		},
+		Unknowns: []*PsbtKeyValue{
+			{Key: hexToSlice(t, "01ee"), Value: hexToSlice(t, "ff")},
+		},
+	}
+
+	serialized, err := psbt.SerializeBase64()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deserialized := &Psbt{}
+	if err := deserialized.DeserializeBase64(serialized); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(psbt, deserialized) {
+		t.Errorf(
+			"unexpected PSBT\nexpected: %+v\nactual:   %+v",
+			psbt,
+			deserialized,
+		)
+	}
+}
+
+func TestPsbt_Deserialize_Errors(t *testing.T) {
+	unsignedTransactionHex := "0100000001e19612be756bf7e740b47bec0e24845089ace48c78d473cb34949b3007c4a2c80000000000ffffffff0108840000000000001600148db50eb52063ea9d98b3eac91489a90f738986f600000000"
+	signedTransactionHex := "0100000001e19612be756bf7e740b47bec0e24845089ace48c78d473cb34949b3007c4a2c8000000000151ffffffff0108840000000000001600148db50eb52063ea9d98b3eac91489a90f738986f600000000"
+
+	globalMap := func(transactionHex string) string {
+		return fmt.Sprintf(
+			"0100%x%s",
+			len(transactionHex)/2,
+			transactionHex,
+		)
+	}
+
+	var tests = map[string]struct {
+		dataHex       string
+		expectedError error
+	}{
+		"invalid magic": {
+			dataHex:       "70736274fe" + globalMap(unsignedTransactionHex) + "000000",
+			expectedError: fmt.Errorf("invalid magic bytes"),
+		},
+		"missing unsigned transaction": {
+			dataHex:       "70736274ff" + "00",
+			expectedError: fmt.Errorf("unsigned transaction is missing"),
+		},
+		"signed unsigned transaction": {
+			dataHex: "70736274ff" + globalMap(signedTransactionHex) + "000000",
+			expectedError: fmt.Errorf(
+				"cannot read global map: [input [0] of the unsigned " +
+					"transaction is signed]",
+			),
+		},
+		"duplicated key": {
+			dataHex: "70736274ff" + globalMap(unsignedTransactionHex) + "00" +
+				"0103" + "0401000000" + "0103" + "0401000000" + "00" + "00",
+			expectedError: fmt.Errorf(
+				"cannot read input [0]: [duplicated key of type [3]]",
+			),
+		},
+		"missing output map": {
+			dataHex: "70736274ff" + globalMap(unsignedTransactionHex) + "00" +
+				"00",
+			expectedError: fmt.Errorf(
+				"cannot read output [0]: [unexpected end of data]",
+			),
+		},
+		"trailing data": {
+			dataHex: "70736274ff" + globalMap(unsignedTransactionHex) + "00" +
+				"00" + "00" + "00",
+			expectedError: fmt.Errorf("unexpected trailing data"),
+		},
+		"unsupported version": {
+			dataHex: "70736274ff" + globalMap(unsignedTransactionHex) +
+				"01fb" + "0402000000" + "00" + "00" + "00",
+			expectedError: fmt.Errorf(
+				"cannot read global map: [unsupported PSBT version]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			psbt := &Psbt{}
+			err := psbt.Deserialize(hexToSlice(t, test.dataHex))
+
+			if !reflect.DeepEqual(
+				fmt.Sprintf("%v", test.expectedError),
+				fmt.Sprintf("%v", err),
+			) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestNewTransactionBuilderFromPsbt_Errors(t *testing.T) {
+	unsignedTransaction := transactionFrom(
+		t,
+		"0100000001e19612be756bf7e740b47bec0e24845089ace48c78d473cb34949b3007c4a2c80000000000ffffffff0108840000000000001600148db50eb52063ea9d98b3eac91489a90f738986f600000000",
+	)
+
+	p2wpkhUtxo := &TransactionOutput{
+		Value:           35400,
+		PublicKeyScript: hexToSlice(t, "00148db50eb52063ea9d98b3eac91489a90f738986f6"),
+	}
+	p2wshUtxo := &TransactionOutput{
+		Value:           35400,
+		PublicKeyScript: hexToSlice(t, "002086a303cdd2e2eab1d1679f1a813835dc5a1b65321077cdccaf08f98cbf04ca96"),
+	}
+
+	var tests = map[string]struct {
+		input         *PsbtInput
+		expectedError error
+	}{
+		"missing UTXO": {
+			input: &PsbtInput{},
+			expectedError: fmt.Errorf(
+				"cannot import input [0]: [UTXO data is missing]",
+			),
+		},
+		"unsupported sighash type": {
+			input: &PsbtInput{
+				WitnessUtxo: p2wpkhUtxo,
+				SighashType: 0x83,
+			},
+			expectedError: fmt.Errorf(
+				"cannot import input [0]: [unsupported sighash type [131]]",
+			),
+		},
+		"missing witness script": {
+			input: &PsbtInput{
+				WitnessUtxo: p2wshUtxo,
+			},
+			expectedError: fmt.Errorf(
+				"cannot import input [0]: [witness script is missing]",
+			),
+		},
+		"mismatched witness script": {
+			input: &PsbtInput{
+				WitnessUtxo:   p2wshUtxo,
+				WitnessScript: hexToSlice(t, "51"),
+			},
+			expectedError: fmt.Errorf(
+				"cannot import input [0]: [witness script does not match " +
+					"the UTXO]",
+			),
+		},
+		"finalized input": {
+			input: &PsbtInput{
+				WitnessUtxo:        p2wpkhUtxo,
+				FinalScriptWitness: [][]byte{{0x01}},
+			},
+			expectedError: fmt.Errorf(
+				"cannot import input [0]: [input is already finalized]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := NewTransactionBuilderFromPsbt(
+				nil,
+				&Psbt{
+					UnsignedTransaction: unsignedTransaction,
+					Inputs:              []*PsbtInput{test.input},
+					Outputs:             []*PsbtOutput{{}},
+				},
+			)
+
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
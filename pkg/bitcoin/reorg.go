@@ -0,0 +1,194 @@
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// ReorgDetectionPollInterval is the interval used to poll chain backends
+	// for recent block hashes by WatchReorgs.
+	ReorgDetectionPollInterval = 1 * time.Minute
+
+	// ReorgDetectionDepth is the number of recent blocks tracked by
+	// WatchReorgs. Reorganizations deeper than that are not detected.
+	ReorgDetectionDepth = 6
+)
+
+// ReorgEvent is emitted once a previously seen block disappears from the
+// chain of a backend.
+type ReorgEvent struct {
+	// Backend identifies the backend the reorganization was observed on.
+	Backend string
+	// ForkHeight is the height of the lowest disappeared block.
+	ForkHeight uint
+	// DisappearedBlocks are hashes of the disappeared blocks, ordered by
+	// block height in the ascending order.
+	DisappearedBlocks []Hash
+}
+
+// ReorgWatcher is an optional interface implemented by Chain implementations
+// able to detect chain reorganizations on their own, e.g. separately for
+// each of their backends.
+type ReorgWatcher interface {
+	// WatchReorgs watches the chain for reorganizations until the given
+	// context is done. The returned channel receives an event each time
+	// a previously seen block disappears. The channel is closed once the
+	// context is done.
+	WatchReorgs(ctx context.Context) <-chan *ReorgEvent
+}
+
+// WatchReorgs watches the given chain for reorganizations until the given
+// context is done. The chain detects reorganizations on its own if it
+// implements ReorgWatcher. Otherwise, the chain is polled for recent block
+// hashes every ReorgDetectionPollInterval. See ReorgWatcher.WatchReorgs for
+// the semantics of the returned channel.
+func WatchReorgs(ctx context.Context, chain Chain) <-chan *ReorgEvent {
+	if watcher, ok := chain.(ReorgWatcher); ok {
+		return watcher.WatchReorgs(ctx)
+	}
+
+	return RunReorgDetectors(
+		ctx,
+		ReorgDetectionPollInterval,
+		NewReorgDetector("default", chain, ReorgDetectionDepth),
+	)
+}
+
+// ReorgDetector tracks hashes of recent blocks of a single chain backend
+// and detects when any of them disappears. It is not safe for concurrent
+// use.
+type ReorgDetector struct {
+	backend string
+	chain   Chain
+	depth   uint
+
+	// blocks holds hashes of recent blocks by their heights.
+	blocks map[uint]Hash
+}
+
+// NewReorgDetector creates a detector tracking the given number of recent
+// blocks of the given chain backend. The backend name is used to label
+// emitted events.
+func NewReorgDetector(backend string, chain Chain, depth uint) *ReorgDetector {
+	return &ReorgDetector{
+		backend: backend,
+		chain:   chain,
+		depth:   depth,
+		blocks:  make(map[uint]Hash),
+	}
+}
+
+// Check fetches hashes of recent blocks and compares them with the hashes
+// seen during the previous check. If any previously seen block disappeared,
+// that is, a different block is at its height now or the chain got shorter,
+// an event is returned. Otherwise, nil is returned.
+func (rd *ReorgDetector) Check() (*ReorgEvent, error) {
+	latestBlockHeight, err := rd.chain.GetLatestBlockHeight()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get latest block height: [%w]", err)
+	}
+
+	count := rd.depth
+	if count > latestBlockHeight+1 {
+		count = latestBlockHeight + 1
+	}
+	startBlockHeight := latestBlockHeight + 1 - count
+
+	headers, err := GetBlockHeaders(rd.chain, startBlockHeight, count)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get recent block headers: [%w]", err)
+	}
+
+	blocks := make(map[uint]Hash, len(headers))
+	for i, header := range headers {
+		blocks[startBlockHeight+uint(i)] = header.Hash()
+	}
+
+	var disappearedHeights []uint
+	for height, hash := range rd.blocks {
+		if height > latestBlockHeight {
+			disappearedHeights = append(disappearedHeights, height)
+			continue
+		}
+
+		if currentHash, ok := blocks[height]; ok && currentHash != hash {
+			disappearedHeights = append(disappearedHeights, height)
+		}
+	}
+
+	previousBlocks := rd.blocks
+	rd.blocks = blocks
+
+	if len(disappearedHeights) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(disappearedHeights, func(i, j int) bool {
+		return disappearedHeights[i] < disappearedHeights[j]
+	})
+
+	disappearedBlocks := make([]Hash, len(disappearedHeights))
+	for i, height := range disappearedHeights {
+		disappearedBlocks[i] = previousBlocks[height]
+	}
+
+	return &ReorgEvent{
+		Backend:           rd.backend,
+		ForkHeight:        disappearedHeights[0],
+		DisappearedBlocks: disappearedBlocks,
+	}, nil
+}
+
+// RunReorgDetectors runs the given detectors until the given context is
+// done. Each detector checks its backend every given poll interval. Checks
+// that fail are retried with the next poll. The returned channel receives
+// events emitted by all detectors and is closed once the context is done.
+func RunReorgDetectors(
+	ctx context.Context,
+	pollInterval time.Duration,
+	detectors ...*ReorgDetector,
+) <-chan *ReorgEvent {
+	eventsChan := make(chan *ReorgEvent, len(detectors))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(detectors))
+
+	for _, detector := range detectors {
+		go func(detector *ReorgDetector) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(pollInterval)
+			defer ticker.Stop()
+
+			for {
+				event, err := detector.Check()
+				// Errors are most likely transient; the check is retried
+				// with the next poll.
+				if err == nil && event != nil {
+					select {
+					case eventsChan <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(detector)
+	}
+
+	go func() {
+		wg.Wait()
+		close(eventsChan)
+	}()
+
+	return eventsChan
+}
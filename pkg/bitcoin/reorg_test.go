@@ -0,0 +1,146 @@
+package bitcoin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestReorgDetector_Check(t *testing.T) {
+	chain := newLocalChain()
+
+	setBlocks := func(fromHeight uint, toHeight uint, nonce uint32) {
+		chain.blockHeadersMutex.Lock()
+		defer chain.blockHeadersMutex.Unlock()
+
+		for height := fromHeight; height <= toHeight; height++ {
+			chain.blockHeaders[height] = &BlockHeader{
+				Nonce: nonce + uint32(height),
+			}
+		}
+	}
+
+	removeBlock := func(height uint) {
+		chain.blockHeadersMutex.Lock()
+		defer chain.blockHeadersMutex.Unlock()
+
+		delete(chain.blockHeaders, height)
+	}
+
+	blockHash := func(height uint, nonce uint32) Hash {
+		return (&BlockHeader{Nonce: nonce + uint32(height)}).Hash()
+	}
+
+	checkNoReorg := func(detector *ReorgDetector) {
+		event, err := detector.Check()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event != nil {
+			t.Fatalf("unexpected reorg event: [%+v]", event)
+		}
+	}
+
+	setBlocks(1, 10, 0)
+
+	detector := NewReorgDetector("test", chain, 3)
+
+	// The first check only records the baseline.
+	checkNoReorg(detector)
+
+	// The chain was extended so no reorg is expected.
+	setBlocks(11, 11, 0)
+	checkNoReorg(detector)
+
+	// Blocks 10 and 11 were replaced.
+	setBlocks(10, 12, 1000)
+
+	event, err := detector.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEvent := &ReorgEvent{
+		Backend:           "test",
+		ForkHeight:        10,
+		DisappearedBlocks: []Hash{blockHash(10, 0), blockHash(11, 0)},
+	}
+	if !reflect.DeepEqual(expectedEvent, event) {
+		t.Errorf(
+			"unexpected reorg event\nexpected: %+v\nactual:   %+v",
+			expectedEvent,
+			event,
+		)
+	}
+
+	// The chain got shorter.
+	removeBlock(12)
+
+	event, err = detector.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEvent = &ReorgEvent{
+		Backend:           "test",
+		ForkHeight:        12,
+		DisappearedBlocks: []Hash{blockHash(12, 1000)},
+	}
+	if !reflect.DeepEqual(expectedEvent, event) {
+		t.Errorf(
+			"unexpected reorg event\nexpected: %+v\nactual:   %+v",
+			expectedEvent,
+			event,
+		)
+	}
+
+	checkNoReorg(detector)
+}
+
+func TestRunReorgDetectors(t *testing.T) {
+	chain := newLocalChain()
+	chain.blockHeaders[1] = &BlockHeader{Nonce: 1}
+	chain.blockHeaders[2] = &BlockHeader{Nonce: 2}
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
+	eventsChan := RunReorgDetectors(
+		ctx,
+		10*time.Millisecond,
+		NewReorgDetector("test", chain, 2),
+	)
+
+	// Let the detector record the baseline.
+	time.Sleep(50 * time.Millisecond)
+
+	chain.blockHeadersMutex.Lock()
+	chain.blockHeaders[2] = &BlockHeader{Nonce: 22}
+	chain.blockHeadersMutex.Unlock()
+
+	select {
+	case event := <-eventsChan:
+		testutils.AssertStringsEqual(t, "backend", "test", event.Backend)
+		testutils.AssertUintsEqual(
+			t,
+			"fork height",
+			2,
+			uint64(event.ForkHeight),
+		)
+	case <-time.After(time.Second):
+		t.Fatal("expected reorg event")
+	}
+
+	cancelCtx()
+
+	select {
+	case _, ok := <-eventsChan:
+		if ok {
+			t.Fatal("expected closed events channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected closed events channel")
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/elliptic"
 	"crypto/sha256"
 	"fmt"
+	"math/big"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcutil"
 )
@@ -19,6 +21,7 @@ const (
 	P2WPKHScript
 	P2SHScript
 	P2WSHScript
+	P2TRScript
 )
 
 func (st ScriptType) String() string {
@@ -31,6 +34,8 @@ func (st ScriptType) String() string {
 		return "P2SH"
 	case P2WSHScript:
 		return "P2WSH"
+	case P2TRScript:
+		return "P2TR"
 	default:
 		return "NonStandard"
 	}
@@ -147,8 +152,77 @@ func PayToScriptHash(scriptHash [20]byte) (Script, error) {
 		Script()
 }
 
+// TaprootOutputKey constructs the 32-byte x-only taproot output key for the
+// provided internal public key, according to BIP-0086. That is, the output
+// key commits to no script tree so the output can be spent only using the
+// key path. For reference see,
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#address-derivation.
+func TaprootOutputKey(internalKey *ecdsa.PublicKey) [32]byte {
+	curve := btcec.S256()
+
+	// BIP-0340 uses x-only public keys that implicitly have an even Y
+	// coordinate. Negate the internal key if its Y coordinate is odd.
+	internalX := internalKey.X
+	internalY := internalKey.Y
+	if internalY.Bit(0) == 1 {
+		internalY = new(big.Int).Sub(curve.P, internalY)
+	}
+
+	var internalXBytes [32]byte
+	internalX.FillBytes(internalXBytes[:])
+
+	tweak := taggedHash("TapTweak", internalXBytes[:])
+
+	tweakX, tweakY := curve.ScalarBaseMult(tweak[:])
+	outputX, _ := curve.Add(internalX, internalY, tweakX, tweakY)
+
+	var result [32]byte
+	outputX.FillBytes(result[:])
+
+	return result
+}
+
+// taggedHash computes the BIP-0340 tagged hash of the provided message, i.e.
+// SHA-256(SHA-256(tag) || SHA-256(tag) || message).
+func taggedHash(tag string, message []byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	hash := sha256.New()
+	hash.Write(tagHash[:])
+	hash.Write(tagHash[:])
+	hash.Write(message)
+
+	var result [32]byte
+	copy(result[:], hash.Sum(nil))
+
+	return result
+}
+
+// PayToTaproot constructs a P2TR script for the provided 32-byte x-only
+// taproot output key. The function assumes the provided output key is valid.
+func PayToTaproot(outputKey [32]byte) (Script, error) {
+	return txscript.NewScriptBuilder().
+		AddOp(txscript.OP_1).
+		AddData(outputKey[:]).
+		Script()
+}
+
+// isPayToTaproot checks whether the given script is a P2TR script, i.e. a
+// version 1 witness program with a 32-byte output key.
+func isPayToTaproot(script Script) bool {
+	return len(script) == 34 &&
+		script[0] == txscript.OP_1 &&
+		script[1] == txscript.OP_DATA_32
+}
+
 // GetScriptType gets the ScriptType of the given Script.
 func GetScriptType(script Script) ScriptType {
+	// The txscript package used here predates taproot so P2TR scripts must
+	// be recognized separately.
+	if isPayToTaproot(script) {
+		return P2TRScript
+	}
+
 	switch txscript.GetScriptClass(script) {
 	case txscript.PubKeyHashTy:
 		return P2PKHScript
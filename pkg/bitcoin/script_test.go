@@ -334,6 +334,70 @@ func TestPayToScriptHash(t *testing.T) {
 	testutils.AssertBytesEqual(t, expectedResult, result[:])
 }
 
+func TestTaprootOutputKey(t *testing.T) {
+	// Test vector of the first receiving address of BIP-0086:
+	// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+	internalKeyBytes, err := hex.DecodeString(
+		"02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	internalKey, err := btcec.ParsePubKey(internalKeyBytes, btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResult, err := hex.DecodeString(
+		"a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := TaprootOutputKey(internalKey.ToECDSA())
+	testutils.AssertBytesEqual(t, expectedResult, result[:])
+
+	// The internal key with the odd Y coordinate should produce the same
+	// output key as x-only keys are used.
+	oddInternalKeyBytes := append([]byte{0x03}, internalKeyBytes[1:]...)
+	oddInternalKey, err := btcec.ParsePubKey(oddInternalKeyBytes, btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result = TaprootOutputKey(oddInternalKey.ToECDSA())
+	testutils.AssertBytesEqual(t, expectedResult, result[:])
+}
+
+func TestPayToTaproot(t *testing.T) {
+	// The 32-byte output key, same as the output of TestTaprootOutputKey.
+	outputKeyBytes, err := hex.DecodeString(
+		"a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var outputKey [32]byte
+	copy(outputKey[:], outputKeyBytes)
+
+	result, err := PayToTaproot(outputKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResult, err := hex.DecodeString(
+		"5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertBytesEqual(t, expectedResult, result[:])
+}
+
 func TestGetScriptType(t *testing.T) {
 	fromHex := func(hexString string) []byte {
 		bytes, err := hex.DecodeString(hexString)
@@ -363,6 +427,10 @@ func TestGetScriptType(t *testing.T) {
 			script:       fromHex("002086a303cdd2e2eab1d1679f1a813835dc5a1b65321077cdccaf08f98cbf04ca96"),
 			expectedType: P2WSHScript,
 		},
+		"p2tr script": {
+			script:       fromHex("5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"),
+			expectedType: P2TRScript,
+		},
 		"non-standard script": {
 			script: fromHex(
 				"14934b98637ca318a4d6e7ca6ffd1690b8e77df6377508f9f0c90d0003" +
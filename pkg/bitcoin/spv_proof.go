@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sync"
 
 	"github.com/keep-network/keep-core/pkg/internal/byteutils"
 )
@@ -38,8 +39,55 @@ func AssembleSpvProof(
 	requiredConfirmations uint,
 	btcChain Chain,
 ) (*Transaction, *SpvProof, error) {
-	confirmations, err := btcChain.GetTransactionConfirmations(
+	return AssembleSpvProofWithHeaders(
 		transactionHash,
+		requiredConfirmations,
+		btcChain,
+		nil,
+	)
+}
+
+// AssembleSpvProofWithHeaders assembles a proof that a given transaction was
+// included in the blockchain and has accumulated the required number of
+// confirmations. The given precomputed block headers, keyed by block height,
+// are used instead of fetching them from the chain. Headers missing in the
+// given map are fetched. The map can be nil.
+//
+// Independent data are fetched concurrently so the proof is assembled in
+// three rounds of requests: the transaction and its confirmations, then the
+// headers chain along with the Merkle proofs and the coinbase transaction
+// hash, and finally the coinbase transaction.
+func AssembleSpvProofWithHeaders(
+	transactionHash Hash,
+	requiredConfirmations uint,
+	btcChain Chain,
+	precomputedHeaders map[uint]*BlockHeader,
+) (*Transaction, *SpvProof, error) {
+	var (
+		transaction       *Transaction
+		confirmations     uint
+		latestBlockHeight uint
+	)
+
+	// The latest block height must be fetched after the confirmations.
+	// Otherwise, a block mined in between would shift the computed
+	// transaction block height.
+	err := runConcurrently(
+		func() (err error) {
+			transaction, err = btcChain.GetTransaction(transactionHash)
+			return err
+		},
+		func() (err error) {
+			confirmations, err = btcChain.GetTransactionConfirmations(
+				transactionHash,
+			)
+			if err != nil {
+				return err
+			}
+
+			latestBlockHeight, err = btcChain.GetLatestBlockHeight()
+			return err
+		},
 	)
 	if err != nil {
 		return nil, nil, err
@@ -53,30 +101,39 @@ func AssembleSpvProof(
 		)
 	}
 
-	transaction, err := btcChain.GetTransaction(transactionHash)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	latestBlockHeight, err := btcChain.GetLatestBlockHeight()
-	if err != nil {
-		return nil, nil, err
-	}
-
 	txBlockHeight := latestBlockHeight - confirmations + 1
 
-	headersChain, err := getHeadersChain(
-		btcChain,
-		txBlockHeight,
-		requiredConfirmations,
+	var (
+		headersChain   []byte
+		merkleBranch   *TransactionMerkleProof
+		coinbaseTxHash Hash
 	)
-	if err != nil {
-		return nil, nil, err
-	}
 
-	merkleBranch, err := btcChain.GetTransactionMerkleProof(
-		transactionHash,
-		txBlockHeight,
+	err = runConcurrently(
+		func() (err error) {
+			headersChain, err = getHeadersChain(
+				btcChain,
+				txBlockHeight,
+				requiredConfirmations,
+				precomputedHeaders,
+			)
+			return err
+		},
+		func() (err error) {
+			merkleBranch, err = btcChain.GetTransactionMerkleProof(
+				transactionHash,
+				txBlockHeight,
+			)
+			return err
+		},
+		func() error {
+			hash, err := btcChain.GetCoinbaseTxHash(txBlockHeight)
+			if err != nil {
+				return fmt.Errorf("failed to get coinbase tx hash [%w]", err)
+			}
+			coinbaseTxHash = hash
+			return nil
+		},
 	)
 	if err != nil {
 		return nil, nil, err
@@ -87,26 +144,34 @@ func AssembleSpvProof(
 		return nil, nil, fmt.Errorf("failed to create Merkle proof [%w]", err)
 	}
 
-	coinbaseTxHash, err := btcChain.GetCoinbaseTxHash(txBlockHeight)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get coinbase tx hash [%w]", err)
-	}
-
-	coinbaseTx, err := btcChain.GetTransaction(coinbaseTxHash)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get coinbase tx [%w]", err)
-	}
-
-	coinbasePreimage := sha256.Sum256(coinbaseTx.Serialize(Standard))
+	var (
+		coinbaseTx           *Transaction
+		coinbaseMerkleBranch *TransactionMerkleProof
+	)
 
-	coinbaseMerkleBranch, err := btcChain.GetTransactionMerkleProof(
-		coinbaseTxHash,
-		txBlockHeight,
+	err = runConcurrently(
+		func() error {
+			tx, err := btcChain.GetTransaction(coinbaseTxHash)
+			if err != nil {
+				return fmt.Errorf("failed to get coinbase tx [%w]", err)
+			}
+			coinbaseTx = tx
+			return nil
+		},
+		func() (err error) {
+			coinbaseMerkleBranch, err = btcChain.GetTransactionMerkleProof(
+				coinbaseTxHash,
+				txBlockHeight,
+			)
+			return err
+		},
 	)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	coinbasePreimage := sha256.Sum256(coinbaseTx.Serialize(Standard))
+
 	coinbaseMerkleProof, err := createMerkleProof(coinbaseMerkleBranch)
 	if err != nil {
 		return nil, nil, fmt.Errorf(
@@ -126,6 +191,33 @@ func AssembleSpvProof(
 	return transaction, proof, nil
 }
 
+// runConcurrently runs the given functions concurrently and waits for all of
+// them to complete. The first error, in the order of the given functions, is
+// returned.
+func runConcurrently(fns ...func() error) error {
+	errs := make([]error, len(fns))
+
+	var wg sync.WaitGroup
+	wg.Add(len(fns))
+
+	for i, fn := range fns {
+		go func(i int, fn func() error) {
+			defer wg.Done()
+			errs[i] = fn()
+		}(i, fn)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // createMerkleProof creates a proof of transaction inclusion in the block by
 // concatenating 32-byte-long hash values. The values are converted to the
 // little endian form. The branch of a Merkle tree leading to a transaction
@@ -149,21 +241,52 @@ func createMerkleProof(txMerkleBranch *TransactionMerkleProof) (
 }
 
 // getHeadersChain gets a chain of Bitcoin block headers that starts at the
-// provided block height and has the specified chain length.
+// provided block height and has the specified chain length. The given
+// precomputed headers are used if available. Missing headers are fetched in
+// consecutive ranges. The headers are verified to form a chain.
 func getHeadersChain(
 	btcChain Chain,
 	blockHeight uint,
 	chainLength uint,
+	precomputedHeaders map[uint]*BlockHeader,
 ) ([]byte, error) {
-	// TODO: Consider exposing a function in the Bitcoin chain for returning
-	//       multiple block headers with one call.
-	var headersChain bytes.Buffer
+	blockHeaders := make([]*BlockHeader, chainLength)
+
+	for i := uint(0); i < chainLength; {
+		if header, ok := precomputedHeaders[blockHeight+i]; ok {
+			blockHeaders[i] = header
+			i++
+			continue
+		}
+
+		// Find the end of the range of missing headers.
+		j := i + 1
+		for ; j < chainLength; j++ {
+			if _, ok := precomputedHeaders[blockHeight+j]; ok {
+				break
+			}
+		}
 
-	for i := blockHeight; i < blockHeight+chainLength; i++ {
-		blockHeader, err := btcChain.GetBlockHeader(i)
+		fetchedHeaders, err := GetBlockHeaders(btcChain, blockHeight+i, j-i)
 		if err != nil {
 			return nil, err
 		}
+
+		copy(blockHeaders[i:j], fetchedHeaders)
+
+		i = j
+	}
+
+	var headersChain bytes.Buffer
+	for i, blockHeader := range blockHeaders {
+		if i > 0 &&
+			blockHeader.PreviousBlockHeaderHash != blockHeaders[i-1].Hash() {
+			return nil, fmt.Errorf(
+				"block header [%v] does not link to the previous one",
+				blockHeight+uint(i),
+			)
+		}
+
 		serializedBlockHeader := blockHeader.Serialize()
 		headersChain.Write(serializedBlockHeader[:])
 	}
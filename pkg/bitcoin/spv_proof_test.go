@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"encoding/hex"
+	"fmt"
 )
 
 // SpvProofData holds details of the transaction proof data used as a test
@@ -377,76 +378,179 @@ func decode32ByteString(s string) [32]byte {
 }
 
 func TestAssembleTransactionProof(t *testing.T) {
-	for testName, test := range SpvProofData {
-		t.Run(testName, func(t *testing.T) {
-			transaction := transactionFrom(t, test.BitcoinChainData.TransactionHex)
-			transactionHash := transaction.Hash()
-			requiredConfirmations := test.RequiredConfirmations
-			accumulatedConfirmations := test.BitcoinChainData.AccumulatedTxConfirmations
-			blockHeaders := test.BitcoinChainData.HeadersChain
-			transactionMerkleProof := test.BitcoinChainData.TransactionMerkleProof
-			expectedProof := test.ExpectedProof
-			expectedTx := transaction
+	type assembler func(
+		lc *localChain,
+		transactionHash Hash,
+		requiredConfirmations uint,
+		blockHeaders map[uint]*BlockHeader,
+	) (*Transaction, *SpvProof, error)
 
-			bitcoinChain := newLocalChain()
-			bitcoinChain.addTransaction(transaction)
-			bitcoinChain.addTransactionConfirmations(
+	assemblers := map[string]assembler{
+		"single block header requests": func(
+			lc *localChain,
+			transactionHash Hash,
+			requiredConfirmations uint,
+			blockHeaders map[uint]*BlockHeader,
+		) (*Transaction, *SpvProof, error) {
+			return AssembleSpvProof(transactionHash, requiredConfirmations, lc)
+		},
+		"batched block header requests": func(
+			lc *localChain,
+			transactionHash Hash,
+			requiredConfirmations uint,
+			blockHeaders map[uint]*BlockHeader,
+		) (*Transaction, *SpvProof, error) {
+			return AssembleSpvProof(
 				transactionHash,
-				accumulatedConfirmations,
+				requiredConfirmations,
+				&batchingLocalChain{lc},
 			)
-
-			var blockNumbers []uint
+		},
+		"precomputed block headers": func(
+			lc *localChain,
+			transactionHash Hash,
+			requiredConfirmations uint,
+			blockHeaders map[uint]*BlockHeader,
+		) (*Transaction, *SpvProof, error) {
+			// Supply every other header so that both precomputed and
+			// fetched headers are used.
+			precomputedHeaders := make(map[uint]*BlockHeader)
 			for blockNumber, blockHeader := range blockHeaders {
-				blockNumbers = append(blockNumbers, blockNumber)
-				bitcoinChain.addBlockHeader(blockNumber, blockHeader)
+				if blockNumber%2 == 0 {
+					precomputedHeaders[blockNumber] = blockHeader
+				}
 			}
-			slices.Sort(blockNumbers)
 
-			bitcoinChain.addTransactionMerkleProof(
+			return AssembleSpvProofWithHeaders(
 				transactionHash,
-				transactionMerkleProof,
+				requiredConfirmations,
+				&batchingLocalChain{lc},
+				precomputedHeaders,
 			)
+		},
+	}
 
-			coinbaseTransaction := transactionFrom(
-				t,
-				test.BitcoinChainData.CoinbaseTransactionHex,
-			)
-			coinbaseTransactionHash := coinbaseTransaction.Hash()
-			bitcoinChain.setCoinbaseTxHash(
-				blockNumbers[0],
-				coinbaseTransactionHash,
-			)
+	for testName, test := range SpvProofData {
+		for assemblerVariant, assemble := range assemblers {
+			testName := fmt.Sprintf("%s - %s", testName, assemblerVariant)
+			test := test
+			assemble := assemble
 
-			bitcoinChain.addTransaction(coinbaseTransaction)
+			t.Run(testName, func(t *testing.T) {
+				transaction := transactionFrom(t, test.BitcoinChainData.TransactionHex)
+				transactionHash := transaction.Hash()
+				requiredConfirmations := test.RequiredConfirmations
+				accumulatedConfirmations := test.BitcoinChainData.AccumulatedTxConfirmations
+				blockHeaders := test.BitcoinChainData.HeadersChain
+				transactionMerkleProof := test.BitcoinChainData.TransactionMerkleProof
+				expectedProof := test.ExpectedProof
+				expectedTx := transaction
 
-			bitcoinChain.addTransactionMerkleProof(
-				coinbaseTransactionHash,
-				test.BitcoinChainData.CoinbaseTransactionMerkleProof,
-			)
+				bitcoinChain := newLocalChain()
+				bitcoinChain.addTransaction(transaction)
+				bitcoinChain.addTransactionConfirmations(
+					transactionHash,
+					accumulatedConfirmations,
+				)
 
-			tx, proof, err := AssembleSpvProof(
-				transactionHash,
-				requiredConfirmations,
-				bitcoinChain,
-			)
-			if err != nil {
-				t.Fatal(err)
-			}
+				var blockNumbers []uint
+				for blockNumber, blockHeader := range blockHeaders {
+					blockNumbers = append(blockNumbers, blockNumber)
+					bitcoinChain.addBlockHeader(blockNumber, blockHeader)
+				}
+				slices.Sort(blockNumbers)
 
-			if !reflect.DeepEqual(expectedProof, proof) {
-				t.Errorf(
-					"unexpected proof\nexpected: %v\nactual:   %v\n",
-					expectedProof,
-					proof,
+				bitcoinChain.addTransactionMerkleProof(
+					transactionHash,
+					transactionMerkleProof,
 				)
-			}
-			if !reflect.DeepEqual(expectedTx, tx) {
-				t.Errorf(
-					"unexpected transaction\nexpected: %v\nactual:   %v\n",
-					expectedTx,
-					tx,
+
+				coinbaseTransaction := transactionFrom(
+					t,
+					test.BitcoinChainData.CoinbaseTransactionHex,
 				)
-			}
-		})
+				coinbaseTransactionHash := coinbaseTransaction.Hash()
+				bitcoinChain.setCoinbaseTxHash(
+					blockNumbers[0],
+					coinbaseTransactionHash,
+				)
+
+				bitcoinChain.addTransaction(coinbaseTransaction)
+
+				bitcoinChain.addTransactionMerkleProof(
+					coinbaseTransactionHash,
+					test.BitcoinChainData.CoinbaseTransactionMerkleProof,
+				)
+
+				tx, proof, err := assemble(
+					bitcoinChain,
+					transactionHash,
+					requiredConfirmations,
+					blockHeaders,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(expectedProof, proof) {
+					t.Errorf(
+						"unexpected proof\nexpected: %v\nactual:   %v\n",
+						expectedProof,
+						proof,
+					)
+				}
+				if !reflect.DeepEqual(expectedTx, tx) {
+					t.Errorf(
+						"unexpected transaction\nexpected: %v\nactual:   %v\n",
+						expectedTx,
+						tx,
+					)
+				}
+			})
+		}
+	}
+}
+
+// batchingLocalChain is a local chain that additionally implements the
+// BlockHeadersGetter interface.
+type batchingLocalChain struct {
+	*localChain
+}
+
+func (blc *batchingLocalChain) GetBlockHeaders(
+	startBlockHeight uint,
+	count uint,
+) ([]*BlockHeader, error) {
+	blockHeaders := make([]*BlockHeader, count)
+	for i := range blockHeaders {
+		blockHeader, err := blc.GetBlockHeader(startBlockHeight + uint(i))
+		if err != nil {
+			return nil, err
+		}
+		blockHeaders[i] = blockHeader
+	}
+
+	return blockHeaders, nil
+}
+
+func TestGetHeadersChain_NotLinked(t *testing.T) {
+	bitcoinChain := newLocalChain()
+	bitcoinChain.addBlockHeader(100, &BlockHeader{Version: 1})
+
+	precomputedHeaders := map[uint]*BlockHeader{
+		101: {Version: 1, PreviousBlockHeaderHash: Hash{0xff}},
+	}
+
+	_, err := getHeadersChain(bitcoinChain, 100, 2, precomputedHeaders)
+
+	expectedErr := fmt.Errorf(
+		"block header [101] does not link to the previous one",
+	)
+	if !reflect.DeepEqual(expectedErr, err) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedErr,
+			err,
+		)
 	}
 }
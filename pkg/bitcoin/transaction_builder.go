@@ -21,6 +21,9 @@ type TransactionBuilder struct {
 	internal    *internalTransaction
 	sigHashArgs []*inputSigHashArgs
 	sigHashes   []*big.Int
+	// utxos holds data of UTXOs pointed by the inputs. Elements are ordered
+	// in the same way as the inputs they correspond to.
+	utxos []*inputUtxo
 }
 
 // NewTransactionBuilder constructs a new TransactionBuilder instance.
@@ -29,6 +32,7 @@ func NewTransactionBuilder(chain Chain) *TransactionBuilder {
 		chain:       chain,
 		internal:    newInternalTransaction(),
 		sigHashArgs: make([]*inputSigHashArgs, 0),
+		utxos:       make([]*inputUtxo, 0),
 	}
 }
 
@@ -37,7 +41,7 @@ func NewTransactionBuilder(chain Chain) *TransactionBuilder {
 func (tb *TransactionBuilder) AddPublicKeyHashInput(
 	utxo *UnspentTransactionOutput,
 ) error {
-	utxoScript, err := tb.getScript(utxo)
+	utxoTransaction, err := tb.getTransaction(utxo)
 	if err != nil {
 		return fmt.Errorf(
 			"cannot get locking script for UTXO pointed "+
@@ -46,6 +50,8 @@ func (tb *TransactionBuilder) AddPublicKeyHashInput(
 		)
 	}
 
+	utxoScript := utxoTransaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript
+
 	class := txscript.GetScriptClass(utxoScript)
 	isPublicKeyHashScript := class == txscript.PubKeyHashTy ||
 		class == txscript.WitnessV0PubKeyHashTy
@@ -75,6 +81,10 @@ func (tb *TransactionBuilder) AddPublicKeyHashInput(
 	tb.internal.AddTxIn(wire.NewTxIn(outpoint, nil, nil))
 
 	tb.sigHashArgs = append(tb.sigHashArgs, sigHashArgs)
+	tb.utxos = append(tb.utxos, &inputUtxo{
+		transaction: utxoTransaction,
+		output:      utxoTransaction.Outputs[utxo.Outpoint.OutputIndex],
+	})
 
 	return nil
 }
@@ -86,7 +96,7 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 	utxo *UnspentTransactionOutput,
 	redeemScript Script,
 ) error {
-	utxoScript, err := tb.getScript(utxo)
+	utxoTransaction, err := tb.getTransaction(utxo)
 	if err != nil {
 		return fmt.Errorf(
 			"cannot get locking script for UTXO pointed "+
@@ -95,6 +105,8 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 		)
 	}
 
+	utxoScript := utxoTransaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript
+
 	class := txscript.GetScriptClass(utxoScript)
 	isPublicKeyHashScript := class == txscript.ScriptHashTy ||
 		class == txscript.WitnessV0ScriptHashTy
@@ -129,15 +141,19 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 	}
 
 	tb.sigHashArgs = append(tb.sigHashArgs, sigHashArgs)
+	tb.utxos = append(tb.utxos, &inputUtxo{
+		transaction: utxoTransaction,
+		output:      utxoTransaction.Outputs[utxo.Outpoint.OutputIndex],
+	})
 
 	return nil
 }
 
-// getScript gets the locking script (PublicKeyScript) for the given unspent
-// transaction output.
-func (tb *TransactionBuilder) getScript(
+// getTransaction gets the transaction holding the given unspent transaction
+// output.
+func (tb *TransactionBuilder) getTransaction(
 	utxo *UnspentTransactionOutput,
-) (Script, error) {
+) (*Transaction, error) {
 	hash := utxo.Outpoint.TransactionHash
 	transaction, err := tb.chain.GetTransaction(hash)
 	if err != nil {
@@ -148,7 +164,15 @@ func (tb *TransactionBuilder) getScript(
 		)
 	}
 
-	return transaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript, nil
+	if int(utxo.Outpoint.OutputIndex) >= len(transaction.Outputs) {
+		return nil, fmt.Errorf(
+			"transaction with hash [%s] has no output [%v]",
+			hash.Hex(InternalByteOrder),
+			utxo.Outpoint.OutputIndex,
+		)
+	}
+
+	return transaction, nil
 }
 
 // AddOutput adds a new transaction's output.
@@ -309,6 +333,208 @@ func (tb *TransactionBuilder) TotalInputsValue() int64 {
 	return totalInputsValue
 }
 
+// Psbt exports the unsigned transaction assembled by the builder as a PSBT
+// so it can be inspected or signed using external tools. Each input contains
+// the sighash type, the UTXO it points to, and the plain-text redeem or
+// witness script if applicable. Signatures added using AddSignatures are not
+// exported.
+func (tb *TransactionBuilder) Psbt() *Psbt {
+	unsignedTransaction := tb.internal.toTransaction()
+
+	inputs := make([]*PsbtInput, len(unsignedTransaction.Inputs))
+	for i, input := range unsignedTransaction.Inputs {
+		input.SignatureScript = nil
+		input.Witness = nil
+
+		sigHashArgs := tb.sigHashArgs[i]
+		utxo := tb.utxos[i]
+
+		psbtInput := &PsbtInput{
+			NonWitnessUtxo: utxo.transaction,
+			SighashType:    uint32(txscript.SigHashAll),
+		}
+
+		if sigHashArgs.witness {
+			psbtInput.WitnessUtxo = utxo.output
+		}
+
+		switch GetScriptType(utxo.output.PublicKeyScript) {
+		case P2SHScript:
+			psbtInput.RedeemScript = sigHashArgs.scriptCode
+		case P2WSHScript:
+			psbtInput.WitnessScript = sigHashArgs.scriptCode
+		}
+
+		inputs[i] = psbtInput
+	}
+
+	outputs := make([]*PsbtOutput, len(unsignedTransaction.Outputs))
+	for i := range outputs {
+		outputs[i] = &PsbtOutput{}
+	}
+
+	return &Psbt{
+		UnsignedTransaction: unsignedTransaction,
+		Inputs:              inputs,
+		Outputs:             outputs,
+	}
+}
+
+// NewTransactionBuilderFromPsbt constructs a new TransactionBuilder instance
+// holding the unsigned transaction of the given PSBT, e.g. one constructed
+// by external tools. The builder can be used to compute signature hashes and
+// apply signatures as usual. Only P2PKH, P2WPKH, P2SH, and P2WSH inputs
+// signed using the SIGHASH_ALL type are supported. Non-witness inputs must
+// contain the whole UTXO transaction while witness inputs must contain at
+// least the UTXO itself. Script hash inputs must contain the plain-text
+// redeem or witness script. Partial signatures held by the PSBT are ignored.
+func NewTransactionBuilderFromPsbt(
+	chain Chain,
+	psbt *Psbt,
+) (*TransactionBuilder, error) {
+	if psbt.UnsignedTransaction == nil {
+		return nil, fmt.Errorf("unsigned transaction is not set")
+	}
+
+	if err := validatePsbtUnsignedTransaction(psbt.UnsignedTransaction); err != nil {
+		return nil, err
+	}
+
+	if len(psbt.Inputs) != len(psbt.UnsignedTransaction.Inputs) {
+		return nil, fmt.Errorf("wrong inputs count")
+	}
+
+	tb := NewTransactionBuilder(chain)
+	tb.internal.fromTransaction(psbt.UnsignedTransaction)
+
+	for i, input := range tb.internal.TxIn {
+		psbtInput := psbt.Inputs[i]
+		if psbtInput == nil {
+			return nil, fmt.Errorf("input [%v] has no signing data", i)
+		}
+
+		sigHashArgs, utxo, err := importPsbtInput(
+			psbt.UnsignedTransaction.Inputs[i].Outpoint,
+			psbtInput,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cannot import input [%v]: [%v]", i, err)
+		}
+
+		// Pre-fill the plain-text redeem script the same way as
+		// AddScriptHashInput does.
+		switch GetScriptType(utxo.output.PublicKeyScript) {
+		case P2SHScript:
+			input.SignatureScript = sigHashArgs.scriptCode
+		case P2WSHScript:
+			input.Witness = [][]byte{sigHashArgs.scriptCode}
+		}
+
+		tb.sigHashArgs = append(tb.sigHashArgs, sigHashArgs)
+		tb.utxos = append(tb.utxos, utxo)
+	}
+
+	return tb, nil
+}
+
+// importPsbtInput determines the sighash arguments and the pointed UTXO of
+// the given PSBT input.
+func importPsbtInput(
+	outpoint *TransactionOutpoint,
+	psbtInput *PsbtInput,
+) (*inputSigHashArgs, *inputUtxo, error) {
+	if len(psbtInput.FinalScriptSig) > 0 ||
+		len(psbtInput.FinalScriptWitness) > 0 {
+		return nil, nil, fmt.Errorf("input is already finalized")
+	}
+
+	if psbtInput.SighashType != 0 &&
+		psbtInput.SighashType != uint32(txscript.SigHashAll) {
+		return nil, nil, fmt.Errorf(
+			"unsupported sighash type [%v]",
+			psbtInput.SighashType,
+		)
+	}
+
+	utxo := &inputUtxo{}
+
+	if psbtInput.NonWitnessUtxo != nil {
+		if psbtInput.NonWitnessUtxo.Hash() != outpoint.TransactionHash {
+			return nil, nil, fmt.Errorf(
+				"non-witness UTXO does not match the input outpoint",
+			)
+		}
+
+		if int(outpoint.OutputIndex) >= len(psbtInput.NonWitnessUtxo.Outputs) {
+			return nil, nil, fmt.Errorf(
+				"non-witness UTXO has no output [%v]",
+				outpoint.OutputIndex,
+			)
+		}
+
+		utxo.transaction = psbtInput.NonWitnessUtxo
+		utxo.output = psbtInput.NonWitnessUtxo.Outputs[outpoint.OutputIndex]
+	} else if psbtInput.WitnessUtxo != nil {
+		utxo.output = psbtInput.WitnessUtxo
+	} else {
+		return nil, nil, fmt.Errorf("UTXO data is missing")
+	}
+
+	utxoScript := utxo.output.PublicKeyScript
+
+	sigHashArgs := &inputSigHashArgs{
+		value:   utxo.output.Value,
+		witness: txscript.IsWitnessProgram(utxoScript),
+	}
+
+	if !sigHashArgs.witness && utxo.transaction == nil {
+		return nil, nil, fmt.Errorf(
+			"non-witness UTXO is required for non-witness inputs",
+		)
+	}
+
+	switch GetScriptType(utxoScript) {
+	case P2PKHScript, P2WPKHScript:
+		sigHashArgs.scriptCode = utxoScript
+	case P2SHScript:
+		if len(psbtInput.RedeemScript) == 0 {
+			return nil, nil, fmt.Errorf("redeem script is missing")
+		}
+
+		if ScriptHash(psbtInput.RedeemScript) != [20]byte(utxoScript[2:22]) {
+			return nil, nil, fmt.Errorf(
+				"redeem script does not match the UTXO",
+			)
+		}
+
+		// Nested witness programs are not supported by the builder.
+		if txscript.IsWitnessProgram(psbtInput.RedeemScript) {
+			return nil, nil, fmt.Errorf("nested witness inputs are not supported")
+		}
+
+		sigHashArgs.scriptCode = psbtInput.RedeemScript
+	case P2WSHScript:
+		if len(psbtInput.WitnessScript) == 0 {
+			return nil, nil, fmt.Errorf("witness script is missing")
+		}
+
+		if WitnessScriptHash(psbtInput.WitnessScript) != [32]byte(utxoScript[2:34]) {
+			return nil, nil, fmt.Errorf(
+				"witness script does not match the UTXO",
+			)
+		}
+
+		sigHashArgs.scriptCode = psbtInput.WitnessScript
+	default:
+		return nil, nil, fmt.Errorf(
+			"unsupported UTXO script type [%v]",
+			GetScriptType(utxoScript),
+		)
+	}
+
+	return sigHashArgs, utxo, nil
+}
+
 // inputSigHashArgs is a helper structure holding some arguments required to
 // compute a sighash for the given input.
 type inputSigHashArgs struct {
@@ -323,6 +549,16 @@ type inputSigHashArgs struct {
 	witness bool
 }
 
+// inputUtxo is a helper structure holding data of the UTXO pointed by the
+// given input.
+type inputUtxo struct {
+	// transaction is the transaction holding the UTXO. It may be nil for
+	// witness inputs imported from a PSBT.
+	transaction *Transaction
+	// output is the UTXO itself.
+	output *TransactionOutput
+}
+
 // internalTransaction is an internal utility representation of the Transaction
 // that expose a lot of tools helpful during transaction manipulation.
 type internalTransaction struct {
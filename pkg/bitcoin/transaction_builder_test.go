@@ -415,6 +415,37 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 				len(builder.sigHashes),
 			)
 
+			// Export the unsigned transaction as PSBT and import it into
+			// a new builder. Both builders should produce the same result.
+			serializedPsbt, err := builder.Psbt().Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			psbt := &Psbt{}
+			if err := psbt.Deserialize(serializedPsbt); err != nil {
+				t.Fatal(err)
+			}
+
+			psbtBuilder, err := NewTransactionBuilderFromPsbt(localChain, psbt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			psbtSigHashes, err := psbtBuilder.ComputeSignatureHashes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range sigHashes {
+				testutils.AssertBigIntsEqual(
+					t,
+					fmt.Sprintf("PSBT sighash for input [%v]", i),
+					sigHashes[i],
+					psbtSigHashes[i],
+				)
+			}
+
 			transaction, err := builder.AddSignatures(test.signatures)
 			if err != nil {
 				t.Fatal(err)
@@ -425,6 +456,17 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 				transaction.Serialize(),
 				hexToSlice(t, test.expectedSignedTransactionHex),
 			)
+
+			psbtTransaction, err := psbtBuilder.AddSignatures(test.signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBytesEqual(
+				t,
+				psbtTransaction.Serialize(),
+				hexToSlice(t, test.expectedSignedTransactionHex),
+			)
 		})
 	}
 }
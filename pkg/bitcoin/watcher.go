@@ -0,0 +1,197 @@
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TransactionWatchPollInterval is the interval used to poll the chain for
+// the transaction confirmations by WatchTransaction if the chain does not
+// implement TransactionWatcher.
+const TransactionWatchPollInterval = 1 * time.Minute
+
+// TransactionWatcher is an optional interface implemented by Chain
+// implementations able to push notifications about transaction state changes.
+type TransactionWatcher interface {
+	// WatchTransaction watches the given transaction until the given context
+	// is done. The returned channel receives the number of the transaction's
+	// confirmations each time it changes, that is, when the transaction
+	// appears in the mempool (zero confirmations) and each time it gains
+	// a confirmation. The channel is closed once the context is done.
+	WatchTransaction(
+		ctx context.Context,
+		transaction *Transaction,
+	) (<-chan uint, error)
+}
+
+// WatchTransaction watches the given transaction until the given context
+// is done. Notifications are pushed by the chain if it implements
+// TransactionWatcher. Otherwise, the chain is polled for the transaction
+// confirmations every TransactionWatchPollInterval. In the latter case,
+// mempool transactions may not be reported. See
+// TransactionWatcher.WatchTransaction for the semantics of the returned
+// channel.
+func WatchTransaction(
+	ctx context.Context,
+	chain Chain,
+	transaction *Transaction,
+) (<-chan uint, error) {
+	if watcher, ok := chain.(TransactionWatcher); ok {
+		return watcher.WatchTransaction(ctx, transaction)
+	}
+
+	return pollTransaction(
+		ctx,
+		chain,
+		transaction.Hash(),
+		TransactionWatchPollInterval,
+	), nil
+}
+
+func pollTransaction(
+	ctx context.Context,
+	chain Chain,
+	transactionHash Hash,
+	pollInterval time.Duration,
+) <-chan uint {
+	confirmationsChan := make(chan uint, 1)
+
+	go func() {
+		defer close(confirmationsChan)
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		lastConfirmations := -1
+
+		for {
+			confirmations, err := chain.GetTransactionConfirmations(
+				transactionHash,
+			)
+			// Errors most likely mean the transaction is not known yet.
+			if err == nil && int(confirmations) != lastConfirmations {
+				lastConfirmations = int(confirmations)
+
+				select {
+				case confirmationsChan <- confirmations:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return confirmationsChan
+}
+
+// WatchConfirmations watches the given transaction until it accumulates
+// the given number of confirmations or the given context is done. The
+// returned channel receives the number of the transaction's confirmations
+// once it reaches the given number and is closed afterwards. If the
+// context is done earlier, the channel is closed without receiving
+// anything.
+//
+// The watch is reorg-aware. Non-zero confirmation counts reported by
+// WatchTransaction are re-verified against the chain before being
+// trusted, and the chain is checked again each time WatchReorgs reports
+// a reorganization, as previously reported counts may be stale then.
+func WatchConfirmations(
+	ctx context.Context,
+	chain Chain,
+	transaction *Transaction,
+	confirmations uint,
+) (<-chan uint, error) {
+	watchCtx, cancelWatchCtx := context.WithCancel(ctx)
+
+	confirmationsChan, err := WatchTransaction(watchCtx, chain, transaction)
+	if err != nil {
+		cancelWatchCtx()
+		return nil, fmt.Errorf("cannot watch transaction: [%w]", err)
+	}
+
+	reorgsChan := WatchReorgs(watchCtx, chain)
+
+	reachedChan := make(chan uint, 1)
+
+	go func() {
+		defer cancelWatchCtx()
+		defer close(reachedChan)
+
+		reached, ok := awaitConfirmations(
+			watchCtx,
+			chain,
+			transaction.Hash(),
+			confirmations,
+			confirmationsChan,
+			reorgsChan,
+		)
+		if ok {
+			reachedChan <- reached
+		}
+	}()
+
+	return reachedChan, nil
+}
+
+// awaitConfirmations blocks until the given transaction reaches the given
+// number of confirmations according to the given notifications and
+// returns the number of confirmations reached. False is returned if
+// the context is done or any of the channels is closed earlier.
+func awaitConfirmations(
+	ctx context.Context,
+	chain Chain,
+	transactionHash Hash,
+	confirmations uint,
+	confirmationsChan <-chan uint,
+	reorgsChan <-chan *ReorgEvent,
+) (uint, bool) {
+	// verify checks the current number of confirmations against the chain.
+	// Errors most likely mean the transaction is not known anymore.
+	verify := func() (uint, bool) {
+		current, err := chain.GetTransactionConfirmations(transactionHash)
+		if err != nil || current < confirmations {
+			return 0, false
+		}
+		return current, true
+	}
+
+	for {
+		select {
+		case reported, ok := <-confirmationsChan:
+			if !ok {
+				return 0, false
+			}
+
+			if reported < confirmations {
+				continue
+			}
+
+			// Mempool transactions may not be known to the chain so zero
+			// confirmations are trusted as reported.
+			if reported == 0 {
+				return reported, true
+			}
+
+			if current, ok := verify(); ok {
+				return current, true
+			}
+		case _, ok := <-reorgsChan:
+			if !ok {
+				return 0, false
+			}
+
+			if current, ok := verify(); ok {
+				return current, true
+			}
+		case <-ctx.Done():
+			return 0, false
+		}
+	}
+}
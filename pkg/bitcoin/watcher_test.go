@@ -0,0 +1,199 @@
+package bitcoin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestPollTransaction(t *testing.T) {
+	chain := newLocalChain()
+	transactionHash := Hash{1}
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	confirmationsChan := pollTransaction(
+		ctx,
+		chain,
+		transactionHash,
+		10*time.Millisecond,
+	)
+
+	setConfirmations := func(confirmations uint) {
+		chain.transactionConfirmationsMutex.Lock()
+		defer chain.transactionConfirmationsMutex.Unlock()
+
+		chain.transactionConfirmations[transactionHash] = confirmations
+	}
+
+	expectConfirmations := func(expectedConfirmations uint) {
+		select {
+		case confirmations := <-confirmationsChan:
+			testutils.AssertIntsEqual(
+				t,
+				"confirmations",
+				int(expectedConfirmations),
+				int(confirmations),
+			)
+		case <-time.After(time.Second):
+			t.Fatal("expected confirmations notification")
+		}
+	}
+
+	// The transaction is not known yet so no notification is expected.
+	select {
+	case confirmations := <-confirmationsChan:
+		t.Fatalf("unexpected notification: [%v]", confirmations)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	setConfirmations(0)
+	expectConfirmations(0)
+
+	setConfirmations(1)
+	expectConfirmations(1)
+
+	// The number of confirmations did not change so no notification is
+	// expected.
+	select {
+	case confirmations := <-confirmationsChan:
+		t.Fatalf("unexpected notification: [%v]", confirmations)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancelCtx()
+
+	select {
+	case _, ok := <-confirmationsChan:
+		if ok {
+			t.Fatal("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected closed channel")
+	}
+}
+
+func TestAwaitConfirmations(t *testing.T) {
+	transactionHash := Hash{1}
+
+	type result struct {
+		confirmations uint
+		ok            bool
+	}
+
+	tests := map[string]struct {
+		// chainConfirmations is the number of confirmations known to the
+		// chain. Nil means the transaction is not known to the chain.
+		chainConfirmations    *uint
+		requiredConfirmations uint
+		// notify sends notifications to the given channels.
+		notify         func(chan uint, chan *ReorgEvent)
+		expectedResult *result
+	}{
+		"required confirmations reported and verified": {
+			chainConfirmations:    uintPtr(4),
+			requiredConfirmations: 3,
+			notify: func(confirmationsChan chan uint, _ chan *ReorgEvent) {
+				confirmationsChan <- 2
+				confirmationsChan <- 3
+			},
+			expectedResult: &result{confirmations: 4, ok: true},
+		},
+		"required confirmations reported but not verified": {
+			chainConfirmations:    uintPtr(2),
+			requiredConfirmations: 3,
+			notify: func(confirmationsChan chan uint, _ chan *ReorgEvent) {
+				confirmationsChan <- 3
+			},
+		},
+		"mempool transaction": {
+			notify: func(confirmationsChan chan uint, _ chan *ReorgEvent) {
+				confirmationsChan <- 0
+			},
+			expectedResult: &result{confirmations: 0, ok: true},
+		},
+		"required confirmations verified upon reorg": {
+			chainConfirmations:    uintPtr(3),
+			requiredConfirmations: 3,
+			notify: func(_ chan uint, reorgsChan chan *ReorgEvent) {
+				reorgsChan <- &ReorgEvent{}
+			},
+			expectedResult: &result{confirmations: 3, ok: true},
+		},
+		"transaction unknown upon reorg": {
+			requiredConfirmations: 3,
+			notify: func(_ chan uint, reorgsChan chan *ReorgEvent) {
+				reorgsChan <- &ReorgEvent{}
+			},
+		},
+		"confirmations channel closed": {
+			chainConfirmations:    uintPtr(3),
+			requiredConfirmations: 3,
+			notify: func(confirmationsChan chan uint, _ chan *ReorgEvent) {
+				close(confirmationsChan)
+			},
+			expectedResult: &result{ok: false},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			chain := newLocalChain()
+			if test.chainConfirmations != nil {
+				chain.transactionConfirmations[transactionHash] =
+					*test.chainConfirmations
+			}
+
+			ctx, cancelCtx := context.WithCancel(context.Background())
+			defer cancelCtx()
+
+			confirmationsChan := make(chan uint, 2)
+			reorgsChan := make(chan *ReorgEvent, 1)
+			test.notify(confirmationsChan, reorgsChan)
+
+			resultChan := make(chan *result, 1)
+			go func() {
+				confirmations, ok := awaitConfirmations(
+					ctx,
+					chain,
+					transactionHash,
+					test.requiredConfirmations,
+					confirmationsChan,
+					reorgsChan,
+				)
+				resultChan <- &result{confirmations, ok}
+			}()
+
+			select {
+			case actualResult := <-resultChan:
+				if test.expectedResult == nil {
+					t.Fatalf("unexpected result: [%+v]", actualResult)
+				}
+
+				testutils.AssertBoolsEqual(
+					t,
+					"ok",
+					test.expectedResult.ok,
+					actualResult.ok,
+				)
+				testutils.AssertUintsEqual(
+					t,
+					"confirmations",
+					uint64(test.expectedResult.confirmations),
+					uint64(actualResult.confirmations),
+				)
+			case <-time.After(50 * time.Millisecond):
+				if test.expectedResult != nil {
+					t.Fatal("expected result")
+				}
+			}
+		})
+	}
+}
+
+func uintPtr(value uint) *uint {
+	return &value
+}
@@ -1,8 +1,10 @@
 package ethereum
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	beaconchain "github.com/keep-network/keep-core/pkg/beacon/chain"
@@ -11,7 +13,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
 	"github.com/keep-network/keep-core/pkg/chain"
+	beaconabi "github.com/keep-network/keep-core/pkg/chain/ethereum/beacon/gen/abi"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum/beacon/gen/contract"
 	"github.com/keep-network/keep-core/pkg/operator"
 )
@@ -21,6 +25,20 @@ const (
 	RandomBeaconContractName = "RandomBeacon"
 )
 
+// RelayEntryMiningCheckInterval is the time after which a relay entry
+// transaction that has not been mined yet is re-submitted with an escalated
+// gas price. It is shorter than the default mining check interval so that
+// the gas price can be escalated several times before the relay entry times
+// out and the group gets punished.
+const RelayEntryMiningCheckInterval = 20 * time.Second
+
+// relayEntrySoftTimeoutMargin is the minimum number of blocks before the relay
+// entry soft timeout allowing to submit the gas-optimized relay entry
+// transaction. It leaves room for a few gas price escalations, each one
+// after RelayEntryMiningCheckInterval, before the gas-optimized transaction
+// is no longer accepted.
+const relayEntrySoftTimeoutMargin = 10
+
 var errNotImplemented = fmt.Errorf("not implemented")
 
 // BeaconChain represents a beacon-specific chain handle.
@@ -29,6 +47,11 @@ type BeaconChain struct {
 
 	randomBeacon  *contract.RandomBeacon
 	sortitionPool *contract.BeaconSortitionPool
+
+	// relayEntrySubmitter is the RandomBeacon handle used to submit relay
+	// entries. Unlike randomBeacon, it escalates the gas price of pending
+	// transactions every RelayEntryMiningCheckInterval.
+	relayEntrySubmitter *contract.RandomBeacon
 }
 
 // newBeaconChain construct a new instance of the beacon-specific Ethereum
@@ -64,6 +87,28 @@ func newBeaconChain(
 		)
 	}
 
+	relayEntrySubmitter, err :=
+		contract.NewRandomBeacon(
+			randomBeaconAddress,
+			baseChain.chainID,
+			baseChain.key,
+			baseChain.client,
+			baseChain.nonceManager,
+			ethutil.NewMiningWaiter(
+				baseChain.client,
+				relayEntryMiningConfig(config),
+			),
+			baseChain.blockCounter,
+			baseChain.transactionMutex,
+		)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to attach relay entry submitter to RandomBeacon "+
+				"contract: [%v]",
+			err,
+		)
+	}
+
 	sortitionPoolAddress, err := randomBeacon.SortitionPool()
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -91,22 +136,39 @@ func newBeaconChain(
 	}
 
 	return &BeaconChain{
-		baseChain:     baseChain,
-		randomBeacon:  randomBeacon,
-		sortitionPool: sortitionPool,
+		baseChain:           baseChain,
+		randomBeacon:        randomBeacon,
+		sortitionPool:       sortitionPool,
+		relayEntrySubmitter: relayEntrySubmitter,
 	}, nil
 }
 
+// relayEntryMiningConfig returns the configuration of the mining waiter of
+// relay entry transactions. The mining check interval is capped at
+// RelayEntryMiningCheckInterval; the maximum gas fee cap is kept.
+func relayEntryMiningConfig(config ethereum.Config) ethereum.Config {
+	if config.MiningCheckInterval == 0 ||
+		config.MiningCheckInterval > RelayEntryMiningCheckInterval {
+		config.MiningCheckInterval = RelayEntryMiningCheckInterval
+	}
+
+	return config
+}
+
 // GetConfig returns the expected configuration of the random beacon.
 // TODO: Adjust to the random beacon v2 requirements.
 func (bc *BeaconChain) GetConfig() *beaconchain.Config {
 	groupSize := 64
+	// The chain rejects DKG results with less than 25% safety margin above
+	// the honest threshold.
+	groupQuorum := 48
 	honestThreshold := 33
 	resultPublicationBlockStep := 1
 	relayEntryTimeout := groupSize * resultPublicationBlockStep
 
 	return &beaconchain.Config{
 		GroupSize:                  groupSize,
+		GroupQuorum:                groupQuorum,
 		HonestThreshold:            honestThreshold,
 		ResultPublicationBlockStep: uint64(resultPublicationBlockStep),
 		RelayEntryTimeout:          uint64(relayEntryTimeout),
@@ -149,6 +211,88 @@ func (bc *BeaconChain) OperatorToStakingProvider() (chain.Address, bool, error)
 	return chain.Address(stakingProvider.Hex()), true, nil
 }
 
+// StakingProviderToOperator returns the operator registered for the staking
+// provider. If no operator has been registered for the staking provider, the
+// returned address is empty and the boolean flag is set to false.
+func (bc *BeaconChain) StakingProviderToOperator(
+	stakingProvider chain.Address,
+) (chain.Address, bool, error) {
+	operator, err := bc.randomBeacon.StakingProviderToOperator(
+		common.HexToAddress(stakingProvider.String()),
+	)
+	if err != nil {
+		return "", false, fmt.Errorf(
+			"failed to map staking provider [%v] to an operator: [%w]",
+			stakingProvider,
+			err,
+		)
+	}
+
+	if (operator == common.Address{}) {
+		return "", false, nil
+	}
+
+	return chain.Address(operator.Hex()), true, nil
+}
+
+// MinimumAuthorization returns the minimum stake authorization required by
+// the RandomBeacon.
+func (bc *BeaconChain) MinimumAuthorization() (*big.Int, error) {
+	return bc.randomBeacon.MinimumAuthorization()
+}
+
+// SelfRegisterOperator executes a transaction registering the operator for
+// the staking provider the transaction is submitted by. As the transaction is
+// submitted with the operator key, the operator is registered for itself as
+// the staking provider.
+func (bc *BeaconChain) SelfRegisterOperator() error {
+	_, err := bc.randomBeacon.RegisterOperator(bc.key.Address)
+	return err
+}
+
+// PendingAuthorizationDecrease returns the amount of the authorization
+// decrease requested for the staking provider in the RandomBeacon, zero if none
+// is pending.
+func (bc *BeaconChain) PendingAuthorizationDecrease(
+	stakingProvider chain.Address,
+) (*big.Int, error) {
+	return bc.randomBeacon.PendingAuthorizationDecrease(
+		common.HexToAddress(stakingProvider.String()),
+	)
+}
+
+// RemainingAuthorizationDecreaseDelay returns the time that has to pass before
+// the pending authorization decrease of the staking provider can be approved.
+// The boolean flag is false if the operator has not updated its status in the
+// sortition pool since the decrease was requested.
+func (bc *BeaconChain) RemainingAuthorizationDecreaseDelay(
+	stakingProvider chain.Address,
+) (time.Duration, bool, error) {
+	seconds, err := bc.randomBeacon.RemainingAuthorizationDecreaseDelay(
+		common.HexToAddress(stakingProvider.String()),
+	)
+	if err != nil {
+		return 0, false, err
+	}
+
+	remainingDelay, isDelayStarted := toRemainingAuthorizationDecreaseDelay(
+		seconds,
+	)
+
+	return remainingDelay, isDelayStarted, nil
+}
+
+// ApproveAuthorizationDecrease executes a transaction approving the pending
+// authorization decrease of the staking provider.
+func (bc *BeaconChain) ApproveAuthorizationDecrease(
+	stakingProvider chain.Address,
+) error {
+	_, err := bc.randomBeacon.ApproveAuthorizationDecrease(
+		common.HexToAddress(stakingProvider.String()),
+	)
+	return err
+}
+
 // EligibleStake returns the current value of the staking provider's eligible
 // stake. Eligible stake is defined as the currently authorized stake minus the
 // pending authorization decrease. Eligible stake is what is used for operator's
@@ -190,6 +334,13 @@ func (bc *BeaconChain) IsOperatorUpToDate() (bool, error) {
 	return bc.randomBeacon.IsOperatorUpToDate(bc.key.Address)
 }
 
+// OperatorWeight returns the current weight of the operator in the sortition
+// pool. If the operator is not in the sortition pool, the returned weight is
+// zero.
+func (bc *BeaconChain) OperatorWeight() (*big.Int, error) {
+	return bc.sortitionPool.GetPoolWeight(bc.key.Address)
+}
+
 // JoinSortitionPool executes a transaction to have the operator join the
 // sortition pool.
 func (bc *BeaconChain) JoinSortitionPool() error {
@@ -204,6 +355,18 @@ func (bc *BeaconChain) UpdateOperatorStatus() error {
 	return err
 }
 
+// JoinSortitionPoolGasEstimate returns the estimated gas of the transaction
+// having the operator join the sortition pool.
+func (bc *BeaconChain) JoinSortitionPoolGasEstimate() (uint64, error) {
+	return bc.randomBeacon.JoinSortitionPoolGasEstimate()
+}
+
+// UpdateOperatorStatusGasEstimate returns the estimated gas of the
+// transaction updating the operator's state in the sortition pool.
+func (bc *BeaconChain) UpdateOperatorStatusGasEstimate() (uint64, error) {
+	return bc.randomBeacon.UpdateOperatorStatusGasEstimate(bc.key.Address)
+}
+
 // IsEligibleForRewards checks whether the operator is eligible for rewards or
 // not.
 func (bc *BeaconChain) IsEligibleForRewards() (bool, error) {
@@ -222,6 +385,70 @@ func (bc *BeaconChain) RestoreRewardEligibility() error {
 	return err
 }
 
+// RewardEligibilityRestorableAt returns the earliest time at which the
+// operator's eligibility for rewards can be restored.
+func (bc *BeaconChain) RewardEligibilityRestorableAt() (time.Time, error) {
+	restorableAt, err := bc.sortitionPool.RewardsEligibilityRestorableAt(
+		bc.key.Address,
+	)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(restorableAt.Int64(), 0), nil
+}
+
+// RewardsIneligibilityReason returns the reason the operator was marked as
+// ineligible for rewards in the given block. Operators are marked as
+// ineligible when an inactivity claim is submitted or when a DKG result
+// naming them as misbehaved is approved.
+func (bc *BeaconChain) RewardsIneligibilityReason(
+	blockNumber uint64,
+) (string, error) {
+	claims, err := bc.randomBeacon.PastInactivityClaimedEvents(
+		blockNumber,
+		&blockNumber,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"could not get past inactivity claimed events: [%w]",
+			err,
+		)
+	}
+
+	if len(claims) > 0 {
+		return fmt.Sprintf(
+			"inactivity claimed for group [%d] by [%s]",
+			claims[0].GroupId,
+			claims[0].Notifier.Hex(),
+		), nil
+	}
+
+	results, err := bc.randomBeacon.PastDkgResultApprovedEvents(
+		blockNumber,
+		&blockNumber,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"could not get past DKG result approved events: [%w]",
+			err,
+		)
+	}
+
+	if len(results) > 0 {
+		return fmt.Sprintf(
+			"marked as misbehaved in DKG result [0x%x] approved by [%s]",
+			results[0].ResultHash,
+			results[0].Approver.Hex(),
+		), nil
+	}
+
+	return "", nil
+}
+
 // Returns true if the chaosnet phase is active, false otherwise.
 func (bc *BeaconChain) IsChaosnetActive() (bool, error) {
 	return bc.sortitionPool.IsChaosnetActive()
@@ -243,6 +470,120 @@ func (bc *BeaconChain) GetOperatorID(
 	)
 }
 
+// OnOperatorStatusChanged registers a callback that is invoked when an
+// on-chain event that may change the status of the operator in the sortition
+// pool is emitted: the operator registration, an authorization increase or
+// decrease of the operator's staking provider, or a change of the operator's
+// eligibility for rewards.
+func (bc *BeaconChain) OnOperatorStatusChanged(
+	handler func(event string, blockNumber uint64),
+) subscription.EventSubscription {
+	operatorFilter := []common.Address{bc.key.Address}
+
+	return subscribeOperatorStatusEvents(
+		&operatorStatusEventSources{
+			operatorRegistered: func(
+				handler func(stakingProvider common.Address, blockNumber uint64),
+			) subscription.EventSubscription {
+				return bc.randomBeacon.OperatorRegisteredEvent(
+					nil,
+					nil,
+					operatorFilter,
+				).OnEvent(
+					func(
+						stakingProvider common.Address,
+						operator common.Address,
+						blockNumber uint64,
+					) {
+						handler(stakingProvider, blockNumber)
+					},
+				)
+			},
+			authorizationIncreased: func(
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return bc.randomBeacon.AuthorizationIncreasedEvent(
+					nil,
+					nil,
+					operatorFilter,
+				).OnEvent(
+					func(
+						stakingProvider common.Address,
+						operator common.Address,
+						fromAmount *big.Int,
+						toAmount *big.Int,
+						blockNumber uint64,
+					) {
+						handler(blockNumber)
+					},
+				)
+			},
+			authorizationDecreaseRequested: func(
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return bc.randomBeacon.AuthorizationDecreaseRequestedEvent(
+					nil,
+					nil,
+					operatorFilter,
+				).OnEvent(
+					func(
+						stakingProvider common.Address,
+						operator common.Address,
+						fromAmount *big.Int,
+						toAmount *big.Int,
+						decreasingAt uint64,
+						blockNumber uint64,
+					) {
+						handler(blockNumber)
+					},
+				)
+			},
+			authorizationDecreaseApproved: func(
+				stakingProvider common.Address,
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return bc.randomBeacon.AuthorizationDecreaseApprovedEvent(
+					nil,
+					[]common.Address{stakingProvider},
+				).OnEvent(
+					func(stakingProvider common.Address, blockNumber uint64) {
+						handler(blockNumber)
+					},
+				)
+			},
+			rewardEligibilityRestored: func(
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return bc.sortitionPool.RewardEligibilityRestoredEvent(
+					nil,
+					operatorFilter,
+					nil,
+				).OnEvent(
+					func(operator common.Address, id uint32, blockNumber uint64) {
+						handler(blockNumber)
+					},
+				)
+			},
+			ineligibleForRewards: func(
+				handler func(operatorsIDs []uint32, blockNumber uint64),
+			) subscription.EventSubscription {
+				return bc.sortitionPool.IneligibleForRewardsEvent(nil).OnEvent(
+					func(ids []uint32, until *big.Int, blockNumber uint64) {
+						handler(ids, blockNumber)
+					},
+				)
+			},
+			operatorToStakingProvider: func() (common.Address, error) {
+				return bc.randomBeacon.OperatorToStakingProvider(bc.key.Address)
+			},
+			operatorID: func() (chain.OperatorID, error) {
+				return bc.GetOperatorID(chain.Address(bc.key.Address.Hex()))
+			},
+		},
+		handler,
+	)
+}
+
 // SelectGroup returns the group members for the group generated by
 // the given seed. This function can return an error if the beacon chain's
 // state does not allow for group selection at the moment.
@@ -288,9 +629,65 @@ func (bc *BeaconChain) IsGroupRegistered(groupPublicKey []byte) (bool, error) {
 	return false, errNotImplemented
 }
 
-// TODO: Implement a real IsStaleGroup function.
+// IsStaleGroup checks if a group with the given public key is considered
+// as stale on-chain. A terminated group is stale immediately as it is never
+// selected for a relay entry again. An active group is stale once it expired
+// and the relay entry hard timeout passed after its expiration so any relay
+// entry the group could be selected for before it expired is no longer
+// in progress.
 func (bc *BeaconChain) IsStaleGroup(groupPublicKey []byte) (bool, error) {
-	return false, nil
+	group, err := bc.randomBeacon.GetGroup0(groupPublicKey)
+	if err != nil {
+		return false, fmt.Errorf("cannot get group: [%v]", err)
+	}
+
+	if group.RegistrationBlockNumber.Sign() == 0 {
+		return false, fmt.Errorf("group does not exist")
+	}
+
+	if group.Terminated {
+		return true, nil
+	}
+
+	groupCreationParameters, err := bc.randomBeacon.GroupCreationParameters()
+	if err != nil {
+		return false, fmt.Errorf(
+			"cannot get group creation parameters: [%v]",
+			err,
+		)
+	}
+
+	relayEntryParameters, err := bc.randomBeacon.RelayEntryParameters()
+	if err != nil {
+		return false, fmt.Errorf(
+			"cannot get relay entry parameters: [%v]",
+			err,
+		)
+	}
+
+	currentBlock, err := bc.blockCounter.CurrentBlock()
+	if err != nil {
+		return false, fmt.Errorf("cannot get current block: [%v]", err)
+	}
+
+	return isStaleGroup(
+		group.RegistrationBlockNumber.Uint64(),
+		groupCreationParameters.GroupLifetime.Uint64(),
+		relayEntryParameters.RelayEntryHardTimeout.Uint64(),
+		currentBlock,
+	), nil
+}
+
+// isStaleGroup checks if an active group registered at the given block is
+// stale at the current block. The group expires once its lifetime passes
+// and becomes stale once the relay entry hard timeout passes after that.
+func isStaleGroup(
+	registrationBlock uint64,
+	groupLifetime uint64,
+	relayEntryHardTimeout uint64,
+	currentBlock uint64,
+) bool {
+	return registrationBlock+groupLifetime+relayEntryHardTimeout < currentBlock
 }
 
 // TODO: Implement a real OnDKGStarted event subscription. The current
@@ -384,45 +781,404 @@ func (bc *BeaconChain) IsRecognized(operatorPublicKey *operator.PublicKey) (bool
 	return true, nil
 }
 
-// TODO: Implement a real SubmitRelayEntry function.
+// HasMinimumAuthorization checks whether the given operator's staking
+// provider has at least the minimum stake authorization required by the
+// RandomBeacon. Only the eligible stake, i.e. the authorized stake not pending
+// a decrease, is taken into account.
+func (bc *BeaconChain) HasMinimumAuthorization(
+	operatorPublicKey *operator.PublicKey,
+) (bool, error) {
+	operatorAddress, err := operatorPublicKeyToChainAddress(operatorPublicKey)
+	if err != nil {
+		return false, fmt.Errorf(
+			"cannot convert from operator key to chain address: [%v]",
+			err,
+		)
+	}
+
+	stakingProvider, err := bc.randomBeacon.OperatorToStakingProvider(
+		operatorAddress,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to map operator [%v] to a staking provider: [%v]",
+			operatorAddress,
+			err,
+		)
+	}
+
+	if (stakingProvider == common.Address{}) {
+		return false, nil
+	}
+
+	eligibleStake, err := bc.randomBeacon.EligibleStake(stakingProvider)
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get eligible stake for staking provider [%v]: [%v]",
+			stakingProvider,
+			err,
+		)
+	}
+
+	minimumAuthorization, err := bc.randomBeacon.MinimumAuthorization()
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get minimum authorization: [%v]",
+			err,
+		)
+	}
+
+	return eligibleStake.Cmp(minimumAuthorization) >= 0, nil
+}
+
+// SubmitRelayEntry submits the relay entry to the RandomBeacon. If the
+// transaction is not mined within RelayEntryMiningCheckInterval, it is
+// re-submitted with an escalated gas price, up to the configured maximum gas
+// fee cap, until it gets mined.
+//
+// The gas-optimized submission is accepted by the RandomBeacon only before
+// the relay entry soft timeout so it is used only if the soft timeout is at
+// least relayEntrySoftTimeoutMargin blocks away. Otherwise, the entry is
+// submitted along with IDs of the group members. The escalation stops at the
+// relay entry hard timeout as each re-submission re-estimates the gas and
+// the RandomBeacon rejects entries of timed out requests. An entry of
+// a request that already timed out is not submitted at all.
 func (bc *BeaconChain) SubmitRelayEntry(
 	entry []byte,
 ) error {
-	return errNotImplemented
+	request, err := bc.currentRelayRequest()
+	if err != nil {
+		return fmt.Errorf("cannot get current relay request: [%v]", err)
+	}
+
+	relayEntryParameters, err := bc.randomBeacon.RelayEntryParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get relay entry parameters: [%v]", err)
+	}
+
+	currentBlock, err := bc.blockCounter.CurrentBlock()
+	if err != nil {
+		return fmt.Errorf("cannot get current block: [%v]", err)
+	}
+
+	withGroupMembers, timedOut := relayEntrySubmissionMode(
+		currentBlock,
+		request.Raw.BlockNumber,
+		relayEntryParameters.RelayEntrySoftTimeout.Uint64(),
+		relayEntryParameters.RelayEntryHardTimeout.Uint64(),
+	)
+	if timedOut {
+		return fmt.Errorf(
+			"relay request started at block [%v] timed out",
+			request.Raw.BlockNumber,
+		)
+	}
+
+	if !withGroupMembers {
+		_, err = bc.relayEntrySubmitter.SubmitRelayEntry0(entry)
+		return err
+	}
+
+	groupMembersIDs, err := bc.groupMembersIDs(request.GroupId)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot get members of group [%v]: [%v]",
+			request.GroupId,
+			err,
+		)
+	}
+
+	_, err = bc.relayEntrySubmitter.SubmitRelayEntry(entry, groupMembersIDs)
+	return err
 }
 
-// TODO: Implement a real OnRelayEntrySubmitted function.
+// relayEntrySubmissionMode determines how the entry of the relay request
+// started at the given block should be submitted at the current block. It
+// returns true as the first value if the entry should be submitted along
+// with IDs of the group members, i.e. the soft timeout is less than
+// relayEntrySoftTimeoutMargin blocks away, and true as the second value if
+// the request already timed out.
+func relayEntrySubmissionMode(
+	currentBlock uint64,
+	startBlock uint64,
+	softTimeout uint64,
+	hardTimeout uint64,
+) (bool, bool) {
+	softTimeoutBlock := startBlock + softTimeout
+	hardTimeoutBlock := softTimeoutBlock + hardTimeout
+
+	if currentBlock > hardTimeoutBlock {
+		return true, true
+	}
+
+	return currentBlock+relayEntrySoftTimeoutMargin > softTimeoutBlock, false
+}
+
+// OnRelayEntrySubmitted registers a handler for RelayEntrySubmitted events
+// of the RandomBeacon.
 func (bc *BeaconChain) OnRelayEntrySubmitted(
 	handler func(entry *event.RelayEntrySubmitted),
 ) subscription.EventSubscription {
-	return subscription.NewEventSubscription(func() {})
+	onEvent := func(
+		requestID *big.Int,
+		submitter common.Address,
+		entry []byte,
+		blockNumber uint64,
+	) {
+		handler(&event.RelayEntrySubmitted{
+			BlockNumber: blockNumber,
+		})
+	}
+
+	return bc.randomBeacon.RelayEntrySubmittedEvent(nil, nil).OnEvent(onEvent)
 }
 
-// TODO: Implement a real OnRelayEntryRequested function.
+// OnRelayEntryRequested registers a handler for RelayEntryRequested events
+// of the RandomBeacon. The event identifies the group selected to produce
+// the entry by its ID so the group is fetched to get its public key. Events
+// whose group cannot be fetched are logged and dropped.
 func (bc *BeaconChain) OnRelayEntryRequested(
 	handler func(request *event.RelayEntryRequested),
 ) subscription.EventSubscription {
-	return subscription.NewEventSubscription(func() {})
+	onEvent := func(
+		requestID *big.Int,
+		groupID uint64,
+		previousEntry []byte,
+		blockNumber uint64,
+	) {
+		group, err := bc.randomBeacon.GetGroup(groupID)
+		if err != nil {
+			logger.Errorf(
+				"cannot get group [%v] of relay entry request [%v]: [%v]",
+				groupID,
+				requestID,
+				err,
+			)
+			return
+		}
+
+		handler(&event.RelayEntryRequested{
+			PreviousEntry:  previousEntry,
+			GroupPublicKey: group.GroupPubKey,
+			BlockNumber:    blockNumber,
+		})
+	}
+
+	return bc.randomBeacon.RelayEntryRequestedEvent(nil, nil).OnEvent(onEvent)
 }
 
-// TODO: Implement a real ReportRelayEntryTimeout function.
+// ReportRelayEntryTimeout reports the timeout of the relay request currently
+// in progress. The RandomBeacon requires IDs of the members of the group
+// selected for the request so they are determined first.
 func (bc *BeaconChain) ReportRelayEntryTimeout() error {
-	return errNotImplemented
+	request, err := bc.currentRelayRequest()
+	if err != nil {
+		return fmt.Errorf("cannot get current relay request: [%v]", err)
+	}
+
+	groupMembersIDs, err := bc.groupMembersIDs(request.GroupId)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot get members of group [%v]: [%v]",
+			request.GroupId,
+			err,
+		)
+	}
+
+	_, err = bc.randomBeacon.ReportRelayEntryTimeout(groupMembersIDs)
+
+	return err
 }
 
-// TODO: Implement a real IsEntryInProgress function.
+// IsEntryInProgress checks if a relay request is currently in progress.
 func (bc *BeaconChain) IsEntryInProgress() (bool, error) {
-	return false, nil // no chain integration so not in progress
+	return bc.randomBeacon.IsRelayRequestInProgress()
 }
 
-// TODO: Implement a real CurrentRequestStartBlock function.
+// CurrentRequestStartBlock returns the block at which the relay request
+// currently in progress was made.
 func (bc *BeaconChain) CurrentRequestStartBlock() (*big.Int, error) {
-	return nil, errNotImplemented
+	request, err := bc.currentRelayRequest()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get current relay request: [%v]", err)
+	}
+
+	return new(big.Int).SetUint64(request.Raw.BlockNumber), nil
 }
 
-// TODO: Implement a real CurrentRequestPreviousEntry function.
+// CurrentRequestPreviousEntry returns the previous entry of the relay request
+// currently in progress.
 func (bc *BeaconChain) CurrentRequestPreviousEntry() ([]byte, error) {
-	return nil, errNotImplemented
+	request, err := bc.currentRelayRequest()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get current relay request: [%v]", err)
+	}
+
+	return request.PreviousEntry, nil
+}
+
+// currentRelayRequest returns the RelayEntryRequested event of the relay
+// request currently in progress. The RandomBeacon does not expose the current
+// request so the latest RelayEntryRequested event is taken. The event is
+// searched for within the relay entry soft and hard timeout first; the
+// search range is doubled until the event is found. An error is returned if
+// no relay request is in progress.
+func (bc *BeaconChain) currentRelayRequest() (
+	*beaconabi.RandomBeaconRelayEntryRequested,
+	error,
+) {
+	isInProgress, err := bc.randomBeacon.IsRelayRequestInProgress()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot check if relay request is in progress: [%v]",
+			err,
+		)
+	}
+
+	if !isInProgress {
+		return nil, fmt.Errorf("no relay request in progress")
+	}
+
+	relayEntryParameters, err := bc.randomBeacon.RelayEntryParameters()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get relay entry parameters: [%v]",
+			err,
+		)
+	}
+
+	currentBlock, err := bc.blockCounter.CurrentBlock()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get current block: [%v]", err)
+	}
+
+	searchBlocks := relayEntryParameters.RelayEntrySoftTimeout.Uint64() +
+		relayEntryParameters.RelayEntryHardTimeout.Uint64() + 1
+
+	for {
+		var startBlock uint64
+		if currentBlock > searchBlocks {
+			startBlock = currentBlock - searchBlocks
+		}
+
+		events, err := bc.randomBeacon.PastRelayEntryRequestedEvents(
+			startBlock,
+			nil,
+			nil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get past relay entry requested events: [%v]",
+				err,
+			)
+		}
+
+		if len(events) > 0 {
+			latest := events[0]
+			for _, event := range events[1:] {
+				if event.Raw.BlockNumber > latest.Raw.BlockNumber {
+					latest = event
+				}
+			}
+
+			return latest, nil
+		}
+
+		if startBlock == 0 {
+			return nil, fmt.Errorf("relay entry requested event not found")
+		}
+
+		searchBlocks *= 2
+	}
+}
+
+// groupMembersIDs returns IDs of members of the group with the given ID that
+// actively took part in DKG, i.e. excluding the misbehaved ones. The
+// RandomBeacon keeps only the hash of the IDs so they are taken from the DKG
+// result approved at the group registration block.
+func (bc *BeaconChain) groupMembersIDs(groupID uint64) ([]uint32, error) {
+	group, err := bc.randomBeacon.GetGroup(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get group: [%v]", err)
+	}
+
+	registrationBlock := group.RegistrationBlockNumber.Uint64()
+
+	approvals, err := bc.randomBeacon.PastDkgResultApprovedEvents(
+		registrationBlock,
+		&registrationBlock,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past DKG result approved events: [%v]",
+			err,
+		)
+	}
+
+	for _, approval := range approvals {
+		submissions, err := bc.randomBeacon.PastDkgResultSubmittedEvents(
+			0,
+			&registrationBlock,
+			[][32]byte{approval.ResultHash},
+			nil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get past DKG result submitted events: [%v]",
+				err,
+			)
+		}
+
+		for _, submission := range submissions {
+			result := submission.Result
+
+			if !bytes.Equal(result.GroupPubKey, group.GroupPubKey) {
+				continue
+			}
+
+			membersIDs := activeGroupMembersIDs(
+				result.Members,
+				result.MisbehavedMembersIndices,
+			)
+
+			membersHash, err := computeOperatorsIDsHash(membersIDs)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot compute members hash: [%v]",
+					err,
+				)
+			}
+
+			if membersHash == group.MembersHash {
+				return membersIDs, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("DKG result of the group not found")
+}
+
+// activeGroupMembersIDs returns IDs of the given members without the ones
+// with the given misbehaved member indices. Member indices start from 1.
+func activeGroupMembersIDs(
+	membersIDs []uint32,
+	misbehavedMembersIndices []uint8,
+) []uint32 {
+	misbehaved := make(map[int]bool, len(misbehavedMembersIndices))
+	for _, index := range misbehavedMembersIndices {
+		misbehaved[int(index)] = true
+	}
+
+	activeMembersIDs := make([]uint32, 0, len(membersIDs))
+	for i, memberID := range membersIDs {
+		if !misbehaved[i+1] {
+			activeMembersIDs = append(activeMembersIDs, memberID)
+		}
+	}
+
+	return activeMembersIDs
 }
 
 // TODO: Implement a real CurrentRequestGroupPublicKey function.
@@ -0,0 +1,170 @@
+package ethereum
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/chain/ethereum"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestRelayEntryMiningConfig(t *testing.T) {
+	var tests = map[string]struct {
+		miningCheckInterval         time.Duration
+		expectedMiningCheckInterval time.Duration
+	}{
+		"default mining check interval": {
+			miningCheckInterval:         0,
+			expectedMiningCheckInterval: RelayEntryMiningCheckInterval,
+		},
+		"longer mining check interval": {
+			miningCheckInterval:         time.Minute,
+			expectedMiningCheckInterval: RelayEntryMiningCheckInterval,
+		},
+		"shorter mining check interval": {
+			miningCheckInterval:         5 * time.Second,
+			expectedMiningCheckInterval: 5 * time.Second,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			maxGasFeeCap := ethereum.WrapWei(big.NewInt(100))
+
+			config := relayEntryMiningConfig(ethereum.Config{
+				MiningCheckInterval: test.miningCheckInterval,
+				MaxGasFeeCap:        *maxGasFeeCap,
+			})
+
+			testutils.AssertIntsEqual(
+				t,
+				"mining check interval",
+				int(test.expectedMiningCheckInterval),
+				int(config.MiningCheckInterval),
+			)
+			testutils.AssertBigIntsEqual(
+				t,
+				"max gas fee cap",
+				maxGasFeeCap.Int,
+				config.MaxGasFeeCap.Int,
+			)
+		})
+	}
+}
+
+func TestRelayEntrySubmissionMode(t *testing.T) {
+	var tests = map[string]struct {
+		currentBlock             uint64
+		expectedWithGroupMembers bool
+		expectedTimedOut         bool
+	}{
+		"request start": {
+			currentBlock:             100,
+			expectedWithGroupMembers: false,
+			expectedTimedOut:         false,
+		},
+		"soft timeout margin away": {
+			currentBlock:             120 - relayEntrySoftTimeoutMargin,
+			expectedWithGroupMembers: false,
+			expectedTimedOut:         false,
+		},
+		"within soft timeout margin": {
+			currentBlock:             121 - relayEntrySoftTimeoutMargin,
+			expectedWithGroupMembers: true,
+			expectedTimedOut:         false,
+		},
+		"after soft timeout": {
+			currentBlock:             121,
+			expectedWithGroupMembers: true,
+			expectedTimedOut:         false,
+		},
+		"hard timeout": {
+			currentBlock:             150,
+			expectedWithGroupMembers: true,
+			expectedTimedOut:         false,
+		},
+		"after hard timeout": {
+			currentBlock:             151,
+			expectedWithGroupMembers: true,
+			expectedTimedOut:         true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			withGroupMembers, timedOut := relayEntrySubmissionMode(
+				test.currentBlock,
+				100,
+				20,
+				30,
+			)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"with group members",
+				test.expectedWithGroupMembers,
+				withGroupMembers,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"timed out",
+				test.expectedTimedOut,
+				timedOut,
+			)
+		})
+	}
+}
+
+func TestIsStaleGroup(t *testing.T) {
+	var tests = map[string]struct {
+		currentBlock  uint64
+		expectedStale bool
+	}{
+		"group active": {
+			currentBlock:  1500,
+			expectedStale: false,
+		},
+		"group expired, hard timeout not passed": {
+			currentBlock:  2100,
+			expectedStale: false,
+		},
+		"group expired, hard timeout block": {
+			currentBlock:  2160,
+			expectedStale: false,
+		},
+		"group expired, hard timeout passed": {
+			currentBlock:  2161,
+			expectedStale: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testutils.AssertBoolsEqual(
+				t,
+				"stale group",
+				test.expectedStale,
+				isStaleGroup(1000, 1000, 160, test.currentBlock),
+			)
+		})
+	}
+}
+
+func TestActiveGroupMembersIDs(t *testing.T) {
+	activeMembersIDs := activeGroupMembersIDs(
+		[]uint32{11, 12, 13, 14, 15},
+		[]uint8{2, 5},
+	)
+
+	expectedMembersIDs := []uint32{11, 13, 14}
+	if !reflect.DeepEqual(expectedMembersIDs, activeMembersIDs) {
+		t.Errorf(
+			"unexpected active members IDs\nexpected: %v\nactual:   %v",
+			expectedMembersIDs,
+			activeMembersIDs,
+		)
+	}
+}
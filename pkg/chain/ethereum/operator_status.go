@@ -0,0 +1,167 @@
+package ethereum
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
+)
+
+// operatorStatusEventSources gives access to the events of an application
+// and its sortition pool that may change the status of the operator in the
+// pool. Applications use separate contract bindings with event types of
+// their own so the events are exposed through functions subscribing to them.
+// All the events, except AuthorizationDecreaseApproved and
+// IneligibleForRewards, are filtered by the operator.
+type operatorStatusEventSources struct {
+	operatorRegistered func(
+		handler func(stakingProvider common.Address, blockNumber uint64),
+	) subscription.EventSubscription
+	authorizationIncreased func(
+		handler func(blockNumber uint64),
+	) subscription.EventSubscription
+	authorizationDecreaseRequested func(
+		handler func(blockNumber uint64),
+	) subscription.EventSubscription
+	// authorizationDecreaseApproved is filtered by the staking provider as
+	// the event does not have the operator field.
+	authorizationDecreaseApproved func(
+		stakingProvider common.Address,
+		handler func(blockNumber uint64),
+	) subscription.EventSubscription
+	rewardEligibilityRestored func(
+		handler func(blockNumber uint64),
+	) subscription.EventSubscription
+	ineligibleForRewards func(
+		handler func(operatorsIDs []uint32, blockNumber uint64),
+	) subscription.EventSubscription
+
+	// operatorToStakingProvider returns the staking provider of the
+	// operator, the zero address if the operator is not registered.
+	operatorToStakingProvider func() (common.Address, error)
+	// operatorID returns the ID of the operator in the sortition pool.
+	operatorID func() (chain.OperatorID, error)
+}
+
+// subscribeOperatorStatusEvents subscribes to events that may change the
+// status of the operator in the sortition pool and invokes the handler with
+// the name of the observed event and the number of the block it was emitted
+// in.
+//
+// The subscription to AuthorizationDecreaseApproved requires the staking
+// provider. If the staking provider cannot be determined at the time of the
+// call, e.g. the operator is not registered yet, the subscription is made
+// once the staking provider is known: from the OperatorRegistered event or
+// on any other event observed.
+func subscribeOperatorStatusEvents(
+	sources *operatorStatusEventSources,
+	handler func(event string, blockNumber uint64),
+) subscription.EventSubscription {
+	var (
+		mutex sync.Mutex
+		// decreaseApprovedSubscription is nil until the staking provider
+		// is known.
+		decreaseApprovedSubscription subscription.EventSubscription
+		unsubscribed                 bool
+	)
+
+	// subscribeDecreaseApproved subscribes to AuthorizationDecreaseApproved
+	// unless already subscribed. The staking provider is resolved if the
+	// zero address is given.
+	subscribeDecreaseApproved := func(stakingProvider common.Address) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if decreaseApprovedSubscription != nil || unsubscribed {
+			return
+		}
+
+		if (stakingProvider == common.Address{}) {
+			var err error
+			stakingProvider, err = sources.operatorToStakingProvider()
+			if err != nil {
+				logger.Errorf(
+					"cannot subscribe to AuthorizationDecreaseApproved "+
+						"event; failed to map operator to a staking "+
+						"provider: [%v]",
+					err,
+				)
+				return
+			}
+
+			if (stakingProvider == common.Address{}) {
+				// The operator is not registered yet.
+				return
+			}
+		}
+
+		decreaseApprovedSubscription = sources.authorizationDecreaseApproved(
+			stakingProvider,
+			func(blockNumber uint64) {
+				handler("AuthorizationDecreaseApproved", blockNumber)
+			},
+		)
+	}
+
+	handle := func(event string) func(blockNumber uint64) {
+		return func(blockNumber uint64) {
+			subscribeDecreaseApproved(common.Address{})
+			handler(event, blockNumber)
+		}
+	}
+
+	subscriptions := []subscription.EventSubscription{
+		sources.operatorRegistered(
+			func(stakingProvider common.Address, blockNumber uint64) {
+				subscribeDecreaseApproved(stakingProvider)
+				handler("OperatorRegistered", blockNumber)
+			},
+		),
+		sources.authorizationIncreased(handle("AuthorizationIncreased")),
+		sources.authorizationDecreaseRequested(
+			handle("AuthorizationDecreaseRequested"),
+		),
+		sources.rewardEligibilityRestored(handle("RewardEligibilityRestored")),
+		sources.ineligibleForRewards(
+			func(operatorsIDs []uint32, blockNumber uint64) {
+				operatorID, err := sources.operatorID()
+				if err != nil {
+					logger.Errorf(
+						"cannot get operator ID to filter "+
+							"IneligibleForRewards event: [%v]",
+						err,
+					)
+					// Let the handler check the status to be on the
+					// safe side.
+					handler("IneligibleForRewards", blockNumber)
+					return
+				}
+
+				for _, id := range operatorsIDs {
+					if id == operatorID {
+						handler("IneligibleForRewards", blockNumber)
+						return
+					}
+				}
+			},
+		),
+	}
+
+	subscribeDecreaseApproved(common.Address{})
+
+	return subscription.NewEventSubscription(func() {
+		for _, sub := range subscriptions {
+			sub.Unsubscribe()
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		unsubscribed = true
+		if decreaseApprovedSubscription != nil {
+			decreaseApprovedSubscription.Unsubscribe()
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package ethereum
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
+)
+
+type testOperatorStatusEvents struct {
+	stakingProvider    common.Address
+	stakingProviderErr error
+
+	operatorRegistered            func(stakingProvider common.Address, blockNumber uint64)
+	authorizationIncreased        func(blockNumber uint64)
+	authorizationDecreaseApproved map[common.Address]func(blockNumber uint64)
+	ineligibleForRewards          func(operatorsIDs []uint32, blockNumber uint64)
+
+	unsubscribed int
+}
+
+func (tose *testOperatorStatusEvents) sources() *operatorStatusEventSources {
+	newSubscription := func() subscription.EventSubscription {
+		return subscription.NewEventSubscription(func() {
+			tose.unsubscribed++
+		})
+	}
+
+	tose.authorizationDecreaseApproved =
+		make(map[common.Address]func(blockNumber uint64))
+
+	return &operatorStatusEventSources{
+		operatorRegistered: func(
+			handler func(stakingProvider common.Address, blockNumber uint64),
+		) subscription.EventSubscription {
+			tose.operatorRegistered = handler
+			return newSubscription()
+		},
+		authorizationIncreased: func(
+			handler func(blockNumber uint64),
+		) subscription.EventSubscription {
+			tose.authorizationIncreased = handler
+			return newSubscription()
+		},
+		authorizationDecreaseRequested: func(
+			handler func(blockNumber uint64),
+		) subscription.EventSubscription {
+			return newSubscription()
+		},
+		authorizationDecreaseApproved: func(
+			stakingProvider common.Address,
+			handler func(blockNumber uint64),
+		) subscription.EventSubscription {
+			tose.authorizationDecreaseApproved[stakingProvider] = handler
+			return newSubscription()
+		},
+		rewardEligibilityRestored: func(
+			handler func(blockNumber uint64),
+		) subscription.EventSubscription {
+			return newSubscription()
+		},
+		ineligibleForRewards: func(
+			handler func(operatorsIDs []uint32, blockNumber uint64),
+		) subscription.EventSubscription {
+			tose.ineligibleForRewards = handler
+			return newSubscription()
+		},
+		operatorToStakingProvider: func() (common.Address, error) {
+			return tose.stakingProvider, tose.stakingProviderErr
+		},
+		operatorID: func() (chain.OperatorID, error) {
+			return 5, nil
+		},
+	}
+}
+
+func TestSubscribeOperatorStatusEvents(t *testing.T) {
+	stakingProvider := common.HexToAddress(
+		"0x8A4E2FAfd9A4a6CB7E0297dB08958Bc4dE5a0e2B",
+	)
+
+	events := &testOperatorStatusEvents{stakingProvider: stakingProvider}
+
+	observed := make([]string, 0)
+	sub := subscribeOperatorStatusEvents(
+		events.sources(),
+		func(event string, blockNumber uint64) {
+			observed = append(observed, fmt.Sprintf("%s@%d", event, blockNumber))
+		},
+	)
+
+	events.authorizationDecreaseApproved[stakingProvider](10)
+	// The operator is not in the group of ineligible operators.
+	events.ineligibleForRewards([]uint32{1, 2}, 11)
+	events.ineligibleForRewards([]uint32{4, 5}, 12)
+
+	expectedObserved := []string{
+		"AuthorizationDecreaseApproved@10",
+		"IneligibleForRewards@12",
+	}
+	if !reflect.DeepEqual(expectedObserved, observed) {
+		t.Errorf(
+			"unexpected observed events\nexpected: %v\nactual:   %v",
+			expectedObserved,
+			observed,
+		)
+	}
+
+	sub.Unsubscribe()
+	testutils.AssertIntsEqual(t, "unsubscribed", 6, events.unsubscribed)
+}
+
+func TestSubscribeOperatorStatusEvents_StakingProviderUnknown(t *testing.T) {
+	stakingProvider := common.HexToAddress(
+		"0x8A4E2FAfd9A4a6CB7E0297dB08958Bc4dE5a0e2B",
+	)
+
+	var tests = map[string]struct {
+		// stakingProviderErr is returned when mapping the operator to the
+		// staking provider at the time of the subscription.
+		stakingProviderErr error
+		// observe observes the event the staking provider becomes known on.
+		observe func(events *testOperatorStatusEvents)
+	}{
+		"operator not registered": {
+			observe: func(events *testOperatorStatusEvents) {
+				events.operatorRegistered(stakingProvider, 20)
+			},
+		},
+		"staking provider mapping failed": {
+			stakingProviderErr: fmt.Errorf("unavailable"),
+			observe: func(events *testOperatorStatusEvents) {
+				events.stakingProvider = stakingProvider
+				events.stakingProviderErr = nil
+				events.authorizationIncreased(20)
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			events := &testOperatorStatusEvents{
+				stakingProviderErr: test.stakingProviderErr,
+			}
+
+			observed := make([]string, 0)
+			sub := subscribeOperatorStatusEvents(
+				events.sources(),
+				func(event string, blockNumber uint64) {
+					observed = append(observed, event)
+				},
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"AuthorizationDecreaseApproved subscriptions before",
+				0,
+				len(events.authorizationDecreaseApproved),
+			)
+
+			test.observe(events)
+
+			handler, ok := events.authorizationDecreaseApproved[stakingProvider]
+			if !ok {
+				t.Fatal("expected AuthorizationDecreaseApproved subscription")
+			}
+			handler(21)
+
+			testutils.AssertStringsEqual(
+				t,
+				"last observed event",
+				"AuthorizationDecreaseApproved",
+				observed[len(observed)-1],
+			)
+
+			sub.Unsubscribe()
+			testutils.AssertIntsEqual(t, "unsubscribed", 6, events.unsubscribed)
+		})
+	}
+}
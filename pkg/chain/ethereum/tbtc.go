@@ -12,6 +12,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
@@ -23,6 +24,7 @@ import (
 	tbtcabi "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen/abi"
 	tbtccontract "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen/contract"
 	"github.com/keep-network/keep-core/pkg/internal/byteutils"
+	"github.com/keep-network/keep-core/pkg/maintainer/spv"
 	"github.com/keep-network/keep-core/pkg/operator"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/subscription"
@@ -265,6 +267,56 @@ func (tc *TbtcChain) IsRecognized(operatorPublicKey *operator.PublicKey) (bool,
 	return true, nil
 }
 
+// HasMinimumAuthorization checks whether the given operator's staking
+// provider has at least the minimum stake authorization required by the
+// WalletRegistry. Only the eligible stake, i.e. the authorized stake not pending
+// a decrease, is taken into account.
+func (tc *TbtcChain) HasMinimumAuthorization(
+	operatorPublicKey *operator.PublicKey,
+) (bool, error) {
+	operatorAddress, err := operatorPublicKeyToChainAddress(operatorPublicKey)
+	if err != nil {
+		return false, fmt.Errorf(
+			"cannot convert from operator key to chain address: [%v]",
+			err,
+		)
+	}
+
+	stakingProvider, err := tc.walletRegistry.OperatorToStakingProvider(
+		operatorAddress,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to map operator [%v] to a staking provider: [%v]",
+			operatorAddress,
+			err,
+		)
+	}
+
+	if (stakingProvider == common.Address{}) {
+		return false, nil
+	}
+
+	eligibleStake, err := tc.walletRegistry.EligibleStake(stakingProvider)
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get eligible stake for staking provider [%v]: [%v]",
+			stakingProvider,
+			err,
+		)
+	}
+
+	minimumAuthorization, err := tc.walletRegistry.MinimumAuthorization()
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get minimum authorization: [%v]",
+			err,
+		)
+	}
+
+	return eligibleStake.Cmp(minimumAuthorization) >= 0, nil
+}
+
 // OperatorToStakingProvider returns the staking provider address for the
 // operator. If the staking provider has not been registered for the
 // operator, the returned address is empty and the boolean flag is set to
@@ -287,6 +339,88 @@ func (tc *TbtcChain) OperatorToStakingProvider() (chain.Address, bool, error) {
 	return chain.Address(stakingProvider.Hex()), true, nil
 }
 
+// StakingProviderToOperator returns the operator registered for the staking
+// provider. If no operator has been registered for the staking provider, the
+// returned address is empty and the boolean flag is set to false.
+func (tc *TbtcChain) StakingProviderToOperator(
+	stakingProvider chain.Address,
+) (chain.Address, bool, error) {
+	operator, err := tc.walletRegistry.StakingProviderToOperator(
+		common.HexToAddress(stakingProvider.String()),
+	)
+	if err != nil {
+		return "", false, fmt.Errorf(
+			"failed to map staking provider [%v] to an operator: [%w]",
+			stakingProvider,
+			err,
+		)
+	}
+
+	if (operator == common.Address{}) {
+		return "", false, nil
+	}
+
+	return chain.Address(operator.Hex()), true, nil
+}
+
+// MinimumAuthorization returns the minimum stake authorization required by
+// the WalletRegistry.
+func (tc *TbtcChain) MinimumAuthorization() (*big.Int, error) {
+	return tc.walletRegistry.MinimumAuthorization()
+}
+
+// SelfRegisterOperator executes a transaction registering the operator for
+// the staking provider the transaction is submitted by. As the transaction is
+// submitted with the operator key, the operator is registered for itself as
+// the staking provider.
+func (tc *TbtcChain) SelfRegisterOperator() error {
+	_, err := tc.walletRegistry.RegisterOperator(tc.key.Address)
+	return err
+}
+
+// PendingAuthorizationDecrease returns the amount of the authorization
+// decrease requested for the staking provider in the WalletRegistry, zero if
+// none is pending.
+func (tc *TbtcChain) PendingAuthorizationDecrease(
+	stakingProvider chain.Address,
+) (*big.Int, error) {
+	return tc.walletRegistry.PendingAuthorizationDecrease(
+		common.HexToAddress(stakingProvider.String()),
+	)
+}
+
+// RemainingAuthorizationDecreaseDelay returns the time that has to pass before
+// the pending authorization decrease of the staking provider can be approved.
+// The boolean flag is false if the operator has not updated its status in the
+// sortition pool since the decrease was requested.
+func (tc *TbtcChain) RemainingAuthorizationDecreaseDelay(
+	stakingProvider chain.Address,
+) (time.Duration, bool, error) {
+	seconds, err := tc.walletRegistry.RemainingAuthorizationDecreaseDelay(
+		common.HexToAddress(stakingProvider.String()),
+	)
+	if err != nil {
+		return 0, false, err
+	}
+
+	remainingDelay, isDelayStarted := toRemainingAuthorizationDecreaseDelay(
+		seconds,
+	)
+
+	return remainingDelay, isDelayStarted, nil
+}
+
+// ApproveAuthorizationDecrease executes a transaction approving the pending
+// authorization decrease of the staking provider.
+func (tc *TbtcChain) ApproveAuthorizationDecrease(
+	stakingProvider chain.Address,
+) error {
+	_, err := tc.walletRegistry.ApproveAuthorizationDecrease(
+		common.HexToAddress(stakingProvider.String()),
+	)
+	return err
+}
+
 // EligibleStake returns the current value of the staking provider's
 // eligible stake. Eligible stake is defined as the currently authorized
 // stake minus the pending authorization decrease. Eligible stake
@@ -330,6 +464,13 @@ func (tc *TbtcChain) IsOperatorUpToDate() (bool, error) {
 	return tc.walletRegistry.IsOperatorUpToDate(tc.key.Address)
 }
 
+// OperatorWeight returns the current weight of the operator in
+// the sortition pool. If the operator is not in the sortition pool,
+// the returned weight is zero.
+func (tc *TbtcChain) OperatorWeight() (*big.Int, error) {
+	return tc.sortitionPool.GetPoolWeight(tc.key.Address)
+}
+
 // JoinSortitionPool executes a transaction to have the operator join the
 // sortition pool.
 func (tc *TbtcChain) JoinSortitionPool() error {
@@ -344,6 +485,18 @@ func (tc *TbtcChain) UpdateOperatorStatus() error {
 	return err
 }
 
+// JoinSortitionPoolGasEstimate returns the estimated gas of the transaction
+// having the operator join the sortition pool.
+func (tc *TbtcChain) JoinSortitionPoolGasEstimate() (uint64, error) {
+	return tc.walletRegistry.JoinSortitionPoolGasEstimate()
+}
+
+// UpdateOperatorStatusGasEstimate returns the estimated gas of the
+// transaction updating the operator's state in the sortition pool.
+func (tc *TbtcChain) UpdateOperatorStatusGasEstimate() (uint64, error) {
+	return tc.walletRegistry.UpdateOperatorStatusGasEstimate(tc.key.Address)
+}
+
 // IsEligibleForRewards checks whether the operator is eligible for rewards
 // or not.
 func (tc *TbtcChain) IsEligibleForRewards() (bool, error) {
@@ -362,6 +515,70 @@ func (tc *TbtcChain) RestoreRewardEligibility() error {
 	return err
 }
 
+// RewardEligibilityRestorableAt returns the earliest time at which the
+// operator's eligibility for rewards can be restored.
+func (tc *TbtcChain) RewardEligibilityRestorableAt() (time.Time, error) {
+	restorableAt, err := tc.sortitionPool.RewardsEligibilityRestorableAt(
+		tc.key.Address,
+	)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(restorableAt.Int64(), 0), nil
+}
+
+// RewardsIneligibilityReason returns the reason the operator was marked as
+// ineligible for rewards in the given block. Operators are marked as
+// ineligible when an inactivity claim is submitted or when a DKG result
+// naming them as misbehaved is approved.
+func (tc *TbtcChain) RewardsIneligibilityReason(
+	blockNumber uint64,
+) (string, error) {
+	claims, err := tc.walletRegistry.PastInactivityClaimedEvents(
+		blockNumber,
+		&blockNumber,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"could not get past inactivity claimed events: [%w]",
+			err,
+		)
+	}
+
+	if len(claims) > 0 {
+		return fmt.Sprintf(
+			"inactivity claimed for wallet [0x%x] by [%s]",
+			claims[0].WalletID,
+			claims[0].Notifier.Hex(),
+		), nil
+	}
+
+	results, err := tc.walletRegistry.PastDkgResultApprovedEvents(
+		blockNumber,
+		&blockNumber,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"could not get past DKG result approved events: [%w]",
+			err,
+		)
+	}
+
+	if len(results) > 0 {
+		return fmt.Sprintf(
+			"marked as misbehaved in DKG result [0x%x] approved by [%s]",
+			results[0].ResultHash,
+			results[0].Approver.Hex(),
+		), nil
+	}
+
+	return "", nil
+}
+
 // Returns true if the chaosnet phase is active, false otherwise.
 func (tc *TbtcChain) IsChaosnetActive() (bool, error) {
 	return tc.sortitionPool.IsChaosnetActive()
@@ -383,6 +600,120 @@ func (tc *TbtcChain) GetOperatorID(
 	)
 }
 
+// OnOperatorStatusChanged registers a callback that is invoked when an
+// on-chain event that may change the status of the operator in the sortition
+// pool is emitted: the operator registration, an authorization increase or
+// decrease of the operator's staking provider, or a change of the operator's
+// eligibility for rewards.
+func (tc *TbtcChain) OnOperatorStatusChanged(
+	handler func(event string, blockNumber uint64),
+) subscription.EventSubscription {
+	operatorFilter := []common.Address{tc.key.Address}
+
+	return subscribeOperatorStatusEvents(
+		&operatorStatusEventSources{
+			operatorRegistered: func(
+				handler func(stakingProvider common.Address, blockNumber uint64),
+			) subscription.EventSubscription {
+				return tc.walletRegistry.OperatorRegisteredEvent(
+					nil,
+					nil,
+					operatorFilter,
+				).OnEvent(
+					func(
+						stakingProvider common.Address,
+						operator common.Address,
+						blockNumber uint64,
+					) {
+						handler(stakingProvider, blockNumber)
+					},
+				)
+			},
+			authorizationIncreased: func(
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return tc.walletRegistry.AuthorizationIncreasedEvent(
+					nil,
+					nil,
+					operatorFilter,
+				).OnEvent(
+					func(
+						stakingProvider common.Address,
+						operator common.Address,
+						fromAmount *big.Int,
+						toAmount *big.Int,
+						blockNumber uint64,
+					) {
+						handler(blockNumber)
+					},
+				)
+			},
+			authorizationDecreaseRequested: func(
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return tc.walletRegistry.AuthorizationDecreaseRequestedEvent(
+					nil,
+					nil,
+					operatorFilter,
+				).OnEvent(
+					func(
+						stakingProvider common.Address,
+						operator common.Address,
+						fromAmount *big.Int,
+						toAmount *big.Int,
+						decreasingAt uint64,
+						blockNumber uint64,
+					) {
+						handler(blockNumber)
+					},
+				)
+			},
+			authorizationDecreaseApproved: func(
+				stakingProvider common.Address,
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return tc.walletRegistry.AuthorizationDecreaseApprovedEvent(
+					nil,
+					[]common.Address{stakingProvider},
+				).OnEvent(
+					func(stakingProvider common.Address, blockNumber uint64) {
+						handler(blockNumber)
+					},
+				)
+			},
+			rewardEligibilityRestored: func(
+				handler func(blockNumber uint64),
+			) subscription.EventSubscription {
+				return tc.sortitionPool.RewardEligibilityRestoredEvent(
+					nil,
+					operatorFilter,
+					nil,
+				).OnEvent(
+					func(operator common.Address, id uint32, blockNumber uint64) {
+						handler(blockNumber)
+					},
+				)
+			},
+			ineligibleForRewards: func(
+				handler func(operatorsIDs []uint32, blockNumber uint64),
+			) subscription.EventSubscription {
+				return tc.sortitionPool.IneligibleForRewardsEvent(nil).OnEvent(
+					func(ids []uint32, until *big.Int, blockNumber uint64) {
+						handler(ids, blockNumber)
+					},
+				)
+			},
+			operatorToStakingProvider: func() (common.Address, error) {
+				return tc.walletRegistry.OperatorToStakingProvider(tc.key.Address)
+			},
+			operatorID: func() (chain.OperatorID, error) {
+				return tc.GetOperatorID(chain.Address(tc.key.Address.Hex()))
+			},
+		},
+		handler,
+	)
+}
+
 // SelectGroup returns the group members selected for the current group
 // selection. The function returns an error if the chain's state does not allow
 // for group selection at the moment.
@@ -876,6 +1207,40 @@ func calculateDKGResultSignatureHash(
 	return dkg.ResultSignatureHash(crypto.Keccak256Hash(bytes)), nil
 }
 
+// CalculateDKGResultHash calculates the hash the WalletRegistry identifies
+// the given DKG result with, i.e. the keccak256 hash of the ABI-encoded
+// result.
+func (tc *TbtcChain) CalculateDKGResultHash(
+	result *tbtc.DKGChainResult,
+) (tbtc.DKGChainResultHash, error) {
+	walletRegistryAbi, err := ecdsaabi.WalletRegistryMetaData.GetAbi()
+	if err != nil {
+		return tbtc.DKGChainResultHash{}, fmt.Errorf(
+			"cannot get WalletRegistry ABI: [%v]",
+			err,
+		)
+	}
+
+	// The approveDkgResult function takes the DKG result as the only
+	// argument so its inputs are used to encode the result.
+	method, ok := walletRegistryAbi.Methods["approveDkgResult"]
+	if !ok {
+		return tbtc.DKGChainResultHash{}, fmt.Errorf(
+			"approveDkgResult method not found in WalletRegistry ABI",
+		)
+	}
+
+	bytes, err := method.Inputs.Pack(convertDkgResultToAbiType(result))
+	if err != nil {
+		return tbtc.DKGChainResultHash{}, fmt.Errorf(
+			"cannot encode DKG result: [%v]",
+			err,
+		)
+	}
+
+	return tbtc.DKGChainResultHash(crypto.Keccak256Hash(bytes)), nil
+}
+
 func (tc *TbtcChain) IsDKGResultValid(
 	dkgResult *tbtc.DKGChainResult,
 ) (bool, error) {
@@ -1328,7 +1693,8 @@ func (tc *TbtcChain) SubmitRedemptionProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
-) error {
+	options *spv.SubmissionOptions,
+) (*big.Int, error) {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
 		InputVector:  transaction.SerializeInputs(),
@@ -1355,7 +1721,7 @@ func (tc *TbtcChain) SubmitRedemptionProofWithReimbursement(
 		walletPublicKeyHash,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// The original estimate for this contract call is too low and the call
@@ -1364,17 +1730,22 @@ func (tc *TbtcChain) SubmitRedemptionProofWithReimbursement(
 	// Here we add a 20% margin to overcome the gas problems.
 	gasEstimateWithMargin := float64(gasEstimate) * float64(1.2)
 
-	_, err = tc.maintainerProxy.SubmitRedemptionProof(
+	submissionTx, err := tc.maintainerProxy.SubmitRedemptionProof(
 		bitcoinTxInfo,
 		redemptionProof,
 		utxo,
 		walletPublicKeyHash,
-		ethutil.TransactionOptions{
-			GasLimit: uint64(gasEstimateWithMargin),
-		},
+		proofSubmissionTransactionOptions(
+			uint64(gasEstimateWithMargin),
+			options,
+		),
 	)
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionMaxFee(submissionTx), nil
 }
 
 func buildRedemptionKey(
@@ -1406,7 +1777,8 @@ func (tc *TbtcChain) SubmitDepositSweepProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	vault common.Address,
-) error {
+	options *spv.SubmissionOptions,
+) (*big.Int, error) {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
 		InputVector:  transaction.SerializeInputs(),
@@ -1433,7 +1805,7 @@ func (tc *TbtcChain) SubmitDepositSweepProofWithReimbursement(
 		vault,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// The original estimate for this contract call is too low and the call
@@ -1442,17 +1814,22 @@ func (tc *TbtcChain) SubmitDepositSweepProofWithReimbursement(
 	// Here we add a 20% margin to overcome the gas problems.
 	gasEstimateWithMargin := float64(gasEstimate) * float64(1.2)
 
-	_, err = tc.maintainerProxy.SubmitDepositSweepProof(
+	submissionTx, err := tc.maintainerProxy.SubmitDepositSweepProof(
 		bitcoinTxInfo,
 		sweepProof,
 		utxo,
 		vault,
-		ethutil.TransactionOptions{
-			GasLimit: uint64(gasEstimateWithMargin),
-		},
+		proofSubmissionTransactionOptions(
+			uint64(gasEstimateWithMargin),
+			options,
+		),
 	)
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionMaxFee(submissionTx), nil
 }
 
 func (tc *TbtcChain) GetRedemptionParameters() (
@@ -1692,7 +2069,8 @@ func (tc *TbtcChain) SubmitMovingFundsProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
-) error {
+	options *spv.SubmissionOptions,
+) (*big.Int, error) {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
 		InputVector:  transaction.SerializeInputs(),
@@ -1719,7 +2097,7 @@ func (tc *TbtcChain) SubmitMovingFundsProofWithReimbursement(
 		walletPublicKeyHash,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// The original estimate for this contract call is too low and the call
@@ -1728,17 +2106,22 @@ func (tc *TbtcChain) SubmitMovingFundsProofWithReimbursement(
 	// Here we add a 20% margin to overcome the gas problems.
 	gasEstimateWithMargin := float64(gasEstimate) * float64(1.2)
 
-	_, err = tc.maintainerProxy.SubmitMovingFundsProof(
+	submissionTx, err := tc.maintainerProxy.SubmitMovingFundsProof(
 		bitcoinTxInfo,
 		movingFundsProof,
 		utxo,
 		walletPublicKeyHash,
-		ethutil.TransactionOptions{
-			GasLimit: uint64(gasEstimateWithMargin),
-		},
+		proofSubmissionTransactionOptions(
+			uint64(gasEstimateWithMargin),
+			options,
+		),
 	)
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionMaxFee(submissionTx), nil
 }
 
 func (tc *TbtcChain) ValidateRedemptionProposal(
@@ -1898,3 +2281,31 @@ func (tc *TbtcChain) ValidateMovingFundsProposal(
 
 	return nil
 }
+
+// proofSubmissionTransactionOptions builds the options of a proof submission
+// transaction with the given gas limit. Fee parameters not set in the given
+// submission options are estimated.
+func proofSubmissionTransactionOptions(
+	gasLimit uint64,
+	options *spv.SubmissionOptions,
+) ethutil.TransactionOptions {
+	transactionOptions := ethutil.TransactionOptions{
+		GasLimit: gasLimit,
+	}
+
+	if options != nil {
+		transactionOptions.GasFeeCap = options.GasFeeCap
+		transactionOptions.GasTipCap = options.GasTipCap
+	}
+
+	return transactionOptions
+}
+
+// transactionMaxFee returns the maximum fee that can be paid for the given
+// transaction, i.e. its gas limit multiplied by its gas fee cap.
+func transactionMaxFee(transaction *types.Transaction) *big.Int {
+	return new(big.Int).Mul(
+		new(big.Int).SetUint64(transaction.Gas()),
+		transaction.GasFeeCap(),
+	)
+}
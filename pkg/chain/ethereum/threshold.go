@@ -1,6 +1,10 @@
 package ethereum
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/keep-network/keep-core/pkg/chain"
 )
@@ -30,3 +34,35 @@ func (bc *baseChain) RolesOf(stakingProvider chain.Address) (
 		true,
 		nil
 }
+
+// HasStakeDelegation returns true if the staking provider has a stake
+// delegation, i.e. the stake owner is set in the staking contract.
+func (bc *baseChain) HasStakeDelegation(
+	stakingProvider chain.Address,
+) (bool, error) {
+	_, _, _, hasStakeDelegation, err := bc.RolesOf(stakingProvider)
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to check stake delegation for staking provider [%v]: [%w]",
+			stakingProvider,
+			err,
+		)
+	}
+
+	return hasStakeDelegation, nil
+}
+
+// toRemainingAuthorizationDecreaseDelay converts the remaining authorization
+// decrease delay, in seconds, returned by the application contracts. The
+// contracts return the maximum uint64 value if the operator has not updated
+// its status in the sortition pool since the decrease was requested, in which
+// case the returned boolean flag is false.
+func toRemainingAuthorizationDecreaseDelay(
+	seconds uint64,
+) (time.Duration, bool) {
+	if seconds == math.MaxUint64 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
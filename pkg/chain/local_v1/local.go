@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/ipfs/go-log"
 
@@ -183,9 +184,14 @@ func ConnectWithKey(
 
 	resultPublicationBlockStep := uint64(3)
 
+	// Mirror the chain that requires a 25% safety margin above the honest
+	// threshold.
+	groupQuorum := honestThreshold + (groupSize-honestThreshold)/2
+
 	return &localChain{
 		relayConfig: &beaconchain.Config{
 			GroupSize:                  groupSize,
+			GroupQuorum:                groupQuorum,
 			HonestThreshold:            honestThreshold,
 			ResultPublicationBlockStep: resultPublicationBlockStep,
 			RelayEntryTimeout:          resultPublicationBlockStep * uint64(groupSize),
@@ -250,11 +256,11 @@ func (c *localChain) SubmitDKGResult(
 	resultToPublish *beaconchain.DKGResult,
 	signatures map[beaconchain.GroupMemberIndex][]byte,
 ) error {
-	if len(signatures) < c.relayConfig.HonestThreshold {
+	if len(signatures) < c.relayConfig.GroupQuorum {
 		return fmt.Errorf(
-			"failed to submit result with [%v] signatures for honest threshold [%v]",
+			"failed to submit result with [%v] signatures for group quorum [%v]",
 			len(signatures),
-			c.relayConfig.HonestThreshold,
+			c.relayConfig.GroupQuorum,
 		)
 	}
 
@@ -371,6 +377,9 @@ func (c *localChain) CurrentRequestGroupPublicKey() ([]byte, error) {
 }
 
 func (c *localChain) GetRelayEntryTimeoutReports() []uint64 {
+	c.relayEntryTimeoutReportsMutex.Lock()
+	defer c.relayEntryTimeoutReportsMutex.Unlock()
+
 	return c.relayEntryTimeoutReports
 }
 
@@ -406,6 +415,10 @@ func (c *localChain) IsOperatorUpToDate() (bool, error) {
 	panic("unsupported")
 }
 
+func (c *localChain) OperatorWeight() (*big.Int, error) {
+	panic("unsupported")
+}
+
 func (c *localChain) JoinSortitionPool() error {
 	panic("unsupported")
 }
@@ -426,6 +439,14 @@ func (c *localChain) RestoreRewardEligibility() error {
 	panic("unsupported")
 }
 
+func (c *localChain) RewardEligibilityRestorableAt() (time.Time, error) {
+	panic("unsupported")
+}
+
+func (c *localChain) RewardsIneligibilityReason(blockNumber uint64) (string, error) {
+	panic("unsupported")
+}
+
 func (c *localChain) IsChaosnetActive() (bool, error) {
 	panic("unsupported")
 }
@@ -434,6 +455,12 @@ func (c *localChain) IsBetaOperator() (bool, error) {
 	panic("unsupported")
 }
 
+func (c *localChain) OnOperatorStatusChanged(
+	handler func(event string, blockNumber uint64),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
 func (c *localChain) GetOperatorID(
 	operatorAddress chain.Address,
 ) (chain.OperatorID, error) {
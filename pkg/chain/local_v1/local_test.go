@@ -105,6 +105,9 @@ func TestLocalOnGroupRegistered(t *testing.T) {
 		2: []byte{102},
 		3: []byte{103},
 		4: []byte{104},
+		5: []byte{105},
+		6: []byte{106},
+		7: []byte{107},
 	}
 
 	err := chainHandle.SubmitDKGResult(memberIndex, dkgResult, signatures)
@@ -154,6 +157,9 @@ func TestLocalOnGroupRegisteredUnsubscribed(t *testing.T) {
 		2: []byte{102},
 		3: []byte{103},
 		4: []byte{104},
+		5: []byte{105},
+		6: []byte{106},
+		7: []byte{107},
 	}
 
 	err := chainHandle.SubmitDKGResult(memberIndex, dkgResult, signatures)
@@ -193,6 +199,9 @@ func TestLocalOnDKGResultSubmitted(t *testing.T) {
 		2: []byte{102},
 		3: []byte{103},
 		4: []byte{104},
+		5: []byte{105},
+		6: []byte{106},
+		7: []byte{107},
 	}
 
 	err := chainHandle.SubmitDKGResult(memberIndex, dkgResult, signatures)
@@ -243,6 +252,9 @@ func TestLocalOnDKGResultSubmittedUnsubscribed(t *testing.T) {
 		2: []byte{102},
 		3: []byte{103},
 		4: []byte{104},
+		5: []byte{105},
+		6: []byte{106},
+		7: []byte{107},
 	}
 
 	err := chainHandle.SubmitDKGResult(memberIndex, dkgResult, signatures)
@@ -491,6 +503,9 @@ func TestLocalSubmitDKGResult(t *testing.T) {
 		2: []byte{102},
 		3: []byte{103},
 		4: []byte{104},
+		5: []byte{105},
+		6: []byte{106},
+		7: []byte{107},
 	}
 
 	err := chainHandle.SubmitDKGResult(memberIndex, result, signatures)
@@ -511,6 +526,7 @@ func TestLocalSubmitDKGResult(t *testing.T) {
 func TestLocalSubmitDKGResultWithSignatures(t *testing.T) {
 	groupSize := 5
 	honestThreshold := 3
+	groupQuorum := 4
 
 	chainHandle := Connect(groupSize, honestThreshold)
 
@@ -520,30 +536,23 @@ func TestLocalSubmitDKGResultWithSignatures(t *testing.T) {
 	}{
 		"no signatures": {
 			signatures:    map[beaconchain.GroupMemberIndex][]byte{},
-			expectedError: fmt.Errorf("failed to submit result with [0] signatures for honest threshold [%v]", honestThreshold),
+			expectedError: fmt.Errorf("failed to submit result with [0] signatures for group quorum [%v]", groupQuorum),
 		},
 		"one signature": {
 			signatures: map[beaconchain.GroupMemberIndex][]byte{
 				1: []byte{101},
 			},
-			expectedError: fmt.Errorf("failed to submit result with [1] signatures for honest threshold [%v]", honestThreshold),
+			expectedError: fmt.Errorf("failed to submit result with [1] signatures for group quorum [%v]", groupQuorum),
 		},
-		"one less signature than threshold": {
-			signatures: map[beaconchain.GroupMemberIndex][]byte{
-				1: []byte{101},
-				2: []byte{102},
-			},
-			expectedError: fmt.Errorf("failed to submit result with [2] signatures for honest threshold [%v]", honestThreshold),
-		},
-		"threshold signatures": {
+		"honest threshold signatures": {
 			signatures: map[beaconchain.GroupMemberIndex][]byte{
 				1: []byte{101},
 				2: []byte{102},
 				3: []byte{103},
 			},
-			expectedError: nil,
+			expectedError: fmt.Errorf("failed to submit result with [3] signatures for group quorum [%v]", groupQuorum),
 		},
-		"one more signature than threshold": {
+		"group quorum signatures": {
 			signatures: map[beaconchain.GroupMemberIndex][]byte{
 				1: []byte{101},
 				2: []byte{102},
@@ -2,11 +2,13 @@ package clientinfo
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/sortition"
 )
 
 // Diagnostics describes data structure returned by the diagnostics endpoint.
@@ -37,9 +39,62 @@ type Chain struct {
 	LatestBlockNumber uint `json:"latest_block_number"`
 }
 
+// BroadcastChannel describes data structure of broadcast channel statistics.
+type BroadcastChannel struct {
+	ReceivedMessages     uint64  `json:"received_messages"`
+	ReceivedMessagesRate float64 `json:"received_messages_rate"`
+	SentMessages         uint64  `json:"sent_messages"`
+	SentMessagesRate     float64 `json:"sent_messages_rate"`
+}
+
 // ApplicationInfo describes data structure of application information.
 type ApplicationInfo map[string]interface{}
 
+// SortitionPool describes data structure of the status of the operator
+// monitoring in the sortition pool of an application.
+type SortitionPool struct {
+	Application         string     `json:"application"`
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	PermanentError      bool       `json:"permanent_error"`
+	LastSuccessfulCheck *time.Time `json:"last_successful_check,omitempty"`
+	NextCheck           time.Time  `json:"next_check"`
+	// Operator is the operator status observed by the last status check,
+	// if any check could read it.
+	Operator *SortitionPoolOperator `json:"operator,omitempty"`
+	// LastTransaction is the last transaction joining the pool or updating
+	// the operator in it, if any was submitted.
+	LastTransaction *SortitionPoolTransaction `json:"last_transaction,omitempty"`
+}
+
+// SortitionPoolTransaction describes data structure of the outcome of
+// a transaction joining the sortition pool or updating the operator in it.
+type SortitionPoolTransaction struct {
+	Action      string    `json:"action"`
+	Transaction string    `json:"transaction"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// SortitionPoolOperator describes data structure of the status of the
+// operator in the sortition pool of an application.
+type SortitionPoolOperator struct {
+	Registered                    bool   `json:"registered"`
+	StakingProvider               string `json:"staking_provider,omitempty"`
+	InPool                        bool   `json:"in_pool"`
+	UpToDate                      bool   `json:"up_to_date"`
+	EligibleForRewards            bool   `json:"eligible_for_rewards"`
+	CanRestoreRewardEligibility   bool   `json:"can_restore_reward_eligibility"`
+	PoolLocked                    bool   `json:"pool_locked"`
+	Weight                        string `json:"weight,omitempty"`
+	EligibleStake                 string `json:"eligible_stake,omitempty"`
+	NextAction                    string `json:"next_action"`
+	MissingRegistrationStep       string `json:"missing_registration_step,omitempty"`
+	RewardEligibilityRestorableAt string `json:"reward_eligibility_restorable_at,omitempty"`
+	TransactionPolicyHold         string `json:"transaction_policy_hold,omitempty"`
+}
+
 // RegisterConnectedPeersSource registers the diagnostics source providing
 // information about connected peers.
 func (r *Registry) RegisterConnectedPeersSource(
@@ -83,6 +138,39 @@ func (r *Registry) RegisterConnectedPeersSource(
 	})
 }
 
+// RegisterBroadcastChannelsSource registers the diagnostics source providing
+// message statistics of every broadcast channel. Nothing is registered if
+// the provider does not collect network statistics.
+func (r *Registry) RegisterBroadcastChannelsSource(netProvider net.Provider) {
+	source, ok := netProvider.(net.NetworkStatsSource)
+	if !ok {
+		return
+	}
+
+	r.RegisterDiagnosticSource("broadcast_channels", func() string {
+		channels := make(map[string]BroadcastChannel)
+		for name, topicStats := range source.NetworkStats().Topics {
+			channels[name] = BroadcastChannel{
+				ReceivedMessages:     topicStats.ReceivedMessages,
+				ReceivedMessagesRate: topicStats.ReceivedMessagesRate,
+				SentMessages:         topicStats.SentMessages,
+				SentMessagesRate:     topicStats.SentMessagesRate,
+			}
+		}
+
+		bytes, err := json.Marshal(channels)
+		if err != nil {
+			logger.Errorf(
+				"error on serializing broadcast channels to JSON: [%v]",
+				err,
+			)
+			return ""
+		}
+
+		return string(bytes)
+	})
+}
+
 // RegisterClientInfoSource registers the diagnostics source providing
 // information about the client itself.
 func (r *Registry) RegisterClientInfoSource(
@@ -190,3 +278,84 @@ func (r *Registry) RegisterApplicationSource(
 		return string(bytes)
 	})
 }
+
+// RegisterSortitionPoolsSource registers the diagnostics source providing
+// the status of the operator monitoring in sortition pools of all
+// applications.
+func (r *Registry) RegisterSortitionPoolsSource(
+	poolsMonitor *sortition.PoolsMonitor,
+) {
+	r.RegisterDiagnosticSource("sortition_pools", func() string {
+		status := poolsMonitor.Status()
+
+		pools := make([]SortitionPool, len(status))
+		for i, applicationStatus := range status {
+			health := applicationStatus.Health
+
+			pools[i] = SortitionPool{
+				Application:         applicationStatus.Application,
+				Healthy:             health.Healthy,
+				ConsecutiveFailures: health.ConsecutiveFailures,
+				PermanentError:      health.PermanentError,
+				NextCheck:           applicationStatus.NextCheck,
+			}
+			if health.LastError != nil {
+				pools[i].LastError = health.LastError.Error()
+			}
+			if !health.LastSuccessfulCheck.IsZero() {
+				lastSuccessfulCheck := health.LastSuccessfulCheck
+				pools[i].LastSuccessfulCheck = &lastSuccessfulCheck
+			}
+			if operatorStatus := applicationStatus.OperatorStatus; operatorStatus != nil {
+				pools[i].Operator = newSortitionPoolOperator(operatorStatus)
+			}
+			if transaction := applicationStatus.LastPoolTransaction; transaction != nil {
+				pools[i].LastTransaction = &SortitionPoolTransaction{
+					Action:      string(transaction.Action),
+					Transaction: transaction.Transaction,
+					SubmittedAt: transaction.SubmittedAt,
+				}
+				if transaction.Err != nil {
+					pools[i].LastTransaction.Error = transaction.Err.Error()
+				}
+			}
+		}
+
+		bytes, err := json.Marshal(pools)
+		if err != nil {
+			logger.Errorf("error on serializing sortition pools to JSON: [%v]", err)
+			return ""
+		}
+
+		return string(bytes)
+	})
+}
+
+func newSortitionPoolOperator(
+	status *sortition.OperatorStatus,
+) *SortitionPoolOperator {
+	operator := &SortitionPoolOperator{
+		Registered:                  status.IsRegistered,
+		StakingProvider:             status.StakingProvider.String(),
+		InPool:                      status.IsInPool,
+		UpToDate:                    status.IsUpToDate,
+		EligibleForRewards:          status.IsEligibleForRewards,
+		CanRestoreRewardEligibility: status.CanRestoreRewardEligibility,
+		PoolLocked:                  status.IsPoolLocked,
+		NextAction:                  string(status.NextAction),
+		MissingRegistrationStep:     string(status.MissingRegistrationStep),
+		TransactionPolicyHold:       status.TransactionPolicyHold,
+	}
+	if status.Weight != nil {
+		operator.Weight = status.Weight.String()
+	}
+	if status.EligibleStake != nil {
+		operator.EligibleStake = status.EligibleStake.String()
+	}
+	if !status.RewardEligibilityRestorableAt.IsZero() {
+		operator.RewardEligibilityRestorableAt =
+			status.RewardEligibilityRestorableAt.Format(time.RFC3339)
+	}
+
+	return operator
+}
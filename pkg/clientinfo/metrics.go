@@ -2,12 +2,16 @@ package clientinfo
 
 import (
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/keep-network/keep-common/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/sortition"
 )
 
 type Source func() float64
@@ -19,6 +23,28 @@ const (
 	EthConnectivityMetricName         = "eth_connectivity"
 	BtcConnectivityMetricName         = "btc_connectivity"
 	ClientInfoMetricName              = "client_info"
+
+	BroadcastCompressedMessagesCountMetricName = "broadcast_compressed_messages_count"
+	BroadcastUncompressedBytesMetricName       = "broadcast_uncompressed_bytes"
+	BroadcastCompressedBytesMetricName         = "broadcast_compressed_bytes"
+	BroadcastDuplicateMessagesCountMetricName  = "broadcast_duplicate_messages_count"
+
+	NetworkBytesInMetricName                = "network_bytes_in"
+	NetworkBytesOutMetricName               = "network_bytes_out"
+	NetworkBytesInRateMetricName            = "network_bytes_in_rate"
+	NetworkBytesOutRateMetricName           = "network_bytes_out_rate"
+	NetworkHandshakeFailuresCountMetricName = "network_handshake_failures_count"
+	BootstrapPeersCountMetricName           = "bootstrap_peers_count"
+	BootstrapFailedRoundsCountMetricName    = "bootstrap_failed_rounds_count"
+	BroadcastReceivedMessagesRateMetricName = "broadcast_received_messages_rate"
+	BroadcastSentMessagesRateMetricName     = "broadcast_sent_messages_rate"
+
+	// ParameterPoolMetricNamePrefix prefixes names of metrics of parameter
+	// pools, followed by the pool name.
+	ParameterPoolMetricNamePrefix = "parameter_pool"
+	// SortitionPoolMetricNamePrefix prefixes names of metrics of the
+	// operator status in sortition pools, followed by the application name.
+	SortitionPoolMetricNamePrefix = "sortition_pool"
 )
 
 const (
@@ -80,6 +106,138 @@ func (r *Registry) ObserveConnectedBootstrapCount(
 	)
 }
 
+// ObserveBroadcastCompression triggers an observation process of
+// the broadcast_compressed_messages_count, broadcast_uncompressed_bytes, and
+// broadcast_compressed_bytes metrics. Nothing is observed if the provider
+// does not compress broadcast messages.
+func (r *Registry) ObserveBroadcastCompression(
+	netProvider net.Provider,
+	tick time.Duration,
+) {
+	source, ok := netProvider.(net.CompressionStatsSource)
+	if !ok {
+		logger.Infof(
+			"network provider [%v] does not support compression",
+			netProvider.Type(),
+		)
+		return
+	}
+
+	tick = validateTick(tick, DefaultNetworkMetricsTick)
+
+	r.observe(
+		BroadcastCompressedMessagesCountMetricName,
+		func() float64 {
+			return float64(source.CompressionStats().CompressedMessages)
+		},
+		tick,
+	)
+
+	r.observe(
+		BroadcastUncompressedBytesMetricName,
+		func() float64 {
+			return float64(source.CompressionStats().UncompressedBytes)
+		},
+		tick,
+	)
+
+	r.observe(
+		BroadcastCompressedBytesMetricName,
+		func() float64 {
+			return float64(source.CompressionStats().CompressedBytes)
+		},
+		tick,
+	)
+}
+
+// ObserveBroadcastDuplicates triggers an observation process of the
+// broadcast_duplicate_messages_count metric. Nothing is observed if the
+// provider does not report dropped duplicate broadcast messages.
+func (r *Registry) ObserveBroadcastDuplicates(
+	netProvider net.Provider,
+	tick time.Duration,
+) {
+	source, ok := netProvider.(net.DuplicateMessagesSource)
+	if !ok {
+		logger.Infof(
+			"network provider [%v] does not report duplicate messages",
+			netProvider.Type(),
+		)
+		return
+	}
+
+	r.observe(
+		BroadcastDuplicateMessagesCountMetricName,
+		func() float64 {
+			return float64(source.DuplicateMessagesCount())
+		},
+		validateTick(tick, DefaultNetworkMetricsTick),
+	)
+}
+
+// ObserveNetworkStats triggers an observation process of the network traffic,
+// handshake failures, bootstrap health, and broadcast message rate metrics.
+// Message rates are summed over all broadcast channels; rates of individual
+// channels are exposed by the broadcast channels diagnostics source. Nothing
+// is observed if the provider does not collect network statistics.
+func (r *Registry) ObserveNetworkStats(
+	netProvider net.Provider,
+	tick time.Duration,
+) {
+	source, ok := netProvider.(net.NetworkStatsSource)
+	if !ok {
+		logger.Infof(
+			"network provider [%v] does not collect network statistics",
+			netProvider.Type(),
+		)
+		return
+	}
+
+	tick = validateTick(tick, DefaultNetworkMetricsTick)
+
+	inputs := map[string]Source{
+		NetworkBytesInMetricName: func() float64 {
+			return float64(source.NetworkStats().BytesIn)
+		},
+		NetworkBytesOutMetricName: func() float64 {
+			return float64(source.NetworkStats().BytesOut)
+		},
+		NetworkBytesInRateMetricName: func() float64 {
+			return source.NetworkStats().BytesInRate
+		},
+		NetworkBytesOutRateMetricName: func() float64 {
+			return source.NetworkStats().BytesOutRate
+		},
+		NetworkHandshakeFailuresCountMetricName: func() float64 {
+			return float64(source.NetworkStats().HandshakeFailures)
+		},
+		BootstrapPeersCountMetricName: func() float64 {
+			return float64(source.NetworkStats().BootstrapPeers)
+		},
+		BootstrapFailedRoundsCountMetricName: func() float64 {
+			return float64(source.NetworkStats().BootstrapFailedRounds)
+		},
+		BroadcastReceivedMessagesRateMetricName: func() float64 {
+			rate := 0.0
+			for _, topicStats := range source.NetworkStats().Topics {
+				rate += topicStats.ReceivedMessagesRate
+			}
+			return rate
+		},
+		BroadcastSentMessagesRateMetricName: func() float64 {
+			rate := 0.0
+			for _, topicStats := range source.NetworkStats().Topics {
+				rate += topicStats.SentMessagesRate
+			}
+			return rate
+		},
+	}
+
+	for name, input := range inputs {
+		r.observe(name, input, tick)
+	}
+}
+
 // ObserveEthConnectivity triggers an observation process of the
 // eth_connectivity metric.
 func (r *Registry) ObserveEthConnectivity(
@@ -139,6 +297,184 @@ func (r *Registry) ObserveApplicationSource(
 	}
 }
 
+// ParameterPoolMetricsSource is a parameter pool exposing its metrics.
+type ParameterPoolMetricsSource interface {
+	Metrics() *generator.PoolMetrics
+}
+
+// ObserveParameterPool triggers an observation process of metrics of the
+// parameter pool with the given name. Names of the metrics start with
+// parameter_pool_<name>. Generation durations are exposed as a cumulative
+// histogram, with one metric per bucket named after the bucket upper bound
+// in seconds.
+func (r *Registry) ObserveParameterPool(
+	name string,
+	pool ParameterPoolMetricsSource,
+) {
+	prefix := fmt.Sprintf(
+		"%s_%s",
+		ParameterPoolMetricNamePrefix,
+		strings.ReplaceAll(name, "-", "_"),
+	)
+
+	inputs := map[string]Source{
+		"size": func() float64 {
+			return float64(pool.Metrics().Size)
+		},
+		"target_size": func() float64 {
+			return float64(pool.Metrics().TargetSize)
+		},
+		"generated_count": func() float64 {
+			return float64(pool.Metrics().GeneratedCount)
+		},
+		"taken_count": func() float64 {
+			return float64(pool.Metrics().TakenCount)
+		},
+		"persistence_errors_count": func() float64 {
+			return float64(pool.Metrics().PersistenceErrorsCount)
+		},
+		"generation_failures_count": func() float64 {
+			return float64(pool.Metrics().FailuresCount)
+		},
+		"generation_timeouts_count": func() float64 {
+			return float64(pool.Metrics().TimeoutsCount)
+		},
+		"consecutive_generation_failures": func() float64 {
+			return float64(pool.Metrics().ConsecutiveFailures)
+		},
+		"fill_rate_per_hour": func() float64 {
+			return pool.Metrics().FillRate
+		},
+		"drain_rate_per_hour": func() float64 {
+			return pool.Metrics().DrainRate
+		},
+		"generation_duration_seconds_count": func() float64 {
+			return float64(pool.Metrics().GenerationDuration.Count)
+		},
+		"generation_duration_seconds_sum": func() float64 {
+			return pool.Metrics().GenerationDuration.Sum.Seconds()
+		},
+	}
+
+	for i, bound := range generator.GenerationDurationBuckets {
+		i := i
+		bucketName := fmt.Sprintf(
+			"generation_duration_seconds_le_%v",
+			bound.Seconds(),
+		)
+		inputs[bucketName] = func() float64 {
+			return float64(pool.Metrics().GenerationDuration.Counts[i])
+		}
+	}
+
+	for k, v := range inputs {
+		r.observe(
+			fmt.Sprintf("%s_%s", prefix, k),
+			v,
+			ApplicationMetricsTick,
+		)
+	}
+}
+
+// ObserveSortitionPools triggers an observation process of metrics of the
+// operator status in sortition pools of all applications registered in the
+// pools monitor. Names of the metrics start with sortition_pool_<application>.
+// Metrics of the operator status are zero until a status check reads it.
+// Applications registered in the monitor later are not observed.
+func (r *Registry) ObserveSortitionPools(poolsMonitor *sortition.PoolsMonitor) {
+	for _, applicationStatus := range poolsMonitor.Status() {
+		application := applicationStatus.Application
+
+		currentStatus := func() *sortition.ApplicationStatus {
+			for _, status := range poolsMonitor.Status() {
+				if status.Application == application {
+					return status
+				}
+			}
+			return nil
+		}
+
+		operatorSource := func(
+			value func(status *sortition.OperatorStatus) float64,
+		) Source {
+			return func() float64 {
+				applicationStatus := currentStatus()
+				if applicationStatus == nil ||
+					applicationStatus.OperatorStatus == nil {
+					return 0
+				}
+				return value(applicationStatus.OperatorStatus)
+			}
+		}
+
+		transactionSource := func(
+			value func(transaction *sortition.TransactionOutcome) float64,
+		) Source {
+			return func() float64 {
+				applicationStatus := currentStatus()
+				if applicationStatus == nil ||
+					applicationStatus.LastPoolTransaction == nil {
+					return 0
+				}
+				return value(applicationStatus.LastPoolTransaction)
+			}
+		}
+
+		inputs := map[string]Source{
+			"in_pool": operatorSource(func(status *sortition.OperatorStatus) float64 {
+				return boolToFloat(status.IsInPool)
+			}),
+			"up_to_date": operatorSource(func(status *sortition.OperatorStatus) float64 {
+				return boolToFloat(status.IsUpToDate)
+			}),
+			"eligible_for_rewards": operatorSource(func(status *sortition.OperatorStatus) float64 {
+				return boolToFloat(status.IsEligibleForRewards)
+			}),
+			"weight": operatorSource(func(status *sortition.OperatorStatus) float64 {
+				return bigIntToFloat(status.Weight)
+			}),
+			"eligible_stake": operatorSource(func(status *sortition.OperatorStatus) float64 {
+				return bigIntToFloat(status.EligibleStake)
+			}),
+			"last_transaction_timestamp": transactionSource(func(transaction *sortition.TransactionOutcome) float64 {
+				return float64(transaction.SubmittedAt.Unix())
+			}),
+			"last_transaction_failed": transactionSource(func(transaction *sortition.TransactionOutcome) float64 {
+				return boolToFloat(transaction.Err != nil)
+			}),
+		}
+
+		for k, v := range inputs {
+			r.observe(
+				fmt.Sprintf(
+					"%s_%s_%s",
+					SortitionPoolMetricNamePrefix,
+					application,
+					k,
+				),
+				v,
+				ApplicationMetricsTick,
+			)
+		}
+	}
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func bigIntToFloat(value *big.Int) float64 {
+	if value == nil {
+		return 0
+	}
+
+	result, _ := new(big.Float).SetInt(value).Float64()
+	return result
+}
+
 // RegisterMetricClientInfo registers static client information labels for metrics.
 func (r *Registry) RegisterMetricClientInfo(version string) {
 	_, err := r.NewMetricInfo(
@@ -1,14 +1,20 @@
 package firewall
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/ipfs/go-log"
+
 	"github.com/keep-network/keep-common/pkg/cache"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/operator"
 )
 
+var logger = log.Logger("keep-firewall")
+
 // Application defines functionalities for operator verification in the firewall.
 type Application interface {
 	// IsRecognized returns true if the application recognizes the operator
@@ -16,6 +22,55 @@ type Application interface {
 	IsRecognized(operatorPublicKey *operator.PublicKey) (bool, error)
 }
 
+// Requirement defines an additional condition the remote peer recognized by
+// an application has to meet to join the network, e.g. a minimum stake.
+type Requirement interface {
+	// IsSatisfied returns true if the operator meets the requirement.
+	IsSatisfied(operatorPublicKey *operator.PublicKey) (bool, error)
+}
+
+// RequirementFunc is an adapter allowing the use of ordinary functions as
+// firewall requirements.
+type RequirementFunc func(operatorPublicKey *operator.PublicKey) (bool, error)
+
+// IsSatisfied calls rf(operatorPublicKey).
+func (rf RequirementFunc) IsSatisfied(
+	operatorPublicKey *operator.PublicKey,
+) (bool, error) {
+	return rf(operatorPublicKey)
+}
+
+// AuthorizationSource defines functionalities for verification of the
+// operator's stake authorization.
+type AuthorizationSource interface {
+	// HasMinimumAuthorization returns true if the operator's staking provider
+	// has at least the minimum stake authorization required by the source.
+	HasMinimumAuthorization(operatorPublicKey *operator.PublicKey) (bool, error)
+}
+
+// MinimumAuthorization returns a requirement satisfied if the operator has
+// at least the minimum stake authorization in any of the given sources.
+func MinimumAuthorization(sources ...AuthorizationSource) Requirement {
+	return RequirementFunc(func(operatorPublicKey *operator.PublicKey) (bool, error) {
+		for _, source := range sources {
+			hasMinimumAuthorization, err := source.HasMinimumAuthorization(
+				operatorPublicKey,
+			)
+			if err != nil {
+				return false, fmt.Errorf(
+					"could not check minimum authorization: [%w]",
+					err,
+				)
+			}
+			if hasMinimumAuthorization {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
+
 // Disabled is an empty Firewall implementation enforcing no rules
 // on the connection.
 var Disabled = &noFirewall{}
@@ -63,27 +118,104 @@ const (
 	NegativeIsRecognizedCachePeriod = 1 * time.Hour
 )
 
-var errNotRecognized = fmt.Errorf(
-	"remote peer has not been recognized by any application",
+var (
+	errNotRecognized = fmt.Errorf(
+		"remote peer has not been recognized by any application",
+	)
+	errRequirementNotSatisfied = fmt.Errorf(
+		"remote peer does not satisfy firewall requirements",
+	)
 )
 
+// Config defines the configuration of the firewall policy.
+type Config struct {
+	// MinimumAuthorization requires non-allowlisted peers to have at least
+	// the minimum stake authorization in any of the applications.
+	MinimumAuthorization bool
+	// PositiveCachePeriod is the time period a successful validation result
+	// is cached for. Peers are validated against the chain again once the
+	// period elapses.
+	PositiveCachePeriod time.Duration
+	// NegativeCachePeriod is the time period a failed validation result is
+	// cached for.
+	NegativeCachePeriod time.Duration
+	// GracePeriod is the time period a peer that stopped passing validation
+	// is still accepted for. It protects against dropping peers due to
+	// short-lived stake changes or chain client failures. Zero value
+	// disables the grace period.
+	GracePeriod time.Duration
+}
+
+// PolicyOption is an option of the firewall policy.
+type PolicyOption func(*anyApplicationPolicy)
+
+// WithRequirements sets additional requirements the remote peer recognized
+// by an application has to meet to pass validation. Allowlisted peers are not
+// checked against the requirements.
+func WithRequirements(requirements ...Requirement) PolicyOption {
+	return func(aap *anyApplicationPolicy) {
+		aap.requirements = append(aap.requirements, requirements...)
+	}
+}
+
+// WithCachePeriods sets periods positive and negative validation results are
+// cached for. Non-positive periods leave the defaults unchanged.
+func WithCachePeriods(positive, negative time.Duration) PolicyOption {
+	return func(aap *anyApplicationPolicy) {
+		if positive > 0 {
+			aap.positiveResultCache = cache.NewTimeCache(positive)
+		}
+		if negative > 0 {
+			aap.negativeResultCache = cache.NewTimeCache(negative)
+		}
+	}
+}
+
+// WithGracePeriod sets the time period a peer that stopped passing validation
+// is still accepted for.
+func WithGracePeriod(gracePeriod time.Duration) PolicyOption {
+	return func(aap *anyApplicationPolicy) {
+		aap.gracePeriod = gracePeriod
+	}
+}
+
+// AnyApplicationPolicy returns a firewall accepting allowlisted peers and
+// peers recognized by any of the applications and meeting all requirements
+// set with the options.
 func AnyApplicationPolicy(
 	applications []Application,
 	allowList *AllowList,
+	options ...PolicyOption,
 ) net.Firewall {
-	return &anyApplicationPolicy{
+	policy := &anyApplicationPolicy{
 		applications:        applications,
 		allowList:           allowList,
 		positiveResultCache: cache.NewTimeCache(PositiveIsRecognizedCachePeriod),
 		negativeResultCache: cache.NewTimeCache(NegativeIsRecognizedCachePeriod),
 	}
+
+	for _, option := range options {
+		option(policy)
+	}
+
+	return policy
 }
 
 type anyApplicationPolicy struct {
 	applications        []Application
+	requirements        []Requirement
 	allowList           *AllowList
 	positiveResultCache *cache.TimeCache
 	negativeResultCache *cache.TimeCache
+
+	gracePeriod time.Duration
+	// graceMutex protects validPeers and graceDeadlines.
+	graceMutex sync.Mutex
+	// validPeers are peers that passed the last validation against the chain.
+	validPeers map[string]bool
+	// graceDeadlines are deadlines until which peers that stopped passing
+	// validation are still accepted.
+	graceDeadlines map[string]time.Time
 }
 
 // Validate checks whether the given operator meets the conditions to join
@@ -121,6 +253,43 @@ func (aap *anyApplicationPolicy) Validate(
 		return errNotRecognized
 	}
 
+	if err := aap.validateOnChain(remotePeerPublicKey); err != nil {
+		if aap.isInGracePeriod(remotePeerPublicKeyHex) {
+			logger.Warnf(
+				"accepting remote peer [%v] in the grace period despite "+
+					"failed validation: [%v]",
+				remotePeerPublicKeyHex,
+				err,
+			)
+			return nil
+		}
+
+		// Chain client errors are not cached; the validation is retried
+		// on the next attempt.
+		if errors.Is(err, errNotRecognized) ||
+			errors.Is(err, errRequirementNotSatisfied) {
+			// `IsRecognized` will not be called again for the entire
+			// caching period.
+			aap.negativeResultCache.Add(remotePeerPublicKeyHex)
+		}
+
+		return err
+	}
+
+	aap.markValid(remotePeerPublicKeyHex)
+
+	// Add this address to the positive result cache.
+	// `IsRecognized` will not be called again for the entire caching period.
+	aap.positiveResultCache.Add(remotePeerPublicKeyHex)
+
+	return nil
+}
+
+// validateOnChain checks whether the given operator is recognized by any of
+// the applications and meets all the requirements.
+func (aap *anyApplicationPolicy) validateOnChain(
+	remotePeerPublicKey *operator.PublicKey,
+) error {
 	validationSuccessful := false
 	for _, application := range aap.applications {
 		isRecognized, err := application.IsRecognized(remotePeerPublicKey)
@@ -137,15 +306,68 @@ func (aap *anyApplicationPolicy) Validate(
 	}
 
 	if !validationSuccessful {
-		// Add this address to the negative result cache.
-		// `IsRecognized` will not be called again for the entire caching period.
-		aap.negativeResultCache.Add(remotePeerPublicKeyHex)
 		return errNotRecognized
 	}
 
-	// Add this address to the positive result cache.
-	// `IsRecognized` will not be called again for the entire caching period.
-	aap.positiveResultCache.Add(remotePeerPublicKeyHex)
+	for _, requirement := range aap.requirements {
+		isSatisfied, err := requirement.IsSatisfied(remotePeerPublicKey)
+		if err != nil {
+			return fmt.Errorf(
+				"could not validate if remote peer satisfies requirement: [%w]",
+				err,
+			)
+		}
+		if !isSatisfied {
+			return errRequirementNotSatisfied
+		}
+	}
 
 	return nil
 }
+
+// markValid records the peer passed validation and ends its grace period,
+// if any.
+func (aap *anyApplicationPolicy) markValid(remotePeerPublicKeyHex string) {
+	aap.graceMutex.Lock()
+	defer aap.graceMutex.Unlock()
+
+	if aap.validPeers == nil {
+		aap.validPeers = make(map[string]bool)
+		aap.graceDeadlines = make(map[string]time.Time)
+	}
+
+	aap.validPeers[remotePeerPublicKeyHex] = true
+	delete(aap.graceDeadlines, remotePeerPublicKeyHex)
+}
+
+// isInGracePeriod returns true if the peer that failed validation passed
+// the previous one and its grace period has not elapsed yet. The grace period
+// starts with the first failed validation. Once it elapses, the peer is
+// treated as never validated.
+func (aap *anyApplicationPolicy) isInGracePeriod(
+	remotePeerPublicKeyHex string,
+) bool {
+	aap.graceMutex.Lock()
+	defer aap.graceMutex.Unlock()
+
+	if aap.gracePeriod <= 0 || !aap.validPeers[remotePeerPublicKeyHex] {
+		return false
+	}
+
+	now := time.Now()
+
+	deadline, ok := aap.graceDeadlines[remotePeerPublicKeyHex]
+	if !ok {
+		deadline = now.Add(aap.gracePeriod)
+		aap.graceDeadlines[remotePeerPublicKeyHex] = deadline
+	}
+
+	if now.Before(deadline) {
+		return true
+	}
+
+	delete(aap.validPeers, remotePeerPublicKeyHex)
+	delete(aap.graceDeadlines, remotePeerPublicKeyHex)
+
+	return false
+}
@@ -1,6 +1,7 @@
 package firewall
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -322,6 +323,233 @@ func TestValidate_PeerIsAllowlistedNode(t *testing.T) {
 	}
 }
 
+func TestValidate_PeerRecognized_RequirementNotSatisfied(t *testing.T) {
+	_, peerOperatorPublicKey, err := operator.GenerateKeyPair(
+		local_v1.DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := newMockApplication()
+	application.setIsRecognized(peerOperatorPublicKey, result{
+		isRecognized: true,
+		err:          nil,
+	})
+
+	requirement := newMockRequirement()
+
+	policy := &anyApplicationPolicy{
+		applications:        []Application{application},
+		requirements:        []Requirement{requirement},
+		allowList:           EmptyAllowList,
+		positiveResultCache: cache.NewTimeCache(cachingPeriod),
+		negativeResultCache: cache.NewTimeCache(cachingPeriod),
+	}
+
+	err = policy.Validate(peerOperatorPublicKey)
+	testutils.AssertErrorsSame(t, errRequirementNotSatisfied, err)
+
+	// The negative result is cached so the requirement is not checked again
+	// before the caching period elapses.
+	requirement.setIsSatisfied(peerOperatorPublicKey, result{
+		isRecognized: true,
+		err:          nil,
+	})
+
+	err = policy.Validate(peerOperatorPublicKey)
+	testutils.AssertErrorsSame(t, errNotRecognized, err)
+
+	time.Sleep(cachingPeriod)
+
+	err = policy.Validate(peerOperatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_PeerRecognized_RequirementError(t *testing.T) {
+	_, peerOperatorPublicKey, err := operator.GenerateKeyPair(
+		local_v1.DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := newMockApplication()
+	application.setIsRecognized(peerOperatorPublicKey, result{
+		isRecognized: true,
+		err:          nil,
+	})
+
+	requirementError := fmt.Errorf("could not check stake")
+	requirement := newMockRequirement()
+	requirement.setIsSatisfied(peerOperatorPublicKey, result{
+		isRecognized: false,
+		err:          requirementError,
+	})
+
+	policy := &anyApplicationPolicy{
+		applications:        []Application{application},
+		requirements:        []Requirement{requirement},
+		allowList:           EmptyAllowList,
+		positiveResultCache: cache.NewTimeCache(cachingPeriod),
+		negativeResultCache: cache.NewTimeCache(cachingPeriod),
+	}
+
+	err = policy.Validate(peerOperatorPublicKey)
+	testutils.AssertErrorsSame(t, requirementError, errors.Unwrap(err))
+
+	// Errors are not cached so the requirement is checked again on the next
+	// validation.
+	requirement.setIsSatisfied(peerOperatorPublicKey, result{
+		isRecognized: true,
+		err:          nil,
+	})
+
+	err = policy.Validate(peerOperatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_GracePeriod(t *testing.T) {
+	_, peerOperatorPublicKey, err := operator.GenerateKeyPair(
+		local_v1.DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := newMockApplication()
+	application.setIsRecognized(peerOperatorPublicKey, result{
+		isRecognized: true,
+		err:          nil,
+	})
+
+	shortCachingPeriod := 100 * time.Millisecond
+	gracePeriod := 500 * time.Millisecond
+
+	policy := &anyApplicationPolicy{
+		applications:        []Application{application},
+		allowList:           EmptyAllowList,
+		positiveResultCache: cache.NewTimeCache(shortCachingPeriod),
+		negativeResultCache: cache.NewTimeCache(shortCachingPeriod),
+		gracePeriod:         gracePeriod,
+	}
+
+	err = policy.Validate(peerOperatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The peer stops being recognized but it is still accepted in the grace
+	// period starting with the first failed validation.
+	application.setIsRecognized(peerOperatorPublicKey, result{
+		isRecognized: false,
+		err:          nil,
+	})
+
+	time.Sleep(shortCachingPeriod)
+
+	err = policy.Validate(peerOperatorPublicKey)
+	if err != nil {
+		t.Fatalf("expected peer to be accepted in the grace period: [%v]", err)
+	}
+
+	time.Sleep(gracePeriod)
+
+	err = policy.Validate(peerOperatorPublicKey)
+	testutils.AssertErrorsSame(t, errNotRecognized, err)
+}
+
+func TestValidate_GracePeriod_NeverValidated(t *testing.T) {
+	_, peerOperatorPublicKey, err := operator.GenerateKeyPair(
+		local_v1.DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &anyApplicationPolicy{
+		applications:        []Application{newMockApplication()},
+		allowList:           EmptyAllowList,
+		positiveResultCache: cache.NewTimeCache(cachingPeriod),
+		negativeResultCache: cache.NewTimeCache(cachingPeriod),
+		gracePeriod:         time.Hour,
+	}
+
+	err = policy.Validate(peerOperatorPublicKey)
+	testutils.AssertErrorsSame(t, errNotRecognized, err)
+}
+
+func TestMinimumAuthorization(t *testing.T) {
+	_, peerOperatorPublicKey, err := operator.GenerateKeyPair(
+		local_v1.DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorizationError := fmt.Errorf("could not get eligible stake")
+
+	var tests = map[string]struct {
+		results             []result
+		expectedIsSatisfied bool
+		expectedError       error
+	}{
+		"no sources": {
+			results:             []result{},
+			expectedIsSatisfied: false,
+		},
+		"no source authorizes": {
+			results: []result{
+				{isRecognized: false},
+				{isRecognized: false},
+			},
+			expectedIsSatisfied: false,
+		},
+		"second source authorizes": {
+			results: []result{
+				{isRecognized: false},
+				{isRecognized: true},
+			},
+			expectedIsSatisfied: true,
+		},
+		"source error": {
+			results: []result{
+				{err: authorizationError},
+				{isRecognized: true},
+			},
+			expectedIsSatisfied: false,
+			expectedError:       authorizationError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			sources := make([]AuthorizationSource, len(test.results))
+			for i, result := range test.results {
+				source := newMockRequirement()
+				source.setIsSatisfied(peerOperatorPublicKey, result)
+				sources[i] = source
+			}
+
+			isSatisfied, err := MinimumAuthorization(sources...).IsSatisfied(
+				peerOperatorPublicKey,
+			)
+
+			testutils.AssertErrorsSame(t, test.expectedError, errors.Unwrap(err))
+			testutils.AssertBoolsEqual(
+				t,
+				"is satisfied",
+				test.expectedIsSatisfied,
+				isSatisfied,
+			)
+		})
+	}
+}
+
 func newMockApplication() *mockApplication {
 	return &mockApplication{
 		results: make(map[*operator.PublicKey]result),
@@ -351,3 +579,35 @@ func (ma *mockApplication) IsRecognized(operatorPublicKey *operator.PublicKey) (
 	result := ma.results[operatorPublicKey]
 	return result.isRecognized, result.err
 }
+
+func newMockRequirement() *mockRequirement {
+	return &mockRequirement{
+		results: make(map[*operator.PublicKey]result),
+	}
+}
+
+// mockRequirement is a mock of both Requirement and AuthorizationSource.
+type mockRequirement struct {
+	results map[*operator.PublicKey]result
+}
+
+func (mr *mockRequirement) setIsSatisfied(
+	operatorPublicKey *operator.PublicKey,
+	result result,
+) {
+	mr.results[operatorPublicKey] = result
+}
+
+func (mr *mockRequirement) IsSatisfied(operatorPublicKey *operator.PublicKey) (
+	bool,
+	error,
+) {
+	result := mr.results[operatorPublicKey]
+	return result.isRecognized, result.err
+}
+
+func (mr *mockRequirement) HasMinimumAuthorization(
+	operatorPublicKey *operator.PublicKey,
+) (bool, error) {
+	return mr.IsSatisfied(operatorPublicKey)
+}
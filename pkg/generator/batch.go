@@ -0,0 +1,215 @@
+package generator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+)
+
+// BatchPersistence is implemented by persistence layers able to save multiple
+// parameters at once, e.g. syncing the storage only once. Parameters buffered
+// by the pool are saved with SaveBatch if the persistence implements it and
+// one by one with Save otherwise.
+type BatchPersistence[T any] interface {
+	// SaveBatch saves the given parameters and returns them persisted, in the
+	// same order. None of the parameters is considered persisted if an error
+	// is returned.
+	SaveBatch([]*T) ([]*Persisted[T], error)
+}
+
+// persistenceBatch buffers parameters generated by the pool and saves them
+// once the batch is full or the flush interval passes since the first
+// buffered parameter.
+type persistenceBatch[T any] struct {
+	persistence Persistence[T]
+	logger      log.StandardLogger
+	metrics     *poolMetrics
+
+	maxSize       int
+	flushInterval time.Duration
+
+	mutex sync.Mutex
+	// pending holds buffered parameters, without identifiers until they are
+	// saved.
+	pending []*Persisted[T]
+	timer   *time.Timer
+}
+
+func newPersistenceBatch[T any](
+	persistence Persistence[T],
+	logger log.StandardLogger,
+	metrics *poolMetrics,
+	maxSize int,
+	flushInterval time.Duration,
+) *persistenceBatch[T] {
+	return &persistenceBatch[T]{
+		persistence:   persistence,
+		logger:        logger,
+		metrics:       metrics,
+		maxSize:       maxSize,
+		flushInterval: flushInterval,
+		pending:       make([]*Persisted[T], 0, maxSize),
+	}
+}
+
+// add buffers the parameter and returns the entry the pool should hold.
+// The identifier of the entry is set once the parameter is saved.
+func (pb *persistenceBatch[T]) add(parameter *T) *Persisted[T] {
+	pb.mutex.Lock()
+	defer pb.mutex.Unlock()
+
+	entry := &Persisted[T]{Data: *parameter}
+	pb.pending = append(pb.pending, entry)
+
+	if len(pb.pending) >= pb.maxSize {
+		if err := pb.flushLocked(); err != nil {
+			pb.logger.Errorf("failed to persist parameters batch: [%v]", err)
+		}
+	} else if pb.timer == nil {
+		pb.startTimerLocked()
+	}
+
+	return entry
+}
+
+// startTimerLocked schedules the flush of the pending parameters after the
+// flush interval. Must be called with the mutex held.
+func (pb *persistenceBatch[T]) startTimerLocked() {
+	pb.timer = time.AfterFunc(pb.flushInterval, func() {
+		if err := pb.flush(); err != nil {
+			pb.logger.Errorf("failed to persist parameters batch: [%v]", err)
+		}
+	})
+}
+
+// flush saves all pending parameters.
+func (pb *persistenceBatch[T]) flush() error {
+	pb.mutex.Lock()
+	defer pb.mutex.Unlock()
+
+	return pb.flushLocked()
+}
+
+// flushLocked saves all pending parameters. Parameters that could not be
+// saved stay pending and the flush is retried after the flush interval.
+// Must be called with the mutex held.
+func (pb *persistenceBatch[T]) flushLocked() error {
+	if pb.timer != nil {
+		pb.timer.Stop()
+		pb.timer = nil
+	}
+
+	if len(pb.pending) == 0 {
+		return nil
+	}
+
+	var err error
+	if batchPersistence, ok := pb.persistence.(BatchPersistence[T]); ok {
+		err = pb.saveBatchLocked(batchPersistence)
+	} else {
+		err = pb.saveOneByOneLocked()
+	}
+
+	if len(pb.pending) > 0 {
+		pb.startTimerLocked()
+	}
+
+	return err
+}
+
+func (pb *persistenceBatch[T]) saveBatchLocked(
+	batchPersistence BatchPersistence[T],
+) error {
+	parameters := make([]*T, len(pb.pending))
+	for i, entry := range pb.pending {
+		parameters[i] = &entry.Data
+	}
+
+	persisted, err := batchPersistence.SaveBatch(parameters)
+	if err != nil {
+		pb.metrics.recordPersistenceError()
+		return fmt.Errorf(
+			"could not save batch of [%d] parameters: [%w]",
+			len(parameters),
+			err,
+		)
+	}
+
+	if len(persisted) != len(pb.pending) {
+		pb.metrics.recordPersistenceError()
+		return fmt.Errorf(
+			"saved [%d] parameters out of [%d]",
+			len(persisted),
+			len(pb.pending),
+		)
+	}
+
+	for i, entry := range pb.pending {
+		entry.ID = persisted[i].ID
+	}
+	pb.pending = pb.pending[:0]
+
+	return nil
+}
+
+func (pb *persistenceBatch[T]) saveOneByOneLocked() error {
+	var lastErr error
+	failed := make([]*Persisted[T], 0)
+
+	for _, entry := range pb.pending {
+		persisted, err := pb.persistence.Save(&entry.Data)
+		if err != nil {
+			pb.metrics.recordPersistenceError()
+			lastErr = err
+			failed = append(failed, entry)
+			continue
+		}
+
+		entry.ID = persisted.ID
+	}
+
+	pb.pending = failed
+
+	if lastErr != nil {
+		return fmt.Errorf(
+			"could not save [%d] parameters: [%w]",
+			len(failed),
+			lastErr,
+		)
+	}
+
+	return nil
+}
+
+// persist ensures the entry about to be dispensed is saved. If the entry
+// is pending, all pending parameters are saved. If the entry still could not
+// be saved, it is dropped from the batch and an error is returned.
+func (pb *persistenceBatch[T]) persist(entry *Persisted[T]) error {
+	pb.mutex.Lock()
+	defer pb.mutex.Unlock()
+
+	if pb.indexLocked(entry) < 0 {
+		return nil
+	}
+
+	err := pb.flushLocked()
+
+	if index := pb.indexLocked(entry); index >= 0 {
+		pb.pending = append(pb.pending[:index], pb.pending[index+1:]...)
+		return err
+	}
+
+	return nil
+}
+
+func (pb *persistenceBatch[T]) indexLocked(entry *Persisted[T]) int {
+	for i, pending := range pb.pending {
+		if pending == entry {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestPersistenceBatch_FullBatch(t *testing.T) {
+	persistence := newMockBatchPersistence()
+	batch := newPersistenceBatch[big.Int](
+		persistence,
+		logger,
+		newPoolMetrics(),
+		3,
+		time.Hour,
+	)
+
+	entries := make([]*Persisted[big.Int], 0)
+	for i := 0; i < 3; i++ {
+		entries = append(entries, batch.add(big.NewInt(int64(i))))
+
+		if i < 2 && persistence.parameterCount() != 0 {
+			t.Fatalf("parameters saved before the batch is full")
+		}
+	}
+
+	testutils.AssertIntsEqual(t, "saved parameters", 3, persistence.parameterCount())
+	testutils.AssertIntsEqual(t, "saved batches", 1, persistence.batchesCount())
+
+	for i, entry := range entries {
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("identifier of entry [%v]", i),
+			calcID(big.NewInt(int64(i))),
+			entry.ID,
+		)
+	}
+}
+
+func TestPersistenceBatch_FlushInterval(t *testing.T) {
+	persistence := newMockBatchPersistence()
+	batch := newPersistenceBatch[big.Int](
+		persistence,
+		logger,
+		newPoolMetrics(),
+		10,
+		20*time.Millisecond,
+	)
+
+	batch.add(big.NewInt(1))
+	batch.add(big.NewInt(2))
+
+	deadline := time.Now().Add(time.Second)
+	for persistence.parameterCount() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("batch not flushed after the flush interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	testutils.AssertIntsEqual(t, "saved batches", 1, persistence.batchesCount())
+}
+
+func TestPersistenceBatch_Persist(t *testing.T) {
+	persistence := newMockBatchPersistence()
+	metrics := newPoolMetrics()
+	batch := newPersistenceBatch[big.Int](
+		persistence,
+		logger,
+		metrics,
+		10,
+		time.Hour,
+	)
+
+	entry := batch.add(big.NewInt(1))
+	batch.add(big.NewInt(2))
+
+	if err := batch.persist(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// All pending parameters are saved together with the dispensed one.
+	testutils.AssertIntsEqual(t, "saved parameters", 2, persistence.parameterCount())
+	testutils.AssertStringsEqual(
+		t,
+		"entry identifier",
+		calcID(big.NewInt(1)),
+		entry.ID,
+	)
+
+	persistence.failure = fmt.Errorf("disk full")
+
+	failed := batch.add(big.NewInt(3))
+	err := batch.persist(failed)
+
+	expectedError := "could not save batch of [1] parameters: [disk full]"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+
+	// The parameter that could not be saved is not saved later.
+	testutils.AssertIntsEqual(t, "pending parameters", 0, len(batch.pending))
+	testutils.AssertUintsEqual(
+		t,
+		"persistence errors count",
+		1,
+		metrics.snapshot(time.Now()).PersistenceErrorsCount,
+	)
+}
+
+func TestParameterPool_BatchedPersistence(t *testing.T) {
+	persistence := newMockBatchPersistence()
+	scheduler := &Scheduler{}
+
+	pool := NewParameterPool[big.Int](
+		logger,
+		scheduler,
+		persistence,
+		4,
+		func(ctx context.Context) (*big.Int, error) {
+			return big.NewInt(time.Now().UnixNano()), nil
+		},
+		0,
+		WithBatchedPersistence(3, time.Hour),
+	)
+
+	deadline := time.Now().Add(time.Second)
+	for pool.ParametersCount() != 4 {
+		if time.Now().After(deadline) {
+			t.Fatal("pool not filled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// One full batch is saved and one parameter is buffered.
+	testutils.AssertIntsEqual(t, "saved parameters", 3, persistence.parameterCount())
+
+	scheduler.stop()
+
+	// The buffered parameter is saved once the generation stops.
+	deadline = time.Now().Add(time.Second)
+	for persistence.parameterCount() != 4 {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered parameter not saved after the generation stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := pool.GetNow(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"saved parameters after dispensing all",
+		0,
+		persistence.parameterCount(),
+	)
+}
+
+// mockBatchPersistence is a persistence saving parameters in batches.
+type mockBatchPersistence struct {
+	*mockPersistence
+
+	batchesMutex sync.Mutex
+	batches      int
+	failure      error
+}
+
+func newMockBatchPersistence() *mockBatchPersistence {
+	return &mockBatchPersistence{
+		mockPersistence: &mockPersistence{storage: make(map[string]*big.Int)},
+	}
+}
+
+func (mbp *mockBatchPersistence) SaveBatch(
+	elements []*big.Int,
+) ([]*Persisted[big.Int], error) {
+	mbp.batchesMutex.Lock()
+	defer mbp.batchesMutex.Unlock()
+
+	if mbp.failure != nil {
+		return nil, mbp.failure
+	}
+
+	mbp.batches++
+
+	persisted := make([]*Persisted[big.Int], 0, len(elements))
+	for _, element := range elements {
+		saved, err := mbp.Save(element)
+		if err != nil {
+			return nil, err
+		}
+
+		persisted = append(persisted, saved)
+	}
+
+	return persisted, nil
+}
+
+func (mbp *mockBatchPersistence) batchesCount() int {
+	mbp.batchesMutex.Lock()
+	defer mbp.batchesMutex.Unlock()
+
+	return mbp.batches
+}
@@ -0,0 +1,184 @@
+package generator
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultGenerationSlots is the default number of generations the scheduler
+// runs at the same time, across all registered pools.
+const DefaultGenerationSlots = 1
+
+// share is the part of the scheduler generation budget assigned to a single
+// parameter pool.
+type share struct {
+	name   string
+	weight float64
+
+	// generationTime is the generation time consumed by the pool, adjusted
+	// when the pool becomes active after being idle so that the idle period
+	// does not give it priority over other pools.
+	generationTime time.Duration
+	waiting        int
+	running        int
+}
+
+// normalizedTime is the consumed generation time relative to the weight of
+// the share. The share with the lowest normalized time is the most behind
+// its weighted share of the budget.
+func (s *share) normalizedTime() float64 {
+	return float64(s.generationTime) / s.weight
+}
+
+func (s *share) isActive() bool {
+	return s.waiting > 0 || s.running > 0
+}
+
+// ShareStats describes the part of the scheduler generation budget assigned
+// to a parameter pool.
+type ShareStats struct {
+	// Name is the name of the pool.
+	Name string
+	// Weight is the weight of the pool's share of the generation time.
+	Weight float64
+	// GenerationTime is the generation time accounted to the pool.
+	GenerationTime time.Duration
+}
+
+// registerShare adds a share of the generation budget for the pool with the
+// given name and weight. The new share starts at the same normalized time as
+// the most behind of the active shares so that it does not take over the
+// budget until it catches up with pools registered earlier.
+func (s *Scheduler) registerShare(name string, weight float64) *share {
+	s.budgetMutex.Lock()
+	defer s.budgetMutex.Unlock()
+
+	newShare := &share{name: name, weight: weight}
+	s.catchUp(newShare)
+
+	s.shares = append(s.shares, newShare)
+
+	return newShare
+}
+
+// catchUp moves the generation time of the share becoming active forward to
+// the lowest normalized time of other active shares, if it is behind.
+// Must be called with the budget mutex held.
+func (s *Scheduler) catchUp(target *share) {
+	lowest := -1.0
+	for _, other := range s.shares {
+		if other == target || !other.isActive() {
+			continue
+		}
+
+		if normalized := other.normalizedTime(); lowest < 0 || normalized < lowest {
+			lowest = normalized
+		}
+	}
+
+	if lowest > target.normalizedTime() {
+		target.generationTime = time.Duration(lowest * target.weight)
+	}
+}
+
+// nextShare returns the waiting share that is the most behind its weighted
+// share of the budget. Shares registered earlier win ties. Must be called
+// with the budget mutex held.
+func (s *Scheduler) nextShare() *share {
+	var next *share
+	for _, candidate := range s.shares {
+		if candidate.waiting == 0 {
+			continue
+		}
+
+		if next == nil || candidate.normalizedTime() < next.normalizedTime() {
+			next = candidate
+		}
+	}
+
+	return next
+}
+
+// notifyBudgetChanged wakes up all goroutines waiting for a generation slot.
+// Must be called with the budget mutex held.
+func (s *Scheduler) notifyBudgetChanged() {
+	if s.budgetChanged != nil {
+		close(s.budgetChanged)
+	}
+	s.budgetChanged = make(chan struct{})
+}
+
+// acquire blocks until a generation slot is free and the given share is the
+// next one to use it. Returns false if the context is done before. A slot
+// acquired successfully must be released with the release function.
+func (s *Scheduler) acquire(ctx context.Context, target *share) bool {
+	s.budgetMutex.Lock()
+
+	if !target.isActive() {
+		s.catchUp(target)
+	}
+	target.waiting++
+
+	for {
+		slots := s.generationSlots
+		if slots <= 0 {
+			slots = DefaultGenerationSlots
+		}
+
+		if s.runningGenerations < slots && s.nextShare() == target {
+			target.waiting--
+			target.running++
+			s.runningGenerations++
+			s.budgetMutex.Unlock()
+			return true
+		}
+
+		if s.budgetChanged == nil {
+			s.notifyBudgetChanged()
+		}
+		changed := s.budgetChanged
+		s.budgetMutex.Unlock()
+
+		select {
+		case <-changed:
+			s.budgetMutex.Lock()
+		case <-ctx.Done():
+			s.budgetMutex.Lock()
+			target.waiting--
+			// Other shares may be next now.
+			s.notifyBudgetChanged()
+			s.budgetMutex.Unlock()
+			return false
+		}
+	}
+}
+
+// release frees the generation slot acquired by the share and accounts the
+// given generation time to it.
+func (s *Scheduler) release(target *share, generationTime time.Duration) {
+	s.budgetMutex.Lock()
+	defer s.budgetMutex.Unlock()
+
+	target.running--
+	target.generationTime += generationTime
+	s.runningGenerations--
+	s.notifyBudgetChanged()
+}
+
+// Shares returns the shares of the generation budget of all parameter pools
+// registered in the scheduler, in the order of registration.
+func (s *Scheduler) Shares() []*ShareStats {
+	s.budgetMutex.Lock()
+	defer s.budgetMutex.Unlock()
+
+	stats := make([]*ShareStats, len(s.shares))
+	for i, share := range s.shares {
+		stats[i] = &ShareStats{
+			Name:           share.name,
+			Weight:         share.weight,
+			GenerationTime: share.generationTime,
+		}
+	}
+
+	return stats
+}
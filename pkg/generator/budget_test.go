@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+// TestNextShare_WeightedTurns ensures shares of pools that all have
+// parameters to generate take turns in proportion to their weights.
+func TestNextShare_WeightedTurns(t *testing.T) {
+	scheduler := &Scheduler{}
+
+	heavy := scheduler.registerShare("heavy", 3)
+	light := scheduler.registerShare("light", 1)
+
+	heavy.waiting = 1
+	light.waiting = 1
+
+	turns := make(map[string]int)
+	for i := 0; i < 40; i++ {
+		next := scheduler.nextShare()
+		turns[next.name]++
+		next.generationTime += time.Second
+	}
+
+	testutils.AssertIntsEqual(t, "heavy pool turns", 30, turns["heavy"])
+	testutils.AssertIntsEqual(t, "light pool turns", 10, turns["light"])
+}
+
+// TestAcquire_IdleShareCatchesUp ensures a pool that was idle, e.g. because
+// it was full, does not take over the budget once it becomes active again.
+func TestAcquire_IdleShareCatchesUp(t *testing.T) {
+	scheduler := &Scheduler{}
+
+	busy := scheduler.registerShare("busy", 1)
+	idle := scheduler.registerShare("idle", 2)
+
+	// The busy pool is generating at the moment so it is active.
+	busy.running = 1
+	busy.generationTime = 10 * time.Minute
+
+	if !scheduler.acquire(context.Background(), idle) {
+		t.Fatal("generation slot not acquired")
+	}
+	scheduler.release(idle, time.Minute)
+
+	testutils.AssertIntsEqual(
+		t,
+		"idle pool generation time in minutes",
+		21,
+		int(idle.generationTime/time.Minute),
+	)
+
+	// A share registered late starts on par with the active shares.
+	late := scheduler.registerShare("late", 1)
+	testutils.AssertIntsEqual(
+		t,
+		"late pool generation time in minutes",
+		10,
+		int(late.generationTime/time.Minute),
+	)
+}
+
+func TestAcquire_ContextDone(t *testing.T) {
+	scheduler := &Scheduler{}
+
+	first := scheduler.registerShare("first", 1)
+	second := scheduler.registerShare("second", 1)
+
+	if !scheduler.acquire(context.Background(), first) {
+		t.Fatal("generation slot not acquired")
+	}
+
+	ctx, cancelCtx := context.WithTimeout(
+		context.Background(),
+		20*time.Millisecond,
+	)
+	defer cancelCtx()
+
+	if scheduler.acquire(ctx, second) {
+		t.Fatal("generation slot acquired while all slots are taken")
+	}
+
+	testutils.AssertIntsEqual(t, "waiting count", 0, second.waiting)
+}
+
+// TestParameterPools_SharedBudget ensures pools sharing a scheduler never
+// generate more parameters at the same time than the scheduler allows and
+// all of them get filled.
+func TestParameterPools_SharedBudget(t *testing.T) {
+	scheduler := &Scheduler{generationSlots: 1}
+	defer scheduler.stop()
+
+	var running, maxRunning int32
+
+	generateFn := func(ctx context.Context) (*big.Int, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed ||
+				atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		return big.NewInt(time.Now().UnixNano()), nil
+	}
+
+	pools := make([]*ParameterPool[big.Int], 0)
+	for _, weight := range []float64{1, 2} {
+		pools = append(pools, NewParameterPool[big.Int](
+			logger,
+			scheduler,
+			&mockPersistence{storage: make(map[string]*big.Int)},
+			5,
+			generateFn,
+			0,
+			WithShareWeight(weight),
+		))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for pools[0].ParametersCount() < 5 || pools[1].ParametersCount() < 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf(
+				"pools not filled; sizes: [%v] and [%v]",
+				pools[0].ParametersCount(),
+				pools[1].ParametersCount(),
+			)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"maximum number of concurrent generations",
+		1,
+		int(atomic.LoadInt32(&maxRunning)),
+	)
+
+	shares := scheduler.Shares()
+	testutils.AssertIntsEqual(t, "number of shares", 2, len(shares))
+	if shares[1].Weight != 2 {
+		t.Errorf("unexpected share weight: [%v]", shares[1].Weight)
+	}
+}
@@ -10,14 +10,74 @@ var logger = log.Logger("keep-generator")
 
 const checkTick = 1 * time.Second
 
+// SchedulerOption allows to customize the Scheduler.
+type SchedulerOption func(config *schedulerConfig)
+
+type schedulerConfig struct {
+	loadThreshold   float64
+	generationSlots int
+}
+
+// WithLoadThreshold sets the CPU utilization of other processes of the
+// machine, as a fraction of its total CPU capacity, at and above which
+// computations are stopped. Thresholds outside of the (0, 1] range are
+// ignored and the default one is used.
+func WithLoadThreshold(threshold float64) SchedulerOption {
+	return func(config *schedulerConfig) {
+		if threshold <= 0 || threshold > 1 {
+			logger.Warnf(
+				"ignoring load threshold [%v] outside of the (0, 1] range; "+
+					"using the default one [%v]",
+				threshold,
+				DefaultLoadThreshold,
+			)
+			return
+		}
+
+		config.loadThreshold = threshold
+	}
+}
+
+// WithGenerationSlots sets the number of generations the scheduler runs at
+// the same time, across all registered parameter pools. Non-positive values
+// are ignored and the default one is used.
+func WithGenerationSlots(slots int) SchedulerOption {
+	return func(config *schedulerConfig) {
+		if slots <= 0 {
+			logger.Warnf(
+				"ignoring non-positive number of generation slots [%v]; "+
+					"using the default one [%v]",
+				slots,
+				DefaultGenerationSlots,
+			)
+			return
+		}
+
+		config.generationSlots = slots
+	}
+}
+
 // StartScheduler creates a new instance of a Scheduler that is responsible
 // for managing long-running, computationally-expensive operations.
 // The scheduler stops and resumes operations based on the state of registered
 // protocols. If at least one of the protocols is currently executing, the
 // scheduler stops all computations. Computations are automatically resumed once
-// none of the protocols is executing.
-func StartScheduler() *Scheduler {
-	scheduler := &Scheduler{}
+// none of the protocols is executing. The scheduler monitors the system load
+// and stops computations as well when other processes of the machine use
+// the CPU capacity above the load threshold.
+func StartScheduler(options ...SchedulerOption) *Scheduler {
+	config := &schedulerConfig{
+		loadThreshold:   DefaultLoadThreshold,
+		generationSlots: DefaultGenerationSlots,
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	scheduler := &Scheduler{
+		load:            newLoadMonitor(config.loadThreshold),
+		generationSlots: config.generationSlots,
+	}
 
 	go func() {
 		for {
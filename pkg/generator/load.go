@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/process"
+)
+
+// DefaultLoadThreshold is the default external CPU utilization, as a fraction
+// of the machine's total CPU capacity, at and above which computations are
+// paused.
+const DefaultLoadThreshold = 0.9
+
+const (
+	// loadSmoothingFactor is the weight of the latest sample in the
+	// exponential moving average of the external CPU utilization. Smoothing
+	// prevents computations from flapping on short load spikes.
+	loadSmoothingFactor = 0.3
+)
+
+// cpuSample holds cumulative CPU times, in seconds, read at a certain moment.
+type cpuSample struct {
+	// total is the total CPU time of the machine, summed over all CPUs.
+	total float64
+	// busy is the non-idle CPU time of the machine, summed over all CPUs.
+	busy float64
+	// own is the CPU time consumed by the client process.
+	own float64
+}
+
+// sampleCPU reads the current CPU times of the machine and the client
+// process.
+func sampleCPU() (*cpuSample, error) {
+	times, err := cpu.Times(false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CPU times: [%v]", err)
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("no CPU times reported")
+	}
+
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect client process: [%v]", err)
+	}
+
+	selfTimes, err := self.Times()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read client process CPU times: [%v]", err)
+	}
+
+	total := times[0].Total()
+	idle := times[0].Idle + times[0].Iowait
+
+	return &cpuSample{
+		total: total,
+		busy:  total - idle,
+		own:   selfTimes.User + selfTimes.System,
+	}, nil
+}
+
+// loadMonitor tracks the CPU utilization of the machine caused by processes
+// other than the client and by the client's own protocols. The client's
+// own utilization is excluded while no protocol executes so that
+// computations managed by the Scheduler do not throttle themselves. While
+// protocols such as signing or DKG execute, computations are stopped and
+// the client's utilization is caused by the protocols, so it is counted.
+// Computations then resume gradually as the smoothed utilization decays.
+type loadMonitor struct {
+	mutex sync.Mutex
+
+	sampleFn func() (*cpuSample, error)
+	cpuCount int
+	// threshold is the external CPU utilization at and above which the
+	// machine is considered overloaded.
+	threshold float64
+
+	lastSample *cpuSample
+	// utilization is the smoothed external CPU utilization as a fraction of
+	// the machine's total CPU capacity. It is zero until two samples were
+	// taken.
+	utilization float64
+}
+
+func newLoadMonitor(threshold float64) *loadMonitor {
+	return &loadMonitor{
+		sampleFn:  sampleCPU,
+		cpuCount:  runtime.NumCPU(),
+		threshold: threshold,
+	}
+}
+
+// sample takes a new CPU sample and updates the external CPU utilization
+// based on the difference with the previous sample. The protocolsExecuting
+// flag tells whether any of the client's protocols executed since the
+// previous sample; if so, the client's own CPU time is counted as well.
+func (lm *loadMonitor) sample(protocolsExecuting bool) {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	current, err := lm.sampleFn()
+	if err != nil {
+		logger.Debugf("cannot sample CPU utilization: [%v]", err)
+		return
+	}
+
+	previous := lm.lastSample
+	lm.lastSample = current
+
+	if previous == nil {
+		return
+	}
+
+	total := current.total - previous.total
+	if total <= 0 {
+		return
+	}
+
+	external := current.busy - previous.busy
+	if !protocolsExecuting {
+		external -= current.own - previous.own
+	}
+	utilization := external / total
+	if utilization < 0 {
+		utilization = 0
+	}
+	if utilization > 1 {
+		utilization = 1
+	}
+
+	lm.utilization = loadSmoothingFactor*utilization +
+		(1-loadSmoothingFactor)*lm.utilization
+}
+
+// isOverloaded returns true if the external CPU utilization reached the
+// threshold.
+func (lm *loadMonitor) isOverloaded() bool {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	return lm.utilization >= lm.threshold
+}
+
+// concurrency returns the number of CPUs not used by other processes,
+// capped to the given maximum. At least one is always returned.
+func (lm *loadMonitor) concurrency(maxConcurrency int) int {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	idleCPUs := int(float64(lm.cpuCount) * (1 - lm.utilization))
+
+	if idleCPUs > maxConcurrency {
+		idleCPUs = maxConcurrency
+	}
+	if idleCPUs < 1 {
+		idleCPUs = 1
+	}
+
+	return idleCPUs
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+// samplesFn returns a sample function returning the given samples one after
+// another. An error is returned once all samples were returned.
+func samplesFn(samples ...*cpuSample) func() (*cpuSample, error) {
+	index := 0
+	return func() (*cpuSample, error) {
+		if index >= len(samples) {
+			return nil, fmt.Errorf("no more samples")
+		}
+		sample := samples[index]
+		index++
+		return sample, nil
+	}
+}
+
+func TestLoadMonitor(t *testing.T) {
+	tests := map[string]struct {
+		samples             []*cpuSample
+		expectedOverloaded  bool
+		expectedConcurrency int
+	}{
+		"no samples": {
+			expectedOverloaded:  false,
+			expectedConcurrency: 4,
+		},
+		"single sample": {
+			samples: []*cpuSample{
+				{total: 100, busy: 100, own: 0},
+			},
+			expectedOverloaded:  false,
+			expectedConcurrency: 4,
+		},
+		"idle machine": {
+			samples: []*cpuSample{
+				{total: 100, busy: 0, own: 0},
+				{total: 200, busy: 0, own: 0},
+			},
+			expectedOverloaded:  false,
+			expectedConcurrency: 4,
+		},
+		"machine busy with the client only": {
+			samples: []*cpuSample{
+				{total: 100, busy: 0, own: 0},
+				{total: 200, busy: 100, own: 100},
+			},
+			expectedOverloaded:  false,
+			expectedConcurrency: 4,
+		},
+		"machine partially busy with other processes": {
+			samples: []*cpuSample{
+				{total: 100, busy: 0, own: 0},
+				// The smoothed utilization is 0.3 * 1 = 0.3 so 5 out of
+				// 8 CPUs are idle.
+				{total: 200, busy: 100, own: 0},
+			},
+			expectedOverloaded:  false,
+			expectedConcurrency: 4,
+		},
+		"machine busy with other processes": {
+			samples: []*cpuSample{
+				{total: 0, busy: 0, own: 0},
+				{total: 100, busy: 100, own: 0},
+				{total: 200, busy: 200, own: 0},
+				{total: 300, busy: 300, own: 0},
+				{total: 400, busy: 400, own: 0},
+				{total: 500, busy: 500, own: 0},
+				{total: 600, busy: 600, own: 0},
+				{total: 700, busy: 700, own: 0},
+			},
+			expectedOverloaded:  true,
+			expectedConcurrency: 1,
+		},
+		"failed sample": {
+			samples: []*cpuSample{
+				{total: 100, busy: 0, own: 0},
+				nil,
+			},
+			expectedOverloaded:  false,
+			expectedConcurrency: 4,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			samples := make([]*cpuSample, 0)
+			for _, sample := range test.samples {
+				if sample != nil {
+					samples = append(samples, sample)
+				}
+			}
+
+			monitor := &loadMonitor{
+				sampleFn:  samplesFn(samples...),
+				cpuCount:  8,
+				threshold: DefaultLoadThreshold,
+			}
+
+			for range test.samples {
+				monitor.sample(false)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"overloaded",
+				test.expectedOverloaded,
+				monitor.isOverloaded(),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"concurrency",
+				test.expectedConcurrency,
+				monitor.concurrency(4),
+			)
+		})
+	}
+}
+
+func TestLoadMonitor_ConcurrencyBelowMaximum(t *testing.T) {
+	monitor := &loadMonitor{
+		sampleFn: samplesFn(
+			&cpuSample{total: 0, busy: 0, own: 0},
+			&cpuSample{total: 100, busy: 100, own: 0},
+			&cpuSample{total: 200, busy: 200, own: 0},
+		),
+		cpuCount:  8,
+		threshold: DefaultLoadThreshold,
+	}
+
+	monitor.sample(false)
+	monitor.sample(false)
+	monitor.sample(false)
+
+	// The smoothed utilization is 0.3 + 0.7 * 0.3 = 0.51 so 3 out of 8 CPUs
+	// are idle.
+	testutils.AssertIntsEqual(t, "concurrency", 3, monitor.concurrency(8))
+}
+
+func TestLoadMonitor_ProtocolActivity(t *testing.T) {
+	tests := map[string]struct {
+		protocolsExecuting  bool
+		expectedConcurrency int
+	}{
+		"client busy with computations": {
+			protocolsExecuting:  false,
+			expectedConcurrency: 8,
+		},
+		"client busy with protocols": {
+			protocolsExecuting: true,
+			// The smoothed utilization is 0.3 + 0.7 * 0.3 = 0.51 so 3 out
+			// of 8 CPUs are idle.
+			expectedConcurrency: 3,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			monitor := &loadMonitor{
+				sampleFn: samplesFn(
+					&cpuSample{total: 0, busy: 0, own: 0},
+					&cpuSample{total: 100, busy: 100, own: 100},
+					&cpuSample{total: 200, busy: 200, own: 200},
+				),
+				cpuCount:  8,
+				threshold: DefaultLoadThreshold,
+			}
+
+			monitor.sample(test.protocolsExecuting)
+			monitor.sample(test.protocolsExecuting)
+			monitor.sample(test.protocolsExecuting)
+
+			testutils.AssertIntsEqual(
+				t,
+				"concurrency",
+				test.expectedConcurrency,
+				monitor.concurrency(8),
+			)
+		})
+	}
+}
+
+// TestStopWhenOverloaded ensures computations are stopped when the machine
+// is overloaded even if no protocol is executing.
+func TestStopWhenOverloaded(t *testing.T) {
+	scheduler := &Scheduler{
+		load: &loadMonitor{
+			sampleFn: samplesFn(
+				&cpuSample{total: 0, busy: 0, own: 0},
+			),
+			cpuCount:    8,
+			threshold:   DefaultLoadThreshold,
+			utilization: 1,
+		},
+	}
+
+	scheduler.checkProtocols()
+
+	if scheduler.state != stopped {
+		t.Errorf("expected computations to be stopped")
+	}
+}
+
+func TestLoadMonitor_Threshold(t *testing.T) {
+	tests := map[string]struct {
+		threshold          float64
+		expectedOverloaded bool
+	}{
+		"utilization below threshold": {
+			threshold:          DefaultLoadThreshold,
+			expectedOverloaded: false,
+		},
+		"utilization at threshold": {
+			threshold:          0.5,
+			expectedOverloaded: true,
+		},
+		"utilization above threshold": {
+			threshold:          0.3,
+			expectedOverloaded: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			monitor := &loadMonitor{
+				sampleFn:    samplesFn(),
+				cpuCount:    8,
+				threshold:   test.threshold,
+				utilization: 0.5,
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"overloaded",
+				test.expectedOverloaded,
+				monitor.isOverloaded(),
+			)
+		})
+	}
+}
+
+func TestWithLoadThreshold(t *testing.T) {
+	tests := map[string]struct {
+		threshold         float64
+		expectedThreshold float64
+	}{
+		"valid threshold": {
+			threshold:         0.75,
+			expectedThreshold: 0.75,
+		},
+		"zero threshold": {
+			threshold:         0,
+			expectedThreshold: DefaultLoadThreshold,
+		},
+		"threshold above one": {
+			threshold:         1.5,
+			expectedThreshold: DefaultLoadThreshold,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			config := &schedulerConfig{loadThreshold: DefaultLoadThreshold}
+
+			WithLoadThreshold(test.threshold)(config)
+
+			if config.loadThreshold != test.expectedThreshold {
+				t.Errorf(
+					"unexpected load threshold\n"+
+						"expected: [%v]\nactual:   [%v]",
+					test.expectedThreshold,
+					config.loadThreshold,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"sync"
+	"time"
+)
+
+// GenerationDurationBuckets are upper bounds of buckets of the generation
+// duration histogram of parameter pools.
+var GenerationDurationBuckets = []time.Duration{
+	15 * time.Second,
+	30 * time.Second,
+	1 * time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+}
+
+// rateWindow is the period over which the fill and drain rates of a pool are
+// computed.
+const rateWindow = time.Hour
+
+// DurationHistogram is a cumulative histogram of observed durations.
+type DurationHistogram struct {
+	// Bounds are upper bounds of the buckets, in ascending order.
+	Bounds []time.Duration
+	// Counts holds the number of observed durations lower than or equal to
+	// the bound with the same index.
+	Counts []uint64
+	// Count is the number of all observed durations.
+	Count uint64
+	// Sum is the sum of all observed durations.
+	Sum time.Duration
+}
+
+func newDurationHistogram(bounds []time.Duration) *DurationHistogram {
+	return &DurationHistogram{
+		Bounds: bounds,
+		Counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (dh *DurationHistogram) observe(duration time.Duration) {
+	for i, bound := range dh.Bounds {
+		if duration <= bound {
+			dh.Counts[i]++
+		}
+	}
+
+	dh.Count++
+	dh.Sum += duration
+}
+
+func (dh *DurationHistogram) copy() *DurationHistogram {
+	counts := make([]uint64, len(dh.Counts))
+	copy(counts, dh.Counts)
+
+	return &DurationHistogram{
+		Bounds: dh.Bounds,
+		Counts: counts,
+		Count:  dh.Count,
+		Sum:    dh.Sum,
+	}
+}
+
+// PoolMetrics holds metrics of a parameter pool. Counters are cumulative
+// since the pool was created.
+type PoolMetrics struct {
+	// Size is the current number of parameters in the pool.
+	Size int
+	// TargetSize is the number of parameters the pool is filled up to.
+	TargetSize int
+	// GeneratedCount is the number of parameters generated by the pool.
+	GeneratedCount uint64
+	// TakenCount is the number of parameters taken from the pool.
+	TakenCount uint64
+	// PersistenceErrorsCount is the number of failed reads, saves, and
+	// deletions of persisted parameters.
+	PersistenceErrorsCount uint64
+	// FailuresCount is the number of failed generations.
+	FailuresCount uint64
+	// TimeoutsCount is the number of timed out generations. Timeouts are
+	// not failures and the pool does not back off on them.
+	TimeoutsCount uint64
+	// ConsecutiveFailures is the number of generations failed since the
+	// last successful one. The pool backs off while it is not zero.
+	ConsecutiveFailures int
+	// FillRate is the number of parameters generated per hour, over the
+	// last hour.
+	FillRate float64
+	// DrainRate is the number of parameters taken per hour, over the
+	// last hour. The pool is draining if it is greater than the fill rate.
+	DrainRate float64
+	// GenerationDuration is the histogram of durations of successful
+	// generations.
+	GenerationDuration *DurationHistogram
+}
+
+// poolMetrics collects metrics of a parameter pool.
+type poolMetrics struct {
+	mutex sync.Mutex
+
+	createdAt              time.Time
+	generatedCount         uint64
+	takenCount             uint64
+	persistenceErrorsCount uint64
+	failuresCount          uint64
+	timeoutsCount          uint64
+	consecutiveFailures    int
+	// lastError is the error of the last generation, nil if it succeeded.
+	lastError error
+	// generatedAt and takenAt hold times of generations and takes within
+	// the rate window.
+	generatedAt        []time.Time
+	takenAt            []time.Time
+	generationDuration *DurationHistogram
+}
+
+func newPoolMetrics() *poolMetrics {
+	return &poolMetrics{
+		createdAt:          time.Now(),
+		generatedAt:        make([]time.Time, 0),
+		takenAt:            make([]time.Time, 0),
+		generationDuration: newDurationHistogram(GenerationDurationBuckets),
+	}
+}
+
+func (pm *poolMetrics) recordGenerated(duration time.Duration) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.generatedCount++
+	pm.consecutiveFailures = 0
+	pm.lastError = nil
+	pm.generatedAt = append(pm.generatedAt, time.Now())
+	pm.generationDuration.observe(duration)
+}
+
+// recordFailure records the failed generation and returns the number of
+// consecutive failures.
+func (pm *poolMetrics) recordFailure(err error) int {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.failuresCount++
+	pm.consecutiveFailures++
+	pm.lastError = err
+
+	return pm.consecutiveFailures
+}
+
+// recordTimeout records the timed out generation. The number of consecutive
+// failures is not affected.
+func (pm *poolMetrics) recordTimeout(err error) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.timeoutsCount++
+	pm.lastError = err
+}
+
+func (pm *poolMetrics) generationError() error {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	return pm.lastError
+}
+
+func (pm *poolMetrics) recordTaken() {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.takenCount++
+	pm.takenAt = append(pm.takenAt, time.Now())
+}
+
+func (pm *poolMetrics) recordPersistenceError() {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.persistenceErrorsCount++
+}
+
+// snapshot returns the current metrics. The size and target size are left
+// for the pool to fill in.
+func (pm *poolMetrics) snapshot(now time.Time) *PoolMetrics {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	pm.generatedAt = withinWindow(pm.generatedAt, now)
+	pm.takenAt = withinWindow(pm.takenAt, now)
+
+	// Rates of a pool younger than the rate window are computed over the
+	// pool lifetime.
+	period := now.Sub(pm.createdAt)
+	if period > rateWindow {
+		period = rateWindow
+	}
+
+	rate := func(events []time.Time) float64 {
+		if period <= 0 {
+			return 0
+		}
+
+		return float64(len(events)) * float64(time.Hour) / float64(period)
+	}
+
+	return &PoolMetrics{
+		GeneratedCount:         pm.generatedCount,
+		TakenCount:             pm.takenCount,
+		PersistenceErrorsCount: pm.persistenceErrorsCount,
+		FailuresCount:          pm.failuresCount,
+		TimeoutsCount:          pm.timeoutsCount,
+		ConsecutiveFailures:    pm.consecutiveFailures,
+		FillRate:               rate(pm.generatedAt),
+		DrainRate:              rate(pm.takenAt),
+		GenerationDuration:     pm.generationDuration.copy(),
+	}
+}
+
+// withinWindow drops times older than the rate window from the sorted
+// slice.
+func withinWindow(times []time.Time, now time.Time) []time.Time {
+	windowStart := now.Add(-rateWindow)
+
+	for i, t := range times {
+		if t.After(windowStart) {
+			return times[i:]
+		}
+	}
+
+	return times[:0]
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestDurationHistogram(t *testing.T) {
+	histogram := newDurationHistogram([]time.Duration{
+		time.Second,
+		time.Minute,
+	})
+
+	for _, duration := range []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		30 * time.Second,
+		time.Hour,
+	} {
+		histogram.observe(duration)
+	}
+
+	expectedHistogram := &DurationHistogram{
+		Bounds: []time.Duration{time.Second, time.Minute},
+		Counts: []uint64{2, 3},
+		Count:  4,
+		Sum:    time.Hour + 31*time.Second + 500*time.Millisecond,
+	}
+	if !reflect.DeepEqual(expectedHistogram, histogram) {
+		t.Errorf(
+			"unexpected histogram\nexpected: %+v\nactual:   %+v",
+			expectedHistogram,
+			histogram,
+		)
+	}
+}
+
+func TestPoolMetricsRates(t *testing.T) {
+	now := time.Now()
+
+	var tests = map[string]struct {
+		age               time.Duration
+		generatedAgo      []time.Duration
+		takenAgo          []time.Duration
+		expectedFillRate  float64
+		expectedDrainRate float64
+	}{
+		"pool younger than the rate window": {
+			age:               30 * time.Minute,
+			generatedAgo:      []time.Duration{20 * time.Minute, 10 * time.Minute},
+			takenAgo:          []time.Duration{5 * time.Minute},
+			expectedFillRate:  4,
+			expectedDrainRate: 2,
+		},
+		"pool older than the rate window": {
+			age: 5 * time.Hour,
+			generatedAgo: []time.Duration{
+				3 * time.Hour,
+				2 * time.Hour,
+				30 * time.Minute,
+			},
+			takenAgo: []time.Duration{
+				50 * time.Minute,
+				40 * time.Minute,
+				30 * time.Minute,
+			},
+			expectedFillRate:  1,
+			expectedDrainRate: 3,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			metrics := newPoolMetrics()
+			metrics.createdAt = now.Add(-test.age)
+			for _, ago := range test.generatedAgo {
+				metrics.generatedAt = append(metrics.generatedAt, now.Add(-ago))
+			}
+			for _, ago := range test.takenAgo {
+				metrics.takenAt = append(metrics.takenAt, now.Add(-ago))
+			}
+
+			snapshot := metrics.snapshot(now)
+
+			if test.expectedFillRate != snapshot.FillRate {
+				t.Errorf(
+					"unexpected fill rate\nexpected: %v\nactual:   %v",
+					test.expectedFillRate,
+					snapshot.FillRate,
+				)
+			}
+			if test.expectedDrainRate != snapshot.DrainRate {
+				t.Errorf(
+					"unexpected drain rate\nexpected: %v\nactual:   %v",
+					test.expectedDrainRate,
+					snapshot.DrainRate,
+				)
+			}
+		})
+	}
+}
+
+func TestParameterPoolMetrics(t *testing.T) {
+	pool, scheduler := newTestPoolWithSupply(5, 3)
+	defer scheduler.stop()
+
+	if _, err := pool.GetNow(); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := pool.Metrics()
+
+	testutils.AssertIntsEqual(t, "size", 2, metrics.Size)
+	testutils.AssertIntsEqual(t, "target size", 5, metrics.TargetSize)
+	testutils.AssertUintsEqual(t, "generated count", 3, metrics.GeneratedCount)
+	testutils.AssertUintsEqual(t, "taken count", 1, metrics.TakenCount)
+	testutils.AssertUintsEqual(
+		t,
+		"persistence errors count",
+		0,
+		metrics.PersistenceErrorsCount,
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"generation duration count",
+		3,
+		metrics.GenerationDuration.Count,
+	)
+
+	if metrics.FillRate <= metrics.DrainRate {
+		t.Errorf(
+			"fill rate [%v] should be greater than drain rate [%v]",
+			metrics.FillRate,
+			metrics.DrainRate,
+		)
+	}
+}
@@ -2,13 +2,16 @@ package generator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ipfs/go-log/v2"
 )
 
-// ErrEmptyPool is returned by GetNow when the pool is empty.
+// ErrEmptyPool is returned by GetNow when the pool is empty and wrapped by
+// Get when the pool stays empty until the context is done.
 var ErrEmptyPool = fmt.Errorf("pool is empty")
 
 // Persistence defines the expected interface for storing and loading generated
@@ -29,118 +32,647 @@ type Persisted[S any] struct {
 	ID   string
 }
 
+// Priority determines which parameters of the pool a consumer can take.
+type Priority int
+
+const (
+	// HighPriority consumers, e.g. an active distributed key generation, can
+	// take any parameter from the pool, including the reserved ones.
+	HighPriority Priority = iota
+	// BackgroundPriority consumers can take only parameters above the
+	// reserved part of the pool.
+	BackgroundPriority
+)
+
+// Reservation describes the part of the pool reserved for high-priority
+// consumers.
+type Reservation struct {
+	// Size is the number of parameters only high-priority consumers can take.
+	Size int
+	// MaxBackgroundWait is the time after which a background consumer waiting
+	// for a parameter can take a reserved one. It prevents starving background
+	// consumers when the pool does not grow above the reserved size, e.g.
+	// because high-priority consumers take all new parameters. Background
+	// consumers never take reserved parameters if zero.
+	MaxBackgroundWait time.Duration
+}
+
+func (r Reservation) validate() error {
+	if r.Size < 0 {
+		return fmt.Errorf("reserved size [%v] must not be negative", r.Size)
+	}
+
+	if r.MaxBackgroundWait < 0 {
+		return fmt.Errorf(
+			"maximum background wait [%v] must not be negative",
+			r.MaxBackgroundWait,
+		)
+	}
+
+	return nil
+}
+
+// DefaultShareWeight is the default weight of the share of the scheduler
+// generation time assigned to a parameter pool.
+const DefaultShareWeight = 1.0
+
+const (
+	// DefaultInitialGenerationBackoff is the default time the pool waits
+	// after a failed generation before generating again.
+	DefaultInitialGenerationBackoff = 10 * time.Second
+	// DefaultMaxGenerationBackoff is the default maximum time the pool waits
+	// after repeated failed generations before generating again.
+	DefaultMaxGenerationBackoff = 10 * time.Minute
+)
+
+// PoolOption allows to customize the ParameterPool.
+type PoolOption func(config *poolConfig)
+
+type poolConfig struct {
+	name           string
+	shareWeight    float64
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	batchSize      int
+	flushInterval  time.Duration
+}
+
+// WithPoolName sets the name of the pool used in logs and scheduler shares.
+func WithPoolName(name string) PoolOption {
+	return func(config *poolConfig) {
+		config.name = name
+	}
+}
+
+// WithShareWeight sets the weight of the share of the scheduler generation
+// time assigned to the pool. Pools registered in the same scheduler get
+// the generation time proportionally to their weights when they all have
+// parameters to generate. Non-positive weights are ignored and the default
+// one is used.
+func WithShareWeight(weight float64) PoolOption {
+	return func(config *poolConfig) {
+		if weight <= 0 {
+			logger.Warnf(
+				"ignoring non-positive share weight [%v] of pool [%s]; "+
+					"using the default one [%v]",
+				weight,
+				config.name,
+				DefaultShareWeight,
+			)
+			return
+		}
+
+		config.shareWeight = weight
+	}
+}
+
+// WithGenerationBackoff sets the time the pool waits after a failed
+// generation before generating again. The time doubles with every
+// consecutive failure, up to the maximum, and is reset once a generation
+// succeeds. Non-positive values, or a maximum lower than the initial value,
+// are ignored and the defaults are used.
+func WithGenerationBackoff(initial, max time.Duration) PoolOption {
+	return func(config *poolConfig) {
+		if initial <= 0 || max < initial {
+			logger.Warnf(
+				"ignoring invalid generation backoff [%v, %v] of pool [%s]; "+
+					"using the default one [%v, %v]",
+				initial,
+				max,
+				config.name,
+				DefaultInitialGenerationBackoff,
+				DefaultMaxGenerationBackoff,
+			)
+			return
+		}
+
+		config.initialBackoff = initial
+		config.maxBackoff = max
+	}
+}
+
+// WithBatchedPersistence makes the pool save generated parameters in batches
+// of the given size instead of one by one, to reduce the pressure on the
+// storage when parameters are generated fast. Buffered parameters are saved
+// once the batch is full, the flush interval passes since the first buffered
+// parameter, the scheduler stops the generation, or the pool is flushed.
+// A buffered parameter is always saved before it is dispensed. Batch sizes
+// lower than two or non-positive flush intervals are ignored and parameters
+// are saved one by one.
+func WithBatchedPersistence(
+	batchSize int,
+	flushInterval time.Duration,
+) PoolOption {
+	return func(config *poolConfig) {
+		if batchSize < 2 || flushInterval <= 0 {
+			logger.Warnf(
+				"ignoring invalid persistence batch size [%v] or flush "+
+					"interval [%v] of pool [%s]; saving parameters one by one",
+				batchSize,
+				flushInterval,
+				config.name,
+			)
+			return
+		}
+
+		config.batchSize = batchSize
+		config.flushInterval = flushInterval
+	}
+}
+
+// generationBackoff returns the time to wait after the given number of
+// consecutive failed generations.
+func (pc *poolConfig) generationBackoff(failures int) time.Duration {
+	backoff := pc.initialBackoff
+	for i := 1; i < failures && backoff < pc.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > pc.maxBackoff {
+		return pc.maxBackoff
+	}
+
+	return backoff
+}
+
 // ParameterPool autogenerates parameters based on the provided generation
-// function up to the pool size. Parameters are stored in the cache and
+// function up to the pool target size. Parameters are stored in the cache and
 // persisted using the provided persistence layer to survive client restarts.
 // When a parameter is pulled from the pool, the pool starts generating a new
 // parameter automatically. The pool submits the work to the provided scheduler
-// instance and can be controlled by the scheduler.
+// instance and can be controlled by the scheduler. The target size can be
+// changed at runtime. Part of the pool can be reserved for high-priority
+// consumers. Multiple pools can share one scheduler; generations of all
+// pools are then limited by the scheduler generation slots.
 type ParameterPool[T any] struct {
 	persistence Persistence[T]
-	pool        chan *Persisted[T]
+
+	mutex       sync.Mutex
+	parameters  []*Persisted[T]
+	targetSize  int
+	reservation Reservation
+	// changed is closed and replaced every time parameters or the target size
+	// change so that goroutines waiting for the change are woken up.
+	changed chan struct{}
+
+	metrics *poolMetrics
+	// batch buffers generated parameters before they are saved. Nil if
+	// parameters are saved one by one.
+	batch *persistenceBatch[T]
 }
 
 // NewParameterPool creates a new instance of ParameterPool.
-// The generateFn may return nil when the context passed to it has been
-// cancelled or timed out during computations.
+// The generateFn returns an error when the generation fails. The pool then
+// waits before generating again, exponentially longer with every consecutive
+// failure. Errors returned when the context passed to the generateFn has
+// been cancelled or timed out during computations are not failures.
+// Errors wrapping context.DeadlineExceeded, returned when the generateFn
+// gave up on a generation taking too long, are timeouts. Timeouts are not
+// failures either: each one already took the generation time so the pool
+// generates again right away.
 func NewParameterPool[T any](
 	logger log.StandardLogger,
 	scheduler *Scheduler,
 	persistence Persistence[T],
-	poolSize int,
-	generateFn func(context.Context) *T,
+	targetSize int,
+	generateFn func(context.Context) (*T, error),
 	generateDelay time.Duration,
+	options ...PoolOption,
 ) *ParameterPool[T] {
-	pool := make(chan *Persisted[T], poolSize)
+	config := &poolConfig{
+		name:           "unnamed",
+		shareWeight:    DefaultShareWeight,
+		initialBackoff: DefaultInitialGenerationBackoff,
+		maxBackoff:     DefaultMaxGenerationBackoff,
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	pool := &ParameterPool[T]{
+		persistence: persistence,
+		parameters:  make([]*Persisted[T], 0, targetSize),
+		targetSize:  targetSize,
+		changed:     make(chan struct{}),
+		metrics:     newPoolMetrics(),
+	}
+
+	if config.batchSize > 0 {
+		pool.batch = newPersistenceBatch[T](
+			persistence,
+			logger,
+			pool.metrics,
+			config.batchSize,
+			config.flushInterval,
+		)
+	}
 
 	all, err := persistence.ReadAll()
 	if err != nil {
 		logger.Errorf("failed to read parameters from persistence: [%w]", err)
+		pool.metrics.recordPersistenceError()
 	}
 
 	logger.Debugf("read [%d] parameters from persistence", len(all))
 
 	for i, parameter := range all {
-		// Load to the pool only the number of the parameters read from the persistence
-		// that can fit within the pool's size, to avoid locking on writing to the
-		// channel.
-		if i >= poolSize {
+		// Load to the pool only the number of the parameters read from the
+		// persistence that can fit within the pool's target size.
+		if i >= targetSize {
 			break
 		}
 
-		pool <- parameter
+		pool.parameters = append(pool.parameters, parameter)
 	}
 
-	logger.Infof("loaded [%d] parameters from persistence", len(pool))
+	logger.Infof("loaded [%d] parameters from persistence", len(pool.parameters))
+
+	share := scheduler.registerShare(config.name, config.shareWeight)
+
+	generate := func(ctx context.Context) {
+		// Do not generate parameters while the pool is full. This way the
+		// generation effort follows changes of the pool target size.
+		if !pool.waitForSpace(ctx) {
+			return
+		}
+
+		// Take turns with other pools of the scheduler.
+		if !scheduler.acquire(ctx, share) {
+			return
+		}
 
-	scheduler.compute(func(ctx context.Context) {
 		start := time.Now()
 
-		generated := generateFn(ctx)
+		generated, err := generateFn(ctx)
+
+		scheduler.release(share, time.Since(start))
 
-		// The generateFn returns nil when the context is done. We should not
-		// add nil element to the pool.
-		if generated == nil {
+		// The generation is interrupted when the context is done. It is not
+		// a failure and there is no parameter to add to the pool.
+		if ctx.Err() != nil {
 			return
 		}
 
-		persisted, err := persistence.Save(generated)
-		if err != nil {
-			logger.Errorf(
-				"failed to persist generated parameter: [%w]",
+		if err == nil && generated == nil {
+			err = fmt.Errorf("no parameter generated")
+		}
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			pool.metrics.recordTimeout(err)
+
+			logger.Warnf(
+				"generation of parameters for pool [%s] timed out "+
+					"after [%s]: [%v]",
+				config.name,
+				time.Since(start),
 				err,
 			)
+
+			return
 		}
 
-		select {
-		case pool <- persisted:
-		case <-ctx.Done():
-			// Do not wait on writing to the full channel if the context for
-			// the generateFn was cancelled. This way we can avoid multiple
-			// generator goroutines hanging on writing to the channel in case
-			// the generator was stopped and resumed multiple times and the
-			// pool was full for all that time.
+		if err != nil {
+			failures := pool.metrics.recordFailure(err)
+			backoff := config.generationBackoff(failures)
+
+			logger.Warnf(
+				"failed to generate parameters for pool [%s], "+
+					"consecutive failures: [%d], retrying in [%s]: [%v]",
+				config.name,
+				failures,
+				backoff,
+				err,
+			)
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+			}
+
 			return
 		}
 
+		pool.metrics.recordGenerated(time.Since(start))
+
+		var persisted *Persisted[T]
+		if pool.batch != nil {
+			persisted = pool.batch.add(generated)
+		} else {
+			persisted, err = persistence.Save(generated)
+			if err != nil {
+				logger.Errorf(
+					"failed to persist generated parameter: [%w]",
+					err,
+				)
+				pool.metrics.recordPersistenceError()
+			}
+		}
+
+		parametersCount := pool.add(persisted)
+
 		logger.Infof(
-			"generated new parameters, took: [%s] current pool size: [%d]",
+			"generated new parameters for pool [%s], took: [%s] "+
+				"current pool size: [%d]",
+			config.name,
 			time.Since(start),
-			len(pool),
+			parametersCount,
 		)
 
 		// Wait some time after delivering the result regardless if the delivery
 		// took some time or not. We want to ensure all other processes of the
 		// client receive access to CPU.
 		time.Sleep(generateDelay)
+	}
+
+	scheduler.compute(func(ctx context.Context) {
+		generate(ctx)
+
+		// Save buffered parameters when the scheduler stops the generation,
+		// e.g. because a protocol started executing.
+		if ctx.Err() != nil && pool.batch != nil {
+			if err := pool.batch.flush(); err != nil {
+				logger.Errorf("failed to persist parameters batch: [%v]", err)
+			}
+		}
 	})
 
-	return &ParameterPool[T]{
-		persistence: persistence,
-		pool:        pool,
+	return pool
+}
+
+// notifyChanged wakes up all goroutines waiting for a change of the pool.
+// Must be called with the mutex held.
+func (pp *ParameterPool[T]) notifyChanged() {
+	close(pp.changed)
+	pp.changed = make(chan struct{})
+}
+
+// add appends the parameter to the pool and returns the number of parameters
+// in the pool.
+func (pp *ParameterPool[T]) add(parameter *Persisted[T]) int {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.parameters = append(pp.parameters, parameter)
+	pp.notifyChanged()
+
+	return len(pp.parameters)
+}
+
+// waitForSpace blocks until the number of parameters in the pool is below
+// the target size. Returns false if the context is done before.
+func (pp *ParameterPool[T]) waitForSpace(ctx context.Context) bool {
+	for {
+		pp.mutex.Lock()
+		hasSpace := len(pp.parameters) < pp.targetSize
+		changed := pp.changed
+		pp.mutex.Unlock()
+
+		if hasSpace {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
 	}
 }
 
+// pull removes the oldest parameter from the pool if the consumer of the
+// given priority, waiting for a parameter since the given time, can take it.
+// Returns false if the consumer cannot take any parameter. In that case, the
+// returned channel is closed once the pool changes and the returned duration
+// is the time after which a background consumer can take a reserved
+// parameter, or zero if it never can.
+func (pp *ParameterPool[T]) pull(
+	priority Priority,
+	waitingSince time.Time,
+) (*Persisted[T], <-chan struct{}, time.Duration, bool) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	var reservedWait time.Duration
+	available := len(pp.parameters)
+
+	if priority == BackgroundPriority {
+		available -= pp.reservation.Size
+
+		if maxWait := pp.reservation.MaxBackgroundWait; maxWait > 0 {
+			// Background consumers waiting for too long can take reserved
+			// parameters as well.
+			if waited := time.Since(waitingSince); waited >= maxWait {
+				available = len(pp.parameters)
+			} else {
+				reservedWait = maxWait - waited
+			}
+		}
+	}
+
+	if available <= 0 {
+		return nil, pp.changed, reservedWait, false
+	}
+
+	parameter := pp.parameters[0]
+	pp.parameters[0] = nil
+	pp.parameters = pp.parameters[1:]
+	pp.notifyChanged()
+
+	return parameter, nil, 0, true
+}
+
 // GetNow returns a new parameter from the pool. Returns ErrEmptyPool when the
-// pool is empty.
+// pool is empty. The parameter is taken with the high priority.
 func (pp *ParameterPool[T]) GetNow() (*T, error) {
-	select {
-	case generated := <-pp.pool:
-		err := pp.persistence.Delete(generated)
-		if err != nil {
+	return pp.GetNowWithPriority(HighPriority)
+}
+
+// GetNowWithPriority returns a new parameter from the pool if the consumer
+// of the given priority can take one. Returns ErrEmptyPool otherwise.
+func (pp *ParameterPool[T]) GetNowWithPriority(priority Priority) (*T, error) {
+	generated, _, _, ok := pp.pull(priority, time.Now())
+	if !ok {
+		return nil, ErrEmptyPool
+	}
+
+	return pp.take(generated)
+}
+
+// Get returns a new parameter from the pool. When the pool is empty, it
+// blocks until a new parameter is generated or the provided context is done.
+// In the latter case, the returned error wraps both ErrEmptyPool and the
+// context error. The parameter is taken with the high priority.
+func (pp *ParameterPool[T]) Get(ctx context.Context) (*T, error) {
+	return pp.GetWithPriority(ctx, HighPriority)
+}
+
+// GetWithPriority returns a new parameter from the pool. When the consumer
+// of the given priority cannot take any parameter, it blocks until it can or
+// the provided context is done. See Get for details.
+func (pp *ParameterPool[T]) GetWithPriority(
+	ctx context.Context,
+	priority Priority,
+) (*T, error) {
+	waitingSince := time.Now()
+
+	for {
+		generated, changed, reservedWait, ok := pp.pull(priority, waitingSince)
+		if ok {
+			return pp.take(generated)
+		}
+
+		var timer *time.Timer
+		var reservedAvailable <-chan time.Time
+		if reservedWait > 0 {
+			timer = time.NewTimer(reservedWait)
+			reservedAvailable = timer.C
+		}
+
+		var ctxErr error
+		select {
+		case <-changed:
+		case <-reservedAvailable:
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if ctxErr != nil {
+			return nil, fmt.Errorf("%w: [%w]", ErrEmptyPool, ctxErr)
+		}
+	}
+}
+
+// GetWithTimeout returns a new parameter from the pool. When the pool is
+// empty, it blocks until a new parameter is generated or the provided
+// timeout passes. See Get for details.
+func (pp *ParameterPool[T]) GetWithTimeout(timeout time.Duration) (*T, error) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), timeout)
+	defer cancelCtx()
+
+	return pp.Get(ctx)
+}
+
+// take deletes the parameter pulled from the pool from the persistent
+// storage and returns it.
+func (pp *ParameterPool[T]) take(generated *Persisted[T]) (*T, error) {
+	if pp.batch != nil {
+		if err := pp.batch.persist(generated); err != nil {
 			return nil, fmt.Errorf(
-				"could not delete persisted parameter: [%w]",
+				"could not persist buffered parameter: [%w]",
 				err,
 			)
 		}
+	}
 
-		return &generated.Data, nil
-	default:
-		return nil, ErrEmptyPool
+	err := pp.persistence.Delete(generated)
+	if err != nil {
+		pp.metrics.recordPersistenceError()
+		return nil, fmt.Errorf(
+			"could not delete persisted parameter: [%w]",
+			err,
+		)
 	}
+
+	pp.metrics.recordTaken()
+
+	return &generated.Data, nil
+}
+
+// Flush saves all generated parameters buffered by the pool. It should be
+// called before the client shuts down if the pool persists parameters in
+// batches. It is a no-op otherwise.
+func (pp *ParameterPool[T]) Flush() error {
+	if pp.batch == nil {
+		return nil
+	}
+
+	return pp.batch.flush()
 }
 
 // ParametersCount returns the number of parameters in the pool.
 func (pp *ParameterPool[T]) ParametersCount() int {
-	return len(pp.pool)
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	return len(pp.parameters)
+}
+
+// TargetSize returns the number of parameters the pool generates parameters
+// up to.
+func (pp *ParameterPool[T]) TargetSize() int {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	return pp.targetSize
+}
+
+// SetTargetSize changes the number of parameters the pool generates
+// parameters up to. When the target size grows, the generation resumes
+// immediately. When it shrinks, the generation stops until the number of
+// parameters in the pool drops below the new target size; parameters already
+// in the pool are kept.
+func (pp *ParameterPool[T]) SetTargetSize(targetSize int) error {
+	if targetSize <= 0 {
+		return fmt.Errorf(
+			"target size [%v] must be greater than zero",
+			targetSize,
+		)
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.targetSize = targetSize
+	pp.notifyChanged()
+
+	return nil
+}
+
+// Metrics returns the current metrics of the pool.
+func (pp *ParameterPool[T]) Metrics() *PoolMetrics {
+	metrics := pp.metrics.snapshot(time.Now())
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	metrics.Size = len(pp.parameters)
+	metrics.TargetSize = pp.targetSize
+
+	return metrics
+}
+
+// GenerationError returns the error of the last generation if it failed.
+// Returns nil if the last generation succeeded or nothing was generated yet.
+func (pp *ParameterPool[T]) GenerationError() error {
+	return pp.metrics.generationError()
+}
+
+// Reservation returns the part of the pool reserved for high-priority
+// consumers.
+func (pp *ParameterPool[T]) Reservation() Reservation {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	return pp.reservation
+}
+
+// SetReservation reserves part of the pool for high-priority consumers.
+// Background consumers can take only parameters above the reserved size,
+// unless they wait for a parameter longer than the maximum background wait.
+func (pp *ParameterPool[T]) SetReservation(reservation Reservation) error {
+	if err := reservation.validate(); err != nil {
+		return err
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.reservation = reservation
+	pp.notifyChanged()
+
+	return nil
 }
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"runtime"
@@ -46,9 +47,9 @@ func TestGetNow(t *testing.T) {
 func TestGetNow_EmptyPool(t *testing.T) {
 	pool, scheduler, _ := newTestPool(
 		5,
-		func(ctx context.Context) *big.Int {
+		func(ctx context.Context) (*big.Int, error) {
 			<-ctx.Done()
-			return nil
+			return nil, ctx.Err()
 		},
 	)
 	defer scheduler.stop()
@@ -58,6 +59,248 @@ func TestGetNow_EmptyPool(t *testing.T) {
 	testutils.AssertErrorsSame(t, ErrEmptyPool, err)
 }
 
+// TestGet_WaitsForParameter ensures the `Get` function blocks on an empty
+// pool until a new parameter is generated.
+func TestGet_WaitsForParameter(t *testing.T) {
+	release := make(chan struct{})
+
+	pool, scheduler, _ := newTestPool(
+		1,
+		func(ctx context.Context) (*big.Int, error) {
+			select {
+			case <-release:
+				return big.NewInt(time.Now().UnixNano()), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	)
+	defer scheduler.stop()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Second)
+	defer cancelCtx()
+
+	e, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+	if e == nil {
+		t.Errorf("expected not-nil parameter")
+	}
+}
+
+// TestGetWithTimeout_EmptyPool ensures the `GetWithTimeout` function gives
+// up once the timeout passes and the pool is still empty.
+func TestGetWithTimeout_EmptyPool(t *testing.T) {
+	pool, scheduler, _ := newTestPool(
+		5,
+		func(ctx context.Context) (*big.Int, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	)
+	defer scheduler.stop()
+
+	_, err := pool.GetWithTimeout(20 * time.Millisecond)
+
+	if !errors.Is(err, ErrEmptyPool) {
+		t.Errorf("expected error wrapping [%v]; has: [%v]", ErrEmptyPool, err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(
+			"expected error wrapping [%v]; has: [%v]",
+			context.DeadlineExceeded,
+			err,
+		)
+	}
+}
+
+// TestSetTargetSize ensures the pool follows changes of its target size,
+// generating more parameters when the target size grows and no parameters
+// when it shrinks below the number of parameters in the pool.
+func TestSetTargetSize(t *testing.T) {
+	pool, scheduler, _ := newTestPool(2)
+	defer scheduler.stop()
+
+	waitForCount := func(expectedCount int) {
+		deadline := time.Now().Add(time.Second)
+		for pool.ParametersCount() != expectedCount {
+			if time.Now().After(deadline) {
+				t.Fatalf(
+					"unexpected number of parameters in the pool\n"+
+						"expected: [%v]\nactual:   [%v]",
+					expectedCount,
+					pool.ParametersCount(),
+				)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitForCount(2)
+
+	if err := pool.SetTargetSize(4); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "target size", 4, pool.TargetSize())
+
+	waitForCount(4)
+
+	if err := pool.SetTargetSize(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parameters already in the pool are kept and no new parameters are
+	// generated until the pool drops below the new target size.
+	for i := 0; i < 4; i++ {
+		if _, err := pool.GetNow(); err != nil {
+			t.Fatalf("unexpected error: [%v]", err)
+		}
+	}
+
+	waitForCount(1)
+
+	// give some time to make sure no more parameters are generated
+	time.Sleep(25 * time.Millisecond)
+	testutils.AssertIntsEqual(
+		t,
+		"number of parameters in the pool",
+		1,
+		pool.ParametersCount(),
+	)
+}
+
+// TestSetTargetSize_Invalid ensures the target size must be positive.
+func TestSetTargetSize_Invalid(t *testing.T) {
+	pool, scheduler, _ := newTestPool(2)
+	defer scheduler.stop()
+
+	err := pool.SetTargetSize(0)
+
+	expectedError := "target size [0] must be greater than zero"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+	testutils.AssertIntsEqual(t, "target size", 2, pool.TargetSize())
+}
+
+// TestGetWithPriority_Reservation ensures background consumers can not take
+// parameters reserved for high-priority consumers.
+func TestGetWithPriority_Reservation(t *testing.T) {
+	pool, scheduler := newTestPoolWithSupply(3, 3)
+	defer scheduler.stop()
+
+	err := pool.SetReservation(Reservation{Size: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := pool.GetNowWithPriority(BackgroundPriority); err != nil {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	_, err = pool.GetNowWithPriority(BackgroundPriority)
+	testutils.AssertErrorsSame(t, ErrEmptyPool, err)
+
+	ctx, cancelCtx := context.WithTimeout(
+		context.Background(),
+		20*time.Millisecond,
+	)
+	defer cancelCtx()
+
+	_, err = pool.GetWithPriority(ctx, BackgroundPriority)
+	if !errors.Is(err, ErrEmptyPool) {
+		t.Errorf("expected error wrapping [%v]; has: [%v]", ErrEmptyPool, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := pool.GetNowWithPriority(HighPriority); err != nil {
+			t.Fatalf("unexpected error: [%v]", err)
+		}
+	}
+}
+
+// TestGetWithPriority_MaxBackgroundWait ensures background consumers waiting
+// for too long can take reserved parameters.
+func TestGetWithPriority_MaxBackgroundWait(t *testing.T) {
+	pool, scheduler := newTestPoolWithSupply(2, 2)
+	defer scheduler.stop()
+
+	maxBackgroundWait := 30 * time.Millisecond
+
+	err := pool.SetReservation(Reservation{
+		Size:              2,
+		MaxBackgroundWait: maxBackgroundWait,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Second)
+	defer cancelCtx()
+
+	start := time.Now()
+
+	if _, err := pool.GetWithPriority(ctx, BackgroundPriority); err != nil {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	if waited := time.Since(start); waited < maxBackgroundWait {
+		t.Errorf(
+			"reserved parameter taken too early\n"+
+				"expected at least: [%v]\nactual:            [%v]",
+			maxBackgroundWait,
+			waited,
+		)
+	}
+}
+
+// TestSetReservation_Invalid ensures the reservation is validated.
+func TestSetReservation_Invalid(t *testing.T) {
+	pool, scheduler, _ := newTestPool(2)
+	defer scheduler.stop()
+
+	var tests = map[string]struct {
+		reservation   Reservation
+		expectedError string
+	}{
+		"negative size": {
+			reservation:   Reservation{Size: -1},
+			expectedError: "reserved size [-1] must not be negative",
+		},
+		"negative maximum background wait": {
+			reservation:   Reservation{MaxBackgroundWait: -time.Second},
+			expectedError: "maximum background wait [-1s] must not be negative",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := pool.SetReservation(test.reservation)
+			if err == nil || err.Error() != test.expectedError {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+
+	if reservation := pool.Reservation(); reservation != (Reservation{}) {
+		t.Errorf("unexpected reservation: [%+v]", reservation)
+	}
+}
+
 // TestStop ensures the pool honors the stop signal send to the scheduler and it
 // does not keep generating params in some internal loop.
 func TestStop(t *testing.T) {
@@ -118,12 +361,13 @@ func TestStopBlocked(t *testing.T) {
 }
 
 // TestStopNoNils ensures no nil result is added to the pool when the context
-// passed to the generate function is done. The generateFn returns nil when the
-// context is done and we should not add nil elements to the pool.
+// passed to the generate function is done. The generateFn returns nil and the
+// context error when the context is done and we should not add nil elements
+// to the pool.
 func TestStopNoNils(t *testing.T) {
-	pool, scheduler, _ := newTestPool(50000, func(ctx context.Context) *big.Int {
+	pool, scheduler, _ := newTestPool(50000, func(ctx context.Context) (*big.Int, error) {
 		<-ctx.Done()
-		return nil
+		return nil, ctx.Err()
 	})
 
 	// give some time to generate parameters and stop
@@ -138,6 +382,156 @@ func TestStopNoNils(t *testing.T) {
 	}
 }
 
+// TestGenerationTimeouts ensures generation timeouts are not failures and
+// the pool does not back off on them.
+func TestGenerationTimeouts(t *testing.T) {
+	timeout := fmt.Errorf("generation timed out: [%w]", context.DeadlineExceeded)
+
+	var attemptsMutex sync.Mutex
+	attempts := 0
+
+	scheduler := &Scheduler{}
+	defer scheduler.stop()
+
+	pool := NewParameterPool[big.Int](
+		logger,
+		scheduler,
+		&mockPersistence{storage: make(map[string]*big.Int)},
+		1,
+		func(ctx context.Context) (*big.Int, error) {
+			attemptsMutex.Lock()
+			defer attemptsMutex.Unlock()
+
+			attempts++
+			if attempts <= 3 {
+				return nil, timeout
+			}
+
+			return big.NewInt(1), nil
+		},
+		0,
+		// No parameter would be generated in time if the pool backed off.
+		WithGenerationBackoff(time.Hour, time.Hour),
+	)
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Second)
+	defer cancelCtx()
+
+	if _, err := pool.Get(ctx); err != nil {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	metrics := pool.Metrics()
+	testutils.AssertUintsEqual(t, "timeouts count", 3, metrics.TimeoutsCount)
+	testutils.AssertUintsEqual(t, "failures count", 0, metrics.FailuresCount)
+	testutils.AssertIntsEqual(
+		t,
+		"consecutive failures",
+		0,
+		metrics.ConsecutiveFailures,
+	)
+}
+
+// TestGenerationFailures ensures the pool backs off exponentially on
+// repeated generation failures and exposes the failure state until
+// a generation succeeds.
+func TestGenerationFailures(t *testing.T) {
+	failure := fmt.Errorf("generation failed")
+
+	var attemptsMutex sync.Mutex
+	attempts := make([]time.Time, 0)
+
+	scheduler := &Scheduler{}
+	defer scheduler.stop()
+
+	pool := NewParameterPool[big.Int](
+		logger,
+		scheduler,
+		&mockPersistence{storage: make(map[string]*big.Int)},
+		1,
+		func(ctx context.Context) (*big.Int, error) {
+			attemptsMutex.Lock()
+			defer attemptsMutex.Unlock()
+
+			attempts = append(attempts, time.Now())
+			if len(attempts) <= 3 {
+				return nil, failure
+			}
+
+			return big.NewInt(1), nil
+		},
+		0,
+		WithGenerationBackoff(20*time.Millisecond, 40*time.Millisecond),
+	)
+
+	// Wait for the first failure.
+	for pool.Metrics().FailuresCount == 0 {
+		runtime.Gosched()
+	}
+
+	testutils.AssertErrorsSame(t, failure, pool.GenerationError())
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Second)
+	defer cancelCtx()
+
+	if _, err := pool.Get(ctx); err != nil {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	if err := pool.GenerationError(); err != nil {
+		t.Errorf("unexpected generation error: [%v]", err)
+	}
+
+	metrics := pool.Metrics()
+	testutils.AssertUintsEqual(t, "failures count", 3, metrics.FailuresCount)
+	testutils.AssertIntsEqual(
+		t,
+		"consecutive failures",
+		0,
+		metrics.ConsecutiveFailures,
+	)
+
+	attemptsMutex.Lock()
+	defer attemptsMutex.Unlock()
+
+	// The backoff doubles with every failure, up to the maximum.
+	for i, minBackoff := range []time.Duration{
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		40 * time.Millisecond,
+	} {
+		if backoff := attempts[i+1].Sub(attempts[i]); backoff < minBackoff {
+			t.Errorf(
+				"backoff after failure [%v] too short: [%v]",
+				i+1,
+				backoff,
+			)
+		}
+	}
+}
+
+func TestGenerationBackoff(t *testing.T) {
+	config := &poolConfig{
+		initialBackoff: 10 * time.Second,
+		maxBackoff:     time.Minute,
+	}
+
+	for failures, expectedBackoff := range map[int]time.Duration{
+		1:   10 * time.Second,
+		2:   20 * time.Second,
+		3:   40 * time.Second,
+		4:   time.Minute,
+		100: time.Minute,
+	} {
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("backoff after [%v] failures", failures),
+			int(expectedBackoff),
+			int(config.generationBackoff(failures)),
+		)
+	}
+}
+
 // TestPersist ensures parameters generated by the pool are persisted.
 func TestPersist(t *testing.T) {
 	pool, scheduler, persistence := newTestPool(50000)
@@ -215,7 +609,7 @@ func TestDelete(t *testing.T) {
 
 func newTestPool(
 	targetSize int,
-	optionalGenerateFn ...func(context.Context) *big.Int,
+	optionalGenerateFn ...func(context.Context) (*big.Int, error),
 ) (*ParameterPool[big.Int], *Scheduler, *mockPersistence) {
 	persistence := &mockPersistence{storage: make(map[string]*big.Int)}
 	pool, scheduler := newTestPoolWithPersistence(
@@ -229,16 +623,16 @@ func newTestPool(
 func newTestPoolWithPersistence(
 	targetSize int,
 	persistence *mockPersistence,
-	optionalGenerateFn ...func(context.Context) *big.Int,
+	optionalGenerateFn ...func(context.Context) (*big.Int, error),
 ) (*ParameterPool[big.Int], *Scheduler) {
-	var generateFn func(context.Context) *big.Int
+	var generateFn func(context.Context) (*big.Int, error)
 
 	if len(optionalGenerateFn) == 1 {
 		generateFn = optionalGenerateFn[0]
 	} else {
-		generateFn = func(context.Context) *big.Int {
+		generateFn = func(context.Context) (*big.Int, error) {
 			time.Sleep(5 * time.Millisecond)
-			return big.NewInt(time.Now().UnixMilli())
+			return big.NewInt(time.Now().UnixMilli()), nil
 		}
 	}
 
@@ -254,6 +648,36 @@ func newTestPoolWithPersistence(
 	), scheduler
 }
 
+// newTestPoolWithSupply creates a pool generating no more than the given
+// number of parameters and waits until all of them are in the pool.
+func newTestPoolWithSupply(
+	targetSize int,
+	supplySize int,
+) (*ParameterPool[big.Int], *Scheduler) {
+	supply := make(chan *big.Int, supplySize)
+	for i := 0; i < supplySize; i++ {
+		supply <- big.NewInt(int64(i))
+	}
+
+	pool, scheduler, _ := newTestPool(
+		targetSize,
+		func(ctx context.Context) (*big.Int, error) {
+			select {
+			case parameter := <-supply:
+				return parameter, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	)
+
+	for pool.ParametersCount() != supplySize {
+		runtime.Gosched()
+	}
+
+	return pool, scheduler
+}
+
 type mockPersistence struct {
 	storage map[string]*big.Int
 	mutex   sync.RWMutex
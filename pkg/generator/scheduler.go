@@ -29,6 +29,15 @@ type Protocol interface {
 // client. This way, the client that would normally be idle, can spend CPU
 // cycles on computationally heavy operations and stop these operations when CPU
 // cycles are needed elsewhere.
+//
+// Multiple parameter pools can be registered in one scheduler. The pools
+// share a budget of generation slots and take turns in using them according
+// to weights of their shares of the generation time.
+//
+// If the scheduler monitors the system load, computations are also stopped
+// when other processes of the machine use almost all of its CPU capacity,
+// and computations can adapt their concurrency level to the CPU capacity
+// left idle.
 type Scheduler struct {
 	state     state
 	workers   []func(context.Context)
@@ -37,6 +46,18 @@ type Scheduler struct {
 
 	protocols      []Protocol
 	protocolsMutex sync.Mutex
+
+	load *loadMonitor
+
+	// generationSlots is the number of generations run at the same time
+	// across all pools. DefaultGenerationSlots is used if not positive.
+	generationSlots    int
+	runningGenerations int
+	shares             []*share
+	// budgetChanged is closed and replaced every time a generation slot is
+	// released or a share stops waiting for it.
+	budgetChanged chan struct{}
+	budgetMutex   sync.Mutex
 }
 
 // RegisterProtocol adds the provided protocol to the list that will be
@@ -127,21 +148,29 @@ func (s *Scheduler) startWorker(workerFn func(context.Context)) {
 	}()
 }
 
+// Concurrency returns the concurrency level computations should use, given
+// the maximum concurrency level they are allowed to use. If the scheduler
+// monitors the system load, the number of CPUs not used by other processes,
+// capped to the maximum, is returned. Otherwise, the maximum is returned.
+func (s *Scheduler) Concurrency(maxConcurrency int) int {
+	if s.load == nil {
+		return maxConcurrency
+	}
+
+	return s.load.concurrency(maxConcurrency)
+}
+
 // CheckProtocol executed a check loop over all registered protocols. If at
 // least one of the protocols is currently executing, the scheduler stops all
 // computations. Computations are automatically resumed once none of the
 // protocols is executing. If there are no protocols registered, the scheduler
-// continues to work.
+// continues to work. If the scheduler monitors the system load, computations
+// are stopped as well when the machine is overloaded by other processes or
+// by the client's protocols.
 func (s *Scheduler) checkProtocols() {
 	s.protocolsMutex.Lock()
 	defer s.protocolsMutex.Unlock()
 
-	// No protocols and scheduler is working by default. Returning to keep it
-	// working because nothing can stop the scheduler right now.
-	if len(s.protocols) == 0 {
-		return
-	}
-
 	atLeastOneProtocolExecuting := false
 
 	for _, protocol := range s.protocols {
@@ -151,6 +180,22 @@ func (s *Scheduler) checkProtocols() {
 		}
 	}
 
+	if s.load != nil {
+		s.load.sample(atLeastOneProtocolExecuting)
+
+		if s.load.isOverloaded() {
+			s.stop()
+			return
+		}
+	}
+
+	// No protocols and scheduler is working by default. Resuming to keep it
+	// working because nothing else can stop the scheduler right now.
+	if len(s.protocols) == 0 {
+		s.resume()
+		return
+	}
+
 	if atLeastOneProtocolExecuting {
 		s.stop()
 	} else {
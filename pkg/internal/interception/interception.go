@@ -81,3 +81,7 @@ func (c *channel) SetUnmarshaler(unmarshaler func() net.TaggedUnmarshaler) {
 func (c *channel) SetFilter(filter net.BroadcastChannelFilter) error {
 	return nil // no-op
 }
+
+func (c *channel) AddValidator(validator net.BroadcastChannelValidator) error {
+	return nil // no-op
+}
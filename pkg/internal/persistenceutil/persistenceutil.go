@@ -0,0 +1,42 @@
+// Package persistenceutil provides helper utilities for working with
+// persistence handles.
+package persistenceutil
+
+import (
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+// ReadAll reads all data persisted by the given handle. The descriptorFn
+// function is called for every data descriptor read and the errorFn function
+// is called for every error that occurred during reading. Both functions are
+// called sequentially from the calling goroutine so they can safely modify
+// shared state. ReadAll returns once all data were read.
+func ReadAll(
+	handle persistence.RWHandle,
+	descriptorFn func(descriptor persistence.DataDescriptor),
+	errorFn func(err error),
+) {
+	descriptorsChan, errorsChan := handle.ReadAll()
+
+	// Both channels are read at the same time as they do not have to be
+	// buffered and we do not know in what order the information is written
+	// to them. A closed channel is set to nil so it is no longer selected.
+	for descriptorsChan != nil || errorsChan != nil {
+		select {
+		case descriptor, ok := <-descriptorsChan:
+			if !ok {
+				descriptorsChan = nil
+				continue
+			}
+
+			descriptorFn(descriptor)
+		case err, ok := <-errorsChan:
+			if !ok {
+				errorsChan = nil
+				continue
+			}
+
+			errorFn(err)
+		}
+	}
+}
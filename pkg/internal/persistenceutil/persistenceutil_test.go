@@ -0,0 +1,96 @@
+package persistenceutil
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+type testDescriptor struct {
+	name string
+}
+
+func (td *testDescriptor) Name() string {
+	return td.name
+}
+
+func (td *testDescriptor) Directory() string {
+	return "dir"
+}
+
+func (td *testDescriptor) Content() ([]byte, error) {
+	return nil, nil
+}
+
+// testHandle writes descriptors and errors to unbuffered channels, all
+// errors first, so that reading only the descriptors channel first would
+// block forever.
+type testHandle struct {
+	names []string
+	errs  []error
+}
+
+func (th *testHandle) Save(data []byte, directory string, name string) error {
+	return nil
+}
+
+func (th *testHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	descriptorsChan := make(chan persistence.DataDescriptor)
+	errorsChan := make(chan error)
+
+	go func() {
+		defer close(descriptorsChan)
+		defer close(errorsChan)
+
+		for _, err := range th.errs {
+			errorsChan <- err
+		}
+
+		for _, name := range th.names {
+			descriptorsChan <- &testDescriptor{name}
+		}
+	}()
+
+	return descriptorsChan, errorsChan
+}
+
+func TestReadAll(t *testing.T) {
+	handle := &testHandle{
+		names: []string{"a", "b", "c"},
+		errs:  []error{fmt.Errorf("first"), fmt.Errorf("second")},
+	}
+
+	var names []string
+	var errs []error
+
+	ReadAll(
+		handle,
+		func(descriptor persistence.DataDescriptor) {
+			names = append(names, descriptor.Name())
+		},
+		func(err error) {
+			errs = append(errs, err)
+		},
+	)
+
+	if !reflect.DeepEqual(handle.names, names) {
+		t.Errorf(
+			"unexpected names\nexpected: [%v]\nactual:   [%v]",
+			handle.names,
+			names,
+		)
+	}
+
+	if !reflect.DeepEqual(handle.errs, errs) {
+		t.Errorf(
+			"unexpected errors\nexpected: [%v]\nactual:   [%v]",
+			handle.errs,
+			errs,
+		)
+	}
+}
@@ -90,6 +90,13 @@ func (lbc *localBitcoinChain) GetTransactionsForPublicKeyHash(
 	panic("unsupported")
 }
 
+func (lbc *localBitcoinChain) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	panic("unsupported")
+}
+
 func (lbc *localBitcoinChain) GetTxHashesForPublicKeyHash(
 	publicKeyHash [20]byte,
 ) ([]bitcoin.Hash, error) {
@@ -242,6 +242,18 @@ func (bdm *bitcoinDifficultyMaintainer) proveNextEpoch(ctx context.Context) (
 	// blockchain only if the blockchain height is equal to or greater than
 	// the end of the range.
 	if currentBlockHeight >= lastBlockHeaderHeight {
+		// If the relay is several epochs behind, epochs are proven one by
+		// one without idling until the relay catches up.
+		if epochsBehind := currentBlockHeight/bitcoinDifficultyEpochLength -
+			uint(currentEpoch); epochsBehind > 1 {
+			logger.Infof(
+				"the Bitcoin difficulty chain is [%d] epochs behind the "+
+					"Bitcoin blockchain; catching up starting from epoch [%d]",
+				epochsBehind,
+				newEpoch,
+			)
+		}
+
 		headers, err := bdm.getBlockHeaders(
 			firstBlockHeaderHeight,
 			lastBlockHeaderHeight,
@@ -318,22 +330,20 @@ func (bdm *bitcoinDifficultyMaintainer) getBlockHeaders(
 ) (
 	[]*bitcoin.BlockHeader, error,
 ) {
-	var headers []*bitcoin.BlockHeader
-
-	// TODO: Consider modifying the Bitcoin chain to return multiple block
-	//       headers: GetBlockHeaders(startHeight, count). Return multiple
-	//       block headers with one call instead of iterating.
-	for height := firstHeaderHeight; height <= lastHeaderHeight; height++ {
-		header, err := bdm.btcChain.GetBlockHeader(height)
-		if err != nil {
-			return []*bitcoin.BlockHeader{}, fmt.Errorf(
-				"failed to get block header at height %d: [%w]",
-				height,
-				err,
-			)
-		}
-
-		headers = append(headers, header)
+	// Headers are fetched with a single call if the Bitcoin chain supports
+	// that. Otherwise, they are fetched one by one.
+	headers, err := bitcoin.GetBlockHeaders(
+		bdm.btcChain,
+		firstHeaderHeight,
+		lastHeaderHeight-firstHeaderHeight+1,
+	)
+	if err != nil {
+		return []*bitcoin.BlockHeader{}, fmt.Errorf(
+			"failed to get block headers from range [%d:%d]: [%w]",
+			firstHeaderHeight,
+			lastHeaderHeight,
+			err,
+		)
 	}
 
 	return headers, nil
@@ -21,6 +21,9 @@ type localBitcoinDifficultyChain struct {
 	currentEpoch uint64
 	proofLength  uint64
 
+	currentEpochDifficulty  *big.Int
+	previousEpochDifficulty *big.Int
+
 	ready                        bool
 	authorizedOperators          map[chain.Address]bool
 	authorizedForRefundOperators map[chain.Address]bool
@@ -117,7 +120,7 @@ func (lbdc *localBitcoinDifficultyChain) ProofLength() (uint64, error) {
 func (lbdc *localBitcoinDifficultyChain) GetCurrentAndPrevEpochDifficulty() (
 	*big.Int, *big.Int, error,
 ) {
-	panic("unimplemented")
+	return lbdc.currentEpochDifficulty, lbdc.previousEpochDifficulty, nil
 }
 
 // SetReady sets chain's status as either ready or not.
@@ -153,6 +156,16 @@ func (lbdc *localBitcoinDifficultyChain) SetProofLength(proofLength uint64) {
 	lbdc.proofLength = proofLength
 }
 
+// SetEpochDifficulties sets the difficulties of the current and previous
+// epochs.
+func (lbdc *localBitcoinDifficultyChain) SetEpochDifficulties(
+	currentEpochDifficulty *big.Int,
+	previousEpochDifficulty *big.Int,
+) {
+	lbdc.currentEpochDifficulty = currentEpochDifficulty
+	lbdc.previousEpochDifficulty = previousEpochDifficulty
+}
+
 // RetargetEvents returns all invocations of the Retarget method.
 func (lbdc *localBitcoinDifficultyChain) RetargetEvents() []*RetargetEvent {
 	return lbdc.retargetEvents
@@ -0,0 +1,219 @@
+package btcdiff
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// RelayAudit is the result of auditing the Bitcoin difficulty chain against
+// the Bitcoin blockchain.
+type RelayAudit struct {
+	// CurrentEpoch is the latest epoch proven to the relay.
+	CurrentEpoch uint64
+	// LatestProvableEpoch is the latest epoch of the Bitcoin blockchain
+	// whose retarget proof can already be built, i.e. the epoch has at least
+	// the relay proof length of blocks.
+	LatestProvableEpoch uint64
+
+	// CurrentEpochDifficulty is the difficulty of the current epoch stored
+	// in the relay.
+	CurrentEpochDifficulty *big.Int
+	// ExpectedCurrentEpochDifficulty is the difficulty of the current epoch
+	// according to the Bitcoin blockchain.
+	ExpectedCurrentEpochDifficulty *big.Int
+
+	// PreviousEpochDifficulty is the difficulty of the previous epoch stored
+	// in the relay. It is zero if no retarget was done since the genesis.
+	PreviousEpochDifficulty *big.Int
+	// ExpectedPreviousEpochDifficulty is the difficulty of the previous
+	// epoch according to the Bitcoin blockchain.
+	ExpectedPreviousEpochDifficulty *big.Int
+}
+
+// EpochsBehind returns the number of epochs the relay lags behind the
+// Bitcoin blockchain.
+func (ra *RelayAudit) EpochsBehind() uint64 {
+	if ra.LatestProvableEpoch <= ra.CurrentEpoch {
+		return 0
+	}
+
+	return ra.LatestProvableEpoch - ra.CurrentEpoch
+}
+
+// DifficultiesMatch returns true if epoch difficulties stored in the relay
+// match the Bitcoin blockchain. The previous epoch difficulty is not
+// compared if it was not initialized yet, i.e. no retarget was done since
+// the genesis.
+func (ra *RelayAudit) DifficultiesMatch() bool {
+	if ra.CurrentEpochDifficulty.Cmp(ra.ExpectedCurrentEpochDifficulty) != 0 {
+		return false
+	}
+
+	if ra.PreviousEpochDifficulty.Sign() == 0 {
+		return true
+	}
+
+	return ra.PreviousEpochDifficulty.Cmp(
+		ra.ExpectedPreviousEpochDifficulty,
+	) == 0
+}
+
+// AuditRelay audits the Bitcoin difficulty chain against the Bitcoin
+// blockchain. It determines how many epochs the relay lags behind and
+// whether the epoch difficulties stored in the relay match the difficulties
+// of the Bitcoin blockchain.
+func AuditRelay(btcChain bitcoin.Chain, chain Chain) (*RelayAudit, error) {
+	isReady, err := chain.Ready()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot check whether genesis has been performed: [%w]",
+			err,
+		)
+	}
+
+	if !isReady {
+		return nil, errNoGenesis
+	}
+
+	currentEpoch, err := chain.CurrentEpoch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current epoch: [%w]", err)
+	}
+
+	proofLength, err := chain.ProofLength()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proof length: [%w]", err)
+	}
+
+	currentDifficulty, previousDifficulty, err :=
+		chain.GetCurrentAndPrevEpochDifficulty()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get epoch difficulties: [%w]",
+			err,
+		)
+	}
+
+	currentBlockHeight, err := btcChain.GetLatestBlockHeight()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get latest block height: [%w]",
+			err,
+		)
+	}
+
+	// The retarget proof of an epoch requires `proofLength` blocks of the
+	// epoch itself.
+	latestProvableEpoch := uint64(0)
+	if currentBlockHeight+1 >= uint(proofLength) {
+		latestProvableEpoch = uint64(
+			(currentBlockHeight + 1 - uint(proofLength)) /
+				bitcoinDifficultyEpochLength,
+		)
+	}
+
+	expectedCurrentDifficulty, err := epochDifficulty(btcChain, currentEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedPreviousDifficulty := new(big.Int)
+	if currentEpoch > 0 {
+		expectedPreviousDifficulty, err = epochDifficulty(
+			btcChain,
+			currentEpoch-1,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &RelayAudit{
+		CurrentEpoch:                    currentEpoch,
+		LatestProvableEpoch:             latestProvableEpoch,
+		CurrentEpochDifficulty:          currentDifficulty,
+		ExpectedCurrentEpochDifficulty:  expectedCurrentDifficulty,
+		PreviousEpochDifficulty:         previousDifficulty,
+		ExpectedPreviousEpochDifficulty: expectedPreviousDifficulty,
+	}, nil
+}
+
+// epochDifficulty returns the difficulty of the given epoch according to
+// the Bitcoin blockchain. All blocks of the epoch have the same difficulty
+// so the difficulty of the first block is taken.
+func epochDifficulty(btcChain bitcoin.Chain, epoch uint64) (*big.Int, error) {
+	header, err := btcChain.GetBlockHeader(
+		uint(epoch) * bitcoinDifficultyEpochLength,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get first block header of epoch [%d]: [%w]",
+			epoch,
+			err,
+		)
+	}
+
+	return header.Difficulty(), nil
+}
+
+// BackfillRelay proves epochs missing in the Bitcoin difficulty chain, in
+// order, until the given target epoch is proven or the relay is up-to-date
+// with the Bitcoin blockchain. Zero target epoch means the relay should be
+// brought up-to-date. The progress is read from the relay before each epoch
+// is proven so an interrupted backfill resumes from the latest proven epoch
+// once run again. The number of proven epochs is returned.
+func BackfillRelay(
+	ctx context.Context,
+	config Config,
+	btcChain bitcoin.Chain,
+	chain Chain,
+	targetEpoch uint64,
+) (uint64, error) {
+	bdm := &bitcoinDifficultyMaintainer{
+		config:   config,
+		btcChain: btcChain,
+		chain:    chain,
+	}
+
+	if err := bdm.verifySubmissionEligibility(); err != nil {
+		return 0, fmt.Errorf(
+			"cannot verify submission eligibility: [%w]",
+			err,
+		)
+	}
+
+	provenEpochs := uint64(0)
+
+	for {
+		if targetEpoch != 0 {
+			currentEpoch, err := chain.CurrentEpoch()
+			if err != nil {
+				return provenEpochs, fmt.Errorf(
+					"failed to get current epoch: [%w]",
+					err,
+				)
+			}
+
+			if currentEpoch >= targetEpoch {
+				return provenEpochs, nil
+			}
+		}
+
+		epochProven, err := bdm.proveNextEpoch(ctx)
+		if err != nil {
+			return provenEpochs, fmt.Errorf(
+				"cannot prove Bitcoin blockchain epoch: [%w]",
+				err,
+			)
+		}
+
+		if !epochProven {
+			return provenEpochs, nil
+		}
+
+		provenEpochs++
+	}
+}
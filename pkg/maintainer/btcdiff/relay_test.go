@@ -0,0 +1,190 @@
+package btcdiff
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestAuditRelay(t *testing.T) {
+	previousEpochHeader := &bitcoin.BlockHeader{Bits: 0x1b0404cb}
+	currentEpochHeader := &bitcoin.BlockHeader{Bits: 0x1a05db8b}
+
+	tests := map[string]struct {
+		currentEpochDifficulty  *big.Int
+		previousEpochDifficulty *big.Int
+		expectedMatch           bool
+	}{
+		"matching difficulties": {
+			currentEpochDifficulty:  currentEpochHeader.Difficulty(),
+			previousEpochDifficulty: previousEpochHeader.Difficulty(),
+			expectedMatch:           true,
+		},
+		"uninitialized previous epoch difficulty": {
+			currentEpochDifficulty:  currentEpochHeader.Difficulty(),
+			previousEpochDifficulty: big.NewInt(0),
+			expectedMatch:           true,
+		},
+		"mismatched current epoch difficulty": {
+			currentEpochDifficulty:  previousEpochHeader.Difficulty(),
+			previousEpochDifficulty: previousEpochHeader.Difficulty(),
+			expectedMatch:           false,
+		},
+		"mismatched previous epoch difficulty": {
+			currentEpochDifficulty:  currentEpochHeader.Difficulty(),
+			previousEpochDifficulty: currentEpochHeader.Difficulty(),
+			expectedMatch:           false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			btcChain := connectLocalBitcoinChain()
+			btcChain.SetBlockHeaders(map[uint]*bitcoin.BlockHeader{
+				600768: previousEpochHeader, // First block of epoch 298.
+				602784: currentEpochHeader,  // First block of epoch 299.
+				// The tip is the third block of epoch 301 so epoch 301 is
+				// provable with the proof length of 3.
+				606818: {},
+			})
+
+			difficultyChain := connectLocalBitcoinDifficultyChain()
+			difficultyChain.SetReady(true)
+			difficultyChain.SetCurrentEpoch(299)
+			difficultyChain.SetProofLength(3)
+			difficultyChain.SetEpochDifficulties(
+				test.currentEpochDifficulty,
+				test.previousEpochDifficulty,
+			)
+
+			audit, err := AuditRelay(btcChain, difficultyChain)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"latest provable epoch",
+				301,
+				audit.LatestProvableEpoch,
+			)
+			testutils.AssertUintsEqual(
+				t,
+				"epochs behind",
+				2,
+				audit.EpochsBehind(),
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"difficulties match",
+				test.expectedMatch,
+				audit.DifficultiesMatch(),
+			)
+		})
+	}
+}
+
+func TestAuditRelay_NoGenesis(t *testing.T) {
+	difficultyChain := connectLocalBitcoinDifficultyChain()
+
+	_, err := AuditRelay(connectLocalBitcoinChain(), difficultyChain)
+	testutils.AssertAnyErrorInChainMatchesTarget(t, errNoGenesis, err)
+}
+
+func TestBackfillRelay(t *testing.T) {
+	tests := map[string]struct {
+		targetEpoch          uint64
+		expectedProvenEpochs uint64
+		expectedCurrentEpoch uint64
+	}{
+		"up to the Bitcoin blockchain tip": {
+			targetEpoch:          0,
+			expectedProvenEpochs: 2,
+			expectedCurrentEpoch: 300,
+		},
+		"up to the target epoch": {
+			targetEpoch:          299,
+			expectedProvenEpochs: 1,
+			expectedCurrentEpoch: 299,
+		},
+		"target epoch already proven": {
+			targetEpoch:          298,
+			expectedProvenEpochs: 0,
+			expectedCurrentEpoch: 298,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			btcChain := connectLocalBitcoinChain()
+			btcChain.SetBlockHeaders(map[uint]*bitcoin.BlockHeader{
+				602783: {Bits: 1111111}, // Last block of epoch 298.
+				602784: {Bits: 2222222}, // First block of epoch 299.
+				604799: {Bits: 2222222}, // Last block of epoch 299.
+				604800: {Bits: 3333333}, // First block of epoch 300.
+			})
+
+			difficultyChain := connectLocalBitcoinDifficultyChain()
+			difficultyChain.SetReady(true)
+			difficultyChain.SetAuthorizedOperator(
+				difficultyChain.Signing().Address(),
+				true,
+			)
+			difficultyChain.SetCurrentEpoch(298)
+			difficultyChain.SetProofLength(1)
+
+			provenEpochs, err := BackfillRelay(
+				context.Background(),
+				Config{DisableProxy: true},
+				btcChain,
+				difficultyChain,
+				test.targetEpoch,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"proven epochs",
+				test.expectedProvenEpochs,
+				provenEpochs,
+			)
+
+			currentEpoch, err := difficultyChain.CurrentEpoch()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"current epoch",
+				test.expectedCurrentEpoch,
+				currentEpoch,
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"retarget events",
+				int(test.expectedProvenEpochs),
+				len(difficultyChain.RetargetEvents()),
+			)
+		})
+	}
+}
+
+func TestBackfillRelay_NotAuthorized(t *testing.T) {
+	difficultyChain := connectLocalBitcoinDifficultyChain()
+	difficultyChain.SetReady(true)
+
+	_, err := BackfillRelay(
+		context.Background(),
+		Config{DisableProxy: true},
+		connectLocalBitcoinChain(),
+		difficultyChain,
+		0,
+	)
+	testutils.AssertAnyErrorInChainMatchesTarget(t, errNotAuthorized, err)
+}
@@ -3,8 +3,10 @@ package maintainer
 import (
 	"context"
 	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-common/pkg/persistence"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/maintainer/btcdiff"
 	"github.com/keep-network/keep-core/pkg/maintainer/spv"
 )
@@ -14,9 +16,13 @@ var logger = log.Logger("keep-maintainer")
 func Initialize(
 	ctx context.Context,
 	config Config,
+	btcNetwork bitcoin.Network,
 	btcChain bitcoin.Chain,
+	fallbackBtcChains []bitcoin.Chain,
 	btcDiffChain btcdiff.Chain,
 	spvChain spv.Chain,
+	persistence persistence.BasicHandle,
+	clientInfo *clientinfo.Registry,
 ) {
 	// If none of the maintainers was specified in the config (i.e. no option was
 	// provided to the `maintainer` command), all maintainers should be launched.
@@ -40,9 +46,13 @@ func Initialize(
 		spv.Initialize(
 			ctx,
 			config.Spv,
+			btcNetwork,
 			spvChain,
 			btcDiffChain,
 			btcChain,
+			fallbackBtcChains,
+			persistence,
+			clientInfo,
 		)
 	}
 
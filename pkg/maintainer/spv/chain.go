@@ -9,16 +9,30 @@ import (
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
+// SubmissionOptions holds the fee parameters of proof submission
+// transactions. Nil values are estimated by the chain.
+type SubmissionOptions struct {
+	// GasTipCap is the maximum priority fee per gas, in wei, the submitter
+	// is willing to pay.
+	GasTipCap *big.Int
+	// GasFeeCap is the maximum total fee per gas, in wei, the submitter
+	// is willing to pay.
+	GasFeeCap *big.Int
+}
+
 type Chain interface {
 	// SubmitDepositSweepProofWithReimbursement submits the deposit sweep proof
 	// via MaintainerProxy. It is used to prove the deposit sweep Bitcoin
 	// transaction and update depositors' balances. The caller is reimbursed.
+	// The returned value is the maximum fee of the submission transaction,
+	// i.e. its gas limit multiplied by its gas fee cap.
 	SubmitDepositSweepProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
 		vault common.Address,
-	) error
+		options *SubmissionOptions,
+	) (*big.Int, error)
 
 	// GetDepositRequest gets the on-chain deposit request for the given
 	// funding transaction hash and output index.The returned values represent:
@@ -58,22 +72,26 @@ type Chain interface {
 	) (*tbtc.RedemptionRequest, bool, error)
 
 	// SubmitRedemptionProofWithReimbursement submits the redemption proof
-	// via MaintainerProxy. The caller is reimbursed.
+	// via MaintainerProxy. The caller is reimbursed. The returned value is the
+	// maximum fee of the submission transaction.
 	SubmitRedemptionProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
 		walletPublicKeyHash [20]byte,
-	) error
+		options *SubmissionOptions,
+	) (*big.Int, error)
 
 	// SubmitMovingFundsProofWithReimbursement submits the moving funds proof
-	// via MaintainerProxy. The caller is reimbursed.
+	// via MaintainerProxy. The caller is reimbursed. The returned value is the
+	// maximum fee of the submission transaction.
 	SubmitMovingFundsProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
 		walletPublicKeyHash [20]byte,
-	) error
+		options *SubmissionOptions,
+	) (*big.Int, error)
 
 	// PastDepositRevealedEvents fetches past deposit reveal events according
 	// to the provided filter or unfiltered if the filter is nil. Returned
@@ -76,7 +76,8 @@ func (lc *localChain) SubmitDepositSweepProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	vault common.Address,
-) error {
+	options *SubmissionOptions,
+) (*big.Int, error) {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
 
@@ -90,7 +91,7 @@ func (lc *localChain) SubmitDepositSweepProofWithReimbursement(
 		},
 	)
 
-	return nil
+	return big.NewInt(0), nil
 }
 
 func (lc *localChain) getSubmittedDepositSweepProofs() []*submittedDepositSweepProof {
@@ -251,7 +252,8 @@ func (lc *localChain) SubmitRedemptionProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
-) error {
+	options *SubmissionOptions,
+) (*big.Int, error) {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
 
@@ -265,7 +267,7 @@ func (lc *localChain) SubmitRedemptionProofWithReimbursement(
 		},
 	)
 
-	return nil
+	return big.NewInt(0), nil
 }
 
 func (lc *localChain) getSubmittedRedemptionProofs() []*submittedRedemptionProof {
@@ -280,7 +282,8 @@ func (lc *localChain) SubmitMovingFundsProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
-) error {
+	options *SubmissionOptions,
+) (*big.Int, error) {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
 
@@ -294,7 +297,7 @@ func (lc *localChain) SubmitMovingFundsProofWithReimbursement(
 		},
 	)
 
-	return nil
+	return big.NewInt(0), nil
 }
 
 func (lc *localChain) getSubmittedMovingFundsProofs() []*submittedMovingFundsProof {
@@ -0,0 +1,111 @@
+package spv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+// checkpointDirectory is the name of the directory the checkpoint store keeps
+// its entries in.
+const checkpointDirectory = "checkpoints"
+
+// checkpointStore keeps track of the latest host chain block processed by
+// the SPV maintainer for each proof type. The checkpoints are persisted so a
+// restarted maintainer resumes scanning from where it stopped instead of
+// looking back from the current block only.
+type checkpointStore struct {
+	mutex sync.Mutex
+
+	persistence persistence.BasicHandle
+	checkpoints map[string]uint64
+}
+
+// newCheckpointStore creates a new checkpoint store backed by the given
+// persistence handle and loads all checkpoints persisted so far. Checkpoints
+// that cannot be read are logged and skipped.
+func newCheckpointStore(persistence persistence.BasicHandle) *checkpointStore {
+	store := &checkpointStore{
+		persistence: persistence,
+		checkpoints: make(map[string]uint64),
+	}
+
+	store.load()
+
+	return store
+}
+
+func (cs *checkpointStore) load() {
+	persistenceutil.ReadAll(
+		cs.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != checkpointDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read checkpoint from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			if len(content) != 8 {
+				logger.Errorf(
+					"could not parse checkpoint from file [%s]: "+
+						"wrong content length [%v]",
+					descriptor.Name(),
+					len(content),
+				)
+				return
+			}
+
+			cs.checkpoints[descriptor.Name()] = binary.BigEndian.Uint64(content)
+		},
+		func(err error) {
+			logger.Errorf("could not load checkpoints from disk: [%v]", err)
+		},
+	)
+}
+
+// get returns the latest processed block for the given proof type. The
+// second return value is false if no checkpoint was recorded so far.
+func (cs *checkpointStore) get(proofType string) (uint64, bool) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	block, ok := cs.checkpoints[proofType]
+	return block, ok
+}
+
+// set records the given block as the latest processed block for the given
+// proof type.
+func (cs *checkpointStore) set(proofType string, block uint64) error {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	content := make([]byte, 8)
+	binary.BigEndian.PutUint64(content, block)
+
+	if err := cs.persistence.Save(
+		content,
+		checkpointDirectory,
+		proofType,
+	); err != nil {
+		return fmt.Errorf(
+			"cannot save checkpoint for [%s]: [%v]",
+			proofType,
+			err,
+		)
+	}
+
+	cs.checkpoints[proofType] = block
+
+	return nil
+}
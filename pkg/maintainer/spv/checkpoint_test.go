@@ -0,0 +1,190 @@
+package spv
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestCheckpointStore(t *testing.T) {
+	handle := newMockPersistenceHandle()
+
+	store := newCheckpointStore(handle)
+
+	_, ok := store.get(tbtc.ActionDepositSweep.String())
+	testutils.AssertBoolsEqual(t, "checkpoint exists", false, ok)
+
+	err := store.set(tbtc.ActionDepositSweep.String(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the store to make sure the checkpoint is persisted.
+	store = newCheckpointStore(handle)
+
+	block, ok := store.get(tbtc.ActionDepositSweep.String())
+	testutils.AssertBoolsEqual(t, "checkpoint exists", true, ok)
+	testutils.AssertUintsEqual(t, "checkpoint", 1000, block)
+
+	_, ok = store.get(tbtc.ActionRedemption.String())
+	testutils.AssertBoolsEqual(t, "other checkpoint exists", false, ok)
+}
+
+func TestSpvMaintainer_ScanStartBlock(t *testing.T) {
+	tests := map[string]struct {
+		checkpoint         uint64
+		rescanFromBlock    uint64
+		rescanned          bool
+		currentBlock       uint64
+		expectedStartBlock uint64
+	}{
+		"no checkpoint": {
+			currentBlock:       1000,
+			expectedStartBlock: 900,
+		},
+		"checkpoint behind current block": {
+			checkpoint:         800,
+			currentBlock:       1000,
+			expectedStartBlock: 700,
+		},
+		"checkpoint ahead of current block": {
+			checkpoint:         1200,
+			currentBlock:       1000,
+			expectedStartBlock: 900,
+		},
+		"history depth exceeds reference block": {
+			currentBlock:       50,
+			expectedStartBlock: 0,
+		},
+		"rescan requested": {
+			checkpoint:         800,
+			rescanFromBlock:    10,
+			currentBlock:       1000,
+			expectedStartBlock: 10,
+		},
+		"rescan already done": {
+			checkpoint:         800,
+			rescanFromBlock:    10,
+			rescanned:          true,
+			currentBlock:       1000,
+			expectedStartBlock: 700,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			checkpoints := newCheckpointStore(newMockPersistenceHandle())
+			if test.checkpoint != 0 {
+				err := checkpoints.set(
+					tbtc.ActionRedemption.String(),
+					test.checkpoint,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			spvMaintainer := &spvMaintainer{
+				config: Config{
+					HistoryDepth:    100,
+					RescanFromBlock: test.rescanFromBlock,
+				},
+				checkpoints: checkpoints,
+				rescanned: map[tbtc.WalletActionType]bool{
+					tbtc.ActionRedemption: test.rescanned,
+				},
+			}
+
+			startBlock := spvMaintainer.scanStartBlock(
+				tbtc.ActionRedemption,
+				test.currentBlock,
+			)
+
+			testutils.AssertUintsEqual(
+				t,
+				"start block",
+				test.expectedStartBlock,
+				startBlock,
+			)
+		})
+	}
+}
+
+type mockPersistenceHandle struct {
+	mutex sync.Mutex
+	data  map[string]*mockDescriptor
+}
+
+func newMockPersistenceHandle() *mockPersistenceHandle {
+	return &mockPersistenceHandle{
+		data: make(map[string]*mockDescriptor),
+	}
+}
+
+func (mph *mockPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	mph.data[directory+"/"+name] = &mockDescriptor{
+		name:      name,
+		directory: directory,
+		content:   data,
+	}
+
+	return nil
+}
+
+func (mph *mockPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	outputData := make(chan persistence.DataDescriptor, len(mph.data))
+	outputErrors := make(chan error)
+
+	for _, descriptor := range mph.data {
+		outputData <- descriptor
+	}
+
+	close(outputData)
+	close(outputErrors)
+
+	return outputData, outputErrors
+}
+
+func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	delete(mph.data, directory+"/"+name)
+
+	return nil
+}
+
+type mockDescriptor struct {
+	name      string
+	directory string
+	content   []byte
+}
+
+func (md *mockDescriptor) Name() string {
+	return md.name
+}
+
+func (md *mockDescriptor) Directory() string {
+	return md.directory
+}
+
+func (md *mockDescriptor) Content() ([]byte, error) {
+	return md.content, nil
+}
@@ -1,7 +1,10 @@
 package spv
 
 import (
+	"math/big"
 	"time"
+
+	"github.com/keep-network/keep-common/pkg/chain/ethereum"
 )
 
 const (
@@ -22,6 +25,10 @@ const (
 	// safe than sorry.
 	DefaultTransactionLimit = 20
 
+	// DefaultProofWorkers is the default value for the number of transaction
+	// proofs assembled and submitted concurrently.
+	DefaultProofWorkers = 4
+
 	// DefaultRestartBackoffTime is the default value for restart back-off time.
 	DefaultRestartBackoffTime = 30 * time.Minute
 
@@ -41,22 +48,63 @@ type Config struct {
 	// far into the past the system will consider events for processing. This
 	// value must not be too high so that the event lookup is efficient. At the
 	// same time, this value can not be too low to make sure all performed and
-	// not yet proven transactions can be found.
+	// not yet proven transactions can be found. If not set,
+	// DefaultHistoryDepth is used.
 	HistoryDepth uint64
 
+	// RescanFromBlock forces the maintainer to search for wallet-related
+	// events starting from the given block during its first round, ignoring
+	// the persisted checkpoints. Subsequent rounds use the checkpoints again.
+	// The rescan is not performed if the value is zero.
+	RescanFromBlock uint64
+
 	// TransactionLimit sets the maximum number of confirmed transactions
 	// returned when getting transactions for a public key hash. Once the
 	// maintainer establishes the list of wallets, it needs to check Bitcoin
 	// transactions executed by each wallet. Then, it tries to find the
 	// transactions matching the given proposal type. For example, if set
-	// to `20`, only the latest twenty transactions will be returned. This
+	// to `20`, only the latest twenty transactions will be returned.
+	// Transactions known to be already proven do not count towards the
+	// limit as the wallet history is walked page by page. This
 	// value must not be too high so that the transaction lookup is efficient.
 	// At the same time, this value can not be too low to make sure the
 	// performed proposal's transaction can be found in case the wallet decided
 	// to execute some other Bitcoin transaction after the yet-not-proven
-	// transaction.
+	// transaction. If not set, DefaultTransactionLimit is used.
 	TransactionLimit int
 
+	// ProofWorkers is the maximum number of transaction proofs assembled and
+	// submitted concurrently. A failure to prove one transaction does not
+	// prevent other transactions from being proven in the same round. If not
+	// set, DefaultProofWorkers is used.
+	ProofWorkers int
+
+	// FallbackElectrumURLs are URLs of Electrum servers used to assemble
+	// transaction proofs when the assembly using the primary Bitcoin backend
+	// fails, for example due to missing headers or rate limiting. The servers
+	// are tried in the given order. Other properties of the Electrum
+	// connections are the same as for the primary Bitcoin backend.
+	FallbackElectrumURLs []string
+
+	// GasTipCap is the maximum priority fee per gas the maintainer is willing
+	// to pay for a proof submission transaction. If not set, the value is
+	// estimated.
+	GasTipCap ethereum.Wei
+
+	// GasFeeCap is the maximum total fee per gas the maintainer is willing to
+	// pay for a proof submission transaction. If not set, the value is
+	// estimated.
+	GasFeeCap ethereum.Wei
+
+	// MaxDailyGasSpend caps the total fees of proof submissions made within
+	// the last 24 hours. Although proof submissions are reimbursed, the
+	// maintainer pays for them upfront. The cap bounds the amount of funds
+	// the maintainer needs to keep aside. Once the cap is reached, proofs are
+	// deferred. The fee of a submission is accounted as its gas limit
+	// multiplied by its gas fee cap, i.e. the maximum possible fee. If not
+	// set, the spending is not capped.
+	MaxDailyGasSpend ethereum.Wei
+
 	// RestartBackoffTime is a restart backoff which should be applied when the
 	// SPV maintainer is restarted. It helps to avoid being flooded with error
 	// logs in case of a permanent error in the SPV maintainer.
@@ -65,4 +113,38 @@ type Config struct {
 	// IdleBackoffTime is a wait time which should be applied when there are no
 	// more transaction proofs to submit.
 	IdleBackoffTime time.Duration
+
+	// Networks holds properties set for particular client networks, keyed
+	// by the network name, e.g. `mainnet` or `testnet`. Properties set for
+	// the network the client is running on override the ones set above.
+	Networks map[string]NetworkConfig
+}
+
+// NetworkConfig holds properties of the SPV maintainer that may differ
+// between client networks. Unset properties are not overridden.
+type NetworkConfig struct {
+	// HistoryDepth overrides Config.HistoryDepth.
+	HistoryDepth uint64
+
+	// TransactionLimit overrides Config.TransactionLimit.
+	TransactionLimit int
+}
+
+// submissionOptions returns the fee parameters of proof submission
+// transactions.
+func (c Config) submissionOptions() *SubmissionOptions {
+	return &SubmissionOptions{
+		GasTipCap: weiValue(c.GasTipCap),
+		GasFeeCap: weiValue(c.GasFeeCap),
+	}
+}
+
+// weiValue returns the value of the given Wei or nil if the value is not set
+// or is zero.
+func weiValue(wei ethereum.Wei) *big.Int {
+	if wei.Int == nil || wei.Sign() == 0 {
+		return nil
+	}
+
+	return wei.Int
 }
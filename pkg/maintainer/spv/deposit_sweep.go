@@ -2,6 +2,7 @@ package spv
 
 import (
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/keep-network/keep-core/pkg/tbtc"
@@ -14,16 +15,19 @@ import (
 
 // SubmitDepositSweepProof prepares deposit sweep proof for the given
 // transaction and submits it to the on-chain contract. If the number of required
-// confirmations is `0`, an error is returned.
+// confirmations is `0`, an error is returned. The returned value is the maximum
+// fee of the submission transaction.
 func SubmitDepositSweepProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
-) error {
+) (*big.Int, error) {
 	return submitDepositSweepProof(
 		transactionHash,
 		requiredConfirmations,
+		options,
 		btcChain,
 		spvChain,
 		bitcoin.AssembleSpvProof,
@@ -33,12 +37,13 @@ func SubmitDepositSweepProof(
 func submitDepositSweepProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 	spvProofAssembler spvProofAssembler,
-) error {
+) (*big.Int, error) {
 	if requiredConfirmations == 0 {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"provided required confirmations count must be greater than 0",
 		)
 	}
@@ -49,7 +54,7 @@ func submitDepositSweepProof(
 		btcChain,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"failed to assemble transaction spv proof: [%v]",
 			err,
 		)
@@ -61,25 +66,68 @@ func submitDepositSweepProof(
 		transaction,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"error while parsing transaction inputs: [%v]",
 			err,
 		)
 	}
 
-	if err := spvChain.SubmitDepositSweepProofWithReimbursement(
+	maxFee, err := spvChain.SubmitDepositSweepProofWithReimbursement(
 		transaction,
 		proof,
 		mainUTXO,
 		vault,
-	); err != nil {
-		return fmt.Errorf(
+		options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
 			"failed to submit deposit sweep proof with reimbursement: [%v]",
 			err,
 		)
 	}
 
-	return nil
+	return maxFee, nil
+}
+
+// isDepositSweepProofRequired checks whether the proof of the given deposit
+// sweep transaction would still be accepted by the host chain. The wallet
+// must be `Live` or `MovingFunds` and the main UTXO spent by the transaction
+// must still be the wallet's current main UTXO.
+func isDepositSweepProofRequired(
+	transaction *bitcoin.Transaction,
+	btcChain bitcoin.Chain,
+	spvChain Chain,
+) (bool, error) {
+	mainUTXO, _, err := parseDepositSweepTransactionInputs(
+		btcChain,
+		spvChain,
+		transaction,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"error while parsing transaction inputs: [%v]",
+			err,
+		)
+	}
+
+	// The deposit sweep transaction transfers funds to the wallet so its
+	// only output is locked with the wallet public key hash.
+	walletPublicKeyHash, err := bitcoin.ExtractPublicKeyHash(
+		transaction.Outputs[0].PublicKeyScript,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"cannot extract wallet public key hash: [%v]",
+			err,
+		)
+	}
+
+	return isWalletReadyForProof(
+		walletPublicKeyHash,
+		mainUTXO,
+		[]tbtc.WalletState{tbtc.StateLive, tbtc.StateMovingFunds},
+		spvChain,
+	)
 }
 
 // parseDepositSweepTransactionInputs parses the transaction's inputs and
@@ -223,28 +271,15 @@ func convertVaultAddress(vault *chain.Address) common.Address {
 }
 
 func getUnprovenDepositSweepTransactions(
-	historyDepth uint64,
+	startBlock uint64,
 	transactionLimit int,
+	provenTransactions *provenTransactionsCache,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 ) (
 	[]*bitcoin.Transaction,
 	error,
 ) {
-	blockCounter, err := spvChain.BlockCounter()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get block counter: [%v]", err)
-	}
-
-	currentBlock, err := blockCounter.CurrentBlock()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block: [%v]", err)
-	}
-
-	// Calculate the starting block of the range in which the events will be
-	// searched for.
-	startBlock := currentBlock - historyDepth
-
 	events, err :=
 		spvChain.PastDepositRevealedEvents(
 			&tbtc.DepositRevealedEventFilter{
@@ -285,9 +320,11 @@ func getUnprovenDepositSweepTransactions(
 			continue
 		}
 
-		walletTransactions, err := btcChain.GetTransactionsForPublicKeyHash(
+		walletTransactions, err := getUnprovenWalletTransactions(
+			btcChain,
 			walletPublicKeyHash,
 			transactionLimit,
+			provenTransactions,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -90,9 +90,10 @@ func TestSubmitDepositSweepProof(t *testing.T) {
 		return nil, nil, fmt.Errorf("error while assembling spv proof")
 	}
 
-	err := submitDepositSweepProof(
+	_, err := submitDepositSweepProof(
 		depositSweepTransaction.Hash(),
 		requiredConfirmations,
+		nil,
 		btcChain,
 		spvChain,
 		mockSpvProofAssembler,
@@ -349,8 +350,9 @@ func TestGetUnprovenDepositSweepTransactions(t *testing.T) {
 	}
 
 	transactions, err := getUnprovenDepositSweepTransactions(
-		historyDepth,
+		currentBlock-historyDepth,
 		transactionLimit,
+		newProvenTransactionsCache(newMockPersistenceHandle()),
 		btcChain,
 		spvChain,
 	)
@@ -0,0 +1,124 @@
+package spv
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+// Reasons of failures to prove transactions exposed by metrics.
+const (
+	failureReasonProofInfo   = "proof_info"
+	failureReasonRelayCheck  = "relay_check"
+	failureReasonWalletCheck = "wallet_check"
+	failureReasonSubmission  = "submission"
+)
+
+var failureReasons = []string{
+	failureReasonProofInfo,
+	failureReasonRelayCheck,
+	failureReasonWalletCheck,
+	failureReasonSubmission,
+}
+
+// metrics holds the values of metrics exposed by the SPV maintainer.
+// Counters are cumulative since the maintainer start.
+type metrics struct {
+	mutex sync.Mutex
+
+	unprovenTransactionsCount uint64
+	submittedProofsCount      uint64
+	failuresCount             map[string]uint64
+	proofLatency              time.Duration
+	relayLag                  uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		failuresCount: make(map[string]uint64),
+	}
+}
+
+func (m *metrics) recordUnprovenTransactions(count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.unprovenTransactionsCount += uint64(count)
+}
+
+func (m *metrics) recordSubmittedProof(latency time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.submittedProofsCount++
+	m.proofLatency = latency
+}
+
+func (m *metrics) recordFailure(reason string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.failuresCount[reason]++
+}
+
+func (m *metrics) recordRelayLag(relayLag uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.relayLag = relayLag
+}
+
+// source returns a metric source reading the value returned by the given
+// function while holding the metrics lock.
+func (m *metrics) source(valueFn func() float64) clientinfo.Source {
+	return func() float64 {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		return valueFn()
+	}
+}
+
+// register starts observing the SPV maintainer metrics using the given
+// client info registry. The gas spending metrics are read from the given
+// spending tracker.
+func (m *metrics) register(
+	clientInfo *clientinfo.Registry,
+	spending *spendingTracker,
+) {
+	sources := map[string]clientinfo.Source{
+		"unproven_transactions_count": m.source(func() float64 {
+			return float64(m.unprovenTransactionsCount)
+		}),
+		"submitted_proofs_count": m.source(func() float64 {
+			return float64(m.submittedProofsCount)
+		}),
+		"proof_latency_seconds": m.source(func() float64 {
+			return m.proofLatency.Seconds()
+		}),
+		"relay_lag_epochs": m.source(func() float64 {
+			return float64(m.relayLag)
+		}),
+		"gas_spent_wei": func() float64 {
+			value, _ := new(big.Float).SetInt(spending.spent()).Float64()
+			return value
+		},
+		"gas_spent_last_day_wei": func() float64 {
+			value, _ := new(big.Float).SetInt(
+				spending.spentInWindow(time.Now()),
+			).Float64()
+			return value
+		},
+	}
+
+	for _, reason := range failureReasons {
+		reason := reason
+		sources["failures_"+reason+"_count"] = m.source(func() float64 {
+			return float64(m.failuresCount[reason])
+		})
+	}
+
+	clientInfo.ObserveApplicationSource("spv", sources)
+}
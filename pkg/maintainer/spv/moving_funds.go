@@ -3,6 +3,7 @@ package spv
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/tbtc"
@@ -10,16 +11,19 @@ import (
 
 // SubmitMovingFundsProof prepares moving funds proof for the given
 // transaction and submits it to the on-chain contract. If the number of
-// required confirmations is `0`, an error is returned.
+// required confirmations is `0`, an error is returned. The returned value is
+// the maximum fee of the submission transaction.
 func SubmitMovingFundsProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
-) error {
+) (*big.Int, error) {
 	return submitMovingFundsProof(
 		transactionHash,
 		requiredConfirmations,
+		options,
 		btcChain,
 		spvChain,
 		bitcoin.AssembleSpvProof,
@@ -29,12 +33,13 @@ func SubmitMovingFundsProof(
 func submitMovingFundsProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 	spvProofAssembler spvProofAssembler,
-) error {
+) (*big.Int, error) {
 	if requiredConfirmations == 0 {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"provided required confirmations count must be greater than 0",
 		)
 	}
@@ -45,7 +50,7 @@ func submitMovingFundsProof(
 		btcChain,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"failed to assemble transaction spv proof: [%v]",
 			err,
 		)
@@ -56,25 +61,55 @@ func submitMovingFundsProof(
 		transaction,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"error while parsing transaction inputs: [%v]",
 			err,
 		)
 	}
 
-	if err := spvChain.SubmitMovingFundsProofWithReimbursement(
+	maxFee, err := spvChain.SubmitMovingFundsProofWithReimbursement(
 		transaction,
 		proof,
 		mainUTXO,
 		walletPublicKeyHash,
-	); err != nil {
-		return fmt.Errorf(
+		options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
 			"failed to submit moving funds proof with reimbursement: [%v]",
 			err,
 		)
 	}
 
-	return nil
+	return maxFee, nil
+}
+
+// isMovingFundsProofRequired checks whether the proof of the given moving
+// funds transaction would still be accepted by the host chain. The wallet
+// must be `MovingFunds` and the main UTXO spent by the transaction must
+// still be the wallet's current main UTXO.
+func isMovingFundsProofRequired(
+	transaction *bitcoin.Transaction,
+	btcChain bitcoin.Chain,
+	spvChain Chain,
+) (bool, error) {
+	mainUTXO, walletPublicKeyHash, err := parseMovingFundsTransactionInput(
+		btcChain,
+		transaction,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"error while parsing transaction inputs: [%v]",
+			err,
+		)
+	}
+
+	return isWalletReadyForProof(
+		walletPublicKeyHash,
+		mainUTXO,
+		[]tbtc.WalletState{tbtc.StateMovingFunds},
+		spvChain,
+	)
 }
 
 // parseMovingFundsTransactionInput parses the transaction's input and
@@ -125,28 +160,15 @@ func parseMovingFundsTransactionInput(
 }
 
 func getUnprovenMovingFundsTransactions(
-	historyDepth uint64,
+	startBlock uint64,
 	transactionLimit int,
+	provenTransactions *provenTransactionsCache,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 ) (
 	[]*bitcoin.Transaction,
 	error,
 ) {
-	blockCounter, err := spvChain.BlockCounter()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get block counter: [%v]", err)
-	}
-
-	currentBlock, err := blockCounter.CurrentBlock()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block: [%v]", err)
-	}
-
-	// Calculate the starting block of the range in which the events will be
-	// searched for.
-	startBlock := currentBlock - historyDepth
-
 	// The `MovingFundsCommitmentSubmitted` event can only be emitted once for
 	// a given wallet. Therefore there will always be only one event for a wallet.
 	// We do not have to worry about duplicate events for the same wallet.
@@ -197,9 +219,11 @@ func getUnprovenMovingFundsTransactions(
 		// source wallet.
 		targetWalletPublicKeyHash := targetWallets[0]
 
-		walletTransactions, err := btcChain.GetTransactionsForPublicKeyHash(
+		walletTransactions, err := getUnprovenWalletTransactions(
+			btcChain,
 			targetWalletPublicKeyHash,
 			transactionLimit,
+			provenTransactions,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(
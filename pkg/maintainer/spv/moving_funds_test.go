@@ -70,9 +70,10 @@ func TestSubmitMovingFundsProof(t *testing.T) {
 		return nil, nil, fmt.Errorf("error while assembling spv proof")
 	}
 
-	err = submitMovingFundsProof(
+	_, err = submitMovingFundsProof(
 		movingFundsTransaction.Hash(),
 		requiredConfirmations,
+		nil,
 		btcChain,
 		spvChain,
 		mockSpvProofAssembler,
@@ -266,8 +267,9 @@ func TestGetUnprovenMovingFundsTransactions(t *testing.T) {
 	}
 
 	transactions, err := getUnprovenMovingFundsTransactions(
-		historyDepth,
+		currentBlock-historyDepth,
 		transactionLimit,
+		newProvenTransactionsCache(newMockPersistenceHandle()),
 		btcChain,
 		spvChain,
 	)
@@ -0,0 +1,216 @@
+package spv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// provenTransactionsDirectory is the name of the directory the proven
+// transactions cache keeps its entries in.
+const provenTransactionsDirectory = "proven"
+
+// provenTransactionsRetention is the period after which transactions are
+// pruned from the proven transactions cache. Transactions older than that
+// are unlikely to be returned by the unproven transactions getters again.
+const provenTransactionsRetention = 7 * 24 * time.Hour
+
+// provenTransactionsCache keeps track of transactions whose proofs are known
+// to be no longer required by the host chain, e.g. because they were already
+// accepted. Transactions are not cached upon proof submission as the
+// submission may still fail on-chain. Instead, they are held as submitted
+// until the host chain confirms the proof is no longer required. The cache is
+// persisted so that subsequent rounds, including rounds of a restarted
+// maintainer, skip checking those transactions against the Bitcoin and host
+// chains.
+type provenTransactionsCache struct {
+	mutex sync.Mutex
+
+	persistence  persistence.BasicHandle
+	transactions map[bitcoin.Hash]time.Time
+
+	// submitted holds transactions whose proofs were submitted but not yet
+	// confirmed by the host chain, grouped by the proof type. They are kept
+	// in memory only; after a restart, they are found by the unproven
+	// transactions getters again.
+	submitted map[tbtc.WalletActionType]map[bitcoin.Hash]*bitcoin.Transaction
+}
+
+// newProvenTransactionsCache creates a new proven transactions cache backed
+// by the given persistence handle and loads all entries persisted so far.
+// Entries that cannot be read are logged and skipped.
+func newProvenTransactionsCache(
+	persistence persistence.BasicHandle,
+) *provenTransactionsCache {
+	cache := &provenTransactionsCache{
+		persistence:  persistence,
+		transactions: make(map[bitcoin.Hash]time.Time),
+		submitted: make(
+			map[tbtc.WalletActionType]map[bitcoin.Hash]*bitcoin.Transaction,
+		),
+	}
+
+	cache.load()
+
+	return cache
+}
+
+func (ptc *provenTransactionsCache) load() {
+	persistenceutil.ReadAll(
+		ptc.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != provenTransactionsDirectory {
+				return
+			}
+
+			transactionHash, err := bitcoin.NewHashFromString(
+				descriptor.Name(),
+				bitcoin.InternalByteOrder,
+			)
+			if err != nil {
+				logger.Errorf(
+					"could not parse proven transaction hash from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read proven transaction from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			if len(content) != 8 {
+				logger.Errorf(
+					"could not parse proven transaction from file [%s]: "+
+						"wrong content length [%v]",
+					descriptor.Name(),
+					len(content),
+				)
+				return
+			}
+
+			ptc.transactions[transactionHash] = time.Unix(
+				int64(binary.BigEndian.Uint64(content)),
+				0,
+			)
+		},
+		func(err error) {
+			logger.Errorf(
+				"could not load proven transactions from disk: [%v]",
+				err,
+			)
+		},
+	)
+}
+
+// contains returns true if the given transaction is known to be proven.
+func (ptc *provenTransactionsCache) contains(transactionHash bitcoin.Hash) bool {
+	ptc.mutex.Lock()
+	defer ptc.mutex.Unlock()
+
+	_, ok := ptc.transactions[transactionHash]
+	return ok
+}
+
+// add records the given transaction as proven at the given time.
+func (ptc *provenTransactionsCache) add(
+	transactionHash bitcoin.Hash,
+	provenAt time.Time,
+) error {
+	ptc.mutex.Lock()
+	defer ptc.mutex.Unlock()
+
+	content := make([]byte, 8)
+	binary.BigEndian.PutUint64(content, uint64(provenAt.Unix()))
+
+	if err := ptc.persistence.Save(
+		content,
+		provenTransactionsDirectory,
+		transactionHash.Hex(bitcoin.InternalByteOrder),
+	); err != nil {
+		return fmt.Errorf(
+			"cannot save proven transaction [%s]: [%v]",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			err,
+		)
+	}
+
+	ptc.transactions[transactionHash] = provenAt
+
+	return nil
+}
+
+// addSubmitted records the given transaction as one whose proof of the given
+// type was submitted but not yet confirmed by the host chain.
+func (ptc *provenTransactionsCache) addSubmitted(
+	proofType tbtc.WalletActionType,
+	transaction *bitcoin.Transaction,
+) {
+	ptc.mutex.Lock()
+	defer ptc.mutex.Unlock()
+
+	if _, ok := ptc.submitted[proofType]; !ok {
+		ptc.submitted[proofType] = make(map[bitcoin.Hash]*bitcoin.Transaction)
+	}
+
+	ptc.submitted[proofType][transaction.Hash()] = transaction
+}
+
+// takeSubmitted returns and forgets all transactions whose proofs of the given
+// type were submitted but not yet confirmed by the host chain.
+func (ptc *provenTransactionsCache) takeSubmitted(
+	proofType tbtc.WalletActionType,
+) []*bitcoin.Transaction {
+	ptc.mutex.Lock()
+	defer ptc.mutex.Unlock()
+
+	transactions := make([]*bitcoin.Transaction, 0, len(ptc.submitted[proofType]))
+	for _, transaction := range ptc.submitted[proofType] {
+		transactions = append(transactions, transaction)
+	}
+
+	delete(ptc.submitted, proofType)
+
+	return transactions
+}
+
+// prune removes transactions that were proven before the given time.
+func (ptc *provenTransactionsCache) prune(provenBefore time.Time) error {
+	ptc.mutex.Lock()
+	defer ptc.mutex.Unlock()
+
+	for transactionHash, provenAt := range ptc.transactions {
+		if !provenAt.Before(provenBefore) {
+			continue
+		}
+
+		if err := ptc.persistence.Delete(
+			provenTransactionsDirectory,
+			transactionHash.Hex(bitcoin.InternalByteOrder),
+		); err != nil {
+			return fmt.Errorf(
+				"cannot delete proven transaction [%s]: [%v]",
+				transactionHash.Hex(bitcoin.ReversedByteOrder),
+				err,
+			)
+		}
+
+		delete(ptc.transactions, transactionHash)
+	}
+
+	return nil
+}
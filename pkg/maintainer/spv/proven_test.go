@@ -0,0 +1,136 @@
+package spv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestProvenTransactionsCache(t *testing.T) {
+	handle := newMockPersistenceHandle()
+
+	cache := newProvenTransactionsCache(handle)
+
+	oldTransactionHash := bitcoin.Hash{1}
+	recentTransactionHash := bitcoin.Hash{2}
+
+	testutils.AssertBoolsEqual(
+		t,
+		"contains old transaction",
+		false,
+		cache.contains(oldTransactionHash),
+	)
+
+	now := time.Unix(1700000000, 0)
+
+	err := cache.add(oldTransactionHash, now.Add(-10*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cache.add(recentTransactionHash, now.Add(-1*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the cache to make sure the transactions are persisted.
+	cache = newProvenTransactionsCache(handle)
+
+	testutils.AssertBoolsEqual(
+		t,
+		"contains old transaction",
+		true,
+		cache.contains(oldTransactionHash),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"contains recent transaction",
+		true,
+		cache.contains(recentTransactionHash),
+	)
+
+	err = cache.prune(now.Add(-5 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the cache to make sure the pruned transaction is deleted.
+	cache = newProvenTransactionsCache(handle)
+
+	testutils.AssertBoolsEqual(
+		t,
+		"contains old transaction",
+		false,
+		cache.contains(oldTransactionHash),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"contains recent transaction",
+		true,
+		cache.contains(recentTransactionHash),
+	)
+}
+
+func TestSpvMaintainer_ConfirmSubmittedProofs(t *testing.T) {
+	acceptedTransaction := &bitcoin.Transaction{Version: 1}
+	pendingTransaction := &bitcoin.Transaction{Version: 2}
+
+	spvMaintainer := &spvMaintainer{
+		provenTransactions: newProvenTransactionsCache(
+			newMockPersistenceHandle(),
+		),
+	}
+
+	spvMaintainer.provenTransactions.addSubmitted(
+		tbtc.ActionRedemption,
+		acceptedTransaction,
+	)
+	spvMaintainer.provenTransactions.addSubmitted(
+		tbtc.ActionRedemption,
+		pendingTransaction,
+	)
+
+	proofRequirementChecker := func(
+		transaction *bitcoin.Transaction,
+		btcChain bitcoin.Chain,
+		spvChain Chain,
+	) (bool, error) {
+		return transaction.Hash() != acceptedTransaction.Hash(), nil
+	}
+
+	// Submitted proofs are not cached as proven before the host chain
+	// confirms them.
+	testutils.AssertBoolsEqual(
+		t,
+		"contains accepted transaction",
+		false,
+		spvMaintainer.provenTransactions.contains(acceptedTransaction.Hash()),
+	)
+
+	spvMaintainer.confirmSubmittedProofs(
+		tbtc.ActionRedemption,
+		proofRequirementChecker,
+	)
+
+	testutils.AssertBoolsEqual(
+		t,
+		"contains accepted transaction",
+		true,
+		spvMaintainer.provenTransactions.contains(acceptedTransaction.Hash()),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"contains pending transaction",
+		false,
+		spvMaintainer.provenTransactions.contains(pendingTransaction.Hash()),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"submitted transactions",
+		0,
+		len(spvMaintainer.provenTransactions.takeSubmitted(tbtc.ActionRedemption)),
+	)
+}
@@ -5,20 +5,24 @@ import (
 	"fmt"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/tbtc"
+	"math/big"
 )
 
 // SubmitRedemptionProof prepares redemption proof for the given transaction
 // and submits it to the on-chain contract. If the number of required
-// confirmations is `0`, an error is returned.
+// confirmations is `0`, an error is returned. The returned value is the maximum
+// fee of the submission transaction.
 func SubmitRedemptionProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
-) error {
+) (*big.Int, error) {
 	return submitRedemptionProof(
 		transactionHash,
 		requiredConfirmations,
+		options,
 		btcChain,
 		spvChain,
 		bitcoin.AssembleSpvProof,
@@ -28,12 +32,13 @@ func SubmitRedemptionProof(
 func submitRedemptionProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 	spvProofAssembler spvProofAssembler,
-) error {
+) (*big.Int, error) {
 	if requiredConfirmations == 0 {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"provided required confirmations count must be greater than 0",
 		)
 	}
@@ -44,7 +49,7 @@ func submitRedemptionProof(
 		btcChain,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"failed to assemble transaction spv proof: [%v]",
 			err,
 		)
@@ -55,25 +60,55 @@ func submitRedemptionProof(
 		transaction,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"error while parsing transaction inputs: [%v]",
 			err,
 		)
 	}
 
-	if err := spvChain.SubmitRedemptionProofWithReimbursement(
+	maxFee, err := spvChain.SubmitRedemptionProofWithReimbursement(
 		transaction,
 		proof,
 		mainUTXO,
 		walletPublicKeyHash,
-	); err != nil {
-		return fmt.Errorf(
+		options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
 			"failed to submit redemption proof with reimbursement: [%v]",
 			err,
 		)
 	}
 
-	return nil
+	return maxFee, nil
+}
+
+// isRedemptionProofRequired checks whether the proof of the given redemption
+// transaction would still be accepted by the host chain. The wallet must be
+// `Live` or `MovingFunds` and the main UTXO spent by the transaction must
+// still be the wallet's current main UTXO.
+func isRedemptionProofRequired(
+	transaction *bitcoin.Transaction,
+	btcChain bitcoin.Chain,
+	spvChain Chain,
+) (bool, error) {
+	mainUTXO, walletPublicKeyHash, err := parseRedemptionTransactionInput(
+		btcChain,
+		transaction,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"error while parsing transaction inputs: [%v]",
+			err,
+		)
+	}
+
+	return isWalletReadyForProof(
+		walletPublicKeyHash,
+		mainUTXO,
+		[]tbtc.WalletState{tbtc.StateLive, tbtc.StateMovingFunds},
+		spvChain,
+	)
 }
 
 // parseRedemptionTransactionInput parses the transaction's input and
@@ -124,28 +159,15 @@ func parseRedemptionTransactionInput(
 }
 
 func getUnprovenRedemptionTransactions(
-	historyDepth uint64,
+	startBlock uint64,
 	transactionLimit int,
+	provenTransactions *provenTransactionsCache,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 ) (
 	[]*bitcoin.Transaction,
 	error,
 ) {
-	blockCounter, err := spvChain.BlockCounter()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get block counter: [%v]", err)
-	}
-
-	currentBlock, err := blockCounter.CurrentBlock()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block: [%v]", err)
-	}
-
-	// Calculate the starting block of the range in which the events will be
-	// searched for.
-	startBlock := currentBlock - historyDepth
-
 	events, err :=
 		spvChain.PastRedemptionRequestedEvents(
 			&tbtc.RedemptionRequestedEventFilter{
@@ -186,9 +208,11 @@ func getUnprovenRedemptionTransactions(
 			continue
 		}
 
-		walletTransactions, err := btcChain.GetTransactionsForPublicKeyHash(
+		walletTransactions, err := getUnprovenWalletTransactions(
+			btcChain,
 			walletPublicKeyHash,
 			transactionLimit,
+			provenTransactions,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(
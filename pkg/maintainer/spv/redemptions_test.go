@@ -72,9 +72,10 @@ func TestSubmitRedemptionProof(t *testing.T) {
 		return nil, nil, fmt.Errorf("error while assembling spv proof")
 	}
 
-	err = submitRedemptionProof(
+	_, err = submitRedemptionProof(
 		redemptionTransaction.Hash(),
 		requiredConfirmations,
+		nil,
 		btcChain,
 		spvChain,
 		mockSpvProofAssembler,
@@ -288,8 +289,9 @@ func TestGetUnprovenRedemptionTransactions(t *testing.T) {
 	}
 
 	transactions, err := getUnprovenRedemptionTransactions(
-		historyDepth,
+		currentBlock-historyDepth,
 		transactionLimit,
+		newProvenTransactionsCache(newMockPersistenceHandle()),
 		btcChain,
 		spvChain,
 	)
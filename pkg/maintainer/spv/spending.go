@@ -0,0 +1,245 @@
+package spv
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+// spendingsDirectory is the name of the directory the spending tracker keeps
+// its entries in.
+const spendingsDirectory = "spendings"
+
+// spendingWindow is the rolling window within which the proof submission
+// spending is capped.
+const spendingWindow = 24 * time.Hour
+
+// spending represents the maximum fee of a single proof submission.
+type spending struct {
+	amount *big.Int
+	at     time.Time
+}
+
+// spendingTracker keeps track of the maximum fees of proof submissions and
+// enforces the cap on the total fees within the rolling spending window.
+// The maximum fee of a submission is its gas limit multiplied by its gas fee
+// cap so the actual spending is never greater than the tracked one.
+//
+// The fee of a submission is known only once the submission is made so
+// a submission must be preceded by a reservation and followed by either
+// a commit or a release of that reservation. While the spending is capped,
+// only one reservation may be held at a time. This way, concurrent
+// submissions cannot pass the cap check together and exceed the cap.
+//
+// Spendings within the spending window are persisted so a restarted
+// maintainer does not start from zero.
+type spendingTracker struct {
+	// reservationMutex is held from a successful reservation until its
+	// commit or release.
+	reservationMutex sync.Mutex
+
+	mutex sync.Mutex
+
+	persistence persistence.BasicHandle
+
+	// limit is the cap on the total fees within the spending window. Nil
+	// means there is no cap.
+	limit *big.Int
+
+	windowSpendings []*spending
+	totalSpent      *big.Int
+}
+
+// newSpendingTracker creates a new spending tracker backed by the given
+// persistence handle and loads all spendings persisted so far. Spendings that
+// cannot be read are logged and skipped.
+func newSpendingTracker(
+	limit *big.Int,
+	persistence persistence.BasicHandle,
+) *spendingTracker {
+	tracker := &spendingTracker{
+		persistence: persistence,
+		limit:       limit,
+		totalSpent:  big.NewInt(0),
+	}
+
+	tracker.load()
+
+	return tracker
+}
+
+func (st *spendingTracker) load() {
+	persistenceutil.ReadAll(
+		st.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != spendingsDirectory {
+				return
+			}
+
+			at, err := strconv.ParseInt(descriptor.Name(), 10, 64)
+			if err != nil {
+				logger.Errorf(
+					"could not parse spending time from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read spending from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			st.windowSpendings = append(st.windowSpendings, &spending{
+				amount: new(big.Int).SetBytes(content),
+				at:     time.Unix(0, at),
+			})
+		},
+		func(err error) {
+			logger.Errorf(
+				"could not load spendings from disk: [%v]",
+				err,
+			)
+		},
+	)
+}
+
+// spendingReservation is a reservation of the spending for a single proof
+// submission. Exactly one of commit and release must be called on it.
+type spendingReservation struct {
+	tracker *spendingTracker
+	capped  bool
+}
+
+// reserve reserves the spending for a single proof submission made at the
+// given time. It returns false if the total fees within the spending window
+// ending at the given time reached the cap. If the spending is capped,
+// the call blocks until the reservation held by another submission is
+// committed or released.
+func (st *spendingTracker) reserve(now time.Time) (*spendingReservation, bool) {
+	if st.limit == nil {
+		return &spendingReservation{tracker: st, capped: false}, true
+	}
+
+	st.reservationMutex.Lock()
+
+	st.mutex.Lock()
+	canSpend := st.windowSpent(now).Cmp(st.limit) < 0
+	st.mutex.Unlock()
+
+	if !canSpend {
+		st.reservationMutex.Unlock()
+		return nil, false
+	}
+
+	return &spendingReservation{tracker: st, capped: true}, true
+}
+
+// commit records the given fee spent at the given time and frees the
+// reservation. The fee is recorded in memory even if it could not be
+// persisted.
+func (sr *spendingReservation) commit(amount *big.Int, at time.Time) error {
+	defer sr.release()
+
+	if amount == nil {
+		return nil
+	}
+
+	return sr.tracker.record(amount, at)
+}
+
+// release frees the reservation without recording any fee. It should be
+// called if the submission was not made.
+func (sr *spendingReservation) release() {
+	if sr.capped {
+		sr.capped = false
+		sr.tracker.reservationMutex.Unlock()
+	}
+}
+
+// record records the given fee spent at the given time and persists it.
+// Persisted spendings that left the spending window are deleted.
+func (st *spendingTracker) record(amount *big.Int, at time.Time) error {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.windowSpendings = append(
+		st.windowSpendings,
+		&spending{amount: new(big.Int).Set(amount), at: at},
+	)
+	st.totalSpent.Add(st.totalSpent, amount)
+
+	if err := st.persistence.Save(
+		amount.Bytes(),
+		spendingsDirectory,
+		strconv.FormatInt(at.UnixNano(), 10),
+	); err != nil {
+		return fmt.Errorf("cannot save spending: [%v]", err)
+	}
+
+	windowStart := at.Add(-spendingWindow)
+	remaining := st.windowSpendings[:0]
+	for i, spending := range st.windowSpendings {
+		if spending.at.After(windowStart) {
+			remaining = append(remaining, spending)
+			continue
+		}
+
+		if err := st.persistence.Delete(
+			spendingsDirectory,
+			strconv.FormatInt(spending.at.UnixNano(), 10),
+		); err != nil {
+			// Keep the spendings not processed yet so their files are
+			// deleted by a subsequent call.
+			st.windowSpendings = append(remaining, st.windowSpendings[i:]...)
+			return fmt.Errorf("cannot delete spending: [%v]", err)
+		}
+	}
+	st.windowSpendings = remaining
+
+	return nil
+}
+
+// spentInWindow returns the total fees within the spending window ending at
+// the given time.
+func (st *spendingTracker) spentInWindow(now time.Time) *big.Int {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	return st.windowSpent(now)
+}
+
+// spent returns the total fees since the tracker was created.
+func (st *spendingTracker) spent() *big.Int {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	return new(big.Int).Set(st.totalSpent)
+}
+
+// windowSpent returns the total fees within the spending window ending at
+// the given time. Spendings that left the window are dropped by record,
+// together with their persisted entries. Must be called with the mutex held.
+func (st *spendingTracker) windowSpent(now time.Time) *big.Int {
+	windowStart := now.Add(-spendingWindow)
+
+	total := big.NewInt(0)
+	for _, spending := range st.windowSpendings {
+		if spending.at.After(windowStart) {
+			total.Add(total, spending.amount)
+		}
+	}
+
+	return total
+}
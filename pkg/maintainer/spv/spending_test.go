@@ -0,0 +1,121 @@
+package spv
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestSpendingTracker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	persistenceHandle := newMockPersistenceHandle()
+	tracker := newSpendingTracker(big.NewInt(1000), persistenceHandle)
+
+	reserveAndCommit := func(amount int64, at time.Time) {
+		reservation, ok := tracker.reserve(at)
+		if !ok {
+			t.Fatal("expected successful reservation")
+		}
+		if err := reservation.commit(big.NewInt(amount), at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reserveAndCommit(600, now)
+	reserveAndCommit(400, now.Add(time.Hour))
+
+	_, ok := tracker.reserve(now.Add(time.Hour))
+	testutils.AssertBoolsEqual(t, "can reserve", false, ok)
+	testutils.AssertBigIntsEqual(
+		t,
+		"spent in window",
+		big.NewInt(1000),
+		tracker.spentInWindow(now.Add(time.Hour)),
+	)
+
+	// The spendings within the window survive a restart.
+	restarted := newSpendingTracker(big.NewInt(1000), persistenceHandle)
+	_, ok = restarted.reserve(now.Add(time.Hour))
+	testutils.AssertBoolsEqual(t, "can reserve after restart", false, ok)
+
+	// The first spending leaves the window.
+	later := now.Add(spendingWindow + time.Minute)
+	testutils.AssertBigIntsEqual(
+		t,
+		"spent in window",
+		big.NewInt(400),
+		tracker.spentInWindow(later),
+	)
+	reserveAndCommit(100, later)
+	testutils.AssertBigIntsEqual(
+		t,
+		"total spent",
+		big.NewInt(1100),
+		tracker.spent(),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"persisted spendings",
+		2,
+		len(persistenceHandle.data),
+	)
+}
+
+func TestSpendingTracker_Release(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tracker := newSpendingTracker(big.NewInt(1000), newMockPersistenceHandle())
+
+	reservation, ok := tracker.reserve(now)
+	if !ok {
+		t.Fatal("expected successful reservation")
+	}
+
+	// A concurrent reservation must wait for the first one to be freed.
+	reserved := make(chan struct{})
+	go func() {
+		concurrent, ok := tracker.reserve(now)
+		if ok {
+			concurrent.release()
+		}
+		close(reserved)
+	}()
+
+	select {
+	case <-reserved:
+		t.Fatal("expected concurrent reservation to block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	reservation.release()
+
+	select {
+	case <-reserved:
+	case <-time.After(time.Second):
+		t.Fatal("expected concurrent reservation to complete")
+	}
+
+	testutils.AssertBigIntsEqual(
+		t,
+		"spent in window",
+		big.NewInt(0),
+		tracker.spentInWindow(now),
+	)
+}
+
+func TestSpendingTracker_NoLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tracker := newSpendingTracker(nil, newMockPersistenceHandle())
+
+	reservation, _ := tracker.reserve(now)
+	if err := reservation.commit(big.NewInt(1000000), now); err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok := tracker.reserve(now)
+	testutils.AssertBoolsEqual(t, "can reserve", true, ok)
+}
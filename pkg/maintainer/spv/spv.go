@@ -6,13 +6,17 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/keep-network/keep-core/pkg/tbtc"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-common/pkg/persistence"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/maintainer/btcdiff"
 )
 
@@ -24,15 +28,48 @@ const difficultyEpochLength = 2016
 func Initialize(
 	ctx context.Context,
 	config Config,
+	btcNetwork bitcoin.Network,
 	spvChain Chain,
 	btcDiffChain btcdiff.Chain,
 	btcChain bitcoin.Chain,
+	fallbackBtcChains []bitcoin.Chain,
+	persistence persistence.BasicHandle,
+	clientInfo *clientinfo.Registry,
 ) {
 	spvMaintainer := &spvMaintainer{
 		config:       config,
+		btcNetwork:   btcNetwork,
 		spvChain:     spvChain,
 		btcDiffChain: btcDiffChain,
 		btcChain:     btcChain,
+		checkpoints:  newCheckpointStore(persistence),
+		rescanned:    make(map[tbtc.WalletActionType]bool),
+		metrics:      newMetrics(),
+		spending: newSpendingTracker(
+			weiValue(config.MaxDailyGasSpend),
+			persistence,
+		),
+		status: newStatus(),
+
+		spvProofAssembler: newFallbackSpvProofAssembler(
+			bitcoin.AssembleSpvProof,
+			fallbackBtcChains,
+		),
+		provenTransactions:  newProvenTransactionsCache(persistence),
+		confirmationWatcher: newConfirmationWatcher(ctx, btcChain),
+		reorgs:              bitcoin.WatchReorgs(ctx, btcChain),
+	}
+
+	if clientInfo != nil {
+		// only if client info endpoint is configured
+		spvMaintainer.metrics.register(clientInfo, spvMaintainer.spending)
+
+		clientInfo.RegisterApplicationSource(
+			"spv",
+			func() clientinfo.ApplicationInfo {
+				return spvMaintainer.status.info(spvMaintainer.checkpoints)
+			},
+		)
 	}
 
 	go spvMaintainer.startControlLoop(ctx)
@@ -42,27 +79,60 @@ func Initialize(
 // SPV maintainer.
 var proofTypes = map[tbtc.WalletActionType]struct {
 	unprovenTransactionsGetter unprovenTransactionsGetter
+	proofRequirementChecker    proofRequirementChecker
 	transactionProofSubmitter  transactionProofSubmitter
 }{
 	tbtc.ActionDepositSweep: {
 		unprovenTransactionsGetter: getUnprovenDepositSweepTransactions,
-		transactionProofSubmitter:  SubmitDepositSweepProof,
+		proofRequirementChecker:    isDepositSweepProofRequired,
+		transactionProofSubmitter:  submitDepositSweepProof,
 	},
 	tbtc.ActionRedemption: {
 		unprovenTransactionsGetter: getUnprovenRedemptionTransactions,
-		transactionProofSubmitter:  SubmitRedemptionProof,
+		proofRequirementChecker:    isRedemptionProofRequired,
+		transactionProofSubmitter:  submitRedemptionProof,
 	},
 	tbtc.ActionMovingFunds: {
 		unprovenTransactionsGetter: getUnprovenMovingFundsTransactions,
-		transactionProofSubmitter:  SubmitMovingFundsProof,
+		proofRequirementChecker:    isMovingFundsProofRequired,
+		transactionProofSubmitter:  submitMovingFundsProof,
 	},
 }
 
 type spvMaintainer struct {
 	config       Config
+	btcNetwork   bitcoin.Network
 	spvChain     Chain
 	btcDiffChain btcdiff.Chain
 	btcChain     bitcoin.Chain
+	checkpoints  *checkpointStore
+	metrics      *metrics
+	spending     *spendingTracker
+	status       *status
+
+	// spvProofAssembler assembles proofs using the primary Bitcoin backend
+	// and falls back to alternate backends if that fails.
+	spvProofAssembler spvProofAssembler
+
+	// provenTransactions holds transactions that do not have to be proven
+	// anymore.
+	provenTransactions *provenTransactionsCache
+
+	// rescanned holds proof types for which the rescan from the configured
+	// block was already done.
+	rescanned map[tbtc.WalletActionType]bool
+
+	// confirmationWatcher watches transactions that have not accumulated
+	// enough confirmations yet and wakes up the maintainer once they do.
+	// Can be nil, in which case the maintainer always waits for the idle
+	// backoff to elapse.
+	confirmationWatcher *confirmationWatcher
+
+	// reorgs receives Bitcoin chain reorganization events. Confirmations of
+	// transactions skipped so far may be stale once the chain reorganizes
+	// so the maintainer re-verifies them immediately. Can be nil, in which
+	// case reorganizations are only noticed in the next regular round.
+	reorgs <-chan *bitcoin.ReorgEvent
 }
 
 func (sm *spvMaintainer) startControlLoop(ctx context.Context) {
@@ -94,15 +164,26 @@ func (sm *spvMaintainer) maintainSpv(ctx context.Context) error {
 		for action, v := range proofTypes {
 			logger.Infof("starting [%s] proof task execution...", action)
 
-			if err := sm.proveTransactions(
+			err := sm.proveTransactions(
+				action,
 				v.unprovenTransactionsGetter,
+				v.proofRequirementChecker,
 				v.transactionProofSubmitter,
-			); err != nil {
-				return fmt.Errorf(
-					"error while proving [%s] transactions: [%v]",
+			)
+
+			sm.status.recordRound(action, err, time.Now())
+
+			// A failure of one proof type must not hold off the other ones
+			// so the error is only logged and the proof type is retried in
+			// the next round.
+			if err != nil {
+				logger.Errorf(
+					"error while proving [%s] transactions: [%v]; "+
+						"continuing with other proof types",
 					action,
 					err,
 				)
+				continue
 			}
 
 			logger.Infof("[%s] proof task completed", action)
@@ -113,8 +194,27 @@ func (sm *spvMaintainer) maintainSpv(ctx context.Context) error {
 			sm.config.IdleBackoffTime,
 		)
 
+		var wakeUps <-chan struct{}
+		if sm.confirmationWatcher != nil {
+			wakeUps = sm.confirmationWatcher.wakeUps()
+		}
+
 		select {
 		case <-time.After(sm.config.IdleBackoffTime):
+		case <-wakeUps:
+			logger.Infof("watched transaction is ready to be proven")
+		case event, ok := <-sm.reorgs:
+			// The channel is closed only once the context is done.
+			if !ok {
+				return ctx.Err()
+			}
+
+			logger.Warnf(
+				"Bitcoin chain reorganization detected by backend [%s] at "+
+					"block [%v]; re-verifying transaction confirmations",
+				event.Backend,
+				event.ForkHeight,
+			)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -122,10 +222,13 @@ func (sm *spvMaintainer) maintainSpv(ctx context.Context) error {
 }
 
 // unprovenTransactionsGetter is a type representing a function that is
-// used to get unproven Bitcoin transactions.
+// used to get unproven Bitcoin transactions. Transactions held by the given
+// proven transactions cache are not checked against the chains and are
+// never returned.
 type unprovenTransactionsGetter func(
-	historyDepth uint64,
+	startBlock uint64,
 	transactionLimit int,
+	provenTransactions *provenTransactionsCache,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
 ) (
@@ -133,25 +236,66 @@ type unprovenTransactionsGetter func(
 	error,
 )
 
+// proofRequirementChecker is a type representing a function that is used
+// to check, right before the submission, whether the proof of the given
+// transaction would still be accepted by the host chain. The wallet's
+// on-chain state may change between the discovery of the transaction and
+// the submission of its proof, for example when another maintainer proves
+// the transaction first or the wallet gets terminated.
+type proofRequirementChecker func(
+	transaction *bitcoin.Transaction,
+	btcChain bitcoin.Chain,
+	spvChain Chain,
+) (bool, error)
+
 // transactionProofSubmitter is a type representing a function that is used
 // to submit the constructed SPV proof to the host chain.
 type transactionProofSubmitter func(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
+	options *SubmissionOptions,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
-) error
+	spvProofAssembler spvProofAssembler,
+) (*big.Int, error)
 
 // proveTransactions gets unproven Bitcoin transactions using the provided
 // unprovenTransactionsGetter, build the SPV proofs, and submits them using
-// the provided transactionProofSubmitter.
+// the provided transactionProofSubmitter. Proofs rejected by the provided
+// proofRequirementChecker are not submitted. Transactions are proven
+// concurrently by a bounded number of workers. Once all transactions are
+// processed, the current block is recorded as the checkpoint of the given
+// proof type. An error is returned if any of the transactions could not be
+// proven.
 func (sm *spvMaintainer) proveTransactions(
+	proofType tbtc.WalletActionType,
 	unprovenTransactionsGetter unprovenTransactionsGetter,
+	proofRequirementChecker proofRequirementChecker,
 	transactionProofSubmitter transactionProofSubmitter,
 ) error {
+	blockCounter, err := sm.spvChain.BlockCounter()
+	if err != nil {
+		return fmt.Errorf("failed to get block counter: [%v]", err)
+	}
+
+	currentBlock, err := blockCounter.CurrentBlock()
+	if err != nil {
+		return fmt.Errorf("failed to get current block: [%v]", err)
+	}
+
+	sm.confirmSubmittedProofs(proofType, proofRequirementChecker)
+
+	startBlock := sm.scanStartBlock(proofType, currentBlock)
+
+	logger.Infof(
+		"searching for unproven transactions in events starting from block [%v]",
+		startBlock,
+	)
+
 	transactions, err := unprovenTransactionsGetter(
-		sm.config.HistoryDepth,
+		startBlock,
 		sm.config.TransactionLimit,
+		sm.provenTransactions,
 		sm.btcChain,
 		sm.spvChain,
 	)
@@ -161,70 +305,334 @@ func (sm *spvMaintainer) proveTransactions(
 
 	logger.Infof("found [%d] unproven transaction(s)", len(transactions))
 
-	for _, transaction := range transactions {
-		// Print the transaction in the same endianness as block explorers do.
-		transactionHashStr := transaction.Hash().Hex(bitcoin.ReversedByteOrder)
+	sm.metrics.recordUnprovenTransactions(len(transactions))
+	sm.status.recordBacklog(proofType, len(transactions))
+
+	relayLag, err := getRelayLag(sm.btcChain, sm.btcDiffChain)
+	if err != nil {
+		return fmt.Errorf("failed to get relay lag: [%v]", err)
+	}
+
+	sm.metrics.recordRelayLag(relayLag)
+
+	if relayLag > 0 {
+		// The relay has not been updated with the latest difficulty epochs
+		// yet. Proofs of recent transactions will be deferred until the relay
+		// catches up.
+		logger.Warnf(
+			"relay is [%v] difficulty epoch(s) behind the Bitcoin chain",
+			relayLag,
+		)
+	}
+
+	concurrency := sm.config.ProofWorkers
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(transactions))
+	workerSlots := make(chan struct{}, concurrency)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(transactions))
+
+	for i, transaction := range transactions {
+		workerSlots <- struct{}{}
+
+		go func(i int, transaction *bitcoin.Transaction) {
+			defer func() {
+				<-workerSlots
+				wg.Done()
+			}()
+
+			// Errors are isolated per transaction so that a single failing
+			// proof does not prevent other transactions from being proven.
+			if err := sm.proveTransaction(
+				proofType,
+				transaction,
+				proofRequirementChecker,
+				transactionProofSubmitter,
+			); err != nil {
+				logger.Errorf(
+					"failed to prove transaction [%s]: [%v]",
+					transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+					err,
+				)
+				errs[i] = err
+			}
+		}(i, transaction)
+	}
+
+	wg.Wait()
+
+	if err := sm.checkpoints.set(proofType.String(), currentBlock); err != nil {
+		return fmt.Errorf("failed to record checkpoint: [%v]", err)
+	}
+	sm.rescanned[proofType] = true
+
+	if err := sm.provenTransactions.prune(
+		time.Now().Add(-provenTransactionsRetention),
+	); err != nil {
+		return fmt.Errorf("failed to prune proven transactions: [%v]", err)
+	}
+
+	var result *multierror.Error
+	for _, err := range errs {
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if result != nil {
+		return fmt.Errorf(
+			"failed to prove [%v/%v] transaction(s): [%v]",
+			len(result.Errors),
+			len(transactions),
+			result,
+		)
+	}
+
+	logger.Infof("finished round of proving transactions")
+
+	return nil
+}
+
+// proveTransaction builds the SPV proof of the given transaction and submits
+// it using the provided transactionProofSubmitter. The transaction is skipped
+// if it cannot be proven yet. It will be proven in one of the next rounds.
+// The transaction is also skipped if the provided proofRequirementChecker
+// reports its proof would be reverted by the host chain.
+func (sm *spvMaintainer) proveTransaction(
+	proofType tbtc.WalletActionType,
+	transaction *bitcoin.Transaction,
+	proofRequirementChecker proofRequirementChecker,
+	transactionProofSubmitter transactionProofSubmitter,
+) error {
+	// Print the transaction in the same endianness as block explorers do.
+	transactionHashStr := transaction.Hash().Hex(bitcoin.ReversedByteOrder)
+
+	logger.Infof(
+		"proceeding with proof for transaction [%s]",
+		transactionHashStr,
+	)
 
+	isProofWithinRelayRange, accumulatedConfirmations, requiredConfirmations, err := getProofInfo(
+		transaction.Hash(),
+		sm.btcChain,
+		sm.spvChain,
+		sm.btcDiffChain,
+	)
+	if err != nil {
+		sm.metrics.recordFailure(failureReasonProofInfo)
+		return fmt.Errorf("failed to get proof info: [%v]", err)
+	}
+
+	if !isProofWithinRelayRange {
+		// The required proof goes outside the previous and current
+		// difficulty epochs as seen by the relay. Skip the transaction. It
+		// will most likely be proven later.
+		logger.Warnf(
+			"skipped proving transaction [%s]; the range "+
+				"of the required proof goes outside the previous and "+
+				"current difficulty epochs as seen by the relay",
+			transactionHashStr,
+		)
+		return nil
+	}
+
+	if accumulatedConfirmations < requiredConfirmations {
+		// Skip the transaction as it has not accumulated enough
+		// confirmations. It will be proven later.
 		logger.Infof(
-			"proceeding with proof for transaction [%s]",
+			"skipped proving transaction [%s]; transaction "+
+				"has [%v/%v] confirmations",
 			transactionHashStr,
+			accumulatedConfirmations,
+			requiredConfirmations,
 		)
 
-		isProofWithinRelayRange, accumulatedConfirmations, requiredConfirmations, err := getProofInfo(
-			transaction.Hash(),
+		if sm.confirmationWatcher != nil {
+			sm.confirmationWatcher.watch(transaction, requiredConfirmations)
+		}
+
+		return nil
+	}
+
+	isProofDifficultyProven, err := isProofDifficultyProvenByRelay(
+		transaction.Hash(),
+		requiredConfirmations,
+		sm.btcNetwork,
+		sm.btcChain,
+		sm.btcDiffChain,
+	)
+	if err != nil {
+		sm.metrics.recordFailure(failureReasonRelayCheck)
+		return fmt.Errorf(
+			"failed to check proof difficulty against relay: [%v]",
+			err,
+		)
+	}
+
+	if !isProofDifficultyProven {
+		// The difficulty of the proof's block headers does not match the
+		// difficulty recorded by the relay for their epochs. Most likely,
+		// the relay is lagging at the difficulty epoch boundary. Skip the
+		// transaction to avoid a reverted submission. It will be proven
+		// in a later round.
+		logger.Warnf(
+			"skipped proving transaction [%s]; the difficulty of the "+
+				"proof's block headers is not yet proven by the relay",
+			transactionHashStr,
+		)
+		return nil
+	}
+
+	isProofRequired, err := proofRequirementChecker(
+		transaction,
+		sm.btcChain,
+		sm.spvChain,
+	)
+	if err != nil {
+		sm.metrics.recordFailure(failureReasonWalletCheck)
+		return fmt.Errorf(
+			"failed to check if proof is still required: [%v]",
+			err,
+		)
+	}
+
+	if !isProofRequired {
+		// The wallet's on-chain state does not allow the proof anymore.
+		// Submitting it would result in a guaranteed revert.
+		logger.Infof(
+			"skipped proving transaction [%s]; the proof is no longer "+
+				"required by the wallet's on-chain state",
+			transactionHashStr,
+		)
+		sm.markTransactionProven(transaction.Hash())
+		return nil
+	}
+
+	reservation, ok := sm.spending.reserve(time.Now())
+	if !ok {
+		// The cap on proof submission fees was reached. Skip the transaction.
+		// It will be proven once the spending window moves forward.
+		logger.Warnf(
+			"skipped proving transaction [%s]; the daily gas spending "+
+				"cap was reached",
+			transactionHashStr,
+		)
+		return nil
+	}
+
+	submissionStart := time.Now()
+
+	maxFee, err := transactionProofSubmitter(
+		transaction.Hash(),
+		requiredConfirmations,
+		sm.config.submissionOptions(),
+		sm.btcChain,
+		sm.spvChain,
+		sm.spvProofAssembler,
+	)
+	if err != nil {
+		reservation.release()
+		sm.metrics.recordFailure(failureReasonSubmission)
+		return err
+	}
+
+	if err := reservation.commit(maxFee, time.Now()); err != nil {
+		logger.Warnf(
+			"failed to record spending of proof for transaction [%s]: [%v]",
+			transactionHashStr,
+			err,
+		)
+	}
+	sm.metrics.recordSubmittedProof(time.Since(submissionStart))
+	// The transaction is not cached as proven yet as the submission may
+	// still revert or never get mined. It is cached once the host chain
+	// confirms the proof, see confirmSubmittedProofs.
+	sm.provenTransactions.addSubmitted(proofType, transaction)
+
+	logger.Infof(
+		"successfully submitted proof for transaction [%s]",
+		transactionHashStr,
+	)
+
+	return nil
+}
+
+// confirmSubmittedProofs checks transactions whose proofs of the given type
+// were submitted in previous rounds against the provided
+// proofRequirementChecker. Transactions whose proofs are no longer required
+// were accepted by the host chain and are cached as proven. The remaining
+// ones are forgotten; if their proofs failed, the unproven transactions
+// getter returns them again and they are resubmitted.
+func (sm *spvMaintainer) confirmSubmittedProofs(
+	proofType tbtc.WalletActionType,
+	proofRequirementChecker proofRequirementChecker,
+) {
+	for _, transaction := range sm.provenTransactions.takeSubmitted(proofType) {
+		isProofRequired, err := proofRequirementChecker(
+			transaction,
 			sm.btcChain,
 			sm.spvChain,
-			sm.btcDiffChain,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to get proof info: [%v]", err)
-		}
-
-		if !isProofWithinRelayRange {
-			// The required proof goes outside the previous and current
-			// difficulty epochs as seen by the relay. Skip the transaction. It
-			// will most likely be proven later.
 			logger.Warnf(
-				"skipped proving transaction [%s]; the range "+
-					"of the required proof goes outside the previous and "+
-					"current difficulty epochs as seen by the relay",
-				transactionHashStr,
+				"failed to check if submitted proof of transaction [%s] "+
+					"was accepted: [%v]",
+				transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+				err,
 			)
+			sm.provenTransactions.addSubmitted(proofType, transaction)
 			continue
 		}
 
-		if accumulatedConfirmations < requiredConfirmations {
-			// Skip the transaction as it has not accumulated enough
-			// confirmations. It will be proven later.
-			logger.Infof(
-				"skipped proving transaction [%s]; transaction "+
-					"has [%v/%v] confirmations",
-				transactionHashStr,
-				accumulatedConfirmations,
-				requiredConfirmations,
-			)
-			continue
+		if !isProofRequired {
+			sm.markTransactionProven(transaction.Hash())
 		}
+	}
+}
 
-		err = transactionProofSubmitter(
-			transaction.Hash(),
-			requiredConfirmations,
-			sm.btcChain,
-			sm.spvChain,
+// markTransactionProven records the given transaction in the proven
+// transactions cache. A failure is only logged as the transaction will be
+// checked against the chains again in the next round.
+func (sm *spvMaintainer) markTransactionProven(transactionHash bitcoin.Hash) {
+	if err := sm.provenTransactions.add(transactionHash, time.Now()); err != nil {
+		logger.Warnf(
+			"failed to record proven transaction [%s]: [%v]",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			err,
 		)
-		if err != nil {
-			return err
-		}
+	}
+}
 
-		logger.Infof(
-			"successfully submitted proof for transaction [%s]",
-			transactionHashStr,
-		)
+// scanStartBlock returns the block from which wallet-related events of the
+// given proof type should be searched for. If a rescan from a given block was
+// requested in the config and was not done yet, that block is returned.
+// Otherwise, the history depth is counted back from the latest processed
+// block recorded in the checkpoint. This way, events emitted while the
+// maintainer was not running are not missed. If there is no checkpoint, the
+// history depth is counted back from the current block.
+func (sm *spvMaintainer) scanStartBlock(
+	proofType tbtc.WalletActionType,
+	currentBlock uint64,
+) uint64 {
+	if sm.config.RescanFromBlock != 0 && !sm.rescanned[proofType] {
+		return sm.config.RescanFromBlock
 	}
 
-	logger.Infof("finished round of proving transactions")
+	referenceBlock := currentBlock
+	if checkpoint, ok := sm.checkpoints.get(proofType.String()); ok &&
+		checkpoint < referenceBlock {
+		referenceBlock = checkpoint
+	}
 
-	return nil
+	if referenceBlock < sm.config.HistoryDepth {
+		return 0
+	}
+
+	return referenceBlock - sm.config.HistoryDepth
 }
 
 func isInputCurrentWalletsMainUTXO(
@@ -259,6 +667,59 @@ func isInputCurrentWalletsMainUTXO(
 	return bytes.Equal(mainUtxoHash[:], wallet.MainUtxoHash[:]), nil
 }
 
+// isWalletReadyForProof checks whether the wallet with the given public key
+// hash is in one of the allowed states and whether the given main UTXO is
+// still the wallet's current main UTXO. A proof submitted for a wallet in
+// any other state is reverted by the host chain. If the main UTXO is no
+// longer current, the proof was already submitted and the requests handled
+// by the transaction are already marked as processed. A zero-filled main
+// UTXO means the transaction does not spend any main UTXO; in that case the
+// wallet must not have a main UTXO either.
+func isWalletReadyForProof(
+	walletPublicKeyHash [20]byte,
+	mainUTXO bitcoin.UnspentTransactionOutput,
+	allowedStates []tbtc.WalletState,
+	spvChain Chain,
+) (bool, error) {
+	wallet, err := spvChain.GetWallet(walletPublicKeyHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to get wallet: [%v]", err)
+	}
+
+	isStateAllowed := false
+	for _, state := range allowedStates {
+		if wallet.State == state {
+			isStateAllowed = true
+			break
+		}
+	}
+
+	if !isStateAllowed {
+		logger.Infof(
+			"wallet [%x] cannot accept proofs because of wallet state [%v]",
+			walletPublicKeyHash,
+			wallet.State,
+		)
+		return false, nil
+	}
+
+	var expectedMainUtxoHash [32]byte
+	if mainUTXO.Outpoint.TransactionHash != (bitcoin.Hash{}) ||
+		mainUTXO.Value != 0 {
+		expectedMainUtxoHash = spvChain.ComputeMainUtxoHash(&mainUTXO)
+	}
+
+	if expectedMainUtxoHash != wallet.MainUtxoHash {
+		logger.Infof(
+			"wallet [%x] main UTXO changed; the transaction was already proven",
+			walletPublicKeyHash,
+		)
+		return false, nil
+	}
+
+	return true, nil
+}
+
 // getProofInfo returns information about the SPV proof. It includes the
 // information whether the transaction proof range is within the previous and
 // current difficulty epochs as seen by the relay, the accumulated number of
@@ -414,6 +875,18 @@ func getProofInfo(
 		requiredConfirmations := numberOfBlocksPreviousEpoch +
 			numberOfBlocksCurrentEpoch.Uint64()
 
+		// The additional block headers may push the end of the proof beyond
+		// the current difficulty epoch as seen by the relay. This can happen
+		// if the difficulty dropped significantly. Such a proof would be
+		// rejected by the relay so defer proving the transaction until the
+		// relay catches up. Once the relay's current epoch moves forward, the
+		// proof will lie entirely within the previous epoch.
+		adjustedProofEndEpoch :=
+			(proofStartBlock + requiredConfirmations - 1) / difficultyEpochLength
+		if adjustedProofEndEpoch != currentEpoch {
+			return false, 0, 0, nil
+		}
+
 		return true, accumulatedConfirmations, uint(requiredConfirmations), nil
 	}
 
@@ -428,6 +901,146 @@ func getProofInfo(
 	return false, 0, 0, nil
 }
 
+// getRelayLag returns the number of Bitcoin difficulty epochs the relay is
+// behind the Bitcoin chain. Zero is returned if the relay is up to date.
+func getRelayLag(
+	btcChain bitcoin.Chain,
+	btcDiffChain btcdiff.Chain,
+) (uint64, error) {
+	latestBlockHeight, err := btcChain.GetLatestBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to get latest block height: [%v]",
+			err,
+		)
+	}
+	latestEpoch := uint64(latestBlockHeight) / difficultyEpochLength
+
+	currentEpoch, err := btcDiffChain.CurrentEpoch()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current epoch: [%v]", err)
+	}
+
+	if latestEpoch <= currentEpoch {
+		return 0, nil
+	}
+
+	return latestEpoch - currentEpoch, nil
+}
+
+// isProofDifficultyProvenByRelay checks whether the difficulty of the block
+// headers forming the proof of the given transaction is already known to
+// the relay. The headers at both ends of the proof are compared against the
+// difficulties the relay recorded for the current and previous epochs. The
+// proof is considered not proven if any of the headers belongs to an epoch
+// unknown to the relay or if its difficulty differs from the relay's one.
+// On networks allowing minimum-difficulty blocks, the difficulty of such
+// a block says nothing about its epoch so the last header of the proof is
+// not compared if it is one. The first header of the proof is always
+// compared as the Bridge requires it to have the difficulty of the current
+// or previous epoch; a transaction mined in a minimum-difficulty block
+// cannot be proven and an error is returned.
+func isProofDifficultyProvenByRelay(
+	transactionHash bitcoin.Hash,
+	requiredConfirmations uint,
+	btcNetwork bitcoin.Network,
+	btcChain bitcoin.Chain,
+	btcDiffChain btcdiff.Chain,
+) (bool, error) {
+	latestBlockHeight, err := btcChain.GetLatestBlockHeight()
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get latest block height: [%v]",
+			err,
+		)
+	}
+
+	accumulatedConfirmations, err := btcChain.GetTransactionConfirmations(
+		transactionHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get transaction confirmations: [%v]",
+			err,
+		)
+	}
+
+	currentEpoch, err := btcDiffChain.CurrentEpoch()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current epoch: [%v]", err)
+	}
+
+	currentEpochDifficulty, previousEpochDifficulty, err :=
+		btcDiffChain.GetCurrentAndPrevEpochDifficulty()
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed to get Bitcoin epoch difficulties: [%v]",
+			err,
+		)
+	}
+
+	// The transaction cannot have more confirmations than blocks, unless
+	// the confirmations and the latest block height were read from Bitcoin
+	// nodes not in sync. Guard against the underflow in that case.
+	if accumulatedConfirmations == 0 ||
+		accumulatedConfirmations > latestBlockHeight+1 {
+		return false, fmt.Errorf(
+			"transaction confirmations [%v] inconsistent with "+
+				"latest block height [%v]",
+			accumulatedConfirmations,
+			latestBlockHeight,
+		)
+	}
+
+	proofStartBlock := latestBlockHeight - accumulatedConfirmations + 1
+	proofEndBlock := proofStartBlock + requiredConfirmations - 1
+
+	if proofEndBlock > latestBlockHeight {
+		// The proof is not complete yet.
+		return false, nil
+	}
+
+	for _, blockHeight := range []uint{proofStartBlock, proofEndBlock} {
+		blockHeader, err := btcChain.GetBlockHeader(blockHeight)
+		if err != nil {
+			return false, fmt.Errorf(
+				"failed to get block header [%v]: [%v]",
+				blockHeight,
+				err,
+			)
+		}
+
+		// The previous epoch is compared without subtracting from the
+		// current one so that there is no underflow at epoch zero.
+		var relayDifficulty *big.Int
+		switch blockEpoch := uint64(blockHeight) / difficultyEpochLength; {
+		case blockEpoch == currentEpoch:
+			relayDifficulty = currentEpochDifficulty
+		case blockEpoch+1 == currentEpoch:
+			relayDifficulty = previousEpochDifficulty
+		default:
+			return false, nil
+		}
+
+		if blockHeader.IsMinDifficulty(btcNetwork) {
+			if blockHeight == proofStartBlock {
+				return false, fmt.Errorf(
+					"transaction mined in minimum-difficulty block [%v]",
+					blockHeight,
+				)
+			}
+
+			continue
+		}
+
+		if blockHeader.Difficulty().Cmp(relayDifficulty) != 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // walletEvent is a type constraint representing wallet-related chain events.
 type walletEvent interface {
 	GetWalletPublicKeyHash() [20]byte
@@ -453,6 +1066,64 @@ func uniqueWalletPublicKeyHashes[T walletEvent](events []T) [][20]byte {
 	return publicKeyHashes
 }
 
+// getUnprovenWalletTransactions walks the confirmed transaction history of
+// the given public key hash from the latest transaction backwards and
+// returns at most the given number of transactions not known to be proven.
+// Transactions known to be proven do not count towards the limit so a burst
+// of recently proven transactions does not hide older unproven ones. The
+// returned transactions are ordered by block height in the ascending order.
+func getUnprovenWalletTransactions(
+	btcChain bitcoin.Chain,
+	publicKeyHash [20]byte,
+	transactionLimit int,
+	provenTransactions *provenTransactionsCache,
+) ([]*bitcoin.Transaction, error) {
+	var transactions []*bitcoin.Transaction
+
+	query := &bitcoin.TransactionHistoryQuery{
+		PageSize: transactionLimit,
+	}
+
+	for len(transactions) < transactionLimit {
+		page, err := btcChain.GetTransactionsPageForPublicKeyHash(
+			publicKeyHash,
+			query,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Pages are walked backwards so iterate from the latest transaction
+		// of the page.
+		for i := len(page.Transactions) - 1; i >= 0; i-- {
+			transaction := page.Transactions[i]
+
+			if provenTransactions.contains(transaction.Hash()) {
+				continue
+			}
+
+			transactions = append(transactions, transaction)
+
+			if len(transactions) == transactionLimit {
+				break
+			}
+		}
+
+		if page.NextCursor == nil {
+			break
+		}
+
+		query.Cursor = page.NextCursor
+	}
+
+	// Restore the ascending order of transactions.
+	for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+		transactions[i], transactions[j] = transactions[j], transactions[i]
+	}
+
+	return transactions, nil
+}
+
 // spvProofAssembler is a type representing a function that is used
 // to assemble an SPV proof for the given transaction hash and confirmations
 // count.
@@ -461,3 +1132,52 @@ type spvProofAssembler func(
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 ) (*bitcoin.Transaction, *bitcoin.SpvProof, error)
+
+// newFallbackSpvProofAssembler returns an SPV proof assembler that assembles
+// the proof with the given assembler using the given Bitcoin chain and, if
+// that fails, retries with the given fallback Bitcoin chains in order. An
+// error is returned only if the proof could not be assembled using any of
+// the chains.
+func newFallbackSpvProofAssembler(
+	assembler spvProofAssembler,
+	fallbackBtcChains []bitcoin.Chain,
+) spvProofAssembler {
+	return func(
+		transactionHash bitcoin.Hash,
+		requiredConfirmations uint,
+		btcChain bitcoin.Chain,
+	) (*bitcoin.Transaction, *bitcoin.SpvProof, error) {
+		btcChains := append([]bitcoin.Chain{btcChain}, fallbackBtcChains...)
+
+		var result *multierror.Error
+		for i, chain := range btcChains {
+			transaction, proof, err := assembler(
+				transactionHash,
+				requiredConfirmations,
+				chain,
+			)
+			if err == nil {
+				return transaction, proof, nil
+			}
+
+			result = multierror.Append(result, err)
+
+			if i < len(btcChains)-1 {
+				logger.Warnf(
+					"failed to assemble proof of transaction [%s] using "+
+						"Bitcoin backend [%d]: [%v]; retrying with the next "+
+						"backend",
+					transactionHash.Hex(bitcoin.ReversedByteOrder),
+					i,
+					err,
+				)
+			}
+		}
+
+		return nil, nil, fmt.Errorf(
+			"failed to assemble proof using [%d] Bitcoin backend(s): [%v]",
+			len(btcChains),
+			result,
+		)
+	}
+}
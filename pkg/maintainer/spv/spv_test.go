@@ -1,10 +1,14 @@
 package spv
 
 import (
+	"context"
 	"encoding/hex"
+	"fmt"
 	"math/big"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/keep-network/keep-core/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
@@ -151,6 +155,574 @@ func TestGetProofInfo(t *testing.T) {
 	}
 }
 
+func TestGetProofInfo_AdjustedProofEnd(t *testing.T) {
+	tests := map[string]struct {
+		previousEpochDifficulty          *big.Int
+		currentEpochDifficulty           *big.Int
+		expectedIsProofWithinRelayRange  bool
+		expectedAccumulatedConfirmations uint
+		expectedRequiredConfirmations    uint
+	}{
+		"adjusted proof ends in current epoch": {
+			previousEpochDifficulty:          big.NewInt(50000000000000),
+			currentEpochDifficulty:           big.NewInt(30000000000000),
+			expectedIsProofWithinRelayRange:  true,
+			expectedAccumulatedConfirmations: 31,
+			expectedRequiredConfirmations:    9,
+		},
+		// The difficulty dropped so much that the additional block headers
+		// push the end of the proof beyond the current epoch as seen by
+		// the relay.
+		"adjusted proof ends outside current epoch": {
+			previousEpochDifficulty:          big.NewInt(1000000),
+			currentEpochDifficulty:           big.NewInt(1000),
+			expectedIsProofWithinRelayRange:  false,
+			expectedAccumulatedConfirmations: 0,
+			expectedRequiredConfirmations:    0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			transactionHash, err := bitcoin.NewHashFromString(
+				"44c568bc0eac07a2a9c2b46829be5b5d46e7d00e17bfb613f506a75ccf86a473",
+				bitcoin.InternalByteOrder,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			localChain := newLocalChain()
+
+			// The proof starts in the previous epoch, 2 blocks before the
+			// current one, and spans both epochs.
+			btcChain := newLocalBitcoinChain()
+			btcChain.addBlockHeader(790300, &bitcoin.BlockHeader{})
+			btcChain.addTransactionConfirmations(transactionHash, 31)
+
+			localChain.setTxProofDifficultyFactor(big.NewInt(6))
+			localChain.setCurrentEpoch(392)
+			localChain.setCurrentAndPrevEpochDifficulty(
+				test.previousEpochDifficulty,
+				test.currentEpochDifficulty,
+			)
+
+			isProofWithinRelayRange,
+				accumulatedConfirmations,
+				requiredConfirmations,
+				err :=
+				getProofInfo(
+					transactionHash,
+					btcChain,
+					localChain,
+					localChain,
+				)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"is proof within range",
+				test.expectedIsProofWithinRelayRange,
+				isProofWithinRelayRange,
+			)
+
+			testutils.AssertUintsEqual(
+				t,
+				"accumulated confirmations",
+				uint64(test.expectedAccumulatedConfirmations),
+				uint64(accumulatedConfirmations),
+			)
+
+			testutils.AssertUintsEqual(
+				t,
+				"required confirmations",
+				uint64(test.expectedRequiredConfirmations),
+				uint64(requiredConfirmations),
+			)
+		})
+	}
+}
+
+func TestGetRelayLag(t *testing.T) {
+	tests := map[string]struct {
+		latestBlockHeight uint
+		currentEpoch      uint64
+		expectedRelayLag  uint64
+	}{
+		"relay up to date": {
+			latestBlockHeight: 790300,
+			currentEpoch:      392,
+			expectedRelayLag:  0,
+		},
+		"relay one epoch behind": {
+			latestBlockHeight: 792300,
+			currentEpoch:      392,
+			expectedRelayLag:  1,
+		},
+		"relay ahead of the Bitcoin chain": {
+			latestBlockHeight: 790300,
+			currentEpoch:      393,
+			expectedRelayLag:  0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := newLocalChain()
+			localChain.setCurrentEpoch(test.currentEpoch)
+
+			btcChain := newLocalBitcoinChain()
+			btcChain.addBlockHeader(
+				test.latestBlockHeight,
+				&bitcoin.BlockHeader{},
+			)
+
+			relayLag, err := getRelayLag(btcChain, localChain)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"relay lag",
+				test.expectedRelayLag,
+				relayLag,
+			)
+		})
+	}
+}
+
+func TestIsProofDifficultyProvenByRelay(t *testing.T) {
+	// Each difficulty epoch has different block header bits.
+	epochBits := map[uint]uint32{
+		391: 0x1d00ffff,
+		392: 0x1c7fff80,
+		393: 0x1c3fffc0,
+	}
+
+	difficulty := func(bits uint32) *big.Int {
+		return (&bitcoin.BlockHeader{Bits: bits}).Difficulty()
+	}
+
+	tests := map[string]struct {
+		latestBlockHeight        uint
+		transactionConfirmations uint
+		requiredConfirmations    uint
+		currentEpochDifficulty   *big.Int
+		expectedResult           bool
+	}{
+		"proof entirely within current epoch": {
+			latestBlockHeight:        790300,
+			transactionConfirmations: 3,
+			requiredConfirmations:    3,
+			currentEpochDifficulty:   difficulty(epochBits[392]),
+			expectedResult:           true,
+		},
+		"proof spans previous and current epochs": {
+			latestBlockHeight:        790300,
+			transactionConfirmations: 31,
+			requiredConfirmations:    9,
+			currentEpochDifficulty:   difficulty(epochBits[392]),
+			expectedResult:           true,
+		},
+		"proof ends in epoch unknown to relay": {
+			latestBlockHeight:        792300,
+			transactionConfirmations: 20,
+			requiredConfirmations:    9,
+			currentEpochDifficulty:   difficulty(epochBits[392]),
+			expectedResult:           false,
+		},
+		"relay difficulty does not match block headers": {
+			latestBlockHeight:        790300,
+			transactionConfirmations: 3,
+			requiredConfirmations:    3,
+			currentEpochDifficulty:   difficulty(epochBits[393]),
+			expectedResult:           false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			transactionHash, err := bitcoin.NewHashFromString(
+				"44c568bc0eac07a2a9c2b46829be5b5d46e7d00e17bfb613f506a75ccf86a473",
+				bitcoin.InternalByteOrder,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			localChain := newLocalChain()
+			localChain.setCurrentEpoch(392)
+			localChain.setCurrentAndPrevEpochDifficulty(
+				difficulty(epochBits[391]),
+				test.currentEpochDifficulty,
+			)
+
+			btcChain := newLocalBitcoinChain()
+			transactionBlockHeight :=
+				test.latestBlockHeight - test.transactionConfirmations + 1
+			for height := transactionBlockHeight; height <= test.latestBlockHeight; height++ {
+				btcChain.addBlockHeader(
+					height,
+					&bitcoin.BlockHeader{
+						Bits: epochBits[height/difficultyEpochLength],
+					},
+				)
+			}
+			btcChain.addTransactionConfirmations(
+				transactionHash,
+				test.transactionConfirmations,
+			)
+
+			result, err := isProofDifficultyProvenByRelay(
+				transactionHash,
+				test.requiredConfirmations,
+				bitcoin.Mainnet,
+				btcChain,
+				localChain,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"is proof difficulty proven by relay",
+				test.expectedResult,
+				result,
+			)
+		})
+	}
+}
+
+func TestIsProofDifficultyProvenByRelay_EpochZero(t *testing.T) {
+	bits := uint32(0x1d00ffff)
+	difficulty := (&bitcoin.BlockHeader{Bits: bits}).Difficulty()
+
+	tests := map[string]struct {
+		transactionConfirmations uint
+		expectedResult           bool
+		expectedError            bool
+	}{
+		"proof within epoch zero": {
+			transactionConfirmations: 3,
+			expectedResult:           true,
+		},
+		// The Bitcoin node reporting the confirmations is ahead of the one
+		// reporting the latest block height.
+		"confirmations inconsistent with latest block height": {
+			transactionConfirmations: 5,
+			expectedError:            true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			transactionHash, err := bitcoin.NewHashFromString(
+				"44c568bc0eac07a2a9c2b46829be5b5d46e7d00e17bfb613f506a75ccf86a473",
+				bitcoin.InternalByteOrder,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			localChain := newLocalChain()
+			localChain.setCurrentEpoch(0)
+			localChain.setCurrentAndPrevEpochDifficulty(big.NewInt(0), difficulty)
+
+			btcChain := newLocalBitcoinChain()
+			for height := uint(1); height <= 3; height++ {
+				btcChain.addBlockHeader(height, &bitcoin.BlockHeader{Bits: bits})
+			}
+			btcChain.addTransactionConfirmations(
+				transactionHash,
+				test.transactionConfirmations,
+			)
+
+			result, err := isProofDifficultyProvenByRelay(
+				transactionHash,
+				3,
+				bitcoin.Mainnet,
+				btcChain,
+				localChain,
+			)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"is proof difficulty proven by relay",
+				test.expectedResult,
+				result,
+			)
+		})
+	}
+}
+
+func TestIsProofDifficultyProvenByRelay_MinDifficultyBlocks(t *testing.T) {
+	epochBits := uint32(0x1c7fff80)
+	minDifficultyBits := uint32(0x1d00ffff)
+	difficulty := (&bitcoin.BlockHeader{Bits: epochBits}).Difficulty()
+
+	tests := map[string]struct {
+		network          bitcoin.Network
+		minDifficultyEnd bool
+		minDifficultyTx  bool
+		expectedResult   bool
+		expectedError    bool
+	}{
+		"epoch difficulty blocks on testnet4": {
+			network:        bitcoin.Testnet4,
+			expectedResult: true,
+		},
+		"min difficulty last block on testnet4": {
+			network:          bitcoin.Testnet4,
+			minDifficultyEnd: true,
+			expectedResult:   true,
+		},
+		"min difficulty last block on mainnet": {
+			network:          bitcoin.Mainnet,
+			minDifficultyEnd: true,
+			expectedResult:   false,
+		},
+		"min difficulty transaction block on testnet4": {
+			network:         bitcoin.Testnet4,
+			minDifficultyTx: true,
+			expectedError:   true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			transactionHash, err := bitcoin.NewHashFromString(
+				"44c568bc0eac07a2a9c2b46829be5b5d46e7d00e17bfb613f506a75ccf86a473",
+				bitcoin.InternalByteOrder,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			localChain := newLocalChain()
+			localChain.setCurrentEpoch(392)
+			localChain.setCurrentAndPrevEpochDifficulty(difficulty, difficulty)
+
+			btcChain := newLocalBitcoinChain()
+			for height := uint(790298); height <= 790300; height++ {
+				bits := epochBits
+				if (height == 790298 && test.minDifficultyTx) ||
+					(height == 790300 && test.minDifficultyEnd) {
+					bits = minDifficultyBits
+				}
+
+				btcChain.addBlockHeader(height, &bitcoin.BlockHeader{Bits: bits})
+			}
+			btcChain.addTransactionConfirmations(transactionHash, 3)
+
+			result, err := isProofDifficultyProvenByRelay(
+				transactionHash,
+				3,
+				test.network,
+				btcChain,
+				localChain,
+			)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"is proof difficulty proven by relay",
+				test.expectedResult,
+				result,
+			)
+		})
+	}
+}
+
+func TestSpvMaintainer_ProveTransactions(t *testing.T) {
+	bits := uint32(0x1c7fff80)
+	difficulty := (&bitcoin.BlockHeader{Bits: bits}).Difficulty()
+
+	localChain := newLocalChain()
+	localChain.setTxProofDifficultyFactor(big.NewInt(6))
+	localChain.setCurrentEpoch(392)
+	localChain.setCurrentAndPrevEpochDifficulty(difficulty, difficulty)
+
+	blockCounter := newMockBlockCounter()
+	blockCounter.SetCurrentBlock(1000)
+	localChain.setBlockCounter(blockCounter)
+
+	btcChain := newLocalBitcoinChain()
+	for height := uint(790291); height <= 790300; height++ {
+		btcChain.addBlockHeader(height, &bitcoin.BlockHeader{Bits: bits})
+	}
+
+	transactions := make([]*bitcoin.Transaction, 5)
+	for i := range transactions {
+		transactions[i] = &bitcoin.Transaction{Locktime: uint32(i)}
+		btcChain.addTransactionConfirmations(transactions[i].Hash(), 10)
+	}
+
+	failingTransactionHash := transactions[2].Hash()
+	alreadyProvenTransactionHash := transactions[4].Hash()
+
+	var mutex sync.Mutex
+	submittedTransactions := make(map[bitcoin.Hash]uint)
+
+	transactionProofSubmitter := func(
+		transactionHash bitcoin.Hash,
+		requiredConfirmations uint,
+		options *SubmissionOptions,
+		btcChain bitcoin.Chain,
+		spvChain Chain,
+		spvProofAssembler spvProofAssembler,
+	) (*big.Int, error) {
+		if transactionHash == failingTransactionHash {
+			return nil, fmt.Errorf("submission failed")
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		submittedTransactions[transactionHash] = requiredConfirmations
+
+		return big.NewInt(1000), nil
+	}
+
+	unprovenTransactionsGetter := func(
+		startBlock uint64,
+		transactionLimit int,
+		provenTransactions *provenTransactionsCache,
+		btcChain bitcoin.Chain,
+		spvChain Chain,
+	) ([]*bitcoin.Transaction, error) {
+		return transactions, nil
+	}
+
+	proofRequirementChecker := func(
+		transaction *bitcoin.Transaction,
+		btcChain bitcoin.Chain,
+		spvChain Chain,
+	) (bool, error) {
+		return transaction.Hash() != alreadyProvenTransactionHash, nil
+	}
+
+	spvMaintainer := &spvMaintainer{
+		config: Config{
+			HistoryDepth: 100,
+			ProofWorkers: 2,
+		},
+		spvChain:     localChain,
+		btcDiffChain: localChain,
+		btcChain:     btcChain,
+		checkpoints:  newCheckpointStore(newMockPersistenceHandle()),
+		rescanned:    make(map[tbtc.WalletActionType]bool),
+		metrics:      newMetrics(),
+		spending:     newSpendingTracker(nil, newMockPersistenceHandle()),
+		status:       newStatus(),
+
+		provenTransactions: newProvenTransactionsCache(
+			newMockPersistenceHandle(),
+		),
+	}
+
+	err := spvMaintainer.proveTransactions(
+		tbtc.ActionDepositSweep,
+		unprovenTransactionsGetter,
+		proofRequirementChecker,
+		transactionProofSubmitter,
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"submitted transactions count",
+		3,
+		len(submittedTransactions),
+	)
+
+	for i, transaction := range transactions {
+		requiredConfirmations, submitted := submittedTransactions[transaction.Hash()]
+		testutils.AssertBoolsEqual(
+			t,
+			fmt.Sprintf("transaction [%v] submitted", i),
+			i != 2 && i != 4,
+			submitted,
+		)
+		if submitted {
+			testutils.AssertUintsEqual(
+				t,
+				fmt.Sprintf("transaction [%v] required confirmations", i),
+				6,
+				uint64(requiredConfirmations),
+			)
+		}
+	}
+
+	for i, transaction := range transactions {
+		testutils.AssertBoolsEqual(
+			t,
+			fmt.Sprintf("transaction [%v] cached as proven", i),
+			i == 4,
+			spvMaintainer.provenTransactions.contains(transaction.Hash()),
+		)
+	}
+
+	checkpoint, ok := spvMaintainer.checkpoints.get(
+		tbtc.ActionDepositSweep.String(),
+	)
+	testutils.AssertBoolsEqual(t, "checkpoint exists", true, ok)
+	testutils.AssertUintsEqual(t, "checkpoint", 1000, checkpoint)
+
+	testutils.AssertUintsEqual(
+		t,
+		"unproven transactions metric",
+		5,
+		spvMaintainer.metrics.unprovenTransactionsCount,
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"submitted proofs metric",
+		3,
+		spvMaintainer.metrics.submittedProofsCount,
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"submission failures metric",
+		1,
+		spvMaintainer.metrics.failuresCount[failureReasonSubmission],
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"relay lag metric",
+		0,
+		spvMaintainer.metrics.relayLag,
+	)
+	testutils.AssertBigIntsEqual(
+		t,
+		"gas spent",
+		big.NewInt(3000),
+		spvMaintainer.spending.spent(),
+	)
+}
+
 func TestUniqueWalletPublicKeyHashes(t *testing.T) {
 	bytesFromHex := func(str string) []byte {
 		value, err := hex.DecodeString(str)
@@ -217,6 +789,63 @@ func TestUniqueWalletPublicKeyHashes(t *testing.T) {
 	}
 }
 
+func TestGetUnprovenWalletTransactions(t *testing.T) {
+	publicKeyHash := [20]byte{1}
+
+	script, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	btcChain := newLocalBitcoinChain()
+
+	transactions := make([]*bitcoin.Transaction, 10)
+	for i := range transactions {
+		transactions[i] = &bitcoin.Transaction{
+			Version: 1,
+			Outputs: []*bitcoin.TransactionOutput{
+				{Value: int64(i + 1), PublicKeyScript: script},
+			},
+		}
+
+		if err := btcChain.BroadcastTransaction(transactions[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provenTransactions := newProvenTransactionsCache(
+		newMockPersistenceHandle(),
+	)
+
+	// The latest four transactions are proven so they should not count
+	// towards the limit.
+	for _, transaction := range transactions[6:] {
+		err := provenTransactions.add(transaction.Hash(), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unprovenTransactions, err := getUnprovenWalletTransactions(
+		btcChain,
+		publicKeyHash,
+		3,
+		provenTransactions,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTransactions := transactions[3:6]
+	if !reflect.DeepEqual(expectedTransactions, unprovenTransactions) {
+		t.Errorf(
+			"unexpected transactions\nexpected: %v\nactual:   %v\n",
+			expectedTransactions,
+			unprovenTransactions,
+		)
+	}
+}
+
 func TestIsInputCurrentWalletsMainUTXO(t *testing.T) {
 	bytesFromHex := func(str string) []byte {
 		value, err := hex.DecodeString(str)
@@ -324,3 +953,289 @@ func TestIsInputCurrentWalletsMainUTXO(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWalletReadyForProof(t *testing.T) {
+	walletPublicKeyHash := [20]byte{1, 2, 3}
+
+	mainUTXO := bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: bitcoin.Hash{4, 5, 6},
+			OutputIndex:     1,
+		},
+		Value: 100000,
+	}
+
+	zeroMainUTXO := bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: bitcoin.Hash{},
+			OutputIndex:     0,
+		},
+		Value: 0,
+	}
+
+	mainUtxoHash := newLocalChain().ComputeMainUtxoHash(&mainUTXO)
+
+	allowedStates := []tbtc.WalletState{tbtc.StateLive, tbtc.StateMovingFunds}
+
+	tests := map[string]struct {
+		walletState        tbtc.WalletState
+		walletMainUtxoHash [32]byte
+		mainUTXO           bitcoin.UnspentTransactionOutput
+		expectedResult     bool
+	}{
+		"live wallet with current main UTXO": {
+			walletState:        tbtc.StateLive,
+			walletMainUtxoHash: mainUtxoHash,
+			mainUTXO:           mainUTXO,
+			expectedResult:     true,
+		},
+		"moving funds wallet with current main UTXO": {
+			walletState:        tbtc.StateMovingFunds,
+			walletMainUtxoHash: mainUtxoHash,
+			mainUTXO:           mainUTXO,
+			expectedResult:     true,
+		},
+		"live wallet without main UTXO": {
+			walletState:    tbtc.StateLive,
+			mainUTXO:       zeroMainUTXO,
+			expectedResult: true,
+		},
+		"terminated wallet": {
+			walletState:        tbtc.StateTerminated,
+			walletMainUtxoHash: mainUtxoHash,
+			mainUTXO:           mainUTXO,
+			expectedResult:     false,
+		},
+		"closed wallet": {
+			walletState:        tbtc.StateClosed,
+			walletMainUtxoHash: mainUtxoHash,
+			mainUTXO:           mainUTXO,
+			expectedResult:     false,
+		},
+		"main UTXO already spent by a proven transaction": {
+			walletState:        tbtc.StateLive,
+			walletMainUtxoHash: [32]byte{7, 8, 9},
+			mainUTXO:           mainUTXO,
+			expectedResult:     false,
+		},
+		"wallet has main UTXO not spent by transaction": {
+			walletState:        tbtc.StateLive,
+			walletMainUtxoHash: mainUtxoHash,
+			mainUTXO:           zeroMainUTXO,
+			expectedResult:     false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := newLocalChain()
+			localChain.setWallet(walletPublicKeyHash, &tbtc.WalletChainData{
+				MainUtxoHash: test.walletMainUtxoHash,
+				State:        test.walletState,
+			})
+
+			result, err := isWalletReadyForProof(
+				walletPublicKeyHash,
+				test.mainUTXO,
+				allowedStates,
+				localChain,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"is wallet ready for proof",
+				test.expectedResult,
+				result,
+			)
+		})
+	}
+}
+
+func TestFallbackSpvProofAssembler(t *testing.T) {
+	primaryBtcChain := newLocalBitcoinChain()
+	fallbackBtcChain1 := newLocalBitcoinChain()
+	fallbackBtcChain2 := newLocalBitcoinChain()
+
+	transactionHash := bitcoin.Hash{1}
+	transaction := &bitcoin.Transaction{Locktime: 1}
+	proof := &bitcoin.SpvProof{CoinbasePreimage: [32]byte{2}}
+
+	tests := map[string]struct {
+		workingBtcChains []bitcoin.Chain
+		expectedAttempts []bitcoin.Chain
+		expectedErr      bool
+	}{
+		"primary backend works": {
+			workingBtcChains: []bitcoin.Chain{primaryBtcChain},
+			expectedAttempts: []bitcoin.Chain{primaryBtcChain},
+		},
+		"first fallback backend works": {
+			workingBtcChains: []bitcoin.Chain{fallbackBtcChain1},
+			expectedAttempts: []bitcoin.Chain{
+				primaryBtcChain,
+				fallbackBtcChain1,
+			},
+		},
+		"second fallback backend works": {
+			workingBtcChains: []bitcoin.Chain{fallbackBtcChain2},
+			expectedAttempts: []bitcoin.Chain{
+				primaryBtcChain,
+				fallbackBtcChain1,
+				fallbackBtcChain2,
+			},
+		},
+		"no backend works": {
+			workingBtcChains: []bitcoin.Chain{},
+			expectedAttempts: []bitcoin.Chain{
+				primaryBtcChain,
+				fallbackBtcChain1,
+				fallbackBtcChain2,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			attempts := []bitcoin.Chain{}
+
+			assembler := func(
+				transactionHash bitcoin.Hash,
+				requiredConfirmations uint,
+				btcChain bitcoin.Chain,
+			) (*bitcoin.Transaction, *bitcoin.SpvProof, error) {
+				attempts = append(attempts, btcChain)
+
+				for _, workingBtcChain := range test.workingBtcChains {
+					if btcChain == workingBtcChain {
+						return transaction, proof, nil
+					}
+				}
+
+				return nil, nil, fmt.Errorf("backend error")
+			}
+
+			fallbackAssembler := newFallbackSpvProofAssembler(
+				assembler,
+				[]bitcoin.Chain{fallbackBtcChain1, fallbackBtcChain2},
+			)
+
+			actualTransaction, actualProof, err := fallbackAssembler(
+				transactionHash,
+				6,
+				primaryBtcChain,
+			)
+
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(transaction, actualTransaction) {
+					t.Errorf("unexpected transaction")
+				}
+				if !reflect.DeepEqual(proof, actualProof) {
+					t.Errorf("unexpected proof")
+				}
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"attempts count",
+				len(test.expectedAttempts),
+				len(attempts),
+			)
+			for i := range test.expectedAttempts {
+				if attempts[i] != test.expectedAttempts[i] {
+					t.Errorf("unexpected backend used in attempt [%v]", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSpvMaintainer_MaintainSpv_ProofTypeFailure(t *testing.T) {
+	originalProofTypes := proofTypes
+	defer func() {
+		proofTypes = originalProofTypes
+	}()
+
+	localChain := newLocalChain()
+	blockCounter := newMockBlockCounter()
+	blockCounter.SetCurrentBlock(1000)
+	localChain.setBlockCounter(blockCounter)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	var mutex sync.Mutex
+	attemptedProofTypes := make(map[tbtc.WalletActionType]bool)
+
+	failingGetter := func(proofType tbtc.WalletActionType) unprovenTransactionsGetter {
+		return func(
+			startBlock uint64,
+			transactionLimit int,
+			provenTransactions *provenTransactionsCache,
+			btcChain bitcoin.Chain,
+			spvChain Chain,
+		) ([]*bitcoin.Transaction, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			attemptedProofTypes[proofType] = true
+			if len(attemptedProofTypes) == 2 {
+				cancelCtx()
+			}
+
+			return nil, fmt.Errorf("unavailable")
+		}
+	}
+
+	proofTypes = map[tbtc.WalletActionType]struct {
+		unprovenTransactionsGetter unprovenTransactionsGetter
+		proofRequirementChecker    proofRequirementChecker
+		transactionProofSubmitter  transactionProofSubmitter
+	}{
+		tbtc.ActionDepositSweep: {
+			unprovenTransactionsGetter: failingGetter(tbtc.ActionDepositSweep),
+		},
+		tbtc.ActionRedemption: {
+			unprovenTransactionsGetter: failingGetter(tbtc.ActionRedemption),
+		},
+	}
+
+	spvMaintainer := &spvMaintainer{
+		config: Config{
+			HistoryDepth:    100,
+			IdleBackoffTime: time.Hour,
+		},
+		spvChain:    localChain,
+		checkpoints: newCheckpointStore(newMockPersistenceHandle()),
+		rescanned:   make(map[tbtc.WalletActionType]bool),
+		metrics:     newMetrics(),
+		status:      newStatus(),
+
+		provenTransactions: newProvenTransactionsCache(
+			newMockPersistenceHandle(),
+		),
+	}
+
+	err := spvMaintainer.maintainSpv(ctx)
+	if err != context.Canceled {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"attempted proof types",
+		2,
+		len(attemptedProofTypes),
+	)
+}
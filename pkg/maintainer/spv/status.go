@@ -0,0 +1,112 @@
+package spv
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// proofTypeStatus holds the status of proving transactions of a single
+// proof type.
+type proofTypeStatus struct {
+	lastSuccessfulRound time.Time
+	backlog             int
+	lastError           string
+	lastErrorAt         time.Time
+}
+
+// status holds the status of the SPV maintainer exposed by the diagnostics
+// endpoint. It lets orchestration systems health-check the maintainer
+// without parsing its logs.
+type status struct {
+	mutex sync.Mutex
+
+	proofTypes map[tbtc.WalletActionType]*proofTypeStatus
+}
+
+func newStatus() *status {
+	return &status{
+		proofTypes: make(map[tbtc.WalletActionType]*proofTypeStatus),
+	}
+}
+
+// statusOf returns the status of the given proof type. Must be called
+// with the mutex held.
+func (s *status) statusOf(
+	proofType tbtc.WalletActionType,
+) *proofTypeStatus {
+	result, ok := s.proofTypes[proofType]
+	if !ok {
+		result = &proofTypeStatus{}
+		s.proofTypes[proofType] = result
+	}
+
+	return result
+}
+
+// recordBacklog records the number of unproven transactions of the given
+// proof type found in the current round.
+func (s *status) recordBacklog(proofType tbtc.WalletActionType, backlog int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.statusOf(proofType).backlog = backlog
+}
+
+// recordRound records the result of a round of proving transactions of the
+// given proof type completed at the given time.
+func (s *status) recordRound(
+	proofType tbtc.WalletActionType,
+	err error,
+	completedAt time.Time,
+) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	proofTypeStatus := s.statusOf(proofType)
+
+	if err != nil {
+		proofTypeStatus.lastError = err.Error()
+		proofTypeStatus.lastErrorAt = completedAt
+		return
+	}
+
+	proofTypeStatus.lastSuccessfulRound = completedAt
+}
+
+// info returns the status in the form exposed by the diagnostics endpoint.
+// The checkpoint blocks are read from the given checkpoint store.
+func (s *status) info(checkpoints *checkpointStore) clientinfo.ApplicationInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	formatTime := func(value time.Time) string {
+		if value.IsZero() {
+			return ""
+		}
+
+		return value.UTC().Format(time.RFC3339)
+	}
+
+	info := clientinfo.ApplicationInfo{}
+
+	for proofType := range proofTypes {
+		proofTypeStatus := s.statusOf(proofType)
+
+		checkpoint, _ := checkpoints.get(proofType.String())
+
+		info[proofType.String()] = map[string]interface{}{
+			"last_successful_round": formatTime(
+				proofTypeStatus.lastSuccessfulRound,
+			),
+			"checkpoint_block": checkpoint,
+			"backlog":          proofTypeStatus.backlog,
+			"last_error":       proofTypeStatus.lastError,
+			"last_error_at":    formatTime(proofTypeStatus.lastErrorAt),
+		}
+	}
+
+	return info
+}
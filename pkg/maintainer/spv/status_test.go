@@ -0,0 +1,69 @@
+package spv
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestStatus(t *testing.T) {
+	checkpoints := newCheckpointStore(newMockPersistenceHandle())
+	err := checkpoints.set(tbtc.ActionDepositSweep.String(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstRoundAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	secondRoundAt := firstRoundAt.Add(10 * time.Minute)
+
+	status := newStatus()
+
+	status.recordBacklog(tbtc.ActionDepositSweep, 3)
+	status.recordRound(tbtc.ActionDepositSweep, nil, firstRoundAt)
+
+	status.recordBacklog(tbtc.ActionRedemption, 2)
+	status.recordRound(tbtc.ActionRedemption, nil, firstRoundAt)
+	status.recordBacklog(tbtc.ActionRedemption, 1)
+	status.recordRound(
+		tbtc.ActionRedemption,
+		fmt.Errorf("failed to prove [1/1] transaction(s)"),
+		secondRoundAt,
+	)
+
+	expectedInfo := map[string]interface{}{
+		"DepositSweep": map[string]interface{}{
+			"last_successful_round": "2023-06-01T12:00:00Z",
+			"checkpoint_block":      uint64(1000),
+			"backlog":               3,
+			"last_error":            "",
+			"last_error_at":         "",
+		},
+		"Redemption": map[string]interface{}{
+			"last_successful_round": "2023-06-01T12:00:00Z",
+			"checkpoint_block":      uint64(0),
+			"backlog":               1,
+			"last_error":            "failed to prove [1/1] transaction(s)",
+			"last_error_at":         "2023-06-01T12:10:00Z",
+		},
+		"MovingFunds": map[string]interface{}{
+			"last_successful_round": "",
+			"checkpoint_block":      uint64(0),
+			"backlog":               0,
+			"last_error":            "",
+			"last_error_at":         "",
+		},
+	}
+
+	info := status.info(checkpoints)
+
+	if !reflect.DeepEqual(expectedInfo, map[string]interface{}(info)) {
+		t.Errorf(
+			"unexpected status info\nexpected: %v\nactual:   %v",
+			expectedInfo,
+			info,
+		)
+	}
+}
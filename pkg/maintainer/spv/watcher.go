@@ -0,0 +1,112 @@
+package spv
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// confirmationWatchTimeout is the maximum time a transaction is watched for
+// accumulating the confirmations required by its proof.
+const confirmationWatchTimeout = 24 * time.Hour
+
+// confirmationWatcher watches transactions skipped by the SPV maintainer due
+// to insufficient confirmations. Once any of them accumulates the required
+// number of confirmations, the maintainer is woken up so the proof can be
+// submitted without waiting for the idle backoff to elapse.
+type confirmationWatcher struct {
+	ctx      context.Context
+	btcChain bitcoin.Chain
+
+	mutex   sync.Mutex
+	watched map[bitcoin.Hash]bool
+
+	wakeUpChan chan struct{}
+}
+
+func newConfirmationWatcher(
+	ctx context.Context,
+	btcChain bitcoin.Chain,
+) *confirmationWatcher {
+	return &confirmationWatcher{
+		ctx:        ctx,
+		btcChain:   btcChain,
+		watched:    make(map[bitcoin.Hash]bool),
+		wakeUpChan: make(chan struct{}, 1),
+	}
+}
+
+// watch starts watching the given transaction until it accumulates the
+// required number of confirmations. Transactions that are already watched
+// are ignored.
+func (cw *confirmationWatcher) watch(
+	transaction *bitcoin.Transaction,
+	requiredConfirmations uint,
+) {
+	transactionHash := transaction.Hash()
+
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+
+	if cw.watched[transactionHash] {
+		return
+	}
+
+	watchCtx, cancelWatchCtx := context.WithTimeout(
+		cw.ctx,
+		confirmationWatchTimeout,
+	)
+
+	reachedChan, err := bitcoin.WatchConfirmations(
+		watchCtx,
+		cw.btcChain,
+		transaction,
+		requiredConfirmations,
+	)
+	if err != nil {
+		cancelWatchCtx()
+		logger.Warnf(
+			"cannot watch transaction [%s]: [%v]",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			err,
+		)
+		return
+	}
+
+	cw.watched[transactionHash] = true
+
+	go func() {
+		defer func() {
+			cancelWatchCtx()
+
+			cw.mutex.Lock()
+			delete(cw.watched, transactionHash)
+			cw.mutex.Unlock()
+		}()
+
+		confirmations, ok := <-reachedChan
+		if !ok {
+			return
+		}
+
+		logger.Infof(
+			"transaction [%s] accumulated [%v] confirmations; "+
+				"waking up SPV maintainer",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			confirmations,
+		)
+
+		select {
+		case cw.wakeUpChan <- struct{}{}:
+		default:
+		}
+	}()
+}
+
+// wakeUps returns a channel receiving a value each time a watched transaction
+// accumulates the required number of confirmations.
+func (cw *confirmationWatcher) wakeUps() <-chan struct{} {
+	return cw.wakeUpChan
+}
@@ -0,0 +1,37 @@
+package spv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestConfirmationWatcher(t *testing.T) {
+	btcChain := newLocalBitcoinChain()
+
+	transaction := &bitcoin.Transaction{Version: 1}
+	btcChain.transactionConfirmations[transaction.Hash()] = 6
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	watcher := newConfirmationWatcher(ctx, btcChain)
+
+	watcher.watch(transaction, 6)
+	// Watching the same transaction again should be a no-op.
+	watcher.watch(transaction, 6)
+
+	select {
+	case <-watcher.wakeUps():
+	case <-time.After(time.Second):
+		t.Fatal("expected wake up")
+	}
+
+	select {
+	case <-watcher.wakeUps():
+		t.Fatal("unexpected wake up")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -51,3 +51,27 @@ func (m *basicMessage) SenderPublicKey() []byte {
 func (m *basicMessage) Seqno() uint64 {
 	return m.seqno
 }
+
+// SignedMessage returns the given message along with the envelope signed by
+// its sender. The envelope is marshaled with the provided function only once
+// it is requested.
+func SignedMessage(
+	message net.Message,
+	envelopeFn func() ([]byte, error),
+) net.SignedMessage {
+	return &signedMessage{
+		Message:    message,
+		envelopeFn: envelopeFn,
+	}
+}
+
+// signedMessage is an implementation of the net.SignedMessage interface
+// wrapping a net.Message.
+type signedMessage struct {
+	net.Message
+	envelopeFn func() ([]byte, error)
+}
+
+func (m *signedMessage) SignedEnvelope() ([]byte, error) {
+	return m.envelopeFn()
+}
@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+// ErrUnsupportedVersion is returned when a message of a format version not
+// supported by the registered unmarshaler is unmarshaled.
+var ErrUnsupportedVersion = errors.New("unsupported message version")
+
+// MessageVersion returns the format version of the given message. Messages
+// not implementing net.VersionedMarshaler are of version zero.
+func MessageVersion(message net.TaggedMarshaler) uint32 {
+	if versioned, ok := message.(net.VersionedMarshaler); ok {
+		return versioned.Version()
+	}
+
+	return 0
+}
+
+// UnmarshalVersion unmarshals the payload of the given format version with
+// the given unmarshaler. Unmarshalers not implementing
+// net.VersionedUnmarshaler support version zero only. ErrUnsupportedVersion
+// is returned if the version is not supported by the unmarshaler.
+func UnmarshalVersion(
+	unmarshaler net.TaggedUnmarshaler,
+	version uint32,
+	payload []byte,
+) error {
+	versioned, ok := unmarshaler.(net.VersionedUnmarshaler)
+	if !ok {
+		if version != 0 {
+			return fmt.Errorf(
+				"%w [%v] for type [%s]; supported: [0]",
+				ErrUnsupportedVersion,
+				version,
+				unmarshaler.Type(),
+			)
+		}
+
+		return unmarshaler.Unmarshal(payload)
+	}
+
+	minVersion, maxVersion := versioned.SupportedVersions()
+	if version < minVersion || version > maxVersion {
+		return fmt.Errorf(
+			"%w [%v] for type [%s]; supported: [%v-%v]",
+			ErrUnsupportedVersion,
+			version,
+			unmarshaler.Type(),
+			minVersion,
+			maxVersion,
+		)
+	}
+
+	return versioned.UnmarshalVersion(version, payload)
+}
@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMessageVersion(t *testing.T) {
+	if version := MessageVersion(&unversionedMessage{}); version != 0 {
+		t.Errorf(
+			"unexpected version of unversioned message\n"+
+				"expected: [0]\nactual:   [%v]",
+			version,
+		)
+	}
+
+	if version := MessageVersion(&versionedMessage{version: 2}); version != 2 {
+		t.Errorf(
+			"unexpected version of versioned message\n"+
+				"expected: [2]\nactual:   [%v]",
+			version,
+		)
+	}
+}
+
+func TestUnmarshalVersion(t *testing.T) {
+	var tests = map[string]struct {
+		unmarshaler     func() unmarshalRecorder
+		version         uint32
+		expectedVersion uint32
+		expectedError   error
+	}{
+		"unversioned message of version zero": {
+			unmarshaler:     func() unmarshalRecorder { return &unversionedMessage{} },
+			version:         0,
+			expectedVersion: 0,
+		},
+		"unversioned message of non-zero version": {
+			unmarshaler: func() unmarshalRecorder { return &unversionedMessage{} },
+			version:     1,
+			expectedError: fmt.Errorf(
+				"unsupported message version [1] for type [test/unversioned]; " +
+					"supported: [0]",
+			),
+		},
+		"versioned message of the lowest supported version": {
+			unmarshaler:     func() unmarshalRecorder { return &versionedMessage{} },
+			version:         1,
+			expectedVersion: 1,
+		},
+		"versioned message of the highest supported version": {
+			unmarshaler:     func() unmarshalRecorder { return &versionedMessage{} },
+			version:         2,
+			expectedVersion: 2,
+		},
+		"versioned message of too low version": {
+			unmarshaler: func() unmarshalRecorder { return &versionedMessage{} },
+			version:     0,
+			expectedError: fmt.Errorf(
+				"unsupported message version [0] for type [test/versioned]; " +
+					"supported: [1-2]",
+			),
+		},
+		"versioned message of too high version": {
+			unmarshaler: func() unmarshalRecorder { return &versionedMessage{} },
+			version:     3,
+			expectedError: fmt.Errorf(
+				"unsupported message version [3] for type [test/versioned]; " +
+					"supported: [1-2]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			unmarshaler := test.unmarshaler()
+
+			err := UnmarshalVersion(unmarshaler, test.version, []byte("payload"))
+			if !reflect.DeepEqual(
+				fmt.Sprintf("%v", test.expectedError),
+				fmt.Sprintf("%v", err),
+			) {
+				t.Fatalf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+
+			if err != nil {
+				if !errors.Is(err, ErrUnsupportedVersion) {
+					t.Errorf("expected unsupported version error")
+				}
+				return
+			}
+
+			payload, version := unmarshaler.unmarshaled()
+			if payload != "payload" {
+				t.Errorf(
+					"unexpected payload\nexpected: [payload]\nactual:   [%v]",
+					payload,
+				)
+			}
+			if version != test.expectedVersion {
+				t.Errorf(
+					"unexpected version\nexpected: [%v]\nactual:   [%v]",
+					test.expectedVersion,
+					version,
+				)
+			}
+		})
+	}
+}
+
+type unmarshalRecorder interface {
+	Type() string
+	Unmarshal(bytes []byte) error
+	unmarshaled() (string, uint32)
+}
+
+type unversionedMessage struct {
+	payload string
+}
+
+func (um *unversionedMessage) Type() string {
+	return "test/unversioned"
+}
+
+func (um *unversionedMessage) Marshal() ([]byte, error) {
+	return []byte(um.payload), nil
+}
+
+func (um *unversionedMessage) Unmarshal(bytes []byte) error {
+	um.payload = string(bytes)
+	return nil
+}
+
+func (um *unversionedMessage) unmarshaled() (string, uint32) {
+	return um.payload, 0
+}
+
+type versionedMessage struct {
+	payload string
+	version uint32
+}
+
+func (vm *versionedMessage) Type() string {
+	return "test/versioned"
+}
+
+func (vm *versionedMessage) Marshal() ([]byte, error) {
+	return []byte(vm.payload), nil
+}
+
+func (vm *versionedMessage) Unmarshal(bytes []byte) error {
+	return fmt.Errorf("version must be provided")
+}
+
+func (vm *versionedMessage) Version() uint32 {
+	return vm.version
+}
+
+func (vm *versionedMessage) SupportedVersions() (uint32, uint32) {
+	return 1, 2
+}
+
+func (vm *versionedMessage) UnmarshalVersion(version uint32, bytes []byte) error {
+	vm.payload = string(bytes)
+	vm.version = version
+	return nil
+}
+
+func (vm *versionedMessage) unmarshaled() (string, uint32) {
+	return vm.payload, vm.version
+}
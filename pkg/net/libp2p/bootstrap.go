@@ -37,6 +37,11 @@ type BootstrapConfig struct {
 	// for the bootstrap process to use. This makes it possible for clients
 	// to control the peers the process uses at any moment.
 	BootstrapPeers func() []peer.AddrInfo
+
+	// RoundCompleted is an optional function called with the result of
+	// every bootstrap round. The error is nil if the round succeeded or
+	// was skipped.
+	RoundCompleted func(err error)
 }
 
 // DefaultBootstrapConfig specifies default sane parameters for bootstrapping.
@@ -45,14 +50,6 @@ var DefaultBootstrapConfig = BootstrapConfig{
 	ConnectionTimeout: (30 * time.Second) / 3, // Perod / 3
 }
 
-func bootstrapConfigWithPeers(pis []peer.AddrInfo) BootstrapConfig {
-	cfg := DefaultBootstrapConfig
-	cfg.BootstrapPeers = func() []peer.AddrInfo {
-		return pis
-	}
-	return cfg
-}
-
 // Bootstrap kicks off bootstrapping. This function will periodically
 // check the number of open connections and -- if there are too few -- initiate
 // connections to well-known bootstrap peers. It also kicks off subsystem
@@ -70,10 +67,15 @@ func Bootstrap(
 	periodic := func(worker goprocess.Process) {
 		ctx := goprocessctx.OnClosingContext(worker)
 
-		if err := bootstrapRound(ctx, host, cfg); err != nil {
+		err := bootstrapRound(ctx, host, cfg)
+		if err != nil {
 			logger.Warnf("bootstrap round error: [%v]", err)
 		}
 
+		if cfg.RoundCompleted != nil {
+			cfg.RoundCompleted(err)
+		}
+
 		<-doneWithRound
 	}
 
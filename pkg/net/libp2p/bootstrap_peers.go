@@ -0,0 +1,183 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// DefaultBootstrapRefreshPeriod is the default period at which bootstrap
+// peers are resolved again from DNS seeds.
+const DefaultBootstrapRefreshPeriod = 30 * time.Minute
+
+// dnsSeedResolveTimeout is the maximum time of resolving a single DNS seed.
+const dnsSeedResolveTimeout = 30 * time.Second
+
+// dnsSeedResolver resolves multiaddresses containing DNS components.
+type dnsSeedResolver interface {
+	Resolve(ctx context.Context, maddr ma.Multiaddr) ([]ma.Multiaddr, error)
+}
+
+// bootstrapPeerSource maintains the set of bootstrap peers. The set consists
+// of static peers from the config and peers resolved from DNS seeds. A DNS
+// seed is a domain with `dnsaddr` TXT records listing bootstrap peer
+// multiaddresses, e.g. `_dnsaddr.bootstrap.example.com` with a record
+// `dnsaddr=/ip4/80.70.60.50/tcp/3919/ipfs/<peer id>`. Seeds are resolved
+// periodically so the set follows changes of the seed records and peers
+// removed from the records are rotated out.
+type bootstrapPeerSource struct {
+	ownID       peer.ID
+	staticPeers []peer.AddrInfo
+	dnsSeeds    []ma.Multiaddr
+	resolver    dnsSeedResolver
+
+	seedPeersMutex sync.RWMutex
+	seedPeers      map[string][]peer.AddrInfo
+}
+
+func newBootstrapPeerSource(
+	ownID peer.ID,
+	staticPeers []string,
+	dnsSeeds []string,
+	resolver dnsSeedResolver,
+) (*bootstrapPeerSource, error) {
+	staticPeerInfos, err := extractMultiAddrFromPeers(staticPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	seedAddresses, err := dnsSeedsToMultiaddrs(dnsSeeds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bootstrapPeerSource{
+		ownID:       ownID,
+		staticPeers: staticPeerInfos,
+		dnsSeeds:    seedAddresses,
+		resolver:    resolver,
+		seedPeers:   make(map[string][]peer.AddrInfo),
+	}, nil
+}
+
+func dnsSeedsToMultiaddrs(dnsSeeds []string) ([]ma.Multiaddr, error) {
+	addresses := make([]ma.Multiaddr, len(dnsSeeds))
+	for i, dnsSeed := range dnsSeeds {
+		address, err := ma.NewMultiaddr("/dnsaddr/" + dnsSeed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DNS seed [%v]: [%v]", dnsSeed, err)
+		}
+
+		addresses[i] = address
+	}
+
+	return addresses, nil
+}
+
+// peers returns the current set of bootstrap peers. The client's own address
+// is filtered out to prevent self-dialing.
+func (bps *bootstrapPeerSource) peers() []peer.AddrInfo {
+	bps.seedPeersMutex.RLock()
+	defer bps.seedPeersMutex.RUnlock()
+
+	all := make([]peer.AddrInfo, 0, len(bps.staticPeers))
+	all = append(all, bps.staticPeers...)
+	for _, seedPeers := range bps.seedPeers {
+		all = append(all, seedPeers...)
+	}
+
+	filtered := make([]peer.AddrInfo, 0, len(all))
+	for _, peerInfo := range peers(all).toPeerInfos() {
+		if peerInfo.ID != bps.ownID {
+			filtered = append(filtered, peerInfo)
+		}
+	}
+
+	return filtered
+}
+
+// refresh resolves all DNS seeds and replaces peers of each seed with the
+// resolved ones. If a seed cannot be resolved, its previously resolved peers
+// are kept.
+func (bps *bootstrapPeerSource) refresh(ctx context.Context) {
+	for _, dnsSeed := range bps.dnsSeeds {
+		seedPeers, err := bps.resolve(ctx, dnsSeed)
+		if err != nil {
+			logger.Warnf("could not resolve DNS seed [%v]: [%v]", dnsSeed, err)
+			continue
+		}
+
+		logger.Debugf(
+			"resolved [%v] bootstrap peers from DNS seed [%v]",
+			len(seedPeers),
+			dnsSeed,
+		)
+
+		bps.seedPeersMutex.Lock()
+		bps.seedPeers[dnsSeed.String()] = seedPeers
+		bps.seedPeersMutex.Unlock()
+	}
+}
+
+func (bps *bootstrapPeerSource) resolve(
+	ctx context.Context,
+	dnsSeed ma.Multiaddr,
+) ([]peer.AddrInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, dnsSeedResolveTimeout)
+	defer cancel()
+
+	addresses, err := bps.resolver.Resolve(ctx, dnsSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	seedPeers := make([]peer.AddrInfo, 0, len(addresses))
+	for _, address := range addresses {
+		peerInfo, err := peer.AddrInfoFromP2pAddr(address)
+		if err != nil {
+			logger.Warnf(
+				"skipping address [%v] of DNS seed [%v]: [%v]",
+				address,
+				dnsSeed,
+				err,
+			)
+			continue
+		}
+
+		seedPeers = append(seedPeers, *peerInfo)
+	}
+
+	if len(seedPeers) == 0 {
+		return nil, fmt.Errorf("no bootstrap peers found")
+	}
+
+	return seedPeers, nil
+}
+
+// start resolves DNS seeds and keeps resolving them again with the given
+// period until the context is done.
+func (bps *bootstrapPeerSource) start(ctx context.Context, period time.Duration) {
+	if len(bps.dnsSeeds) == 0 {
+		return
+	}
+
+	bps.refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				bps.refresh(ctx)
+			}
+		}
+	}()
+}
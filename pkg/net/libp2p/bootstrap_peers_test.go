@@ -0,0 +1,224 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+func TestBootstrapPeerSource_Peers(t *testing.T) {
+	ownID := generateTestIdentity(t).id
+	staticID := generateTestIdentity(t).id
+	seedID := generateTestIdentity(t).id
+
+	resolver := newMockDNSSeedResolver()
+	resolver.setResult(
+		"/dnsaddr/bootstrap.example.com",
+		[]string{
+			fmt.Sprintf("/ip4/80.70.60.50/tcp/3919/ipfs/%v", seedID),
+			// The same peer is listed by the static peers; its addresses
+			// are merged.
+			fmt.Sprintf("/ip4/80.70.60.51/tcp/3919/ipfs/%v", staticID),
+			// Own address is filtered out.
+			fmt.Sprintf("/ip4/80.70.60.52/tcp/3919/ipfs/%v", ownID),
+			// Addresses without peer ID are skipped.
+			"/ip4/80.70.60.53/tcp/3919",
+		},
+		nil,
+	)
+
+	source, err := newBootstrapPeerSource(
+		ownID,
+		[]string{fmt.Sprintf("/ip4/127.0.0.1/tcp/3919/ipfs/%v", staticID)},
+		[]string{"bootstrap.example.com"},
+		resolver,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertBootstrapPeers(
+		t,
+		map[peer.ID][]string{
+			staticID: {"/ip4/127.0.0.1/tcp/3919"},
+		},
+		source.peers(),
+	)
+
+	source.refresh(context.Background())
+
+	assertBootstrapPeers(
+		t,
+		map[peer.ID][]string{
+			staticID: {"/ip4/127.0.0.1/tcp/3919", "/ip4/80.70.60.51/tcp/3919"},
+			seedID:   {"/ip4/80.70.60.50/tcp/3919"},
+		},
+		source.peers(),
+	)
+}
+
+func TestBootstrapPeerSource_Refresh(t *testing.T) {
+	ownID := generateTestIdentity(t).id
+	seedID1 := generateTestIdentity(t).id
+	seedID2 := generateTestIdentity(t).id
+
+	resolver := newMockDNSSeedResolver()
+	resolver.setResult(
+		"/dnsaddr/bootstrap.example.com",
+		[]string{fmt.Sprintf("/ip4/80.70.60.50/tcp/3919/ipfs/%v", seedID1)},
+		nil,
+	)
+
+	source, err := newBootstrapPeerSource(
+		ownID,
+		[]string{},
+		[]string{"bootstrap.example.com"},
+		resolver,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source.refresh(context.Background())
+
+	assertBootstrapPeers(
+		t,
+		map[peer.ID][]string{
+			seedID1: {"/ip4/80.70.60.50/tcp/3919"},
+		},
+		source.peers(),
+	)
+
+	// The seed records change; peers removed from the records are rotated out.
+	resolver.setResult(
+		"/dnsaddr/bootstrap.example.com",
+		[]string{fmt.Sprintf("/ip4/80.70.60.51/tcp/3919/ipfs/%v", seedID2)},
+		nil,
+	)
+
+	source.refresh(context.Background())
+
+	assertBootstrapPeers(
+		t,
+		map[peer.ID][]string{
+			seedID2: {"/ip4/80.70.60.51/tcp/3919"},
+		},
+		source.peers(),
+	)
+
+	// The seed cannot be resolved; previously resolved peers are kept.
+	resolver.setResult(
+		"/dnsaddr/bootstrap.example.com",
+		nil,
+		fmt.Errorf("lookup failed"),
+	)
+
+	source.refresh(context.Background())
+
+	assertBootstrapPeers(
+		t,
+		map[peer.ID][]string{
+			seedID2: {"/ip4/80.70.60.51/tcp/3919"},
+		},
+		source.peers(),
+	)
+}
+
+func TestNewBootstrapPeerSource_InvalidDNSSeed(t *testing.T) {
+	_, err := newBootstrapPeerSource(
+		generateTestIdentity(t).id,
+		[]string{},
+		[]string{"bootstrap.example.com/tcp"},
+		newMockDNSSeedResolver(),
+	)
+
+	expectedErr := fmt.Errorf(
+		"invalid DNS seed [bootstrap.example.com/tcp]: " +
+			"[failed to parse multiaddr \"/dnsaddr/bootstrap.example.com/tcp\": " +
+			"unexpected end of multiaddr]",
+	)
+	if err == nil || err.Error() != expectedErr.Error() {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func assertBootstrapPeers(
+	t *testing.T,
+	expected map[peer.ID][]string,
+	actual []peer.AddrInfo,
+) {
+	actualMap := make(map[peer.ID][]string)
+	for _, peerInfo := range actual {
+		addresses := make([]string, len(peerInfo.Addrs))
+		for i, address := range peerInfo.Addrs {
+			addresses[i] = address.String()
+		}
+		sort.Strings(addresses)
+		actualMap[peerInfo.ID] = addresses
+	}
+
+	if !reflect.DeepEqual(expected, actualMap) {
+		t.Errorf(
+			"unexpected bootstrap peers\nexpected: [%v]\nactual:   [%v]",
+			expected,
+			actualMap,
+		)
+	}
+}
+
+type mockDNSSeedResult struct {
+	addresses []ma.Multiaddr
+	err       error
+}
+
+type mockDNSSeedResolver struct {
+	mutex   sync.Mutex
+	results map[string]mockDNSSeedResult
+}
+
+func newMockDNSSeedResolver() *mockDNSSeedResolver {
+	return &mockDNSSeedResolver{
+		results: make(map[string]mockDNSSeedResult),
+	}
+}
+
+func (mdsr *mockDNSSeedResolver) setResult(
+	dnsSeed string,
+	addresses []string,
+	err error,
+) {
+	mdsr.mutex.Lock()
+	defer mdsr.mutex.Unlock()
+
+	result := mockDNSSeedResult{err: err}
+	for _, address := range addresses {
+		result.addresses = append(result.addresses, ma.StringCast(address))
+	}
+
+	mdsr.results[dnsSeed] = result
+}
+
+func (mdsr *mockDNSSeedResolver) Resolve(
+	ctx context.Context,
+	maddr ma.Multiaddr,
+) ([]ma.Multiaddr, error) {
+	mdsr.mutex.Lock()
+	defer mdsr.mutex.Unlock()
+
+	result, ok := mdsr.results[maddr.String()]
+	if !ok {
+		return nil, fmt.Errorf("unknown DNS seed [%v]", maddr)
+	}
+
+	return result.addresses, result.err
+}
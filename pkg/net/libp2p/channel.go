@@ -2,6 +2,7 @@ package libp2p
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
@@ -31,6 +32,10 @@ const (
 	messageHandlerThrottle  = 512
 )
 
+// errNoUnmarshaler is returned when a message of a type without a registered
+// unmarshaler is processed.
+var errNoUnmarshaler = errors.New("couldn't find unmarshaler")
+
 type validator interface {
 	RegisterTopicValidator(
 		topic string,
@@ -59,6 +64,8 @@ type channel struct {
 
 	validatorMutex sync.Mutex
 	validator      validator
+	filter         net.BroadcastChannelFilter
+	validators     []net.BroadcastChannelValidator
 
 	publisherMutex sync.Mutex
 	publisher      publisher
@@ -73,6 +80,16 @@ type channel struct {
 	unmarshalersByType map[string]func() net.TaggedUnmarshaler
 
 	retransmissionTicker *retransmission.Ticker
+	// retransmissionCacheSize limits the number of messages remembered by
+	// each message handler to filter out retransmissions. Zero means no
+	// limit.
+	retransmissionCacheSize int
+
+	compressor *payloadCompressor
+
+	// messagesTracer meters messages published to the channel. Nil if
+	// messages are not metered.
+	messagesTracer *topicMessagesTracer
 }
 
 type messageHandler struct {
@@ -143,7 +160,10 @@ func (c *channel) Recv(ctx context.Context, handler func(m net.Message)) {
 	c.messageHandlers = append(c.messageHandlers, messageHandler)
 	c.messageHandlersMutex.Unlock()
 
-	handleWithRetransmissions := retransmission.WithRetransmissionSupport(handler)
+	handleWithRetransmissions := retransmission.WithBoundedRetransmissionSupport(
+		handler,
+		c.retransmissionCacheSize,
+	)
 
 	// A separate goroutine controls the lifecycle of the handler. The message
 	// handler is removed from the channel if the context is done. This logic is
@@ -223,10 +243,14 @@ func (c *channel) messageProto(
 		return nil, err
 	}
 
+	payloadBytes, compression := c.compressor.compress(payloadBytes)
+
 	return &pb.BroadcastNetworkMessage{
-		Payload: payloadBytes,
-		Sender:  senderIdentityBytes,
-		Type:    []byte(message.Type()),
+		Payload:     payloadBytes,
+		Sender:      senderIdentityBytes,
+		Type:        []byte(message.Type()),
+		Compression: compression,
+		Version:     internal.MessageVersion(message),
 	}, nil
 }
 
@@ -239,7 +263,15 @@ func (c *channel) publish(message *pb.BroadcastNetworkMessage) error {
 	c.publisherMutex.Lock()
 	defer c.publisherMutex.Unlock()
 
-	return c.publisher.Publish(context.TODO(), messageBytes)
+	if err := c.publisher.Publish(context.TODO(), messageBytes); err != nil {
+		return err
+	}
+
+	if c.messagesTracer != nil {
+		c.messagesTracer.messageSent(c.name)
+	}
+
+	return nil
 }
 
 func (c *channel) handleMessages(ctx context.Context) {
@@ -290,40 +322,85 @@ func (c *channel) incomingMessageWorker(ctx context.Context) {
 }
 
 func (c *channel) processPubsubMessage(pubsubMessage *pubsub.Message) error {
+	// The message has already been unmarshaled by the topic validator.
+	if netMessage, ok := pubsubMessage.ValidatorData.(net.Message); ok {
+		c.deliver(netMessage)
+		return nil
+	}
+
 	var messageProto pb.BroadcastNetworkMessage
 	if err := proto.Unmarshal(pubsubMessage.Data, &messageProto); err != nil {
 		return err
 	}
 
-	return c.processContainerMessage(pubsubMessage.GetFrom(), &messageProto)
+	return c.processContainerMessage(pubsubMessage, &messageProto)
 }
 
 func (c *channel) processContainerMessage(
-	proposedSender peer.ID,
+	pubsubMessage *pubsub.Message,
 	message *pb.BroadcastNetworkMessage,
 ) error {
+	netMessage, err := c.unmarshalContainerMessage(
+		pubsubMessage.GetFrom(),
+		message,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.deliver(withSignedEnvelope(netMessage, pubsubMessage))
+
+	return nil
+}
+
+// withSignedEnvelope attaches the pubsub message signed by the sender to
+// the given message unmarshaled from it.
+func withSignedEnvelope(
+	netMessage net.Message,
+	pubsubMessage *pubsub.Message,
+) net.Message {
+	if pubsubMessage.Message == nil {
+		return netMessage
+	}
+
+	return internal.SignedMessage(netMessage, pubsubMessage.Message.Marshal)
+}
+
+func (c *channel) unmarshalContainerMessage(
+	proposedSender peer.ID,
+	message *pb.BroadcastNetworkMessage,
+) (net.Message, error) {
 	// The protocol type is on the envelope; let's pull that type
 	// from our map of unmarshallers.
 	unmarshaled, err := c.getUnmarshalingContainerByType(string(message.Type))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := unmarshaled.Unmarshal(message.GetPayload()); err != nil {
-		return err
+	payload, err := c.compressor.decompress(
+		message.GetPayload(),
+		message.GetCompression(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = internal.UnmarshalVersion(unmarshaled, message.GetVersion(), payload)
+	if err != nil {
+		return nil, err
 	}
 
 	// Construct an identifier from the sender.
 	senderIdentifier := &identity{}
 	if err := senderIdentifier.Unmarshal(message.Sender); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Ensure the sender wasn't tampered by:
 	//     Test that the proposed sender (outer layer) matches the
 	//     sender identifier we grab from the message (inner layer).
 	if proposedSender != senderIdentifier.id {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"outer layer sender [%v] does not match inner layer sender [%v]",
 			proposedSender,
 			senderIdentifier,
@@ -332,7 +409,7 @@ func (c *channel) processContainerMessage(
 
 	operatorPublicKey, err := networkPublicKeyToOperatorPublicKey(senderIdentifier.pubKey)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"sender [%v] with key [%v] is not of correct type",
 			senderIdentifier.id,
 			senderIdentifier.pubKey,
@@ -341,17 +418,13 @@ func (c *channel) processContainerMessage(
 
 	operatorPublicKeyBytes := operator.MarshalUncompressed(operatorPublicKey)
 
-	netMessage := internal.BasicMessage(
+	return internal.BasicMessage(
 		senderIdentifier.id,
 		unmarshaled,
 		string(message.Type),
 		operatorPublicKeyBytes,
 		message.SequenceNumber,
-	)
-
-	c.deliver(netMessage)
-
-	return nil
+	), nil
 }
 
 func (c *channel) getUnmarshalingContainerByType(messageType string) (net.TaggedUnmarshaler, error) {
@@ -361,7 +434,8 @@ func (c *channel) getUnmarshalingContainerByType(messageType string) (net.Tagged
 	unmarshaler, found := c.unmarshalersByType[messageType]
 	if !found {
 		return nil, fmt.Errorf(
-			"couldn't find unmarshaler for type [%s]",
+			"%w for type [%s]",
+			errNoUnmarshaler,
 			messageType,
 		)
 	}
@@ -388,10 +462,27 @@ func (c *channel) SetFilter(filter net.BroadcastChannelFilter) error {
 	c.validatorMutex.Lock()
 	defer c.validatorMutex.Unlock()
 
+	c.filter = filter
+
+	return c.registerTopicValidator()
+}
+
+func (c *channel) AddValidator(validator net.BroadcastChannelValidator) error {
+	c.validatorMutex.Lock()
+	defer c.validatorMutex.Unlock()
+
+	c.validators = append(c.validators, validator)
+
+	return c.registerTopicValidator()
+}
+
+// registerTopicValidator replaces the topic validator with one running the
+// current filter and validators. Must be called with validatorMutex held.
+func (c *channel) registerTopicValidator() error {
 	err := c.validator.UnregisterTopicValidator(c.name)
 	if err != nil {
-		// That error can occur when the filter is set for the first time
-		// and no prior filter exists.
+		// That error can occur when the validator is registered for the
+		// first time and no prior validator exists.
 		logger.Debugf(
 			"could not unregister topic validator for channel [%v]: [%v]",
 			c.name,
@@ -399,7 +490,101 @@ func (c *channel) SetFilter(filter net.BroadcastChannelFilter) error {
 		)
 	}
 
-	return c.validator.RegisterTopicValidator(c.name, createTopicValidator(filter))
+	if len(c.validators) == 0 {
+		return c.validator.RegisterTopicValidator(
+			c.name,
+			createTopicValidator(c.filter),
+		)
+	}
+
+	validators := make([]net.BroadcastChannelValidator, len(c.validators))
+	copy(validators, c.validators)
+
+	return c.validator.RegisterTopicValidator(
+		c.name,
+		c.createPayloadValidator(c.filter, validators),
+	)
+}
+
+// createPayloadValidator creates a topic validator running the filter, if
+// set, and unmarshaling the message to run the validators. Unmarshaled
+// messages are passed to the subscription so that they are not unmarshaled
+// again before being delivered.
+func (c *channel) createPayloadValidator(
+	filter net.BroadcastChannelFilter,
+	validators []net.BroadcastChannelValidator,
+) pubsub.ValidatorEx {
+	var filterValidator pubsub.Validator
+	if filter != nil {
+		filterValidator = createTopicValidator(filter)
+	}
+
+	return func(
+		ctx context.Context,
+		from peer.ID,
+		message *pubsub.Message,
+	) pubsub.ValidationResult {
+		if filterValidator != nil && !filterValidator(ctx, from, message) {
+			return pubsub.ValidationReject
+		}
+
+		var messageProto pb.BroadcastNetworkMessage
+		if err := proto.Unmarshal(message.Data, &messageProto); err != nil {
+			logger.Warnf(
+				"rejecting malformed message from [%v] in channel [%v]: [%v]",
+				message.GetFrom(),
+				c.name,
+				err,
+			)
+			return pubsub.ValidationReject
+		}
+
+		netMessage, err := c.unmarshalContainerMessage(
+			message.GetFrom(),
+			&messageProto,
+		)
+		if errors.Is(err, errNoUnmarshaler) ||
+			errors.Is(err, internal.ErrUnsupportedVersion) {
+			// The message may be valid but it cannot be validated so it is
+			// dropped without penalizing the peer that relayed it. Messages
+			// of unsupported versions may come from peers running newer
+			// client versions.
+			logger.Debugf(
+				"ignoring message from [%v] in channel [%v]: [%v]",
+				message.GetFrom(),
+				c.name,
+				err,
+			)
+			return pubsub.ValidationIgnore
+		}
+		if err != nil {
+			logger.Warnf(
+				"rejecting malformed message from [%v] in channel [%v]: [%v]",
+				message.GetFrom(),
+				c.name,
+				err,
+			)
+			return pubsub.ValidationReject
+		}
+
+		for _, validator := range validators {
+			if err := validator(netMessage); err != nil {
+				logger.Warnf(
+					"rejecting invalid message of type [%v] from [%v] "+
+						"in channel [%v]: [%v]",
+					netMessage.Type(),
+					message.GetFrom(),
+					c.name,
+					err,
+				)
+				return pubsub.ValidationReject
+			}
+		}
+
+		message.ValidatorData = withSignedEnvelope(netMessage, message)
+
+		return pubsub.ValidationAccept
+	}
 }
 
 func createTopicValidator(filter net.BroadcastChannelFilter) pubsub.Validator {
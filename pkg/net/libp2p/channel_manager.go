@@ -9,25 +9,14 @@ import (
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/net/retransmission"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
-	pubsubtc "github.com/libp2p/go-libp2p-pubsub/timecache"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 )
 
 const (
 	libp2pPeerOutboundQueueSize = 256
 	libp2pValidationQueueSize   = 4096
-	// libp2pSeenMessagesTTL is the time-to-live used for pubsub seen messages
-	// cache. Once a message is received and validated, pubsub re-broadcasts it
-	// to other peers and puts it into the seen messages cache. This way,
-	// subsequent arrivals of the same message are not re-broadcasted
-	// unnecessarily. This mechanism is important for the network to avoid
-	// excessive message flooding. The default value used by libp2p is 2 minutes.
-	// However, Keep client messaging sessions are quite time-consuming so,
-	// we use a longer TTL to reduce flooding risk even further. Worth noting
-	// that this time cannot be too long as the cache may grow excessively and
-	// impact memory consumption.
-	libp2pSeenMessagesTTL = 5 * time.Minute
 )
 
 type channelManager struct {
@@ -40,8 +29,17 @@ type channelManager struct {
 	channels      map[string]*channel
 
 	pubsub *pubsub.PubSub
+	// topicScoreParams are the score parameters set for every joined topic.
+	// Nil if peer scoring is disabled.
+	topicScoreParams *pubsub.TopicScoreParams
 
-	retransmissionTicker *retransmission.Ticker
+	retransmissionTicker    *retransmission.Ticker
+	retransmissionCacheSize int
+
+	compressor *payloadCompressor
+
+	duplicateMessagesTracer *duplicateMessagesTracer
+	topicMessagesTracer     *topicMessagesTracer
 
 	forwardersMutex sync.Mutex
 	forwarders      map[string]pubsub.RelayCancelFunc
@@ -55,29 +53,97 @@ func newChannelManager(
 	identity *identity,
 	p2phost host.Host,
 	retransmissionTicker *retransmission.Ticker,
+	compressor *payloadCompressor,
+	peerScoring PeerScoringConfig,
+	seenMessages SeenMessagesConfig,
+	rateLimit RateLimitConfig,
 ) (*channelManager, error) {
-	floodsub, err := pubsub.NewFloodSub(
-		ctx,
-		p2phost,
+	if err := seenMessages.validate(); err != nil {
+		return nil, fmt.Errorf("invalid seen messages config: [%v]", err)
+	}
+
+	duplicateMessagesTracer := &duplicateMessagesTracer{}
+	topicMessagesTracer := newTopicMessagesTracer()
+
+	options := []pubsub.Option{
 		pubsub.WithMessageAuthor(identity.id),
 		pubsub.WithMessageSignaturePolicy(pubsub.StrictSign),
 		pubsub.WithPeerOutboundQueueSize(libp2pPeerOutboundQueueSize),
 		pubsub.WithValidateQueueSize(libp2pValidationQueueSize),
-		pubsub.WithSeenMessagesStrategy(pubsubtc.Strategy_LastSeen),
-		pubsub.WithSeenMessagesTTL(libp2pSeenMessagesTTL),
+		pubsub.WithRawTracer(duplicateMessagesTracer),
+		pubsub.WithRawTracer(topicMessagesTracer),
+	}
+	options = append(options, seenMessages.options()...)
+
+	if rateLimit.Enabled {
+		if err := rateLimit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid rate limit config: [%v]", err)
+		}
+
+		rateLimiter := newSenderRateLimiter(
+			ctx,
+			rateLimit,
+			identity.id,
+			func(peerID peer.ID) {
+				if err := p2phost.Network().ClosePeer(peerID); err != nil {
+					logger.Warnf(
+						"could not disconnect peer [%v]: [%v]",
+						peerID,
+						err,
+					)
+				}
+			},
+		)
+
+		options = append(
+			options,
+			pubsub.WithDefaultValidator(rateLimiter.validator()),
+		)
+	}
+
+	var (
+		router           *pubsub.PubSub
+		topicScoreParams *pubsub.TopicScoreParams
+		err              error
 	)
+
+	if peerScoring.Enabled {
+		if err := peerScoring.validate(); err != nil {
+			return nil, fmt.Errorf("invalid peer scoring config: [%v]", err)
+		}
+
+		options = append(
+			options,
+			pubsub.WithPeerScore(
+				peerScoring.peerScoreParams(),
+				peerScoring.peerScoreThresholds(),
+			),
+		)
+
+		topicScoreParams = peerScoring.topicScoreParams()
+
+		router, err = pubsub.NewGossipSub(ctx, p2phost, options...)
+	} else {
+		router, err = pubsub.NewFloodSub(ctx, p2phost, options...)
+	}
 	if err != nil {
 		return nil, err
 	}
+
 	return &channelManager{
-		channels:             make(map[string]*channel),
-		pubsub:               floodsub,
-		peerStore:            p2phost.Peerstore(),
-		identity:             identity,
-		ctx:                  ctx,
-		retransmissionTicker: retransmissionTicker,
-		forwarders:           make(map[string]pubsub.RelayCancelFunc),
-		topics:               make(map[string]*pubsub.Topic),
+		channels:                make(map[string]*channel),
+		pubsub:                  router,
+		topicScoreParams:        topicScoreParams,
+		peerStore:               p2phost.Peerstore(),
+		identity:                identity,
+		ctx:                     ctx,
+		retransmissionTicker:    retransmissionTicker,
+		retransmissionCacheSize: seenMessages.CacheSize,
+		compressor:              compressor,
+		duplicateMessagesTracer: duplicateMessagesTracer,
+		topicMessagesTracer:     topicMessagesTracer,
+		forwarders:              make(map[string]pubsub.RelayCancelFunc),
+		topics:                  make(map[string]*pubsub.Topic),
 	}, nil
 }
 
@@ -133,16 +199,19 @@ func (cm *channelManager) newChannel(name string) (*channel, error) {
 	}
 
 	channel := &channel{
-		name:                 name,
-		clientIdentity:       cm.identity,
-		peerStore:            cm.peerStore,
-		validator:            cm.pubsub,
-		publisher:            topic,
-		subscription:         subscription,
-		incomingMessageQueue: make(chan *pubsub.Message, incomingMessageThrottle),
-		messageHandlers:      make([]*messageHandler, 0),
-		unmarshalersByType:   make(map[string]func() net.TaggedUnmarshaler),
-		retransmissionTicker: cm.retransmissionTicker,
+		name:                    name,
+		clientIdentity:          cm.identity,
+		peerStore:               cm.peerStore,
+		validator:               cm.pubsub,
+		publisher:               topic,
+		subscription:            subscription,
+		incomingMessageQueue:    make(chan *pubsub.Message, incomingMessageThrottle),
+		messageHandlers:         make([]*messageHandler, 0),
+		unmarshalersByType:      make(map[string]func() net.TaggedUnmarshaler),
+		retransmissionTicker:    cm.retransmissionTicker,
+		retransmissionCacheSize: cm.retransmissionCacheSize,
+		compressor:              cm.compressor,
+		messagesTracer:          cm.topicMessagesTracer,
 	}
 
 	go channel.handleMessages(cm.ctx)
@@ -229,6 +298,16 @@ func (cm *channelManager) getTopic(name string) (*pubsub.Topic, error) {
 			return nil, err
 		}
 
+		if cm.topicScoreParams != nil {
+			if err := topic.SetScoreParams(cm.topicScoreParams); err != nil {
+				return nil, fmt.Errorf(
+					"could not set score parameters of topic [%v]: [%v]",
+					name,
+					err,
+				)
+			}
+		}
+
 		cm.topics[name] = topic
 	}
 
@@ -3,12 +3,16 @@ package libp2p
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"reflect"
 	"sort"
 	"sync"
 	"testing"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
 	"github.com/keep-network/keep-core/pkg/operator"
 
 	"github.com/keep-network/keep-core/pkg/net"
@@ -223,6 +227,230 @@ func TestCreateTopicValidator(t *testing.T) {
 	}
 }
 
+func TestCreatePayloadValidator(t *testing.T) {
+	identity := generateTestIdentity(t)
+
+	compressor, err := newPayloadCompressor(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &channel{
+		name:               "testchannel",
+		clientIdentity:     identity,
+		unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+		compressor:         compressor,
+	}
+	channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &testMessage{}
+	})
+
+	rejectInvalid := func(message net.Message) error {
+		if message.Payload().(*testMessage).Payload == "invalid" {
+			return fmt.Errorf("invalid payload")
+		}
+		return nil
+	}
+
+	acceptAll := func(*operator.PublicKey) bool { return true }
+	rejectAll := func(*operator.PublicKey) bool { return false }
+
+	pubsubMessage := func(
+		payload string,
+		messageType string,
+		version uint32,
+	) *pubsub.Message {
+		messageProto, err := channel.messageProto(&testMessage{Payload: payload})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if messageType != "" {
+			messageProto.Type = []byte(messageType)
+		}
+		messageProto.Version = version
+
+		data, err := proto.Marshal(messageProto)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &pubsub.Message{
+			Message: &pubsubpb.Message{
+				From: []byte(identity.id),
+				Data: data,
+			},
+		}
+	}
+
+	var tests = map[string]struct {
+		filter         net.BroadcastChannelFilter
+		message        *pubsub.Message
+		expectedResult pubsub.ValidationResult
+	}{
+		"valid message": {
+			message:        pubsubMessage("valid", "", 0),
+			expectedResult: pubsub.ValidationAccept,
+		},
+		"valid message passing the filter": {
+			filter:         acceptAll,
+			message:        pubsubMessage("valid", "", 0),
+			expectedResult: pubsub.ValidationAccept,
+		},
+		"valid message not passing the filter": {
+			filter:         rejectAll,
+			message:        pubsubMessage("valid", "", 0),
+			expectedResult: pubsub.ValidationReject,
+		},
+		"invalid message": {
+			message:        pubsubMessage("invalid", "", 0),
+			expectedResult: pubsub.ValidationReject,
+		},
+		"message of unknown type": {
+			message:        pubsubMessage("valid", "unknown", 0),
+			expectedResult: pubsub.ValidationIgnore,
+		},
+		"message of unsupported version": {
+			message:        pubsubMessage("valid", "", 1),
+			expectedResult: pubsub.ValidationIgnore,
+		},
+		"malformed message": {
+			message: &pubsub.Message{
+				Message: &pubsubpb.Message{
+					From: []byte(identity.id),
+					Data: []byte{0xff, 0xff, 0xff},
+				},
+			},
+			expectedResult: pubsub.ValidationReject,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			validator := channel.createPayloadValidator(
+				test.filter,
+				[]net.BroadcastChannelValidator{rejectInvalid},
+			)
+
+			result := validator(context.Background(), identity.id, test.message)
+
+			if test.expectedResult != result {
+				t.Fatalf(
+					"unexpected validation result\nexpected: [%v]\nactual:   [%v]",
+					test.expectedResult,
+					result,
+				)
+			}
+
+			if result != pubsub.ValidationAccept {
+				return
+			}
+
+			netMessage, ok := test.message.ValidatorData.(net.Message)
+			if !ok {
+				t.Fatalf(
+					"unexpected validator data type [%T]",
+					test.message.ValidatorData,
+				)
+			}
+
+			if netMessage.TransportSenderID().String() != identity.id.String() {
+				t.Errorf(
+					"unexpected sender\nexpected: [%v]\nactual:   [%v]",
+					identity.id,
+					netMessage.TransportSenderID(),
+				)
+			}
+		})
+	}
+}
+
+func TestChannel_AddValidator(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	providers := make([]*provider, 2)
+	for i := range providers {
+		operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		netProvider, err := Connect(
+			ctx,
+			Config{Port: 8095 + i},
+			operatorPrivateKey,
+			firewall.Disabled,
+			idleTicker(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		providers[i] = netProvider.(*provider)
+	}
+
+	err := providers[0].host.Connect(ctx, peer.AddrInfo{
+		ID:    providers[1].identity.id,
+		Addrs: providers[1].host.Addrs(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channels := make([]net.BroadcastChannel, len(providers))
+	for i, provider := range providers {
+		channel, err := provider.BroadcastChannelFor("testchannel")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		channel.SetUnmarshaler(
+			func() net.TaggedUnmarshaler { return &testMessage{} },
+		)
+
+		channels[i] = channel
+	}
+
+	err = channels[1].AddValidator(func(message net.Message) error {
+		if message.Payload().(*testMessage).Payload == "invalid" {
+			return fmt.Errorf("invalid payload")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receivedPayloads := make(chan string, 512)
+	channels[1].Recv(ctx, func(message net.Message) {
+		receivedPayloads <- message.Payload().(*testMessage).Payload
+	})
+
+	for {
+		// Subscriptions are propagated asynchronously so keep sending until
+		// the receiver is known to the sender.
+		for _, payload := range []string{"invalid", "valid"} {
+			if err := channels[0].Send(
+				ctx,
+				&testMessage{Payload: payload},
+			); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		select {
+		case payload := <-receivedPayloads:
+			if payload != "valid" {
+				t.Fatalf("unexpected payload received: [%v]", payload)
+			}
+			return
+		case <-ctx.Done():
+			t.Fatal("no valid message received")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
 func toEncodedBytes(t *testing.T, publicKey *operator.PublicKey) string {
 	publicKeyBytes := operator.MarshalUncompressed(publicKey)
 
@@ -0,0 +1,110 @@
+package libp2p
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+// Algorithms used to compress broadcast channel payloads. The algorithm is
+// carried in the message envelope so the receiver knows how to decompress
+// the payload.
+const (
+	noCompression   uint32 = 0
+	zstdCompression uint32 = 1
+)
+
+// maxDecompressedPayloadSize is the maximum size of a decompressed payload.
+// It protects against payloads crafted to decompress to an excessive size.
+const maxDecompressedPayloadSize = 16 << 20
+
+// payloadCompressor compresses payloads of outgoing broadcast channel
+// messages and decompresses payloads of incoming ones. Only payloads of
+// at least the threshold size are compressed, and a compressed payload is
+// used only if it is smaller than the original one. If the threshold is not
+// positive, outgoing payloads are never compressed but incoming compressed
+// payloads are still decompressed.
+type payloadCompressor struct {
+	threshold int
+
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+
+	compressedMessages uint64
+	uncompressedBytes  uint64
+	compressedBytes    uint64
+}
+
+func newPayloadCompressor(threshold int) (*payloadCompressor, error) {
+	encoder, err := zstd.NewWriter(
+		nil,
+		zstd.WithEncoderLevel(zstd.SpeedDefault),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create zstd encoder: [%v]", err)
+	}
+
+	decoder, err := zstd.NewReader(
+		nil,
+		zstd.WithDecoderMaxMemory(maxDecompressedPayloadSize),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create zstd decoder: [%v]", err)
+	}
+
+	return &payloadCompressor{
+		threshold: threshold,
+		encoder:   encoder,
+		decoder:   decoder,
+	}, nil
+}
+
+// compress returns the payload to be sent and the algorithm it is compressed
+// with.
+func (pc *payloadCompressor) compress(payload []byte) ([]byte, uint32) {
+	if pc.threshold <= 0 || len(payload) < pc.threshold {
+		return payload, noCompression
+	}
+
+	compressed := pc.encoder.EncodeAll(payload, nil)
+	if len(compressed) >= len(payload) {
+		return payload, noCompression
+	}
+
+	atomic.AddUint64(&pc.compressedMessages, 1)
+	atomic.AddUint64(&pc.uncompressedBytes, uint64(len(payload)))
+	atomic.AddUint64(&pc.compressedBytes, uint64(len(compressed)))
+
+	return compressed, zstdCompression
+}
+
+// decompress returns the original payload of a payload compressed with
+// the given algorithm.
+func (pc *payloadCompressor) decompress(
+	payload []byte,
+	algorithm uint32,
+) ([]byte, error) {
+	switch algorithm {
+	case noCompression:
+		return payload, nil
+	case zstdCompression:
+		decompressed, err := pc.decoder.DecodeAll(payload, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress payload: [%v]", err)
+		}
+		return decompressed, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression [%v]", algorithm)
+	}
+}
+
+func (pc *payloadCompressor) stats() net.CompressionStats {
+	return net.CompressionStats{
+		CompressedMessages: atomic.LoadUint64(&pc.compressedMessages),
+		UncompressedBytes:  atomic.LoadUint64(&pc.uncompressedBytes),
+		CompressedBytes:    atomic.LoadUint64(&pc.compressedBytes),
+	}
+}
@@ -0,0 +1,223 @@
+package libp2p
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+func TestPayloadCompressor(t *testing.T) {
+	compressiblePayload := bytes.Repeat([]byte("keep"), 512)
+
+	incompressiblePayload := make([]byte, 2048)
+	if _, err := rand.Read(incompressiblePayload); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		threshold           int
+		payload             []byte
+		expectedCompression uint32
+	}{
+		"compression disabled": {
+			threshold:           0,
+			payload:             compressiblePayload,
+			expectedCompression: noCompression,
+		},
+		"payload below threshold": {
+			threshold:           len(compressiblePayload) + 1,
+			payload:             compressiblePayload,
+			expectedCompression: noCompression,
+		},
+		"payload at threshold": {
+			threshold:           len(compressiblePayload),
+			payload:             compressiblePayload,
+			expectedCompression: zstdCompression,
+		},
+		"incompressible payload": {
+			threshold:           1,
+			payload:             incompressiblePayload,
+			expectedCompression: noCompression,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			compressor, err := newPayloadCompressor(test.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			compressed, compression := compressor.compress(test.payload)
+
+			testutils.AssertUintsEqual(
+				t,
+				"compression",
+				uint64(test.expectedCompression),
+				uint64(compression),
+			)
+
+			decompressed, err := compressor.decompress(compressed, compression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.AssertBytesEqual(t, test.payload, decompressed)
+
+			stats := compressor.stats()
+			if compression == noCompression {
+				testutils.AssertUintsEqual(
+					t,
+					"compressed messages",
+					0,
+					stats.CompressedMessages,
+				)
+				return
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"compressed messages",
+				1,
+				stats.CompressedMessages,
+			)
+			testutils.AssertUintsEqual(
+				t,
+				"uncompressed bytes",
+				uint64(len(test.payload)),
+				stats.UncompressedBytes,
+			)
+			testutils.AssertUintsEqual(
+				t,
+				"compressed bytes",
+				uint64(len(compressed)),
+				stats.CompressedBytes,
+			)
+		})
+	}
+}
+
+func TestPayloadCompressor_DecompressTooLarge(t *testing.T) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bomb := encoder.EncodeAll(
+		make([]byte, maxDecompressedPayloadSize+1),
+		nil,
+	)
+
+	compressor, err := newPayloadCompressor(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := compressor.decompress(bomb, zstdCompression); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPayloadCompressor_DecompressUnsupported(t *testing.T) {
+	compressor, err := newPayloadCompressor(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = compressor.decompress([]byte{0x01}, 2)
+
+	expectedErr := fmt.Errorf("unsupported compression [2]")
+	if err == nil || err.Error() != expectedErr.Error() {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func TestChannel_CompressedMessage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	identity := generateTestIdentity(t)
+
+	compressor, err := newPayloadCompressor(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &channel{
+		clientIdentity:     identity,
+		unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+		compressor:         compressor,
+	}
+	channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &testMessage{}
+	})
+
+	received := make(chan net.Message, 1)
+	channel.Recv(ctx, func(msg net.Message) {
+		received <- msg
+	})
+
+	expectedPayload := string(bytes.Repeat([]byte("keep"), 256))
+
+	messageProto, err := channel.messageProto(
+		&testMessage{Payload: expectedPayload},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertUintsEqual(
+		t,
+		"compression",
+		uint64(zstdCompression),
+		uint64(messageProto.Compression),
+	)
+
+	if err := channel.processContainerMessage(
+		&pubsub.Message{Message: &pubsubpb.Message{From: []byte(identity.id)}},
+		messageProto,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-received:
+		testPayload, ok := msg.Payload().(*testMessage)
+		if !ok {
+			t.Fatalf("unexpected payload type [%T]", msg.Payload())
+		}
+
+		testutils.AssertStringsEqual(
+			t,
+			"payload",
+			expectedPayload,
+			testPayload.Payload,
+		)
+
+		signedMsg, ok := msg.(net.SignedMessage)
+		if !ok {
+			t.Fatal("expected message with the signed envelope")
+		}
+		envelope, err := signedMsg.SignedEnvelope()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(envelope) == 0 {
+			t.Error("expected non-empty signed envelope")
+		}
+	case <-ctx.Done():
+		t.Fatal("expected message not received")
+	}
+}
@@ -0,0 +1,241 @@
+package libp2p
+
+import (
+	"fmt"
+	gonet "net"
+	"strings"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+// Compile time assertion of the connection gater type.
+var _ connmgr.ConnectionGater = (*connectionGater)(nil)
+
+// connectionGater gates connections with remote peers according to peer ID
+// and IP address allow and deny lists. The lists can be replaced at runtime.
+type connectionGater struct {
+	rulesMutex sync.RWMutex
+	rules      net.ConnectionGaterRules
+
+	allowedPeers map[peer.ID]bool
+	deniedPeers  map[peer.ID]bool
+	allowedIPs   []*gonet.IPNet
+	deniedIPs    []*gonet.IPNet
+}
+
+func newConnectionGater(
+	rules net.ConnectionGaterRules,
+) (*connectionGater, error) {
+	gater := &connectionGater{}
+
+	if err := gater.setRules(rules); err != nil {
+		return nil, err
+	}
+
+	return gater, nil
+}
+
+// setRules replaces the gater rules. The rules are not changed if any of
+// them is invalid.
+func (cg *connectionGater) setRules(rules net.ConnectionGaterRules) error {
+	allowedPeers, err := parsePeerIDs(rules.AllowedPeers)
+	if err != nil {
+		return fmt.Errorf("invalid allowed peers: [%v]", err)
+	}
+
+	deniedPeers, err := parsePeerIDs(rules.DeniedPeers)
+	if err != nil {
+		return fmt.Errorf("invalid denied peers: [%v]", err)
+	}
+
+	allowedIPs, err := parseIPNets(rules.AllowedIPs)
+	if err != nil {
+		return fmt.Errorf("invalid allowed IPs: [%v]", err)
+	}
+
+	deniedIPs, err := parseIPNets(rules.DeniedIPs)
+	if err != nil {
+		return fmt.Errorf("invalid denied IPs: [%v]", err)
+	}
+
+	cg.rulesMutex.Lock()
+	defer cg.rulesMutex.Unlock()
+
+	cg.rules = net.ConnectionGaterRules{
+		AllowedPeers: append([]string{}, rules.AllowedPeers...),
+		DeniedPeers:  append([]string{}, rules.DeniedPeers...),
+		AllowedIPs:   append([]string{}, rules.AllowedIPs...),
+		DeniedIPs:    append([]string{}, rules.DeniedIPs...),
+	}
+	cg.allowedPeers = allowedPeers
+	cg.deniedPeers = deniedPeers
+	cg.allowedIPs = allowedIPs
+	cg.deniedIPs = deniedIPs
+
+	return nil
+}
+
+func (cg *connectionGater) getRules() net.ConnectionGaterRules {
+	cg.rulesMutex.RLock()
+	defer cg.rulesMutex.RUnlock()
+
+	return net.ConnectionGaterRules{
+		AllowedPeers: append([]string{}, cg.rules.AllowedPeers...),
+		DeniedPeers:  append([]string{}, cg.rules.DeniedPeers...),
+		AllowedIPs:   append([]string{}, cg.rules.AllowedIPs...),
+		DeniedIPs:    append([]string{}, cg.rules.DeniedIPs...),
+	}
+}
+
+// isPeerGated returns true if connections with the given peer are gated.
+func (cg *connectionGater) isPeerGated(peerID peer.ID) bool {
+	cg.rulesMutex.RLock()
+	defer cg.rulesMutex.RUnlock()
+
+	return cg.deniedPeers[peerID] && !cg.allowedPeers[peerID]
+}
+
+// isAddressGated returns true if connections with the given address are
+// gated. Addresses without an IP component, like relayed addresses, are
+// never gated.
+func (cg *connectionGater) isAddressGated(address ma.Multiaddr) bool {
+	ip, err := manet.ToIP(address)
+	if err != nil {
+		return false
+	}
+
+	cg.rulesMutex.RLock()
+	defer cg.rulesMutex.RUnlock()
+
+	return containsIP(cg.deniedIPs, ip) && !containsIP(cg.allowedIPs, ip)
+}
+
+// isConnectionGated returns true if the given connection is gated.
+func (cg *connectionGater) isConnectionGated(connection libp2pnet.Conn) bool {
+	return cg.isPeerGated(connection.RemotePeer()) ||
+		cg.isAddressGated(connection.RemoteMultiaddr())
+}
+
+func (cg *connectionGater) InterceptPeerDial(peerID peer.ID) bool {
+	return !cg.isPeerGated(peerID)
+}
+
+func (cg *connectionGater) InterceptAddrDial(
+	_ peer.ID,
+	address ma.Multiaddr,
+) bool {
+	return !cg.isAddressGated(address)
+}
+
+func (cg *connectionGater) InterceptAccept(
+	connection libp2pnet.ConnMultiaddrs,
+) bool {
+	gated := cg.isAddressGated(connection.RemoteMultiaddr())
+	if gated {
+		logger.Infof(
+			"gated inbound connection from [%v]",
+			connection.RemoteMultiaddr(),
+		)
+	}
+
+	return !gated
+}
+
+func (cg *connectionGater) InterceptSecured(
+	_ libp2pnet.Direction,
+	peerID peer.ID,
+	connection libp2pnet.ConnMultiaddrs,
+) bool {
+	gated := cg.isPeerGated(peerID)
+	if gated {
+		logger.Infof(
+			"gated connection with peer [%v]",
+			multiaddressWithIdentity(connection.RemoteMultiaddr(), peerID),
+		)
+	}
+
+	return !gated
+}
+
+func (cg *connectionGater) InterceptUpgraded(
+	libp2pnet.Conn,
+) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+func parsePeerIDs(peerIDs []string) (map[peer.ID]bool, error) {
+	parsed := make(map[peer.ID]bool, len(peerIDs))
+
+	for _, peerID := range peerIDs {
+		decoded, err := peer.Decode(peerID)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not decode peer ID [%v]: [%v]",
+				peerID,
+				err,
+			)
+		}
+
+		parsed[decoded] = true
+	}
+
+	return parsed, nil
+}
+
+// parseIPNets parses IP addresses and CIDR ranges. A single IP address is
+// parsed as a range containing only that address.
+func parseIPNets(addresses []string) ([]*gonet.IPNet, error) {
+	parsed := make([]*gonet.IPNet, 0, len(addresses))
+
+	for _, address := range addresses {
+		if !strings.Contains(address, "/") {
+			ip := gonet.ParseIP(address)
+			if ip == nil {
+				return nil, fmt.Errorf("could not parse IP [%v]", address)
+			}
+
+			bits := 8 * gonet.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * gonet.IPv4len
+			}
+
+			parsed = append(parsed, &gonet.IPNet{
+				IP:   ip,
+				Mask: gonet.CIDRMask(bits, bits),
+			})
+			continue
+		}
+
+		_, ipNet, err := gonet.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not parse CIDR [%v]: [%v]",
+				address,
+				err,
+			)
+		}
+
+		parsed = append(parsed, ipNet)
+	}
+
+	return parsed, nil
+}
+
+func containsIP(ipNets []*gonet.IPNet, ip gonet.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,263 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestNewConnectionGater_InvalidRules(t *testing.T) {
+	var tests = map[string]struct {
+		rules         net.ConnectionGaterRules
+		expectedError error
+	}{
+		"invalid allowed peer": {
+			rules: net.ConnectionGaterRules{
+				AllowedPeers: []string{"invalid"},
+			},
+			expectedError: fmt.Errorf(
+				"invalid allowed peers: [could not decode peer ID [invalid]: " +
+					"[failed to parse peer ID: invalid cid: selected encoding not supported]]",
+			),
+		},
+		"invalid denied IP": {
+			rules: net.ConnectionGaterRules{
+				DeniedIPs: []string{"10.0.0.300"},
+			},
+			expectedError: fmt.Errorf(
+				"invalid denied IPs: [could not parse IP [10.0.0.300]]",
+			),
+		},
+		"invalid allowed CIDR": {
+			rules: net.ConnectionGaterRules{
+				AllowedIPs: []string{"10.0.0.0/40"},
+			},
+			expectedError: fmt.Errorf(
+				"invalid allowed IPs: [could not parse CIDR [10.0.0.0/40]: " +
+					"[invalid CIDR address: 10.0.0.0/40]]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := newConnectionGater(test.rules)
+			if !reflect.DeepEqual(
+				fmt.Sprintf("%v", test.expectedError),
+				fmt.Sprintf("%v", err),
+			) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestConnectionGater_SetRulesKeepsRulesOnError(t *testing.T) {
+	rules := net.ConnectionGaterRules{
+		DeniedIPs: []string{"10.0.0.0/8"},
+	}
+
+	gater, err := newConnectionGater(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gater.setRules(net.ConnectionGaterRules{
+		DeniedIPs: []string{"invalid"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	expectedRules := net.ConnectionGaterRules{
+		AllowedPeers: []string{},
+		DeniedPeers:  []string{},
+		AllowedIPs:   []string{},
+		DeniedIPs:    []string{"10.0.0.0/8"},
+	}
+	if !reflect.DeepEqual(expectedRules, gater.getRules()) {
+		t.Errorf(
+			"unexpected rules\nexpected: [%+v]\nactual:   [%+v]",
+			expectedRules,
+			gater.getRules(),
+		)
+	}
+}
+
+func TestConnectionGater_IsPeerGated(t *testing.T) {
+	allowedAndDenied := generateTestIdentity(t).id
+	denied := generateTestIdentity(t).id
+	other := generateTestIdentity(t).id
+
+	gater, err := newConnectionGater(net.ConnectionGaterRules{
+		AllowedPeers: []string{allowedAndDenied.String()},
+		DeniedPeers:  []string{allowedAndDenied.String(), denied.String()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		peerID        peer.ID
+		expectedGated bool
+	}{
+		"allowed and denied peer": {
+			peerID:        allowedAndDenied,
+			expectedGated: false,
+		},
+		"denied peer": {
+			peerID:        denied,
+			expectedGated: true,
+		},
+		"other peer": {
+			peerID:        other,
+			expectedGated: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			gated := gater.isPeerGated(test.peerID)
+			if gated != test.expectedGated {
+				t.Errorf(
+					"unexpected result\nexpected: [%v]\nactual:   [%v]",
+					test.expectedGated,
+					gated,
+				)
+			}
+		})
+	}
+}
+
+func TestConnectionGater_IsAddressGated(t *testing.T) {
+	gater, err := newConnectionGater(net.ConnectionGaterRules{
+		AllowedIPs: []string{"10.0.0.1", "2001:db8::1"},
+		DeniedIPs:  []string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		address       string
+		expectedGated bool
+	}{
+		"denied range": {
+			address:       "/ip4/10.1.2.3/tcp/3919",
+			expectedGated: true,
+		},
+		"allowed address in denied range": {
+			address:       "/ip4/10.0.0.1/tcp/3919",
+			expectedGated: false,
+		},
+		"denied address": {
+			address:       "/ip4/192.168.1.1/tcp/3919",
+			expectedGated: true,
+		},
+		"address next to denied address": {
+			address:       "/ip4/192.168.1.2/tcp/3919",
+			expectedGated: false,
+		},
+		"denied IPv6 range": {
+			address:       "/ip6/2001:db8::2/tcp/3919",
+			expectedGated: true,
+		},
+		"allowed IPv6 address in denied range": {
+			address:       "/ip6/2001:db8::1/tcp/3919",
+			expectedGated: false,
+		},
+		"address without IP": {
+			address:       "/dns4/example.com/tcp/3919",
+			expectedGated: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			gated := gater.isAddressGated(ma.StringCast(test.address))
+			if gated != test.expectedGated {
+				t.Errorf(
+					"unexpected result\nexpected: [%v]\nactual:   [%v]",
+					test.expectedGated,
+					gated,
+				)
+			}
+		})
+	}
+}
+
+func TestProviderSetConnectionGaterRules(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	connect := func(port int) *provider {
+		operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		netProvider, err := Connect(
+			ctx,
+			Config{Port: port},
+			operatorPrivateKey,
+			firewall.Disabled,
+			idleTicker(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return netProvider.(*provider)
+	}
+
+	gating := connect(8097)
+	gated := connect(8098)
+
+	gatedAddrInfo := peer.AddrInfo{
+		ID:    gated.identity.id,
+		Addrs: gated.host.Addrs(),
+	}
+
+	if err := gating.host.Connect(ctx, gatedAddrInfo); err != nil {
+		t.Fatal(err)
+	}
+
+	err := gating.SetConnectionGaterRules(net.ConnectionGaterRules{
+		DeniedPeers: []string{gated.identity.id.String()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connectedness := gating.host.Network().Connectedness(gated.identity.id)
+	if connectedness == libp2pnet.Connected {
+		t.Error("gated peer is still connected")
+	}
+
+	if err := gating.host.Connect(ctx, gatedAddrInfo); err == nil {
+		t.Error("expected the connection with the gated peer to fail")
+	}
+
+	err = gating.SetConnectionGaterRules(net.ConnectionGaterRules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gating.host.Connect(ctx, gatedAddrInfo); err != nil {
+		t.Errorf("unexpected connection error: [%v]", err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	libp2pnet "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/pnet"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	rhost "github.com/libp2p/go-libp2p/p2p/host/routed"
 	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
@@ -31,6 +32,7 @@ import (
 	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
 
 	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
 )
 
 var logger = log.Logger("keep-libp2p")
@@ -80,16 +82,46 @@ type Config struct {
 	Port               int
 	AnnouncedAddresses []string
 	DisseminationTime  int // TODO: Convert to time.Duration
+	// CompressionThreshold is the minimum size in bytes of a broadcast
+	// channel payload to be compressed before sending. Compression is
+	// disabled if the value is not positive. Compressed payloads received
+	// from other peers are decompressed regardless of this setting.
+	CompressionThreshold int
+	PeerScoring          PeerScoringConfig
+	SeenMessages         SeenMessagesConfig
+	RateLimit            RateLimitConfig
+	NAT                  NATConfig
+	// DNSSeeds are domains with `dnsaddr` TXT records listing bootstrap
+	// peers, e.g. `bootstrap.example.com` resolved from records of
+	// `_dnsaddr.bootstrap.example.com`. Peers resolved from DNS seeds are
+	// used along with Peers but, unlike them, are not firewall-allowlisted.
+	DNSSeeds []string
+	// BootstrapRefreshPeriod is the period at which bootstrap peers are
+	// resolved again from DNS seeds.
+	BootstrapRefreshPeriod time.Duration
+	// ConnectionGater holds the initial peer ID and IP address allow and
+	// deny lists. They can be replaced at runtime.
+	ConnectionGater net.ConnectionGaterRules
+	// PrivateNetworkKeyFile is the path to the pre-shared key file of
+	// a private network. Nodes of a private network connect only with nodes
+	// sharing the same key and are isolated from the public network. The
+	// node joins the public network if the path is empty.
+	PrivateNetworkKeyFile string
 }
 
 type provider struct {
 	channelManagerMutex     sync.Mutex
 	broadcastChannelManager *channelManager
+	unicastChannelManager   *unicastChannelManager
 
 	identity          *identity
 	host              host.Host
 	routing           *dht.IpfsDHT
 	disseminationTime int
+	compressor        *payloadCompressor
+	bootstrapPeers    *bootstrapPeerSource
+	stats             *networkStats
+	connectionGater   *connectionGater
 
 	connectionManager *connectionManager
 }
@@ -100,6 +132,21 @@ func (p *provider) BroadcastChannelFor(name string) (net.BroadcastChannel, error
 	return p.broadcastChannelManager.getChannel(name)
 }
 
+func (p *provider) UnicastChannelWith(
+	peerID net.TransportIdentifier,
+) (net.UnicastChannel, error) {
+	remotePeerID, err := peer.Decode(peerID.String())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode peer ID from [%v]: [%v]",
+			peerID,
+			err,
+		)
+	}
+
+	return p.unicastChannelManager.getChannel(remotePeerID)
+}
+
 func (p *provider) Type() string {
 	return "libp2p"
 }
@@ -108,6 +155,66 @@ func (p *provider) ID() net.TransportIdentifier {
 	return networkIdentity(p.identity.id)
 }
 
+// DuplicateMessagesCount implements the net.DuplicateMessagesSource interface.
+func (p *provider) DuplicateMessagesCount() uint64 {
+	return p.broadcastChannelManager.duplicateMessagesTracer.duplicateMessagesCount()
+}
+
+// NetworkStats implements the net.NetworkStatsSource interface.
+func (p *provider) NetworkStats() net.NetworkStats {
+	bandwidth := p.stats.bandwidthCounter.GetBandwidthTotals()
+
+	return net.NetworkStats{
+		BytesIn:               uint64(bandwidth.TotalIn),
+		BytesOut:              uint64(bandwidth.TotalOut),
+		BytesInRate:           bandwidth.RateIn,
+		BytesOutRate:          bandwidth.RateOut,
+		HandshakeFailures:     p.stats.handshakeFailuresCount(),
+		BootstrapPeers:        len(p.bootstrapPeers.peers()),
+		BootstrapFailedRounds: p.stats.bootstrapFailedRoundsCount(),
+		Topics:                p.broadcastChannelManager.topicMessagesTracer.topicStats(),
+	}
+}
+
+// CompressionStats implements the net.CompressionStatsSource interface.
+func (p *provider) CompressionStats() net.CompressionStats {
+	return p.compressor.stats()
+}
+
+// ConnectionGaterRules implements the net.ConnectionGater interface.
+func (p *provider) ConnectionGaterRules() net.ConnectionGaterRules {
+	return p.connectionGater.getRules()
+}
+
+// SetConnectionGaterRules implements the net.ConnectionGater interface.
+func (p *provider) SetConnectionGaterRules(rules net.ConnectionGaterRules) error {
+	if err := p.connectionGater.setRules(rules); err != nil {
+		return err
+	}
+
+	logger.Infof("connection gater rules updated: [%+v]", rules)
+
+	for _, connection := range p.host.Network().Conns() {
+		if !p.connectionGater.isConnectionGated(connection) {
+			continue
+		}
+
+		logger.Infof(
+			"closing gated connection with [%v]",
+			multiaddressWithIdentity(
+				connection.RemoteMultiaddr(),
+				connection.RemotePeer(),
+			),
+		)
+
+		if err := connection.Close(); err != nil {
+			logger.Warnf("could not close gated connection: [%v]", err)
+		}
+	}
+
+	return nil
+}
+
 func (p *provider) ConnectionManager() net.ConnectionManager {
 	return p.connectionManager
 }
@@ -301,6 +408,23 @@ func Connect(
 		)
 	}
 
+	if err := config.NAT.validate(); err != nil {
+		return nil, fmt.Errorf("invalid NAT config: [%v]", err)
+	}
+
+	connectionGater, err := newConnectionGater(config.ConnectionGater)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection gater rules: [%v]", err)
+	}
+
+	privateNetworkKey, err := readPrivateNetworkKey(config.PrivateNetworkKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read private network key: [%v]", err)
+	}
+	if privateNetworkKey != nil {
+		logger.Infof("joining private network")
+	}
+
 	connectOptions := defaultConnectOptions()
 	connectOptions.apply(options...)
 
@@ -314,20 +438,52 @@ func Connect(
 		return nil, err
 	}
 
+	stats := newNetworkStats()
+
 	host, err := discoverAndListen(
 		ctx,
 		identity,
 		config.Port,
 		config.AnnouncedAddresses,
+		config.NAT,
 		firewall,
+		connectionGater,
+		privateNetworkKey,
+		stats,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if config.NAT.AutoNATService {
+		err := enableAutoNATService(
+			host,
+			identity,
+			privateNetworkKey,
+			securityOption(firewall, stats.handshakeFailed),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	host.Network().Notify(buildNotifiee(host))
 
-	broadcastChannelManager, err := newChannelManager(ctx, identity, host, ticker)
+	compressor, err := newPayloadCompressor(config.CompressionThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastChannelManager, err := newChannelManager(
+		ctx,
+		identity,
+		host,
+		ticker,
+		compressor,
+		config.PeerScoring,
+		config.SeenMessages,
+		config.RateLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -352,13 +508,39 @@ func Connect(
 		host:                    rhost.Wrap(host, router),
 		routing:                 router,
 		disseminationTime:       config.DisseminationTime,
+		compressor:              compressor,
+		stats:                   stats,
+		connectionGater:         connectionGater,
 	}
 
-	if len(config.Peers) == 0 {
+	provider.unicastChannelManager = newUnicastChannelManager(
+		ctx,
+		identity,
+		provider.host,
+	)
+
+	if len(config.Peers) == 0 && len(config.DNSSeeds) == 0 {
 		logger.Infof("bootstrap peers list is empty")
 	}
 
-	if err := provider.bootstrap(ctx, config.Peers); err != nil {
+	bootstrapPeers, err := newBootstrapPeerSource(
+		identity.id,
+		config.Peers,
+		config.DNSSeeds,
+		madns.DefaultResolver,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bootstrap peers: [%v]", err)
+	}
+
+	bootstrapRefreshPeriod := config.BootstrapRefreshPeriod
+	if bootstrapRefreshPeriod <= 0 {
+		bootstrapRefreshPeriod = DefaultBootstrapRefreshPeriod
+	}
+	bootstrapPeers.start(ctx, bootstrapRefreshPeriod)
+	provider.bootstrapPeers = bootstrapPeers
+
+	if err := provider.bootstrap(); err != nil {
 		return nil, fmt.Errorf("bootstrap failed: [%v]", err)
 	}
 
@@ -381,7 +563,11 @@ func discoverAndListen(
 	identity *identity,
 	port int,
 	announcedAddresses []string,
+	natConfig NATConfig,
 	firewall net.Firewall,
+	connectionGater *connectionGater,
+	privateNetworkKey pnet.PSK,
+	stats *networkStats,
 ) (host.Host, error) {
 	var err error
 
@@ -406,31 +592,14 @@ func discoverAndListen(
 	options := []libp2p.Option{
 		libp2p.ListenAddrs(addrs...),
 		libp2p.Identity(identity.privKey),
-		libp2p.Security(
-			securityProtocolID,
-			func(
-				protocolID protocol.ID,
-				privateKey libp2pcrypto.PrivKey,
-				muxers []upgrader.StreamMuxer,
-			) (*transport, error) {
-				encAuthTransport, err := newEncryptedAuthenticatedTransport(
-					protocolID,
-					authProtocolID,
-					privateKey,
-					muxers,
-					firewall,
-				)
-				if err != nil {
-					return nil, fmt.Errorf(
-						"could not create encrypted authenticated transport: [%v]",
-						err,
-					)
-				}
-
-				return encAuthTransport, nil
-			},
-		),
+		securityOption(firewall, stats.handshakeFailed),
 		libp2p.ConnectionManager(connectionManager),
+		libp2p.ConnectionGater(connectionGater),
+		libp2p.BandwidthReporter(stats.bandwidthCounter),
+	}
+
+	if privateNetworkKey != nil {
+		options = append(options, libp2p.PrivateNetwork(privateNetworkKey))
 	}
 
 	if addresses := parseMultiaddresses(announcedAddresses); len(addresses) > 0 {
@@ -445,9 +614,49 @@ func discoverAndListen(
 		options = append(options, libp2p.AddrsFactory(addressFactory))
 	}
 
+	natOptions, err := natConfig.options()
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, natOptions...)
+
 	return libp2p.New(options...)
 }
 
+// securityOption returns the libp2p option setting up the encrypted and
+// authenticated transport checking remote peers against the firewall.
+// The handshakeFailed function is called whenever securing a connection fails.
+func securityOption(
+	firewall net.Firewall,
+	handshakeFailed func(),
+) libp2p.Option {
+	return libp2p.Security(
+		securityProtocolID,
+		func(
+			protocolID protocol.ID,
+			privateKey libp2pcrypto.PrivKey,
+			muxers []upgrader.StreamMuxer,
+		) (*transport, error) {
+			encAuthTransport, err := newEncryptedAuthenticatedTransport(
+				protocolID,
+				authProtocolID,
+				privateKey,
+				muxers,
+				firewall,
+				handshakeFailed,
+			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"could not create encrypted authenticated transport: [%v]",
+					err,
+				)
+			}
+
+			return encAuthTransport, nil
+		},
+	)
+}
+
 func getListenAddrs(port int) ([]ma.Multiaddr, error) {
 	ia, err := addrutil.InterfaceAddresses()
 	if err != nil {
@@ -482,31 +691,14 @@ func parseMultiaddresses(addresses []string) []ma.Multiaddr {
 	return multiaddresses
 }
 
-func (p *provider) bootstrap(
-	ctx context.Context,
-	bootstrapPeers []string,
-) error {
-	peerInfos, err := extractMultiAddrFromPeers(bootstrapPeers)
-	if err != nil {
-		return err
-	}
-
-	ownID := p.identity.id
-	filteredPeerInfos := make([]peer.AddrInfo, 0)
-
-	// If the client's own address is on the list of bootstrap peers, filter it
-	// out to prevent self-dialing.
-	for _, peerInfo := range peerInfos {
-		if peerInfo.ID != ownID {
-			filteredPeerInfos = append(filteredPeerInfos, peerInfo)
-		}
-	}
-
-	bootstrapConfig := bootstrapConfigWithPeers(filteredPeerInfos)
+func (p *provider) bootstrap() error {
+	bootstrapConfig := DefaultBootstrapConfig
+	bootstrapConfig.BootstrapPeers = p.bootstrapPeers.peers
+	bootstrapConfig.RoundCompleted = p.stats.bootstrapRoundCompleted
 
 	// TODO: use the io.Closer to shutdown the bootstrapper when we build out
 	// a shutdown process.
-	_, err = Bootstrap(
+	_, err := Bootstrap(
 		p.identity.id,
 		p.host,
 		p.routing,
@@ -0,0 +1,150 @@
+package libp2p
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/pnet"
+	"github.com/libp2p/go-libp2p/p2p/host/autonat"
+	"github.com/libp2p/go-libp2p/p2p/transport/tcp"
+)
+
+// Reachability modes of the node.
+const (
+	// ReachabilityAuto detects reachability of the node with AutoNAT.
+	ReachabilityAuto = "auto"
+	// ReachabilityPublic assumes the node is publicly reachable.
+	ReachabilityPublic = "public"
+	// ReachabilityPrivate assumes the node is behind NAT and not publicly
+	// reachable.
+	ReachabilityPrivate = "private"
+)
+
+// NATConfig defines the NAT traversal configuration of the libp2p provider.
+//
+// A node behind NAT stays reachable by making reservations with circuit
+// relay v2 servers and announcing relayed addresses. Peers connecting over
+// a relay try to upgrade to a direct connection with hole punching. Relayed
+// connections are authenticated and checked against the firewall the same
+// way as direct ones.
+type NATConfig struct {
+	// Reachability is one of ReachabilityAuto, ReachabilityPublic, or
+	// ReachabilityPrivate. Empty value is treated as ReachabilityAuto.
+	// Automatic detection requires peers running the AutoNAT service.
+	Reachability string
+	// PortMapping enables port mapping on the NAT device with UPnP and
+	// NAT-PMP.
+	PortMapping bool
+	// HolePunching enables upgrading relayed connections to direct ones.
+	HolePunching bool
+	// AutoNATService enables serving AutoNAT reachability checks of other
+	// peers. Enabling the service assumes the node is publicly reachable.
+	// Should be enabled only on publicly reachable nodes, e.g. bootstraps.
+	AutoNATService bool
+	// RelayService enables the circuit relay v2 service once the node is
+	// publicly reachable. Should be enabled only on selected bootstraps.
+	RelayService bool
+	// Relays are addresses of circuit relay v2 servers the node makes
+	// reservations with if it is not publicly reachable. Addresses must be
+	// in the format: <endpoint>/ipfs/<cid>
+	Relays []string
+}
+
+func (nc *NATConfig) validate() error {
+	switch nc.Reachability {
+	case "", ReachabilityAuto, ReachabilityPublic, ReachabilityPrivate:
+	default:
+		return fmt.Errorf("unknown reachability [%v]", nc.Reachability)
+	}
+
+	if nc.AutoNATService && nc.Reachability == ReachabilityPrivate {
+		return fmt.Errorf(
+			"AutoNAT service cannot be enabled for a privately reachable node",
+		)
+	}
+
+	if _, err := extractMultiAddrFromPeers(nc.Relays); err != nil {
+		return fmt.Errorf("invalid relay address: [%v]", err)
+	}
+
+	return nil
+}
+
+// options returns libp2p options enabling NAT traversal. The config must be
+// validated before.
+func (nc *NATConfig) options() ([]libp2p.Option, error) {
+	options := make([]libp2p.Option, 0)
+
+	switch {
+	case nc.Reachability == ReachabilityPublic || nc.AutoNATService:
+		options = append(options, libp2p.ForceReachabilityPublic())
+	case nc.Reachability == ReachabilityPrivate:
+		options = append(options, libp2p.ForceReachabilityPrivate())
+	}
+
+	if nc.PortMapping {
+		options = append(options, libp2p.NATPortMap())
+	}
+
+	if nc.HolePunching {
+		options = append(options, libp2p.EnableHolePunching())
+	}
+
+	if nc.RelayService {
+		options = append(options, libp2p.EnableRelayService())
+	}
+
+	if len(nc.Relays) > 0 {
+		relays, err := extractMultiAddrFromPeers(nc.Relays)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, libp2p.EnableAutoRelayWithStaticRelays(relays))
+	}
+
+	return options, nil
+}
+
+// enableAutoNATService starts serving AutoNAT reachability checks of other
+// peers on the given host. The AutoNAT service built into libp2p dials peers
+// back with a random identity which is rejected by the firewall of the
+// checked peer. Here, dial-backs are done by a separate dial-only host
+// sharing the identity, the private network key, and the security transport
+// with the node.
+func enableAutoNATService(
+	p2phost host.Host,
+	identity *identity,
+	privateNetworkKey pnet.PSK,
+	securityOption libp2p.Option,
+) error {
+	options := []libp2p.Option{
+		libp2p.Identity(identity.privKey),
+		libp2p.NoListenAddrs,
+		libp2p.Transport(tcp.NewTCPTransport),
+		securityOption,
+		libp2p.DisableRelay(),
+	}
+
+	if privateNetworkKey != nil {
+		options = append(options, libp2p.PrivateNetwork(privateNetworkKey))
+	}
+
+	dialer, err := libp2p.New(options...)
+	if err != nil {
+		return fmt.Errorf("could not create AutoNAT dialer: [%v]", err)
+	}
+
+	_, err = autonat.New(
+		p2phost,
+		autonat.EnableService(dialer.Network()),
+		autonat.WithReachability(libp2pnet.ReachabilityPublic),
+	)
+	if err != nil {
+		return fmt.Errorf("could not start AutoNAT service: [%v]", err)
+	}
+
+	return nil
+}
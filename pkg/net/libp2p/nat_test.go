@@ -0,0 +1,215 @@
+package libp2p
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+const (
+	autoNATProtocolID    = protocol.ID("/libp2p/autonat/1.0.0")
+	relayHopProtocolID   = protocol.ID("/libp2p/circuit/relay/0.2.0/hop")
+	autoRelayTag         = "autorelay"
+	natTestRelayAddress  = "/ip4/127.0.0.1/tcp/3919/ipfs/16Uiu2HAmFRJtCWfdXhZEZHWb4tUpH1QMMgzH1oiamCfUuK6NgqWX"
+	natTestCheckInterval = 100 * time.Millisecond
+)
+
+func TestNATConfig_Validate(t *testing.T) {
+	var tests = map[string]struct {
+		config        NATConfig
+		expectedError error
+	}{
+		"empty config": {
+			config:        NATConfig{},
+			expectedError: nil,
+		},
+		"auto reachability": {
+			config:        NATConfig{Reachability: ReachabilityAuto},
+			expectedError: nil,
+		},
+		"public reachability with services": {
+			config: NATConfig{
+				Reachability:   ReachabilityPublic,
+				AutoNATService: true,
+				RelayService:   true,
+			},
+			expectedError: nil,
+		},
+		"private reachability with relays": {
+			config: NATConfig{
+				Reachability: ReachabilityPrivate,
+				HolePunching: true,
+				Relays:       []string{natTestRelayAddress},
+			},
+			expectedError: nil,
+		},
+		"unknown reachability": {
+			config:        NATConfig{Reachability: "unknown"},
+			expectedError: fmt.Errorf("unknown reachability [unknown]"),
+		},
+		"private reachability with AutoNAT service": {
+			config: NATConfig{
+				Reachability:   ReachabilityPrivate,
+				AutoNATService: true,
+			},
+			expectedError: fmt.Errorf(
+				"AutoNAT service cannot be enabled for a privately reachable node",
+			),
+		},
+		"relay address without peer ID": {
+			config: NATConfig{
+				Relays: []string{"/ip4/127.0.0.1/tcp/3919"},
+			},
+			expectedError: fmt.Errorf(
+				"invalid relay address: [invalid p2p multiaddr]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := test.config.validate()
+
+			if fmt.Sprintf("%v", test.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestConnect_NATServices(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	netProvider, err := Connect(
+		ctx,
+		Config{
+			Port: 8086,
+			NAT: NATConfig{
+				HolePunching:   true,
+				AutoNATService: true,
+				RelayService:   true,
+			},
+		},
+		operatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := netProvider.(*provider).host
+
+	// Services are started asynchronously once the host learns it is
+	// publicly reachable.
+	for _, protocolID := range []protocol.ID{
+		autoNATProtocolID,
+		relayHopProtocolID,
+	} {
+		for !supportsProtocol(host.Mux().Protocols(), protocolID) {
+			select {
+			case <-ctx.Done():
+				t.Fatalf("protocol [%v] is not supported", protocolID)
+			case <-time.After(natTestCheckInterval):
+			}
+		}
+	}
+}
+
+func TestConnect_StaticRelay(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	relayOperatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relayProvider, err := Connect(
+		ctx,
+		Config{
+			Port: 8087,
+			NAT: NATConfig{
+				Reachability: ReachabilityPublic,
+				RelayService: true,
+			},
+		},
+		relayOperatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relayAddress := fmt.Sprintf(
+		"/ip4/127.0.0.1/tcp/8087/ipfs/%v",
+		relayProvider.ID(),
+	)
+
+	operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	netProvider, err := Connect(
+		ctx,
+		Config{
+			Port: 8088,
+			NAT: NATConfig{
+				Reachability: ReachabilityPrivate,
+				HolePunching: true,
+				Relays:       []string{relayAddress},
+			},
+		},
+		operatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := netProvider.(*provider).host
+
+	relayPeerID, err := peer.Decode(relayProvider.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The connection with the relay is protected once the node makes
+	// a reservation with it. Relayed addresses are not announced as
+	// the relay has no public address in the test.
+	for !host.ConnManager().IsProtected(relayPeerID, autoRelayTag) {
+		select {
+		case <-ctx.Done():
+			t.Fatal("no reservation made with the relay")
+		case <-time.After(natTestCheckInterval):
+		}
+	}
+}
+
+func supportsProtocol(protocols []protocol.ID, protocolID protocol.ID) bool {
+	for _, p := range protocols {
+		if p == protocolID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package libp2p
+
+import (
+	"sync"
+	"sync/atomic"
+
+	flow "github.com/libp2p/go-flow-metrics"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+// networkStats collects connectivity and traffic statistics of the provider
+// that are not tracked by the broadcast channel manager.
+type networkStats struct {
+	handshakeFailures     uint64
+	bootstrapFailedRounds int64
+
+	bandwidthCounter *metrics.BandwidthCounter
+}
+
+func newNetworkStats() *networkStats {
+	return &networkStats{
+		bandwidthCounter: metrics.NewBandwidthCounter(),
+	}
+}
+
+// handshakeFailed records a failed attempt to establish an encrypted and
+// authenticated connection with a remote peer, including peers rejected
+// by the firewall.
+func (ns *networkStats) handshakeFailed() {
+	atomic.AddUint64(&ns.handshakeFailures, 1)
+}
+
+// bootstrapRoundCompleted records the result of a bootstrap round. A
+// successful round resets the number of consecutive failed rounds.
+func (ns *networkStats) bootstrapRoundCompleted(err error) {
+	if err != nil {
+		atomic.AddInt64(&ns.bootstrapFailedRounds, 1)
+		return
+	}
+
+	atomic.StoreInt64(&ns.bootstrapFailedRounds, 0)
+}
+
+func (ns *networkStats) handshakeFailuresCount() uint64 {
+	return atomic.LoadUint64(&ns.handshakeFailures)
+}
+
+func (ns *networkStats) bootstrapFailedRoundsCount() int {
+	return int(atomic.LoadInt64(&ns.bootstrapFailedRounds))
+}
+
+// topicMessagesTracer is a pubsub tracer metering messages received from
+// other peers in every broadcast channel. Pubsub does not trace messages
+// published by the provider itself so broadcast channels meter them
+// explicitly by calling messageSent.
+type topicMessagesTracer struct {
+	noopRawTracer
+
+	metersMutex sync.Mutex
+	received    map[string]*flow.Meter
+	sent        map[string]*flow.Meter
+}
+
+func newTopicMessagesTracer() *topicMessagesTracer {
+	return &topicMessagesTracer{
+		received: make(map[string]*flow.Meter),
+		sent:     make(map[string]*flow.Meter),
+	}
+}
+
+func (tmt *topicMessagesTracer) DeliverMessage(message *pubsub.Message) {
+	tmt.mark(tmt.received, message.GetTopic())
+}
+
+// messageSent records a message published by the provider to the given
+// topic.
+func (tmt *topicMessagesTracer) messageSent(topic string) {
+	tmt.mark(tmt.sent, topic)
+}
+
+func (tmt *topicMessagesTracer) mark(meters map[string]*flow.Meter, topic string) {
+	tmt.metersMutex.Lock()
+	defer tmt.metersMutex.Unlock()
+
+	meter, ok := meters[topic]
+	if !ok {
+		meter = flow.NewMeter()
+		meters[topic] = meter
+	}
+
+	meter.Mark(1)
+}
+
+// topicStats returns statistics of messages delivered in every broadcast
+// channel since the provider was started.
+func (tmt *topicMessagesTracer) topicStats() map[string]net.TopicStats {
+	tmt.metersMutex.Lock()
+	defer tmt.metersMutex.Unlock()
+
+	stats := make(map[string]net.TopicStats)
+
+	for topic, meter := range tmt.received {
+		snapshot := meter.Snapshot()
+
+		topicStats := stats[topic]
+		topicStats.ReceivedMessages = snapshot.Total
+		topicStats.ReceivedMessagesRate = snapshot.Rate
+		stats[topic] = topicStats
+	}
+
+	for topic, meter := range tmt.sent {
+		snapshot := meter.Snapshot()
+
+		topicStats := stats[topic]
+		topicStats.SentMessages = snapshot.Total
+		topicStats.SentMessagesRate = snapshot.Rate
+		stats[topic] = topicStats
+	}
+
+	return stats
+}
+
+// noopRawTracer implements the pubsub.RawTracer interface ignoring all
+// events. It is meant to be embedded by tracers interested only in some
+// of the events.
+type noopRawTracer struct{}
+
+func (nrt noopRawTracer) AddPeer(peer.ID, protocol.ID)          {}
+func (nrt noopRawTracer) RemovePeer(peer.ID)                    {}
+func (nrt noopRawTracer) Join(string)                           {}
+func (nrt noopRawTracer) Leave(string)                          {}
+func (nrt noopRawTracer) Graft(peer.ID, string)                 {}
+func (nrt noopRawTracer) Prune(peer.ID, string)                 {}
+func (nrt noopRawTracer) ValidateMessage(*pubsub.Message)       {}
+func (nrt noopRawTracer) DeliverMessage(*pubsub.Message)        {}
+func (nrt noopRawTracer) RejectMessage(*pubsub.Message, string) {}
+func (nrt noopRawTracer) DuplicateMessage(*pubsub.Message)      {}
+func (nrt noopRawTracer) ThrottlePeer(peer.ID)                  {}
+func (nrt noopRawTracer) RecvRPC(*pubsub.RPC)                   {}
+func (nrt noopRawTracer) SendRPC(*pubsub.RPC, peer.ID)          {}
+func (nrt noopRawTracer) DropRPC(*pubsub.RPC, peer.ID)          {}
+func (nrt noopRawTracer) UndeliverableMessage(*pubsub.Message)  {}
@@ -0,0 +1,194 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestTopicMessagesTracer(t *testing.T) {
+	tracer := newTopicMessagesTracer()
+
+	deliver := func(topic string, count int) {
+		for i := 0; i < count; i++ {
+			tracer.DeliverMessage(&pubsub.Message{
+				Message:      &pb.Message{Topic: &topic},
+				ReceivedFrom: generateTestIdentity(t).id,
+			})
+		}
+	}
+
+	send := func(topic string, count int) {
+		for i := 0; i < count; i++ {
+			tracer.messageSent(topic)
+		}
+	}
+
+	deliver("channel-1", 3)
+	send("channel-1", 1)
+	send("channel-2", 2)
+
+	expectedTotals := map[string][2]uint64{
+		"channel-1": {3, 1},
+		"channel-2": {0, 2},
+	}
+
+	// Meters totals are updated asynchronously, once per second.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		actualTotals := make(map[string][2]uint64)
+		for topic, topicStats := range tracer.topicStats() {
+			actualTotals[topic] = [2]uint64{
+				topicStats.ReceivedMessages,
+				topicStats.SentMessages,
+			}
+		}
+
+		if reflect.DeepEqual(expectedTotals, actualTotals) {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf(
+				"unexpected received and sent messages\n"+
+					"expected: [%v]\nactual:   [%v]",
+				expectedTotals,
+				actualTotals,
+			)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestNetworkStats_BootstrapRoundCompleted(t *testing.T) {
+	stats := newNetworkStats()
+
+	stats.bootstrapRoundCompleted(fmt.Errorf("all bootstrap attempts failed"))
+	stats.bootstrapRoundCompleted(fmt.Errorf("all bootstrap attempts failed"))
+
+	if count := stats.bootstrapFailedRoundsCount(); count != 2 {
+		t.Errorf(
+			"unexpected failed rounds count\nexpected: [2]\nactual:   [%v]",
+			count,
+		)
+	}
+
+	stats.bootstrapRoundCompleted(nil)
+
+	if count := stats.bootstrapFailedRoundsCount(); count != 0 {
+		t.Errorf(
+			"unexpected failed rounds count\nexpected: [0]\nactual:   [%v]",
+			count,
+		)
+	}
+}
+
+func TestProviderNetworkStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	connect := func(port int, firewall net.Firewall) *provider {
+		operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		netProvider, err := Connect(
+			ctx,
+			Config{Port: port},
+			operatorPrivateKey,
+			firewall,
+			idleTicker(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return netProvider.(*provider)
+	}
+
+	sender := connect(8092, firewall.Disabled)
+	receiver := connect(8093, firewall.Disabled)
+	// Rejects all peers so that every connection attempt fails.
+	rejecting := connect(8094, newMockFirewall())
+
+	err := sender.host.Connect(ctx, peer.AddrInfo{
+		ID:    receiver.identity.id,
+		Addrs: receiver.host.Addrs(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The connection is rejected by the responder's firewall once the
+	// initiator already considers the handshake complete so the result
+	// is not deterministic for the initiator.
+	_ = sender.host.Connect(ctx, peer.AddrInfo{
+		ID:    rejecting.identity.id,
+		Addrs: rejecting.host.Addrs(),
+	})
+
+	channels := make([]net.BroadcastChannel, 2)
+	for i, provider := range []*provider{sender, receiver} {
+		channel, err := provider.BroadcastChannelFor("testchannel")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		channel.SetUnmarshaler(
+			func() net.TaggedUnmarshaler { return &testMessage{} },
+		)
+
+		channels[i] = channel
+	}
+
+	for receiver.NetworkStats().Topics["testchannel"].ReceivedMessages == 0 {
+		// Subscriptions are propagated asynchronously so keep sending until
+		// the receiver is known to the sender.
+		if err := channels[0].Send(
+			ctx,
+			&testMessage{Payload: "some text"},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("no messages received")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	senderStats := sender.NetworkStats()
+
+	if senderStats.Topics["testchannel"].SentMessages == 0 {
+		t.Error("no sent messages recorded by the sender")
+	}
+	if senderStats.BytesOut == 0 {
+		t.Error("no sent bytes recorded by the sender")
+	}
+	if receiver.NetworkStats().BytesIn == 0 {
+		t.Error("no received bytes recorded by the receiver")
+	}
+	if rejecting.NetworkStats().HandshakeFailures == 0 {
+		t.Error("no handshake failures recorded by the rejecting peer")
+	}
+	if receiver.NetworkStats().HandshakeFailures != 0 {
+		t.Errorf(
+			"unexpected handshake failures of the receiver\n"+
+				"expected: [0]\nactual:   [%v]",
+			receiver.NetworkStats().HandshakeFailures,
+		)
+	}
+}
@@ -0,0 +1,164 @@
+package libp2p
+
+import (
+	"fmt"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Default values of the peer scoring configuration.
+const (
+	// DefaultPeerScoringTopicWeight is the default weight of the score of
+	// every broadcast channel topic.
+	DefaultPeerScoringTopicWeight = 1.0
+	// DefaultPeerScoringInvalidMessagePenaltyWeight is the default weight of
+	// the penalty for delivering invalid messages. The penalty grows with
+	// the square of the number of invalid messages delivered by the peer.
+	DefaultPeerScoringInvalidMessagePenaltyWeight = -100.0
+	// DefaultPeerScoringInvalidMessagePenaltyDecay is the default time after
+	// which the penalty for delivering invalid messages decays to zero.
+	DefaultPeerScoringInvalidMessagePenaltyDecay = 10 * time.Minute
+	// DefaultPeerScoringIPColocationWeight is the default weight of the
+	// penalty for peers sharing the same IP address.
+	DefaultPeerScoringIPColocationWeight = -10.0
+	// DefaultPeerScoringIPColocationThreshold is the default number of peers
+	// that can share the same IP address without being penalized.
+	DefaultPeerScoringIPColocationThreshold = 10
+	// DefaultPeerScoringGossipThreshold is the default score below which
+	// gossip is neither emitted to nor accepted from the peer.
+	DefaultPeerScoringGossipThreshold = -500.0
+	// DefaultPeerScoringPublishThreshold is the default score below which
+	// own messages are not published to the peer.
+	DefaultPeerScoringPublishThreshold = -1000.0
+	// DefaultPeerScoringGraylistThreshold is the default score below which
+	// all messages from the peer are ignored.
+	DefaultPeerScoringGraylistThreshold = -2500.0
+)
+
+const (
+	// peerScoringDecayInterval is the interval at which peer score counters
+	// are decayed.
+	peerScoringDecayInterval = pubsub.DefaultDecayInterval
+	// peerScoringRetainScore is the time the score of a disconnected peer is
+	// retained for so the peer cannot reset its score by reconnecting.
+	peerScoringRetainScore = 30 * time.Minute
+	// peerScoringTimeInMeshQuantum is the time in mesh quantum. It is required
+	// by pubsub even though the time in mesh is not scored.
+	peerScoringTimeInMeshQuantum = time.Second
+)
+
+// PeerScoringConfig defines the configuration of the gossipsub peer scoring.
+// Peer scoring requires the gossipsub router, so it replaces the default
+// floodsub router when enabled. Gossipsub is backward-compatible with
+// floodsub peers.
+type PeerScoringConfig struct {
+	Enabled bool
+	// TopicWeight is the weight of the score of every broadcast channel
+	// topic. Must not be negative.
+	TopicWeight float64
+	// InvalidMessagePenaltyWeight is the weight of the penalty for delivering
+	// messages rejected by the broadcast channel filter. Must not be positive.
+	InvalidMessagePenaltyWeight float64
+	// InvalidMessagePenaltyDecay is the time after which the penalty for
+	// delivering invalid messages decays to zero.
+	InvalidMessagePenaltyDecay time.Duration
+	// IPColocationWeight is the weight of the penalty for peers sharing
+	// the same IP address. Must not be positive.
+	IPColocationWeight float64
+	// IPColocationThreshold is the number of peers that can share the same
+	// IP address without being penalized.
+	IPColocationThreshold int
+	// GossipThreshold is the score below which gossip is neither emitted to
+	// nor accepted from the peer. Must not be positive.
+	GossipThreshold float64
+	// PublishThreshold is the score below which own messages are not
+	// published to the peer. Must not be greater than GossipThreshold.
+	PublishThreshold float64
+	// GraylistThreshold is the score below which all messages from the peer
+	// are ignored. Must not be greater than PublishThreshold.
+	GraylistThreshold float64
+}
+
+func (psc *PeerScoringConfig) validate() error {
+	if psc.TopicWeight < 0 {
+		return fmt.Errorf("topic weight must not be negative")
+	}
+
+	if psc.InvalidMessagePenaltyWeight > 0 {
+		return fmt.Errorf("invalid message penalty weight must not be positive")
+	}
+
+	if psc.InvalidMessagePenaltyDecay < peerScoringDecayInterval {
+		return fmt.Errorf(
+			"invalid message penalty decay must be at least [%v]",
+			peerScoringDecayInterval,
+		)
+	}
+
+	if psc.IPColocationWeight > 0 {
+		return fmt.Errorf("IP colocation weight must not be positive")
+	}
+
+	if psc.IPColocationWeight != 0 && psc.IPColocationThreshold < 1 {
+		return fmt.Errorf("IP colocation threshold must be at least 1")
+	}
+
+	if psc.GossipThreshold > 0 {
+		return fmt.Errorf("gossip threshold must not be positive")
+	}
+
+	if psc.PublishThreshold > psc.GossipThreshold {
+		return fmt.Errorf(
+			"publish threshold must not be greater than gossip threshold",
+		)
+	}
+
+	if psc.GraylistThreshold > psc.PublishThreshold {
+		return fmt.Errorf(
+			"graylist threshold must not be greater than publish threshold",
+		)
+	}
+
+	return nil
+}
+
+// peerScoreParams returns the peer score parameters. Topic-specific
+// parameters are set separately for each joined topic; see topicScoreParams.
+func (psc *PeerScoringConfig) peerScoreParams() *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		Topics: make(map[string]*pubsub.TopicScoreParams),
+		AppSpecificScore: func(peer.ID) float64 {
+			return 0
+		},
+		IPColocationFactorWeight:    psc.IPColocationWeight,
+		IPColocationFactorThreshold: psc.IPColocationThreshold,
+		DecayInterval:               peerScoringDecayInterval,
+		DecayToZero:                 pubsub.DefaultDecayToZero,
+		RetainScore:                 peerScoringRetainScore,
+	}
+}
+
+// topicScoreParams returns the score parameters of a broadcast channel topic.
+// Only invalid message deliveries are scored; message delivery rates are not
+// as broadcast channel traffic is bursty and heavily depends on the protocol
+// being executed.
+func (psc *PeerScoringConfig) topicScoreParams() *pubsub.TopicScoreParams {
+	return &pubsub.TopicScoreParams{
+		TopicWeight:                    psc.TopicWeight,
+		TimeInMeshQuantum:              peerScoringTimeInMeshQuantum,
+		InvalidMessageDeliveriesWeight: psc.InvalidMessagePenaltyWeight,
+		InvalidMessageDeliveriesDecay: pubsub.ScoreParameterDecay(
+			psc.InvalidMessagePenaltyDecay,
+		),
+	}
+}
+
+func (psc *PeerScoringConfig) peerScoreThresholds() *pubsub.PeerScoreThresholds {
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:   psc.GossipThreshold,
+		PublishThreshold:  psc.PublishThreshold,
+		GraylistThreshold: psc.GraylistThreshold,
+	}
+}
@@ -0,0 +1,202 @@
+package libp2p
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestPeerScoringConfig_Validate(t *testing.T) {
+	var tests = map[string]struct {
+		modifyConfig  func(config *PeerScoringConfig)
+		expectedError error
+	}{
+		"default config": {
+			modifyConfig:  func(config *PeerScoringConfig) {},
+			expectedError: nil,
+		},
+		"negative topic weight": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.TopicWeight = -1
+			},
+			expectedError: fmt.Errorf("topic weight must not be negative"),
+		},
+		"positive invalid message penalty weight": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.InvalidMessagePenaltyWeight = 1
+			},
+			expectedError: fmt.Errorf(
+				"invalid message penalty weight must not be positive",
+			),
+		},
+		"invalid message penalty decay too short": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.InvalidMessagePenaltyDecay = 500 * time.Millisecond
+			},
+			expectedError: fmt.Errorf(
+				"invalid message penalty decay must be at least [1s]",
+			),
+		},
+		"positive IP colocation weight": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.IPColocationWeight = 1
+			},
+			expectedError: fmt.Errorf("IP colocation weight must not be positive"),
+		},
+		"zero IP colocation threshold": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.IPColocationThreshold = 0
+			},
+			expectedError: fmt.Errorf("IP colocation threshold must be at least 1"),
+		},
+		"zero IP colocation threshold with IP colocation disabled": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.IPColocationWeight = 0
+				config.IPColocationThreshold = 0
+			},
+			expectedError: nil,
+		},
+		"positive gossip threshold": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.GossipThreshold = 1
+			},
+			expectedError: fmt.Errorf("gossip threshold must not be positive"),
+		},
+		"publish threshold above gossip threshold": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.PublishThreshold = config.GossipThreshold + 1
+			},
+			expectedError: fmt.Errorf(
+				"publish threshold must not be greater than gossip threshold",
+			),
+		},
+		"graylist threshold above publish threshold": {
+			modifyConfig: func(config *PeerScoringConfig) {
+				config.GraylistThreshold = config.PublishThreshold + 1
+			},
+			expectedError: fmt.Errorf(
+				"graylist threshold must not be greater than publish threshold",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			config := defaultTestPeerScoringConfig()
+			test.modifyConfig(&config)
+
+			err := config.validate()
+
+			if fmt.Sprintf("%v", test.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestSendReceive_PeerScoringEnabled(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	config := Config{
+		Port:        8084,
+		PeerScoring: defaultTestPeerScoringConfig(),
+	}
+
+	operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := Connect(
+		ctx,
+		config,
+		operatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broadcastChannel, err := provider.BroadcastChannelFor("testchannel")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broadcastChannel.SetUnmarshaler(
+		func() net.TaggedUnmarshaler { return &testMessage{} },
+	)
+
+	recvChan := make(chan net.Message, 1)
+	broadcastChannel.Recv(ctx, func(msg net.Message) {
+		recvChan <- msg
+	})
+
+	if err := broadcastChannel.Send(
+		ctx,
+		&testMessage{Payload: "some text"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-recvChan:
+	case <-ctx.Done():
+		t.Fatal("expected message not received")
+	}
+}
+
+func TestConnect_InvalidPeerScoringConfig(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	peerScoring := defaultTestPeerScoringConfig()
+	peerScoring.TopicWeight = -1
+
+	operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Connect(
+		ctx,
+		Config{Port: 8085, PeerScoring: peerScoring},
+		operatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+
+	expectedErr := fmt.Errorf(
+		"invalid peer scoring config: [topic weight must not be negative]",
+	)
+	if err == nil || err.Error() != expectedErr.Error() {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func defaultTestPeerScoringConfig() PeerScoringConfig {
+	return PeerScoringConfig{
+		Enabled:                     true,
+		TopicWeight:                 DefaultPeerScoringTopicWeight,
+		InvalidMessagePenaltyWeight: DefaultPeerScoringInvalidMessagePenaltyWeight,
+		InvalidMessagePenaltyDecay:  DefaultPeerScoringInvalidMessagePenaltyDecay,
+		IPColocationWeight:          DefaultPeerScoringIPColocationWeight,
+		IPColocationThreshold:       DefaultPeerScoringIPColocationThreshold,
+		GossipThreshold:             DefaultPeerScoringGossipThreshold,
+		PublishThreshold:            DefaultPeerScoringPublishThreshold,
+		GraylistThreshold:           DefaultPeerScoringGraylistThreshold,
+	}
+}
@@ -0,0 +1,38 @@
+package libp2p
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/libp2p/go-libp2p/core/pnet"
+)
+
+// readPrivateNetworkKey reads the pre-shared key of a private network from
+// the given file. The file must be in the libp2p swarm key format:
+//
+//	/key/swarm/psk/1.0.0/
+//	/base16/
+//	<64 hex characters>
+//
+// Nil key is returned if the path is empty, meaning the node joins the public
+// network.
+func readPrivateNetworkKey(path string) (pnet.PSK, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	// #nosec G304 (file path provided as taint input)
+	// The path is provided by the node operator in the client configuration.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open key file: [%v]", err)
+	}
+	defer file.Close()
+
+	psk, err := pnet.DecodeV1PSK(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode key file: [%v]", err)
+	}
+
+	return psk, nil
+}
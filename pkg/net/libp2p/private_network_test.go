@@ -0,0 +1,174 @@
+package libp2p
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+const (
+	testPrivateNetworkKey = "/key/swarm/psk/1.0.0/\n/base16/\n" +
+		"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	otherTestPrivateNetworkKey = "/key/swarm/psk/1.0.0/\n/base16/\n" +
+		"fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+)
+
+func TestReadPrivateNetworkKey(t *testing.T) {
+	keyFile := writeTestPrivateNetworkKey(t, testPrivateNetworkKey)
+
+	psk, err := readPrivateNetworkKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedKey := []byte{
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+	}
+	if !bytes.Equal(expectedKey, psk) {
+		t.Errorf(
+			"unexpected key\nexpected: [%x]\nactual:   [%x]",
+			expectedKey,
+			psk,
+		)
+	}
+}
+
+func TestReadPrivateNetworkKey_EmptyPath(t *testing.T) {
+	psk, err := readPrivateNetworkKey("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if psk != nil {
+		t.Errorf("unexpected key: [%x]", psk)
+	}
+}
+
+func TestReadPrivateNetworkKey_Errors(t *testing.T) {
+	var tests = map[string]struct {
+		keyFileContent string
+		expectedError  error
+	}{
+		"invalid header": {
+			keyFileContent: strings.Replace(
+				testPrivateNetworkKey,
+				"1.0.0",
+				"2.0.0",
+				1,
+			),
+			expectedError: fmt.Errorf(
+				"could not decode key file: [expected file header " +
+					"/key/swarm/psk/1.0.0/, got: /key/swarm/psk/2.0.0/]",
+			),
+		},
+		"too short key": {
+			keyFileContent: "/key/swarm/psk/1.0.0/\n/base16/\n0123456789abcdef",
+			expectedError: fmt.Errorf(
+				"could not decode key file: [unexpected EOF]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			keyFile := writeTestPrivateNetworkKey(t, test.keyFileContent)
+
+			_, err := readPrivateNetworkKey(keyFile)
+			if !reflect.DeepEqual(
+				fmt.Sprintf("%v", test.expectedError),
+				fmt.Sprintf("%v", err),
+			) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestConnect_PrivateNetwork(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	keyFile := writeTestPrivateNetworkKey(t, testPrivateNetworkKey)
+	otherKeyFile := writeTestPrivateNetworkKey(t, otherTestPrivateNetworkKey)
+
+	connect := func(port int, keyFile string) *provider {
+		operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		netProvider, err := Connect(
+			ctx,
+			Config{Port: port, PrivateNetworkKeyFile: keyFile},
+			operatorPrivateKey,
+			firewall.Disabled,
+			idleTicker(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return netProvider.(*provider)
+	}
+
+	private := connect(8099, keyFile)
+	samePrivate := connect(8100, keyFile)
+	otherPrivate := connect(8101, otherKeyFile)
+	public := connect(8102, "")
+
+	addrInfo := func(provider *provider) peer.AddrInfo {
+		return peer.AddrInfo{
+			ID:    provider.identity.id,
+			Addrs: provider.host.Addrs(),
+		}
+	}
+
+	if err := private.host.Connect(ctx, addrInfo(samePrivate)); err != nil {
+		t.Errorf("could not connect with the same private network: [%v]", err)
+	}
+
+	// Peers of different networks cannot complete the handshake so the
+	// connection attempts fail or time out.
+	connectWithTimeout := func(provider *provider) error {
+		connectCtx, connectCancel := context.WithTimeout(ctx, 2*time.Second)
+		defer connectCancel()
+
+		return private.host.Connect(connectCtx, addrInfo(provider))
+	}
+
+	if err := connectWithTimeout(otherPrivate); err == nil {
+		t.Error("expected the connection with other private network to fail")
+	}
+
+	if err := connectWithTimeout(public); err == nil {
+		t.Error("expected the connection with the public network to fail")
+	}
+}
+
+func writeTestPrivateNetworkKey(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "swarm.key")
+
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
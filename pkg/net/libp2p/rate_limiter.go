@@ -0,0 +1,200 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"golang.org/x/time/rate"
+)
+
+// Default values of the broadcast channel rate limiting configuration.
+const (
+	// DefaultRateLimitMessagesPerSecond is the default sustained number of
+	// messages per second a single sender can publish to a broadcast channel.
+	DefaultRateLimitMessagesPerSecond = 50.0
+	// DefaultRateLimitBurst is the default maximum number of messages a single
+	// sender can publish to a broadcast channel at once.
+	DefaultRateLimitBurst = 500
+	// DefaultRateLimitDisconnectThreshold is the default number of messages
+	// over the budget after which the sender is disconnected.
+	DefaultRateLimitDisconnectThreshold = 100
+)
+
+const (
+	// rateLimitSenderExpiry is the time after which the budget of a sender
+	// that has not published any message is forgotten.
+	rateLimitSenderExpiry = 10 * time.Minute
+	// rateLimitPruneInterval is the interval at which expired sender budgets
+	// are removed.
+	rateLimitPruneInterval = time.Minute
+)
+
+// RateLimitConfig defines the configuration of the per-sender rate limiting
+// of broadcast channel messages. Each sender has a separate budget in every
+// broadcast channel. Messages over the budget are dropped and not forwarded
+// to other peers. A sender exceeding the budget repeatedly is disconnected.
+type RateLimitConfig struct {
+	Enabled bool
+	// MessagesPerSecond is the sustained number of messages per second
+	// a single sender can publish to a broadcast channel. Must be positive.
+	MessagesPerSecond float64
+	// Burst is the maximum number of messages a single sender can publish
+	// to a broadcast channel at once. Must be positive.
+	Burst int
+	// DisconnectThreshold is the number of messages over the budget after
+	// which the sender is disconnected. Zero value disables disconnecting.
+	DisconnectThreshold int
+}
+
+func (rlc *RateLimitConfig) validate() error {
+	if rlc.MessagesPerSecond <= 0 {
+		return fmt.Errorf("messages per second must be positive")
+	}
+
+	if rlc.Burst <= 0 {
+		return fmt.Errorf("burst must be positive")
+	}
+
+	if rlc.DisconnectThreshold < 0 {
+		return fmt.Errorf("disconnect threshold must not be negative")
+	}
+
+	return nil
+}
+
+type senderKey struct {
+	topic  string
+	sender peer.ID
+}
+
+type senderBudget struct {
+	limiter    *rate.Limiter
+	violations int
+	lastSeen   time.Time
+}
+
+// senderRateLimiter limits the rate of broadcast channel messages published
+// by every sender. Messages are attributed to their authors, not to peers
+// relaying them.
+type senderRateLimiter struct {
+	config     RateLimitConfig
+	ownID      peer.ID
+	disconnect func(peer.ID)
+
+	sendersMutex sync.Mutex
+	senders      map[senderKey]*senderBudget
+}
+
+func newSenderRateLimiter(
+	ctx context.Context,
+	config RateLimitConfig,
+	ownID peer.ID,
+	disconnect func(peer.ID),
+) *senderRateLimiter {
+	limiter := &senderRateLimiter{
+		config:     config,
+		ownID:      ownID,
+		disconnect: disconnect,
+		senders:    make(map[senderKey]*senderBudget),
+	}
+
+	go func() {
+		ticker := time.NewTicker(rateLimitPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				limiter.prune(time.Now())
+			}
+		}
+	}()
+
+	return limiter
+}
+
+// allow returns true if the sender has the budget to publish the message to
+// the given topic. It disconnects the sender once it exceeds the budget
+// the configured number of times.
+func (srl *senderRateLimiter) allow(topic string, sender peer.ID) bool {
+	// Own messages are never limited.
+	if sender == srl.ownID {
+		return true
+	}
+
+	now := time.Now()
+	key := senderKey{topic: topic, sender: sender}
+
+	srl.sendersMutex.Lock()
+
+	budget, ok := srl.senders[key]
+	if !ok {
+		budget = &senderBudget{
+			limiter: rate.NewLimiter(
+				rate.Limit(srl.config.MessagesPerSecond),
+				srl.config.Burst,
+			),
+		}
+		srl.senders[key] = budget
+	}
+	budget.lastSeen = now
+
+	if budget.limiter.AllowN(now, 1) {
+		srl.sendersMutex.Unlock()
+		return true
+	}
+
+	budget.violations++
+	shouldDisconnect := srl.config.DisconnectThreshold > 0 &&
+		budget.violations >= srl.config.DisconnectThreshold
+	if shouldDisconnect {
+		budget.violations = 0
+	}
+
+	srl.sendersMutex.Unlock()
+
+	if shouldDisconnect {
+		logger.Warnf(
+			"disconnecting peer [%v] exceeding message budget of channel [%v]",
+			sender,
+			topic,
+		)
+		srl.disconnect(sender)
+	}
+
+	return false
+}
+
+func (srl *senderRateLimiter) prune(now time.Time) {
+	srl.sendersMutex.Lock()
+	defer srl.sendersMutex.Unlock()
+
+	for key, budget := range srl.senders {
+		if now.Sub(budget.lastSeen) > rateLimitSenderExpiry {
+			delete(srl.senders, key)
+		}
+	}
+}
+
+// validator returns a pubsub validator dropping messages of senders without
+// the budget. Dropped messages are ignored rather than rejected so that peers
+// relaying them are not penalized by peer scoring.
+func (srl *senderRateLimiter) validator() pubsub.ValidatorEx {
+	return func(
+		_ context.Context,
+		_ peer.ID,
+		message *pubsub.Message,
+	) pubsub.ValidationResult {
+		if !srl.allow(message.GetTopic(), message.GetFrom()) {
+			return pubsub.ValidationIgnore
+		}
+
+		return pubsub.ValidationAccept
+	}
+}
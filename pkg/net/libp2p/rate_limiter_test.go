@@ -0,0 +1,234 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	var tests = map[string]struct {
+		config        RateLimitConfig
+		expectedError error
+	}{
+		"valid config": {
+			config: RateLimitConfig{
+				MessagesPerSecond:   10,
+				Burst:               100,
+				DisconnectThreshold: 20,
+			},
+			expectedError: nil,
+		},
+		"disconnecting disabled": {
+			config: RateLimitConfig{
+				MessagesPerSecond:   10,
+				Burst:               100,
+				DisconnectThreshold: 0,
+			},
+			expectedError: nil,
+		},
+		"zero messages per second": {
+			config: RateLimitConfig{
+				MessagesPerSecond: 0,
+				Burst:             100,
+			},
+			expectedError: fmt.Errorf("messages per second must be positive"),
+		},
+		"zero burst": {
+			config: RateLimitConfig{
+				MessagesPerSecond: 10,
+				Burst:             0,
+			},
+			expectedError: fmt.Errorf("burst must be positive"),
+		},
+		"negative disconnect threshold": {
+			config: RateLimitConfig{
+				MessagesPerSecond:   10,
+				Burst:               100,
+				DisconnectThreshold: -1,
+			},
+			expectedError: fmt.Errorf("disconnect threshold must not be negative"),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := test.config.validate()
+
+			if fmt.Sprintf("%v", test.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestSenderRateLimiter_Allow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ownID := generateTestIdentity(t).id
+	senderID := generateTestIdentity(t).id
+
+	disconnected := newMockDisconnect()
+
+	limiter := newSenderRateLimiter(
+		ctx,
+		RateLimitConfig{
+			// Low enough for the budget not to be replenished during the test.
+			MessagesPerSecond:   0.001,
+			Burst:               3,
+			DisconnectThreshold: 2,
+		},
+		ownID,
+		disconnected.disconnect,
+	)
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow("channel-1", senderID) {
+			t.Fatalf("message [%v] within the budget has not been allowed", i)
+		}
+	}
+
+	if limiter.allow("channel-1", senderID) {
+		t.Fatal("message over the budget has been allowed")
+	}
+
+	if count := disconnected.count(senderID); count != 0 {
+		t.Fatalf(
+			"unexpected disconnects count\nexpected: [0]\nactual:   [%v]",
+			count,
+		)
+	}
+
+	// Each channel has a separate budget.
+	if !limiter.allow("channel-2", senderID) {
+		t.Fatal("message within the budget of another channel has not been allowed")
+	}
+
+	if limiter.allow("channel-1", senderID) {
+		t.Fatal("message over the budget has been allowed")
+	}
+
+	if count := disconnected.count(senderID); count != 1 {
+		t.Fatalf(
+			"unexpected disconnects count\nexpected: [1]\nactual:   [%v]",
+			count,
+		)
+	}
+
+	// Own messages are never limited.
+	for i := 0; i < 10; i++ {
+		if !limiter.allow("channel-1", ownID) {
+			t.Fatalf("own message [%v] has not been allowed", i)
+		}
+	}
+
+	if count := disconnected.count(ownID); count != 0 {
+		t.Fatalf(
+			"unexpected own disconnects count\nexpected: [0]\nactual:   [%v]",
+			count,
+		)
+	}
+}
+
+func TestSenderRateLimiter_AllowNoDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	senderID := generateTestIdentity(t).id
+
+	disconnected := newMockDisconnect()
+
+	limiter := newSenderRateLimiter(
+		ctx,
+		RateLimitConfig{
+			MessagesPerSecond:   0.001,
+			Burst:               1,
+			DisconnectThreshold: 0,
+		},
+		generateTestIdentity(t).id,
+		disconnected.disconnect,
+	)
+
+	limiter.allow("channel", senderID)
+	for i := 0; i < 10; i++ {
+		if limiter.allow("channel", senderID) {
+			t.Fatalf("message [%v] over the budget has been allowed", i)
+		}
+	}
+
+	if count := disconnected.count(senderID); count != 0 {
+		t.Fatalf(
+			"unexpected disconnects count\nexpected: [0]\nactual:   [%v]",
+			count,
+		)
+	}
+}
+
+func TestSenderRateLimiter_Prune(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	senderID := generateTestIdentity(t).id
+
+	limiter := newSenderRateLimiter(
+		ctx,
+		RateLimitConfig{
+			MessagesPerSecond:   0.001,
+			Burst:               1,
+			DisconnectThreshold: 0,
+		},
+		generateTestIdentity(t).id,
+		newMockDisconnect().disconnect,
+	)
+
+	limiter.allow("channel", senderID)
+	if limiter.allow("channel", senderID) {
+		t.Fatal("message over the budget has been allowed")
+	}
+
+	limiter.prune(time.Now())
+
+	if limiter.allow("channel", senderID) {
+		t.Fatal("budget of a recently seen sender has been pruned")
+	}
+
+	limiter.prune(time.Now().Add(rateLimitSenderExpiry + time.Second))
+
+	if !limiter.allow("channel", senderID) {
+		t.Fatal("budget of an expired sender has not been pruned")
+	}
+}
+
+type mockDisconnect struct {
+	mutex        sync.Mutex
+	disconnected map[peer.ID]int
+}
+
+func newMockDisconnect() *mockDisconnect {
+	return &mockDisconnect{
+		disconnected: make(map[peer.ID]int),
+	}
+}
+
+func (md *mockDisconnect) disconnect(peerID peer.ID) {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	md.disconnected[peerID]++
+}
+
+func (md *mockDisconnect) count(peerID peer.ID) int {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	return md.disconnected[peerID]
+}
@@ -0,0 +1,107 @@
+package libp2p
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubtc "github.com/libp2p/go-libp2p-pubsub/timecache"
+)
+
+// DefaultSeenMessagesTTL is the default time-to-live used for pubsub seen
+// messages cache. Once a message is received and validated, pubsub
+// re-broadcasts it to other peers and puts it into the seen messages cache.
+// This way, subsequent arrivals of the same message are not re-broadcasted
+// unnecessarily. This mechanism is important for the network to avoid
+// excessive message flooding. The default value used by libp2p is 2 minutes.
+// However, Keep client messaging sessions are quite time-consuming so,
+// we use a longer TTL to reduce flooding risk even further. Worth noting
+// that this time cannot be too long as the cache may grow excessively and
+// impact memory consumption.
+const DefaultSeenMessagesTTL = 5 * time.Minute
+
+// Strategies of expiring entries of the seen messages cache.
+const (
+	// SeenMessagesStrategyFirstSeen expires the entry the TTL after the
+	// message was first seen. It keeps the cache smaller for protocols
+	// retransmitting messages often.
+	SeenMessagesStrategyFirstSeen = "first-seen"
+	// SeenMessagesStrategyLastSeen expires the entry the TTL after the
+	// message was last seen so messages arriving repeatedly are suppressed
+	// for longer. This is the default strategy.
+	SeenMessagesStrategyLastSeen = "last-seen"
+)
+
+// SeenMessagesConfig defines the configuration of the broadcast channel seen
+// messages caches used to suppress duplicate messages. The pubsub cache has
+// no size limit; its memory usage is governed by the TTL and the expiration
+// strategy. The cache used by broadcast channel message handlers to filter
+// out retransmissions is limited by CacheSize.
+type SeenMessagesConfig struct {
+	// TTL is the time-to-live of seen messages cache entries. Zero value
+	// means DefaultSeenMessagesTTL.
+	TTL time.Duration
+	// Strategy is one of SeenMessagesStrategyFirstSeen or
+	// SeenMessagesStrategyLastSeen. Empty value means
+	// SeenMessagesStrategyLastSeen.
+	Strategy string
+	// CacheSize is the maximum number of messages remembered by each
+	// broadcast channel message handler to filter out retransmissions.
+	// Once the limit is reached, the oldest messages are forgotten. Zero
+	// value means no limit.
+	CacheSize int
+}
+
+func (smc *SeenMessagesConfig) validate() error {
+	if smc.TTL < 0 {
+		return fmt.Errorf("TTL must not be negative")
+	}
+
+	if smc.CacheSize < 0 {
+		return fmt.Errorf("cache size must not be negative")
+	}
+
+	switch smc.Strategy {
+	case "", SeenMessagesStrategyFirstSeen, SeenMessagesStrategyLastSeen:
+	default:
+		return fmt.Errorf("unknown strategy [%v]", smc.Strategy)
+	}
+
+	return nil
+}
+
+// options returns pubsub options configuring the seen messages cache. The
+// config must be validated before.
+func (smc *SeenMessagesConfig) options() []pubsub.Option {
+	ttl := smc.TTL
+	if ttl == 0 {
+		ttl = DefaultSeenMessagesTTL
+	}
+
+	strategy := pubsubtc.Strategy_LastSeen
+	if smc.Strategy == SeenMessagesStrategyFirstSeen {
+		strategy = pubsubtc.Strategy_FirstSeen
+	}
+
+	return []pubsub.Option{
+		pubsub.WithSeenMessagesStrategy(strategy),
+		pubsub.WithSeenMessagesTTL(ttl),
+	}
+}
+
+// duplicateMessagesTracer is a pubsub tracer counting duplicate messages
+// dropped by pubsub thanks to the seen messages cache.
+type duplicateMessagesTracer struct {
+	noopRawTracer
+
+	count uint64
+}
+
+func (dmt *duplicateMessagesTracer) duplicateMessagesCount() uint64 {
+	return atomic.LoadUint64(&dmt.count)
+}
+
+func (dmt *duplicateMessagesTracer) DuplicateMessage(*pubsub.Message) {
+	atomic.AddUint64(&dmt.count, 1)
+}
@@ -0,0 +1,156 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestSeenMessagesConfig_Validate(t *testing.T) {
+	var tests = map[string]struct {
+		config        SeenMessagesConfig
+		expectedError error
+	}{
+		"empty config": {
+			config:        SeenMessagesConfig{},
+			expectedError: nil,
+		},
+		"first-seen strategy": {
+			config: SeenMessagesConfig{
+				TTL:       time.Minute,
+				Strategy:  SeenMessagesStrategyFirstSeen,
+				CacheSize: 1000,
+			},
+			expectedError: nil,
+		},
+		"last-seen strategy": {
+			config: SeenMessagesConfig{
+				TTL:      time.Minute,
+				Strategy: SeenMessagesStrategyLastSeen,
+			},
+			expectedError: nil,
+		},
+		"negative TTL": {
+			config:        SeenMessagesConfig{TTL: -time.Minute},
+			expectedError: fmt.Errorf("TTL must not be negative"),
+		},
+		"negative cache size": {
+			config:        SeenMessagesConfig{CacheSize: -1},
+			expectedError: fmt.Errorf("cache size must not be negative"),
+		},
+		"unknown strategy": {
+			config:        SeenMessagesConfig{Strategy: "never-seen"},
+			expectedError: fmt.Errorf("unknown strategy [never-seen]"),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := test.config.validate()
+
+			if fmt.Sprintf("%v", test.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestDuplicateMessagesCount(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Every provider is connected with the two others. A message published
+	// by one of them reaches each of the two others directly and is also
+	// forwarded by the other one, so it arrives twice.
+	providers := make([]*provider, 3)
+	for i := range providers {
+		operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		netProvider, err := Connect(
+			ctx,
+			Config{
+				Port: 8089 + i,
+				SeenMessages: SeenMessagesConfig{
+					Strategy: SeenMessagesStrategyFirstSeen,
+				},
+			},
+			operatorPrivateKey,
+			firewall.Disabled,
+			idleTicker(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		providers[i] = netProvider.(*provider)
+	}
+
+	for i := range providers {
+		for j := i + 1; j < len(providers); j++ {
+			err := providers[i].host.Connect(ctx, peer.AddrInfo{
+				ID:    providers[j].identity.id,
+				Addrs: providers[j].host.Addrs(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	channels := make([]net.BroadcastChannel, len(providers))
+	for i, provider := range providers {
+		channel, err := provider.BroadcastChannelFor("testchannel")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		channel.SetUnmarshaler(
+			func() net.TaggedUnmarshaler { return &testMessage{} },
+		)
+
+		channels[i] = channel
+	}
+
+	duplicateMessagesCount := func() uint64 {
+		return providers[1].DuplicateMessagesCount() +
+			providers[2].DuplicateMessagesCount()
+	}
+
+	for duplicateMessagesCount() == 0 {
+		// Subscriptions are propagated asynchronously so keep sending until
+		// both receivers are known to the sender.
+		if err := channels[0].Send(
+			ctx,
+			&testMessage{Payload: "some text"},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("no duplicate messages dropped")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if providers[0].DuplicateMessagesCount() != 0 {
+		t.Errorf(
+			"unexpected duplicates dropped by the sender\nexpected: [0]\nactual:   [%v]",
+			providers[0].DuplicateMessagesCount(),
+		)
+	}
+}
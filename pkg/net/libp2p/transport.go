@@ -37,6 +37,9 @@ type transport struct {
 	encryptionLayer sec.SecureTransport
 
 	firewall keepNet.Firewall
+
+	// handshakeFailed is called whenever securing a connection fails.
+	handshakeFailed func()
 }
 
 func newEncryptedAuthenticatedTransport(
@@ -45,6 +48,7 @@ func newEncryptedAuthenticatedTransport(
 	privateKey libp2pcrypto.PrivKey,
 	muxers []upgrader.StreamMuxer,
 	firewall keepNet.Firewall,
+	handshakeFailed func(),
 ) (*transport, error) {
 	id, err := peer.IDFromPrivateKey(privateKey)
 	if err != nil {
@@ -63,6 +67,7 @@ func newEncryptedAuthenticatedTransport(
 		privateKey:      privateKey,
 		encryptionLayer: encryptionLayer,
 		firewall:        firewall,
+		handshakeFailed: handshakeFailed,
 	}, nil
 }
 
@@ -74,10 +79,11 @@ func (t *transport) SecureInbound(
 ) (sec.SecureConn, error) {
 	encryptedConnection, err := t.encryptionLayer.SecureInbound(ctx, connection, remotePeerID)
 	if err != nil {
+		t.handshakeFailed()
 		return nil, err
 	}
 
-	return newAuthenticatedInboundConnection(
+	authenticatedConnection, err := newAuthenticatedInboundConnection(
 		encryptedConnection,
 		encryptedConnection.ConnState(),
 		t.localPeerID,
@@ -85,6 +91,12 @@ func (t *transport) SecureInbound(
 		t.firewall,
 		t.authProtocolID,
 	)
+	if err != nil {
+		t.handshakeFailed()
+		return nil, err
+	}
+
+	return authenticatedConnection, nil
 }
 
 // SecureOutbound secures an outbound connection.
@@ -99,10 +111,11 @@ func (t *transport) SecureOutbound(
 		remotePeerID,
 	)
 	if err != nil {
+		t.handshakeFailed()
 		return nil, err
 	}
 
-	return newAuthenticatedOutboundConnection(
+	authenticatedConnection, err := newAuthenticatedOutboundConnection(
 		encryptedConnection,
 		encryptedConnection.ConnState(),
 		t.localPeerID,
@@ -111,6 +124,12 @@ func (t *transport) SecureOutbound(
 		t.firewall,
 		t.authProtocolID,
 	)
+	if err != nil {
+		t.handshakeFailed()
+		return nil, err
+	}
+
+	return authenticatedConnection, nil
 }
 
 // ID is the protocol ID of the security protocol.
@@ -0,0 +1,285 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/keep-network/keep-core/pkg/operator"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
+	"github.com/keep-network/keep-core/pkg/net/internal"
+
+	// TODO: Stop using `dev` version of `google.golang.org/protobuf` once v.1.28.2
+	// is published.
+	protodelim "google.golang.org/protobuf/dev/encoding/protodelim"
+)
+
+// unicastWriteTimeout is the maximum time a single message write to
+// a unicast stream may take if the send context has no deadline. It prevents
+// a stalled remote peer from blocking all subsequent sends on the channel.
+const unicastWriteTimeout = 30 * time.Second
+
+// streamFactory opens a new unicast protocol stream with the given peer.
+type streamFactory func(
+	ctx context.Context,
+	remotePeerID peer.ID,
+) (libp2pnet.Stream, error)
+
+// unicastChannel is a point-to-point channel with a single remote peer.
+// Outgoing messages are written to a single stream opened with the remote
+// peer on the first send. The stream is reopened on the next send if writing
+// to it fails. Incoming messages are read from streams opened by the remote
+// peer and handed over to the channel by the unicast channel manager.
+//
+// Unicast messages use the same envelope as broadcast channel messages.
+type unicastChannel struct {
+	// channel-scoped atomic counter for sequence numbers
+	//
+	// Must be declared at the top of the struct!
+	// See: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	counter uint64
+
+	clientIdentity *identity
+	remotePeerID   peer.ID
+
+	streamMutex   sync.Mutex
+	streamFactory streamFactory
+	stream        libp2pnet.Stream
+
+	messageHandlersMutex sync.Mutex
+	messageHandlers      []*messageHandler
+
+	unmarshalersMutex  sync.Mutex
+	unmarshalersByType map[string]func() net.TaggedUnmarshaler
+}
+
+func (uc *unicastChannel) nextSeqno() uint64 {
+	return atomic.AddUint64(&uc.counter, 1)
+}
+
+func (uc *unicastChannel) RemotePeerID() net.TransportIdentifier {
+	return networkIdentity(uc.remotePeerID)
+}
+
+func (uc *unicastChannel) Send(
+	ctx context.Context,
+	message net.TaggedMarshaler,
+) error {
+	payloadBytes, err := message.Marshal()
+	if err != nil {
+		return err
+	}
+
+	senderIdentityBytes, err := uc.clientIdentity.Marshal()
+	if err != nil {
+		return err
+	}
+
+	messageProto := &pb.BroadcastNetworkMessage{
+		Payload:        payloadBytes,
+		Sender:         senderIdentityBytes,
+		Type:           []byte(message.Type()),
+		SequenceNumber: uc.nextSeqno(),
+		Version:        internal.MessageVersion(message),
+	}
+
+	uc.streamMutex.Lock()
+	defer uc.streamMutex.Unlock()
+
+	if uc.stream == nil {
+		stream, err := uc.streamFactory(ctx, uc.remotePeerID)
+		if err != nil {
+			return fmt.Errorf(
+				"could not open stream with peer [%v]: [%v]",
+				uc.remotePeerID,
+				err,
+			)
+		}
+
+		uc.stream = stream
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(unicastWriteTimeout)
+	}
+	if err := uc.stream.SetWriteDeadline(deadline); err != nil {
+		logger.Debugf("could not set unicast stream deadline: [%v]", err)
+	}
+
+	if _, err := protodelim.MarshalTo(uc.stream, messageProto); err != nil {
+		// The stream is unusable; the next send opens a new one.
+		_ = uc.stream.Reset()
+		uc.stream = nil
+
+		return fmt.Errorf(
+			"could not send message to peer [%v]: [%v]",
+			uc.remotePeerID,
+			err,
+		)
+	}
+
+	// The stream is reused by subsequent sends so the deadline must not
+	// outlive this one.
+	if err := uc.stream.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Debugf("could not clear unicast stream deadline: [%v]", err)
+	}
+
+	return nil
+}
+
+func (uc *unicastChannel) Recv(ctx context.Context, handler func(m net.Message)) {
+	messageHandler := &messageHandler{
+		ctx:     ctx,
+		channel: make(chan net.Message, messageHandlerThrottle),
+	}
+
+	uc.messageHandlersMutex.Lock()
+	uc.messageHandlers = append(uc.messageHandlers, messageHandler)
+	uc.messageHandlersMutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		logger.Debug("context is done; removing message handler")
+		uc.removeHandler(messageHandler)
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case msg := <-messageHandler.channel:
+				// Handler must not be called after the context is done.
+				// See channel.Recv for details.
+				if messageHandler.ctx.Err() != nil {
+					continue
+				}
+
+				handler(msg)
+			}
+		}
+	}()
+}
+
+func (uc *unicastChannel) removeHandler(handler *messageHandler) {
+	uc.messageHandlersMutex.Lock()
+	defer uc.messageHandlersMutex.Unlock()
+
+	for i, h := range uc.messageHandlers {
+		if h.channel == handler.channel {
+			uc.messageHandlers[i] = uc.messageHandlers[len(uc.messageHandlers)-1]
+			uc.messageHandlers = uc.messageHandlers[:len(uc.messageHandlers)-1]
+			break
+		}
+	}
+}
+
+func (uc *unicastChannel) SetUnmarshaler(unmarshaler func() net.TaggedUnmarshaler) {
+	tpe := unmarshaler().Type()
+
+	uc.unmarshalersMutex.Lock()
+	defer uc.unmarshalersMutex.Unlock()
+
+	uc.unmarshalersByType[tpe] = unmarshaler
+}
+
+// processContainerMessage unmarshals the given message received from the
+// remote peer and delivers it to the message handlers. The message is
+// rejected if its sender is not the remote peer of the channel.
+func (uc *unicastChannel) processContainerMessage(
+	message *pb.BroadcastNetworkMessage,
+) error {
+	senderIdentifier := &identity{}
+	if err := senderIdentifier.Unmarshal(message.Sender); err != nil {
+		return err
+	}
+
+	// The remote peer is authenticated by the connection the message was
+	// received through; make sure the message was not authored by another
+	// peer.
+	if senderIdentifier.id != uc.remotePeerID {
+		return fmt.Errorf(
+			"remote peer [%v] does not match message sender [%v]",
+			uc.remotePeerID,
+			senderIdentifier.id,
+		)
+	}
+
+	unmarshaled, err := uc.getUnmarshalingContainerByType(string(message.Type))
+	if err != nil {
+		return err
+	}
+
+	err = internal.UnmarshalVersion(
+		unmarshaled,
+		message.GetVersion(),
+		message.GetPayload(),
+	)
+	if err != nil {
+		return err
+	}
+
+	operatorPublicKey, err := networkPublicKeyToOperatorPublicKey(
+		senderIdentifier.pubKey,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"sender [%v] with key [%v] is not of correct type",
+			senderIdentifier.id,
+			senderIdentifier.pubKey,
+		)
+	}
+
+	uc.deliver(
+		internal.BasicMessage(
+			senderIdentifier.id,
+			unmarshaled,
+			string(message.Type),
+			operator.MarshalUncompressed(operatorPublicKey),
+			message.SequenceNumber,
+		),
+	)
+
+	return nil
+}
+
+func (uc *unicastChannel) getUnmarshalingContainerByType(
+	messageType string,
+) (net.TaggedUnmarshaler, error) {
+	uc.unmarshalersMutex.Lock()
+	defer uc.unmarshalersMutex.Unlock()
+
+	unmarshaler, found := uc.unmarshalersByType[messageType]
+	if !found {
+		return nil, fmt.Errorf(
+			"couldn't find unmarshaler for type [%s]",
+			messageType,
+		)
+	}
+
+	return unmarshaler(), nil
+}
+
+func (uc *unicastChannel) deliver(message net.Message) {
+	uc.messageHandlersMutex.Lock()
+	snapshot := make([]*messageHandler, len(uc.messageHandlers))
+	copy(snapshot, uc.messageHandlers)
+	uc.messageHandlersMutex.Unlock()
+
+	for _, handler := range snapshot {
+		select {
+		case handler.channel <- message:
+		default:
+			logger.Warnf("message handler is too slow; dropping message")
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package libp2p
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
+
+	// TODO: Stop using `dev` version of `google.golang.org/protobuf` once v.1.28.2
+	// is published.
+	protodelim "google.golang.org/protobuf/dev/encoding/protodelim"
+)
+
+// unicastProtocolID is the identifier of the libp2p protocol used to exchange
+// unicast channel messages. Each message is sent as a length-delimited
+// protobuf over a stream opened with the remote peer.
+const unicastProtocolID = protocol.ID("/keep/unicast/1.0.0")
+
+// maxUnicastMessageSize is the maximum size of a single unicast message. It is
+// the same as the maximum size of a pubsub message used by broadcast channels.
+const maxUnicastMessageSize = 1 << 20
+
+type unicastChannelManager struct {
+	identity *identity
+	host     host.Host
+
+	channelsMutex sync.Mutex
+	channels      map[peer.ID]*unicastChannel
+}
+
+func newUnicastChannelManager(
+	ctx context.Context,
+	identity *identity,
+	p2phost host.Host,
+) *unicastChannelManager {
+	manager := &unicastChannelManager{
+		identity: identity,
+		host:     p2phost,
+		channels: make(map[peer.ID]*unicastChannel),
+	}
+
+	p2phost.SetStreamHandler(unicastProtocolID, manager.handleStream)
+
+	go func() {
+		<-ctx.Done()
+		p2phost.RemoveStreamHandler(unicastProtocolID)
+	}()
+
+	return manager
+}
+
+func (ucm *unicastChannelManager) getChannel(
+	remotePeerID peer.ID,
+) (*unicastChannel, error) {
+	if remotePeerID == ucm.identity.id {
+		return nil, fmt.Errorf("cannot open unicast channel with self")
+	}
+
+	ucm.channelsMutex.Lock()
+	defer ucm.channelsMutex.Unlock()
+
+	channel, exists := ucm.channels[remotePeerID]
+	if !exists {
+		channel = &unicastChannel{
+			clientIdentity:     ucm.identity,
+			remotePeerID:       remotePeerID,
+			streamFactory:      ucm.newStream,
+			messageHandlers:    make([]*messageHandler, 0),
+			unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+		}
+		ucm.channels[remotePeerID] = channel
+	}
+
+	return channel, nil
+}
+
+func (ucm *unicastChannelManager) newStream(
+	ctx context.Context,
+	remotePeerID peer.ID,
+) (libp2pnet.Stream, error) {
+	return ucm.host.NewStream(ctx, remotePeerID, unicastProtocolID)
+}
+
+// handleStream reads messages from the incoming stream and passes them to
+// the unicast channel with the stream's remote peer. The remote peer is
+// authenticated by the underlying connection so the channel can verify the
+// messages are authored by it.
+func (ucm *unicastChannelManager) handleStream(stream libp2pnet.Stream) {
+	defer func() {
+		if err := stream.Close(); err != nil {
+			logger.Debugf("could not close unicast stream: [%v]", err)
+		}
+	}()
+
+	remotePeerID := stream.Conn().RemotePeer()
+
+	channel, err := ucm.getChannel(remotePeerID)
+	if err != nil {
+		logger.Warnf(
+			"could not get unicast channel with peer [%v]: [%v]",
+			remotePeerID,
+			err,
+		)
+		_ = stream.Reset()
+		return
+	}
+
+	reader := bufio.NewReader(stream)
+	unmarshalOptions := protodelim.UnmarshalOptions{
+		MaxSize: maxUnicastMessageSize,
+	}
+
+	for {
+		var message pb.BroadcastNetworkMessage
+		if err := unmarshalOptions.UnmarshalFrom(reader, &message); err != nil {
+			if !errors.Is(err, io.EOF) {
+				logger.Warnf(
+					"could not read unicast message from peer [%v]: [%v]",
+					remotePeerID,
+					err,
+				)
+				_ = stream.Reset()
+			}
+			return
+		}
+
+		if err := channel.processContainerMessage(&message); err != nil {
+			logger.Warnf(
+				"could not process unicast message from peer [%v]: [%v]",
+				remotePeerID,
+				err,
+			)
+		}
+	}
+}
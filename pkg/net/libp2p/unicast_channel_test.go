@@ -0,0 +1,223 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestUnicastChannel_SendReceive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expectedPayload := "some text"
+
+	operatorPrivateKey1, operatorPublicKey1, err := operator.GenerateKeyPair(
+		DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operatorPrivateKey2, operatorPublicKey2, err := operator.GenerateKeyPair(
+		DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider1, err := Connect(
+		ctx,
+		Config{Port: 8081},
+		operatorPrivateKey1,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider2, err := Connect(
+		ctx,
+		Config{
+			Port: 8082,
+			Peers: []string{
+				fmt.Sprintf("/ip4/127.0.0.1/tcp/8081/ipfs/%v", provider1.ID()),
+			},
+		},
+		operatorPrivateKey2,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peerID1, err := provider2.CreateTransportIdentifier(operatorPublicKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerID2, err := provider1.CreateTransportIdentifier(operatorPublicKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel1, err := provider1.UnicastChannelWith(peerID2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel2, err := provider2.UnicastChannelWith(peerID1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if channel1.RemotePeerID().String() != provider2.ID().String() {
+		t.Errorf(
+			"unexpected remote peer ID\nexpected: [%v]\nactual:   [%v]",
+			provider2.ID(),
+			channel1.RemotePeerID(),
+		)
+	}
+
+	channel2.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &testMessage{}
+	})
+
+	recvChan := make(chan net.Message, 1)
+	channel2.Recv(ctx, func(msg net.Message) {
+		recvChan <- msg
+	})
+
+	if err := channel1.Send(
+		ctx,
+		&testMessage{Payload: expectedPayload},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-recvChan:
+		testPayload, ok := msg.Payload().(*testMessage)
+		if !ok {
+			t.Fatalf("unexpected payload type [%T]", msg.Payload())
+		}
+
+		if expectedPayload != testPayload.Payload {
+			t.Errorf(
+				"unexpected payload\nexpected: [%v]\nactual:   [%v]",
+				expectedPayload,
+				testPayload.Payload,
+			)
+		}
+
+		if msg.TransportSenderID().String() != provider1.ID().String() {
+			t.Errorf(
+				"unexpected sender\nexpected: [%v]\nactual:   [%v]",
+				provider1.ID(),
+				msg.TransportSenderID(),
+			)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected message not received")
+	}
+}
+
+func TestUnicastChannel_Self(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	operatorPrivateKey, operatorPublicKey, err := operator.GenerateKeyPair(
+		DefaultCurve,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := Connect(
+		ctx,
+		Config{Port: 8083},
+		operatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peerID, err := provider.CreateTransportIdentifier(operatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.UnicastChannelWith(peerID); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnicastChannel_ProcessContainerMessage_SenderMismatch(t *testing.T) {
+	remoteIdentity := generateTestIdentity(t)
+	otherIdentity := generateTestIdentity(t)
+
+	channel := &unicastChannel{
+		remotePeerID:       remoteIdentity.id,
+		unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+	}
+	channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &testMessage{}
+	})
+
+	senderBytes, err := otherIdentity.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloadBytes, err := (&testMessage{Payload: "some text"}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = channel.processContainerMessage(&pb.BroadcastNetworkMessage{
+		Sender:  senderBytes,
+		Payload: payloadBytes,
+		Type:    []byte((&testMessage{}).Type()),
+	})
+
+	expectedErr := fmt.Errorf(
+		"remote peer [%v] does not match message sender [%v]",
+		remoteIdentity.id,
+		otherIdentity.id,
+	)
+	if err == nil || err.Error() != expectedErr.Error() {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func generateTestIdentity(t *testing.T) *identity {
+	operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	networkPrivateKey, _, err := operatorPrivateKeyToNetworkKeyPair(
+		operatorPrivateKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, err := createIdentity(networkPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return identity
+}
@@ -2,6 +2,7 @@ package local
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -18,6 +19,28 @@ const messageHandlerThrottle = 256
 type messageHandler struct {
 	ctx     context.Context
 	channel chan net.Message
+	handler func(m net.Message)
+	// synchronous is true if the handler is called synchronously, in the
+	// deterministic delivery mode.
+	synchronous bool
+}
+
+// handle passes the message to the handler goroutine. In the deterministic
+// delivery mode, the handler is called synchronously instead so that the
+// message is handled once the dispatcher delivers it.
+func (mh *messageHandler) handle(message net.Message) {
+	if mh.synchronous {
+		if mh.ctx.Err() == nil {
+			mh.handler(message)
+		}
+		return
+	}
+
+	select {
+	case mh.channel <- message:
+	default:
+		logger.Warnf("handler too slow, dropping message")
+	}
 }
 
 type localChannel struct {
@@ -29,7 +52,15 @@ type localChannel struct {
 	messageHandlers      []*messageHandler
 	unmarshalersMutex    sync.Mutex
 	unmarshalersByType   map[string]func() net.TaggedUnmarshaler
+	validatorsMutex      sync.Mutex
+	validators           []net.BroadcastChannelValidator
 	retransmissionTicker *retransmission.Ticker
+	// dispatcher delivers messages sent to the channel in the deterministic
+	// delivery mode. Nil if messages are delivered as soon as they are sent.
+	dispatcher *Dispatcher
+	// recorder records messages sent to the channel. Nil if messages are
+	// not recorded.
+	recorder *Recorder
 }
 
 func (lc *localChannel) nextSeqno() uint64 {
@@ -56,21 +87,35 @@ func (lc *localChannel) Send(
 	}
 
 	unmarshaled := unmarshaler()
-	err = unmarshaled.Unmarshal(bytes)
+	err = internal.UnmarshalVersion(
+		unmarshaled,
+		internal.MessageVersion(message),
+		bytes,
+	)
 	if err != nil {
 		return err
 	}
 
 	operatorPublicKeyBytes := operator.MarshalUncompressed(lc.operatorPublicKey)
+	seqno := lc.nextSeqno()
 
 	netMessage := internal.BasicMessage(
 		lc.identifier,
 		unmarshaled,
 		message.Type(),
 		operatorPublicKeyBytes,
-		lc.nextSeqno(),
+		seqno,
 	)
 
+	lc.recorder.record(&RecordedMessage{
+		Channel: lc.name,
+		Sender:  hex.EncodeToString(operatorPublicKeyBytes),
+		Type:    message.Type(),
+		Version: internal.MessageVersion(message),
+		Seqno:   seqno,
+		Payload: bytes,
+	})
+
 	var strategy net.RetransmissionStrategy
 	switch len(retransmissionStrategy) {
 	case 1:
@@ -79,46 +124,116 @@ func (lc *localChannel) Send(
 		strategy = net.StandardRetransmissionStrategy
 	}
 
-	retransmission.ScheduleRetransmissions(
-		ctx,
-		logger,
-		lc.retransmissionTicker,
-		func() error {
-			return broadcastMessage(lc.name, netMessage)
-		},
-		retransmission.WithStrategy(strategy),
+	retransmit := func() error {
+		return broadcastMessage(
+			lc.name,
+			lc.operatorPublicKey,
+			netMessage,
+			lc.dispatcher,
+		)
+	}
+
+	if lc.dispatcher != nil {
+		lc.dispatcher.scheduleRetransmissions(
+			ctx,
+			retransmit,
+			retransmission.WithStrategy(strategy),
+		)
+	} else {
+		retransmission.ScheduleRetransmissions(
+			ctx,
+			logger,
+			lc.retransmissionTicker,
+			retransmit,
+			retransmission.WithStrategy(strategy),
+		)
+	}
+
+	return broadcastMessage(
+		lc.name,
+		lc.operatorPublicKey,
+		netMessage,
+		lc.dispatcher,
 	)
+}
+
+// receive unmarshals the given payload using unmarshalers registered in the
+// channel and delivers the message to the message handlers. Messages of
+// types with no unmarshaler registered or of unsupported format versions
+// are dropped.
+func (lc *localChannel) receive(
+	transportSenderID net.TransportIdentifier,
+	messageType string,
+	version uint32,
+	payload []byte,
+	senderPublicKey []byte,
+	seqno uint64,
+) {
+	lc.unmarshalersMutex.Lock()
+	unmarshaler, found := lc.unmarshalersByType[messageType]
+	lc.unmarshalersMutex.Unlock()
+
+	if !found {
+		logger.Warnf(
+			"couldn't find unmarshaler for type [%s]; dropping message",
+			messageType,
+		)
+		return
+	}
+
+	unmarshaled := unmarshaler()
+	err := internal.UnmarshalVersion(unmarshaled, version, payload)
+	if err != nil {
+		logger.Warnf("couldn't unmarshal message; dropping it: [%v]", err)
+		return
+	}
 
-	return broadcastMessage(lc.name, netMessage)
+	lc.deliver(
+		internal.BasicMessage(
+			transportSenderID,
+			unmarshaled,
+			messageType,
+			senderPublicKey,
+			seqno,
+		),
+	)
 }
 
 func (lc *localChannel) deliver(message net.Message) {
+	if err := lc.validate(message); err != nil {
+		logger.Warnf(
+			"dropping invalid message of type [%v] from [%v]: [%v]",
+			message.Type(),
+			message.TransportSenderID(),
+			err,
+		)
+		return
+	}
+
 	lc.messageHandlersMutex.Lock()
 	snapshot := make([]*messageHandler, len(lc.messageHandlers))
 	copy(snapshot, lc.messageHandlers)
 	lc.messageHandlersMutex.Unlock()
 
 	for _, handler := range snapshot {
-		select {
-		case handler.channel <- message:
-		default:
-			logger.Warnf("handler too slow, dropping message")
-		}
+		handler.handle(message)
 	}
 }
 
 func (lc *localChannel) Recv(ctx context.Context, handler func(m net.Message)) {
+	handleWithRetransmissions := retransmission.WithRetransmissionSupport(handler)
+
 	messageHandler := &messageHandler{
-		ctx:     ctx,
-		channel: make(chan net.Message, messageHandlerThrottle),
+		ctx:         ctx,
+		channel:     make(chan net.Message, messageHandlerThrottle),
+		handler:     handleWithRetransmissions,
+		synchronous: lc.dispatcher != nil,
 	}
 
 	lc.messageHandlersMutex.Lock()
 	lc.messageHandlers = append(lc.messageHandlers, messageHandler)
 	lc.messageHandlersMutex.Unlock()
 
-	handleWithRetransmissions := retransmission.WithRetransmissionSupport(handler)
-
 	go func() {
 		for {
 			select {
@@ -172,3 +287,27 @@ func (lc *localChannel) SetUnmarshaler(unmarshaler func() net.TaggedUnmarshaler)
 func (lc *localChannel) SetFilter(filter net.BroadcastChannelFilter) error {
 	return nil // no-op
 }
+
+func (lc *localChannel) AddValidator(validator net.BroadcastChannelValidator) error {
+	lc.validatorsMutex.Lock()
+	defer lc.validatorsMutex.Unlock()
+
+	lc.validators = append(lc.validators, validator)
+
+	return nil
+}
+
+func (lc *localChannel) validate(message net.Message) error {
+	lc.validatorsMutex.Lock()
+	validators := make([]net.BroadcastChannelValidator, len(lc.validators))
+	copy(validators, lc.validators)
+	lc.validatorsMutex.Unlock()
+
+	for _, validator := range validators {
+		if err := validator(message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -25,6 +25,8 @@ var broadcastChannels map[string][]*localChannel
 func getBroadcastChannel(
 	name string,
 	operatorPublicKey *operator.PublicKey,
+	dispatcher *Dispatcher,
+	recorder *Recorder,
 ) net.BroadcastChannel {
 	broadcastChannelsMutex.Lock()
 	defer broadcastChannelsMutex.Unlock()
@@ -49,19 +51,43 @@ func getBroadcastChannel(
 		retransmissionTicker: retransmission.NewTimeTicker(
 			context.Background(), RetransmissionTick,
 		),
+		dispatcher: dispatcher,
+		recorder:   recorder,
 	}
 	broadcastChannels[name] = append(broadcastChannels[name], channel)
 
 	return channel
 }
 
-func broadcastMessage(name string, message net.Message) error {
+// broadcastMessage delivers the message to all channels with the given name
+// according to conditions of links from the sender to channel owners. The
+// message is queued in the given dispatcher of the sender, if any.
+func broadcastMessage(
+	name string,
+	sender *operator.PublicKey,
+	message net.Message,
+	dispatcher *Dispatcher,
+) error {
 	broadcastChannelsMutex.Lock()
 	targetChannels := broadcastChannels[name]
 	broadcastChannelsMutex.Unlock()
 
+	senderID, err := createLocalIdentifier(sender)
+	if err != nil {
+		return err
+	}
+
 	for _, targetChannel := range targetChannels {
-		targetChannel.deliver(message)
+		targetChannel := targetChannel
+
+		receiverID, err := createLocalIdentifier(targetChannel.operatorPublicKey)
+		if err != nil {
+			return err
+		}
+
+		transmit(senderID, receiverID, dispatcher, func() {
+			targetChannel.deliver(message)
+		})
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package local
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sort"
 	"sync"
@@ -233,13 +234,65 @@ loop:
 	}
 }
 
-func initTestChannel(channelName string) (*operator.PublicKey, net.BroadcastChannel, error) {
+func TestAddValidator(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	channelName := "channel name"
+
+	_, localChannel1, err := initTestChannel(channelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, localChannel2, err := initTestChannel(channelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = localChannel2.AddValidator(func(msg net.Message) error {
+		return fmt.Errorf("invalid message")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inMsgChan1 := make(chan net.Message, 1)
+	localChannel1.Recv(ctx, func(msg net.Message) {
+		inMsgChan1 <- msg
+	})
+
+	inMsgChan2 := make(chan net.Message, 1)
+	localChannel2.Recv(ctx, func(msg net.Message) {
+		inMsgChan2 <- msg
+	})
+
+	if err := localChannel1.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatalf("failed to send message: [%v]", err)
+	}
+
+	select {
+	case <-inMsgChan1:
+	case <-ctx.Done():
+		t.Fatal("valid message not delivered")
+	}
+
+	select {
+	case <-inMsgChan2:
+		t.Fatal("invalid message delivered")
+	case <-ctx.Done():
+	}
+}
+
+func initTestChannel(
+	channelName string,
+	options ...ConnectOption,
+) (*operator.PublicKey, net.BroadcastChannel, error) {
 	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	provider := ConnectWithKey(operatorPublicKey)
+	provider := ConnectWithKey(operatorPublicKey, options...)
 	localChannel, err := provider.BroadcastChannelFor(channelName)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,176 @@
+package local
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/net/retransmission"
+)
+
+// Dispatcher delivers messages sent by local providers connected with it in
+// a deterministic order. Messages are not delivered when sent but queued
+// until the test dispatches them from its own goroutine. Message handlers
+// are called synchronously during the dispatch so that the state of
+// receivers can be inspected right after it, without sleeping or waiting for
+// timeouts.
+//
+// Link latencies advance the virtual time of the dispatcher instead of the
+// wall clock. Queued messages are delivered in the order of their virtual
+// delivery times and messages with the same delivery time are delivered in
+// the order they were sent. Retransmissions of broadcast messages happen
+// only on dispatcher ticks.
+type Dispatcher struct {
+	mutex    sync.Mutex
+	now      time.Duration
+	sequence uint64
+	queue    []*delivery
+
+	retransmissionsMutex sync.Mutex
+	retransmissions      []*scheduledRetransmission
+}
+
+// delivery is a message queued for dispatch.
+type delivery struct {
+	at       time.Duration
+	sequence uint64
+	deliver  func()
+}
+
+// scheduledRetransmission is a retransmission routine called on dispatcher
+// ticks for the lifetime of the context.
+type scheduledRetransmission struct {
+	ctx        context.Context
+	retransmit retransmission.RetransmitFn
+	strategy   retransmission.Strategy
+}
+
+// NewDispatcher creates a new dispatcher. Local providers are switched to
+// the deterministic delivery mode by connecting them with the dispatcher
+// using the WithDispatcher option. Providers connected without a dispatcher
+// deliver messages as soon as they are sent so tests using the dispatcher
+// do not affect other tests running in the same process.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		queue:           make([]*delivery, 0),
+		retransmissions: make([]*scheduledRetransmission, 0),
+	}
+}
+
+// Now returns the virtual time elapsed since the dispatcher was created.
+func (d *Dispatcher) Now() time.Duration {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.now
+}
+
+// Pending returns the number of messages queued for delivery.
+func (d *Dispatcher) Pending() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return len(d.queue)
+}
+
+// DeliverNext delivers the queued message with the earliest delivery time
+// and advances the virtual time to it. Message handlers are called before
+// the function returns. Returns false if there was no message to deliver.
+func (d *Dispatcher) DeliverNext() bool {
+	d.mutex.Lock()
+	if len(d.queue) == 0 {
+		d.mutex.Unlock()
+		return false
+	}
+
+	next := d.queue[0]
+	d.queue = d.queue[1:]
+	if next.at > d.now {
+		d.now = next.at
+	}
+	d.mutex.Unlock()
+
+	next.deliver()
+
+	return true
+}
+
+// DeliverAll delivers queued messages until the queue is empty, including
+// messages sent by message handlers during the dispatch. Returns the number
+// of delivered messages. The function never returns if handlers keep
+// responding to each other's messages.
+func (d *Dispatcher) DeliverAll() int {
+	delivered := 0
+	for d.DeliverNext() {
+		delivered++
+	}
+
+	return delivered
+}
+
+// Tick triggers one retransmission tick. Retransmission routines of broadcast
+// messages whose send contexts are not done yet are called in the order the
+// messages were sent, according to their retransmission strategies. The
+// retransmitted messages are queued for delivery.
+func (d *Dispatcher) Tick() {
+	d.retransmissionsMutex.Lock()
+	active := make([]*scheduledRetransmission, 0, len(d.retransmissions))
+	for _, scheduled := range d.retransmissions {
+		if scheduled.ctx.Err() == nil {
+			active = append(active, scheduled)
+		}
+	}
+	d.retransmissions = active
+	d.retransmissionsMutex.Unlock()
+
+	for _, scheduled := range active {
+		if err := scheduled.strategy.Tick(scheduled.retransmit); err != nil {
+			logger.Errorf("could not retransmit message: [%v]", err)
+		}
+	}
+}
+
+// schedule queues the deliver function to be called once the given latency
+// elapses in the virtual time of the dispatcher.
+func (d *Dispatcher) schedule(latency time.Duration, deliver func()) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.sequence++
+	entry := &delivery{
+		at:       d.now + latency,
+		sequence: d.sequence,
+		deliver:  deliver,
+	}
+
+	index := sort.Search(len(d.queue), func(i int) bool {
+		queued := d.queue[i]
+		return queued.at > entry.at ||
+			(queued.at == entry.at && queued.sequence > entry.sequence)
+	})
+
+	d.queue = append(d.queue, nil)
+	copy(d.queue[index+1:], d.queue[index:])
+	d.queue[index] = entry
+}
+
+// scheduleRetransmissions registers the retransmission routine to be called
+// on dispatcher ticks for the lifetime of the context.
+func (d *Dispatcher) scheduleRetransmissions(
+	ctx context.Context,
+	retransmit retransmission.RetransmitFn,
+	strategy retransmission.Strategy,
+) {
+	d.retransmissionsMutex.Lock()
+	defer d.retransmissionsMutex.Unlock()
+
+	d.retransmissions = append(
+		d.retransmissions,
+		&scheduledRetransmission{
+			ctx:        ctx,
+			retransmit: retransmit,
+			strategy:   strategy,
+		},
+	)
+}
@@ -0,0 +1,286 @@
+package local
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestDispatcher_DeliveryOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := NewDispatcher()
+
+	sender, _ := connectTestProvider(t, WithDispatcher(dispatcher))
+	defer sender.ResetLinkConditions()
+
+	latencies := map[string]time.Duration{
+		"slow":    30 * time.Millisecond,
+		"fast":    10 * time.Millisecond,
+		"instant": 0,
+	}
+
+	// Channels are never unregistered so the name must not be reused by
+	// repeated test runs.
+	channelName := "deterministic-delivery-order-" +
+		randomLocalIdentifier().String()
+	delivered := make([]string, 0)
+
+	for _, name := range []string{"slow", "fast", "instant"} {
+		name := name
+
+		receiver, receiverKey := connectTestProvider(t, WithDispatcher(dispatcher))
+
+		err := sender.SetLinkConditions(
+			receiverKey,
+			LinkConditions{Latency: ConstantLatency(latencies[name])},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		channel, err := receiver.BroadcastChannelFor(channelName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+			return &mockNetMessage{}
+		})
+		channel.Recv(ctx, func(message net.Message) {
+			delivered = append(delivered, name)
+		})
+	}
+
+	senderChannel, err := sender.BroadcastChannelFor(channelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	senderChannel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+
+	sendCtx, sendCancel := context.WithCancel(ctx)
+	defer sendCancel()
+
+	if err := senderChannel.Send(sendCtx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Three receivers and the sender's own channel.
+	if pending := dispatcher.Pending(); pending != 4 {
+		t.Fatalf("unexpected number of pending messages: [%v]", pending)
+	}
+	if len(delivered) != 0 {
+		t.Fatalf("messages delivered before dispatch: [%v]", delivered)
+	}
+
+	if !dispatcher.DeliverNext() {
+		t.Fatal("no message delivered")
+	}
+	if !reflect.DeepEqual([]string{"instant"}, delivered) {
+		t.Fatalf("unexpected first delivery: [%v]", delivered)
+	}
+
+	if delivered := dispatcher.DeliverAll(); delivered != 3 {
+		t.Errorf("unexpected number of delivered messages: [%v]", delivered)
+	}
+
+	expectedDelivered := []string{"instant", "fast", "slow"}
+	if !reflect.DeepEqual(expectedDelivered, delivered) {
+		t.Errorf(
+			"unexpected delivery order\nexpected: [%v]\nactual:   [%v]",
+			expectedDelivered,
+			delivered,
+		)
+	}
+
+	if now := dispatcher.Now(); now != latencies["slow"] {
+		t.Errorf(
+			"unexpected virtual time\nexpected: [%v]\nactual:   [%v]",
+			latencies["slow"],
+			now,
+		)
+	}
+
+	if dispatcher.DeliverNext() {
+		t.Error("message delivered from an empty queue")
+	}
+}
+
+func TestDispatcher_HandlerResponses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := NewDispatcher()
+
+	requester, requesterKey := connectTestProvider(t, WithDispatcher(dispatcher))
+	responder, responderKey := connectTestProvider(t, WithDispatcher(dispatcher))
+
+	requesterChannel := unicastTestChannel(t, requester, responderKey)
+	responderChannel := unicastTestChannel(t, responder, requesterKey)
+
+	responderChannel.Recv(ctx, func(message net.Message) {
+		if err := responderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	responses := 0
+	requesterChannel.Recv(ctx, func(message net.Message) {
+		responses++
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := requesterChannel.Send(ctx, &mockNetMessage{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if delivered := dispatcher.DeliverAll(); delivered != 6 {
+		t.Errorf("unexpected number of delivered messages: [%v]", delivered)
+	}
+
+	if responses != 3 {
+		t.Errorf("unexpected number of responses: [%v]", responses)
+	}
+}
+
+func TestDispatcher_Retransmissions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := NewDispatcher()
+
+	_, channel, err := initTestChannel(
+		"deterministic-retransmissions-"+randomLocalIdentifier().String(),
+		WithDispatcher(dispatcher),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := 0
+	channel.Recv(ctx, func(message net.Message) {
+		handled++
+	})
+
+	sendCtx, sendCancel := context.WithCancel(ctx)
+
+	if err := channel.Send(sendCtx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.DeliverAll()
+
+	dispatcher.Tick()
+	if pending := dispatcher.Pending(); pending != 1 {
+		t.Fatalf("unexpected number of pending messages: [%v]", pending)
+	}
+
+	dispatcher.DeliverAll()
+
+	// Retransmissions are filtered out by the handler.
+	if handled != 1 {
+		t.Errorf("unexpected number of handled messages: [%v]", handled)
+	}
+
+	sendCancel()
+
+	dispatcher.Tick()
+	if pending := dispatcher.Pending(); pending != 0 {
+		t.Errorf(
+			"unexpected number of pending messages after the send "+
+				"context is done: [%v]",
+			pending,
+		)
+	}
+}
+
+func TestDispatcher_Partition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := NewDispatcher()
+	defer HealPartition()
+
+	sender, senderKey := connectTestProvider(t, WithDispatcher(dispatcher))
+	receiver, receiverKey := connectTestProvider(t, WithDispatcher(dispatcher))
+
+	senderChannel := unicastTestChannel(t, sender, receiverKey)
+	receiverChannel := unicastTestChannel(t, receiver, senderKey)
+
+	received := 0
+	receiverChannel.Recv(ctx, func(message net.Message) {
+		received++
+	})
+
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The partition applies to messages queued before it.
+	err := Partition(
+		[]*operator.PublicKey{senderKey},
+		[]*operator.PublicKey{receiverKey},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.DeliverAll()
+
+	if received != 0 {
+		t.Fatal("message delivered across the partition")
+	}
+
+	HealPartition()
+
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.DeliverAll()
+
+	if received != 1 {
+		t.Errorf("unexpected number of received messages: [%v]", received)
+	}
+}
+
+func TestDispatcher_ProvidersWithoutDispatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := NewDispatcher()
+
+	// Providers connected without the dispatcher, e.g. by other tests
+	// running in the same process, are not affected by it.
+	sender, senderKey := connectTestProvider(t)
+	receiver, receiverKey := connectTestProvider(t)
+
+	senderChannel := unicastTestChannel(t, sender, receiverKey)
+	receiverChannel := unicastTestChannel(t, receiver, senderKey)
+
+	received := make(chan net.Message, 1)
+	receiverChannel.Recv(ctx, func(message net.Message) {
+		received <- message
+	})
+
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+
+	if pending := dispatcher.Pending(); pending != 0 {
+		t.Errorf("unexpected number of pending messages: [%v]", pending)
+	}
+}
@@ -0,0 +1,258 @@
+package local
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// LatencyDistribution draws latencies of messages sent over a link using the
+// given source of randomness of the link.
+type LatencyDistribution func(random *rand.Rand) time.Duration
+
+// ConstantLatency returns a distribution delaying every message by the given
+// latency.
+func ConstantLatency(latency time.Duration) LatencyDistribution {
+	return func(*rand.Rand) time.Duration {
+		return latency
+	}
+}
+
+// UniformLatency returns a distribution delaying messages by latencies drawn
+// uniformly from the [min, max) range.
+func UniformLatency(min, max time.Duration) LatencyDistribution {
+	return func(random *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+
+		return min + time.Duration(random.Int63n(int64(max-min)))
+	}
+}
+
+// NormalLatency returns a distribution delaying messages by latencies drawn
+// from the normal distribution with the given mean and standard deviation.
+// Negative latencies are truncated to zero.
+func NormalLatency(mean, stdDev time.Duration) LatencyDistribution {
+	return func(random *rand.Rand) time.Duration {
+		latency := mean + time.Duration(random.NormFloat64()*float64(stdDev))
+		if latency < 0 {
+			return 0
+		}
+
+		return latency
+	}
+}
+
+// LinkConditions describes conditions of a link delivering messages from one
+// local provider to another.
+type LinkConditions struct {
+	// Latency is the distribution of latencies messages are delivered with.
+	// Messages are delivered immediately if nil.
+	Latency LatencyDistribution
+	// DropRate is the probability in the [0, 1] range of dropping a message.
+	DropRate float64
+	// Seed seeds the source of randomness of the link so that latencies and
+	// drops are the same for the same sequence of messages. Links created
+	// from default conditions combine the seed with the receiver identifier.
+	Seed int64
+}
+
+func (lc LinkConditions) validate() error {
+	if lc.DropRate < 0 || lc.DropRate > 1 {
+		return fmt.Errorf(
+			"drop rate [%v] is not in the [0, 1] range",
+			lc.DropRate,
+		)
+	}
+
+	return nil
+}
+
+// link delivers messages from one local provider to another according to
+// the link conditions.
+type link struct {
+	mutex      sync.Mutex
+	conditions LinkConditions
+	random     *rand.Rand
+	// isDefault is true if the link was created from default conditions of
+	// the sender.
+	isDefault bool
+}
+
+func newLink(conditions LinkConditions, isDefault bool) *link {
+	return &link{
+		conditions: conditions,
+		// #nosec G404 (insecure random number source (rand))
+		// Link conditions simulation doesn't require secure randomness.
+		random:    rand.New(rand.NewSource(conditions.Seed)),
+		isDefault: isDefault,
+	}
+}
+
+// sample decides whether the next message sent over the link is dropped
+// and draws its latency from the link latency distribution.
+func (l *link) sample() (latency time.Duration, dropped bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.random.Float64() < l.conditions.DropRate {
+		return 0, true
+	}
+
+	if l.conditions.Latency != nil {
+		latency = l.conditions.Latency(l.random)
+	}
+
+	return latency, false
+}
+
+var linksMutex sync.Mutex
+
+// links holds links between local providers with conditions set. Links are
+// indexed by the identifier of the sender and then by the identifier of the
+// receiver.
+var links map[localIdentifier]map[localIdentifier]*link
+
+// defaultLinkConditions holds default conditions of links from senders,
+// indexed by the sender identifier.
+var defaultLinkConditions map[localIdentifier]LinkConditions
+
+func setLinkConditions(
+	sender localIdentifier,
+	receiver localIdentifier,
+	conditions LinkConditions,
+) error {
+	if err := conditions.validate(); err != nil {
+		return err
+	}
+
+	linksMutex.Lock()
+	defer linksMutex.Unlock()
+
+	senderLinks(sender)[receiver] = newLink(conditions, false)
+
+	return nil
+}
+
+func setDefaultLinkConditions(
+	sender localIdentifier,
+	conditions LinkConditions,
+) error {
+	if err := conditions.validate(); err != nil {
+		return err
+	}
+
+	linksMutex.Lock()
+	defer linksMutex.Unlock()
+
+	if defaultLinkConditions == nil {
+		defaultLinkConditions = make(map[localIdentifier]LinkConditions)
+	}
+
+	defaultLinkConditions[sender] = conditions
+
+	// Links created from the previous default conditions are recreated on
+	// the next message.
+	for receiver, link := range links[sender] {
+		if link.isDefault {
+			delete(links[sender], receiver)
+		}
+	}
+
+	return nil
+}
+
+func resetLinkConditions(sender localIdentifier) {
+	linksMutex.Lock()
+	defer linksMutex.Unlock()
+
+	delete(links, sender)
+	delete(defaultLinkConditions, sender)
+}
+
+// senderLinks returns links from the given sender. Must be called with
+// linksMutex held.
+func senderLinks(sender localIdentifier) map[localIdentifier]*link {
+	if links == nil {
+		links = make(map[localIdentifier]map[localIdentifier]*link)
+	}
+
+	if _, exists := links[sender]; !exists {
+		links[sender] = make(map[localIdentifier]*link)
+	}
+
+	return links[sender]
+}
+
+// transmit calls the deliver function according to conditions of the link
+// from the sender to the receiver. The function is called immediately if
+// the link has no conditions set. The function is not called if the message
+// is dropped or the sender and the receiver are partitioned at the time of
+// delivery. In the deterministic delivery mode, i.e. if the dispatcher of
+// the sender is given, the function is queued in the dispatcher instead.
+func transmit(
+	sender localIdentifier,
+	receiver localIdentifier,
+	dispatcher *Dispatcher,
+	deliver func(),
+) {
+	deliverUnlessPartitioned := func() {
+		if arePartitioned(sender, receiver) {
+			return
+		}
+
+		deliver()
+	}
+
+	var latency time.Duration
+	if link := getLink(sender, receiver); link != nil {
+		var dropped bool
+		if latency, dropped = link.sample(); dropped {
+			return
+		}
+	}
+
+	if dispatcher != nil {
+		dispatcher.schedule(latency, deliverUnlessPartitioned)
+		return
+	}
+
+	if latency <= 0 {
+		deliverUnlessPartitioned()
+		return
+	}
+
+	time.AfterFunc(latency, deliverUnlessPartitioned)
+}
+
+func getLink(sender localIdentifier, receiver localIdentifier) *link {
+	if sender == receiver {
+		return nil
+	}
+
+	linksMutex.Lock()
+	defer linksMutex.Unlock()
+
+	if link, exists := links[sender][receiver]; exists {
+		return link
+	}
+
+	conditions, exists := defaultLinkConditions[sender]
+	if !exists {
+		return nil
+	}
+
+	// Links created from default conditions draw from different sources of
+	// randomness so that messages are not dropped for all receivers at once.
+	receiverHash := fnv.New64a()
+	_, _ = receiverHash.Write([]byte(receiver))
+	conditions.Seed ^= int64(receiverHash.Sum64())
+
+	link := newLink(conditions, true)
+	senderLinks(sender)[receiver] = link
+
+	return link
+}
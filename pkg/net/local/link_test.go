@@ -0,0 +1,338 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestLatencyDistributions(t *testing.T) {
+	var tests = map[string]struct {
+		distribution LatencyDistribution
+		min          time.Duration
+		max          time.Duration
+	}{
+		"constant": {
+			distribution: ConstantLatency(50 * time.Millisecond),
+			min:          50 * time.Millisecond,
+			max:          50 * time.Millisecond,
+		},
+		"uniform": {
+			distribution: UniformLatency(
+				10*time.Millisecond,
+				20*time.Millisecond,
+			),
+			min: 10 * time.Millisecond,
+			max: 20 * time.Millisecond,
+		},
+		"normal": {
+			distribution: NormalLatency(
+				10*time.Millisecond,
+				20*time.Millisecond,
+			),
+			min: 0,
+			max: time.Second,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			draw := func(seed int64) []time.Duration {
+				random := rand.New(rand.NewSource(seed))
+
+				latencies := make([]time.Duration, 100)
+				for i := range latencies {
+					latencies[i] = test.distribution(random)
+				}
+
+				return latencies
+			}
+
+			latencies := draw(7)
+
+			for _, latency := range latencies {
+				if latency < test.min || latency > test.max {
+					t.Fatalf(
+						"latency [%v] not in the [%v, %v] range",
+						latency,
+						test.min,
+						test.max,
+					)
+				}
+			}
+
+			if !reflect.DeepEqual(latencies, draw(7)) {
+				t.Errorf("latencies drawn with the same seed differ")
+			}
+		})
+	}
+}
+
+func TestSetLinkConditions_InvalidDropRate(t *testing.T) {
+	provider, _ := connectTestProvider(t)
+	_, receiver := connectTestProvider(t)
+
+	var tests = map[string]struct {
+		dropRate      float64
+		expectedError error
+	}{
+		"negative drop rate": {
+			dropRate:      -0.1,
+			expectedError: fmt.Errorf("drop rate [-0.1] is not in the [0, 1] range"),
+		},
+		"drop rate above one": {
+			dropRate:      1.5,
+			expectedError: fmt.Errorf("drop rate [1.5] is not in the [0, 1] range"),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			conditions := LinkConditions{DropRate: test.dropRate}
+
+			err := provider.SetLinkConditions(receiver, conditions)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected link conditions error\n"+
+						"expected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+
+			err = provider.SetDefaultLinkConditions(conditions)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected default link conditions error\n"+
+						"expected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestBroadcastChannel_LinkConditions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	sender, _ := connectTestProvider(t)
+	dropping, droppingKey := connectTestProvider(t)
+	delaying, delayingKey := connectTestProvider(t)
+	defer sender.ResetLinkConditions()
+
+	err := sender.SetLinkConditions(droppingKey, LinkConditions{DropRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latency := 200 * time.Millisecond
+	err = sender.SetLinkConditions(
+		delayingKey,
+		LinkConditions{Latency: ConstantLatency(latency)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelName := "link-conditions"
+	receive := func(provider Provider) <-chan net.Message {
+		channel, err := provider.BroadcastChannelFor(channelName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+			return &mockNetMessage{}
+		})
+
+		received := make(chan net.Message, messageHandlerThrottle)
+		channel.Recv(ctx, func(message net.Message) {
+			received <- message
+		})
+
+		return received
+	}
+
+	droppingReceived := receive(dropping)
+	delayingReceived := receive(delaying)
+
+	senderChannel, err := sender.BroadcastChannelFor(channelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	senderChannel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+
+	sentAt := time.Now()
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-delayingReceived:
+		if elapsed := time.Since(sentAt); elapsed < latency {
+			t.Errorf(
+				"message delivered too early\n"+
+					"expected at least: [%v]\nactual:            [%v]",
+				latency,
+				elapsed,
+			)
+		}
+	case <-ctx.Done():
+		t.Fatal("delayed message not delivered")
+	}
+
+	select {
+	case <-droppingReceived:
+		t.Error("dropped message delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestUnicastChannel_LinkConditions(t *testing.T) {
+	// Drops of messages sent over links with the same seed must be the same.
+	receivedSequence := func() []uint64 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		sender, senderKey := connectTestProvider(t)
+		receiver, receiverKey := connectTestProvider(t)
+		defer sender.ResetLinkConditions()
+
+		err := sender.SetDefaultLinkConditions(
+			LinkConditions{DropRate: 0.5, Seed: 42},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Link-specific conditions take precedence over the default ones.
+		err = sender.SetLinkConditions(
+			receiverKey,
+			LinkConditions{DropRate: 0.5, Seed: 7},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		senderChannel := unicastTestChannel(t, sender, receiverKey)
+		receiverChannel := unicastTestChannel(t, receiver, senderKey)
+
+		received := make(chan uint64, messageHandlerThrottle)
+		receiverChannel.Recv(ctx, func(message net.Message) {
+			received <- message.Seqno()
+		})
+
+		for i := 0; i < 100; i++ {
+			if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sequence := make([]uint64, 0)
+		for {
+			select {
+			case seqno := <-received:
+				sequence = append(sequence, seqno)
+			case <-time.After(100 * time.Millisecond):
+				return sequence
+			}
+		}
+	}
+
+	sequence := receivedSequence()
+
+	if len(sequence) == 0 || len(sequence) == 100 {
+		t.Fatalf("unexpected number of received messages: [%v]", len(sequence))
+	}
+
+	if !reflect.DeepEqual(sequence, receivedSequence()) {
+		t.Errorf("received messages differ for the same seed")
+	}
+}
+
+func TestResetLinkConditions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	sender, senderKey := connectTestProvider(t)
+	receiver, receiverKey := connectTestProvider(t)
+
+	if err := sender.SetDefaultLinkConditions(
+		LinkConditions{DropRate: 1},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	senderChannel := unicastTestChannel(t, sender, receiverKey)
+	receiverChannel := unicastTestChannel(t, receiver, senderKey)
+
+	received := make(chan net.Message, 1)
+	receiverChannel.Recv(ctx, func(message net.Message) {
+		received <- message
+	})
+
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-received:
+		t.Fatal("dropped message delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sender.ResetLinkConditions()
+
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-received:
+	case <-ctx.Done():
+		t.Fatal("message not delivered")
+	}
+}
+
+func connectTestProvider(
+	t *testing.T,
+	options ...ConnectOption,
+) (Provider, *operator.PublicKey) {
+	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ConnectWithKey(operatorPublicKey, options...), operatorPublicKey
+}
+
+func unicastTestChannel(
+	t *testing.T,
+	provider Provider,
+	remotePeer *operator.PublicKey,
+) net.UnicastChannel {
+	remotePeerID, err := provider.CreateTransportIdentifier(remotePeer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel, err := provider.UnicastChannelWith(remotePeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+
+	return channel
+}
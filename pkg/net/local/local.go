@@ -4,6 +4,7 @@
 package local
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/keep-network/keep-core/pkg/operator"
@@ -22,12 +23,55 @@ type Provider interface {
 	// AddPeer allows the simulation of adding a peer to the client's local
 	// registry of peers.
 	AddPeer(peerID string, publicKey *operator.PublicKey)
+
+	// SetLinkConditions sets conditions of the link delivering messages from
+	// this provider to the provider with the given operator public key.
+	SetLinkConditions(
+		receiver *operator.PublicKey,
+		conditions LinkConditions,
+	) error
+
+	// SetDefaultLinkConditions sets conditions of links delivering messages
+	// from this provider to all providers without link-specific conditions.
+	SetDefaultLinkConditions(conditions LinkConditions) error
+
+	// ResetLinkConditions removes conditions of all links delivering messages
+	// from this provider so that messages are delivered immediately.
+	ResetLinkConditions()
 }
 
 type localProvider struct {
 	id                localIdentifier
 	operatorPublicKey *operator.PublicKey
 	connectionManager *localConnectionManager
+	// dispatcher delivers messages sent by the provider in the deterministic
+	// delivery mode. Nil if messages are delivered as soon as they are sent.
+	dispatcher *Dispatcher
+	// recorder records messages sent by the provider. Nil if messages are
+	// not recorded.
+	recorder *Recorder
+}
+
+// ConnectOption allows to customize the local provider.
+type ConnectOption func(provider *localProvider)
+
+// WithDispatcher switches the provider to the deterministic delivery mode.
+// Messages sent by the provider are queued in the given dispatcher and
+// handlers of the provider's channels are called synchronously once the
+// dispatcher delivers messages to them. All providers taking part in
+// a test should be connected with the same dispatcher.
+func WithDispatcher(dispatcher *Dispatcher) ConnectOption {
+	return func(provider *localProvider) {
+		provider.dispatcher = dispatcher
+	}
+}
+
+// WithRecorder makes the provider record all messages it sends with the
+// given recorder. Multiple providers can share one recorder.
+func WithRecorder(recorder *Recorder) ConnectOption {
+	return func(provider *localProvider) {
+		provider.recorder = recorder
+	}
 }
 
 func (lp *localProvider) ID() net.TransportIdentifier {
@@ -35,7 +79,34 @@ func (lp *localProvider) ID() net.TransportIdentifier {
 }
 
 func (lp *localProvider) BroadcastChannelFor(name string) (net.BroadcastChannel, error) {
-	return getBroadcastChannel(name, lp.operatorPublicKey), nil
+	return getBroadcastChannel(
+		name,
+		lp.operatorPublicKey,
+		lp.dispatcher,
+		lp.recorder,
+	), nil
+}
+
+func (lp *localProvider) UnicastChannelWith(
+	peerID net.TransportIdentifier,
+) (net.UnicastChannel, error) {
+	localPeerID, err := createLocalIdentifier(lp.operatorPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	remotePeerID := localIdentifier(peerID.String())
+	if remotePeerID == localPeerID {
+		return nil, fmt.Errorf("cannot open unicast channel with self")
+	}
+
+	return getUnicastChannel(
+		localPeerID,
+		remotePeerID,
+		lp.operatorPublicKey,
+		lp.dispatcher,
+		lp.recorder,
+	), nil
 }
 
 func (lp *localProvider) Type() string {
@@ -46,6 +117,44 @@ func (lp *localProvider) AddPeer(peerID string, publicKey *operator.PublicKey) {
 	lp.connectionManager.peers[peerID] = publicKey
 }
 
+func (lp *localProvider) SetLinkConditions(
+	receiver *operator.PublicKey,
+	conditions LinkConditions,
+) error {
+	sender, err := createLocalIdentifier(lp.operatorPublicKey)
+	if err != nil {
+		return err
+	}
+
+	receiverID, err := createLocalIdentifier(receiver)
+	if err != nil {
+		return err
+	}
+
+	return setLinkConditions(sender, receiverID, conditions)
+}
+
+func (lp *localProvider) SetDefaultLinkConditions(
+	conditions LinkConditions,
+) error {
+	sender, err := createLocalIdentifier(lp.operatorPublicKey)
+	if err != nil {
+		return err
+	}
+
+	return setDefaultLinkConditions(sender, conditions)
+}
+
+func (lp *localProvider) ResetLinkConditions() {
+	sender, err := createLocalIdentifier(lp.operatorPublicKey)
+	if err != nil {
+		logger.Errorf("could not reset link conditions: [%v]", err)
+		return
+	}
+
+	resetLinkConditions(sender)
+}
+
 func (lp *localProvider) CreateTransportIdentifier(
 	operatorPublicKey *operator.PublicKey,
 ) (
@@ -59,26 +168,61 @@ func (lp *localProvider) BroadcastChannelForwarderFor(name string) {
 	//no-op
 }
 
+var connectedProvidersMutex sync.RWMutex
+
+// connectedProviders holds identifiers of all connected local providers.
+// Unicast messages can be sent only to connected providers, the same way
+// a network peer can open a stream only with a reachable peer.
+var connectedProviders map[localIdentifier]bool
+
+func isConnected(identifier localIdentifier) bool {
+	connectedProvidersMutex.RLock()
+	defer connectedProvidersMutex.RUnlock()
+
+	return connectedProviders[identifier]
+}
+
 // Connect returns a local instance of a net provider that does not go over the
 // network.
-func Connect() Provider {
+func Connect(options ...ConnectOption) Provider {
 	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
 	if err != nil {
 		panic(err)
 	}
 
-	return ConnectWithKey(operatorPublicKey)
+	return ConnectWithKey(operatorPublicKey, options...)
 }
 
 // ConnectWithKey returns a local instance of net provider that does not go
 // over the network. The returned instance uses the provided network key to
 // identify network messages.
-func ConnectWithKey(operatorPublicKey *operator.PublicKey) Provider {
-	return &localProvider{
+func ConnectWithKey(
+	operatorPublicKey *operator.PublicKey,
+	options ...ConnectOption,
+) Provider {
+	identifier, err := createLocalIdentifier(operatorPublicKey)
+	if err != nil {
+		panic(err)
+	}
+
+	connectedProvidersMutex.Lock()
+	if connectedProviders == nil {
+		connectedProviders = make(map[localIdentifier]bool)
+	}
+	connectedProviders[identifier] = true
+	connectedProvidersMutex.Unlock()
+
+	provider := &localProvider{
 		id:                randomLocalIdentifier(),
 		operatorPublicKey: operatorPublicKey,
 		connectionManager: &localConnectionManager{peers: make(map[string]*operator.PublicKey)},
 	}
+
+	for _, option := range options {
+		option(provider)
+	}
+
+	return provider
 }
 
 func (lp *localProvider) ConnectionManager() net.ConnectionManager {
@@ -0,0 +1,84 @@
+package local
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+// unlistedGroup is the partition group of providers not listed in any of the
+// partition groups.
+const unlistedGroup = -1
+
+var partitionMutex sync.RWMutex
+
+// partitionGroups holds partition group indexes of local providers, indexed
+// by provider identifiers. Nil if the network is not partitioned.
+var partitionGroups map[localIdentifier]int
+
+// Partition splits local providers into groups identified by operator public
+// keys of providers. Messages between providers of different groups are
+// dropped, including messages already sent but not delivered yet due to link
+// latency. Providers not listed in any group form one more group. The new
+// partition replaces the previous one.
+func Partition(groups ...[]*operator.PublicKey) error {
+	newPartitionGroups := make(map[localIdentifier]int)
+
+	for index, group := range groups {
+		for _, operatorPublicKey := range group {
+			identifier, err := createLocalIdentifier(operatorPublicKey)
+			if err != nil {
+				return err
+			}
+
+			if otherIndex, exists := newPartitionGroups[identifier]; exists {
+				return fmt.Errorf(
+					"provider [%v] is in groups [%v] and [%v]",
+					identifier,
+					otherIndex,
+					index,
+				)
+			}
+
+			newPartitionGroups[identifier] = index
+		}
+	}
+
+	partitionMutex.Lock()
+	defer partitionMutex.Unlock()
+
+	partitionGroups = newPartitionGroups
+
+	return nil
+}
+
+// HealPartition removes the partition of local providers so that all of
+// them can exchange messages again.
+func HealPartition() {
+	partitionMutex.Lock()
+	defer partitionMutex.Unlock()
+
+	partitionGroups = nil
+}
+
+// arePartitioned returns true if the given providers are in different
+// partition groups and cannot exchange messages.
+func arePartitioned(sender localIdentifier, receiver localIdentifier) bool {
+	partitionMutex.RLock()
+	defer partitionMutex.RUnlock()
+
+	if partitionGroups == nil {
+		return false
+	}
+
+	groupOf := func(identifier localIdentifier) int {
+		if index, exists := partitionGroups[identifier]; exists {
+			return index
+		}
+
+		return unlistedGroup
+	}
+
+	return groupOf(sender) != groupOf(receiver)
+}
@@ -0,0 +1,203 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestPartition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer HealPartition()
+
+	names := []string{"a", "b", "c", "d"}
+	providers := make(map[string]Provider)
+	keys := make(map[string]*operator.PublicKey)
+	channels := make(map[string]net.BroadcastChannel)
+
+	receivedMutex := sync.Mutex{}
+	received := make(map[string][]string)
+
+	for _, name := range names {
+		name := name
+		providers[name], keys[name] = connectTestProvider(t)
+
+		channel, err := providers[name].BroadcastChannelFor("partition")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+			return &mockNetMessage{}
+		})
+
+		channel.Recv(ctx, func(message net.Message) {
+			receivedMutex.Lock()
+			defer receivedMutex.Unlock()
+
+			for sender, key := range keys {
+				if reflect.DeepEqual(
+					operator.MarshalUncompressed(key),
+					message.SenderPublicKey(),
+				) && !slices.Contains(received[name], sender) {
+					received[name] = append(received[name], sender)
+				}
+			}
+		})
+
+		channels[name] = channel
+	}
+
+	// Sends a message from every provider and returns distinct senders of
+	// messages received by every provider.
+	broadcast := func() map[string][]string {
+		receivedMutex.Lock()
+		received = make(map[string][]string)
+		receivedMutex.Unlock()
+
+		// Retransmissions are stopped once the send context is done.
+		sendCtx, sendCancel := context.WithCancel(ctx)
+		defer sendCancel()
+
+		for _, name := range names {
+			if err := channels[name].Send(sendCtx, &mockNetMessage{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		receivedMutex.Lock()
+		defer receivedMutex.Unlock()
+
+		for _, senders := range received {
+			sort.Strings(senders)
+		}
+
+		return received
+	}
+
+	// Provider d is not listed so it forms a group on its own.
+	err := Partition(
+		[]*operator.PublicKey{keys["a"]},
+		[]*operator.PublicKey{keys["b"], keys["c"]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPartitioned := map[string][]string{
+		"a": {"a"},
+		"b": {"b", "c"},
+		"c": {"b", "c"},
+		"d": {"d"},
+	}
+	if partitioned := broadcast(); !reflect.DeepEqual(
+		expectedPartitioned,
+		partitioned,
+	) {
+		t.Errorf(
+			"unexpected messages received in partitioned network\n"+
+				"expected: [%v]\nactual:   [%v]",
+			expectedPartitioned,
+			partitioned,
+		)
+	}
+
+	HealPartition()
+
+	expectedHealed := map[string][]string{
+		"a": {"a", "b", "c", "d"},
+		"b": {"a", "b", "c", "d"},
+		"c": {"a", "b", "c", "d"},
+		"d": {"a", "b", "c", "d"},
+	}
+	if healed := broadcast(); !reflect.DeepEqual(expectedHealed, healed) {
+		t.Errorf(
+			"unexpected messages received in healed network\n"+
+				"expected: [%v]\nactual:   [%v]",
+			expectedHealed,
+			healed,
+		)
+	}
+}
+
+func TestPartition_MessagesInFlight(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	defer HealPartition()
+
+	sender, senderKey := connectTestProvider(t)
+	receiver, receiverKey := connectTestProvider(t)
+	defer sender.ResetLinkConditions()
+
+	err := sender.SetLinkConditions(
+		receiverKey,
+		LinkConditions{Latency: ConstantLatency(100 * time.Millisecond)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	senderChannel := unicastTestChannel(t, sender, receiverKey)
+	receiverChannel := unicastTestChannel(t, receiver, senderKey)
+
+	received := make(chan net.Message, 1)
+	receiverChannel.Recv(ctx, func(message net.Message) {
+		received <- message
+	})
+
+	if err := senderChannel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = Partition(
+		[]*operator.PublicKey{senderKey},
+		[]*operator.PublicKey{receiverKey},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-received:
+		t.Error("message in flight delivered across the partition")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestPartition_DuplicatedProvider(t *testing.T) {
+	_, key := connectTestProvider(t)
+
+	err := Partition(
+		[]*operator.PublicKey{key},
+		[]*operator.PublicKey{key},
+	)
+
+	identifier, _ := createLocalIdentifier(key)
+	expectedError := fmt.Errorf(
+		"provider [%v] is in groups [0] and [1]",
+		identifier,
+	)
+	if !reflect.DeepEqual(expectedError, err) {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+
+	if arePartitioned(identifier, localIdentifier("other")) {
+		t.Errorf("network partitioned with invalid groups")
+	}
+}
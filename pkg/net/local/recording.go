@@ -0,0 +1,218 @@
+package local
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordedMessage is a message sent by a local provider and captured by
+// the Recorder.
+type RecordedMessage struct {
+	// Timestamp is the time the message was sent at.
+	Timestamp time.Time `json:"timestamp"`
+	// Channel is the name of the broadcast channel the message was sent to.
+	// Empty for unicast messages.
+	Channel string `json:"channel,omitempty"`
+	// Sender is the transport identifier of the sending provider.
+	Sender string `json:"sender"`
+	// Receiver is the transport identifier of the remote peer of a unicast
+	// message. Empty for broadcast messages.
+	Receiver string `json:"receiver,omitempty"`
+	// Type is the type of the message.
+	Type string `json:"type"`
+	// Version is the format version of the message payload.
+	Version uint32 `json:"version"`
+	// Seqno is the sequence number of the message.
+	Seqno uint64 `json:"seqno"`
+	// Payload is the marshaled message.
+	Payload []byte `json:"payload"`
+}
+
+// Recorder records messages sent by local providers connected with it to
+// a file, one JSON object per line. Retransmissions of broadcast messages
+// are not recorded.
+type Recorder struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	stopped bool
+}
+
+// NewRecorder creates a recorder writing messages to the file under the
+// given path. The file is truncated if it exists. Messages sent by a local
+// provider are recorded if the provider is connected with the recorder
+// using the WithRecorder option.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create recording file: [%v]", err)
+	}
+
+	return &Recorder{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Stop stops recording messages and closes the recording file.
+func (r *Recorder) Stop() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stopped {
+		return nil
+	}
+	r.stopped = true
+
+	return r.file.Close()
+}
+
+// record writes the given message to the recording file, unless the
+// recorder is stopped. Nothing is recorded if the recorder is nil.
+func (r *Recorder) record(message *RecordedMessage) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stopped {
+		return
+	}
+
+	message.Timestamp = time.Now()
+	if err := r.encoder.Encode(message); err != nil {
+		logger.Errorf("could not record message: [%v]", err)
+	}
+}
+
+// ReadRecording reads messages from the recording file under the given path.
+func ReadRecording(path string) ([]*RecordedMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open recording file: [%v]", err)
+	}
+	defer file.Close()
+
+	messages := make([]*RecordedMessage, 0)
+
+	decoder := json.NewDecoder(file)
+	for {
+		message := &RecordedMessage{}
+		if err := decoder.Decode(message); err != nil {
+			if errors.Is(err, io.EOF) {
+				return messages, nil
+			}
+
+			return nil, fmt.Errorf(
+				"could not decode recorded message [%v]: [%v]",
+				len(messages),
+				err,
+			)
+		}
+
+		messages = append(messages, message)
+	}
+}
+
+// Replay delivers the recorded messages to channels of local providers as if
+// they were sent again by the recorded senders. Broadcast messages are
+// delivered to all channels with the recorded name and unicast messages are
+// delivered to the channel of the recorded receiver with the recorded
+// sender. Messages are unmarshaled with unmarshalers of the receiving
+// channels and are subject to link conditions and partitions of the
+// recorded senders.
+//
+// Messages are replayed in the recorded order, keeping the recorded
+// intervals between them. If the dispatcher is given, messages are replayed
+// in the deterministic delivery mode and all of them are queued in the
+// dispatcher at once. The function returns an error if the context is done
+// before all messages are replayed.
+func Replay(
+	ctx context.Context,
+	messages []*RecordedMessage,
+	dispatcher *Dispatcher,
+) error {
+	for i, message := range messages {
+		if i > 0 && dispatcher == nil {
+			interval := message.Timestamp.Sub(messages[i-1].Timestamp)
+			if interval > 0 {
+				select {
+				case <-time.After(interval):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := replayMessage(message, dispatcher); err != nil {
+			return fmt.Errorf("could not replay message [%v]: [%v]", i, err)
+		}
+	}
+
+	return nil
+}
+
+func replayMessage(message *RecordedMessage, dispatcher *Dispatcher) error {
+	sender := localIdentifier(message.Sender)
+
+	senderPublicKey, err := hex.DecodeString(message.Sender)
+	if err != nil {
+		return fmt.Errorf("invalid sender: [%v]", err)
+	}
+
+	if len(message.Receiver) > 0 {
+		receiver := localIdentifier(message.Receiver)
+		channel := getUnicastChannel(receiver, sender, nil, nil, nil)
+
+		transmit(sender, receiver, dispatcher, func() {
+			channel.receive(
+				message.Type,
+				message.Version,
+				message.Payload,
+				senderPublicKey,
+				message.Seqno,
+			)
+		})
+
+		return nil
+	}
+
+	broadcastChannelsMutex.Lock()
+	targetChannels := broadcastChannels[message.Channel]
+	broadcastChannelsMutex.Unlock()
+
+	for _, targetChannel := range targetChannels {
+		targetChannel := targetChannel
+
+		receiver, err := createLocalIdentifier(targetChannel.operatorPublicKey)
+		if err != nil {
+			return err
+		}
+
+		transmit(sender, receiver, dispatcher, func() {
+			targetChannel.receive(
+				sender,
+				message.Type,
+				message.Version,
+				message.Payload,
+				senderPublicKey,
+				message.Seqno,
+			)
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package local
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dispatcher := NewDispatcher()
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	channelName := "recording-" + randomLocalIdentifier().String()
+
+	_, senderKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, receiverKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	senderID, _ := createLocalIdentifier(senderKey)
+	receiverID, _ := createLocalIdentifier(receiverKey)
+
+	// Record a session with one broadcast and one unicast message.
+	recordCtx, recordCancel := context.WithCancel(context.Background())
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := ConnectWithKey(
+		senderKey,
+		WithDispatcher(dispatcher),
+		WithRecorder(recorder),
+	)
+	ConnectWithKey(
+		receiverKey,
+		WithDispatcher(dispatcher),
+		WithRecorder(recorder),
+	)
+
+	broadcastChannel, err := sender.BroadcastChannelFor(channelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broadcastChannel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+
+	if err := broadcastChannel.Send(recordCtx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+	// Retransmissions are not recorded.
+	dispatcher.Tick()
+
+	unicastChannel := unicastTestChannel(t, sender, receiverKey)
+	if err := unicastChannel.Send(recordCtx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages sent after the recording is stopped are not recorded.
+	if err := unicastChannel.Send(recordCtx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	recordCancel()
+	dispatcher.DeliverAll()
+
+	messages, err := ReadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 2 {
+		t.Fatalf("unexpected number of recorded messages: [%v]", len(messages))
+	}
+
+	for _, message := range messages {
+		if message.Timestamp.IsZero() {
+			t.Errorf("message recorded without timestamp")
+		}
+		message.Timestamp = messages[0].Timestamp
+	}
+
+	expectedMessages := []*RecordedMessage{
+		{
+			Timestamp: messages[0].Timestamp,
+			Channel:   channelName,
+			Sender:    senderID.String(),
+			Type:      mockNetMessageType,
+			Seqno:     1,
+			Payload:   []byte("some mocked bytes"),
+		},
+		{
+			Timestamp: messages[0].Timestamp,
+			Sender:    senderID.String(),
+			Receiver:  receiverID.String(),
+			Type:      mockNetMessageType,
+			Seqno:     1,
+			Payload:   []byte("some mocked bytes"),
+		},
+	}
+	if !reflect.DeepEqual(expectedMessages, messages) {
+		t.Errorf(
+			"unexpected recorded messages\nexpected: [%+v]\nactual:   [%+v]",
+			expectedMessages,
+			messages,
+		)
+	}
+
+	// Replay the session into fresh channels of the receiver.
+	replayCtx, replayCancel := context.WithCancel(context.Background())
+	defer replayCancel()
+
+	receiver := ConnectWithKey(receiverKey, WithDispatcher(dispatcher))
+
+	replayedBroadcastChannel, err := receiver.BroadcastChannelFor(channelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayedBroadcastChannel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+
+	replayedUnicastChannel := unicastTestChannel(t, receiver, senderKey)
+
+	replayed := make([]net.Message, 0)
+	handler := func(message net.Message) {
+		replayed = append(replayed, message)
+	}
+	replayedBroadcastChannel.Recv(replayCtx, handler)
+	replayedUnicastChannel.Recv(replayCtx, handler)
+
+	if err := Replay(replayCtx, messages, dispatcher); err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.DeliverAll()
+
+	if len(replayed) != 2 {
+		t.Fatalf("unexpected number of replayed messages: [%v]", len(replayed))
+	}
+
+	for _, message := range replayed {
+		testutils.AssertStringsEqual(
+			t,
+			"message type",
+			mockNetMessageType,
+			message.Type(),
+		)
+		testutils.AssertStringsEqual(
+			t,
+			"transport sender ID",
+			senderID.String(),
+			message.TransportSenderID().String(),
+		)
+		testutils.AssertBytesEqual(
+			t,
+			operator.MarshalUncompressed(senderKey),
+			message.SenderPublicKey(),
+		)
+	}
+}
+
+func TestReadRecording_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	content := strings.Join(
+		[]string{
+			`{"sender":"` + hex.EncodeToString([]byte{1}) + `","type":"a"}`,
+			`{"sender":`,
+		},
+		"\n",
+	)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ReadRecording(path)
+
+	expectedError := "could not decode recorded message [1]: [unexpected EOF]"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+}
+
+func TestRecorder_ProvidersWithoutRecorder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages of providers connected without the recorder, e.g. by other
+	// tests running in the same process, are not recorded.
+	_, channel, err := initTestChannel(
+		"not-recorded-" + randomLocalIdentifier().String(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := channel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := ReadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "recorded messages", 0, len(messages))
+}
@@ -0,0 +1,289 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/keep-network/keep-core/pkg/operator"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/internal"
+)
+
+var unicastChannelsMutex sync.Mutex
+
+// unicastChannels holds unicast channels of all local providers. Channels
+// are indexed by the transport identifier of the provider owning the channel
+// and then by the transport identifier of the remote peer.
+var unicastChannels map[string]map[string]*unicastChannel
+
+// getUnicastChannel returns the unicast channel the given local peer uses to
+// communicate with the given remote peer. The channel is created if it does
+// not exist yet. The operator public key of the local peer may be nil if
+// the channel is created on behalf of a remote peer sending the first
+// message; it is set along with the dispatcher and the recorder of the local
+// peer once the local peer requests the channel.
+func getUnicastChannel(
+	localPeerID localIdentifier,
+	remotePeerID localIdentifier,
+	operatorPublicKey *operator.PublicKey,
+	dispatcher *Dispatcher,
+	recorder *Recorder,
+) *unicastChannel {
+	unicastChannelsMutex.Lock()
+	defer unicastChannelsMutex.Unlock()
+
+	if unicastChannels == nil {
+		unicastChannels = make(map[string]map[string]*unicastChannel)
+	}
+
+	peerChannels, exists := unicastChannels[localPeerID.String()]
+	if !exists {
+		peerChannels = make(map[string]*unicastChannel)
+		unicastChannels[localPeerID.String()] = peerChannels
+	}
+
+	channel, exists := peerChannels[remotePeerID.String()]
+	if !exists {
+		channel = &unicastChannel{
+			localPeerID:        localPeerID,
+			remotePeerID:       remotePeerID,
+			operatorPublicKey:  operatorPublicKey,
+			messageHandlers:    make([]*messageHandler, 0),
+			unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+		}
+		peerChannels[remotePeerID.String()] = channel
+	}
+
+	if operatorPublicKey != nil {
+		channel.setOwner(operatorPublicKey, dispatcher, recorder)
+	}
+
+	return channel
+}
+
+type unicastChannel struct {
+	counter uint64
+
+	localPeerID  localIdentifier
+	remotePeerID localIdentifier
+
+	ownerMutex        sync.Mutex
+	operatorPublicKey *operator.PublicKey
+	// dispatcher delivers messages sent through the channel in the
+	// deterministic delivery mode. Nil if messages are delivered as soon as
+	// they are sent.
+	dispatcher *Dispatcher
+	// recorder records messages sent through the channel. Nil if messages
+	// are not recorded.
+	recorder *Recorder
+
+	messageHandlersMutex sync.Mutex
+	messageHandlers      []*messageHandler
+
+	unmarshalersMutex  sync.Mutex
+	unmarshalersByType map[string]func() net.TaggedUnmarshaler
+}
+
+func (uc *unicastChannel) nextSeqno() uint64 {
+	return atomic.AddUint64(&uc.counter, 1)
+}
+
+func (uc *unicastChannel) setOwner(
+	operatorPublicKey *operator.PublicKey,
+	dispatcher *Dispatcher,
+	recorder *Recorder,
+) {
+	uc.ownerMutex.Lock()
+	defer uc.ownerMutex.Unlock()
+
+	uc.operatorPublicKey = operatorPublicKey
+	uc.dispatcher = dispatcher
+	uc.recorder = recorder
+}
+
+func (uc *unicastChannel) getOwner() (
+	*operator.PublicKey,
+	*Dispatcher,
+	*Recorder,
+) {
+	uc.ownerMutex.Lock()
+	defer uc.ownerMutex.Unlock()
+
+	return uc.operatorPublicKey, uc.dispatcher, uc.recorder
+}
+
+func (uc *unicastChannel) RemotePeerID() net.TransportIdentifier {
+	return uc.remotePeerID
+}
+
+func (uc *unicastChannel) Send(
+	ctx context.Context,
+	message net.TaggedMarshaler,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	bytes, err := message.Marshal()
+	if err != nil {
+		return err
+	}
+
+	operatorPublicKey, dispatcher, recorder := uc.getOwner()
+	if operatorPublicKey == nil {
+		return fmt.Errorf("channel is not owned by a local provider")
+	}
+
+	if !isConnected(uc.remotePeerID) {
+		return fmt.Errorf(
+			"could not open stream with peer [%v]: [peer is not connected]",
+			uc.remotePeerID,
+		)
+	}
+
+	// The channel of the remote peer is created on the first message, the
+	// same way a network peer would accept an incoming connection.
+	remoteChannel := getUnicastChannel(
+		uc.remotePeerID,
+		uc.localPeerID,
+		nil,
+		nil,
+		nil,
+	)
+
+	version := internal.MessageVersion(message)
+	senderPublicKey := operator.MarshalUncompressed(operatorPublicKey)
+	seqno := uc.nextSeqno()
+
+	recorder.record(&RecordedMessage{
+		Sender:   uc.localPeerID.String(),
+		Receiver: uc.remotePeerID.String(),
+		Type:     message.Type(),
+		Version:  version,
+		Seqno:    seqno,
+		Payload:  bytes,
+	})
+
+	transmit(uc.localPeerID, uc.remotePeerID, dispatcher, func() {
+		remoteChannel.receive(
+			message.Type(),
+			version,
+			bytes,
+			senderPublicKey,
+			seqno,
+		)
+	})
+
+	return nil
+}
+
+// receive unmarshals the given message using unmarshalers registered in the
+// channel and delivers it to the message handlers. Messages of types with no
+// unmarshaler registered or of unsupported format versions are dropped.
+func (uc *unicastChannel) receive(
+	messageType string,
+	version uint32,
+	payload []byte,
+	senderPublicKey []byte,
+	seqno uint64,
+) {
+	uc.unmarshalersMutex.Lock()
+	unmarshaler, found := uc.unmarshalersByType[messageType]
+	uc.unmarshalersMutex.Unlock()
+
+	if !found {
+		logger.Warnf(
+			"couldn't find unmarshaler for type [%s]; dropping message",
+			messageType,
+		)
+		return
+	}
+
+	unmarshaled := unmarshaler()
+	err := internal.UnmarshalVersion(unmarshaled, version, payload)
+	if err != nil {
+		logger.Warnf("couldn't unmarshal message; dropping it: [%v]", err)
+		return
+	}
+
+	uc.deliver(
+		internal.BasicMessage(
+			uc.remotePeerID,
+			unmarshaled,
+			messageType,
+			senderPublicKey,
+			seqno,
+		),
+	)
+}
+
+func (uc *unicastChannel) deliver(message net.Message) {
+	uc.messageHandlersMutex.Lock()
+	snapshot := make([]*messageHandler, len(uc.messageHandlers))
+	copy(snapshot, uc.messageHandlers)
+	uc.messageHandlersMutex.Unlock()
+
+	for _, handler := range snapshot {
+		handler.handle(message)
+	}
+}
+
+func (uc *unicastChannel) Recv(ctx context.Context, handler func(m net.Message)) {
+	_, dispatcher, _ := uc.getOwner()
+
+	messageHandler := &messageHandler{
+		ctx:         ctx,
+		channel:     make(chan net.Message, messageHandlerThrottle),
+		handler:     handler,
+		synchronous: dispatcher != nil,
+	}
+
+	uc.messageHandlersMutex.Lock()
+	uc.messageHandlers = append(uc.messageHandlers, messageHandler)
+	uc.messageHandlersMutex.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Debug("context is done, removing handler")
+				uc.removeHandler(messageHandler)
+				return
+
+			case msg := <-messageHandler.channel:
+				// Handler must not be called after the context is done.
+				// See localChannel.Recv for details.
+				if messageHandler.ctx.Err() != nil {
+					continue
+				}
+
+				handler(msg)
+			}
+		}
+	}()
+}
+
+func (uc *unicastChannel) removeHandler(handler *messageHandler) {
+	uc.messageHandlersMutex.Lock()
+	defer uc.messageHandlersMutex.Unlock()
+
+	for i, h := range uc.messageHandlers {
+		if h.channel == handler.channel {
+			uc.messageHandlers[i] = uc.messageHandlers[len(uc.messageHandlers)-1]
+			uc.messageHandlers = uc.messageHandlers[:len(uc.messageHandlers)-1]
+			break
+		}
+	}
+}
+
+func (uc *unicastChannel) SetUnmarshaler(unmarshaler func() net.TaggedUnmarshaler) {
+	tpe := unmarshaler().Type()
+
+	uc.unmarshalersMutex.Lock()
+	defer uc.unmarshalersMutex.Unlock()
+
+	uc.unmarshalersByType[tpe] = unmarshaler
+}
@@ -0,0 +1,158 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestUnicastChannel_SendReceive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, operatorPublicKey1, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, operatorPublicKey2, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, operatorPublicKey3, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider1 := ConnectWithKey(operatorPublicKey1)
+	provider2 := ConnectWithKey(operatorPublicKey2)
+	provider3 := ConnectWithKey(operatorPublicKey3)
+
+	peerID1, err := provider2.CreateTransportIdentifier(operatorPublicKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerID2, err := provider1.CreateTransportIdentifier(operatorPublicKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel1, err := provider1.UnicastChannelWith(peerID2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel2, err := provider2.UnicastChannelWith(peerID1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel3, err := provider3.UnicastChannelWith(peerID2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"remote peer ID",
+		peerID2.String(),
+		channel1.RemotePeerID().String(),
+	)
+
+	channel2.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+
+	received := make(chan net.Message, 2)
+	channel2.Recv(ctx, func(msg net.Message) {
+		received <- msg
+	})
+
+	// A message sent by another peer must not reach the channel with
+	// the first peer.
+	if err := channel3.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := channel1.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-received:
+		testutils.AssertStringsEqual(
+			t,
+			"message type",
+			mockNetMessageType,
+			msg.Type(),
+		)
+		testutils.AssertStringsEqual(
+			t,
+			"transport sender ID",
+			peerID1.String(),
+			msg.TransportSenderID().String(),
+		)
+		testutils.AssertBytesEqual(
+			t,
+			operator.MarshalUncompressed(operatorPublicKey1),
+			msg.SenderPublicKey(),
+		)
+	case <-ctx.Done():
+		t.Fatal("expected message not received")
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected message from [%v]", msg.TransportSenderID())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestUnicastChannel_Self(t *testing.T) {
+	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := ConnectWithKey(operatorPublicKey)
+
+	peerID, err := provider.CreateTransportIdentifier(operatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.UnicastChannelWith(peerID); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnicastChannel_NotConnectedPeer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	provider, _ := connectTestProvider(t)
+
+	// No provider is connected with the remote peer key.
+	_, remotePublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := unicastTestChannel(t, provider, remotePublicKey)
+
+	err = channel.Send(ctx, &mockNetMessage{})
+
+	expectedError := fmt.Errorf(
+		"could not open stream with peer [%v]: [peer is not connected]",
+		channel.RemotePeerID(),
+	)
+	if !reflect.DeepEqual(expectedError, err) {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+}
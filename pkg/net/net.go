@@ -39,6 +39,18 @@ type Message interface {
 	Seqno() uint64
 }
 
+// SignedMessage is a Message received along with the envelope signed by its
+// sender. The envelope allows a third party to verify the sender has sent
+// the message, e.g. when the message is an evidence of the sender's
+// misbehavior.
+type SignedMessage interface {
+	Message
+
+	// SignedEnvelope returns the marshaled envelope carrying the message and
+	// the signature of its sender, as received from the network.
+	SignedEnvelope() ([]byte, error)
+}
+
 // TaggedMarshaler is an interface that includes the proto.Marshaler interface,
 // but also provides a string type for the marshalable object.
 type TaggedMarshaler interface {
@@ -49,7 +61,8 @@ type TaggedMarshaler interface {
 // Provider represents an entity that can provide network access.
 //
 // Providers expose the ability to get a named BroadcastChannel, the ability to
-// return a provider type, which is an informational string indicating what type
+// get a UnicastChannel with the given remote peer, the ability to return
+// a provider type, which is an informational string indicating what type
 // of provider this is, the list of IP addresses on which it can listen, and
 // known peers from peer discovery mechanims.
 type Provider interface {
@@ -62,6 +75,12 @@ type Provider interface {
 	// channel name.
 	BroadcastChannelFor(name string) (BroadcastChannel, error)
 
+	// UnicastChannelWith provides a unicast channel instance for the given
+	// remote peer. The remote peer is identified by the transport identifier
+	// returned by CreateTransportIdentifier for the peer's operator public
+	// key. An error is returned for the provider's own transport identifier.
+	UnicastChannelWith(peerID TransportIdentifier) (UnicastChannel, error)
+
 	// ConnectionManager returns the connection manager used by the provider.
 	ConnectionManager() ConnectionManager
 
@@ -75,6 +94,115 @@ type Provider interface {
 	BroadcastChannelForwarderFor(name string)
 }
 
+// CompressionStats holds statistics of broadcast channel payloads compressed
+// by the provider before sending them to the network.
+type CompressionStats struct {
+	// CompressedMessages is the number of sent messages whose payloads were
+	// compressed.
+	CompressedMessages uint64
+	// UncompressedBytes is the total size of payloads of compressed messages
+	// before the compression.
+	UncompressedBytes uint64
+	// CompressedBytes is the total size of payloads of compressed messages
+	// after the compression.
+	CompressedBytes uint64
+}
+
+// CompressionStatsSource is implemented by providers compressing broadcast
+// channel payloads. It is not a part of the Provider interface as not all
+// providers support the compression.
+type CompressionStatsSource interface {
+	// CompressionStats returns broadcast channel payloads compression
+	// statistics collected since the provider was started.
+	CompressionStats() CompressionStats
+}
+
+// DuplicateMessagesSource is implemented by providers suppressing duplicate
+// broadcast channel messages. It is not a part of the Provider interface as
+// not all providers suppress duplicates.
+type DuplicateMessagesSource interface {
+	// DuplicateMessagesCount returns the number of duplicate broadcast
+	// channel messages dropped since the provider was started.
+	DuplicateMessagesCount() uint64
+}
+
+// TopicStats holds statistics of messages delivered in a single broadcast
+// channel.
+type TopicStats struct {
+	// ReceivedMessages is the number of messages received from other peers.
+	ReceivedMessages uint64
+	// ReceivedMessagesRate is the recent number of messages per second
+	// received from other peers.
+	ReceivedMessagesRate float64
+	// SentMessages is the number of messages published by the provider.
+	SentMessages uint64
+	// SentMessagesRate is the recent number of messages per second published
+	// by the provider.
+	SentMessagesRate float64
+}
+
+// NetworkStats holds connectivity and traffic statistics of the provider.
+type NetworkStats struct {
+	// BytesIn is the total number of bytes received from other peers.
+	BytesIn uint64
+	// BytesOut is the total number of bytes sent to other peers.
+	BytesOut uint64
+	// BytesInRate is the recent number of bytes per second received from
+	// other peers.
+	BytesInRate float64
+	// BytesOutRate is the recent number of bytes per second sent to other
+	// peers.
+	BytesOutRate float64
+	// HandshakeFailures is the number of connections with other peers that
+	// could not be secured, including peers rejected by the firewall.
+	HandshakeFailures uint64
+	// BootstrapPeers is the number of currently known bootstrap peers.
+	BootstrapPeers int
+	// BootstrapFailedRounds is the number of consecutive bootstrap rounds
+	// that failed to connect with any bootstrap peer. Zero means the last
+	// round succeeded.
+	BootstrapFailedRounds int
+	// Topics holds statistics of every broadcast channel, by channel name.
+	Topics map[string]TopicStats
+}
+
+// NetworkStatsSource is implemented by providers collecting connectivity and
+// traffic statistics. It is not a part of the Provider interface as not all
+// providers collect them.
+type NetworkStatsSource interface {
+	// NetworkStats returns connectivity and traffic statistics collected
+	// since the provider was started.
+	NetworkStats() NetworkStats
+}
+
+// ConnectionGaterRules holds allow and deny lists used to gate connections
+// with remote peers. A connection is gated if the remote peer ID is denied
+// or the remote IP address is denied. Allow lists take precedence over deny
+// lists so that, for example, a whole subnet can be denied except for
+// selected addresses.
+type ConnectionGaterRules struct {
+	// AllowedPeers are IDs of peers never gated by DeniedPeers.
+	AllowedPeers []string
+	// DeniedPeers are IDs of peers whose connections are gated.
+	DeniedPeers []string
+	// AllowedIPs are IP addresses or CIDR ranges never gated by DeniedIPs.
+	AllowedIPs []string
+	// DeniedIPs are IP addresses or CIDR ranges whose connections are gated.
+	DeniedIPs []string
+}
+
+// ConnectionGater is implemented by providers gating connections with remote
+// peers. It is not a part of the Provider interface as not all providers
+// gate connections.
+type ConnectionGater interface {
+	// ConnectionGaterRules returns the current connection gater rules.
+	ConnectionGaterRules() ConnectionGaterRules
+	// SetConnectionGaterRules replaces the connection gater rules. Existing
+	// connections that are gated by the new rules are closed. An error is
+	// returned and the rules are not changed if any of them is invalid.
+	SetConnectionGaterRules(rules ConnectionGaterRules) error
+}
+
 // ConnectionManager is an interface which exposes peers a client is connected
 // to, and their individual identities, so that a client may forcibly disconnect
 // from any given connected peer.
@@ -99,6 +227,39 @@ type TaggedUnmarshaler interface {
 	Type() string
 }
 
+// VersionedMarshaler is implemented by messages with a versioned format.
+// The format version is sent along with the message type and payload so
+// that the format of a message type can change without breaking the network.
+// Messages not implementing this interface are sent with version zero.
+//
+// Versions are not negotiated between peers at runtime: peers do not
+// advertise versions they support and the sender does not learn them.
+// A message of the given version is dropped by receivers not supporting it
+// so a new version is rolled out in two steps. First, a client release adds
+// the version to the supported versions of the message. Then, once all
+// receivers support it, a subsequent release starts sending it. The switch
+// to the new version should be tied to a condition all peers observe the
+// same way, e.g. a chain parameter or block, rather than to the sender's
+// client version.
+type VersionedMarshaler interface {
+	TaggedMarshaler
+	// Version returns the format version the message is marshaled with.
+	Version() uint32
+}
+
+// VersionedUnmarshaler is implemented by messages able to unmarshal more than
+// one format version. Messages not implementing this interface support
+// version zero only.
+type VersionedUnmarshaler interface {
+	TaggedUnmarshaler
+	// SupportedVersions returns the lowest and the highest format version
+	// the message can be unmarshaled from.
+	SupportedVersions() (uint32, uint32)
+	// UnmarshalVersion unmarshals the message from bytes of the given format
+	// version. The version is always within the supported range.
+	UnmarshalVersion(version uint32, bytes []byte) error
+}
+
 // BroadcastChannel represents a named pubsub channel. It allows group members
 // to broadcast and receive messages. BroadcastChannel implements strategy
 // for the retransmission of broadcast messages and handle duplicates before
@@ -137,6 +298,43 @@ type BroadcastChannel interface {
 	// to determine if given broadcast channel message should be processed
 	// by the receivers.
 	SetFilter(filter BroadcastChannelFilter) error
+	// AddValidator registers a broadcast channel validator which will be
+	// used to validate every message of the channel before it is delivered
+	// to the receivers. Invalid messages are dropped and not propagated
+	// further in the network. Once a validator is registered, messages of
+	// types without a registered unmarshaler are dropped as well.
+	AddValidator(validator BroadcastChannelValidator) error
+}
+
+// UnicastChannel represents a point-to-point channel with a single remote
+// peer. Messages sent through the channel are delivered only to the remote
+// peer and messages received through the channel are guaranteed to be
+// authored by the remote peer. Unlike BroadcastChannel, UnicastChannel does
+// not retransmit messages; the delivery is best-effort and the client is
+// responsible for retransmitting messages if needed.
+type UnicastChannel interface {
+	// RemotePeerID returns the transport identifier of the remote peer.
+	RemotePeerID() TransportIdentifier
+	// Send sends a message to the remote peer. Message needs to conform to
+	// the marshalling interface. The message is sent once; an error is
+	// returned if it could not be handed over to the transport layer within
+	// the lifetime of the provided context.
+	Send(ctx context.Context, message TaggedMarshaler) error
+	// Recv installs a message handler that will receive messages from the
+	// remote peer for the entire lifetime of the provided context.
+	// When the context is done, handler is automatically unregistered and
+	// receives no more messages. Messages received when no handler is
+	// installed are dropped.
+	Recv(ctx context.Context, handler func(m Message))
+	// SetUnmarshaler set an unmarshaler that will unmarshal a given type
+	// to a concrete object that can be passed to and understood by any
+	// registered message handling functions. Unmarshalers are registered
+	// separately for each unicast channel and are independent of the
+	// unmarshalers registered for broadcast channels.
+	//
+	// The string type associated with the unmarshaler is the result of calling
+	// Type() on a raw unmarshaler.
+	SetUnmarshaler(unmarshaler func() TaggedUnmarshaler)
 }
 
 // BroadcastChannelFilter represents a filter which determine if the incoming
@@ -145,6 +343,12 @@ type BroadcastChannel interface {
 // processed or false otherwise.
 type BroadcastChannelFilter func(*operator.PublicKey) bool
 
+// BroadcastChannelValidator represents a validator which determines if the
+// incoming message is well-formed and valid from the protocol perspective.
+// It takes the unmarshaled message as its argument and returns an error
+// describing the problem if the message is invalid or nil otherwise.
+type BroadcastChannelValidator func(Message) error
+
 // Firewall represents a set of rules the remote peer has to conform to so that
 // a connection with that peer can be approved.
 type Firewall interface {
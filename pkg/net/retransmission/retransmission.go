@@ -52,8 +52,23 @@ func ScheduleRetransmissions(
 // considered the same. Handler can not be reused between channels if sequence
 // number of message is local for channel.
 func WithRetransmissionSupport(delegate func(m net.Message)) func(m net.Message) {
+	return WithBoundedRetransmissionSupport(delegate, 0)
+}
+
+// WithBoundedRetransmissionSupport works like WithRetransmissionSupport but
+// remembers at most cacheSize messages. Once the limit is reached, the oldest
+// seen message is forgotten so its further retransmissions are passed to the
+// delegate handler again. Zero cacheSize means the number of remembered
+// messages is not limited.
+func WithBoundedRetransmissionSupport(
+	delegate func(m net.Message),
+	cacheSize int,
+) func(m net.Message) {
 	mutex := &sync.Mutex{}
 	cache := make(map[string]bool)
+	// order holds IDs of cached messages from the oldest to the newest and
+	// is used only if the cache size is limited.
+	order := make([]string, 0)
 
 	return func(message net.Message) {
 		messageID := fmt.Sprintf(
@@ -66,6 +81,14 @@ func WithRetransmissionSupport(delegate func(m net.Message)) func(m net.Message)
 		_, seen := cache[messageID]
 		if !seen {
 			cache[messageID] = true
+
+			if cacheSize > 0 {
+				order = append(order, messageID)
+				if len(order) > cacheSize {
+					delete(cache, order[0])
+					order = order[1:]
+				}
+			}
 		}
 		mutex.Unlock()
 
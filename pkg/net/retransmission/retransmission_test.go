@@ -78,6 +78,32 @@ func TestHandlerReceiveRetransmissions(t *testing.T) {
 	}
 }
 
+func TestBoundedHandlerReceiveRetransmissions(t *testing.T) {
+	var received []net.Message
+
+	handler := WithBoundedRetransmissionSupport(
+		func(message net.Message) {
+			received = append(received, message)
+		},
+		2,
+	)
+
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 2})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 3})
+	// a-1 is the oldest message so it was forgotten when a-3 was seen.
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 3})
+
+	if len(received) != 4 {
+		t.Fatalf(
+			"unexpected number of accepted messages\nactual:   [%v]\nexpected: [4]",
+			len(received),
+		)
+	}
+}
+
 type mockNetworkMessage struct {
 	senderID string
 	seqno    uint64
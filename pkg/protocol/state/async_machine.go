@@ -41,6 +41,10 @@ type AsyncMachine struct {
 	ctx          context.Context
 	channel      net.BroadcastChannel
 	initialState AsyncState // first state from which execution starts
+
+	// completedStates is the number of states completed during the
+	// execution.
+	completedStates int
 }
 
 // NewAsyncMachine returns a new protocol asynchronous state machine
@@ -116,6 +120,8 @@ func (am *AsyncMachine) Execute() (AsyncState, error) {
 				)
 			}
 
+			am.completedStates++
+
 			if nextState == nil {
 				am.logger.Infof(
 					"[member:%v,state:%T] reached final state",
@@ -138,6 +144,13 @@ func (am *AsyncMachine) Execute() (AsyncState, error) {
 	}
 }
 
+// CompletedStates returns the number of states completed during the
+// execution, including the final state if it was reached. It should be
+// called once Execute returns.
+func (am *AsyncMachine) CompletedStates() int {
+	return am.completedStates
+}
+
 // asyncStateTransition kicks of the state initiation calling Initiate()
 // function and returns the channel that gets closed when the initiation is
 // done. In case the initiation failed, the channel receives an error from
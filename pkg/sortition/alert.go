@@ -0,0 +1,208 @@
+package sortition
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// DefaultAlertHookTimeout is the maximum time an alert hook is given to
+// deliver an alert.
+const DefaultAlertHookTimeout = 30 * time.Second
+
+// ineligibleForRewardsEvent is the name of the event the chain reports when
+// the operator is marked as ineligible for rewards.
+const ineligibleForRewardsEvent = "IneligibleForRewards"
+
+// unknownIneligibilityReason is the reason of the rewards ineligibility used
+// if the event marking the operator as ineligible was not observed or the
+// reason could not be determined from it.
+const unknownIneligibilityReason = "unknown"
+
+// RewardsIneligibilityAlert is raised when the operator becomes ineligible
+// for rewards in the sortition pool of an application.
+type RewardsIneligibilityAlert struct {
+	// Application is the name of the application.
+	Application string `json:"application"`
+	// StakingProvider is the staking provider the operator is registered for.
+	StakingProvider string `json:"staking_provider"`
+	// Reason describes why the operator was marked as ineligible for rewards.
+	Reason string `json:"reason"`
+	// RestorableAt is the earliest time at which the operator's eligibility
+	// for rewards can be restored.
+	RestorableAt time.Time `json:"restorable_at"`
+	// DetectedAt is the time the ineligibility was detected at.
+	DetectedAt time.Time `json:"detected_at"`
+	// Text is a human-readable summary of the alert. It lets the alert be
+	// posted as-is to chat services accepting Slack-compatible webhooks.
+	Text string `json:"text"`
+}
+
+func newRewardsIneligibilityAlert(
+	application string,
+	status *OperatorStatus,
+	reason string,
+) *RewardsIneligibilityAlert {
+	return &RewardsIneligibilityAlert{
+		Application:     application,
+		StakingProvider: status.StakingProvider.String(),
+		Reason:          reason,
+		RestorableAt:    status.RewardEligibilityRestorableAt,
+		DetectedAt:      time.Now(),
+		Text: fmt.Sprintf(
+			"operator of staking provider [%s] is ineligible for rewards "+
+				"in the [%s] sortition pool; reason: [%s]; eligibility "+
+				"can be restored at [%s]",
+			status.StakingProvider,
+			application,
+			reason,
+			status.RewardEligibilityRestorableAt.Format(time.RFC3339),
+		),
+	}
+}
+
+// AlertHook delivers the alert to the operator.
+type AlertHook func(alert *RewardsIneligibilityAlert) error
+
+// NewWebhookAlertHook returns an alert hook posting the alert, encoded as
+// JSON, to the given URL. Responses with a status other than 2xx are
+// considered failures.
+func NewWebhookAlertHook(url string) AlertHook {
+	client := &http.Client{Timeout: DefaultAlertHookTimeout}
+
+	return func(alert *RewardsIneligibilityAlert) error {
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return fmt.Errorf("could not marshal alert: [%w]", err)
+		}
+
+		response, err := client.Post(
+			url,
+			"application/json",
+			bytes.NewReader(payload),
+		)
+		if err != nil {
+			return fmt.Errorf("could not post alert: [%w]", err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return fmt.Errorf(
+				"unexpected webhook response status: [%s]",
+				response.Status,
+			)
+		}
+
+		return nil
+	}
+}
+
+// NewCommandAlertHook returns an alert hook executing the given command with
+// the shell. The alert, encoded as JSON, is passed to the command's standard
+// input, e.g. to be piped to a mail client. The command is considered failed
+// if it exits with a non-zero status or does not complete within
+// DefaultAlertHookTimeout.
+func NewCommandAlertHook(command string) AlertHook {
+	return func(alert *RewardsIneligibilityAlert) error {
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return fmt.Errorf("could not marshal alert: [%w]", err)
+		}
+
+		ctx, cancelCtx := context.WithTimeout(
+			context.Background(),
+			DefaultAlertHookTimeout,
+		)
+		defer cancelCtx()
+
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+		cmd.Stdin = bytes.NewReader(payload)
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf(
+				"alert command failed: [%w]; output: [%s]",
+				err,
+				bytes.TrimSpace(output),
+			)
+		}
+
+		return nil
+	}
+}
+
+// WithRewardsIneligibilityAlertHooks sets hooks notified when the operator
+// becomes ineligible for rewards in a monitored sortition pool. Hooks are
+// executed in the background, one after another, so that a slow hook does
+// not delay status checks. Failures of hooks are logged.
+func WithRewardsIneligibilityAlertHooks(hooks ...AlertHook) MonitorOption {
+	return func(config *monitorConfig) {
+		config.alertHooks = append(config.alertHooks, hooks...)
+	}
+}
+
+// alertRewardsIneligibility raises an alert if the status observed by the
+// check shows the operator became ineligible for rewards. An alert is raised
+// once per ineligibility period. Periods are told apart by the time the
+// eligibility can be restored at so an extended ineligibility raises another
+// alert. Must be called only by the status check.
+func (pm *PoolsMonitor) alertRewardsIneligibility(
+	app *monitoredApplication,
+	status *OperatorStatus,
+) {
+	if !status.IsInPool {
+		return
+	}
+
+	if status.IsEligibleForRewards {
+		app.alertedRestorableAt = time.Time{}
+		return
+	}
+
+	if status.RewardEligibilityRestorableAt.Equal(app.alertedRestorableAt) {
+		return
+	}
+	app.alertedRestorableAt = status.RewardEligibilityRestorableAt
+
+	pm.mutex.Lock()
+	eventBlock, isEventObserved := app.lastEventBlocks[ineligibleForRewardsEvent]
+	pm.mutex.Unlock()
+
+	reason := unknownIneligibilityReason
+	if isEventObserved {
+		eventReason, err := app.chain.RewardsIneligibilityReason(eventBlock)
+		if err != nil {
+			app.logger.Warnf(
+				"could not determine rewards ineligibility reason: [%v]",
+				err,
+			)
+		} else if eventReason != "" {
+			reason = eventReason
+		}
+	}
+
+	alert := newRewardsIneligibilityAlert(app.name, status, reason)
+
+	app.logger.Warn(alert.Text)
+
+	hooks := pm.config.alertHooks
+	if len(hooks) == 0 {
+		return
+	}
+
+	go func() {
+		for i, hook := range hooks {
+			if err := hook(alert); err != nil {
+				app.logger.Errorf(
+					"could not deliver rewards ineligibility alert "+
+						"with hook [%d]: [%v]",
+					i,
+					err,
+				)
+			}
+		}
+	}()
+}
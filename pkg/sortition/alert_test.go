@@ -0,0 +1,231 @@
+package sortition
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestPoolsMonitor_RewardsIneligibilityAlert(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+	localChain.SetCurrentTimestamp(big.NewInt(0))
+	localChain.JoinSortitionPool()
+
+	alerts := make(chan *RewardsIneligibilityAlert, 10)
+
+	poolsMonitor := NewPoolsMonitor(
+		time.Hour,
+		WithRewardsIneligibilityAlertHooks(
+			func(alert *RewardsIneligibilityAlert) error {
+				alerts <- alert
+				return nil
+			},
+		),
+	)
+
+	_, err := poolsMonitor.Register(
+		"tbtc",
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolsMonitor.Start(ctx)
+
+	expectNoAlert := func(description string) {
+		select {
+		case alert := <-alerts:
+			t.Fatalf("unexpected alert %s: [%s]", description, alert.Text)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	expectAlert := func() *RewardsIneligibilityAlert {
+		select {
+		case alert := <-alerts:
+			return alert
+		case <-time.After(time.Second):
+			t.Fatal("alert not raised")
+			return nil
+		}
+	}
+
+	expectNoAlert("for an eligible operator")
+
+	localChain.SetRewardIneligibility(big.NewInt(1000))
+	localChain.SetRewardsIneligibilityReason(10, "inactivity claimed")
+	localChain.EmitOperatorStatusChanged(ineligibleForRewardsEvent, 10)
+
+	alert := expectAlert()
+	testutils.AssertStringsEqual(t, "application", "tbtc", alert.Application)
+	testutils.AssertStringsEqual(
+		t,
+		"staking provider",
+		testStakingProviderAddress,
+		alert.StakingProvider,
+	)
+	testutils.AssertStringsEqual(t, "reason", "inactivity claimed", alert.Reason)
+	testutils.AssertBoolsEqual(
+		t,
+		"restorable at",
+		true,
+		alert.RestorableAt.Equal(time.Unix(1000, 0)),
+	)
+
+	// The alert is not raised again for the same ineligibility period.
+	localChain.EmitOperatorStatusChanged(ineligibleForRewardsEvent, 11)
+	expectNoAlert("for the same ineligibility period")
+
+	// The ineligibility got extended; the reason is not known for the block.
+	localChain.SetRewardIneligibility(big.NewInt(2000))
+	localChain.EmitOperatorStatusChanged(ineligibleForRewardsEvent, 12)
+
+	alert = expectAlert()
+	testutils.AssertStringsEqual(
+		t,
+		"reason",
+		unknownIneligibilityReason,
+		alert.Reason,
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"restorable at",
+		true,
+		alert.RestorableAt.Equal(time.Unix(2000, 0)),
+	)
+}
+
+func TestNewWebhookAlertHook(t *testing.T) {
+	var tests = map[string]struct {
+		responseStatus int
+		expectedError  string
+	}{
+		"accepted": {
+			responseStatus: http.StatusOK,
+		},
+		"rejected": {
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  "unexpected webhook response status: [500 Internal Server Error]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var received RewardsIneligibilityAlert
+
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					testutils.AssertStringsEqual(
+						t,
+						"content type",
+						"application/json",
+						r.Header.Get("Content-Type"),
+					)
+
+					body, err := io.ReadAll(r.Body)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if err := json.Unmarshal(body, &received); err != nil {
+						t.Fatal(err)
+					}
+
+					w.WriteHeader(test.responseStatus)
+				},
+			))
+			defer server.Close()
+
+			err := NewWebhookAlertHook(server.URL)(newTestAlert())
+			assertErrorContains(t, test.expectedError, err)
+
+			testutils.AssertStringsEqual(
+				t,
+				"received reason",
+				"inactivity claimed",
+				received.Reason,
+			)
+		})
+	}
+}
+
+func TestNewCommandAlertHook(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "alert.json")
+
+	err := NewCommandAlertHook("cat > " + outputPath)(newTestAlert())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received RewardsIneligibilityAlert
+	if err := json.Unmarshal(output, &received); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(t, "application", "beacon", received.Application)
+	testutils.AssertStringsEqual(
+		t,
+		"reason",
+		"inactivity claimed",
+		received.Reason,
+	)
+
+	err = NewCommandAlertHook("echo no mail client; exit 1")(newTestAlert())
+	assertErrorContains(
+		t,
+		"alert command failed: [exit status 1]; output: [no mail client]",
+		err,
+	)
+}
+
+func newTestAlert() *RewardsIneligibilityAlert {
+	return newRewardsIneligibilityAlert(
+		"beacon",
+		&OperatorStatus{
+			StakingProvider:               testStakingProviderAddress,
+			RewardEligibilityRestorableAt: time.Unix(1000, 0),
+		},
+		"inactivity claimed",
+	)
+}
+
+func assertErrorContains(t *testing.T, expectedError string, err error) {
+	if expectedError == "" {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	if err == nil || !strings.Contains(err.Error(), expectedError) {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedError,
+			err,
+		)
+	}
+}
@@ -2,8 +2,10 @@ package sortition
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
 )
 
 // Chain handle for interaction with the sortition pool contracts.
@@ -39,6 +41,11 @@ type Chain interface {
 	// is non-zero, function returns false.
 	IsOperatorUpToDate() (bool, error)
 
+	// OperatorWeight returns the current weight of the operator in
+	// the sortition pool. If the operator is not in the sortition pool,
+	// the returned weight is zero.
+	OperatorWeight() (*big.Int, error)
+
 	// JoinSortitionPool executes a transaction to have the operator join the
 	// sortition pool.
 	JoinSortitionPool() error
@@ -58,6 +65,17 @@ type Chain interface {
 	// Restores reward eligibility for the operator.
 	RestoreRewardEligibility() error
 
+	// RewardEligibilityRestorableAt returns the earliest time at which the
+	// operator's eligibility for rewards can be restored. The returned time
+	// is meaningful only if the operator is ineligible for rewards.
+	RewardEligibilityRestorableAt() (time.Time, error)
+
+	// RewardsIneligibilityReason returns the reason the operator was marked
+	// as ineligible for rewards in the given block, determined from the
+	// application events emitted in that block. If the reason cannot be
+	// determined, an empty string is returned.
+	RewardsIneligibilityReason(blockNumber uint64) (string, error)
+
 	// Returns true if the chaosnet phase is active, false otherwise.
 	IsChaosnetActive() (bool, error)
 
@@ -67,4 +85,15 @@ type Chain interface {
 
 	// GetOperatorID returns the operator ID for the given operator address.
 	GetOperatorID(operatorAddress chain.Address) (chain.OperatorID, error)
+
+	// OnOperatorStatusChanged registers a callback that is invoked when
+	// an on-chain event that may change the status of the operator in the
+	// sortition pool is emitted: the operator registration, an authorization
+	// increase or decrease of the operator's staking provider, or a change
+	// of the operator's eligibility for rewards. The callback receives the
+	// name of the event and the number of the block it was emitted in.
+	// The same event may be delivered more than once.
+	OnOperatorStatusChanged(
+		handler func(event string, blockNumber uint64),
+	) subscription.EventSubscription
 }
@@ -0,0 +1,40 @@
+package sortition
+
+import "github.com/keep-network/keep-common/pkg/chain/ethereum"
+
+// Config stores configuration of the sortition pool monitoring.
+type Config struct {
+	// RegistrationBootstrap enables the registration bootstrap mode. If the
+	// operator is not registered for a staking provider yet, the client does
+	// not fail but keeps reporting which on-chain registration step is
+	// missing until the operator gets registered.
+	RegistrationBootstrap bool
+	// StakingProvider is the address of the staking provider the operator
+	// is going to be registered for, used in the registration bootstrap
+	// mode. If not set, the operator address is assumed to be the staking
+	// provider address.
+	StakingProvider string
+	// AutoRegisterOperator enables the registration of the operator by the
+	// client in the registration bootstrap mode. The client can register
+	// the operator only if the operator address is the staking provider
+	// address, once the stake is delegated and authorized.
+	AutoRegisterOperator bool
+	// RewardsIneligibilityWebhook is the URL the alert is posted to, as JSON,
+	// when the operator becomes ineligible for rewards in a sortition pool.
+	RewardsIneligibilityWebhook string
+	// RewardsIneligibilityCommand is the shell command executed when the
+	// operator becomes ineligible for rewards in a sortition pool. The
+	// alert, encoded as JSON, is passed to the command's standard input.
+	RewardsIneligibilityCommand string
+	// MinimumJoinStake is the minimum eligible stake of the staking provider
+	// the client joins the sortition pools with. Zero means no minimum.
+	MinimumJoinStake ethereum.Wei
+	// MaintenanceWindows are recurring time windows, in the
+	// `[Day ]HH:MM-HH:MM` format in UTC, during which the client neither
+	// joins the sortition pools nor updates the operator in them.
+	MaintenanceWindows []string
+	// DryRun enables the dry-run mode in which the client only logs
+	// transactions the sortition pool monitoring would submit, along with
+	// their estimated gas, without submitting them.
+	DryRun bool
+}
@@ -0,0 +1,79 @@
+package sortition
+
+import (
+	"github.com/ipfs/go-log"
+)
+
+// GasEstimationChain handle for estimating gas of transactions joining the
+// sortition pool and updating the operator in it. In the dry-run mode, the
+// estimated gas is reported only for chains implementing it.
+type GasEstimationChain interface {
+	// JoinSortitionPoolGasEstimate returns the estimated gas of the
+	// transaction having the operator join the sortition pool.
+	JoinSortitionPoolGasEstimate() (uint64, error)
+
+	// UpdateOperatorStatusGasEstimate returns the estimated gas of the
+	// transaction updating the operator's state in the sortition pool.
+	UpdateOperatorStatusGasEstimate() (uint64, error)
+}
+
+// WithDryRun enables the dry-run mode. In the dry-run mode, the monitoring
+// checks the operator status as usual but only logs transactions it would
+// submit, along with the estimated gas of transactions joining the pool and
+// updating the operator in it. No transaction is submitted.
+func WithDryRun() MonitorOption {
+	return func(config *monitorConfig) {
+		config.dryRun = true
+	}
+}
+
+// gasEstimateFn returns the function estimating gas of the transaction
+// taking the given action, nil if the chain does not support it.
+func gasEstimateFn(chain Chain, action OperatorAction) func() (uint64, error) {
+	gasEstimationChain, ok := chain.(GasEstimationChain)
+	if !ok {
+		return nil
+	}
+
+	switch action {
+	case ActionJoinPool:
+		return gasEstimationChain.JoinSortitionPoolGasEstimate
+	case ActionUpdateOperatorStatus:
+		return gasEstimationChain.UpdateOperatorStatusGasEstimate
+	default:
+		return nil
+	}
+}
+
+// logDryRunTransaction logs the transaction the monitoring would submit if
+// not in the dry-run mode. The estimated gas is logged if estimateFn is set.
+func logDryRunTransaction(
+	logger log.StandardLogger,
+	transaction string,
+	estimateFn func() (uint64, error),
+) {
+	if estimateFn == nil {
+		logger.Warnf(
+			"dry run; not submitting transaction to [%s]",
+			transaction,
+		)
+		return
+	}
+
+	gas, err := estimateFn()
+	if err != nil {
+		logger.Warnf(
+			"dry run; not submitting transaction to [%s]; "+
+				"could not estimate gas: [%v]",
+			transaction,
+			err,
+		)
+		return
+	}
+
+	logger.Warnf(
+		"dry run; not submitting transaction to [%s]; estimated gas: [%d]",
+		transaction,
+		gas,
+	)
+}
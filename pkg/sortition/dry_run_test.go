@@ -0,0 +1,144 @@
+package sortition
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestMonitor_DryRun_JoinPool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+
+	logger := &warningsRecordingLogger{}
+
+	poolsMonitor := NewPoolsMonitor(time.Hour, WithDryRun())
+
+	_, err := poolsMonitor.Register(
+		"tbtc",
+		logger,
+		&gasEstimatingChain{localChain},
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolsMonitor.Start(ctx)
+
+	isOperatorInPool, err := localChain.IsOperatorInPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isOperatorInPool {
+		t.Fatal("expected the operator not to join the pool")
+	}
+
+	logger.assertWarning(
+		t,
+		"dry run; not submitting transaction to [join sortition pool "+
+			"with stake [100]]; estimated gas: [21000]",
+	)
+
+	if status := poolsMonitor.Status()[0]; status.LastPoolTransaction != nil {
+		t.Fatal("expected no pool transaction outcome")
+	}
+}
+
+func TestMonitor_DryRun_RestoreRewardsEligibility(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+	localChain.JoinSortitionPool()
+
+	// Operator is ineligible for rewards and eligibility can
+	// be restored at this point
+	localChain.SetRewardIneligibility(big.NewInt(1))
+	localChain.SetCurrentTimestamp(big.NewInt(2))
+
+	logger := &warningsRecordingLogger{}
+
+	_, err := MonitorPool(
+		ctx,
+		logger,
+		localChain,
+		time.Hour,
+		UnconditionalJoinPolicy,
+		WithDryRun(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isEligibleForRewards, err := localChain.IsEligibleForRewards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isEligibleForRewards {
+		t.Fatal("expected the operator not to be restored for rewards")
+	}
+
+	logger.assertWarning(
+		t,
+		"dry run; not submitting transaction to [restore reward eligibility]",
+	)
+}
+
+// gasEstimatingChain is a local chain estimating gas of transactions joining
+// the pool and updating the operator in it.
+type gasEstimatingChain struct {
+	*local.Chain
+}
+
+func (gec *gasEstimatingChain) JoinSortitionPoolGasEstimate() (uint64, error) {
+	return 21000, nil
+}
+
+func (gec *gasEstimatingChain) UpdateOperatorStatusGasEstimate() (uint64, error) {
+	return 42000, nil
+}
+
+// warningsRecordingLogger is a logger recording logged warnings.
+type warningsRecordingLogger struct {
+	testutils.MockLogger
+
+	mutex    sync.Mutex
+	warnings []string
+}
+
+func (wrl *warningsRecordingLogger) Warnf(format string, args ...interface{}) {
+	wrl.mutex.Lock()
+	defer wrl.mutex.Unlock()
+
+	wrl.warnings = append(wrl.warnings, fmt.Sprintf(format, args...))
+}
+
+func (wrl *warningsRecordingLogger) assertWarning(t *testing.T, expected string) {
+	wrl.mutex.Lock()
+	defer wrl.mutex.Unlock()
+
+	for _, warning := range wrl.warnings {
+		if warning == expected {
+			return
+		}
+	}
+
+	t.Errorf(
+		"expected warning not logged\nexpected: [%v]\nlogged:   %v",
+		expected,
+		wrl.warnings,
+	)
+}
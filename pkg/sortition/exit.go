@@ -0,0 +1,318 @@
+package sortition
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+// DefaultExitCheckTick is the period of status checks while the operator
+// exit is in progress.
+const DefaultExitCheckTick = time.Minute
+
+// DeauthorizationChain handle for checking and executing on-chain steps of
+// the staking provider's authorization decrease. The client approves the
+// authorization decrease during the operator exit only for chains
+// implementing it.
+type DeauthorizationChain interface {
+	// PendingAuthorizationDecrease returns the amount of the authorization
+	// decrease requested for the staking provider, zero if none is pending.
+	PendingAuthorizationDecrease(stakingProvider chain.Address) (*big.Int, error)
+
+	// RemainingAuthorizationDecreaseDelay returns the time that has to pass
+	// before the pending authorization decrease of the staking provider can
+	// be approved. The boolean flag is false if the operator has not updated
+	// its status in the sortition pool since the decrease was requested, in
+	// which case the delay has not started yet.
+	RemainingAuthorizationDecreaseDelay(
+		stakingProvider chain.Address,
+	) (time.Duration, bool, error)
+
+	// ApproveAuthorizationDecrease executes a transaction approving the
+	// pending authorization decrease of the staking provider.
+	ApproveAuthorizationDecrease(stakingProvider chain.Address) error
+}
+
+// ProtocolTracker reports whether protocols of an application, e.g. DKG or
+// signing, are being executed by the client.
+type ProtocolTracker interface {
+	IsExecuting() bool
+}
+
+// ExitStep is a step of the operator exit from the sortition pool.
+type ExitStep string
+
+const (
+	// ExitStepWaitForProtocols means the client waits for the protocols it
+	// executes to complete before leaving the pool.
+	ExitStepWaitForProtocols ExitStep = "wait for in-flight protocols"
+	// ExitStepRequestAuthorizationDecrease means the authorizer has to
+	// request the decrease of the staking provider's authorization for the
+	// application. It requires the authorizer's action.
+	ExitStepRequestAuthorizationDecrease ExitStep = "request authorization decrease"
+	// ExitStepUpdateOperatorStatus means the client updates the operator's
+	// status in the pool so that the authorization decrease delay starts.
+	ExitStepUpdateOperatorStatus ExitStep = "update operator status"
+	// ExitStepWaitForDecreaseDelay means the authorization decrease cannot
+	// be approved until the decrease delay passes.
+	ExitStepWaitForDecreaseDelay ExitStep = "wait for authorization decrease delay"
+	// ExitStepApproveAuthorizationDecrease means the client approves the
+	// authorization decrease.
+	ExitStepApproveAuthorizationDecrease ExitStep = "approve authorization decrease"
+	// ExitStepCompleted means the operator is not in the pool and no
+	// authorization decrease is pending.
+	ExitStepCompleted ExitStep = "completed"
+)
+
+// ApplicationExitStatus describes the progress of the operator exit from the
+// sortition pool of a single application.
+type ApplicationExitStatus struct {
+	// Application is the name of the application.
+	Application string
+	// Step is the step of the exit the operator is at, empty if it has not
+	// been determined by a status check yet.
+	Step ExitStep
+	// IsExecutingProtocols is true if the client executes protocols of the
+	// application.
+	IsExecutingProtocols bool
+	// IsInPool is true if the operator is in the sortition pool.
+	IsInPool bool
+	// PendingAuthorizationDecrease is the amount of the pending authorization
+	// decrease, nil if the chain does not support checking it.
+	PendingAuthorizationDecrease *big.Int
+	// RemainingDecreaseDelay is the time remaining until the authorization
+	// decrease can be approved. It is determined only for the
+	// ExitStepWaitForDecreaseDelay step.
+	RemainingDecreaseDelay time.Duration
+	// Err is the error the last exit check failed with, nil if it succeeded.
+	Err error
+}
+
+// ExitStatus describes the progress of the operator exit from sortition
+// pools of all registered applications.
+type ExitStatus struct {
+	// IsRequested is true if the operator exit was requested.
+	IsRequested bool
+	// RequestedAt is the time the exit was requested at.
+	RequestedAt time.Time
+	// IsCompleted is true if the operator exited pools of all applications.
+	IsCompleted bool
+	// Applications are the exit statuses of registered applications, in the
+	// order of registration.
+	Applications []*ApplicationExitStatus
+}
+
+// exitPolicy is a TransactionPolicy holding off joining the sortition pool
+// once the operator exit is requested. Updates of the operator in the pool
+// are permitted as they are needed to leave it.
+type exitPolicy struct {
+	isRequested *atomic.Bool
+}
+
+func (ep *exitPolicy) Permits(
+	action OperatorAction,
+	status *OperatorStatus,
+) error {
+	if action == ActionJoinPool && ep.isRequested.Load() {
+		return fmt.Errorf("operator exit requested")
+	}
+
+	return nil
+}
+
+// TrackProtocols sets the tracker of protocols executed for the application
+// with the given name. Before leaving the pool, the operator exit waits for
+// the tracked protocols to complete.
+func (pm *PoolsMonitor) TrackProtocols(
+	application string,
+	tracker ProtocolTracker,
+) error {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	for _, app := range pm.applications {
+		if app.name == application {
+			app.protocols = tracker
+			return nil
+		}
+	}
+
+	return fmt.Errorf("application [%s] is not registered", application)
+}
+
+// RequestExit starts the operator exit from sortition pools of all
+// registered applications. From now on, the client does not join the pools
+// and applications are expected not to accept new work. Once in-flight
+// protocols complete, status checks are repeated every DefaultExitCheckTick
+// and the client takes steps of the exit it is able to take on its own until
+// the exit is completed. Requesting the exit again has no effect.
+//
+// The exit request is kept in memory only and is lost when the client
+// restarts. The exit state is not persisted because all steps already taken
+// are recorded on-chain: the pending authorization decrease and the
+// operator status in the pool. After a restart, the client joins the pools
+// again as long as the operator is eligible, so the exit has to be requested
+// again; it then resumes from the step determined by the on-chain state.
+func (pm *PoolsMonitor) RequestExit() {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if !pm.exitRequested.CompareAndSwap(false, true) {
+		return
+	}
+
+	pm.exitRequestedAt = time.Now()
+	for _, app := range pm.applications {
+		app.logger.Warn("operator exit requested; leaving the sortition pool")
+		app.nextCheck = time.Now()
+	}
+
+	pm.notify()
+}
+
+// IsExitRequested returns true if the operator exit was requested.
+// Applications should not accept new work once it returns true.
+func (pm *PoolsMonitor) IsExitRequested() bool {
+	return pm.exitRequested.Load()
+}
+
+// ExitStatus returns the progress of the operator exit.
+func (pm *PoolsMonitor) ExitStatus() *ExitStatus {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	status := &ExitStatus{
+		IsRequested:  pm.exitRequested.Load(),
+		RequestedAt:  pm.exitRequestedAt,
+		IsCompleted:  pm.exitRequested.Load(),
+		Applications: make([]*ApplicationExitStatus, len(pm.applications)),
+	}
+
+	for i, app := range pm.applications {
+		appStatus := &ApplicationExitStatus{Application: app.name}
+		if app.exitStatus != nil {
+			*appStatus = *app.exitStatus
+			appStatus.Application = app.name
+		}
+
+		if appStatus.Step != ExitStepCompleted {
+			status.IsCompleted = false
+		}
+
+		status.Applications[i] = appStatus
+	}
+
+	return status
+}
+
+// checkExit determines the step of the operator exit the operator with the
+// given status is at and takes it if the client can do it on its own.
+// Nothing is submitted while protocols are executed.
+func checkExit(
+	logger log.StandardLogger,
+	chain Chain,
+	status *OperatorStatus,
+	protocols ProtocolTracker,
+	transactions *transactionGuard,
+) (*ApplicationExitStatus, error) {
+	exitStatus := &ApplicationExitStatus{
+		IsExecutingProtocols: protocols != nil && protocols.IsExecuting(),
+		IsInPool:             status.IsInPool,
+	}
+
+	if !status.IsRegistered {
+		exitStatus.Step = ExitStepCompleted
+		return exitStatus, nil
+	}
+
+	if exitStatus.IsExecutingProtocols {
+		logger.Info("operator exit waits for in-flight protocols to complete")
+		exitStatus.Step = ExitStepWaitForProtocols
+		return exitStatus, nil
+	}
+
+	deauthorizationChain, ok := chain.(DeauthorizationChain)
+	if !ok {
+		if status.IsInPool {
+			exitStatus.Step = ExitStepRequestAuthorizationDecrease
+		} else {
+			exitStatus.Step = ExitStepCompleted
+		}
+		return exitStatus, nil
+	}
+
+	pendingDecrease, err := deauthorizationChain.PendingAuthorizationDecrease(
+		status.StakingProvider,
+	)
+	if err != nil {
+		return exitStatus, fmt.Errorf(
+			"could not get pending authorization decrease: [%w]",
+			err,
+		)
+	}
+	exitStatus.PendingAuthorizationDecrease = pendingDecrease
+
+	if pendingDecrease.Sign() == 0 {
+		if status.IsInPool {
+			logger.Warn(
+				"operator exit waits for the authorizer to request " +
+					"the authorization decrease",
+			)
+			exitStatus.Step = ExitStepRequestAuthorizationDecrease
+		} else {
+			exitStatus.Step = ExitStepCompleted
+		}
+		return exitStatus, nil
+	}
+
+	remainingDelay, isDelayStarted, err := deauthorizationChain.
+		RemainingAuthorizationDecreaseDelay(status.StakingProvider)
+	if err != nil {
+		return exitStatus, fmt.Errorf(
+			"could not get remaining authorization decrease delay: [%w]",
+			err,
+		)
+	}
+
+	if !isDelayStarted {
+		// The operator status is updated by the status check.
+		exitStatus.Step = ExitStepUpdateOperatorStatus
+		return exitStatus, nil
+	}
+
+	if remainingDelay > 0 {
+		logger.Infof(
+			"operator exit waits [%v] for the authorization decrease delay",
+			remainingDelay,
+		)
+		exitStatus.Step = ExitStepWaitForDecreaseDelay
+		exitStatus.RemainingDecreaseDelay = remainingDelay
+		return exitStatus, nil
+	}
+
+	exitStatus.Step = ExitStepApproveAuthorizationDecrease
+
+	logger.Info("approving authorization decrease")
+	err = transactions.submit(
+		logger,
+		fmt.Sprintf("approve authorization decrease of [%v]", pendingDecrease),
+		func() error {
+			return deauthorizationChain.ApproveAuthorizationDecrease(
+				status.StakingProvider,
+			)
+		},
+	)
+	if err != nil {
+		return exitStatus, fmt.Errorf(
+			"could not approve authorization decrease: [%w]",
+			err,
+		)
+	}
+
+	return exitStatus, nil
+}
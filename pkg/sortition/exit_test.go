@@ -0,0 +1,271 @@
+package sortition
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestCheckExit(t *testing.T) {
+	var tests = map[string]struct {
+		isRegistered           bool
+		isInPool               bool
+		isExecutingProtocols   bool
+		deauthorization        *deauthorizingChain
+		expectedStep           ExitStep
+		expectedRemainingDelay time.Duration
+		expectedApproved       bool
+		expectedError          string
+	}{
+		"operator not registered": {
+			expectedStep: ExitStepCompleted,
+		},
+		"protocols executed": {
+			isRegistered:         true,
+			isInPool:             true,
+			isExecutingProtocols: true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(100),
+				isDelayStarted:  true,
+			},
+			expectedStep: ExitStepWaitForProtocols,
+		},
+		"deauthorization not supported, operator in pool": {
+			isRegistered: true,
+			isInPool:     true,
+			expectedStep: ExitStepRequestAuthorizationDecrease,
+		},
+		"deauthorization not supported, operator not in pool": {
+			isRegistered: true,
+			expectedStep: ExitStepCompleted,
+		},
+		"authorization decrease not requested": {
+			isRegistered: true,
+			isInPool:     true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(0),
+			},
+			expectedStep: ExitStepRequestAuthorizationDecrease,
+		},
+		"authorization decrease delay not started": {
+			isRegistered: true,
+			isInPool:     true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(100),
+			},
+			expectedStep: ExitStepUpdateOperatorStatus,
+		},
+		"authorization decrease delay not passed": {
+			isRegistered: true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(100),
+				isDelayStarted:  true,
+				remainingDelay:  time.Hour,
+			},
+			expectedStep:           ExitStepWaitForDecreaseDelay,
+			expectedRemainingDelay: time.Hour,
+		},
+		"authorization decrease delay passed": {
+			isRegistered: true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(100),
+				isDelayStarted:  true,
+			},
+			expectedStep:     ExitStepApproveAuthorizationDecrease,
+			expectedApproved: true,
+		},
+		"approval failed": {
+			isRegistered: true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(100),
+				isDelayStarted:  true,
+				approveError:    fmt.Errorf("execution reverted"),
+			},
+			expectedStep:  ExitStepApproveAuthorizationDecrease,
+			expectedError: "could not approve authorization decrease: [execution reverted]",
+		},
+		"operator exited": {
+			isRegistered: true,
+			deauthorization: &deauthorizingChain{
+				pendingDecrease: big.NewInt(0),
+			},
+			expectedStep: ExitStepCompleted,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var exitChain Chain = local.Connect(testOperatorAddress)
+			if test.deauthorization != nil {
+				test.deauthorization.Chain = exitChain.(*local.Chain)
+				exitChain = test.deauthorization
+			}
+
+			status := &OperatorStatus{
+				IsRegistered:    test.isRegistered,
+				StakingProvider: testStakingProviderAddress,
+				IsInPool:        test.isInPool,
+			}
+
+			exitStatus, err := checkExit(
+				&testutils.MockLogger{},
+				exitChain,
+				status,
+				&protocolTracker{test.isExecutingProtocols},
+				newTransactionGuard(DefaultTransactionCooldown, false),
+			)
+			assertErrorContains(t, test.expectedError, err)
+
+			testutils.AssertStringsEqual(
+				t,
+				"step",
+				string(test.expectedStep),
+				string(exitStatus.Step),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"remaining delay",
+				int(test.expectedRemainingDelay),
+				int(exitStatus.RemainingDecreaseDelay),
+			)
+
+			if test.deauthorization != nil {
+				testutils.AssertBoolsEqual(
+					t,
+					"approved",
+					test.expectedApproved,
+					test.deauthorization.approved,
+				)
+			}
+		})
+	}
+}
+
+func TestPoolsMonitor_Exit(t *testing.T) {
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+
+	poolsMonitor := NewPoolsMonitor(time.Hour)
+
+	exitStatus := poolsMonitor.ExitStatus()
+	testutils.AssertBoolsEqual(t, "requested", false, exitStatus.IsRequested)
+	testutils.AssertBoolsEqual(t, "completed", false, exitStatus.IsCompleted)
+
+	poolsMonitor.RequestExit()
+
+	_, err := poolsMonitor.Register(
+		"tbtc",
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = poolsMonitor.TrackProtocols("tbtc", &protocolTracker{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isOperatorInPool, err := localChain.IsOperatorInPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isOperatorInPool {
+		t.Fatal("expected the operator not to join the pool")
+	}
+
+	status := poolsMonitor.Status()[0].OperatorStatus
+	testutils.AssertStringsEqual(
+		t,
+		"transaction policy hold",
+		"operator exit requested",
+		status.TransactionPolicyHold,
+	)
+
+	exitStatus = poolsMonitor.ExitStatus()
+	testutils.AssertBoolsEqual(t, "requested", true, exitStatus.IsRequested)
+	testutils.AssertBoolsEqual(t, "completed", true, exitStatus.IsCompleted)
+	testutils.AssertStringsEqual(
+		t,
+		"application",
+		"tbtc",
+		exitStatus.Applications[0].Application,
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"step",
+		string(ExitStepCompleted),
+		string(exitStatus.Applications[0].Step),
+	)
+
+	// The exit is requested only once.
+	requestedAt := exitStatus.RequestedAt
+	poolsMonitor.RequestExit()
+	testutils.AssertBoolsEqual(
+		t,
+		"requested at",
+		true,
+		poolsMonitor.ExitStatus().RequestedAt.Equal(requestedAt),
+	)
+}
+
+func TestPoolsMonitor_TrackProtocols_NotRegistered(t *testing.T) {
+	poolsMonitor := NewPoolsMonitor(time.Hour)
+
+	assertErrorContains(
+		t,
+		"application [tbtc] is not registered",
+		poolsMonitor.TrackProtocols("tbtc", &protocolTracker{}),
+	)
+}
+
+// deauthorizingChain is a local chain supporting the authorization decrease
+// steps of the operator exit.
+type deauthorizingChain struct {
+	*local.Chain
+
+	pendingDecrease *big.Int
+	remainingDelay  time.Duration
+	isDelayStarted  bool
+	approveError    error
+	approved        bool
+}
+
+func (dc *deauthorizingChain) PendingAuthorizationDecrease(
+	stakingProvider chain.Address,
+) (*big.Int, error) {
+	return dc.pendingDecrease, nil
+}
+
+func (dc *deauthorizingChain) RemainingAuthorizationDecreaseDelay(
+	stakingProvider chain.Address,
+) (time.Duration, bool, error) {
+	return dc.remainingDelay, dc.isDelayStarted, nil
+}
+
+func (dc *deauthorizingChain) ApproveAuthorizationDecrease(
+	stakingProvider chain.Address,
+) error {
+	if dc.approveError != nil {
+		return dc.approveError
+	}
+
+	dc.approved = true
+	return nil
+}
+
+type protocolTracker struct {
+	isExecuting bool
+}
+
+func (pt *protocolTracker) IsExecuting() bool {
+	return pt.isExecuting
+}
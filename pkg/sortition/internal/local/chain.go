@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
 )
 
 var errOperatorUnknown = fmt.Errorf("operator not registered for the staking provider")
@@ -13,6 +15,7 @@ var errAuthorizationBelowMinimum = fmt.Errorf("authorization below the minimum")
 var errOperatorAlreadyRegisteredInPool = fmt.Errorf("operator is already registered in the pool")
 var errOperatorAlreadyEligibleForRewards = fmt.Errorf("operator already eligible")
 var errOperatorStillIneligibleForRewards = fmt.Errorf("operator still ineligible")
+var errOperatorAlreadySet = fmt.Errorf("operator already set for the staking provider")
 
 type Chain struct {
 	operatorAddress chain.Address
@@ -32,20 +35,38 @@ type Chain struct {
 	ineligibleForRewardsUntil      map[chain.Address]*big.Int
 	ineligibleForRewardsUntilMutex sync.RWMutex
 
+	// Reasons of the rewards ineligibility, by the number of the block the
+	// operator was marked as ineligible in.
+	rewardsIneligibilityReasons      map[uint64]string
+	rewardsIneligibilityReasonsMutex sync.RWMutex
+
+	// Staking providers with a stake delegation, as set in the Token Staking
+	// contract.
+	stakeDelegations      map[chain.Address]bool
+	stakeDelegationsMutex sync.RWMutex
+
+	minimumAuthorization *big.Int
+
 	isChaosnetActive bool
 	isBetaOperator   bool
 
 	isPoolLocked     bool
 	currentTimestamp *big.Int
+
+	operatorStatusChangedHandlers      []func(event string, blockNumber uint64)
+	operatorStatusChangedHandlersMutex sync.Mutex
 }
 
 func Connect(operatorAddress chain.Address) *Chain {
 	return &Chain{
-		operatorAddress:           operatorAddress,
-		operatorToStakingProvider: make(map[chain.Address]chain.Address),
-		sortitionPool:             make(map[chain.Address]*big.Int),
-		eligibleStake:             make(map[chain.Address]*big.Int),
-		ineligibleForRewardsUntil: make(map[chain.Address]*big.Int),
+		operatorAddress:             operatorAddress,
+		operatorToStakingProvider:   make(map[chain.Address]chain.Address),
+		sortitionPool:               make(map[chain.Address]*big.Int),
+		eligibleStake:               make(map[chain.Address]*big.Int),
+		ineligibleForRewardsUntil:   make(map[chain.Address]*big.Int),
+		rewardsIneligibilityReasons: make(map[uint64]string),
+		stakeDelegations:            make(map[chain.Address]bool),
+		minimumAuthorization:        big.NewInt(0),
 	}
 }
 
@@ -54,7 +75,20 @@ func (c *Chain) RegisterOperator(stakingProvider chain.Address, operator chain.A
 	c.operatorToStakingProviderMutex.Lock()
 	defer c.operatorToStakingProviderMutex.Unlock()
 
-	c.operatorToStakingProvider[c.operatorAddress] = stakingProvider
+	c.operatorToStakingProvider[operator] = stakingProvider
+}
+
+// This is a test util function to setup the chain
+func (c *Chain) SetStakeDelegation(stakingProvider chain.Address) {
+	c.stakeDelegationsMutex.Lock()
+	defer c.stakeDelegationsMutex.Unlock()
+
+	c.stakeDelegations[stakingProvider] = true
+}
+
+// This is a test util function to setup the chain
+func (c *Chain) SetMinimumAuthorization(minimumAuthorization *big.Int) {
+	c.minimumAuthorization = minimumAuthorization
 }
 
 // This is a test util function to setup the chain
@@ -120,6 +154,18 @@ func (c *Chain) IsOperatorUpToDate() (bool, error) {
 	}
 }
 
+func (c *Chain) OperatorWeight() (*big.Int, error) {
+	c.sortitionPoolMutex.RLock()
+	defer c.sortitionPoolMutex.RUnlock()
+
+	weight, isInPool := c.sortitionPool[c.operatorAddress]
+	if !isInPool {
+		return big.NewInt(0), nil
+	}
+
+	return new(big.Int).Set(weight), nil
+}
+
 func (c *Chain) JoinSortitionPool() error {
 	c.operatorToStakingProviderMutex.Lock()
 	defer c.operatorToStakingProviderMutex.Unlock()
@@ -207,6 +253,25 @@ func (c *Chain) RestoreRewardEligibility() error {
 	return nil
 }
 
+func (c *Chain) RewardEligibilityRestorableAt() (time.Time, error) {
+	c.ineligibleForRewardsUntilMutex.RLock()
+	defer c.ineligibleForRewardsUntilMutex.RUnlock()
+
+	ineligibleUntil, isIneligible := c.ineligibleForRewardsUntil[c.operatorAddress]
+	if !isIneligible {
+		return time.Unix(0, 0), nil
+	}
+
+	return time.Unix(ineligibleUntil.Int64(), 0), nil
+}
+
+func (c *Chain) RewardsIneligibilityReason(blockNumber uint64) (string, error) {
+	c.rewardsIneligibilityReasonsMutex.RLock()
+	defer c.rewardsIneligibilityReasonsMutex.RUnlock()
+
+	return c.rewardsIneligibilityReasons[blockNumber], nil
+}
+
 func (c *Chain) IsChaosnetActive() (bool, error) {
 	return c.isChaosnetActive, nil
 }
@@ -221,6 +286,72 @@ func (c *Chain) GetOperatorID(
 	panic("unsupported")
 }
 
+func (c *Chain) OnOperatorStatusChanged(
+	handler func(event string, blockNumber uint64),
+) subscription.EventSubscription {
+	c.operatorStatusChangedHandlersMutex.Lock()
+	defer c.operatorStatusChangedHandlersMutex.Unlock()
+
+	c.operatorStatusChangedHandlers = append(
+		c.operatorStatusChangedHandlers,
+		handler,
+	)
+
+	return subscription.NewEventSubscription(func() {})
+}
+
+// This is a test util function to emit an event changing the operator status
+func (c *Chain) EmitOperatorStatusChanged(event string, blockNumber uint64) {
+	c.operatorStatusChangedHandlersMutex.Lock()
+	defer c.operatorStatusChangedHandlersMutex.Unlock()
+
+	for _, handler := range c.operatorStatusChangedHandlers {
+		handler(event, blockNumber)
+	}
+}
+
+func (c *Chain) HasStakeDelegation(stakingProvider chain.Address) (bool, error) {
+	c.stakeDelegationsMutex.RLock()
+	defer c.stakeDelegationsMutex.RUnlock()
+
+	return c.stakeDelegations[stakingProvider], nil
+}
+
+func (c *Chain) MinimumAuthorization() (*big.Int, error) {
+	return c.minimumAuthorization, nil
+}
+
+func (c *Chain) StakingProviderToOperator(
+	stakingProvider chain.Address,
+) (chain.Address, bool, error) {
+	c.operatorToStakingProviderMutex.RLock()
+	defer c.operatorToStakingProviderMutex.RUnlock()
+
+	for operator, registeredStakingProvider := range c.operatorToStakingProvider {
+		if registeredStakingProvider == stakingProvider {
+			return operator, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func (c *Chain) SelfRegisterOperator() error {
+	c.operatorToStakingProviderMutex.Lock()
+	defer c.operatorToStakingProviderMutex.Unlock()
+
+	// The operator registers itself as its own staking provider.
+	for _, stakingProvider := range c.operatorToStakingProvider {
+		if stakingProvider == c.operatorAddress {
+			return errOperatorAlreadySet
+		}
+	}
+
+	c.operatorToStakingProvider[c.operatorAddress] = c.operatorAddress
+
+	return nil
+}
+
 func (c *Chain) SetCurrentTimestamp(currentTimestamp *big.Int) {
 	c.currentTimestamp = currentTimestamp
 }
@@ -232,6 +363,18 @@ func (c *Chain) SetRewardIneligibility(until *big.Int) {
 	c.ineligibleForRewardsUntil[c.operatorAddress] = until
 }
 
+// This is a test util function to setup the chain
+func (c *Chain) SetRewardsIneligibilityReason(blockNumber uint64, reason string) {
+	c.rewardsIneligibilityReasonsMutex.Lock()
+	defer c.rewardsIneligibilityReasonsMutex.Unlock()
+
+	c.rewardsIneligibilityReasons[blockNumber] = reason
+}
+
+func (c *Chain) SetPoolLocked(isPoolLocked bool) {
+	c.isPoolLocked = isPoolLocked
+}
+
 func (c *Chain) SetChaosnetStatus(isChaosnetActive bool) {
 	c.isChaosnetActive = isChaosnetActive
 }
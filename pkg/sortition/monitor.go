@@ -0,0 +1,83 @@
+package sortition
+
+import (
+	"sync"
+	"time"
+)
+
+// Health describes the health of the sortition pool monitoring.
+type Health struct {
+	// Healthy is false if the operator status could not be checked for
+	// the number of consecutive times reaching the unhealthy threshold or
+	// if the last check failed due to a permanent condition.
+	Healthy bool
+	// ConsecutiveFailures is the number of status checks failed since
+	// the last successful one.
+	ConsecutiveFailures int
+	// LastError is the error of the last status check, nil if it succeeded.
+	LastError error
+	// PermanentError is true if the last status check failed due to
+	// a condition that requires the operator's action, e.g. the operator
+	// not registered for the staking provider.
+	PermanentError bool
+	// LastSuccessfulCheck is the time of the last successful status check,
+	// zero if none succeeded yet.
+	LastSuccessfulCheck time.Time
+}
+
+// Monitor reports the health of the sortition pool monitoring started with
+// MonitorPool.
+type Monitor struct {
+	mutex sync.Mutex
+
+	unhealthyFailuresThreshold int
+
+	consecutiveFailures int
+	lastError           error
+	permanentError      bool
+	lastSuccessfulCheck time.Time
+}
+
+func newMonitor(unhealthyFailuresThreshold int) *Monitor {
+	return &Monitor{
+		unhealthyFailuresThreshold: unhealthyFailuresThreshold,
+	}
+}
+
+func (m *Monitor) recordSuccess() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.consecutiveFailures = 0
+	m.lastError = nil
+	m.permanentError = false
+	m.lastSuccessfulCheck = time.Now()
+}
+
+// recordFailure records the failed status check and returns the number of
+// consecutive failures.
+func (m *Monitor) recordFailure(err error, permanent bool) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.consecutiveFailures++
+	m.lastError = err
+	m.permanentError = permanent
+
+	return m.consecutiveFailures
+}
+
+// Health returns the current health of the sortition pool monitoring.
+func (m *Monitor) Health() *Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return &Health{
+		Healthy: !m.permanentError &&
+			m.consecutiveFailures < m.unhealthyFailuresThreshold,
+		ConsecutiveFailures: m.consecutiveFailures,
+		LastError:           m.lastError,
+		PermanentError:      m.permanentError,
+		LastSuccessfulCheck: m.lastSuccessfulCheck,
+	}
+}
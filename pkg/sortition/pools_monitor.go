@@ -0,0 +1,430 @@
+package sortition
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-core/pkg/subscription"
+)
+
+// PoolsMonitor monitors the status of the operator in sortition pools of
+// multiple applications, e.g. the random beacon and tBTC, in one loop.
+// Status checks of all applications are executed one after another so that
+// the chain is never accessed by more than one check at the same time.
+// Transactions submitted by a check are not submitted again while they may
+// still be pending. The status of each application is reported separately.
+type PoolsMonitor struct {
+	tick   time.Duration
+	config *monitorConfig
+
+	mutex        sync.Mutex
+	applications []*monitoredApplication
+	// wake is signalled when a status check of an application becomes due
+	// before the time the loop waits for.
+	wake chan struct{}
+
+	// checkMutex ensures status checks are not executed concurrently.
+	checkMutex sync.Mutex
+
+	// exitRequested is set once the operator exit is requested. It is kept
+	// in memory only; see RequestExit.
+	exitRequested atomic.Bool
+	// exitRequestedAt is the time the operator exit was requested at.
+	// Guarded by the mutex.
+	exitRequestedAt time.Time
+}
+
+// monitoredApplication is a sortition pool of an application registered in
+// the pools monitor.
+type monitoredApplication struct {
+	name         string
+	logger       log.StandardLogger
+	chain        Chain
+	policy       JoinPolicy
+	monitor      *Monitor
+	transactions *transactionGuard
+	subscription subscription.EventSubscription
+
+	// nextCheck is the time the next status check is due at. Guarded by
+	// the pools monitor mutex.
+	nextCheck time.Time
+	// statusChanged is set if a status change event was observed since the
+	// last check started. Guarded by the pools monitor mutex.
+	statusChanged bool
+	// lastEventBlocks holds the number of the block of the last observed
+	// event of the given name as events may be delivered more than once.
+	// Guarded by the pools monitor mutex.
+	lastEventBlocks map[string]uint64
+	// operatorStatus is the operator status observed by the last check that
+	// could read it, nil if none did. Guarded by the pools monitor mutex.
+	operatorStatus *OperatorStatus
+	// lastPoolTransaction is the outcome of the last transaction joining the
+	// pool or updating the operator in it, nil if none was submitted.
+	// Guarded by the pools monitor mutex.
+	lastPoolTransaction *TransactionOutcome
+	// alertedRestorableAt is the rewards eligibility restoration time of the
+	// ineligibility period an alert was raised for, zero if the operator is
+	// eligible for rewards. Accessed only by status checks.
+	alertedRestorableAt time.Time
+	// protocols tracks protocols executed for the application, nil if not
+	// tracked. Guarded by the pools monitor mutex.
+	protocols ProtocolTracker
+	// exitTransactions guards transactions submitted by the operator exit.
+	// They are not forgotten when the operator gets up to date in the pool.
+	exitTransactions *transactionGuard
+	// exitStatus is the exit status determined by the last check since the
+	// operator exit was requested, nil if none. Guarded by the pools monitor
+	// mutex.
+	exitStatus *ApplicationExitStatus
+}
+
+// ApplicationStatus describes the monitoring of the sortition pool of
+// a single application.
+type ApplicationStatus struct {
+	// Application is the name of the application.
+	Application string
+	// Health is the health of the application's pool monitoring.
+	Health *Health
+	// NextCheck is the time the next status check is due at.
+	NextCheck time.Time
+	// OperatorStatus is the operator status observed by the last check that
+	// could read it, nil if none did. Transactions submitted by the check
+	// are not reflected in it.
+	OperatorStatus *OperatorStatus
+	// LastPoolTransaction is the outcome of the last transaction joining
+	// the pool or updating the operator in it, nil if none was submitted.
+	LastPoolTransaction *TransactionOutcome
+}
+
+// NewPoolsMonitor creates a new pools monitor checking the operator status
+// in the registered pools with the given tick. The monitor does not check
+// anything until it is started.
+func NewPoolsMonitor(tick time.Duration, options ...MonitorOption) *PoolsMonitor {
+	config := &monitorConfig{
+		initialBackoff:             DefaultStatusCheckInitialBackoff,
+		maxBackoff:                 tick,
+		unhealthyFailuresThreshold: DefaultUnhealthyFailuresThreshold,
+		transactionCooldown:        DefaultTransactionCooldown,
+		exitCheckTick:              DefaultExitCheckTick,
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	pm := &PoolsMonitor{
+		tick:         tick,
+		config:       config,
+		applications: make([]*monitoredApplication, 0),
+		wake:         make(chan struct{}, 1),
+	}
+
+	config.transactionPolicies = append(
+		config.transactionPolicies,
+		&exitPolicy{&pm.exitRequested},
+	)
+
+	return pm
+}
+
+// Register adds the sortition pool of the application with the given name to
+// the monitored pools and checks the operator status in the pool right away.
+// The operator must be registered for the staking provider in the
+// application. The returned monitor reports the health of the application's
+// pool monitoring.
+func (pm *PoolsMonitor) Register(
+	application string,
+	logger log.StandardLogger,
+	chain Chain,
+	policy JoinPolicy,
+) (*Monitor, error) {
+	_, isRegistered, err := chain.OperatorToStakingProvider()
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve staking provider: [%w]", err)
+	}
+
+	if !isRegistered {
+		if pm.config.registrationBootstrap == nil {
+			return nil, errOperatorUnknown
+		}
+
+		if _, ok := chain.(RegistrationChain); !ok {
+			return nil, fmt.Errorf(
+				"registration bootstrap not supported by the chain: [%w]",
+				errOperatorUnknown,
+			)
+		}
+
+		logger.Warnf(
+			"operator is not registered for the staking provider; " +
+				"monitoring the registration in the bootstrap mode",
+		)
+	}
+
+	app := &monitoredApplication{
+		name:    application,
+		logger:  logger,
+		chain:   chain,
+		policy:  policy,
+		monitor: newMonitor(pm.config.unhealthyFailuresThreshold),
+		transactions: newTransactionGuard(
+			pm.config.transactionCooldown,
+			pm.config.dryRun,
+		),
+		exitTransactions: newTransactionGuard(
+			pm.config.transactionCooldown,
+			pm.config.dryRun,
+		),
+		lastEventBlocks: make(map[string]uint64),
+	}
+
+	app.subscription = chain.OnOperatorStatusChanged(
+		func(event string, blockNumber uint64) {
+			pm.onStatusChanged(app, event, blockNumber)
+		},
+	)
+
+	pm.check(app)
+
+	pm.mutex.Lock()
+	pm.applications = append(pm.applications, app)
+	pm.mutex.Unlock()
+
+	pm.notify()
+
+	return app.monitor, nil
+}
+
+// onStatusChanged makes the status check of the application due immediately.
+func (pm *PoolsMonitor) onStatusChanged(
+	app *monitoredApplication,
+	event string,
+	blockNumber uint64,
+) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if lastBlock, ok := app.lastEventBlocks[event]; ok &&
+		blockNumber <= lastBlock {
+		return
+	}
+	app.lastEventBlocks[event] = blockNumber
+
+	app.logger.Infof(
+		"observed [%s] event at block [%d]; "+
+			"checking sortition pool operator status",
+		event,
+		blockNumber,
+	)
+
+	app.statusChanged = true
+	app.nextCheck = time.Now()
+
+	pm.notify()
+}
+
+// notify wakes up the monitoring loop so that it re-evaluates the time of
+// the next check.
+func (pm *PoolsMonitor) notify() {
+	select {
+	case pm.wake <- struct{}{}:
+	default:
+	}
+}
+
+// check checks the operator status in the application's pool and schedules
+// the next check. A check that failed due to a transient error is retried
+// with a backoff; other checks are repeated after the tick unless a status
+// change event is observed in the meantime.
+func (pm *PoolsMonitor) check(app *monitoredApplication) {
+	pm.checkMutex.Lock()
+	defer pm.checkMutex.Unlock()
+
+	pm.mutex.Lock()
+	app.statusChanged = false
+	pm.mutex.Unlock()
+
+	next := pm.tick
+
+	status, err := checkOperatorStatus(
+		app.logger,
+		app.chain,
+		app.policy,
+		app.transactions,
+		pm.config,
+	)
+	if err == nil {
+		app.monitor.recordSuccess()
+	} else {
+		permanent := isPermanentError(err)
+		failures := app.monitor.recordFailure(err, permanent)
+
+		if permanent {
+			app.logger.Errorf(
+				"could not check operator sortition pool status; "+
+					"the check will not be retried before the next "+
+					"periodic check: [%v]",
+				err,
+			)
+		} else {
+			next = pm.config.backoff(failures)
+			app.logger.Errorf(
+				"could not check operator sortition pool status; "+
+					"retrying in [%v]: [%v]",
+				next,
+				err,
+			)
+		}
+	}
+
+	if status != nil {
+		pm.alertRewardsIneligibility(app, status)
+	}
+
+	var exitStatus *ApplicationExitStatus
+	if pm.exitRequested.Load() && status != nil {
+		pm.mutex.Lock()
+		protocols := app.protocols
+		pm.mutex.Unlock()
+
+		exitStatus, err = checkExit(
+			app.logger,
+			app.chain,
+			status,
+			protocols,
+			app.exitTransactions,
+		)
+		if err != nil {
+			app.logger.Errorf("could not check operator exit: [%v]", err)
+			exitStatus.Err = err
+		}
+
+		if exitStatus.Step != ExitStepCompleted &&
+			next > pm.config.exitCheckTick {
+			next = pm.config.exitCheckTick
+		}
+	}
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if status != nil {
+		app.operatorStatus = status
+	}
+	if exitStatus != nil {
+		app.exitStatus = exitStatus
+	}
+	app.lastPoolTransaction = app.transactions.lastPoolTransaction
+
+	if app.statusChanged {
+		// The status may have changed during the check.
+		app.nextCheck = time.Now()
+	} else {
+		app.nextCheck = time.Now().Add(next)
+	}
+
+	// Retry the transaction held off by a transaction policy as soon as
+	// the hold ends.
+	if status != nil &&
+		!status.TransactionPolicyHoldUntil.IsZero() &&
+		status.TransactionPolicyHoldUntil.Before(app.nextCheck) {
+		app.nextCheck = status.TransactionPolicyHoldUntil
+	}
+}
+
+// Start starts the monitoring loop. The loop stops and unsubscribes from
+// status change events once the context is done.
+func (pm *PoolsMonitor) Start(ctx context.Context) {
+	go func() {
+		defer func() {
+			pm.mutex.Lock()
+			applications := pm.applications
+			pm.mutex.Unlock()
+
+			for _, app := range applications {
+				app.subscription.Unsubscribe()
+			}
+		}()
+
+		for {
+			for _, app := range pm.dueApplications() {
+				if ctx.Err() != nil {
+					return
+				}
+
+				pm.check(app)
+			}
+
+			timer := time.NewTimer(pm.untilNextCheck())
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			case <-pm.wake:
+				timer.Stop()
+			}
+		}
+	}()
+}
+
+// dueApplications returns applications the status check of which is due.
+func (pm *PoolsMonitor) dueApplications() []*monitoredApplication {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	now := time.Now()
+
+	due := make([]*monitoredApplication, 0)
+	for _, app := range pm.applications {
+		if !app.nextCheck.After(now) {
+			due = append(due, app)
+		}
+	}
+
+	return due
+}
+
+// untilNextCheck returns the time remaining until the earliest due check.
+func (pm *PoolsMonitor) untilNextCheck() time.Duration {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	wait := pm.tick
+	for _, app := range pm.applications {
+		if untilCheck := time.Until(app.nextCheck); untilCheck < wait {
+			wait = untilCheck
+		}
+	}
+
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// Status returns the status of the monitoring of all registered pools, in
+// the order of registration.
+func (pm *PoolsMonitor) Status() []*ApplicationStatus {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	status := make([]*ApplicationStatus, len(pm.applications))
+	for i, app := range pm.applications {
+		status[i] = &ApplicationStatus{
+			Application:         app.name,
+			Health:              app.monitor.Health(),
+			NextCheck:           app.nextCheck,
+			OperatorStatus:      app.operatorStatus,
+			LastPoolTransaction: app.lastPoolTransaction,
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,227 @@
+package sortition
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestPoolsMonitor_MultipleApplications(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var running, maxRunning int32
+
+	poolsMonitor := NewPoolsMonitor(5 * time.Millisecond)
+
+	chains := make([]*local.Chain, 0)
+	for _, application := range []string{"beacon", "tbtc"} {
+		localChain := local.Connect(testOperatorAddress)
+		localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+		localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+
+		_, err := poolsMonitor.Register(
+			application,
+			&testutils.MockLogger{},
+			&concurrencyTrackingChain{
+				Chain:      localChain,
+				running:    &running,
+				maxRunning: &maxRunning,
+			},
+			UnconditionalJoinPolicy,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chains = append(chains, localChain)
+	}
+
+	poolsMonitor.Start(ctx)
+
+	// Let's give some time for the monitoring loop to run a few checks...
+	time.Sleep(100 * time.Millisecond)
+
+	for i, localChain := range chains {
+		isOperatorInPool, err := localChain.IsOperatorInPool()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isOperatorInPool {
+			t.Errorf("expected the operator to join pool [%d]", i)
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"maximum number of concurrent checks",
+		1,
+		int(atomic.LoadInt32(&maxRunning)),
+	)
+
+	status := poolsMonitor.Status()
+	testutils.AssertIntsEqual(t, "number of applications", 2, len(status))
+	for i, application := range []string{"beacon", "tbtc"} {
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("application [%d]", i),
+			application,
+			status[i].Application,
+		)
+		testutils.AssertBoolsEqual(
+			t,
+			fmt.Sprintf("application [%d] healthy", i),
+			true,
+			status[i].Health.Healthy,
+		)
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("application [%d] next action", i),
+			string(ActionNone),
+			string(status[i].OperatorStatus.NextAction),
+		)
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("application [%d] last pool transaction", i),
+			string(ActionJoinPool),
+			string(status[i].LastPoolTransaction.Action),
+		)
+	}
+}
+
+func TestTransactionGuard(t *testing.T) {
+	guard := newTransactionGuard(time.Hour, false)
+	logger := &testutils.MockLogger{}
+
+	submitted := 0
+	submitFn := func() error {
+		submitted++
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := guard.submit(logger, "join", submitFn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testutils.AssertIntsEqual(t, "submitted transactions", 1, submitted)
+
+	// A different transaction is not affected.
+	if err := guard.submit(logger, "update", submitFn); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "submitted transactions", 2, submitted)
+
+	// A failed transaction can be submitted again right away.
+	failure := fmt.Errorf("nonce too low")
+	err := guard.submit(logger, "restore", func() error { return failure })
+	testutils.AssertErrorsSame(t, failure, err)
+	if err := guard.submit(logger, "restore", submitFn); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "submitted transactions", 3, submitted)
+
+	guard.reset()
+
+	if err := guard.submit(logger, "join", submitFn); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "submitted transactions", 4, submitted)
+}
+
+func TestTransactionGuard_SubmitPoolTransaction(t *testing.T) {
+	guard := newTransactionGuard(time.Hour, false)
+	logger := &testutils.MockLogger{}
+
+	if guard.lastPoolTransaction != nil {
+		t.Fatal("expected no pool transaction outcome")
+	}
+
+	failure := fmt.Errorf("nonce too low")
+	err := guard.submitPoolTransaction(
+		logger,
+		ActionJoinPool,
+		"join",
+		func() error { return failure },
+		nil,
+	)
+	testutils.AssertErrorsSame(t, failure, err)
+	testutils.AssertStringsEqual(
+		t,
+		"action",
+		string(ActionJoinPool),
+		string(guard.lastPoolTransaction.Action),
+	)
+	testutils.AssertErrorsSame(t, failure, guard.lastPoolTransaction.Err)
+
+	err = guard.submitPoolTransaction(
+		logger,
+		ActionJoinPool,
+		"join",
+		func() error { return nil },
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guard.lastPoolTransaction.Err != nil {
+		t.Fatalf("unexpected error: [%v]", guard.lastPoolTransaction.Err)
+	}
+
+	submittedAt := guard.lastPoolTransaction.SubmittedAt
+
+	// The transaction not submitted again due to the cooldown is not
+	// recorded.
+	err = guard.submitPoolTransaction(
+		logger,
+		ActionJoinPool,
+		"join",
+		func() error { return failure },
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guard.lastPoolTransaction.Err != nil ||
+		!guard.lastPoolTransaction.SubmittedAt.Equal(submittedAt) {
+		t.Fatal("expected the outcome of the last submitted transaction")
+	}
+
+	// The outcome is kept after the guard is reset.
+	guard.reset()
+	if guard.lastPoolTransaction == nil {
+		t.Fatal("expected the pool transaction outcome to be kept")
+	}
+}
+
+// concurrencyTrackingChain is a local chain tracking the maximum number of
+// status checks executed at the same time across all chains.
+type concurrencyTrackingChain struct {
+	*local.Chain
+
+	running    *int32
+	maxRunning *int32
+}
+
+func (ctc *concurrencyTrackingChain) IsOperatorInPool() (bool, error) {
+	current := atomic.AddInt32(ctc.running, 1)
+	defer atomic.AddInt32(ctc.running, -1)
+
+	for {
+		observed := atomic.LoadInt32(ctc.maxRunning)
+		if current <= observed ||
+			atomic.CompareAndSwapInt32(ctc.maxRunning, observed, current) {
+			break
+		}
+	}
+
+	time.Sleep(time.Millisecond)
+
+	return ctc.Chain.IsOperatorInPool()
+}
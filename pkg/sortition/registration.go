@@ -0,0 +1,209 @@
+package sortition
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+// RegistrationChain handle for checking and executing on-chain steps required
+// for the operator to be registered for a staking provider. The registration
+// bootstrap mode is available only for chains implementing it.
+type RegistrationChain interface {
+	// HasStakeDelegation returns true if the staking provider has a stake
+	// delegation, i.e. the stake owner is set in the staking contract.
+	HasStakeDelegation(stakingProvider chain.Address) (bool, error)
+
+	// MinimumAuthorization returns the minimum stake authorization required
+	// by the application.
+	MinimumAuthorization() (*big.Int, error)
+
+	// StakingProviderToOperator returns the operator registered for the
+	// staking provider. If no operator has been registered for the staking
+	// provider, the returned address is empty and the boolean flag is set to
+	// false.
+	StakingProviderToOperator(
+		stakingProvider chain.Address,
+	) (chain.Address, bool, error)
+
+	// SelfRegisterOperator executes a transaction registering the operator
+	// for the staking provider the transaction is submitted by. As the
+	// transaction is submitted with the operator key, the operator is
+	// registered for itself as the staking provider.
+	SelfRegisterOperator() error
+}
+
+// RegistrationStep is an on-chain step required for the operator to be
+// registered for a staking provider.
+type RegistrationStep string
+
+const (
+	// StepDelegateStake means the stake owner has to delegate stake to
+	// the staking provider.
+	StepDelegateStake RegistrationStep = "delegate stake"
+	// StepAuthorizeApplication means the authorizer has to authorize at
+	// least the minimum authorization of the staking provider's stake for
+	// the application.
+	StepAuthorizeApplication RegistrationStep = "authorize application"
+	// StepRegisterOperator means the staking provider has to register
+	// the operator for the application.
+	StepRegisterOperator RegistrationStep = "register operator"
+)
+
+// errOperatorAlreadySet is returned if the staking provider has already
+// registered another operator for the application.
+var errOperatorAlreadySet = fmt.Errorf("another operator already registered for the staking provider")
+
+// registrationBootstrap is the configuration of the registration bootstrap
+// mode.
+type registrationBootstrap struct {
+	operator        chain.Address
+	stakingProvider chain.Address
+	autoRegister    bool
+}
+
+// WithRegistrationBootstrap enables the registration bootstrap mode for the
+// given operator and the staking provider it is going to be registered for.
+// Pools of operators not registered for a staking provider are monitored
+// instead of failing the registration in the monitor. Until the operator gets
+// registered, each status check reports which on-chain registration step is
+// missing. If autoRegister is true and the operator is its own staking
+// provider, the operator is registered by the monitoring once the stake is
+// delegated and authorized.
+func WithRegistrationBootstrap(
+	operator chain.Address,
+	stakingProvider chain.Address,
+	autoRegister bool,
+) MonitorOption {
+	return func(config *monitorConfig) {
+		config.registrationBootstrap = &registrationBootstrap{
+			operator:        operator,
+			stakingProvider: stakingProvider,
+			autoRegister:    autoRegister,
+		}
+	}
+}
+
+// MissingRegistrationStep returns the first on-chain step missing for the
+// operator to be registered for the staking provider in the application.
+// An empty step is returned if no step is missing, e.g. the operator has just
+// been registered. The chain must implement RegistrationChain.
+func MissingRegistrationStep(
+	sortitionChain Chain,
+	operator chain.Address,
+	stakingProvider chain.Address,
+) (RegistrationStep, error) {
+	registrationChain, ok := sortitionChain.(RegistrationChain)
+	if !ok {
+		return "", fmt.Errorf("chain does not support registration checks")
+	}
+
+	hasStakeDelegation, err := registrationChain.HasStakeDelegation(
+		stakingProvider,
+	)
+	if err != nil {
+		return "", fmt.Errorf("could not check stake delegation: [%w]", err)
+	}
+
+	if !hasStakeDelegation {
+		return StepDelegateStake, nil
+	}
+
+	eligibleStake, err := sortitionChain.EligibleStake(stakingProvider)
+	if err != nil {
+		return "", fmt.Errorf("could not get eligible stake: [%w]", err)
+	}
+
+	minimumAuthorization, err := registrationChain.MinimumAuthorization()
+	if err != nil {
+		return "", fmt.Errorf("could not get minimum authorization: [%w]", err)
+	}
+
+	if eligibleStake.Cmp(minimumAuthorization) < 0 {
+		return StepAuthorizeApplication, nil
+	}
+
+	registeredOperator, isRegistered, err :=
+		registrationChain.StakingProviderToOperator(stakingProvider)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve operator: [%w]", err)
+	}
+
+	if !isRegistered {
+		return StepRegisterOperator, nil
+	}
+
+	if registeredOperator != operator {
+		return "", fmt.Errorf(
+			"%w: [%s]",
+			errOperatorAlreadySet,
+			registeredOperator,
+		)
+	}
+
+	return "", nil
+}
+
+// run reports the on-chain step missing for the operator to be registered
+// and registers the operator if the registration is the missing step and
+// the client is permitted to execute it. Returns the missing step.
+func (rb *registrationBootstrap) run(
+	logger log.StandardLogger,
+	chain Chain,
+	transactions *transactionGuard,
+) (RegistrationStep, error) {
+	step, err := MissingRegistrationStep(
+		chain,
+		rb.operator,
+		rb.stakingProvider,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	switch step {
+	case StepDelegateStake:
+		logger.Warnf(
+			"operator is not registered; staking provider [%s] has no "+
+				"stake delegation; the stake owner has to stake first",
+			rb.stakingProvider,
+		)
+	case StepAuthorizeApplication:
+		logger.Warnf(
+			"operator is not registered; stake of staking provider [%s] "+
+				"is not authorized for the application; the authorizer "+
+				"has to authorize at least the minimum authorization",
+			rb.stakingProvider,
+		)
+	case StepRegisterOperator:
+		if !rb.autoRegister || rb.operator != rb.stakingProvider {
+			logger.Warnf(
+				"operator is not registered; staking provider [%s] has "+
+					"to register operator [%s] for the application",
+				rb.stakingProvider,
+				rb.operator,
+			)
+			break
+		}
+
+		logger.Infof(
+			"operator is not registered; registering operator [%s] "+
+				"for itself as the staking provider",
+			rb.operator,
+		)
+
+		registrationChain := chain.(RegistrationChain)
+		if err := transactions.submit(
+			logger,
+			"register operator",
+			registrationChain.SelfRegisterOperator,
+		); err != nil {
+			return step, fmt.Errorf("could not register operator: [%w]", err)
+		}
+	}
+
+	return step, nil
+}
@@ -0,0 +1,231 @@
+package sortition
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestMissingRegistrationStep(t *testing.T) {
+	const otherOperatorAddress = "0x6299496199d99941193Fdd2d717ef585F431eA05"
+
+	var tests = map[string]struct {
+		setup         func(localChain *local.Chain)
+		expectedStep  RegistrationStep
+		expectedError error
+	}{
+		"no stake delegation": {
+			setup:        func(localChain *local.Chain) {},
+			expectedStep: StepDelegateStake,
+		},
+		"stake not authorized": {
+			setup: func(localChain *local.Chain) {
+				localChain.SetStakeDelegation(testStakingProviderAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(99))
+			},
+			expectedStep: StepAuthorizeApplication,
+		},
+		"operator not registered": {
+			setup: func(localChain *local.Chain) {
+				localChain.SetStakeDelegation(testStakingProviderAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+			},
+			expectedStep: StepRegisterOperator,
+		},
+		"another operator registered": {
+			setup: func(localChain *local.Chain) {
+				localChain.SetStakeDelegation(testStakingProviderAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.RegisterOperator(testStakingProviderAddress, otherOperatorAddress)
+			},
+			expectedError: errOperatorAlreadySet,
+		},
+		"operator registered": {
+			setup: func(localChain *local.Chain) {
+				localChain.SetStakeDelegation(testStakingProviderAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+			},
+			expectedStep: "",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := local.Connect(testOperatorAddress)
+			localChain.SetMinimumAuthorization(big.NewInt(100))
+			test.setup(localChain)
+
+			step, err := MissingRegistrationStep(
+				localChain,
+				testOperatorAddress,
+				testStakingProviderAddress,
+			)
+
+			testutils.AssertAnyErrorInChainMatchesTarget(t, test.expectedError, err)
+			testutils.AssertStringsEqual(
+				t,
+				"missing registration step",
+				string(test.expectedStep),
+				string(step),
+			)
+		})
+	}
+}
+
+func TestPoolsMonitor_RegistrationBootstrap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testStakingProviderAddress)
+	localChain.SetMinimumAuthorization(big.NewInt(100))
+
+	// The periodic check never happens during the test.
+	poolsMonitor := NewPoolsMonitor(
+		time.Hour,
+		WithRegistrationBootstrap(
+			testStakingProviderAddress,
+			testStakingProviderAddress,
+			true,
+		),
+	)
+
+	monitor, err := poolsMonitor.Register(
+		"tbtc",
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolsMonitor.Start(ctx)
+
+	assertStep := func(expected RegistrationStep) {
+		status := poolsMonitor.Status()[0].OperatorStatus
+		testutils.AssertStringsEqual(
+			t,
+			"missing registration step",
+			string(expected),
+			string(status.MissingRegistrationStep),
+		)
+	}
+
+	assertStep(StepDelegateStake)
+	testutils.AssertBoolsEqual(
+		t,
+		"permanent error",
+		true,
+		monitor.Health().PermanentError,
+	)
+
+	localChain.SetStakeDelegation(testStakingProviderAddress)
+	localChain.EmitOperatorStatusChanged("AuthorizationIncreased", 10)
+	// Let's give some time for the monitoring loop to react...
+	time.Sleep(50 * time.Millisecond)
+	assertStep(StepAuthorizeApplication)
+
+	// The operator is its own staking provider so it gets registered by
+	// the monitoring once the stake is authorized.
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+	localChain.EmitOperatorStatusChanged("AuthorizationIncreased", 11)
+	time.Sleep(50 * time.Millisecond)
+	assertStep(StepRegisterOperator)
+
+	stakingProvider, isRegistered, err := localChain.OperatorToStakingProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBoolsEqual(t, "operator registered", true, isRegistered)
+	testutils.AssertStringsEqual(
+		t,
+		"staking provider",
+		testStakingProviderAddress,
+		stakingProvider.String(),
+	)
+
+	localChain.EmitOperatorStatusChanged("OperatorRegistered", 12)
+	time.Sleep(50 * time.Millisecond)
+
+	isOperatorInPool, err := localChain.IsOperatorInPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBoolsEqual(t, "operator in pool", true, isOperatorInPool)
+	testutils.AssertBoolsEqual(t, "healthy", true, monitor.Health().Healthy)
+}
+
+func TestPoolsMonitor_RegistrationBootstrap_NotPermitted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.SetStakeDelegation(testStakingProviderAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+
+	// The operator is not its own staking provider so it cannot register
+	// itself.
+	poolsMonitor := NewPoolsMonitor(
+		time.Hour,
+		WithRegistrationBootstrap(
+			testOperatorAddress,
+			testStakingProviderAddress,
+			true,
+		),
+	)
+
+	_, err := poolsMonitor.Register(
+		"tbtc",
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolsMonitor.Start(ctx)
+
+	testutils.AssertStringsEqual(
+		t,
+		"missing registration step",
+		string(StepRegisterOperator),
+		string(poolsMonitor.Status()[0].OperatorStatus.MissingRegistrationStep),
+	)
+
+	_, isRegistered, err := localChain.OperatorToStakingProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBoolsEqual(t, "operator registered", false, isRegistered)
+}
+
+func TestPoolsMonitor_RegistrationBootstrap_NotSupported(t *testing.T) {
+	poolsMonitor := NewPoolsMonitor(
+		time.Hour,
+		WithRegistrationBootstrap(
+			testOperatorAddress,
+			testStakingProviderAddress,
+			false,
+		),
+	)
+
+	_, err := poolsMonitor.Register(
+		"tbtc",
+		&testutils.MockLogger{},
+		&sortitionOnlyChain{local.Connect(testOperatorAddress)},
+		UnconditionalJoinPolicy,
+	)
+	testutils.AssertAnyErrorInChainMatchesTarget(t, errOperatorUnknown, err)
+}
+
+// sortitionOnlyChain exposes only the sortition Chain functions of the
+// wrapped chain.
+type sortitionOnlyChain struct {
+	Chain
+}
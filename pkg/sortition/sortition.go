@@ -2,6 +2,7 @@ package sortition
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,157 +11,395 @@ import (
 
 const (
 	DefaultStatusCheckTick = 6 * time.Hour
+	// DefaultStatusCheckInitialBackoff is the delay before the first retry of
+	// a status check that failed due to a transient error. Delays of
+	// subsequent retries are doubled, up to the status check tick.
+	DefaultStatusCheckInitialBackoff = 30 * time.Second
+	// DefaultUnhealthyFailuresThreshold is the number of consecutive failed
+	// status checks after which the monitoring is reported as unhealthy.
+	DefaultUnhealthyFailuresThreshold = 3
+	// DefaultTransactionCooldown is the period during which a transaction
+	// submitted by the monitoring is considered possibly pending and is not
+	// submitted again.
+	DefaultTransactionCooldown = 10 * time.Minute
 )
 
 var errOperatorUnknown = fmt.Errorf("operator not registered for the staking provider, check Threshold dashboard")
 
+// isPermanentError returns true if the error is caused by a condition that
+// is not going to change without the operator's action, so retrying the
+// status check sooner than on the next tick is pointless. Other errors, e.g.
+// failed chain RPC calls, are considered transient.
+func isPermanentError(err error) bool {
+	return errors.Is(err, errOperatorUnknown) ||
+		errors.Is(err, errOperatorAlreadySet)
+}
+
+// MonitorOption allows to customize the sortition pool monitoring.
+type MonitorOption func(config *monitorConfig)
+
+type monitorConfig struct {
+	initialBackoff             time.Duration
+	maxBackoff                 time.Duration
+	unhealthyFailuresThreshold int
+	transactionCooldown        time.Duration
+	// registrationBootstrap is nil if the registration bootstrap mode is
+	// disabled.
+	registrationBootstrap *registrationBootstrap
+	// alertHooks are notified when the operator becomes ineligible for
+	// rewards.
+	alertHooks []AlertHook
+	// transactionPolicies are evaluated before joining the pool or updating
+	// the operator in it.
+	transactionPolicies []TransactionPolicy
+	// dryRun is true if transactions are only logged instead of being
+	// submitted.
+	dryRun bool
+	// exitCheckTick is the period of status checks while the operator exit
+	// is in progress.
+	exitCheckTick time.Duration
+}
+
+// WithStatusCheckBackoff sets the delay before the first retry of a status
+// check that failed due to a transient error and the maximum delay the
+// subsequent, doubled, delays are capped at. By default, the delay starts at
+// DefaultStatusCheckInitialBackoff and is capped at the status check tick.
+func WithStatusCheckBackoff(initial time.Duration, max time.Duration) MonitorOption {
+	return func(config *monitorConfig) {
+		config.initialBackoff = initial
+		config.maxBackoff = max
+	}
+}
+
+// WithUnhealthyFailuresThreshold sets the number of consecutive failed
+// status checks after which the monitoring is reported as unhealthy.
+func WithUnhealthyFailuresThreshold(threshold int) MonitorOption {
+	return func(config *monitorConfig) {
+		config.unhealthyFailuresThreshold = threshold
+	}
+}
+
+// WithTransactionCooldown sets the period during which a transaction
+// submitted by the monitoring is not submitted again, e.g. because a status
+// change event was observed before the transaction got mined.
+func WithTransactionCooldown(cooldown time.Duration) MonitorOption {
+	return func(config *monitorConfig) {
+		config.transactionCooldown = cooldown
+	}
+}
+
+// backoff returns the delay before retrying the status check after the
+// given number of consecutive failures.
+func (mc *monitorConfig) backoff(failures int) time.Duration {
+	backoff := mc.initialBackoff
+	for i := 1; i < failures && backoff < mc.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > mc.maxBackoff {
+		return mc.maxBackoff
+	}
+
+	return backoff
+}
+
 // MonitorPool periodically checks the status of the operator in the sortition
 // pool. If the operator is supposed to be in the sortition pool but is not
 // there yet, the function attempts to add the operator to the pool. If the
 // operator is already in the pool and its status is no longer up to date, the
-// function attempts to update the operator's status in the pool.
+// function attempts to update the operator's status in the pool. Apart from
+// the periodic checks, the status is checked as soon as an on-chain event
+// that may change it is observed. A check that failed due to a transient
+// error is retried with an exponential backoff. The returned monitor
+// reports the health of the monitoring. To monitor pools of multiple
+// applications in one loop, use PoolsMonitor instead.
 func MonitorPool(
 	ctx context.Context,
 	logger log.StandardLogger,
 	chain Chain,
 	tick time.Duration,
 	policy JoinPolicy,
-) error {
-	_, isRegistered, err := chain.OperatorToStakingProvider()
-	if err != nil {
-		return fmt.Errorf("could not resolve staking provider: [%w]", err)
-	}
+	options ...MonitorOption,
+) (*Monitor, error) {
+	poolsMonitor := NewPoolsMonitor(tick, options...)
 
-	if !isRegistered {
-		return errOperatorUnknown
+	monitor, err := poolsMonitor.Register("", logger, chain, policy)
+	if err != nil {
+		return nil, err
 	}
 
-	err = checkOperatorStatus(logger, chain, policy)
-	if err != nil {
-		logger.Errorf("could not check operator sortition pool status: [%v]", err)
-	}
-
-	ticker := time.NewTicker(tick)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			case <-ticker.C:
-				err = checkOperatorStatus(logger, chain, policy)
-				if err != nil {
-					logger.Errorf("could not check operator sortition pool status: [%v]", err)
-					continue
-				}
-			}
-		}
-	}()
+	poolsMonitor.Start(ctx)
 
-	return nil
+	return monitor, nil
 }
 
+// checkOperatorStatus checks the status of the operator in the sortition pool
+// and submits transactions needed to bring it up to date, if permitted by
+// the transaction policies of the monitor configuration. Returns the status
+// the check observed.
 func checkOperatorStatus(
 	logger log.StandardLogger,
 	chain Chain,
 	policy JoinPolicy,
-) error {
+	transactions *transactionGuard,
+	config *monitorConfig,
+) (*OperatorStatus, error) {
 	logger.Info("checking sortition pool operator status")
 
-	isOperatorInPool, err := chain.IsOperatorInPool()
+	status, err := GetOperatorStatus(chain, policy)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	isOperatorUpToDate, err := chain.IsOperatorUpToDate()
-	if err != nil {
-		return err
+	if !status.IsRegistered {
+		if config.registrationBootstrap == nil {
+			return status, errOperatorUnknown
+		}
+
+		step, err := config.registrationBootstrap.run(logger, chain, transactions)
+		if err != nil {
+			return status, err
+		}
+
+		status.MissingRegistrationStep = step
+
+		if step == "" {
+			// The operator got registered after its status was read.
+			return status, errOperatorUnknown
+		}
+
+		return status, fmt.Errorf(
+			"%w; missing registration step: [%s]",
+			errOperatorUnknown,
+			step,
+		)
 	}
 
-	if isOperatorInPool {
+	if status.IsInPool {
 		logger.Info("operator is in the sortition pool")
 
-		err = checkRewardsEligibility(logger, chain)
+		err = restoreRewardsEligibility(logger, chain, status, transactions)
 		if err != nil {
-			logger.Errorf("could not check for rewards eligibility: [%v]", err)
+			logger.Errorf("could not restore rewards eligibility: [%v]", err)
 		}
 	} else {
 		logger.Info("operator is not in the sortition pool")
 	}
 
-	if isOperatorUpToDate {
-		if isOperatorInPool {
+	if status.IsUpToDate {
+		// Transactions submitted earlier, if any, got mined.
+		transactions.reset()
+
+		if status.IsInPool {
 			logger.Info("sortition pool operator weight is up to date")
 		} else {
 			logger.Info("please inspect staking providers's authorization for the Random Beacon")
 		}
 
-		return nil
+		return status, nil
 	}
 
-	isLocked, err := chain.IsPoolLocked()
-	if err != nil {
-		return err
-	}
-
-	if isLocked {
+	if status.IsPoolLocked {
 		logger.Info("sortition pool state is locked, waiting with the update")
-		return nil
+		return status, nil
 	}
 
-	if isOperatorInPool {
+	// Transactions are identified by the eligible stake they bring the pool
+	// up to date with so that a transaction is submitted again if the stake
+	// changes while the previous one is pending.
+	if status.IsInPool {
+		if err := checkTransactionPolicies(
+			config.transactionPolicies,
+			ActionUpdateOperatorStatus,
+			status,
+		); err != nil {
+			logger.Warnf(
+				"holding off with updating operator status in the "+
+					"sortition pool due to transaction policy: [%v]",
+				err,
+			)
+			return status, nil
+		}
+
 		logger.Info("updating operator status in the sortition pool")
-		err := chain.UpdateOperatorStatus()
+		err := transactions.submitPoolTransaction(
+			logger,
+			ActionUpdateOperatorStatus,
+			fmt.Sprintf("update operator status with stake [%v]", status.EligibleStake),
+			chain.UpdateOperatorStatus,
+			gasEstimateFn(chain, ActionUpdateOperatorStatus),
+		)
 		if err != nil {
-			logger.Errorf("could not update the sortition pool: [%v]", err)
+			return status, fmt.Errorf("could not update the sortition pool: [%w]", err)
 		}
-	} else {
-		if policy.ShouldJoin() {
-			logger.Info("joining the sortition pool")
-			err := chain.JoinSortitionPool()
-			if err != nil {
-				logger.Errorf("could not join the sortition pool: [%v]", err)
-			}
-		} else {
-			logger.Info("holding off with joining the sortition pool due to joining policy")
+	} else if status.NextAction == ActionJoinPool {
+		if err := checkTransactionPolicies(
+			config.transactionPolicies,
+			ActionJoinPool,
+			status,
+		); err != nil {
+			logger.Warnf(
+				"holding off with joining the sortition pool due to "+
+					"transaction policy: [%v]",
+				err,
+			)
+			return status, nil
+		}
+
+		logger.Info("joining the sortition pool")
+		err := transactions.submitPoolTransaction(
+			logger,
+			ActionJoinPool,
+			fmt.Sprintf("join sortition pool with stake [%v]", status.EligibleStake),
+			chain.JoinSortitionPool,
+			gasEstimateFn(chain, ActionJoinPool),
+		)
+		if err != nil {
+			return status, fmt.Errorf("could not join the sortition pool: [%w]", err)
 		}
+	} else {
+		logger.Info("holding off with joining the sortition pool due to joining policy")
 	}
 
-	return nil
+	return status, nil
 }
 
-func checkRewardsEligibility(logger log.StandardLogger, chain Chain) error {
-	isEligibleForRewards, err := chain.IsEligibleForRewards()
-	if err != nil {
-		return err
-	}
-
-	if isEligibleForRewards {
+func restoreRewardsEligibility(
+	logger log.StandardLogger,
+	chain Chain,
+	status *OperatorStatus,
+	transactions *transactionGuard,
+) error {
+	if status.IsEligibleForRewards {
 		// TODO: Uncomment once the rewards get allocated via the sortition pool.
 		// We do not want to confuse the operators not meeting the requirements
 		// for the interim rewards allocations with false-positive messages
 		// from logs.
 		// logger.Info("operator is eligible for rewards")
-	} else {
-		logger.Info("operator is marked as ineligible for rewards")
+		return nil
+	}
 
-		canRestoreRewardEligibility, err := chain.CanRestoreRewardEligibility()
-		if err != nil {
-			return err
-		}
+	logger.Info("operator is marked as ineligible for rewards")
 
-		if canRestoreRewardEligibility {
-			logger.Info("restoring eligibility for rewards")
+	if !status.CanRestoreRewardEligibility {
+		logger.Info("cannot restore eligibility for rewards yet")
+		return nil
+	}
 
-			err = chain.RestoreRewardEligibility()
-			if err != nil {
-				return err
-			}
-		} else {
-			logger.Info("cannot restore eligibility for rewards yet")
-		}
+	logger.Info("restoring eligibility for rewards")
+
+	return transactions.submit(
+		logger,
+		"restore reward eligibility",
+		chain.RestoreRewardEligibility,
+	)
+}
+
+// TransactionOutcome describes a transaction joining the sortition pool or
+// updating the operator in it, submitted by the monitoring.
+type TransactionOutcome struct {
+	// Action is the action the transaction takes.
+	Action OperatorAction
+	// Transaction describes the transaction.
+	Transaction string
+	// SubmittedAt is the time the transaction was submitted at.
+	SubmittedAt time.Time
+	// Err is the error the submission failed with, nil if the transaction
+	// was submitted successfully.
+	Err error
+}
+
+// transactionGuard prevents submitting a transaction again while the one
+// submitted earlier may still be pending. Transactions are identified by
+// their descriptions. In the dry-run mode, transactions are only logged.
+type transactionGuard struct {
+	cooldown  time.Duration
+	dryRun    bool
+	submitted map[string]time.Time
+
+	// lastPoolTransaction is the outcome of the last transaction joining
+	// the pool or updating the operator in it, nil if none was submitted.
+	lastPoolTransaction *TransactionOutcome
+}
+
+func newTransactionGuard(cooldown time.Duration, dryRun bool) *transactionGuard {
+	return &transactionGuard{
+		cooldown:  cooldown,
+		dryRun:    dryRun,
+		submitted: make(map[string]time.Time),
 	}
+}
+
+// submit submits the transaction with the given submit function unless the
+// same transaction was submitted within the cooldown period.
+func (tg *transactionGuard) submit(
+	logger log.StandardLogger,
+	transaction string,
+	submitFn func() error,
+) error {
+	if tg.dryRun {
+		logDryRunTransaction(logger, transaction, nil)
+		return nil
+	}
+
+	if submittedAt, ok := tg.submitted[transaction]; ok &&
+		time.Since(submittedAt) < tg.cooldown {
+		logger.Infof(
+			"transaction to [%s] submitted at [%v] may still be pending; "+
+				"not submitting it again",
+			transaction,
+			submittedAt.Format(time.RFC3339),
+		)
+		return nil
+	}
+
+	if err := submitFn(); err != nil {
+		return err
+	}
+
+	tg.submitted[transaction] = time.Now()
 
 	return nil
 }
+
+// submitPoolTransaction submits the transaction joining the pool or updating
+// the operator in it, taking the given action, and records its outcome.
+// Transactions not submitted again due to the cooldown are not recorded.
+// In the dry-run mode, the transaction is logged along with its gas estimated
+// with estimateFn, if set.
+func (tg *transactionGuard) submitPoolTransaction(
+	logger log.StandardLogger,
+	action OperatorAction,
+	transaction string,
+	submitFn func() error,
+	estimateFn func() (uint64, error),
+) error {
+	if tg.dryRun {
+		logDryRunTransaction(logger, transaction, estimateFn)
+		return nil
+	}
+
+	isSubmitted := false
+
+	err := tg.submit(logger, transaction, func() error {
+		isSubmitted = true
+		return submitFn()
+	})
+
+	if isSubmitted {
+		tg.lastPoolTransaction = &TransactionOutcome{
+			Action:      action,
+			Transaction: transaction,
+			SubmittedAt: time.Now(),
+			Err:         err,
+		}
+	}
+
+	return err
+}
+
+// reset forgets all submitted transactions. The outcome of the last pool
+// transaction is kept.
+func (tg *transactionGuard) reset() {
+	tg.submitted = make(map[string]time.Time)
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ipfs/go-log"
 
 	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
 )
 
@@ -41,7 +43,7 @@ func TestMonitorPool_NotRegisteredOperator(t *testing.T) {
 
 	localChain := local.Connect(testOperatorAddress)
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -58,7 +60,7 @@ func TestMonitorPool_NoStake(t *testing.T) {
 	localChain := local.Connect(testOperatorAddress)
 	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -87,7 +89,7 @@ func TestMonitor_JoinPool(t *testing.T) {
 	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
 	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -116,7 +118,7 @@ func TestMonitor_JoinPool_PolicyNotSatisfied(t *testing.T) {
 	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
 	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -148,7 +150,7 @@ func TestMonitor_UpdatePool(t *testing.T) {
 
 	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(101))
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -175,7 +177,7 @@ func TestMonitor_JoinPool_WithDelay(t *testing.T) {
 	localChain := local.Connect(testOperatorAddress)
 	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -217,7 +219,7 @@ func TestMonitor_UpdatePool_WithDelay(t *testing.T) {
 	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
 	localChain.JoinSortitionPool()
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -263,7 +265,7 @@ func TestMonitor_CannotRestoreRewardsEligibility_TimeNotPassed(t *testing.T) {
 	localChain.SetRewardIneligibility(big.NewInt(1))
 	localChain.SetCurrentTimestamp(big.NewInt(0))
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -297,7 +299,7 @@ func TestMonitor_CanRestoreRewardsEligibility(t *testing.T) {
 	localChain.SetRewardIneligibility(big.NewInt(1))
 	localChain.SetCurrentTimestamp(big.NewInt(2))
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx, &testutils.MockLogger{}, localChain, statusCheckTick, UnconditionalJoinPolicy)
 	if err != nil {
 		t.Fatal(err)
@@ -326,7 +328,7 @@ func TestMonitor_CanRestoreRewardsEligibility_WithDelay(t *testing.T) {
 	localChain.SetRewardIneligibility(big.NewInt(1))
 	localChain.SetCurrentTimestamp(big.NewInt(0))
 
-	err := MonitorPool(
+	_, err := MonitorPool(
 		ctx,
 		&testutils.MockLogger{},
 		localChain,
@@ -356,3 +358,254 @@ type neverJoinPolicy struct{}
 func (njp *neverJoinPolicy) ShouldJoin() bool {
 	return false
 }
+
+func TestMonitor_UpdatePool_OnStatusChangedEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+	localChain.JoinSortitionPool()
+
+	// The periodic check never happens during the test.
+	_, err := MonitorPool(
+		ctx,
+		&testutils.MockLogger{},
+		localChain,
+		time.Hour,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertUpToDate := func(expected bool) {
+		// Let's give some time for the monitoring loop to react...
+		time.Sleep(50 * time.Millisecond)
+
+		isOperatorUpToDate, err := localChain.IsOperatorUpToDate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testutils.AssertBoolsEqual(
+			t,
+			"operator up to date",
+			expected,
+			isOperatorUpToDate,
+		)
+	}
+
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(101))
+	localChain.EmitOperatorStatusChanged("AuthorizationIncreased", 10)
+	assertUpToDate(true)
+
+	// The same event delivered again does not trigger the check.
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(102))
+	localChain.EmitOperatorStatusChanged("AuthorizationIncreased", 10)
+	assertUpToDate(false)
+
+	localChain.EmitOperatorStatusChanged("AuthorizationIncreased", 11)
+	assertUpToDate(true)
+}
+
+func TestMonitor_TransientFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+
+	failing := &failingChain{
+		Chain:                    localChain,
+		isOperatorInPoolFailures: 2,
+	}
+
+	// The periodic check never happens during the test.
+	monitor, err := MonitorPool(
+		ctx,
+		&testutils.MockLogger{},
+		failing,
+		time.Hour,
+		UnconditionalJoinPolicy,
+		WithStatusCheckBackoff(5*time.Millisecond, time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	health := monitor.Health()
+	testutils.AssertIntsEqual(t, "consecutive failures", 1, health.ConsecutiveFailures)
+	testutils.AssertBoolsEqual(t, "healthy", true, health.Healthy)
+	testutils.AssertBoolsEqual(t, "permanent error", false, health.PermanentError)
+
+	// Let's give some time for the retries...
+	time.Sleep(100 * time.Millisecond)
+
+	isOperatorInPool, err := localChain.IsOperatorInPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isOperatorInPool {
+		t.Fatal("expected the operator to join the pool")
+	}
+
+	health = monitor.Health()
+	testutils.AssertIntsEqual(t, "consecutive failures", 0, health.ConsecutiveFailures)
+	testutils.AssertBoolsEqual(t, "healthy", true, health.Healthy)
+	if health.LastError != nil {
+		t.Errorf("unexpected last error: [%v]", health.LastError)
+	}
+	if health.LastSuccessfulCheck.IsZero() {
+		t.Error("expected the last successful check to be set")
+	}
+}
+
+func TestMonitor_PersistentTransientFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+
+	failing := &failingChain{
+		Chain:                    localChain,
+		isOperatorInPoolFailures: -1,
+	}
+
+	monitor, err := MonitorPool(
+		ctx,
+		&testutils.MockLogger{},
+		failing,
+		time.Hour,
+		UnconditionalJoinPolicy,
+		WithStatusCheckBackoff(time.Millisecond, 2*time.Millisecond),
+		WithUnhealthyFailuresThreshold(3),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Let's give some time for the retries...
+	time.Sleep(100 * time.Millisecond)
+
+	health := monitor.Health()
+	testutils.AssertBoolsEqual(t, "healthy", false, health.Healthy)
+	testutils.AssertBoolsEqual(t, "permanent error", false, health.PermanentError)
+	if health.ConsecutiveFailures < 3 {
+		t.Errorf(
+			"unexpected number of consecutive failures: [%v]",
+			health.ConsecutiveFailures,
+		)
+	}
+	if health.LastError == nil {
+		t.Error("expected the last error to be set")
+	}
+}
+
+func TestMonitor_PermanentFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+
+	failing := &failingChain{
+		Chain: localChain,
+		// The operator is registered when the monitoring starts but not
+		// when the status is checked.
+		registeredChecks: 1,
+	}
+
+	monitor, err := MonitorPool(
+		ctx,
+		&testutils.MockLogger{},
+		failing,
+		time.Hour,
+		UnconditionalJoinPolicy,
+		WithStatusCheckBackoff(time.Millisecond, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Let's give some time for the monitoring loop to retry if it would...
+	time.Sleep(50 * time.Millisecond)
+
+	health := monitor.Health()
+	testutils.AssertBoolsEqual(t, "healthy", false, health.Healthy)
+	testutils.AssertBoolsEqual(t, "permanent error", true, health.PermanentError)
+	testutils.AssertErrorsSame(t, errOperatorUnknown, health.LastError)
+	// The check is not retried until the next tick.
+	testutils.AssertIntsEqual(t, "consecutive failures", 1, health.ConsecutiveFailures)
+}
+
+func TestMonitorConfig_Backoff(t *testing.T) {
+	config := &monitorConfig{
+		initialBackoff: time.Second,
+		maxBackoff:     10 * time.Second,
+	}
+
+	var tests = map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		// No overflow for a long series of failures.
+		100: 10 * time.Second,
+	}
+
+	for failures, expectedBackoff := range tests {
+		t.Run(fmt.Sprintf("%d failures", failures), func(t *testing.T) {
+			testutils.AssertIntsEqual(
+				t,
+				"backoff in milliseconds",
+				int(expectedBackoff.Milliseconds()),
+				int(config.backoff(failures).Milliseconds()),
+			)
+		})
+	}
+}
+
+// failingChain is a local chain failing some of the calls.
+type failingChain struct {
+	*local.Chain
+
+	mutex sync.Mutex
+	// isOperatorInPoolFailures is the number of calls of IsOperatorInPool
+	// that fail; all calls fail if negative.
+	isOperatorInPoolFailures int
+	// registeredChecks is the number of calls of OperatorToStakingProvider
+	// for which the operator is registered; all if zero.
+	registeredChecks  int
+	registrationCalls int
+}
+
+func (fc *failingChain) IsOperatorInPool() (bool, error) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	if fc.isOperatorInPoolFailures != 0 {
+		if fc.isOperatorInPoolFailures > 0 {
+			fc.isOperatorInPoolFailures--
+		}
+		return false, fmt.Errorf("connection refused")
+	}
+
+	return fc.Chain.IsOperatorInPool()
+}
+
+func (fc *failingChain) OperatorToStakingProvider() (chain.Address, bool, error) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	fc.registrationCalls++
+	if fc.registeredChecks > 0 && fc.registrationCalls > fc.registeredChecks {
+		return "", false, nil
+	}
+
+	return fc.Chain.OperatorToStakingProvider()
+}
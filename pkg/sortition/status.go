@@ -0,0 +1,209 @@
+package sortition
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+// OperatorAction is the action that has to be taken next for the operator
+// to be up to date in the sortition pool.
+type OperatorAction string
+
+const (
+	// ActionNone means the operator is in the pool with an up to date weight
+	// and is eligible for rewards.
+	ActionNone OperatorAction = "none"
+	// ActionRegisterOperator means the operator has to be registered for
+	// the staking provider in the application. It requires the staking
+	// provider's action.
+	ActionRegisterOperator OperatorAction = "register operator"
+	// ActionAuthorizeStake means the staking provider's stake has to be
+	// authorized for the application before the operator can join the pool.
+	// It requires the staking provider's action.
+	ActionAuthorizeStake OperatorAction = "authorize stake"
+	// ActionJoinPool means the client joins the sortition pool.
+	ActionJoinPool OperatorAction = "join sortition pool"
+	// ActionWaitForJoinPolicy means the client does not join the sortition
+	// pool until its join policy is fulfilled, e.g. the chaosnet is over.
+	ActionWaitForJoinPolicy OperatorAction = "wait for join policy"
+	// ActionWaitForTransactionPolicy means the client does not join the
+	// sortition pool or update the operator in it until the transaction
+	// policies configured by the operator permit it, e.g. a maintenance
+	// window is over.
+	ActionWaitForTransactionPolicy OperatorAction = "wait for transaction policy"
+	// ActionUpdateOperatorStatus means the client updates the operator's
+	// weight in the sortition pool.
+	ActionUpdateOperatorStatus OperatorAction = "update operator status"
+	// ActionWaitForPoolUnlock means the client does not update the operator
+	// in the sortition pool until the pool gets unlocked.
+	ActionWaitForPoolUnlock OperatorAction = "wait for sortition pool unlock"
+	// ActionRestoreRewardEligibility means the client restores the
+	// operator's eligibility for rewards.
+	ActionRestoreRewardEligibility OperatorAction = "restore reward eligibility"
+	// ActionWaitForRewardEligibility means the operator's eligibility for
+	// rewards cannot be restored yet.
+	ActionWaitForRewardEligibility OperatorAction = "wait for reward eligibility"
+)
+
+// OperatorStatus describes the status of the operator in the sortition pool.
+type OperatorStatus struct {
+	// IsRegistered is true if the operator is registered for a staking
+	// provider in the application. Other fields except NextAction and
+	// MissingRegistrationStep are not set if it is false.
+	IsRegistered bool
+	// StakingProvider is the staking provider the operator is registered for.
+	StakingProvider chain.Address
+	// IsInPool is true if the operator is in the sortition pool.
+	IsInPool bool
+	// IsUpToDate is true if the operator's weight in the sortition pool is
+	// in sync with the staking provider's eligible stake.
+	IsUpToDate bool
+	// IsEligibleForRewards is true if the operator is eligible for rewards.
+	// It is only determined for operators in the sortition pool.
+	IsEligibleForRewards bool
+	// CanRestoreRewardEligibility is true if the operator is ineligible for
+	// rewards and can restore the eligibility right away.
+	CanRestoreRewardEligibility bool
+	// RewardEligibilityRestorableAt is the earliest time at which the
+	// operator's eligibility for rewards can be restored. It is only
+	// determined for operators ineligible for rewards.
+	RewardEligibilityRestorableAt time.Time
+	// IsPoolLocked is true if the sortition pool is locked. It is only
+	// determined for operators that are not up to date.
+	IsPoolLocked bool
+	// Weight is the current weight of the operator in the sortition pool.
+	Weight *big.Int
+	// EligibleStake is the staking provider's eligible stake the operator's
+	// weight should be in sync with.
+	EligibleStake *big.Int
+	// NextAction is the action that has to be taken next.
+	NextAction OperatorAction
+	// MissingRegistrationStep is the on-chain step missing for the operator
+	// to be registered. It is determined only in the registration bootstrap
+	// mode.
+	MissingRegistrationStep RegistrationStep
+	// TransactionPolicyHold describes why a transaction policy configured
+	// by the operator holds off joining the pool or updating the operator in
+	// it. It is determined only by the status check of the pool monitoring.
+	TransactionPolicyHold string
+	// TransactionPolicyHoldUntil is the time the transaction policy hold
+	// ends at, zero if there is no hold or the time is not known.
+	TransactionPolicyHoldUntil time.Time
+}
+
+// GetOperatorStatus reads the status of the operator in the sortition pool
+// from the chain and determines the next action of the pool monitoring,
+// using the given join policy. Nothing is submitted to the chain.
+func GetOperatorStatus(chain Chain, policy JoinPolicy) (*OperatorStatus, error) {
+	stakingProvider, isRegistered, err := chain.OperatorToStakingProvider()
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve staking provider: [%w]", err)
+	}
+
+	if !isRegistered {
+		return &OperatorStatus{NextAction: ActionRegisterOperator}, nil
+	}
+
+	status := &OperatorStatus{
+		IsRegistered:    true,
+		StakingProvider: stakingProvider,
+	}
+
+	status.IsInPool, err = chain.IsOperatorInPool()
+	if err != nil {
+		return nil, fmt.Errorf("could not check if operator is in pool: [%w]", err)
+	}
+
+	status.IsUpToDate, err = chain.IsOperatorUpToDate()
+	if err != nil {
+		return nil, fmt.Errorf("could not check if operator is up to date: [%w]", err)
+	}
+
+	status.Weight, err = chain.OperatorWeight()
+	if err != nil {
+		return nil, fmt.Errorf("could not get operator weight: [%w]", err)
+	}
+
+	status.EligibleStake, err = chain.EligibleStake(stakingProvider)
+	if err != nil {
+		return nil, fmt.Errorf("could not get eligible stake: [%w]", err)
+	}
+
+	if status.IsInPool {
+		status.IsEligibleForRewards, err = chain.IsEligibleForRewards()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not check rewards eligibility: [%w]",
+				err,
+			)
+		}
+
+		if !status.IsEligibleForRewards {
+			status.CanRestoreRewardEligibility, err =
+				chain.CanRestoreRewardEligibility()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"could not check if rewards eligibility can be "+
+						"restored: [%w]",
+					err,
+				)
+			}
+
+			status.RewardEligibilityRestorableAt, err =
+				chain.RewardEligibilityRestorableAt()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"could not get rewards eligibility restoration "+
+						"time: [%w]",
+					err,
+				)
+			}
+		}
+	}
+
+	if !status.IsUpToDate {
+		status.IsPoolLocked, err = chain.IsPoolLocked()
+		if err != nil {
+			return nil, fmt.Errorf("could not check if pool is locked: [%w]", err)
+		}
+	}
+
+	status.NextAction = nextOperatorAction(status, policy)
+
+	return status, nil
+}
+
+// nextOperatorAction determines the action the pool monitoring takes next.
+// The reward eligibility is restored before the pool is updated, the same
+// way the status check does.
+func nextOperatorAction(status *OperatorStatus, policy JoinPolicy) OperatorAction {
+	if status.IsInPool && status.CanRestoreRewardEligibility {
+		return ActionRestoreRewardEligibility
+	}
+
+	if !status.IsUpToDate {
+		switch {
+		case status.IsPoolLocked:
+			return ActionWaitForPoolUnlock
+		case status.IsInPool:
+			return ActionUpdateOperatorStatus
+		case policy.ShouldJoin():
+			return ActionJoinPool
+		default:
+			return ActionWaitForJoinPolicy
+		}
+	}
+
+	if !status.IsInPool {
+		return ActionAuthorizeStake
+	}
+
+	if !status.IsEligibleForRewards {
+		return ActionWaitForRewardEligibility
+	}
+
+	return ActionNone
+}
@@ -0,0 +1,182 @@
+package sortition
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestGetOperatorStatus(t *testing.T) {
+	var tests = map[string]struct {
+		setup                        func(localChain *local.Chain)
+		policy                       JoinPolicy
+		expectedIsRegistered         bool
+		expectedIsInPool             bool
+		expectedIsUpToDate           bool
+		expectedIsEligibleForRewards bool
+		expectedWeight               *big.Int
+		expectedNextAction           OperatorAction
+	}{
+		"not registered operator": {
+			setup:              func(localChain *local.Chain) {},
+			policy:             UnconditionalJoinPolicy,
+			expectedNextAction: ActionRegisterOperator,
+		},
+		"no stake": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+			},
+			policy:               UnconditionalJoinPolicy,
+			expectedIsRegistered: true,
+			expectedIsUpToDate:   true,
+			expectedWeight:       big.NewInt(0),
+			expectedNextAction:   ActionAuthorizeStake,
+		},
+		"not in pool": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+			},
+			policy:               UnconditionalJoinPolicy,
+			expectedIsRegistered: true,
+			expectedWeight:       big.NewInt(0),
+			expectedNextAction:   ActionJoinPool,
+		},
+		"not in pool, policy not satisfied": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+			},
+			policy:               &neverJoinPolicy{},
+			expectedIsRegistered: true,
+			expectedWeight:       big.NewInt(0),
+			expectedNextAction:   ActionWaitForJoinPolicy,
+		},
+		"in pool, up to date": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.JoinSortitionPool()
+			},
+			policy:                       UnconditionalJoinPolicy,
+			expectedIsRegistered:         true,
+			expectedIsInPool:             true,
+			expectedIsUpToDate:           true,
+			expectedIsEligibleForRewards: true,
+			expectedWeight:               big.NewInt(100),
+			expectedNextAction:           ActionNone,
+		},
+		"in pool, not up to date": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.JoinSortitionPool()
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(200))
+			},
+			policy:                       UnconditionalJoinPolicy,
+			expectedIsRegistered:         true,
+			expectedIsInPool:             true,
+			expectedIsEligibleForRewards: true,
+			expectedWeight:               big.NewInt(100),
+			expectedNextAction:           ActionUpdateOperatorStatus,
+		},
+		"in pool, not up to date, pool locked": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.JoinSortitionPool()
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(200))
+				localChain.SetPoolLocked(true)
+			},
+			policy:                       UnconditionalJoinPolicy,
+			expectedIsRegistered:         true,
+			expectedIsInPool:             true,
+			expectedIsEligibleForRewards: true,
+			expectedWeight:               big.NewInt(100),
+			expectedNextAction:           ActionWaitForPoolUnlock,
+		},
+		"in pool, ineligible for rewards, can restore": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.JoinSortitionPool()
+				localChain.SetRewardIneligibility(big.NewInt(1))
+				localChain.SetCurrentTimestamp(big.NewInt(2))
+			},
+			policy:               UnconditionalJoinPolicy,
+			expectedIsRegistered: true,
+			expectedIsInPool:     true,
+			expectedIsUpToDate:   true,
+			expectedWeight:       big.NewInt(100),
+			expectedNextAction:   ActionRestoreRewardEligibility,
+		},
+		"in pool, ineligible for rewards, cannot restore yet": {
+			setup: func(localChain *local.Chain) {
+				localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+				localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+				localChain.JoinSortitionPool()
+				localChain.SetRewardIneligibility(big.NewInt(1))
+				localChain.SetCurrentTimestamp(big.NewInt(0))
+			},
+			policy:               UnconditionalJoinPolicy,
+			expectedIsRegistered: true,
+			expectedIsInPool:     true,
+			expectedIsUpToDate:   true,
+			expectedWeight:       big.NewInt(100),
+			expectedNextAction:   ActionWaitForRewardEligibility,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := local.Connect(testOperatorAddress)
+			test.setup(localChain)
+
+			status, err := GetOperatorStatus(localChain, test.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"registered",
+				test.expectedIsRegistered,
+				status.IsRegistered,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"in pool",
+				test.expectedIsInPool,
+				status.IsInPool,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"up to date",
+				test.expectedIsUpToDate,
+				status.IsUpToDate,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"eligible for rewards",
+				test.expectedIsEligibleForRewards,
+				status.IsEligibleForRewards,
+			)
+			if test.expectedWeight != nil {
+				testutils.AssertBigIntsEqual(
+					t,
+					"weight",
+					test.expectedWeight,
+					status.Weight,
+				)
+			}
+			testutils.AssertStringsEqual(
+				t,
+				"next action",
+				string(test.expectedNextAction),
+				string(status.NextAction),
+			)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package sortition
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// TransactionPolicy determines whether the sortition pool monitoring is
+// permitted to submit the transaction taking the given action. Unlike
+// JoinPolicy, set by the application, transaction policies are configured
+// by the operator and apply to sortition pools of all applications.
+type TransactionPolicy interface {
+	// Permits returns nil if the action is permitted for the operator
+	// status. Otherwise, it returns an error describing the condition that
+	// is not fulfilled.
+	Permits(action OperatorAction, status *OperatorStatus) error
+}
+
+// ExpiringTransactionPolicy is a TransactionPolicy that knows when the action
+// it holds off becomes permitted. The status check of the action is retried
+// at that time instead of waiting for the next periodic check.
+type ExpiringTransactionPolicy interface {
+	TransactionPolicy
+
+	// HoldUntil returns the time the action not permitted by the policy
+	// becomes permitted, zero if not known.
+	HoldUntil(action OperatorAction, status *OperatorStatus) time.Time
+}
+
+// WithTransactionPolicies sets transaction policies evaluated by status
+// checks before joining the sortition pool or updating the operator in it.
+// A transaction is held off until all the policies permit it.
+func WithTransactionPolicies(policies ...TransactionPolicy) MonitorOption {
+	return func(config *monitorConfig) {
+		config.transactionPolicies = append(
+			config.transactionPolicies,
+			policies...,
+		)
+	}
+}
+
+// checkTransactionPolicies returns the error of the first policy that does
+// not permit the action. The hold is recorded in the status, along with the
+// time the hold ends if the policy knows it.
+func checkTransactionPolicies(
+	policies []TransactionPolicy,
+	action OperatorAction,
+	status *OperatorStatus,
+) error {
+	for _, policy := range policies {
+		if err := policy.Permits(action, status); err != nil {
+			if status.NextAction == action {
+				status.NextAction = ActionWaitForTransactionPolicy
+			}
+			status.TransactionPolicyHold = err.Error()
+			if expiring, ok := policy.(ExpiringTransactionPolicy); ok {
+				status.TransactionPolicyHoldUntil = expiring.HoldUntil(
+					action,
+					status,
+				)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MinimumStakePolicy is a TransactionPolicy implementation permitting to join
+// the sortition pool only if the staking provider's eligible stake is at
+// least the given minimum. Updates of the operator already in the pool are
+// not affected.
+type MinimumStakePolicy struct {
+	minimum *big.Int
+}
+
+func NewMinimumStakePolicy(minimum *big.Int) *MinimumStakePolicy {
+	return &MinimumStakePolicy{minimum}
+}
+
+func (msp *MinimumStakePolicy) Permits(
+	action OperatorAction,
+	status *OperatorStatus,
+) error {
+	if action != ActionJoinPool {
+		return nil
+	}
+
+	if status.EligibleStake == nil || status.EligibleStake.Cmp(msp.minimum) < 0 {
+		return fmt.Errorf(
+			"eligible stake [%v] is below the minimum join stake [%v]",
+			status.EligibleStake,
+			msp.minimum,
+		)
+	}
+
+	return nil
+}
+
+// MaintenanceWindow is a recurring window of time, in UTC, repeated every
+// day or every week on the given day. A window ending at or before its start
+// time of day ends on the next day.
+type MaintenanceWindow struct {
+	// weekday is nil for windows repeated every day.
+	weekday *time.Weekday
+	start   time.Duration
+	end     time.Duration
+	text    string
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseMaintenanceWindow parses the maintenance window in the
+// `[Day ]HH:MM-HH:MM` format, e.g. `02:00-04:00` for a daily window or
+// `Sat 22:00-02:00` for a weekly window starting on Saturday and ending on
+// Sunday. Times are in UTC.
+func ParseMaintenanceWindow(text string) (*MaintenanceWindow, error) {
+	window := &MaintenanceWindow{text: text}
+
+	fields := strings.Fields(text)
+	switch len(fields) {
+	case 1:
+	case 2:
+		weekday, ok := weekdays[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("invalid day of week: [%s]", fields[0])
+		}
+		window.weekday = &weekday
+		fields = fields[1:]
+	default:
+		return nil, fmt.Errorf(
+			"invalid maintenance window [%s]; expected format "+
+				"is [Day ]HH:MM-HH:MM",
+			text,
+		)
+	}
+
+	times := strings.Split(fields[0], "-")
+	if len(times) != 2 {
+		return nil, fmt.Errorf(
+			"invalid maintenance window [%s]; expected format "+
+				"is [Day ]HH:MM-HH:MM",
+			text,
+		)
+	}
+
+	var err error
+	window.start, err = parseTimeOfDay(times[0])
+	if err != nil {
+		return nil, err
+	}
+
+	window.end, err = parseTimeOfDay(times[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if window.start == window.end {
+		return nil, fmt.Errorf(
+			"invalid maintenance window [%s]; start and end must differ",
+			text,
+		)
+	}
+
+	return window, nil
+}
+
+func parseTimeOfDay(text string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day [%s]: [%w]", text, err)
+	}
+
+	return time.Duration(parsed.Hour())*time.Hour +
+		time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// Contains returns true if the given time is within the maintenance window.
+func (mw *MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	sinceMidnight := t.Sub(midnight)
+
+	startsOn := func(weekday time.Weekday) bool {
+		return mw.weekday == nil || *mw.weekday == weekday
+	}
+
+	if mw.start < mw.end {
+		return startsOn(t.Weekday()) &&
+			sinceMidnight >= mw.start &&
+			sinceMidnight < mw.end
+	}
+
+	// The window ends on the day after it starts.
+	yesterday := (t.Weekday() + 6) % 7
+	return (startsOn(t.Weekday()) && sinceMidnight >= mw.start) ||
+		(startsOn(yesterday) && sinceMidnight < mw.end)
+}
+
+// endsAt returns the time the maintenance window containing the given time
+// ends at. The given time must be within the maintenance window.
+func (mw *MaintenanceWindow) endsAt(t time.Time) time.Time {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if mw.start > mw.end && t.Sub(midnight) >= mw.start {
+		// The window ends on the next day.
+		return midnight.AddDate(0, 0, 1).Add(mw.end)
+	}
+
+	return midnight.Add(mw.end)
+}
+
+func (mw *MaintenanceWindow) String() string {
+	return mw.text
+}
+
+// MaintenanceWindowPolicy is a TransactionPolicy implementation holding off
+// joining the sortition pool and updating the operator in it during the
+// given maintenance windows. Held off transactions are rechecked as soon as
+// the maintenance window is over.
+type MaintenanceWindowPolicy struct {
+	windows []*MaintenanceWindow
+	now     func() time.Time
+}
+
+func NewMaintenanceWindowPolicy(
+	windows ...*MaintenanceWindow,
+) *MaintenanceWindowPolicy {
+	return &MaintenanceWindowPolicy{
+		windows: windows,
+		now:     time.Now,
+	}
+}
+
+func (mwp *MaintenanceWindowPolicy) Permits(
+	action OperatorAction,
+	status *OperatorStatus,
+) error {
+	if action != ActionJoinPool && action != ActionUpdateOperatorStatus {
+		return nil
+	}
+
+	now := mwp.now()
+	for _, window := range mwp.windows {
+		if window.Contains(now) {
+			return fmt.Errorf(
+				"[%s] is within maintenance window [%s]",
+				now.UTC().Format(time.RFC3339),
+				window,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (mwp *MaintenanceWindowPolicy) HoldUntil(
+	action OperatorAction,
+	status *OperatorStatus,
+) time.Time {
+	if action != ActionJoinPool && action != ActionUpdateOperatorStatus {
+		return time.Time{}
+	}
+
+	now := mwp.now()
+	for _, window := range mwp.windows {
+		if window.Contains(now) {
+			return window.endsAt(now)
+		}
+	}
+
+	return time.Time{}
+}
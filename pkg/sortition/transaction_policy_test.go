@@ -0,0 +1,411 @@
+package sortition
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/sortition/internal/local"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	var tests = map[string]struct {
+		text          string
+		expectedError string
+	}{
+		"daily window": {
+			text: "02:00-04:00",
+		},
+		"weekly window": {
+			text: "Sat 22:00-02:00",
+		},
+		"invalid day of week": {
+			text:          "Someday 02:00-04:00",
+			expectedError: "invalid day of week: [Someday]",
+		},
+		"invalid time of day": {
+			text:          "02:00-25:00",
+			expectedError: "invalid time of day [25:00]",
+		},
+		"missing end": {
+			text:          "02:00",
+			expectedError: "invalid maintenance window [02:00]; expected format is [Day ]HH:MM-HH:MM",
+		},
+		"empty window": {
+			text:          "02:00-02:00",
+			expectedError: "invalid maintenance window [02:00-02:00]; start and end must differ",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			window, err := ParseMaintenanceWindow(test.text)
+			assertErrorContains(t, test.expectedError, err)
+
+			if err == nil {
+				testutils.AssertStringsEqual(t, "window", test.text, window.String())
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_Contains(t *testing.T) {
+	// 2024-06-01 is a Saturday.
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.June, 1, hour, minute, 0, 0, time.UTC)
+	}
+	sunday := func(hour, minute int) time.Time {
+		return saturday(hour, minute).AddDate(0, 0, 1)
+	}
+
+	var tests = map[string]struct {
+		window   string
+		time     time.Time
+		expected bool
+	}{
+		"daily window, before start": {
+			window:   "02:00-04:00",
+			time:     saturday(1, 59),
+			expected: false,
+		},
+		"daily window, at start": {
+			window:   "02:00-04:00",
+			time:     saturday(2, 0),
+			expected: true,
+		},
+		"daily window, at end": {
+			window:   "02:00-04:00",
+			time:     saturday(4, 0),
+			expected: false,
+		},
+		"daily window ending next day, before midnight": {
+			window:   "22:00-02:00",
+			time:     saturday(23, 0),
+			expected: true,
+		},
+		"daily window ending next day, after midnight": {
+			window:   "22:00-02:00",
+			time:     sunday(1, 0),
+			expected: true,
+		},
+		"weekly window, on the day": {
+			window:   "Sat 02:00-04:00",
+			time:     saturday(3, 0),
+			expected: true,
+		},
+		"weekly window, on another day": {
+			window:   "Sat 02:00-04:00",
+			time:     sunday(3, 0),
+			expected: false,
+		},
+		"weekly window ending next day, on the next day": {
+			window:   "Sat 22:00-02:00",
+			time:     sunday(1, 0),
+			expected: true,
+		},
+		"weekly window ending next day, on the day after midnight": {
+			window:   "Sat 22:00-02:00",
+			time:     saturday(1, 0),
+			expected: false,
+		},
+		"time in another zone": {
+			window:   "02:00-04:00",
+			time:     saturday(3, 0).In(time.FixedZone("UTC+5", 5*60*60)),
+			expected: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			window, err := ParseMaintenanceWindow(test.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"contains",
+				test.expected,
+				window.Contains(test.time),
+			)
+		})
+	}
+}
+
+func TestMaintenanceWindow_EndsAt(t *testing.T) {
+	// 2024-06-01 is a Saturday.
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.June, 1, hour, minute, 0, 0, time.UTC)
+	}
+	sunday := func(hour, minute int) time.Time {
+		return saturday(hour, minute).AddDate(0, 0, 1)
+	}
+
+	var tests = map[string]struct {
+		window   string
+		time     time.Time
+		expected time.Time
+	}{
+		"daily window": {
+			window:   "02:00-04:00",
+			time:     saturday(3, 0),
+			expected: saturday(4, 0),
+		},
+		"overnight window, before midnight": {
+			window:   "Sat 22:00-02:00",
+			time:     saturday(23, 0),
+			expected: sunday(2, 0),
+		},
+		"overnight window, after midnight": {
+			window:   "Sat 22:00-02:00",
+			time:     sunday(1, 0),
+			expected: sunday(2, 0),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			window, err := ParseMaintenanceWindow(test.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if end := window.endsAt(test.time); !end.Equal(test.expected) {
+				t.Errorf(
+					"unexpected window end\nexpected: %v\nactual:   %v",
+					test.expected,
+					end,
+				)
+			}
+		})
+	}
+}
+
+func TestMinimumStakePolicy(t *testing.T) {
+	policy := NewMinimumStakePolicy(big.NewInt(100))
+
+	status := &OperatorStatus{EligibleStake: big.NewInt(99)}
+
+	assertErrorContains(
+		t,
+		"eligible stake [99] is below the minimum join stake [100]",
+		policy.Permits(ActionJoinPool, status),
+	)
+	// Updates of the operator in the pool are not affected.
+	assertErrorContains(
+		t,
+		"",
+		policy.Permits(ActionUpdateOperatorStatus, status),
+	)
+
+	status.EligibleStake = big.NewInt(100)
+	assertErrorContains(t, "", policy.Permits(ActionJoinPool, status))
+}
+
+func TestMonitor_JoinPool_MinimumStakePolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(50))
+
+	poolsMonitor := NewPoolsMonitor(
+		time.Hour,
+		WithTransactionPolicies(NewMinimumStakePolicy(big.NewInt(100))),
+	)
+
+	_, err := poolsMonitor.Register(
+		"tbtc",
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolsMonitor.Start(ctx)
+
+	isOperatorInPool, err := localChain.IsOperatorInPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isOperatorInPool {
+		t.Fatal("expected the operator not to join the pool")
+	}
+
+	status := poolsMonitor.Status()[0].OperatorStatus
+	testutils.AssertStringsEqual(
+		t,
+		"next action",
+		string(ActionWaitForTransactionPolicy),
+		string(status.NextAction),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"transaction policy hold",
+		"eligible stake [50] is below the minimum join stake [100]",
+		status.TransactionPolicyHold,
+	)
+
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+	localChain.EmitOperatorStatusChanged("AuthorizationIncreased", 10)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		isOperatorInPool, err := localChain.IsOperatorInPool()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isOperatorInPool {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the operator to join the pool")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMonitor_UpdatePool_MaintenanceWindowPolicy(t *testing.T) {
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+	localChain.JoinSortitionPool()
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(200))
+
+	window, err := ParseMaintenanceWindow("02:00-04:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := NewMaintenanceWindowPolicy(window)
+	policy.now = func() time.Time {
+		return time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC)
+	}
+
+	config := &monitorConfig{
+		transactionPolicies: []TransactionPolicy{policy},
+	}
+	transactions := newTransactionGuard(DefaultTransactionCooldown, false)
+
+	status, err := checkOperatorStatus(
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+		transactions,
+		config,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"next action",
+		string(ActionWaitForTransactionPolicy),
+		string(status.NextAction),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"transaction policy hold",
+		"[2024-06-01T03:00:00Z] is within maintenance window [02:00-04:00]",
+		status.TransactionPolicyHold,
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"transaction policy hold until window end",
+		true,
+		status.TransactionPolicyHoldUntil.Equal(
+			time.Date(2024, time.June, 1, 4, 0, 0, 0, time.UTC),
+		),
+	)
+
+	weight, err := localChain.OperatorWeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBigIntsEqual(t, "weight in maintenance window", big.NewInt(100), weight)
+
+	// The maintenance window is over.
+	policy.now = func() time.Time {
+		return time.Date(2024, time.June, 1, 4, 0, 0, 0, time.UTC)
+	}
+
+	status, err = checkOperatorStatus(
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+		transactions,
+		config,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"next action",
+		string(ActionUpdateOperatorStatus),
+		string(status.NextAction),
+	)
+
+	weight, err = localChain.OperatorWeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBigIntsEqual(t, "weight after maintenance window", big.NewInt(200), weight)
+}
+
+func TestPoolsMonitor_RetryAtTransactionPolicyHoldEnd(t *testing.T) {
+	localChain := local.Connect(testOperatorAddress)
+	localChain.RegisterOperator(testStakingProviderAddress, testOperatorAddress)
+	localChain.SetEligibleStake(testStakingProviderAddress, big.NewInt(100))
+
+	now := time.Now().UTC()
+	window, err := ParseMaintenanceWindow(
+		fmt.Sprintf(
+			"%s-%s",
+			now.Add(-time.Hour).Format("15:04"),
+			now.Add(time.Hour).Format("15:04"),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolsMonitor := NewPoolsMonitor(
+		DefaultStatusCheckTick,
+		WithTransactionPolicies(NewMaintenanceWindowPolicy(window)),
+	)
+
+	_, err = poolsMonitor.Register(
+		"beacon",
+		&testutils.MockLogger{},
+		localChain,
+		UnconditionalJoinPolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := poolsMonitor.Status()[0]
+	holdUntil := status.OperatorStatus.TransactionPolicyHoldUntil
+
+	testutils.AssertBoolsEqual(
+		t,
+		"hold until within next hour",
+		true,
+		holdUntil.After(now) && !holdUntil.After(now.Add(time.Hour)),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"next check at hold end",
+		true,
+		!status.NextCheck.After(holdUntil),
+	)
+}
@@ -0,0 +1,425 @@
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+const (
+	// DefaultBackupInterval is the default time between consecutive key store
+	// backups.
+	DefaultBackupInterval = 24 * time.Hour
+	// DefaultBackupRetention is the default number of most recent key store
+	// backups kept by the backup target.
+	DefaultBackupRetention = 7
+
+	// backupNamePrefix and backupNameSuffix surround the creation timestamp
+	// in names of key store backups.
+	backupNamePrefix = "keystore-"
+	backupNameSuffix = ".tar"
+	// backupTimestampLayout is the layout of the creation timestamp in names
+	// of key store backups. Names are lexicographically ordered by the
+	// creation time.
+	backupTimestampLayout = "20060102T150405Z"
+
+	// restoreStagingSuffix is the suffix of the directory the backup is
+	// extracted to before it replaces the key store directory.
+	restoreStagingSuffix = ".restore"
+	// restoreReplacedSuffix is the suffix of the directory the key store
+	// data are moved to once the restored data replace them. It is followed
+	// by the restore timestamp.
+	restoreReplacedSuffix = ".pre-restore-"
+)
+
+// BackupConfig configures periodic backups of the key store.
+type BackupConfig struct {
+	// Dir is the local directory backups are written to. Backups are
+	// disabled if empty. Remote object storage, e.g. S3 or GCS, is not
+	// supported directly. To keep backups there, the directory should be
+	// a mounted volume of that storage, e.g. via s3fs or gcsfuse, or it
+	// should be synchronized to that storage by an external tool.
+	Dir string
+	// Interval is the time between consecutive backups. Must be positive
+	// if backups are enabled.
+	Interval time.Duration
+	// Retention is the number of most recent backups kept in the backup
+	// directory. Older backups are removed. Retention lower than 1 disables
+	// removal of old backups.
+	Retention int
+}
+
+// BackupTarget is a place key store backups are kept in. DirBackupTarget is
+// the only implementation at the moment.
+type BackupTarget interface {
+	// Put stores the backup under the given name.
+	Put(name string, backup []byte) error
+	// Get returns the backup stored under the given name.
+	Get(name string) ([]byte, error)
+	// List returns names of all stored backups.
+	List() ([]string, error)
+	// Remove removes the backup stored under the given name.
+	Remove(name string) error
+}
+
+// DirBackupTarget is a BackupTarget keeping backups in a directory.
+type DirBackupTarget struct {
+	dir string
+}
+
+// NewDirBackupTarget creates a new backup target keeping backups in the
+// given directory. The directory is created if it does not exist.
+func NewDirBackupTarget(dir string) (*DirBackupTarget, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create backup directory: [%w]", err)
+	}
+
+	return &DirBackupTarget{dir: dir}, nil
+}
+
+// Put stores the backup under the given name. The backup is written to
+// a temporary file first so a partially written backup is never stored
+// under the given name.
+func (dbt *DirBackupTarget) Put(name string, backup []byte) error {
+	path := filepath.Join(dbt.dir, name)
+	tmpPath := path + ".tmp"
+
+	if err := persistence.Write(tmpPath, backup); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// Get returns the backup stored under the given name.
+func (dbt *DirBackupTarget) Get(name string) ([]byte, error) {
+	return persistence.Read(filepath.Join(dbt.dir, name))
+}
+
+// List returns names of all stored backups.
+func (dbt *DirBackupTarget) List() ([]string, error) {
+	entries, err := os.ReadDir(dbt.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && isBackupName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// Remove removes the backup stored under the given name.
+func (dbt *DirBackupTarget) Remove(name string) error {
+	return os.Remove(filepath.Join(dbt.dir, name))
+}
+
+func isBackupName(name string) bool {
+	return strings.HasPrefix(name, backupNamePrefix) &&
+		strings.HasSuffix(name, backupNameSuffix)
+}
+
+// RunBackups backs up the key store to the given target every interval,
+// starting immediately, and removes backups exceeding the retention. An
+// additional backup is made every time a request is received from the given
+// requests channel, e.g. once new key material is persisted. Failed backups
+// are logged. The function blocks until the given context is done. The
+// interval must be positive.
+func (s *Storage) RunBackups(
+	ctx context.Context,
+	target BackupTarget,
+	interval time.Duration,
+	retention int,
+	requests <-chan struct{},
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		name, err := s.Backup(target)
+		if err != nil {
+			logger.Errorf("cannot back up key store: [%v]", err)
+		} else {
+			logger.Infof("key store backed up as [%s]", name)
+
+			if err := pruneBackups(target, retention); err != nil {
+				logger.Errorf("cannot prune key store backups: [%v]", err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-requests:
+			logger.Infof("key store backup requested")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Backup stores a snapshot of the key store in the given target and returns
+// the name of the backup. The key store data are backed up in their
+// encrypted form. Each file is verified to decrypt with the storage password
+// before it is backed up and the stored backup is read back and verified to
+// match the key store.
+func (s *Storage) Backup(target BackupTarget) (string, error) {
+	box := newEncryptionBox(s.encryptionPassword)
+
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+
+	checksums := make(map[string][sha256.Size]byte)
+
+	err := filepath.WalkDir(s.keystoreDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return fmt.Errorf("unexpected non-regular file [%s]", path)
+		}
+
+		content, err := persistence.Read(path)
+		if err != nil {
+			return fmt.Errorf("cannot read file [%s]: [%w]", path, err)
+		}
+
+		if _, err := box.Decrypt(content); err != nil {
+			return fmt.Errorf("cannot decrypt file [%s]: [%w]", path, err)
+		}
+
+		relativePath, err := filepath.Rel(s.keystoreDir, path)
+		if err != nil {
+			return fmt.Errorf("cannot resolve path [%s]: [%w]", path, err)
+		}
+
+		if err := writer.WriteHeader(&tar.Header{
+			Name:    filepath.ToSlash(relativePath),
+			Mode:    0600,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+		if _, err := writer.Write(content); err != nil {
+			return err
+		}
+
+		checksums[relativePath] = sha256.Sum256(content)
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot archive key store: [%w]", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("cannot archive key store: [%w]", err)
+	}
+
+	name := backupNamePrefix +
+		time.Now().UTC().Format(backupTimestampLayout) +
+		backupNameSuffix
+
+	if err := target.Put(name, buffer.Bytes()); err != nil {
+		return "", fmt.Errorf("cannot store backup [%s]: [%w]", name, err)
+	}
+
+	stored, err := target.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("cannot read stored backup [%s]: [%w]", name, err)
+	}
+
+	if err := verifyBackup(stored, checksums); err != nil {
+		return "", fmt.Errorf("cannot verify stored backup [%s]: [%w]", name, err)
+	}
+
+	return name, nil
+}
+
+// verifyBackup checks whether the given backup holds files with exactly the
+// given checksums.
+func verifyBackup(
+	backup []byte,
+	expectedChecksums map[string][sha256.Size]byte,
+) error {
+	checksums := make(map[string][sha256.Size]byte)
+
+	err := readBackup(backup, func(name string, content []byte) error {
+		checksums[name] = sha256.Sum256(content)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(checksums) != len(expectedChecksums) {
+		return fmt.Errorf(
+			"backup holds [%d] files instead of [%d]",
+			len(checksums),
+			len(expectedChecksums),
+		)
+	}
+
+	for name, expectedChecksum := range expectedChecksums {
+		if checksum, ok := checksums[name]; !ok || checksum != expectedChecksum {
+			return fmt.Errorf("content of file [%s] differs", name)
+		}
+	}
+
+	return nil
+}
+
+// readBackup calls the given function for each file of the given backup.
+func readBackup(
+	backup []byte,
+	fileFn func(name string, content []byte) error,
+) error {
+	reader := tar.NewReader(bytes.NewReader(backup))
+
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read backup: [%w]", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return fmt.Errorf("unexpected entry [%s] in backup", header.Name)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unexpected path [%s] in backup", header.Name)
+		}
+
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf("cannot read file [%s] from backup: [%w]", name, err)
+		}
+
+		if err := fileFn(name, content); err != nil {
+			return err
+		}
+	}
+}
+
+// pruneBackups removes the oldest backups stored in the given target so that
+// at most retention backups are kept. Retention lower than 1 disables
+// pruning.
+func pruneBackups(target BackupTarget, retention int) error {
+	if retention < 1 {
+		return nil
+	}
+
+	names, err := target.List()
+	if err != nil {
+		return fmt.Errorf("cannot list backups: [%w]", err)
+	}
+
+	if len(names) <= retention {
+		return nil
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names[:len(names)-retention] {
+		if err := target.Remove(name); err != nil {
+			return fmt.Errorf("cannot remove backup [%s]: [%w]", name, err)
+		}
+
+		logger.Infof("key store backup [%s] removed", name)
+	}
+
+	return nil
+}
+
+// RestoreBackup replaces the key store with the backup stored under the given
+// name in the given target. The backup is extracted to a staging directory
+// and each file is verified to decrypt with the storage password. Only then
+// the key store directory is replaced with the staging one. The replaced key
+// store is kept and the path of its directory is returned. The client must
+// not be running during the restore.
+func (s *Storage) RestoreBackup(target BackupTarget, name string) (string, error) {
+	backup, err := target.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("cannot read backup [%s]: [%w]", name, err)
+	}
+
+	box := newEncryptionBox(s.encryptionPassword)
+
+	stagingDir := s.keystoreDir + restoreStagingSuffix
+
+	// Remove leftovers of a previous interrupted restore.
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return "", fmt.Errorf("cannot remove staging directory: [%w]", err)
+	}
+
+	cleanUpStagingDir := func() {
+		if err := os.RemoveAll(stagingDir); err != nil {
+			logger.Errorf(
+				"cannot remove staging directory [%s]: [%v]",
+				stagingDir,
+				err,
+			)
+		}
+	}
+
+	if err := os.Mkdir(stagingDir, 0700); err != nil {
+		return "", fmt.Errorf("cannot create staging directory: [%w]", err)
+	}
+
+	err = readBackup(backup, func(name string, content []byte) error {
+		if _, err := box.Decrypt(content); err != nil {
+			return fmt.Errorf("cannot decrypt file [%s]: [%w]", name, err)
+		}
+
+		path := filepath.Join(stagingDir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+
+		return persistence.Write(path, content)
+	})
+	if err != nil {
+		cleanUpStagingDir()
+		return "", fmt.Errorf("cannot extract backup [%s]: [%w]", name, err)
+	}
+
+	replacedDir := fmt.Sprintf(
+		"%s%s%d",
+		s.keystoreDir,
+		restoreReplacedSuffix,
+		time.Now().Unix(),
+	)
+
+	if err := swapDirectory(s.keystoreDir, stagingDir, replacedDir); err != nil {
+		cleanUpStagingDir()
+		return "", fmt.Errorf("cannot replace key store: [%w]", err)
+	}
+
+	return replacedDir, nil
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer-1"),
+		"wallet-1",
+		"membership_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	backupTarget, err := NewDirBackupTarget(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := storage.Backup(backupTarget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := backupTarget.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{name}, names) {
+		t.Fatalf(
+			"unexpected backups\nexpected: [%v]\nactual:   [%v]",
+			[]string{name},
+			names,
+		)
+	}
+
+	// Modify the key store after the backup was taken.
+	if err := keyStorePersistence.Save(
+		[]byte("signer-2"),
+		"wallet-2",
+		"membership_2",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	replacedDir, err := storage.RestoreBackup(backupTarget, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(replacedDir); err != nil {
+		t.Errorf("replaced directory [%s] not found: [%v]", replacedDir, err)
+	}
+
+	restoredKeyStorePersistence, err :=
+		storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPersistedContent(
+		t,
+		map[string]string{"wallet-1/membership_1": "signer-1"},
+		restoredKeyStorePersistence,
+	)
+}
+
+func TestRestoreBackup_UndecryptableBackup(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer-1"),
+		"wallet-1",
+		"membership_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	backupTarget, err := NewDirBackupTarget(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Take a backup of a key store encrypted with a different password.
+	otherStorage, err := Initialize(Config{Dir: t.TempDir()}, "other-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKeyStorePersistence, err :=
+		otherStorage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherKeyStorePersistence.Save(
+		[]byte("signer-2"),
+		"wallet-2",
+		"membership_2",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := otherStorage.Backup(backupTarget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = storage.RestoreBackup(backupTarget, name)
+	if err == nil || !strings.Contains(err.Error(), "cannot decrypt file") {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	entries, err := os.ReadDir(config.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	// No staging nor replaced directories should be left.
+	expectedNames := []string{keyStoreDirName, workDirName}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Errorf(
+			"unexpected storage directories\nexpected: [%v]\nactual:   [%v]",
+			expectedNames,
+			names,
+		)
+	}
+
+	// The key store must be left untouched.
+	assertPersistedContent(
+		t,
+		map[string]string{"wallet-1/membership_1": "signer-1"},
+		keyStorePersistence,
+	)
+}
+
+func TestPruneBackups(t *testing.T) {
+	backupTarget, err := NewDirBackupTarget(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"keystore-20230103T000000Z.tar",
+		"keystore-20230101T000000Z.tar",
+		"keystore-20230104T000000Z.tar",
+		"keystore-20230102T000000Z.tar",
+		"unrelated.tar",
+	} {
+		if err := backupTarget.Put(name, []byte{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pruneBackups(backupTarget, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := backupTarget.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedNames := []string{
+		"keystore-20230103T000000Z.tar",
+		"keystore-20230104T000000Z.tar",
+	}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Errorf(
+			"unexpected backups\nexpected: [%v]\nactual:   [%v]",
+			expectedNames,
+			names,
+		)
+	}
+
+	// Files not being backups must be left untouched.
+	if _, err := os.Stat(
+		filepath.Join(backupTarget.dir, "unrelated.tar"),
+	); err != nil {
+		t.Errorf("unrelated file not found: [%v]", err)
+	}
+
+	// Retention lower than 1 disables pruning.
+	if err := pruneBackups(backupTarget, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err = backupTarget.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "backups count", 2, len(names))
+}
+
+func TestRunBackups_Requested(t *testing.T) {
+	storage, err := Initialize(Config{Dir: t.TempDir()}, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dirBackupTarget, err := NewDirBackupTarget(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backupTarget := &notifyingBackupTarget{
+		BackupTarget: dirBackupTarget,
+		puts:         make(chan string, 10),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := make(chan struct{})
+
+	// The interval is long enough so that only the initial and the
+	// requested backups are made.
+	go storage.RunBackups(ctx, backupTarget, time.Hour, 0, requests)
+
+	awaitPut := func() {
+		select {
+		case <-backupTarget.puts:
+		case <-time.After(5 * time.Second):
+			t.Fatal("backup not made")
+		}
+	}
+
+	// The initial backup.
+	awaitPut()
+
+	requests <- struct{}{}
+
+	// The requested backup.
+	awaitPut()
+}
+
+type notifyingBackupTarget struct {
+	BackupTarget
+	puts chan string
+}
+
+func (nbt *notifyingBackupTarget) Put(name string, backup []byte) error {
+	if err := nbt.BackupTarget.Put(name, backup); err != nil {
+		return err
+	}
+
+	nbt.puts <- name
+
+	return nil
+}
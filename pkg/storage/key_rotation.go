@@ -0,0 +1,387 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+const (
+	// keyRotationJournalFileName is the name of the file tracking progress
+	// of the key rotation. The file is kept in the storage root directory
+	// until the rotation completes.
+	keyRotationJournalFileName = "key_rotation.journal"
+	// keyRotationTmpSuffix is the suffix of the temporary file a re-encrypted
+	// file is written to before it replaces the original one.
+	keyRotationTmpSuffix = ".key-rotation-tmp"
+)
+
+// keyRotationCheckValue is the plaintext of the key check values kept in the
+// key rotation journal. It is specific to the key rotation so the check
+// values cannot be mistaken for any other data encrypted with storage keys.
+var keyRotationCheckValue = []byte("keep-client/storage/key-rotation/check-value/v1")
+
+// errKeyRotationMismatch is returned when the key rotation in progress was
+// started with different encryption keys.
+var errKeyRotationMismatch = fmt.Errorf(
+	"key rotation in progress was started with different encryption keys",
+)
+
+// RotateKey re-encrypts all data persisted in the keystore and work
+// directories with the key derived from the new encryption password. Unlike
+// ReEncrypt, files are re-encrypted in place, one by one, so no additional
+// disk space is needed. Each file is replaced atomically and recorded in
+// a journal kept in the storage root directory. If the rotation is
+// interrupted, running it again with the same passwords resumes it from
+// the last recorded file. Until the rotation completes, the storage holds
+// a mix of data encrypted with both keys and the client must not be
+// started; see KeyRotationInProgress. Once all files are re-encrypted,
+// they are verified to decrypt with the new key and the journal is removed.
+// All files are checked before the rotation starts and no file is changed
+// if any of them decrypts with neither key. Files of quarantine directories,
+// see QuarantineDirSuffix, are left as they are.
+func (s *Storage) RotateKey(newEncryptionPassword string) error {
+	oldBox := newEncryptionBox(s.encryptionPassword)
+	newBox := newEncryptionBox(newEncryptionPassword)
+
+	dirs := []string{s.keystoreDir, s.workDir}
+
+	var journal *keyRotationJournal
+	defer func() {
+		if journal != nil {
+			journal.close()
+		}
+	}()
+
+	inProgress, err := s.KeyRotationInProgress()
+	if err != nil {
+		return fmt.Errorf("cannot check key rotation journal: [%w]", err)
+	}
+
+	// An interrupted rotation must be resumed with the same passwords.
+	// Check that before anything else.
+	if inProgress {
+		journal, err = openKeyRotationJournal(
+			s.keyRotationJournalPath(),
+			oldBox,
+			newBox,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot open key rotation journal: [%w]", err)
+		}
+
+		logger.Infof(
+			"resuming key rotation; [%d] files already re-encrypted",
+			len(journal.done),
+		)
+	}
+
+	// Check all files before any of them is re-encrypted so that a file
+	// the rotation cannot handle does not leave the storage with data
+	// encrypted with different keys.
+	var files []*storageFile
+	for _, dir := range dirs {
+		// Remove leftovers of a previous interrupted rotation.
+		if err := removeKeyRotationTmpFiles(dir); err != nil {
+			return err
+		}
+
+		dirFiles, err := checkStorageFiles(dir, oldBox, newBox)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot rotate key of directory [%s]: [%w]",
+				dir,
+				err,
+			)
+		}
+
+		files = append(files, dirFiles...)
+	}
+
+	if journal == nil {
+		journal, err = openKeyRotationJournal(
+			s.keyRotationJournalPath(),
+			oldBox,
+			newBox,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot open key rotation journal: [%w]", err)
+		}
+	}
+
+	for _, file := range files {
+		// Quarantined files are kept as they are.
+		if file.key == fileKeyUnknown || journal.done[file.path] {
+			continue
+		}
+
+		if err := rotateFileKey(file, oldBox, newBox); err != nil {
+			return fmt.Errorf("cannot rotate key: [%w]", err)
+		}
+
+		if err := journal.record(file.path); err != nil {
+			return err
+		}
+	}
+
+	// Verification pass: every file must decrypt with the new key.
+	for _, file := range files {
+		if file.key == fileKeyUnknown {
+			continue
+		}
+
+		if _, err := decryptFile(file.path, newBox); err != nil {
+			return fmt.Errorf("verification failed: [%w]", err)
+		}
+	}
+
+	if err := journal.remove(); err != nil {
+		return fmt.Errorf("cannot remove key rotation journal: [%w]", err)
+	}
+
+	s.encryptionPassword = newEncryptionPassword
+
+	return nil
+}
+
+// KeyRotationInProgress returns true if a key rotation was started but not
+// completed. The client must not be started until the rotation is resumed
+// and completed as the storage holds data encrypted with different keys.
+func (s *Storage) KeyRotationInProgress() (bool, error) {
+	_, err := os.Stat(s.keyRotationJournalPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Storage) keyRotationJournalPath() string {
+	// The keystore directory is placed directly in the storage root
+	// directory.
+	return filepath.Join(filepath.Dir(s.keystoreDir), keyRotationJournalFileName)
+}
+
+// newKeyRotationHeader returns the journal header identifying the key
+// rotation between keys of the given boxes. The header holds key check
+// values, i.e. keyRotationCheckValue encrypted with the old and the new key.
+// Unlike a hash of the keys, check values are ordinary ciphertexts revealing
+// no more about the keys than any other encrypted file in the storage, yet
+// they allow to detect an attempt to resume the rotation with different
+// passwords.
+func newKeyRotationHeader(
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) (string, error) {
+	oldCheckValue, err := oldBox.Encrypt(keyRotationCheckValue)
+	if err != nil {
+		return "", fmt.Errorf("cannot compute old key check value: [%w]", err)
+	}
+
+	newCheckValue, err := newBox.Encrypt(keyRotationCheckValue)
+	if err != nil {
+		return "", fmt.Errorf("cannot compute new key check value: [%w]", err)
+	}
+
+	return hex.EncodeToString(oldCheckValue) + " " +
+		hex.EncodeToString(newCheckValue), nil
+}
+
+// verifyKeyRotationHeader returns errKeyRotationMismatch if the given journal
+// header was not created for the key rotation between keys of the given
+// boxes.
+func verifyKeyRotationHeader(
+	header string,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) error {
+	checkValues := strings.Split(header, " ")
+	if len(checkValues) != 2 {
+		return errKeyRotationMismatch
+	}
+
+	for i, box := range []encryption.Box{oldBox, newBox} {
+		checkValue, err := hex.DecodeString(checkValues[i])
+		if err != nil {
+			return errKeyRotationMismatch
+		}
+
+		plaintext, err := box.Decrypt(checkValue)
+		if err != nil || !bytes.Equal(plaintext, keyRotationCheckValue) {
+			return errKeyRotationMismatch
+		}
+	}
+
+	return nil
+}
+
+// rotateFileKey re-encrypts the given file with the new key. The file is
+// written to a temporary file first which then atomically replaces the
+// original one. A file already encrypted with the new key is left untouched;
+// this happens if the rotation was interrupted after the file was replaced
+// but before it was recorded in the journal.
+func rotateFileKey(
+	file *storageFile,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) error {
+	if file.key != fileKeyOld {
+		return nil
+	}
+
+	ciphertext, err := reEncryptFile(file, oldBox, newBox)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := file.path + keyRotationTmpSuffix
+
+	if err := persistence.Write(tmpPath, ciphertext); err != nil {
+		return fmt.Errorf("cannot write file [%s]: [%w]", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, file.path); err != nil {
+		return fmt.Errorf("cannot replace file [%s]: [%w]", file.path, err)
+	}
+
+	return nil
+}
+
+// removeKeyRotationTmpFiles removes temporary files left in the given
+// directory by an interrupted key rotation.
+func removeKeyRotationTmpFiles(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(path, keyRotationTmpSuffix) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf(
+				"cannot remove temporary file [%s]: [%w]",
+				path,
+				err,
+			)
+		}
+
+		return nil
+	})
+}
+
+// keyRotationJournal tracks files already re-encrypted by the key rotation.
+// The first line of the journal file holds the key check values and each
+// following line holds the path of a re-encrypted file.
+type keyRotationJournal struct {
+	path string
+	file *os.File
+	done map[string]bool
+}
+
+// openKeyRotationJournal opens the journal file under the given path or
+// creates it if it does not exist. An error is returned if the existing
+// journal was created for a rotation between different keys.
+func openKeyRotationJournal(
+	path string,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) (*keyRotationJournal, error) {
+	journal := &keyRotationJournal{
+		path: path,
+		done: make(map[string]bool),
+	}
+
+	content, err := persistence.Read(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		header, err := newKeyRotationHeader(oldBox, newBox)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := persistence.Write(path, []byte(header+"\n")); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+
+		if !scanner.Scan() {
+			return nil, errKeyRotationMismatch
+		}
+
+		if err := verifyKeyRotationHeader(
+			scanner.Text(),
+			oldBox,
+			newBox,
+		); err != nil {
+			return nil, err
+		}
+
+		for scanner.Scan() {
+			journal.done[scanner.Text()] = true
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	// #nosec G304 (file path provided as taint input)
+	// The path points to the predefined storage. There is no user input.
+	journal.file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return journal, nil
+}
+
+// record records the given file as re-encrypted. The record is synced to
+// disk before the function returns.
+func (krj *keyRotationJournal) record(path string) error {
+	if _, err := krj.file.WriteString(path + "\n"); err != nil {
+		return fmt.Errorf("cannot record file [%s]: [%w]", path, err)
+	}
+
+	if err := krj.file.Sync(); err != nil {
+		return fmt.Errorf("cannot record file [%s]: [%w]", path, err)
+	}
+
+	krj.done[path] = true
+
+	return nil
+}
+
+func (krj *keyRotationJournal) close() {
+	if krj.file == nil {
+		return
+	}
+
+	if err := krj.file.Close(); err != nil {
+		logger.Errorf("cannot close key rotation journal: [%v]", err)
+	}
+
+	krj.file = nil
+}
+
+// remove closes and removes the journal file.
+func (krj *keyRotationJournal) remove() error {
+	krj.close()
+	return os.Remove(krj.path)
+}
@@ -0,0 +1,331 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestRotateKey(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, membership := range []string{"membership_1", "membership_2"} {
+		if err := keyStorePersistence.Save(
+			[]byte("signer-"+membership),
+			"wallet-1",
+			membership,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workPersistence.Save(
+		[]byte("pre-params"),
+		"dkg",
+		"pre_params",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a rotation interrupted after the first membership file was
+	// re-encrypted and recorded and the second one was re-encrypted but
+	// not recorded in the journal.
+	oldBox := newEncryptionBox("old-password")
+	newBox := newEncryptionBox("new-password")
+
+	walletDir := filepath.Join(config.Dir, keyStoreDirName, "tbtc", "current", "wallet-1")
+	firstPath := filepath.Join(walletDir, "membership_1")
+	secondPath := filepath.Join(walletDir, "membership_2")
+
+	journal, err := openKeyRotationJournal(
+		storage.keyRotationJournalPath(),
+		oldBox,
+		newBox,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rotateFileKey(&storageFile{path: firstPath, key: fileKeyOld}, oldBox, newBox); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.record(firstPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := rotateFileKey(&storageFile{path: secondPath, key: fileKeyOld}, oldBox, newBox); err != nil {
+		t.Fatal(err)
+	}
+	journal.close()
+
+	// Leftover of a file being re-encrypted when the rotation was
+	// interrupted.
+	if err := os.WriteFile(
+		filepath.Join(walletDir, "membership_3"+keyRotationTmpSuffix),
+		[]byte("partial"),
+		0600,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	inProgress, err := storage.KeyRotationInProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBoolsEqual(t, "rotation in progress", true, inProgress)
+
+	// Resuming with different passwords must be rejected.
+	otherStorage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = otherStorage.RotateKey("other-password")
+	if !errors.Is(err, errKeyRotationMismatch) {
+		t.Fatalf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			errKeyRotationMismatch,
+			err,
+		)
+	}
+
+	if err := storage.RotateKey("new-password"); err != nil {
+		t.Fatal(err)
+	}
+
+	inProgress, err = storage.KeyRotationInProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBoolsEqual(t, "rotation in progress", false, inProgress)
+
+	rotatedStorage, err := Initialize(config, "new-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotatedKeyStorePersistence, err :=
+		rotatedStorage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPersistedContent(
+		t,
+		map[string]string{
+			"wallet-1/membership_1": "signer-membership_1",
+			"wallet-1/membership_2": "signer-membership_2",
+		},
+		rotatedKeyStorePersistence,
+	)
+
+	rotatedWorkPersistence, err :=
+		rotatedStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPersistedContent(
+		t,
+		map[string]string{"dkg/pre_params": "pre-params"},
+		rotatedWorkPersistence,
+	)
+}
+
+func TestRotateKey_UndecryptableFile(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer"),
+		"wallet-1",
+		"membership_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Data encrypted with a different password cannot be re-encrypted.
+	otherStorage, err := Initialize(config, "other-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherWorkPersistence, err := otherStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherWorkPersistence.Save(
+		[]byte("pre-params"),
+		"dkg",
+		"pre_params",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.RotateKey("new-password")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	// All files are checked before the rotation starts so the storage is
+	// left untouched and the client can still be started.
+	inProgress, err := storage.KeyRotationInProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBoolsEqual(t, "rotation in progress", false, inProgress)
+
+	assertPersistedContent(
+		t,
+		map[string]string{"wallet-1/membership_1": "signer"},
+		keyStorePersistence,
+	)
+}
+
+func TestRotateKey_QuarantinedFile(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workPersistence.Save(
+		[]byte("pre-params"),
+		"preparams",
+		"pp_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quarantined entries are moved as they are and may not decrypt with
+	// any key.
+	quarantinedPath := filepath.Join(
+		config.Dir,
+		workDirName,
+		"tbtc",
+		"preparams"+QuarantineDirSuffix,
+		"pp_corrupted",
+	)
+	if err := os.MkdirAll(filepath.Dir(quarantinedPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(quarantinedPath, []byte("corrupted"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := storage.RotateKey("new-password"); err != nil {
+		t.Fatal(err)
+	}
+
+	quarantined, err := os.ReadFile(quarantinedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertStringsEqual(
+		t,
+		"quarantined file content",
+		"corrupted",
+		string(quarantined),
+	)
+
+	preParams, err := decryptFile(
+		filepath.Join(config.Dir, workDirName, "tbtc", "preparams", "pp_1"),
+		newEncryptionBox("new-password"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertStringsEqual(
+		t,
+		"pre-params content",
+		"pre-params",
+		string(preParams),
+	)
+}
+
+func TestVerifyKeyRotationHeader(t *testing.T) {
+	oldBox := newEncryptionBox("old-password")
+	newBox := newEncryptionBox("new-password")
+	otherBox := newEncryptionBox("other-password")
+
+	header, err := newKeyRotationHeader(oldBox, newBox)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		header        string
+		oldBox        encryption.Box
+		newBox        encryption.Box
+		expectedError error
+	}{
+		"same keys": {
+			header:        header,
+			oldBox:        oldBox,
+			newBox:        newBox,
+			expectedError: nil,
+		},
+		"different old key": {
+			header:        header,
+			oldBox:        otherBox,
+			newBox:        newBox,
+			expectedError: errKeyRotationMismatch,
+		},
+		"different new key": {
+			header:        header,
+			oldBox:        oldBox,
+			newBox:        otherBox,
+			expectedError: errKeyRotationMismatch,
+		},
+		"swapped keys": {
+			header:        header,
+			oldBox:        newBox,
+			newBox:        oldBox,
+			expectedError: errKeyRotationMismatch,
+		},
+		"malformed header": {
+			header:        "not-a-header",
+			oldBox:        oldBox,
+			newBox:        newBox,
+			expectedError: errKeyRotationMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := verifyKeyRotationHeader(test.header, test.oldBox, test.newBox)
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+// plaintextMigrationTmpSuffix is the suffix of the temporary file an
+// encrypted file is written to before it replaces the plaintext one.
+const plaintextMigrationTmpSuffix = ".encryption-tmp"
+
+// EncryptPlaintextWorkData encrypts files of the given work subdirectory
+// that were persisted without encryption. A file is considered plaintext if
+// it does not decrypt with the storage encryption key and the isPlaintext
+// function recognizes its content as a valid entry. Other files that do not
+// decrypt are left untouched so that they are handled by the component
+// reading them. Each file is replaced atomically so an interrupted migration
+// can be safely run again. Returns the number of encrypted files.
+func (s *Storage) EncryptPlaintextWorkData(
+	dir string,
+	isPlaintext func(content []byte) bool,
+) (int, error) {
+	box := newEncryptionBox(s.encryptionPassword)
+	root := filepath.Join(s.workDir, dir)
+
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	// Remove leftovers of a previous interrupted migration; the original
+	// files are still in place.
+	if err := removePlaintextMigrationTmpFiles(root); err != nil {
+		return 0, err
+	}
+
+	encrypted := 0
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return fmt.Errorf("unexpected non-regular file [%s]", path)
+		}
+
+		content, err := persistence.Read(path)
+		if err != nil {
+			return fmt.Errorf("cannot read file [%s]: [%w]", path, err)
+		}
+
+		if _, err := box.Decrypt(content); err == nil {
+			return nil
+		}
+
+		if !isPlaintext(content) {
+			logger.Warnf(
+				"file [%s] could not be decrypted and is not a valid "+
+					"plaintext entry; leaving it untouched",
+				path,
+			)
+			return nil
+		}
+
+		ciphertext, err := box.Encrypt(content)
+		if err != nil {
+			return fmt.Errorf("cannot encrypt file [%s]: [%w]", path, err)
+		}
+
+		decrypted, err := box.Decrypt(ciphertext)
+		if err != nil || !bytes.Equal(content, decrypted) {
+			return fmt.Errorf("verification of file [%s] failed", path)
+		}
+
+		tmpPath := path + plaintextMigrationTmpSuffix
+
+		if err := persistence.Write(tmpPath, ciphertext); err != nil {
+			return fmt.Errorf("cannot write file [%s]: [%w]", tmpPath, err)
+		}
+
+		if err := os.Rename(tmpPath, path); err != nil {
+			return fmt.Errorf("cannot replace file [%s]: [%w]", path, err)
+		}
+
+		logger.Infof("encrypted plaintext file [%s]", path)
+		encrypted++
+
+		return nil
+	})
+	if err != nil {
+		return encrypted, fmt.Errorf(
+			"cannot encrypt plaintext files of directory [%s]: [%w]",
+			root,
+			err,
+		)
+	}
+
+	return encrypted, nil
+}
+
+// removePlaintextMigrationTmpFiles removes temporary files left in the given
+// directory by an interrupted migration.
+func removePlaintextMigrationTmpFiles(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(path, plaintextMigrationTmpSuffix) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf(
+				"cannot remove temporary file [%s]: [%w]",
+				path,
+				err,
+			)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestEncryptPlaintextWorkData(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workPersistence.Save(
+		[]byte("valid-encrypted"),
+		"preparams",
+		"pp_encrypted",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	preParamsDir := filepath.Join(config.Dir, workDirName, "tbtc", "preparams")
+	encryptedPath := filepath.Join(preParamsDir, "pp_encrypted")
+
+	encryptedBefore, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile := func(name string, content []byte) {
+		if err := os.WriteFile(
+			filepath.Join(preParamsDir, name),
+			content,
+			0600,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("pp_plaintext", []byte("valid-plaintext"))
+	writeFile("pp_corrupted", []byte("corrupted"))
+	// Leftover of a file being encrypted when the migration was interrupted.
+	writeFile("pp_plaintext"+plaintextMigrationTmpSuffix, []byte("partial"))
+
+	isPlaintext := func(content []byte) bool {
+		return bytes.HasPrefix(content, []byte("valid-"))
+	}
+
+	encrypted, err := storage.EncryptPlaintextWorkData(
+		filepath.Join("tbtc", "preparams"),
+		isPlaintext,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "encrypted files", 1, encrypted)
+
+	// Files that are already encrypted are left untouched.
+	encryptedAfter, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encryptedBefore, encryptedAfter) {
+		t.Errorf("encrypted file was modified")
+	}
+
+	// Files that are not valid plaintext entries are left untouched.
+	corrupted, err := os.ReadFile(filepath.Join(preParamsDir, "pp_corrupted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertStringsEqual(t, "corrupted file", "corrupted", string(corrupted))
+
+	if _, err := os.Stat(
+		filepath.Join(preParamsDir, "pp_plaintext"+plaintextMigrationTmpSuffix),
+	); !os.IsNotExist(err) {
+		t.Errorf("temporary file was not removed")
+	}
+
+	plaintext, err := decryptFile(
+		filepath.Join(preParamsDir, "pp_plaintext"),
+		newEncryptionBox("password"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertStringsEqual(
+		t,
+		"decrypted content",
+		"valid-plaintext",
+		string(plaintext),
+	)
+
+	// The migration can be run again.
+	encrypted, err = storage.EncryptPlaintextWorkData(
+		filepath.Join("tbtc", "preparams"),
+		isPlaintext,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "encrypted files on rerun", 0, encrypted)
+}
+
+func TestEncryptPlaintextWorkData_MissingDirectory(t *testing.T) {
+	storage, err := Initialize(Config{Dir: t.TempDir()}, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := storage.EncryptPlaintextWorkData(
+		filepath.Join("tbtc", "preparams"),
+		func(content []byte) bool { return true },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "encrypted files", 0, encrypted)
+}
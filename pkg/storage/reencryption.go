@@ -0,0 +1,517 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+const (
+	// reEncryptionStagingSuffix is the suffix of the directory holding data
+	// re-encrypted with the new password before it replaces the original
+	// directory.
+	reEncryptionStagingSuffix = ".reencryption"
+	// reEncryptionBackupSuffix is the suffix of the directory the original
+	// data are moved to once the re-encrypted data replace them. It is
+	// followed by the re-encryption timestamp.
+	reEncryptionBackupSuffix = ".backup-"
+)
+
+// ReEncrypt re-encrypts all data persisted in the keystore and work
+// directories with the new encryption password. Data are re-encrypted into
+// staging directories first and each re-encrypted file is verified to
+// decrypt with the new password to the original content. Only then the
+// original directories are replaced with the staging ones by renaming, so
+// the storage is never left partially re-encrypted. The original directories
+// are kept as backups and their paths are returned. The storage must not be
+// modified by a running client during re-encryption; if a modification is
+// detected, the re-encryption is aborted and the storage is left untouched.
+// Files are checked the same way RotateKey does: no staging directory is
+// created if any file decrypts with neither key, and quarantined files are
+// copied as they are.
+func (s *Storage) ReEncrypt(newEncryptionPassword string) ([]string, error) {
+	oldBox := newEncryptionBox(s.encryptionPassword)
+	newBox := newEncryptionBox(newEncryptionPassword)
+
+	dirs := []string{s.keystoreDir, s.workDir}
+
+	var stagingDirs []string
+	cleanUpStagingDirs := func() {
+		for _, stagingDir := range stagingDirs {
+			if err := os.RemoveAll(stagingDir); err != nil {
+				logger.Errorf(
+					"cannot remove staging directory [%s]: [%v]",
+					stagingDir,
+					err,
+				)
+			}
+		}
+	}
+
+	// Check all files before any staging directory is created.
+	states := make([]map[string]fileState, len(dirs))
+	files := make([][]*storageFile, len(dirs))
+	for i, dir := range dirs {
+		var err error
+
+		states[i], err = directoryState(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		files[i], err = checkStorageFiles(dir, oldBox, newBox)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot re-encrypt directory [%s]: [%w]",
+				dir,
+				err,
+			)
+		}
+	}
+
+	for i, dir := range dirs {
+		stagingDir, err := reEncryptDirectory(
+			dir,
+			files[i],
+			states[i],
+			oldBox,
+			newBox,
+		)
+		if stagingDir != "" {
+			stagingDirs = append(stagingDirs, stagingDir)
+		}
+		if err != nil {
+			cleanUpStagingDirs()
+			return nil, fmt.Errorf(
+				"cannot re-encrypt directory [%s]: [%w]",
+				dir,
+				err,
+			)
+		}
+	}
+
+	backupSuffix := fmt.Sprintf(
+		"%s%d",
+		reEncryptionBackupSuffix,
+		time.Now().Unix(),
+	)
+
+	var backupDirs []string
+	for i, dir := range dirs {
+		backupDir := dir + backupSuffix
+
+		if err := swapDirectory(dir, stagingDirs[i], backupDir); err != nil {
+			// Directories swapped so far hold data re-encrypted with the
+			// new password while the rest hold data encrypted with the old
+			// one. Bring back the original directories to not leave
+			// the storage in a mixed state.
+			for j := 0; j < i; j++ {
+				if restoreErr := swapDirectory(
+					dirs[j],
+					backupDirs[j],
+					stagingDirs[j],
+				); restoreErr != nil {
+					return nil, fmt.Errorf(
+						"cannot restore directory [%s] from backup [%s] "+
+							"after swap failure [%v]: [%w]",
+						dirs[j],
+						backupDirs[j],
+						err,
+						restoreErr,
+					)
+				}
+			}
+
+			cleanUpStagingDirs()
+
+			return nil, fmt.Errorf(
+				"cannot swap directory [%s]: [%w]",
+				dir,
+				err,
+			)
+		}
+
+		backupDirs = append(backupDirs, backupDir)
+	}
+
+	s.encryptionPassword = newEncryptionPassword
+
+	return backupDirs, nil
+}
+
+func newEncryptionBox(password string) encryption.Box {
+	// Derive the key the same way the encrypted persistence does.
+	return encryption.NewBox(sha256.Sum256([]byte(password)))
+}
+
+// reEncryptDirectory re-encrypts the given files of the given directory
+// into a staging directory and verifies the result. The directory must be in
+// the given state, captured before its files were checked, for the result to
+// be accepted. It returns the path of the staging directory if it was
+// created, even if an error occurred.
+func reEncryptDirectory(
+	dir string,
+	files []*storageFile,
+	stateBefore map[string]fileState,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) (string, error) {
+	stagingDir := dir + reEncryptionStagingSuffix
+
+	// Remove leftovers of a previous interrupted re-encryption.
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return "", fmt.Errorf("cannot remove staging directory: [%w]", err)
+	}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		stagingPath, err := stagingPath(dir, stagingDir, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		return os.Mkdir(stagingPath, info.Mode().Perm())
+	})
+	if err != nil {
+		return stagingDir, err
+	}
+
+	for _, file := range files {
+		stagingPath, err := stagingPath(dir, stagingDir, file.path)
+		if err != nil {
+			return stagingDir, err
+		}
+
+		content, err := reEncryptFile(file, oldBox, newBox)
+		if err != nil {
+			return stagingDir, err
+		}
+
+		if err := persistence.Write(stagingPath, content); err != nil {
+			return stagingDir, err
+		}
+	}
+
+	// Verification pass: every original file must have its counterpart
+	// in the staging directory holding the same content under the new key.
+	for _, file := range files {
+		stagingPath, err := stagingPath(dir, stagingDir, file.path)
+		if err != nil {
+			return stagingDir, err
+		}
+
+		if err := verifyReEncryptedFile(
+			file,
+			stagingPath,
+			oldBox,
+			newBox,
+		); err != nil {
+			return stagingDir, fmt.Errorf("verification failed: [%w]", err)
+		}
+	}
+
+	stateAfter, err := directoryState(dir)
+	if err != nil {
+		return stagingDir, err
+	}
+
+	if !reflect.DeepEqual(stateBefore, stateAfter) {
+		return stagingDir, fmt.Errorf(
+			"directory was modified during re-encryption; make sure the " +
+				"client is not running and try again",
+		)
+	}
+
+	return stagingDir, nil
+}
+
+// reEncryptFile returns the content of the given file to be persisted under
+// the new key. A file encrypted with the old key is re-encrypted and the
+// result is verified to decrypt to the original content. Other files are
+// returned as they are.
+func reEncryptFile(
+	file *storageFile,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) ([]byte, error) {
+	if file.key != fileKeyOld {
+		content, err := persistence.Read(file.path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot read file [%s]: [%w]",
+				file.path,
+				err,
+			)
+		}
+
+		return content, nil
+	}
+
+	plaintext, err := decryptFile(file.path, oldBox)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := newBox.Encrypt(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encrypt file [%s]: [%w]", file.path, err)
+	}
+
+	reEncrypted, err := newBox.Decrypt(ciphertext)
+	if err != nil || !bytes.Equal(plaintext, reEncrypted) {
+		return nil, fmt.Errorf("verification of file [%s] failed", file.path)
+	}
+
+	return ciphertext, nil
+}
+
+// verifyReEncryptedFile checks whether the file under the given path holds
+// the content of the given original file under the new key.
+func verifyReEncryptedFile(
+	file *storageFile,
+	path string,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) error {
+	if file.key != fileKeyOld {
+		original, err := persistence.Read(file.path)
+		if err != nil {
+			return fmt.Errorf("cannot read file [%s]: [%w]", file.path, err)
+		}
+
+		copied, err := persistence.Read(path)
+		if err != nil {
+			return fmt.Errorf("cannot read file [%s]: [%w]", path, err)
+		}
+
+		if !bytes.Equal(original, copied) {
+			return fmt.Errorf("content of file [%s] differs", path)
+		}
+
+		return nil
+	}
+
+	original, err := decryptFile(file.path, oldBox)
+	if err != nil {
+		return err
+	}
+
+	reEncrypted, err := decryptFile(path, newBox)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(original, reEncrypted) {
+		return fmt.Errorf("content of file [%s] differs", path)
+	}
+
+	return nil
+}
+
+func stagingPath(dir string, stagingDir string, path string) (string, error) {
+	relativePath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve path [%s]: [%w]", path, err)
+	}
+
+	return filepath.Join(stagingDir, relativePath), nil
+}
+
+func decryptFile(path string, box encryption.Box) ([]byte, error) {
+	ciphertext, err := persistence.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file [%s]: [%w]", path, err)
+	}
+
+	plaintext, err := box.Decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt file [%s]: [%w]", path, err)
+	}
+
+	return plaintext, nil
+}
+
+// fileKey describes the key a file of the storage is encrypted with.
+type fileKey int
+
+const (
+	fileKeyOld fileKey = iota
+	fileKeyNew
+	// fileKeyUnknown is the key of quarantined files that are kept as they
+	// are and never decrypted.
+	fileKeyUnknown
+)
+
+// storageFile is a regular file of a storage directory.
+type storageFile struct {
+	path string
+	key  fileKey
+}
+
+// checkStorageFiles determines the key of every file of the given directory
+// before any file is re-encrypted. Files of quarantine directories, see
+// QuarantineDirSuffix, are not decrypted. If any other file decrypts with
+// neither key, an error listing all such files is returned so the operator
+// can handle them at once.
+func checkStorageFiles(
+	dir string,
+	oldBox encryption.Box,
+	newBox encryption.Box,
+) ([]*storageFile, error) {
+	var files []*storageFile
+	var undecryptable []string
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return fmt.Errorf("unexpected non-regular file [%s]", path)
+		}
+
+		file := &storageFile{path: path}
+
+		if isQuarantined(dir, path) {
+			file.key = fileKeyUnknown
+		} else if _, err := decryptFile(path, oldBox); err == nil {
+			file.key = fileKeyOld
+		} else if _, err := decryptFile(path, newBox); err == nil {
+			file.key = fileKeyNew
+		} else {
+			undecryptable = append(undecryptable, path)
+			return nil
+		}
+
+		files = append(files, file)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(undecryptable) > 0 {
+		return nil, fmt.Errorf(
+			"files decrypt with neither the current nor the new password; "+
+				"restore or remove them and try again: [%s]",
+			strings.Join(undecryptable, ", "),
+		)
+	}
+
+	return files, nil
+}
+
+// isQuarantined returns true if the given file of the given directory is
+// placed in a quarantine directory.
+func isQuarantined(dir string, path string) bool {
+	relativePath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	for _, name := range strings.Split(
+		filepath.Dir(relativePath),
+		string(filepath.Separator),
+	) {
+		if strings.HasSuffix(name, QuarantineDirSuffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileState describes a file of a directory for the purpose of detecting
+// directory modifications.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// directoryState returns the state of all files of the given directory,
+// keyed by their paths.
+func directoryState(dir string) (map[string]fileState, error) {
+	state := make(map[string]fileState)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		state[path] = fileState{
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot determine state of directory [%s]: [%w]",
+			dir,
+			err,
+		)
+	}
+
+	return state, nil
+}
+
+// swapDirectory moves the given directory to the backup path and moves
+// the replacement directory in its place. If the latter fails, the original
+// directory is moved back.
+func swapDirectory(dir string, replacementDir string, backupDir string) error {
+	if err := os.Rename(dir, backupDir); err != nil {
+		return fmt.Errorf("cannot move directory to backup: [%w]", err)
+	}
+
+	if err := os.Rename(replacementDir, dir); err != nil {
+		if restoreErr := os.Rename(backupDir, dir); restoreErr != nil {
+			return fmt.Errorf(
+				"cannot restore directory from backup [%s] after "+
+					"replacement failure [%v]: [%w]",
+				backupDir,
+				err,
+				restoreErr,
+			)
+		}
+
+		return fmt.Errorf("cannot replace directory: [%w]", err)
+	}
+
+	return nil
+}
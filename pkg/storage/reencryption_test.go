@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestReEncrypt(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer-1"),
+		"wallet-1",
+		"membership_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer-2"),
+		"wallet-2",
+		"membership_2",
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Archive("wallet-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workPersistence.Save(
+		[]byte("pre-params"),
+		"dkg",
+		"pre_params",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	backupDirs, err := storage.ReEncrypt("new-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "backup directories count", 2, len(backupDirs))
+	for _, backupDir := range backupDirs {
+		if _, err := os.Stat(backupDir); err != nil {
+			t.Errorf("backup directory [%s] not found: [%v]", backupDir, err)
+		}
+	}
+
+	reEncryptedStorage, err := Initialize(config, "new-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reEncryptedKeyStorePersistence, err :=
+		reEncryptedStorage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPersistedContent(
+		t,
+		map[string]string{"wallet-1/membership_1": "signer-1"},
+		reEncryptedKeyStorePersistence,
+	)
+
+	reEncryptedWorkPersistence, err :=
+		reEncryptedStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPersistedContent(
+		t,
+		map[string]string{"dkg/pre_params": "pre-params"},
+		reEncryptedWorkPersistence,
+	)
+
+	// The archived data must be re-encrypted as well.
+	archivedContent, err := decryptFile(
+		filepath.Join(
+			config.Dir,
+			keyStoreDirName,
+			"tbtc",
+			"archive",
+			"wallet-2",
+			"membership_2",
+		),
+		newEncryptionBox("new-password"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertStringsEqual(
+		t,
+		"archived content",
+		"signer-2",
+		string(archivedContent),
+	)
+}
+
+func TestReEncrypt_UndecryptableFile(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer-1"),
+		"wallet-1",
+		"membership_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Data encrypted with a different password cannot be re-encrypted.
+	otherStorage, err := Initialize(config, "other-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherWorkPersistence, err := otherStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherWorkPersistence.Save(
+		[]byte("pre-params"),
+		"dkg",
+		"pre_params",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = storage.ReEncrypt("new-password")
+	if err == nil || !strings.Contains(err.Error(), "decrypt with neither") {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	entries, err := os.ReadDir(config.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	// No staging nor backup directories should be left.
+	expectedNames := []string{keyStoreDirName, workDirName}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Errorf(
+			"unexpected storage directories\nexpected: [%v]\nactual:   [%v]",
+			expectedNames,
+			names,
+		)
+	}
+
+	// The keystore must still be readable with the old password.
+	assertPersistedContent(
+		t,
+		map[string]string{"wallet-1/membership_1": "signer-1"},
+		keyStorePersistence,
+	)
+}
+
+func TestReEncrypt_QuarantinedFile(t *testing.T) {
+	config := Config{Dir: t.TempDir()}
+
+	storage, err := Initialize(config, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Quarantined entries are moved as they are and may not decrypt with
+	// any key.
+	quarantinedRelativePath := filepath.Join(
+		"tbtc",
+		"preparams"+QuarantineDirSuffix,
+		"pp_corrupted",
+	)
+	quarantinedPath := filepath.Join(
+		config.Dir,
+		workDirName,
+		quarantinedRelativePath,
+	)
+	if err := os.MkdirAll(filepath.Dir(quarantinedPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(quarantinedPath, []byte("corrupted"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupDirs, err := storage.ReEncrypt("new-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		quarantinedPath,
+		filepath.Join(backupDirs[1], quarantinedRelativePath),
+	} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertStringsEqual(
+			t,
+			"quarantined file content",
+			"corrupted",
+			string(content),
+		)
+	}
+}
+
+func assertPersistedContent(
+	t *testing.T,
+	expectedContent map[string]string,
+	handle persistence.RWHandle,
+) {
+	descriptorsChan, errorsChan := handle.ReadAll()
+
+	content := make(map[string]string)
+	var contentErrs, readErrs []error
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			data, err := descriptor.Content()
+			if err != nil {
+				contentErrs = append(contentErrs, err)
+				continue
+			}
+
+			content[descriptor.Directory()+"/"+descriptor.Name()] = string(data)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			readErrs = append(readErrs, err)
+		}
+	}()
+
+	wg.Wait()
+
+	if errs := append(contentErrs, readErrs...); len(errs) > 0 {
+		t.Fatalf("unexpected errors: [%v]", errs)
+	}
+
+	if !reflect.DeepEqual(expectedContent, content) {
+		t.Errorf(
+			"unexpected content\nexpected: [%v]\nactual:   [%v]",
+			expectedContent,
+			content,
+		)
+	}
+}
@@ -2,16 +2,23 @@ package storage
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 
+	"github.com/ipfs/go-log"
+
 	"github.com/keep-network/keep-common/pkg/persistence"
 )
 
+var logger = log.Logger("keep-storage")
+
 // Config stores meta-info about keeping data on disk
 type Config struct {
 	// Path to the persistent storage directory on disk.
 	Dir string
+	// Backup configures periodic backups of the key store.
+	Backup BackupConfig
 }
 
 const (
@@ -25,6 +32,10 @@ const (
 	// lead to losing rewards as a result of inactivity but is not
 	// a protocol violation.
 	workDirName = "work"
+	// QuarantineDirSuffix is the suffix of directories holding entries that
+	// could not be read and were moved there as they were. Such entries may
+	// not decrypt with the storage key so they are never re-encrypted.
+	QuarantineDirSuffix = "_quarantine"
 )
 
 // Storage is a disk persistent storage for the client.
@@ -135,8 +146,46 @@ func (s *Storage) initializeWorkPersistence(parentDir string, dir string) (
 		return nil, fmt.Errorf("cannot create [%s] disk handle: [%w]", path, err)
 	}
 
-	return persistence.NewEncryptedBasicPersistence(
-		diskHandle,
-		s.encryptionPassword,
-	), nil
+	return &workPersistence{
+		BasicHandle: persistence.NewEncryptedBasicPersistence(
+			diskHandle,
+			s.encryptionPassword,
+		),
+		dir: path,
+	}, nil
+}
+
+// workPersistence is an encrypted basic handle of a work persistence
+// directory that can also move persisted entries between its
+// subdirectories.
+type workPersistence struct {
+	persistence.BasicHandle
+
+	dir string
+}
+
+// Move moves the entry with the given name from one subdirectory to another
+// without reading its content. It allows setting aside entries whose
+// content cannot be read or decrypted. The content is moved as-is, i.e. it
+// stays encrypted.
+func (wp *workPersistence) Move(
+	fromDirectory string,
+	name string,
+	toDirectory string,
+) error {
+	if err := persistence.EnsureDirectoryExists(
+		wp.dir,
+		toDirectory,
+	); err != nil {
+		return fmt.Errorf(
+			"cannot create directory [%s]: [%w]",
+			toDirectory,
+			err,
+		)
+	}
+
+	return os.Rename(
+		filepath.Join(wp.dir, fromDirectory, name),
+		filepath.Join(wp.dir, toDirectory, name),
+	)
 }
@@ -2,6 +2,7 @@ package tbtc
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"sync"
 
@@ -80,6 +81,20 @@ func (lbc *localBitcoinChain) GetBlockHeader(
 	panic("not implemented")
 }
 
+func (lbc *localBitcoinChain) WatchReorgs(
+	ctx context.Context,
+) <-chan *bitcoin.ReorgEvent {
+	// The local chain never reorganizes.
+	reorgsChan := make(chan *bitcoin.ReorgEvent)
+
+	go func() {
+		<-ctx.Done()
+		close(reorgsChan)
+	}()
+
+	return reorgsChan
+}
+
 func (lbc *localBitcoinChain) GetTransactionMerkleProof(
 	transactionHash bitcoin.Hash,
 	blockHeight uint,
@@ -123,6 +138,63 @@ func (lbc *localBitcoinChain) GetTransactionsForPublicKeyHash(
 	return matchingTransactions, nil
 }
 
+func (lbc *localBitcoinChain) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	lbc.transactionsMutex.Lock()
+	defer lbc.transactionsMutex.Unlock()
+
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each transaction is considered to be included in a separate block,
+	// according to the order of transactions.
+	items := make([]*bitcoin.TransactionHistoryItem, 0)
+	transactions := make(map[bitcoin.Hash]*bitcoin.Transaction)
+
+	for i, transaction := range lbc.transactions {
+		for _, output := range transaction.Outputs {
+			script := output.PublicKeyScript
+			if bytes.Equal(script, p2pkh) || bytes.Equal(script, p2wpkh) {
+				transactionHash := transaction.Hash()
+				items = append(items, &bitcoin.TransactionHistoryItem{
+					TransactionHash: transactionHash,
+					BlockHeight:     uint(i + 1),
+				})
+				transactions[transactionHash] = transaction
+				break
+			}
+		}
+	}
+
+	selected, nextCursor, err := bitcoin.SelectTransactionHistoryPage(
+		items,
+		uint(len(lbc.transactions)),
+		query,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &bitcoin.TransactionHistoryPage{
+		Transactions: make([]*bitcoin.Transaction, len(selected)),
+		NextCursor:   nextCursor,
+	}
+	for i, item := range selected {
+		page.Transactions[i] = transactions[item.TransactionHash]
+	}
+
+	return page, nil
+}
+
 func (lbc *localBitcoinChain) GetTxHashesForPublicKeyHash(
 	publicKeyHash [20]byte,
 ) ([]bitcoin.Hash, error) {
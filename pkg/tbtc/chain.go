@@ -106,6 +106,10 @@ type DistributedKeyGenerationChain interface {
 		startBlock uint64,
 	) (dkg.ResultSignatureHash, error)
 
+	// CalculateDKGResultHash calculates the hash the chain identifies the
+	// given DKG result with.
+	CalculateDKGResultHash(result *DKGChainResult) (DKGChainResultHash, error)
+
 	// IsDKGResultValid checks whether the submitted DKG result is valid from
 	// the on-chain contract standpoint.
 	IsDKGResultValid(dkgResult *DKGChainResult) (bool, error)
@@ -171,6 +171,10 @@ func (lc *localChain) IsOperatorUpToDate() (bool, error) {
 	panic("unsupported")
 }
 
+func (lc *localChain) OperatorWeight() (*big.Int, error) {
+	panic("unsupported")
+}
+
 func (lc *localChain) JoinSortitionPool() error {
 	panic("unsupported")
 }
@@ -191,6 +195,14 @@ func (lc *localChain) RestoreRewardEligibility() error {
 	panic("unsupported")
 }
 
+func (lc *localChain) RewardEligibilityRestorableAt() (time.Time, error) {
+	panic("unsupported")
+}
+
+func (lc *localChain) RewardsIneligibilityReason(blockNumber uint64) (string, error) {
+	panic("unsupported")
+}
+
 func (lc *localChain) IsChaosnetActive() (bool, error) {
 	panic("unsupported")
 }
@@ -199,6 +211,12 @@ func (lc *localChain) IsBetaOperator() (bool, error) {
 	panic("unsupported")
 }
 
+func (lc *localChain) OnOperatorStatusChanged(
+	handler func(event string, blockNumber uint64),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
 func (lc *localChain) GetOperatorID(
 	operatorAddress chain.Address,
 ) (chain.OperatorID, error) {
@@ -398,6 +416,12 @@ func (lc *localChain) CalculateDKGResultSignatureHash(
 	return sha3.Sum256([]byte(encoded)), nil
 }
 
+func (lc *localChain) CalculateDKGResultHash(
+	result *DKGChainResult,
+) (DKGChainResultHash, error) {
+	return computeDkgChainResultHash(result), nil
+}
+
 func (lc *localChain) IsDKGResultValid(dkgResult *DKGChainResult) (bool, error) {
 	lc.dkgMutex.Lock()
 	defer lc.dkgMutex.Unlock()
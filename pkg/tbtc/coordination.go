@@ -212,8 +212,25 @@ type CoordinationProposalGenerator interface {
 	// should be checked for the given coordination window. The generator is
 	// expected to return a proposal for the first action from the checklist
 	// that is valid for the given wallet's state. If none of the actions are
-	// valid, the generator should return a no-op proposal.
-	Generate(request *CoordinationProposalRequest) (CoordinationProposal, error)
+	// valid, the generator should return a no-op proposal. The given context
+	// is done once the proposal is no longer needed, e.g. the active phase
+	// of the coordination window ended.
+	Generate(
+		ctx context.Context,
+		request *CoordinationProposalRequest,
+	) (CoordinationProposal, error)
+}
+
+// CoordinationProposalObserver is an optional interface that can be
+// implemented by a CoordinationProposalGenerator to get notified about
+// proposals successfully executed by wallets controlled by the node.
+type CoordinationProposalObserver interface {
+	// ProposalExecuted is called once the wallet with the given public key
+	// hash successfully executed the given proposal.
+	ProposalExecuted(
+		walletPublicKeyHash [20]byte,
+		proposal CoordinationProposal,
+	)
 }
 
 // CoordinationProposal represents a single action proposal for the given wallet.
@@ -579,6 +596,7 @@ func (ce *coordinationExecutor) executeLeaderRoutine(
 	walletPublicKeyHash := ce.walletPublicKeyHash()
 
 	proposal, err := ce.proposalGenerator.Generate(
+		ctx,
 		&CoordinationProposalRequest{
 			WalletPublicKeyHash: walletPublicKeyHash,
 			WalletOperators:     ce.coordinatedWallet.signingGroupOperators,
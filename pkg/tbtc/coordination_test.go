@@ -1178,6 +1178,7 @@ func newMockCoordinationProposalGenerator(
 }
 
 func (mcpg *mockCoordinationProposalGenerator) Generate(
+	ctx context.Context,
 	request *CoordinationProposalRequest,
 ) (CoordinationProposal, error) {
 	return mcpg.delegate(request.WalletPublicKeyHash, request.ActionsChecklist)
@@ -193,6 +193,7 @@ func (dsa *depositSweepAction) execute() error {
 
 	sweepTx, err := dsa.transactionExecutor.signTransaction(
 		signTxLogger,
+		ActionDepositSweep,
 		unsignedSweepTx,
 		dsa.proposalProcessingStartBlock,
 		dsa.proposalExpiryBlock-dsa.signingTimeoutSafetyMarginBlocks,
@@ -424,6 +425,10 @@ func (dsa *depositSweepAction) actionType() WalletActionType {
 	return ActionDepositSweep
 }
 
+func (dsa *depositSweepAction) executedProposal() CoordinationProposal {
+	return dsa.proposal
+}
+
 // assembleDepositSweepTransaction constructs an unsigned deposit sweep Bitcoin
 // transaction.
 //
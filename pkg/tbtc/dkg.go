@@ -7,17 +7,21 @@ import (
 	"golang.org/x/exp/maps"
 	"math/big"
 	"sort"
+	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-common/pkg/persistence"
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/announcer"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -40,10 +44,6 @@ const (
 	// submission. Once the period elapses, the DKG state is checked to confirm
 	// the challenge was accepted successfully.
 	dkgResultChallengeConfirmationBlocks = 20
-	// dkgAttemptsLimit determines the maximum number of attempts to execute
-	// the DKG protocol. If the limit is reached, the protocol execution is
-	// aborted.
-	dkgAttemptsLimit = 1
 )
 
 // dkgExecutor is a component responsible for the full execution of ECDSA
@@ -64,6 +64,24 @@ type dkgExecutor struct {
 	waitForBlockFn waitForBlockFn
 
 	tecdsaExecutor *dkg.Executor
+
+	// pendingApprovals holds DKG results whose approval is scheduled by
+	// this node so the approvals survive node restarts.
+	pendingApprovals *pendingDkgApprovalsStore
+
+	// misbehaviorEvidence persists evidence of group members misbehavior
+	// observed during DKG and DKG result publication.
+	misbehaviorEvidence *misbehaviorEvidenceStore
+
+	// lastDkgParameters holds DKG parameters read from the chain most
+	// recently. It is used to report parameter updates.
+	lastDkgParameters      *DKGParameters
+	lastDkgParametersMutex sync.Mutex
+
+	// missedDkgCount is the number of DKGs the node's operator was selected
+	// for but which the node could not join because it was offline when
+	// they started. It must be accessed atomically.
+	missedDkgCount uint64
 }
 
 // newDkgExecutor creates a new instance of dkgExecutor struct. There should
@@ -78,6 +96,7 @@ func newDkgExecutor(
 	protocolLatch *generator.ProtocolLatch,
 	config Config,
 	workPersistence persistence.BasicHandle,
+	misbehaviorEvidence *misbehaviorEvidenceStore,
 	scheduler *generator.Scheduler,
 	waitForBlockFn waitForBlockFn,
 ) *dkgExecutor {
@@ -102,6 +121,10 @@ func newDkgExecutor(
 		protocolLatch:   protocolLatch,
 		tecdsaExecutor:  tecdsaExecutor,
 		waitForBlockFn:  waitForBlockFn,
+		pendingApprovals: newPendingDkgApprovalsStore(
+			workPersistence,
+		),
+		misbehaviorEvidence: misbehaviorEvidence,
 	}
 }
 
@@ -110,6 +133,38 @@ func (de *dkgExecutor) preParamsCount() int {
 	return de.tecdsaExecutor.PreParamsCount()
 }
 
+// preParamsTargetCount returns the current target count of the ECDSA DKG
+// pre-parameters.
+func (de *dkgExecutor) preParamsTargetCount() int {
+	return de.tecdsaExecutor.PreParamsPoolTargetSize()
+}
+
+// preParamsPool returns the ECDSA DKG pre-parameters pool.
+func (de *dkgExecutor) preParamsPool() PreParamsPool {
+	return &preParamsPool{executor: de.tecdsaExecutor}
+}
+
+// preParamsPoolStats returns the current statistics of the ECDSA DKG
+// pre-parameters pool.
+func (de *dkgExecutor) preParamsPoolStats() *dkg.PreParamsPoolStats {
+	return de.tecdsaExecutor.PreParamsPoolStats()
+}
+
+// preParamsStorageInfo returns the report of reading the persisted ECDSA
+// DKG pre-parameters on the node start, including entries discarded as
+// corrupted or duplicated.
+func (de *dkgExecutor) preParamsStorageInfo() clientinfo.ApplicationInfo {
+	report := de.tecdsaExecutor.PreParamsStorageReport()
+	if report == nil {
+		return clientinfo.ApplicationInfo{}
+	}
+
+	return clientinfo.ApplicationInfo{
+		"loaded":    report.Loaded,
+		"discarded": report.Discarded,
+	}
+}
+
 // executeDkgIfEligible is the main function of dkgExecutor. It performs the
 // full execution of ECDSA Distributed Key Generation: determining members
 // selected to the signing group, executing off-chain protocol, and publishing
@@ -270,7 +325,7 @@ func (de *dkgExecutor) generateSigningGroup(
 		return
 	}
 
-	dkgParameters, err := de.chain.DKGParameters()
+	dkgParameters, err := de.dkgParameters()
 	if err != nil {
 		dkgLogger.Errorf("cannot get DKG parameters: [%v]", err)
 		return
@@ -327,7 +382,11 @@ func (de *dkgExecutor) generateSigningGroup(
 				groupSelectionResult.OperatorsAddresses,
 				de.groupParameters,
 				announcer,
-				dkgAttemptsLimit,
+				dkgAttemptsLimit(
+					dkgParameters.SubmissionTimeoutBlocks,
+					delayBlocks,
+					de.groupParameters.GroupSize,
+				),
 			)
 
 			result, err := retryLoop.start(
@@ -373,6 +432,10 @@ func (de *dkgExecutor) generateSigningGroup(
 						attempt.excludedMembersIndexes,
 						broadcastChannel,
 						membershipValidator,
+						de.misbehaviorEvidence.recorder(
+							misbehaviorEvidenceProtocolDkg,
+							groupSelectionResult.OperatorsAddresses,
+						),
 					)
 					if err != nil {
 						dkgAttemptLogger.Errorf(
@@ -405,6 +468,9 @@ func (de *dkgExecutor) generateSigningGroup(
 				return
 			}
 
+			// The signer must be persisted before the result is published.
+			// Otherwise, the node could crash after the publication and
+			// lose the key share of a wallet registered on-chain.
 			signer, err := de.registerSigner(
 				result,
 				memberIndex,
@@ -412,10 +478,12 @@ func (de *dkgExecutor) generateSigningGroup(
 			)
 			if err != nil {
 				dkgLogger.Errorf(
-					"[member:%v] failed to register signing group member: [%v]",
+					"[member:%v] failed to register signing group member: "+
+						"[%v]; aborting DKG result publication",
 					memberIndex,
 					err,
 				)
+				return
 			}
 
 			dkgLogger.Infof("registered %s", signer)
@@ -452,6 +520,36 @@ func (de *dkgExecutor) generateSigningGroup(
 	}
 }
 
+// dkgAttemptsLimit determines the maximum number of attempts to execute
+// the DKG protocol. Failed attempts, e.g. due to network issues or missed
+// messages, are retried with misbehaving members excluded as long as the
+// retried attempt can complete before the DKG result submission timeout,
+// leaving enough blocks for all members to submit the result. At least one
+// attempt is always executed. If the limit is reached, the protocol
+// execution is aborted.
+func dkgAttemptsLimit(
+	submissionTimeoutBlocks uint64,
+	delayBlocks uint64,
+	groupSize int,
+) uint {
+	publicationBlocks := uint64(groupSize) * dkgResultSubmissionDelayStepBlocks
+	reservedBlocks := delayBlocks + publicationBlocks
+
+	if submissionTimeoutBlocks <= reservedBlocks {
+		return 1
+	}
+
+	limit := uint(
+		(submissionTimeoutBlocks - reservedBlocks) /
+			uint64(dkgAttemptMaximumBlocks()),
+	)
+	if limit < 1 {
+		return 1
+	}
+
+	return limit
+}
+
 // registerSigner determines the final signing group shape and persists the
 // generated signer with a unique key share. Note that the final group members
 // may differ from the ones returned by the sortition pool if there was any
@@ -535,6 +633,10 @@ func (de *dkgExecutor) publishDkgResult(
 			de.waitForBlockFn,
 		),
 		dkgResult,
+		de.misbehaviorEvidence.recorder(
+			misbehaviorEvidenceProtocolDkgResultPublication,
+			groupSelectionResult.OperatorsAddresses,
+		),
 	)
 }
 
@@ -653,42 +755,53 @@ func (de *dkgExecutor) executeDkgValidation(
 
 	dkgLogger.Infof("scheduling DKG result approval")
 
-	parameters, err := de.chain.DKGParameters()
+	if de.pendingApprovals != nil {
+		err := de.pendingApprovals.add(&pendingDkgApproval{
+			Seed:            seed,
+			SubmissionBlock: submissionBlock,
+			Result:          result,
+			ResultHash:      resultHash,
+		})
+		if err != nil {
+			// Not a big deal, the approval is still scheduled. It just
+			// won't be resumed if the node restarts in the meantime.
+			dkgLogger.Warnf("cannot persist pending DKG approval: [%v]", err)
+		}
+	}
+
+	parameters, err := de.dkgParameters()
 	if err != nil {
 		dkgLogger.Errorf("cannot get current DKG parameters: [%v]", err)
 		return
 	}
 
-	// The challenge period starts at the result submission block and lasts
-	// for challengePeriodBlocks.
-	challengePeriodEndBlock := submissionBlock + parameters.ChallengePeriodBlocks
-	// The approval is possible one block after the challenge period end.
-	// The result submitter has precedence for approvePrecedencePeriodBlocks.
-	approvePrecedencePeriodStartBlock := challengePeriodEndBlock + 1
-	// Everyone else can approve once the precedence period ends.
-	approvePeriodStartBlock := approvePrecedencePeriodStartBlock +
-		parameters.ApprovePrecedencePeriodBlocks
+	approvalsWg := sync.WaitGroup{}
+	approvalsWg.Add(len(memberIndexes))
+
+	// Once all members are done, the approval is no longer pending,
+	// no matter whether it succeeded or not. Approvals interrupted by
+	// a node restart remain pending and are resumed upon the next start.
+	go func() {
+		approvalsWg.Wait()
+
+		if de.pendingApprovals == nil {
+			return
+		}
+
+		if err := de.pendingApprovals.remove(resultHash); err != nil {
+			dkgLogger.Warnf("cannot remove pending DKG approval: [%v]", err)
+		}
+	}()
 
 	for _, currentMemberIndex := range memberIndexes {
 		go func(memberIndex group.MemberIndex) {
-			var approveBlock uint64
-
-			if memberIndex == result.SubmitterMemberIndex {
-				// The submitter can approve earlier, during the precedence
-				// period.
-				approveBlock = approvePrecedencePeriodStartBlock
-			} else {
-				// Everyone else must approve after the precedence period ends.
-				// Each member preserves a delay according to their index
-				// to avoid simultaneous approval.
-				delayBlocks := uint64(memberIndex-1) * dkgResultApprovalDelayStepBlocks
-				approveBlock = approvePeriodStartBlock + delayBlocks
-			}
+			defer approvalsWg.Done()
 
-			dkgLogger.Infof(
-				"[member:%v] waiting for block [%v] to approve DKG result",
+			approveBlock := dkgResultApproveBlock(
+				parameters,
+				submissionBlock,
 				memberIndex,
-				approveBlock,
+				result.SubmitterMemberIndex,
 			)
 
 			ctx, cancelCtx := context.WithCancel(context.Background())
@@ -701,22 +814,75 @@ func (de *dkgExecutor) executeDkgValidation(
 			)
 			defer subscription.Unsubscribe()
 
-			err := de.waitForBlockFn(ctx, approveBlock)
-			if err != nil {
-				dkgLogger.Errorf(
-					"[member:%v] error while waiting for DKG result "+
-						"approve block: [%v]",
+			for {
+				dkgLogger.Infof(
+					"[member:%v] waiting for block [%v] to approve DKG result",
 					memberIndex,
-					err,
+					approveBlock,
 				)
-				return
+
+				err := de.waitForBlockFn(ctx, approveBlock)
+				if err != nil {
+					dkgLogger.Errorf(
+						"[member:%v] error while waiting for DKG result "+
+							"approve block: [%v]",
+						memberIndex,
+						err,
+					)
+					return
+				}
+
+				// If the context got cancelled that means the result was
+				// approved by someone else.
+				if ctx.Err() != nil {
+					dkgLogger.Infof(
+						"[member:%v] DKG result approved by someone else",
+						memberIndex,
+					)
+					return
+				}
+
+				// DKG parameters may have been updated by the governance
+				// while waiting. The chain enforces the current values so
+				// the approval must be postponed if the approve block
+				// moved forward.
+				currentParameters, err := de.dkgParameters()
+				if err != nil {
+					dkgLogger.Warnf(
+						"[member:%v] cannot refresh DKG parameters; "+
+							"approving with parameters read before: [%v]",
+						memberIndex,
+						err,
+					)
+					break
+				}
+
+				currentApproveBlock := dkgResultApproveBlock(
+					currentParameters,
+					submissionBlock,
+					memberIndex,
+					result.SubmitterMemberIndex,
+				)
+				if currentApproveBlock <= approveBlock {
+					break
+				}
+
+				approveBlock = currentApproveBlock
 			}
 
-			// If the context got cancelled that means the result was approved
-			// by someone else.
-			if ctx.Err() != nil {
-				dkgLogger.Infof(
-					"[member:%v] DKG result approved by someone else",
+			// A member that was not marked as misbehaved took part in
+			// generating the group key so this node must hold a signer of
+			// the group public key by now. Otherwise, the result does not
+			// match the local DKG outcome. Such a result cannot be challenged
+			// as it is valid on-chain but it is not approved by this node.
+			if !slices.Contains(result.MisbehavedMembersIndexes, memberIndex) &&
+				len(de.walletRegistry.getSigners(
+					unmarshalPublicKey(result.GroupPublicKey),
+				)) == 0 {
+				dkgLogger.Warnf(
+					"[member:%v] DKG result does not match the local DKG "+
+						"outcome; no signer of the group public key is "+
+						"held; skipping DKG result approval",
 					memberIndex,
 				)
 				return
@@ -737,6 +903,303 @@ func (de *dkgExecutor) executeDkgValidation(
 	}
 }
 
+// dkgResultApproveBlock returns the block at which the given member should
+// approve the DKG result submitted at the given block by the given submitter,
+// according to the given DKG parameters.
+func dkgResultApproveBlock(
+	parameters *DKGParameters,
+	submissionBlock uint64,
+	memberIndex group.MemberIndex,
+	submitterMemberIndex group.MemberIndex,
+) uint64 {
+	// The challenge period starts at the result submission block and lasts
+	// for challengePeriodBlocks.
+	challengePeriodEndBlock := submissionBlock + parameters.ChallengePeriodBlocks
+	// The approval is possible one block after the challenge period end.
+	// The result submitter has precedence for approvePrecedencePeriodBlocks.
+	approvePrecedencePeriodStartBlock := challengePeriodEndBlock + 1
+
+	if memberIndex == submitterMemberIndex {
+		// The submitter can approve earlier, during the precedence period.
+		return approvePrecedencePeriodStartBlock
+	}
+
+	// Everyone else can approve once the precedence period ends. Each member
+	// preserves a delay according to their index to avoid simultaneous
+	// approval.
+	approvePeriodStartBlock := approvePrecedencePeriodStartBlock +
+		parameters.ApprovePrecedencePeriodBlocks
+	delayBlocks := uint64(memberIndex-1) * dkgResultApprovalDelayStepBlocks
+
+	return approvePeriodStartBlock + delayBlocks
+}
+
+// dkgParameters reads the current DKG parameters from the chain. Parameters
+// are read each time they are needed so updates made by the governance take
+// effect without restarting the node. Updates are logged once noticed.
+func (de *dkgExecutor) dkgParameters() (*DKGParameters, error) {
+	parameters, err := de.chain.DKGParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	de.lastDkgParametersMutex.Lock()
+	defer de.lastDkgParametersMutex.Unlock()
+
+	if de.lastDkgParameters != nil && *de.lastDkgParameters != *parameters {
+		logger.Infof(
+			"DKG parameters updated; submission timeout: [%v] blocks, "+
+				"challenge period: [%v] blocks, approve precedence "+
+				"period: [%v] blocks",
+			parameters.SubmissionTimeoutBlocks,
+			parameters.ChallengePeriodBlocks,
+			parameters.ApprovePrecedencePeriodBlocks,
+		)
+	}
+
+	de.lastDkgParameters = parameters
+
+	return parameters, nil
+}
+
+// resumePendingApprovals resumes DKG result approvals scheduled before the
+// node restart. Approvals of results that are no longer awaiting approval,
+// i.e. the DKG is not in the challenge state anymore, are dropped. Approvals
+// whose persisted result does not hash to the persisted result hash are
+// dropped as well as the persisted data cannot be trusted.
+func (de *dkgExecutor) resumePendingApprovals() {
+	approvals := de.pendingApprovals.all()
+	if len(approvals) == 0 {
+		return
+	}
+
+	dkgState, err := de.chain.GetDKGState()
+	if err != nil {
+		logger.Errorf(
+			"cannot check DKG state to resume pending DKG approvals: [%v]",
+			err,
+		)
+		return
+	}
+
+	for _, approval := range approvals {
+		if dkgState != Challenge {
+			logger.Infof(
+				"dropping pending approval of DKG result with hash [0x%x]; "+
+					"DKG is not in the challenge state anymore",
+				approval.ResultHash,
+			)
+
+			if err := de.pendingApprovals.remove(approval.ResultHash); err != nil {
+				logger.Warnf(
+					"cannot remove pending approval of DKG result "+
+						"with hash [0x%x]: [%v]",
+					approval.ResultHash,
+					err,
+				)
+			}
+
+			continue
+		}
+
+		resultHash, err := de.chain.CalculateDKGResultHash(approval.Result)
+		if err != nil || resultHash != approval.ResultHash {
+			logger.Errorf(
+				"dropping pending approval of DKG result with hash [0x%x]; "+
+					"persisted result hash could not be verified: "+
+					"recomputed hash [0x%x], error [%v]",
+				approval.ResultHash,
+				resultHash,
+				err,
+			)
+
+			if err := de.pendingApprovals.remove(approval.ResultHash); err != nil {
+				logger.Warnf(
+					"cannot remove pending approval of DKG result "+
+						"with hash [0x%x]: [%v]",
+					approval.ResultHash,
+					err,
+				)
+			}
+
+			continue
+		}
+
+		logger.Infof(
+			"resuming pending approval of DKG result with hash [0x%x] "+
+				"submitted at block [%v]",
+			approval.ResultHash,
+			approval.SubmissionBlock,
+		)
+
+		go de.executeDkgValidation(
+			approval.Seed,
+			approval.SubmissionBlock,
+			approval.Result,
+			approval.ResultHash,
+		)
+	}
+}
+
+// recoverMissedDkg checks whether a DKG started while the node was offline
+// is still awaiting the result. Only live DKG started events trigger the
+// DKG participation so such a DKG would be otherwise missed. If there is
+// enough time left to complete the DKG, the node joins it if eligible.
+// Otherwise, if the node's operator was selected to the missed DKG, an error
+// is logged and the DKG is counted as missed. The deduplicator ensures the
+// DKG is not joined twice if the live event is seen as well.
+func (de *dkgExecutor) recoverMissedDkg(deduplicator *deduplicator) {
+	dkgState, err := de.chain.GetDKGState()
+	if err != nil {
+		logger.Errorf("cannot check DKG state to recover missed DKG: [%v]", err)
+		return
+	}
+
+	if dkgState != AwaitingResult {
+		return
+	}
+
+	dkgParameters, err := de.dkgParameters()
+	if err != nil {
+		logger.Errorf("cannot get DKG parameters to recover missed DKG: [%v]", err)
+		return
+	}
+
+	blockCounter, err := de.chain.BlockCounter()
+	if err != nil {
+		logger.Errorf("cannot get block counter to recover missed DKG: [%v]", err)
+		return
+	}
+
+	currentBlock, err := blockCounter.CurrentBlock()
+	if err != nil {
+		logger.Errorf("cannot get current block to recover missed DKG: [%v]", err)
+		return
+	}
+
+	// The DKG awaiting the result must have started within the submission
+	// timeout unless it has already timed out. Look one confirmation period
+	// further back to not miss events moved by a chain reorganization.
+	lookBackBlocks := dkgParameters.SubmissionTimeoutBlocks +
+		dkgStartedConfirmationBlocks
+	startBlock := uint64(0)
+	if currentBlock > lookBackBlocks {
+		startBlock = currentBlock - lookBackBlocks
+	}
+
+	pastEvents, err := de.chain.PastDKGStartedEvents(
+		&DKGStartedEventFilter{StartBlock: startBlock},
+	)
+	if err != nil {
+		logger.Errorf(
+			"cannot get past DKG started events to recover missed DKG: [%v]",
+			err,
+		)
+		return
+	}
+
+	if len(pastEvents) == 0 {
+		logger.Warnf(
+			"DKG is awaiting the result but no DKG started event was "+
+				"found since block [%v]; the DKG has likely timed out",
+			startBlock,
+		)
+		return
+	}
+
+	event := pastEvents[len(pastEvents)-1]
+
+	if ok := deduplicator.notifyDKGStarted(event.Seed); !ok {
+		return
+	}
+
+	dkgLogger := logger.With(
+		zap.String("seed", fmt.Sprintf("0x%x", event.Seed)),
+	)
+
+	if currentBlock < dkgJoinDeadlineBlock(
+		event.BlockNumber,
+		dkgParameters.SubmissionTimeoutBlocks,
+		de.groupParameters.GroupSize,
+	) {
+		dkgLogger.Infof(
+			"DKG started at block [%v] while the node was offline is "+
+				"still in progress; trying to join it",
+			event.BlockNumber,
+		)
+
+		// Use the same delay as for live events so the attempts of this
+		// node are scheduled at the same blocks as attempts of other
+		// members. Attempts that are already in the past are skipped by
+		// the retry loop as their announcement phase cannot gather enough
+		// ready members.
+		de.executeDkgIfEligible(
+			event.Seed,
+			event.BlockNumber,
+			dkgStartedConfirmationBlocks,
+		)
+		return
+	}
+
+	memberIndexes, _, err := de.checkEligibility(dkgLogger)
+	if err != nil {
+		dkgLogger.Errorf(
+			"could not check eligibility for missed DKG: [%v]",
+			err,
+		)
+		return
+	}
+
+	if len(memberIndexes) == 0 {
+		dkgLogger.Infof(
+			"not eligible for DKG started at block [%v] while the node "+
+				"was offline",
+			event.BlockNumber,
+		)
+		return
+	}
+
+	atomic.AddUint64(&de.missedDkgCount, 1)
+
+	dkgLogger.Errorf(
+		"missed DKG started at block [%v] while the node was offline; "+
+			"the operator was selected to control [%v] group members but "+
+			"too little time is left to join the DKG; the members will be "+
+			"considered inactive",
+		event.BlockNumber,
+		len(memberIndexes),
+	)
+}
+
+// missedDkgs returns the number of DKGs the node's operator was selected
+// for but which were missed because the node was offline when they started.
+func (de *dkgExecutor) missedDkgs() uint64 {
+	return atomic.LoadUint64(&de.missedDkgCount)
+}
+
+// dkgJoinDeadlineBlock returns the block before which a node can still join
+// the DKG started at the given block. This is the end of the announcement
+// phase of the last DKG attempt that fits into the DKG result submission
+// timeout. A node joining later cannot take part in any attempt.
+func dkgJoinDeadlineBlock(
+	startBlock uint64,
+	submissionTimeoutBlocks uint64,
+	groupSize int,
+) uint64 {
+	attemptsLimit := dkgAttemptsLimit(
+		submissionTimeoutBlocks,
+		dkgStartedConfirmationBlocks,
+		groupSize,
+	)
+
+	lastAttemptStartBlock := startBlock + dkgStartedConfirmationBlocks +
+		uint64(attemptsLimit-1)*uint64(dkgAttemptMaximumBlocks())
+
+	return lastAttemptStartBlock +
+		dkgAttemptAnnouncementDelayBlocks +
+		dkgAttemptAnnouncementActiveBlocks
+}
+
 // finalSigningGroup takes three parameters:
 //   - selectedOperators: Contains addresses of all selected operators. Slice
 //     length equals to the groupSize. Each element with index N corresponds
@@ -800,3 +1263,29 @@ func finalSigningGroup(
 
 	return finalOperators, finalMembersIndexes, nil
 }
+
+// preParamsPool exposes the pre-parameters pool of the ECDSA DKG executor
+// as PreParamsPool.
+type preParamsPool struct {
+	executor *dkg.Executor
+}
+
+func (ppp *preParamsPool) ParametersCount() int {
+	return ppp.executor.PreParamsCount()
+}
+
+func (ppp *preParamsPool) TargetSize() int {
+	return ppp.executor.PreParamsPoolTargetSize()
+}
+
+func (ppp *preParamsPool) SetTargetSize(targetSize int) error {
+	return ppp.executor.SetPreParamsPoolTargetSize(targetSize)
+}
+
+func (ppp *preParamsPool) Metrics() *generator.PoolMetrics {
+	return ppp.executor.PreParamsPoolMetrics()
+}
+
+func (ppp *preParamsPool) Flush() error {
+	return ppp.executor.FlushPreParams()
+}
@@ -0,0 +1,154 @@
+package tbtc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+// pendingDkgApprovalsDirectory is the name of the directory the pending DKG
+// approvals store keeps its entries in.
+const pendingDkgApprovalsDirectory = "dkg_approvals"
+
+// pendingDkgApproval is a valid DKG result submitted to the chain whose
+// approval is scheduled by the node.
+type pendingDkgApproval struct {
+	Seed            *big.Int
+	SubmissionBlock uint64
+	Result          *DKGChainResult
+	ResultHash      [32]byte
+}
+
+// pendingDkgApprovalsStore keeps track of DKG results whose approval is
+// scheduled by the node. The entries are persisted so a restarted node
+// resumes approvals scheduled before the restart instead of forgetting them.
+// An unapproved result blocks the activation of the new wallet.
+type pendingDkgApprovalsStore struct {
+	mutex sync.Mutex
+
+	persistence persistence.BasicHandle
+	approvals   map[string]*pendingDkgApproval
+}
+
+// newPendingDkgApprovalsStore creates a new store backed by the given
+// persistence handle and loads all approvals persisted so far. Approvals that
+// cannot be read are logged and skipped.
+func newPendingDkgApprovalsStore(
+	persistence persistence.BasicHandle,
+) *pendingDkgApprovalsStore {
+	store := &pendingDkgApprovalsStore{
+		persistence: persistence,
+		approvals:   make(map[string]*pendingDkgApproval),
+	}
+
+	store.load()
+
+	return store
+}
+
+func (pdas *pendingDkgApprovalsStore) load() {
+	persistenceutil.ReadAll(
+		pdas.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != pendingDkgApprovalsDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read pending DKG approval from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			approval := &pendingDkgApproval{}
+			if err := json.Unmarshal(content, approval); err != nil {
+				logger.Errorf(
+					"could not parse pending DKG approval from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			pdas.approvals[descriptor.Name()] = approval
+		},
+		func(err error) {
+			logger.Errorf(
+				"could not load pending DKG approvals from disk: [%v]",
+				err,
+			)
+		},
+	)
+}
+
+// add records the given approval as pending. Adding an approval of the same
+// result again overwrites the previous entry.
+func (pdas *pendingDkgApprovalsStore) add(approval *pendingDkgApproval) error {
+	pdas.mutex.Lock()
+	defer pdas.mutex.Unlock()
+
+	content, err := json.Marshal(approval)
+	if err != nil {
+		return fmt.Errorf("cannot marshal pending DKG approval: [%v]", err)
+	}
+
+	name := hex.EncodeToString(approval.ResultHash[:])
+
+	if err := pdas.persistence.Save(
+		content,
+		pendingDkgApprovalsDirectory,
+		name,
+	); err != nil {
+		return fmt.Errorf("cannot save pending DKG approval: [%v]", err)
+	}
+
+	pdas.approvals[name] = approval
+
+	return nil
+}
+
+// remove removes the approval of the result with the given hash. Removing
+// an unknown approval is a no-op.
+func (pdas *pendingDkgApprovalsStore) remove(resultHash [32]byte) error {
+	pdas.mutex.Lock()
+	defer pdas.mutex.Unlock()
+
+	name := hex.EncodeToString(resultHash[:])
+
+	if _, ok := pdas.approvals[name]; !ok {
+		return nil
+	}
+
+	if err := pdas.persistence.Delete(
+		pendingDkgApprovalsDirectory,
+		name,
+	); err != nil {
+		return fmt.Errorf("cannot delete pending DKG approval: [%v]", err)
+	}
+
+	delete(pdas.approvals, name)
+
+	return nil
+}
+
+// all returns all pending approvals.
+func (pdas *pendingDkgApprovalsStore) all() []*pendingDkgApproval {
+	pdas.mutex.Lock()
+	defer pdas.mutex.Unlock()
+
+	approvals := make([]*pendingDkgApproval, 0, len(pdas.approvals))
+	for _, approval := range pdas.approvals {
+		approvals = append(approvals, approval)
+	}
+
+	return approvals
+}
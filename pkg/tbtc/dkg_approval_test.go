@@ -0,0 +1,134 @@
+package tbtc
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestPendingDkgApprovalsStore(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	approval := &pendingDkgApproval{
+		Seed:            big.NewInt(100),
+		SubmissionBlock: 200,
+		Result: &DKGChainResult{
+			SubmitterMemberIndex:     1,
+			GroupPublicKey:           []byte{0x04, 0x01, 0x02},
+			MisbehavedMembersIndexes: []group.MemberIndex{2, 5},
+			Signatures:               []byte{0x03, 0x04},
+			SigningMembersIndexes:    []group.MemberIndex{1, 3, 4},
+			Members:                  chain.OperatorIDs{1, 2, 3, 4, 5},
+			MembersHash:              [32]byte{0x05},
+		},
+		ResultHash: [32]byte{0x06},
+	}
+
+	store := newPendingDkgApprovalsStore(persistenceHandle)
+
+	err := store.add(approval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The approval should be loaded by a store created after a restart.
+	restartedStore := newPendingDkgApprovalsStore(persistenceHandle)
+
+	approvals := restartedStore.all()
+	testutils.AssertIntsEqual(t, "approvals count", 1, len(approvals))
+
+	if !reflect.DeepEqual(approval, approvals[0]) {
+		t.Errorf(
+			"unexpected approval\nexpected: %+v\nactual:   %+v",
+			approval,
+			approvals[0],
+		)
+	}
+
+	err = restartedStore.remove(approval.ResultHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing an unknown approval should be a no-op.
+	err = restartedStore.remove(approval.ResultHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"approvals count after restart",
+		0,
+		len(newPendingDkgApprovalsStore(persistenceHandle).all()),
+	)
+}
+
+func TestDkgExecutor_ResumePendingApprovals_NotInChallenge(t *testing.T) {
+	localChain := Connect()
+
+	pendingApprovals := newPendingDkgApprovalsStore(&mockPersistenceHandle{})
+
+	err := pendingApprovals.add(&pendingDkgApproval{
+		Seed:            big.NewInt(100),
+		SubmissionBlock: 200,
+		Result:          &DKGChainResult{},
+		ResultHash:      [32]byte{0x01},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setting only the fields really needed for this test.
+	dkgExecutor := &dkgExecutor{
+		chain:            localChain,
+		pendingApprovals: pendingApprovals,
+	}
+
+	// The local chain is idle so the approval is no longer awaited.
+	dkgExecutor.resumePendingApprovals()
+
+	testutils.AssertIntsEqual(
+		t,
+		"approvals count",
+		0,
+		len(pendingApprovals.all()),
+	)
+}
+
+func TestDkgExecutor_ResumePendingApprovals_ResultHashMismatch(t *testing.T) {
+	localChain := Connect()
+	localChain.dkgState = Challenge
+
+	pendingApprovals := newPendingDkgApprovalsStore(&mockPersistenceHandle{})
+
+	err := pendingApprovals.add(&pendingDkgApproval{
+		Seed:            big.NewInt(100),
+		SubmissionBlock: 200,
+		Result:          &DKGChainResult{GroupPublicKey: []byte{0x02}},
+		// Does not match the hash of the result.
+		ResultHash: [32]byte{0x01},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setting only the fields really needed for this test.
+	dkgExecutor := &dkgExecutor{
+		chain:            localChain,
+		pendingApprovals: pendingApprovals,
+	}
+
+	dkgExecutor.resumePendingApprovals()
+
+	testutils.AssertIntsEqual(
+		t,
+		"approvals count",
+		0,
+		len(pendingApprovals.all()),
+	)
+}
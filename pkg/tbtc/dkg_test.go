@@ -89,7 +89,10 @@ func TestDkgExecutor_RegisterSigner(t *testing.T) {
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
 			persistenceHandle := &mockPersistenceHandle{}
-			walletRegistry := newWalletRegistry(persistenceHandle)
+			walletRegistry, err := newWalletRegistry(persistenceHandle)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			dkgExecutor := &dkgExecutor{
 				// setting only the fields really needed for this test
@@ -191,6 +194,7 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 	var tests = map[string]struct {
 		submitterMemberIndex     group.MemberIndex
 		resultValid              bool
+		signerRegistered         bool
 		rejectedApprovalsIndexes []int
 		expectedEvent            interface{}
 		expectedDkgState         DKGState
@@ -198,6 +202,7 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 		"result approved by the submitter": {
 			submitterMemberIndex: group.MemberIndex(1),
 			resultValid:          true,
+			signerRegistered:     true,
 			expectedEvent: &DKGResultApprovedEvent{
 				ResultHash: sha3.Sum256(groupPublicKey),
 				Approver:   "",
@@ -209,6 +214,7 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 		"result approved by a non-submitter": {
 			submitterMemberIndex: group.MemberIndex(1),
 			resultValid:          true,
+			signerRegistered:     true,
 			// Reject the first approval (with index 0) that will be made by
 			// member 1 (the submitter) in order to force the member 2 to
 			// approve after the precedence period.
@@ -223,6 +229,13 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 			},
 			expectedDkgState: Idle,
 		},
+		"result not matching the local outcome": {
+			submitterMemberIndex: group.MemberIndex(1),
+			resultValid:          true,
+			signerRegistered:     false,
+			expectedEvent:        nil,
+			expectedDkgState:     Challenge,
+		},
 		"result challenged": {
 			submitterMemberIndex: group.MemberIndex(1),
 			resultValid:          false,
@@ -316,6 +329,27 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			walletRegistry, err := newWalletRegistry(&mockPersistenceHandle{})
+
+			if err != nil {
+
+				t.Fatal(err)
+
+			}
+			if test.signerRegistered {
+				err = walletRegistry.registerSigner(
+					newSigner(
+						groupPublicKey,
+						operatorsAddresses,
+						group.MemberIndex(1),
+						tecdsaDkgResult.PrivateKeyShare,
+					),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			// Setting only the fields really needed for this test.
 			dkgExecutor := &dkgExecutor{
 				groupParameters: groupParameters,
@@ -324,6 +358,7 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 				},
 				operatorAddress: operatorAddress,
 				chain:           localChain,
+				walletRegistry:  walletRegistry,
 				waitForBlockFn:  testWaitForBlockFn(localChain),
 			}
 
@@ -347,10 +382,17 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 				dkgResultSubmittedEvent.ResultHash,
 			)
 
+			// If no event is expected, wait long enough for the submitter's
+			// approval block to be mined.
+			eventTimeout := 1 * time.Minute
+			if test.expectedEvent == nil {
+				eventTimeout = 15 * time.Second
+			}
+
 			var event interface{}
 			select {
 			case event = <-eventChan:
-			case <-time.After(1 * time.Minute):
+			case <-time.After(eventTimeout):
 			}
 
 			if !reflect.DeepEqual(test.expectedEvent, event) {
@@ -378,6 +420,151 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 	}
 }
 
+func TestDkgAttemptsLimit(t *testing.T) {
+	// Single attempt takes 216 blocks, and the result publication of
+	// a group of 100 members takes 300 blocks.
+	var tests = map[string]struct {
+		submissionTimeoutBlocks uint64
+		delayBlocks             uint64
+		groupSize               int
+		expectedLimit           uint
+	}{
+		"window fits one attempt": {
+			submissionTimeoutBlocks: 536,
+			delayBlocks:             20,
+			groupSize:               100,
+			expectedLimit:           1,
+		},
+		"window fits three attempts": {
+			submissionTimeoutBlocks: 1000,
+			delayBlocks:             20,
+			groupSize:               100,
+			expectedLimit:           3,
+		},
+		"window shorter than one attempt": {
+			submissionTimeoutBlocks: 300,
+			delayBlocks:             20,
+			groupSize:               100,
+			expectedLimit:           1,
+		},
+		"window shorter than the reserved blocks": {
+			submissionTimeoutBlocks: 10,
+			delayBlocks:             20,
+			groupSize:               100,
+			expectedLimit:           1,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			limit := dkgAttemptsLimit(
+				test.submissionTimeoutBlocks,
+				test.delayBlocks,
+				test.groupSize,
+			)
+
+			testutils.AssertUintsEqual(
+				t,
+				"attempts limit",
+				uint64(test.expectedLimit),
+				uint64(limit),
+			)
+		})
+	}
+}
+
+func TestDkgJoinDeadlineBlock(t *testing.T) {
+	// Single attempt takes 216 blocks, its announcement phase ends 11 blocks
+	// after the attempt start, and the result publication of a group of 100
+	// members takes 300 blocks. Attempts start 20 blocks after the DKG start.
+	var tests = map[string]struct {
+		startBlock              uint64
+		submissionTimeoutBlocks uint64
+		expectedDeadlineBlock   uint64
+	}{
+		"window fits one attempt": {
+			startBlock:              1000,
+			submissionTimeoutBlocks: 536,
+			expectedDeadlineBlock:   1031, // 1000 + 20 + 11
+		},
+		"window fits three attempts": {
+			startBlock:              1000,
+			submissionTimeoutBlocks: 1000,
+			expectedDeadlineBlock:   1463, // 1000 + 20 + 2 * 216 + 11
+		},
+		"window shorter than one attempt": {
+			startBlock:              1000,
+			submissionTimeoutBlocks: 300,
+			expectedDeadlineBlock:   1031, // 1000 + 20 + 11
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			deadlineBlock := dkgJoinDeadlineBlock(
+				test.startBlock,
+				test.submissionTimeoutBlocks,
+				100,
+			)
+
+			testutils.AssertUintsEqual(
+				t,
+				"deadline block",
+				test.expectedDeadlineBlock,
+				deadlineBlock,
+			)
+		})
+	}
+}
+
+func TestDkgResultApproveBlock(t *testing.T) {
+	parameters := &DKGParameters{
+		SubmissionTimeoutBlocks:       10,
+		ChallengePeriodBlocks:         15,
+		ApprovePrecedencePeriodBlocks: 5,
+	}
+
+	var tests = map[string]struct {
+		memberIndex          group.MemberIndex
+		submitterMemberIndex group.MemberIndex
+		expectedApproveBlock uint64
+	}{
+		"submitter": {
+			memberIndex:          3,
+			submitterMemberIndex: 3,
+			expectedApproveBlock: 116, // 100 + 15 + 1
+		},
+		"first non-submitter": {
+			memberIndex:          1,
+			submitterMemberIndex: 3,
+			expectedApproveBlock: 121, // 100 + 15 + 1 + 5
+		},
+		"subsequent non-submitter": {
+			memberIndex:          4,
+			submitterMemberIndex: 3,
+			expectedApproveBlock: 166, // 100 + 15 + 1 + 5 + 3 * 15
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			approveBlock := dkgResultApproveBlock(
+				parameters,
+				100,
+				test.memberIndex,
+				test.submitterMemberIndex,
+			)
+
+			testutils.AssertUintsEqual(
+				t,
+				"approve block",
+				test.expectedApproveBlock,
+				approveBlock,
+			)
+		})
+	}
+}
+
 func TestFinalSigningGroup(t *testing.T) {
 	groupParameters := &GroupParameters{
 		GroupSize:       5,
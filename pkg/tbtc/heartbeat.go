@@ -42,6 +42,7 @@ func (hp *HeartbeatProposal) ValidityBlocks() uint64 {
 type heartbeatSigningExecutor interface {
 	sign(
 		ctx context.Context,
+		actionType WalletActionType,
 		message *big.Int,
 		startBlock uint64,
 	) (*tecdsa.Signature, uint64, error)
@@ -111,7 +112,12 @@ func (ha *heartbeatAction) execute() error {
 	)
 	defer cancelHeartbeatCtx()
 
-	signature, _, err := ha.signingExecutor.sign(heartbeatCtx, messageToSign, ha.startBlock)
+	signature, _, err := ha.signingExecutor.sign(
+		heartbeatCtx,
+		ActionHeartbeat,
+		messageToSign,
+		ha.startBlock,
+	)
 	if err != nil {
 		return fmt.Errorf("cannot sign heartbeat message: [%v]", err)
 	}
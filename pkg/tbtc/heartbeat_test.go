@@ -134,6 +134,7 @@ type mockHeartbeatSigningExecutor struct {
 
 func (mhse *mockHeartbeatSigningExecutor) sign(
 	ctx context.Context,
+	actionType WalletActionType,
 	message *big.Int,
 	startBlock uint64,
 ) (*tecdsa.Signature, uint64, error) {
@@ -0,0 +1,319 @@
+package tbtc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/common"
+)
+
+// misbehaviorEvidenceDirectory is the name of the directory the misbehavior
+// evidence store keeps its entries in.
+const misbehaviorEvidenceDirectory = "misbehavior_evidence"
+
+const (
+	// misbehaviorEvidenceMaxAge is the time after which recorded evidence is
+	// deleted. Evidence is useful only for a limited time, e.g. until the
+	// misbehaving operator is no longer staking.
+	misbehaviorEvidenceMaxAge = 90 * 24 * time.Hour
+	// misbehaviorEvidenceMaxCount is the maximum number of evidence entries
+	// kept. Oldest entries are deleted first. Evidence messages may be
+	// large so the cap bounds the storage used by evidence, even if peers
+	// misbehave on purpose.
+	misbehaviorEvidenceMaxCount = 1000
+)
+
+// Names of protocols misbehavior evidence can be recorded for.
+const (
+	misbehaviorEvidenceProtocolDkg                  = "dkg"
+	misbehaviorEvidenceProtocolDkgResultPublication = "dkg_result_publication"
+	misbehaviorEvidenceProtocolSigning              = "signing"
+)
+
+// MisbehaviorEvidence is an evidence of misbehavior of a group member
+// observed by the node during DKG, DKG result publication, or signing. It can
+// be used to accuse the misbehaving operator on-chain or to resolve disputes
+// off-chain.
+type MisbehaviorEvidence struct {
+	// Protocol is the name of the protocol the misbehavior was observed in.
+	Protocol string
+	// SessionID is the identifier of the protocol session. It is built from
+	// the DKG seed or the signed message and the attempt number.
+	SessionID string
+	// ReporterMemberIndex is the index of the node's member that observed
+	// the misbehavior.
+	ReporterMemberIndex group.MemberIndex
+	// AccusedMemberIndex is the index of the misbehaving member.
+	AccusedMemberIndex group.MemberIndex
+	// AccusedOperator is the address of the operator controlling the
+	// misbehaving member.
+	AccusedOperator chain.Address
+	// Reason describes the observed misbehavior.
+	Reason string
+	// MessageType is the type of the message proving the misbehavior.
+	MessageType string
+	// Message is the marshaled message sent by the misbehaving member.
+	Message []byte
+	// SignedEnvelope is the marshaled network envelope carrying the message,
+	// signed by the misbehaving member. It allows third parties to verify
+	// the misbehaving member sent the message. Empty if not available.
+	SignedEnvelope []byte
+	// ObservedAt is the time the misbehavior was observed.
+	ObservedAt time.Time
+}
+
+// misbehaviorEvidenceStore persists evidence of group members misbehavior
+// observed by the node so it can be exported later. Evidence older than
+// misbehaviorEvidenceMaxAge is deleted and at most
+// misbehaviorEvidenceMaxCount newest entries are kept.
+type misbehaviorEvidenceStore struct {
+	// mutex guards saving and pruning entries.
+	mutex       sync.Mutex
+	persistence persistence.BasicHandle
+}
+
+// storedMisbehaviorEvidence is an evidence along with the name of the file
+// it is persisted in.
+type storedMisbehaviorEvidence struct {
+	name     string
+	evidence *MisbehaviorEvidence
+}
+
+// newMisbehaviorEvidenceStore creates a new store backed by the given
+// persistence handle.
+func newMisbehaviorEvidenceStore(
+	persistence persistence.BasicHandle,
+) *misbehaviorEvidenceStore {
+	return &misbehaviorEvidenceStore{
+		persistence: persistence,
+	}
+}
+
+// save persists the given evidence and deletes entries exceeding the age
+// and count caps.
+func (mes *misbehaviorEvidenceStore) save(evidence *MisbehaviorEvidence) error {
+	mes.mutex.Lock()
+	defer mes.mutex.Unlock()
+
+	content, err := json.Marshal(evidence)
+	if err != nil {
+		return fmt.Errorf("cannot marshal misbehavior evidence: [%v]", err)
+	}
+
+	contentHash := sha256.Sum256(content)
+
+	if err := mes.persistence.Save(
+		content,
+		misbehaviorEvidenceDirectory,
+		hex.EncodeToString(contentHash[:]),
+	); err != nil {
+		return fmt.Errorf("cannot save misbehavior evidence: [%v]", err)
+	}
+
+	if err := mes.prune(evidence.ObservedAt); err != nil {
+		return fmt.Errorf("cannot prune misbehavior evidence: [%v]", err)
+	}
+
+	return nil
+}
+
+// prune deletes evidence older than misbehaviorEvidenceMaxAge at the given
+// time and the oldest evidence exceeding misbehaviorEvidenceMaxCount. Must
+// be called with the mutex held.
+func (mes *misbehaviorEvidenceStore) prune(now time.Time) error {
+	stored, err := mes.load()
+	if err != nil {
+		return err
+	}
+
+	minObservedAt := now.Add(-misbehaviorEvidenceMaxAge)
+
+	for i, entry := range stored {
+		if len(stored)-i <= misbehaviorEvidenceMaxCount &&
+			!entry.evidence.ObservedAt.Before(minObservedAt) {
+			// Entries are sorted by the observation time so all remaining
+			// entries are within the caps.
+			break
+		}
+
+		if err := mes.persistence.Delete(
+			misbehaviorEvidenceDirectory,
+			entry.name,
+		); err != nil {
+			return fmt.Errorf(
+				"cannot delete misbehavior evidence [%s]: [%v]",
+				entry.name,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// all returns all persisted evidence sorted by the observation time.
+func (mes *misbehaviorEvidenceStore) all() ([]*MisbehaviorEvidence, error) {
+	stored, err := mes.load()
+	if err != nil {
+		return nil, err
+	}
+
+	evidence := make([]*MisbehaviorEvidence, len(stored))
+	for i, entry := range stored {
+		evidence[i] = entry.evidence
+	}
+
+	return evidence, nil
+}
+
+// load returns all persisted evidence along with the names of their files,
+// sorted by the observation time.
+func (mes *misbehaviorEvidenceStore) load() (
+	[]*storedMisbehaviorEvidence,
+	error,
+) {
+	var stored []*storedMisbehaviorEvidence
+	var errs []error
+
+	persistenceutil.ReadAll(
+		mes.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != misbehaviorEvidenceDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read misbehavior evidence from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			entry := &MisbehaviorEvidence{}
+			if err := json.Unmarshal(content, entry); err != nil {
+				logger.Errorf(
+					"could not parse misbehavior evidence from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			stored = append(stored, &storedMisbehaviorEvidence{
+				name:     descriptor.Name(),
+				evidence: entry,
+			})
+		},
+		func(err error) {
+			errs = append(errs, err)
+		},
+	)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf(
+			"could not load misbehavior evidence from disk: [%v]",
+			errs[0],
+		)
+	}
+
+	sort.SliceStable(stored, func(i, j int) bool {
+		return stored[i].evidence.ObservedAt.Before(
+			stored[j].evidence.ObservedAt,
+		)
+	})
+
+	return stored, nil
+}
+
+// recorder returns an evidence recorder for the given protocol executed by
+// a group formed by the given operators. Member indexes are resolved to
+// operators using their positions in the given list.
+func (mes *misbehaviorEvidenceStore) recorder(
+	protocol string,
+	operators chain.Addresses,
+) *misbehaviorEvidenceRecorder {
+	return &misbehaviorEvidenceRecorder{
+		store:     mes,
+		protocol:  protocol,
+		operators: operators,
+	}
+}
+
+// misbehaviorEvidenceRecorder implements common.EvidenceRecorder for
+// a single protocol execution.
+type misbehaviorEvidenceRecorder struct {
+	store     *misbehaviorEvidenceStore
+	protocol  string
+	operators chain.Addresses
+}
+
+// RecordEvidence persists the given evidence. Problems with persisting the
+// evidence are logged as they must not affect the protocol execution.
+func (mer *misbehaviorEvidenceRecorder) RecordEvidence(
+	evidence *common.Evidence,
+) {
+	var accusedOperator chain.Address
+	if index := int(evidence.AccusedMemberIndex); index > 0 &&
+		index <= len(mer.operators) {
+		accusedOperator = mer.operators[index-1]
+	}
+
+	logger.Warnf(
+		"[member:%v] recording evidence of misbehavior of member [%v] "+
+			"controlled by operator [%v] during %s session [%v]: [%v]",
+		evidence.ReporterMemberIndex,
+		evidence.AccusedMemberIndex,
+		accusedOperator,
+		mer.protocol,
+		evidence.SessionID,
+		evidence.Reason,
+	)
+
+	err := mer.store.save(&MisbehaviorEvidence{
+		Protocol:            mer.protocol,
+		SessionID:           evidence.SessionID,
+		ReporterMemberIndex: evidence.ReporterMemberIndex,
+		AccusedMemberIndex:  evidence.AccusedMemberIndex,
+		AccusedOperator:     accusedOperator,
+		Reason:              evidence.Reason,
+		MessageType:         evidence.MessageType,
+		Message:             evidence.Message,
+		SignedEnvelope:      evidence.SignedEnvelope,
+		ObservedAt:          time.Now(),
+	})
+	if err != nil {
+		logger.Errorf(
+			"[member:%v] cannot record evidence of misbehavior of "+
+				"member [%v]: [%v]",
+			evidence.ReporterMemberIndex,
+			evidence.AccusedMemberIndex,
+			err,
+		)
+	}
+}
+
+// ExportMisbehaviorEvidence returns all evidence of group members misbehavior
+// recorded by the node in the given work persistence, encoded as a JSON array
+// sorted by the observation time.
+func ExportMisbehaviorEvidence(
+	workPersistence persistence.BasicHandle,
+) ([]byte, error) {
+	evidence, err := newMisbehaviorEvidenceStore(workPersistence).all()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(evidence, "", "  ")
+}
@@ -0,0 +1,167 @@
+package tbtc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/common"
+)
+
+func TestMisbehaviorEvidence(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	store := newMisbehaviorEvidenceStore(persistenceHandle)
+
+	recorder := store.recorder(
+		misbehaviorEvidenceProtocolSigning,
+		chain.Addresses{"0xAA", "0xBB", "0xCC"},
+	)
+
+	recorder.RecordEvidence(&common.Evidence{
+		SessionID:           "ff-1",
+		ReporterMemberIndex: 1,
+		AccusedMemberIndex:  3,
+		Reason:              "invalid message",
+		MessageType:         "tecdsa/signing_tss_round_one_message",
+		Message:             []byte{0x01, 0x02},
+		SignedEnvelope:      []byte{0xAA, 0xBB},
+	})
+	// An out-of-group member index should not prevent recording.
+	recorder.RecordEvidence(&common.Evidence{
+		SessionID:           "ff-2",
+		ReporterMemberIndex: 1,
+		AccusedMemberIndex:  4,
+		Reason:              "invalid message",
+		MessageType:         "tecdsa/signing_tss_round_two_message",
+		Message:             []byte{0x03},
+	})
+
+	export, err := ExportMisbehaviorEvidence(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var evidence []*MisbehaviorEvidence
+	if err := json.Unmarshal(export, &evidence); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "evidence count", 2, len(evidence))
+
+	first := evidence[0]
+	testutils.AssertStringsEqual(
+		t,
+		"protocol",
+		misbehaviorEvidenceProtocolSigning,
+		first.Protocol,
+	)
+	testutils.AssertStringsEqual(t, "session ID", "ff-1", first.SessionID)
+	testutils.AssertIntsEqual(
+		t,
+		"reporter member index",
+		1,
+		int(first.ReporterMemberIndex),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"accused member index",
+		3,
+		int(first.AccusedMemberIndex),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"accused operator",
+		"0xCC",
+		first.AccusedOperator.String(),
+	)
+	testutils.AssertStringsEqual(t, "reason", "invalid message", first.Reason)
+	testutils.AssertStringsEqual(
+		t,
+		"message type",
+		"tecdsa/signing_tss_round_one_message",
+		first.MessageType,
+	)
+	testutils.AssertBytesEqual(t, []byte{0x01, 0x02}, first.Message)
+	testutils.AssertBytesEqual(t, []byte{0xAA, 0xBB}, first.SignedEnvelope)
+
+	second := evidence[1]
+	testutils.AssertStringsEqual(t, "session ID", "ff-2", second.SessionID)
+	testutils.AssertIntsEqual(
+		t,
+		"accused member index",
+		int(group.MemberIndex(4)),
+		int(second.AccusedMemberIndex),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"accused operator",
+		"",
+		second.AccusedOperator.String(),
+	)
+}
+
+func TestMisbehaviorEvidenceStore_Prune(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	store := newMisbehaviorEvidenceStore(persistenceHandle)
+
+	now := time.Now()
+
+	save := func(sessionID string, observedAt time.Time) {
+		err := store.save(&MisbehaviorEvidence{
+			Protocol:   misbehaviorEvidenceProtocolSigning,
+			SessionID:  sessionID,
+			ObservedAt: observedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The evidence exceeding the age cap is deleted.
+	save("expired", now.Add(-misbehaviorEvidenceMaxAge-time.Minute))
+	save("recent", now)
+
+	evidence, err := store.all()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "evidence count", 1, len(evidence))
+	testutils.AssertStringsEqual(t, "session ID", "recent", evidence[0].SessionID)
+
+	// The oldest evidence exceeding the count cap is deleted.
+	for i := 1; i <= misbehaviorEvidenceMaxCount; i++ {
+		save(fmt.Sprintf("recent-%v", i), now.Add(time.Duration(i)))
+	}
+
+	evidence, err = store.all()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"evidence count",
+		misbehaviorEvidenceMaxCount,
+		len(evidence),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"oldest session ID",
+		"recent-1",
+		evidence[0].SessionID,
+	)
+}
+
+func TestExportMisbehaviorEvidence_Empty(t *testing.T) {
+	export, err := ExportMisbehaviorEvidence(&mockPersistenceHandle{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(t, "export", "[]", string(export))
+}
@@ -222,6 +222,7 @@ func (mfa *movingFundsAction) execute() error {
 
 	movingFundsTx, err := mfa.transactionExecutor.signTransaction(
 		signTxLogger,
+		ActionMovingFunds,
 		unsignedMovingFundsTx,
 		mfa.proposalProcessingStartBlock+movingFundsCommitmentConfirmationBlocks,
 		mfa.proposalExpiryBlock-mfa.signingTimeoutSafetyMarginBlocks,
@@ -75,6 +75,18 @@ type node struct {
 	// wallet.
 	signingExecutors map[string]*signingExecutor
 
+	// signingReliability keeps track of signing group members that failed
+	// to announce their readiness for signing attempts of the node's wallets.
+	signingReliability *signingReliabilityStore
+
+	// misbehaviorEvidence persists evidence of group members misbehavior
+	// observed by the DKG executor and all signing executors of the node.
+	misbehaviorEvidence *misbehaviorEvidenceStore
+
+	// signingMetrics records outcomes of signings executed by all signing
+	// executors of the node.
+	signingMetrics *signingMetrics
+
 	coordinationExecutorsMutex sync.Mutex
 	// coordinationExecutors is the cache holding coordination executors for
 	// specific wallets. The cache key is the uncompressed public key
@@ -87,6 +99,14 @@ type node struct {
 	// proposalGenerator is the implementation of the coordination proposal
 	// generator used by the node.
 	proposalGenerator CoordinationProposalGenerator
+
+	// pendingProposals keeps track of coordination proposals received by
+	// wallets controlled by the node until they expire.
+	pendingProposals *pendingProposalStore
+
+	// walletStates caches on-chain states of wallets reported by the wallets
+	// diagnostics source.
+	walletStates *walletStateCache
 }
 
 func newNode(
@@ -100,22 +120,52 @@ func newNode(
 	proposalGenerator CoordinationProposalGenerator,
 	config Config,
 ) (*node, error) {
-	walletRegistry := newWalletRegistry(keyStorePersistance)
+	walletRegistry, err := newWalletRegistry(keyStorePersistance)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create wallet registry: [%w]", err)
+	}
+
+	// All the stores below load their state from the work persistence
+	// on creation. Scan the work directory only once for all of them.
+	workPersistence = newSnapshotReadHandle(workPersistence)
+	walletDispatcher := newWalletDispatcher(
+		newWalletLastActionStore(workPersistence),
+	)
+	if observer, ok := proposalGenerator.(CoordinationProposalObserver); ok {
+		walletDispatcher.actionSucceededHandler = func(action walletAction) {
+			if proposalAction, ok := action.(proposalWalletAction); ok {
+				observer.ProposalExecuted(
+					bitcoin.PublicKeyHash(action.wallet().publicKey),
+					proposalAction.executedProposal(),
+				)
+			}
+		}
+	}
 
 	latch := generator.NewProtocolLatch()
 	scheduler.RegisterProtocol(latch)
 
 	node := &node{
-		groupParameters:       groupParameters,
-		chain:                 chain,
-		btcChain:              btcChain,
-		netProvider:           netProvider,
-		walletRegistry:        walletRegistry,
-		walletDispatcher:      newWalletDispatcher(),
-		protocolLatch:         latch,
-		signingExecutors:      make(map[string]*signingExecutor),
+		groupParameters:    groupParameters,
+		chain:              chain,
+		btcChain:           btcChain,
+		netProvider:        netProvider,
+		walletRegistry:     walletRegistry,
+		walletDispatcher:   walletDispatcher,
+		protocolLatch:      latch,
+		signingExecutors:   make(map[string]*signingExecutor),
+		signingReliability: newSigningReliabilityStore(workPersistence),
+		misbehaviorEvidence: newMisbehaviorEvidenceStore(
+			workPersistence,
+		),
+		signingMetrics:        newSigningMetrics(),
 		coordinationExecutors: make(map[string]*coordinationExecutor),
 		proposalGenerator:     proposalGenerator,
+		pendingProposals:      newPendingProposalStore(),
+		walletStates: newWalletStateCache(
+			chain,
+			walletStateCachePeriod,
+		),
 	}
 
 	// Only the operator address is known at this point and can be pre-fetched.
@@ -138,6 +188,7 @@ func newNode(
 		latch,
 		config,
 		workPersistence,
+		node.misbehaviorEvidence,
 		scheduler,
 		node.waitForBlockHeight,
 	)
@@ -208,6 +259,18 @@ func (n *node) validateDKG(
 	n.dkgExecutor.executeDkgValidation(seed, submissionBlock, result, resultHash)
 }
 
+// recoverMissedDKG joins the DKG started while the node was offline if it
+// is still in progress or reports it as missed if it is too late to join.
+func (n *node) recoverMissedDKG(deduplicator *deduplicator) {
+	n.dkgExecutor.recoverMissedDkg(deduplicator)
+}
+
+// resumeDKGApprovals resumes DKG result approvals that were scheduled
+// before the node restart and are still awaited by the chain.
+func (n *node) resumeDKGApprovals() {
+	n.dkgExecutor.resumePendingApprovals()
+}
+
 // getSigningExecutor gets the signing executor responsible for executing
 // signing related to a specific wallet whose part is controlled by this node.
 // The second boolean return value indicates whether the node controls at least
@@ -298,6 +361,9 @@ func (n *node) getSigningExecutor(
 		blockCounter.CurrentBlock,
 		n.waitForBlockHeight,
 		signingAttemptsLimit,
+		n.signingReliability,
+		n.misbehaviorEvidence,
+		n.signingMetrics,
 	)
 
 	n.signingExecutors[executorKey] = executor
@@ -834,6 +900,16 @@ func processCoordinationResult(node *node, result *coordinationResult) {
 	startBlock := result.window.endBlock()
 	expiryBlock := startBlock + result.proposal.ValidityBlocks()
 
+	node.pendingProposals.record(
+		bitcoin.PublicKeyHash(result.wallet.publicKey),
+		&PendingProposal{
+			ActionType:        proposedAction,
+			CoordinationBlock: result.window.coordinationBlock,
+			StartBlock:        startBlock,
+			ExpiryBlock:       expiryBlock,
+		},
+	)
+
 	switch proposedAction {
 	case ActionHeartbeat:
 		if proposal, ok := result.proposal.(*HeartbeatProposal); ok {
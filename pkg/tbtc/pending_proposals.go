@@ -0,0 +1,81 @@
+package tbtc
+
+import (
+	"sync"
+)
+
+// PendingProposal describes a coordination proposal received by a wallet
+// whose validity period may not have ended yet.
+type PendingProposal struct {
+	// ActionType is the type of the action proposed.
+	ActionType WalletActionType
+	// CoordinationBlock is the coordination block of the window the proposal
+	// was received in.
+	CoordinationBlock uint64
+	// StartBlock is the block the proposed action starts at.
+	StartBlock uint64
+	// ExpiryBlock is the block the proposal expires at.
+	ExpiryBlock uint64
+}
+
+// pendingProposalStore keeps track of coordination proposals received by
+// wallets controlled by the node until they expire. The proposals are held
+// in memory only.
+type pendingProposalStore struct {
+	mutex sync.Mutex
+
+	proposals map[[20]byte][]*PendingProposal
+}
+
+func newPendingProposalStore() *pendingProposalStore {
+	return &pendingProposalStore{
+		proposals: make(map[[20]byte][]*PendingProposal),
+	}
+}
+
+// record records the given proposal received by the given wallet. Proposals
+// of the wallet that expired before the given proposal's start block are
+// removed.
+func (pps *pendingProposalStore) record(
+	walletPublicKeyHash [20]byte,
+	proposal *PendingProposal,
+) {
+	pps.mutex.Lock()
+	defer pps.mutex.Unlock()
+
+	proposals := []*PendingProposal{proposal}
+	for _, existing := range pps.proposals[walletPublicKeyHash] {
+		if existing.ExpiryBlock >= proposal.StartBlock {
+			proposals = append(proposals, existing)
+		}
+	}
+
+	pps.proposals[walletPublicKeyHash] = proposals
+}
+
+// get returns proposals received by the given wallet that have not expired
+// as of the given block, ordered from the newest to the oldest one.
+func (pps *pendingProposalStore) get(
+	walletPublicKeyHash [20]byte,
+	currentBlock uint64,
+) []*PendingProposal {
+	pps.mutex.Lock()
+	defer pps.mutex.Unlock()
+
+	var result []*PendingProposal
+	for _, proposal := range pps.proposals[walletPublicKeyHash] {
+		if proposal.ExpiryBlock >= currentBlock {
+			result = append(result, proposal)
+		}
+	}
+
+	return result
+}
+
+// remove removes all proposals of the given wallet.
+func (pps *pendingProposalStore) remove(walletPublicKeyHash [20]byte) {
+	pps.mutex.Lock()
+	defer pps.mutex.Unlock()
+
+	delete(pps.proposals, walletPublicKeyHash)
+}
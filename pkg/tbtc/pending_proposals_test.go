@@ -0,0 +1,115 @@
+package tbtc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestPendingProposalStore(t *testing.T) {
+	store := newPendingProposalStore()
+
+	walletPublicKeyHash := [20]byte{0x01}
+	otherWalletPublicKeyHash := [20]byte{0x02}
+
+	heartbeatProposal := &PendingProposal{
+		ActionType:        ActionHeartbeat,
+		CoordinationBlock: 900,
+		StartBlock:        980,
+		ExpiryBlock:       1280,
+	}
+	store.record(walletPublicKeyHash, heartbeatProposal)
+
+	redemptionProposal := &PendingProposal{
+		ActionType:        ActionRedemption,
+		CoordinationBlock: 1800,
+		StartBlock:        1880,
+		ExpiryBlock:       2480,
+	}
+	store.record(otherWalletPublicKeyHash, redemptionProposal)
+
+	depositSweepProposal := &PendingProposal{
+		ActionType:        ActionDepositSweep,
+		CoordinationBlock: 1200,
+		StartBlock:        1280,
+		ExpiryBlock:       2480,
+	}
+	store.record(walletPublicKeyHash, depositSweepProposal)
+
+	var tests = map[string]struct {
+		walletPublicKeyHash [20]byte
+		currentBlock        uint64
+		expectedProposals   []*PendingProposal
+	}{
+		"all proposals pending": {
+			walletPublicKeyHash: walletPublicKeyHash,
+			currentBlock:        1280,
+			expectedProposals: []*PendingProposal{
+				depositSweepProposal,
+				heartbeatProposal,
+			},
+		},
+		"older proposal expired": {
+			walletPublicKeyHash: walletPublicKeyHash,
+			currentBlock:        1281,
+			expectedProposals: []*PendingProposal{
+				depositSweepProposal,
+			},
+		},
+		"all proposals expired": {
+			walletPublicKeyHash: walletPublicKeyHash,
+			currentBlock:        2481,
+			expectedProposals:   nil,
+		},
+		"other wallet": {
+			walletPublicKeyHash: otherWalletPublicKeyHash,
+			currentBlock:        2000,
+			expectedProposals: []*PendingProposal{
+				redemptionProposal,
+			},
+		},
+		"unknown wallet": {
+			walletPublicKeyHash: [20]byte{0x03},
+			currentBlock:        0,
+			expectedProposals:   nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			proposals := store.get(test.walletPublicKeyHash, test.currentBlock)
+
+			if !reflect.DeepEqual(test.expectedProposals, proposals) {
+				t.Errorf(
+					"unexpected proposals\nexpected: [%v]\nactual:   [%v]",
+					test.expectedProposals,
+					proposals,
+				)
+			}
+		})
+	}
+
+	// Recording a proposal starting after the expiry of the existing ones
+	// removes them.
+	store.record(walletPublicKeyHash, &PendingProposal{
+		ActionType:        ActionHeartbeat,
+		CoordinationBlock: 2500,
+		StartBlock:        2580,
+		ExpiryBlock:       2880,
+	})
+	testutils.AssertIntsEqual(
+		t,
+		"recorded proposals count",
+		1,
+		len(store.proposals[walletPublicKeyHash]),
+	)
+
+	store.remove(walletPublicKeyHash)
+	testutils.AssertIntsEqual(
+		t,
+		"proposals count after removal",
+		0,
+		len(store.get(walletPublicKeyHash, 0)),
+	)
+}
@@ -232,6 +232,7 @@ func (ra *redemptionAction) execute() error {
 
 	redemptionTx, err := ra.transactionExecutor.signTransaction(
 		signTxLogger,
+		ActionRedemption,
 		unsignedRedemptionTx,
 		ra.proposalProcessingStartBlock,
 		ra.proposalExpiryBlock-ra.signingTimeoutSafetyMarginBlocks,
@@ -333,6 +334,10 @@ func (ra *redemptionAction) actionType() WalletActionType {
 	return ActionRedemption
 }
 
+func (ra *redemptionAction) executedProposal() CoordinationProposal {
+	return ra.proposal
+}
+
 // redemptionFeeDistributionFn calculates the redemption transaction fee
 // distribution for the given redemption requests. The resulting list
 // contains the fee shares ordered in the same way as the input requests, i.e.
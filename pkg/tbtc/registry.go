@@ -4,10 +4,14 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"sync"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/subscription"
 
 	"github.com/keep-network/keep-common/pkg/persistence"
 )
@@ -26,6 +30,31 @@ type walletRegistry struct {
 	// walletStorage is the handle to the wallet storage responsible for
 	// wallet persistence.
 	walletStorage *walletStorage
+
+	// signerRegisteredHandlersMutex guards the signer registered handlers.
+	// It is separate from the struct-wide lock so handlers can be
+	// registered while the registry is busy.
+	signerRegisteredHandlersMutex sync.Mutex
+	// signerRegisteredHandlers are handlers invoked when a new signer is
+	// registered, keyed by their identifiers.
+	signerRegisteredHandlers map[int]func(event *SignerRegisteredEvent)
+	// nextSignerRegisteredHandlerID is the identifier assigned to the next
+	// registered signer registered handler.
+	nextSignerRegisteredHandlerID int
+}
+
+// SignerRegisteredEvent represents the registration of a new signer in the
+// wallet registry. A signer is registered once the node successfully
+// completes DKG for the given wallet.
+type SignerRegisteredEvent struct {
+	// WalletPublicKey is the public key of the wallet the signer belongs to.
+	WalletPublicKey *ecdsa.PublicKey
+	// WalletPublicKeyHash is the 20-byte public key hash of the wallet the
+	// signer belongs to.
+	WalletPublicKeyHash [20]byte
+	// SigningGroupMemberIndex is the index of the signer in the wallet
+	// signing group.
+	SigningGroupMemberIndex group.MemberIndex
 }
 
 type walletCacheValue struct {
@@ -36,13 +65,30 @@ type walletCacheValue struct {
 	signers []*signer
 }
 
-// newWalletRegistry creates a new instance of the walletRegistry.
-func newWalletRegistry(persistence persistence.ProtectedHandle) *walletRegistry {
+// newWalletRegistry creates a new instance of the walletRegistry. It returns
+// an error if any signer could not be read from the storage. Such a signer is
+// not necessarily corrupted so it is left in place and nothing is quarantined
+// until the storage can be read entirely.
+func newWalletRegistry(
+	persistence persistence.ProtectedHandle,
+) (*walletRegistry, error) {
 	walletStorage := newWalletStorage(persistence)
 
 	// Pre-populate the wallet cache using the wallet storage.
 	walletCache := make(map[string]*walletCacheValue)
-	walletSigners := walletStorage.loadSigners()
+	walletSigners, corruptedSigners, err := walletStorage.loadSigners()
+	if err != nil {
+		return nil, fmt.Errorf("could not load signers: [%w]", err)
+	}
+	if len(corruptedSigners) > 0 {
+		logger.Errorf(
+			"[%v] corrupted signers found in the storage; "+
+				"quarantining them",
+			len(corruptedSigners),
+		)
+
+		walletStorage.quarantineSigners(corruptedSigners, walletSigners)
+	}
 	if len(walletSigners) > 0 {
 		for walletStorageKey, signers := range walletSigners {
 			// We need to extract the wallet from the signers array. The
@@ -72,8 +118,43 @@ func newWalletRegistry(persistence persistence.ProtectedHandle) *walletRegistry
 	}
 
 	return &walletRegistry{
-		walletCache:   walletCache,
-		walletStorage: walletStorage,
+		walletCache:              walletCache,
+		walletStorage:            walletStorage,
+		signerRegisteredHandlers: make(map[int]func(event *SignerRegisteredEvent)),
+	}, nil
+}
+
+// onSignerRegistered registers a handler that is invoked every time a new
+// signer is registered in the walletRegistry. Each handler is invoked in
+// a separate goroutine so handlers do not block the registration. Signers
+// loaded from the storage at startup do not trigger the handler.
+func (wr *walletRegistry) onSignerRegistered(
+	handler func(event *SignerRegisteredEvent),
+) subscription.EventSubscription {
+	wr.signerRegisteredHandlersMutex.Lock()
+	defer wr.signerRegisteredHandlersMutex.Unlock()
+
+	handlerID := wr.nextSignerRegisteredHandlerID
+	wr.nextSignerRegisteredHandlerID++
+
+	wr.signerRegisteredHandlers[handlerID] = handler
+
+	return subscription.NewEventSubscription(func() {
+		wr.signerRegisteredHandlersMutex.Lock()
+		defer wr.signerRegisteredHandlersMutex.Unlock()
+
+		delete(wr.signerRegisteredHandlers, handlerID)
+	})
+}
+
+// notifySignerRegistered invokes all signer registered handlers with
+// the given event.
+func (wr *walletRegistry) notifySignerRegistered(event *SignerRegisteredEvent) {
+	wr.signerRegisteredHandlersMutex.Lock()
+	defer wr.signerRegisteredHandlersMutex.Unlock()
+
+	for _, handler := range wr.signerRegisteredHandlers {
+		go handler(event)
 	}
 }
 
@@ -119,6 +200,12 @@ func (wr *walletRegistry) registerSigner(signer *signer) error {
 		signer,
 	)
 
+	wr.notifySignerRegistered(&SignerRegisteredEvent{
+		WalletPublicKey:         signer.wallet.publicKey,
+		WalletPublicKeyHash:     wr.walletCache[walletStorageKey].walletPublicKeyHash,
+		SigningGroupMemberIndex: signer.signingGroupMemberIndex,
+	})
+
 	return nil
 }
 
@@ -156,6 +243,40 @@ func (wr *walletRegistry) getWalletByPublicKeyHash(
 	return wallet{}, false
 }
 
+// archiveWallet archives the wallet with the given 20-byte wallet public key
+// hash. Signers of the wallet are moved to the archive of the wallet storage
+// and the wallet is removed from the registry. Archived signers are not
+// loaded by the registry anymore but their data are kept in case they are
+// needed in the future.
+func (wr *walletRegistry) archiveWallet(walletPublicKeyHash [20]byte) error {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	var walletStorageKey string
+	for key, value := range wr.walletCache {
+		if value.walletPublicKeyHash == walletPublicKeyHash {
+			walletStorageKey = key
+			break
+		}
+	}
+
+	if walletStorageKey == "" {
+		return fmt.Errorf(
+			"wallet with public key hash [0x%x] not found",
+			walletPublicKeyHash,
+		)
+	}
+
+	err := wr.walletStorage.archiveWallet(walletStorageKey)
+	if err != nil {
+		return fmt.Errorf("cannot archive wallet in the storage: [%w]", err)
+	}
+
+	delete(wr.walletCache, walletStorageKey)
+
+	return nil
+}
+
 // walletStorage is the component that persists data of the wallets managed
 // by the given node using the underlying persistence layer. It should be
 // used directly only by the walletRegistry.
@@ -194,10 +315,48 @@ func (ws *walletStorage) saveSigner(signer *signer) error {
 	return nil
 }
 
+// archiveWallet moves all signers of the wallet with the given wallet storage
+// key to the archive of the underlying persistence layer. It does not remove
+// the wallet from any in-memory cache and should not be called from any other
+// place than walletRegistry.
+func (ws *walletStorage) archiveWallet(walletStorageKey string) error {
+	if err := ws.persistence.Archive(walletStorageKey); err != nil {
+		return fmt.Errorf(
+			"could not archive wallet using the "+
+				"underlying persistence layer: [%w]",
+			err,
+		)
+	}
+
+	return nil
+}
+
+// corruptedSigner describes a signer entry of the wallet storage that failed
+// the integrity verification.
+type corruptedSigner struct {
+	// directory is the directory of the entry.
+	directory string
+	// name is the name of the entry.
+	name string
+	// err is the reason the verification failed.
+	err error
+}
+
 // loadSigners loads all signers stored using the underlying persistence layer.
-// This function should not be called from any other place than walletRegistry.
-func (ws *walletStorage) loadSigners() map[string][]*signer {
+// Each signer is verified to decrypt, unmarshal, and hold a private key share
+// matching the recorded wallet public key. Signers failing the verification
+// are not loaded but returned as corrupted ones. Signers whose files could not
+// be read, e.g. due to missing permissions, are not considered corrupted;
+// an error listing them is returned instead. This function should not be
+// called from any other place than walletRegistry.
+func (ws *walletStorage) loadSigners() (
+	map[string][]*signer,
+	[]*corruptedSigner,
+	error,
+) {
 	signersByWallet := make(map[string][]*signer)
+	var corruptedSigners []*corruptedSigner
+	var unreadableSigners []string
 
 	descriptorsChan, errorsChan := ws.persistence.ReadAll()
 
@@ -212,30 +371,70 @@ func (ws *walletStorage) loadSigners() map[string][]*signer {
 
 	go func() {
 		for descriptor := range descriptorsChan {
-			content, err := descriptor.Content()
-			if err != nil {
+			if descriptor.Directory() == walletStorageSchemaDirectory {
+				continue
+			}
+
+			reportCorrupted := func(err error) {
 				logger.Errorf(
-					"could not get content from file [%v] "+
-						"in directory [%v]: [%v]",
+					"corrupted signer in file [%v] in directory [%v]: [%v]",
 					descriptor.Name(),
 					descriptor.Directory(),
 					err,
 				)
+
+				corruptedSigners = append(corruptedSigners, &corruptedSigner{
+					directory: descriptor.Directory(),
+					name:      descriptor.Name(),
+					err:       err,
+				})
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				// The file could not be read from the disk. This says
+				// nothing about the signer itself so it must not be
+				// quarantined.
+				var pathErr *fs.PathError
+				if errors.As(err, &pathErr) {
+					logger.Errorf(
+						"could not read signer from file [%v] "+
+							"in directory [%v]: [%v]",
+						descriptor.Name(),
+						descriptor.Directory(),
+						err,
+					)
+
+					unreadableSigners = append(
+						unreadableSigners,
+						fmt.Sprintf(
+							"%v/%v",
+							descriptor.Directory(),
+							descriptor.Name(),
+						),
+					)
+					continue
+				}
+
+				reportCorrupted(
+					fmt.Errorf("could not decrypt content: [%w]", err),
+				)
 				continue
 			}
 
 			signer := &signer{}
 			if err := signer.Unmarshal(content); err != nil {
-				logger.Errorf(
-					"could not unmarshal signer from file [%v] "+
-						"in directory [%v]: [%v]",
-					descriptor.Name(),
-					descriptor.Directory(),
-					err,
+				reportCorrupted(
+					fmt.Errorf("could not unmarshal signer: [%w]", err),
 				)
 				continue
 			}
 
+			if err := verifySigner(signer); err != nil {
+				reportCorrupted(err)
+				continue
+			}
+
 			walletStorageKey := getWalletStorageKey(signer.wallet.publicKey)
 
 			signersByWallet[walletStorageKey] = append(
@@ -260,7 +459,78 @@ func (ws *walletStorage) loadSigners() map[string][]*signer {
 
 	wg.Wait()
 
-	return signersByWallet
+	if len(unreadableSigners) > 0 {
+		return nil, nil, fmt.Errorf(
+			"could not read signers %v",
+			unreadableSigners,
+		)
+	}
+
+	return signersByWallet, corruptedSigners, nil
+}
+
+// verifySigner checks whether the private key share of the given signer
+// corresponds to the public key of the signer's wallet.
+func verifySigner(signer *signer) error {
+	walletPublicKey := signer.wallet.publicKey
+	privateKeySharePublicKey := signer.privateKeyShare.PublicKey()
+
+	if walletPublicKey.X.Cmp(privateKeySharePublicKey.X) != 0 ||
+		walletPublicKey.Y.Cmp(privateKeySharePublicKey.Y) != 0 {
+		return fmt.Errorf(
+			"private key share does not match wallet public key",
+		)
+	}
+
+	return nil
+}
+
+// quarantineSigners moves the given corrupted signers to the archive of the
+// underlying persistence layer so they are not loaded anymore. The layer can
+// archive whole directories only so each directory holding a corrupted signer
+// is archived entirely and the given valid signers of the affected wallets
+// are saved again. The archived data are kept for further investigation.
+// This function should not be called from any other place than
+// walletRegistry.
+func (ws *walletStorage) quarantineSigners(
+	corruptedSigners []*corruptedSigner,
+	signersByWallet map[string][]*signer,
+) {
+	directories := make(map[string]bool)
+	for _, corruptedSigner := range corruptedSigners {
+		directories[corruptedSigner.directory] = true
+	}
+
+	for directory := range directories {
+		if err := ws.archiveWallet(directory); err != nil {
+			logger.Errorf(
+				"could not quarantine directory [%v]: [%v]",
+				directory,
+				err,
+			)
+			continue
+		}
+
+		for _, signer := range signersByWallet[directory] {
+			if err := ws.saveSigner(signer); err != nil {
+				logger.Errorf(
+					"could not restore valid signer with index [%v] "+
+						"in directory [%v] after quarantine; the signer "+
+						"is kept in the archive: [%v]",
+					signer.signingGroupMemberIndex,
+					directory,
+					err,
+				)
+			}
+		}
+
+		logger.Warnf(
+			"directory [%v] with corrupted signers moved to the archive; "+
+				"[%v] valid signers saved back",
+			directory,
+			len(signersByWallet[directory]),
+		)
+	}
 }
 
 // getWalletStorageKey compute the wallet storage key that is used to identify
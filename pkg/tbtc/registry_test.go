@@ -2,9 +2,12 @@ package tbtc
 
 import (
 	"crypto/ecdsa"
+	"fmt"
+	"io/fs"
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
@@ -16,13 +19,19 @@ import (
 func TestWalletRegistry_RegisterSigner(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
 
 	signer := createMockSigner(t)
 
 	walletStorageKey := getWalletStorageKey(signer.wallet.publicKey)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -63,11 +72,17 @@ func TestWalletRegistry_RegisterSigner(t *testing.T) {
 func TestWalletRegistry_GetSigners(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
 
 	signer := createMockSigner(t)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -86,14 +101,131 @@ func TestWalletRegistry_GetSigners(t *testing.T) {
 	}
 }
 
+func TestWalletRegistry_OnSignerRegistered(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
+
+	eventsChan := make(chan *SignerRegisteredEvent, 1)
+	subscription := walletRegistry.onSignerRegistered(
+		func(event *SignerRegisteredEvent) {
+			eventsChan <- event
+		},
+	)
+
+	signer := createMockSigner(t)
+
+	err = walletRegistry.registerSigner(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEvent := &SignerRegisteredEvent{
+		WalletPublicKey:         signer.wallet.publicKey,
+		WalletPublicKeyHash:     bitcoin.PublicKeyHash(signer.wallet.publicKey),
+		SigningGroupMemberIndex: signer.signingGroupMemberIndex,
+	}
+
+	select {
+	case event := <-eventsChan:
+		if !reflect.DeepEqual(expectedEvent, event) {
+			t.Errorf(
+				"unexpected event\nexpected: [%+v]\nactual:   [%+v]",
+				expectedEvent,
+				event,
+			)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected signer registered event")
+	}
+
+	subscription.Unsubscribe()
+
+	err = walletRegistry.registerSigner(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-eventsChan:
+		t.Errorf("unexpected event after unsubscribing: [%+v]", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWalletRegistry_ArchiveWallet(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
+
+	signer := createMockSigner(t)
+
+	err = walletRegistry.registerSigner(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+	err = walletRegistry.archiveWallet(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"registered wallets count",
+		0,
+		len(walletRegistry.getWalletsPublicKeys()),
+	)
+
+	testutils.AssertIntsEqual(
+		t,
+		"persisted signers count",
+		0,
+		len(persistenceHandle.saved),
+	)
+
+	err = walletRegistry.archiveWallet(walletPublicKeyHash)
+	expectedErr := fmt.Sprintf(
+		"wallet with public key hash [0x%x] not found",
+		walletPublicKeyHash,
+	)
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedErr,
+			err,
+		)
+	}
+}
+
 func TestWalletRegistry_getWalletByPublicKeyHash(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
 
 	signer := createMockSigner(t)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -111,11 +243,17 @@ func TestWalletRegistry_getWalletByPublicKeyHash(t *testing.T) {
 func TestWalletRegistry_getWalletByPublicKeyHash_NotFound(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
 
 	signer := createMockSigner(t)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -154,7 +292,10 @@ func TestWalletRegistry_PrePopulateWalletCache(t *testing.T) {
 	}
 
 	// Cache pre-population happens within newWalletRegistry.
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	testutils.AssertIntsEqual(
 		t,
@@ -182,14 +323,158 @@ func TestWalletRegistry_PrePopulateWalletCache(t *testing.T) {
 	}
 }
 
+func TestWalletRegistry_PrePopulateWalletCache_CorruptedSigners(t *testing.T) {
+	validSigner := createMockSigner(t)
+	signerBytes, err := validSigner.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletStorageKey := getWalletStorageKey(validSigner.wallet.publicKey)
+
+	// Signer whose private key share does not match the wallet public key.
+	x, y := tecdsa.Curve.ScalarBaseMult(big.NewInt(100).Bytes())
+	mismatchedWalletPublicKey := &ecdsa.PublicKey{
+		Curve: tecdsa.Curve,
+		X:     x,
+		Y:     y,
+	}
+	mismatchedSigner := &signer{
+		wallet: wallet{
+			publicKey:             mismatchedWalletPublicKey,
+			signingGroupOperators: validSigner.wallet.signingGroupOperators,
+		},
+		signingGroupMemberIndex: 1,
+		privateKeyShare:         validSigner.privateKeyShare,
+	}
+	mismatchedSignerBytes, err := mismatchedSigner.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mismatchedWalletStorageKey := getWalletStorageKey(mismatchedWalletPublicKey)
+
+	persistenceHandle := &mockPersistenceHandle{
+		saved: []persistence.DataDescriptor{
+			&mockDescriptor{
+				name:      "membership_1",
+				directory: walletStorageKey,
+				content:   signerBytes,
+			},
+			&mockDescriptor{
+				name:      "membership_2",
+				directory: walletStorageKey,
+				content:   []byte{0x01, 0x02, 0x03},
+			},
+			&mockDescriptor{
+				name:      "membership_1",
+				directory: mismatchedWalletStorageKey,
+				content:   mismatchedSignerBytes,
+			},
+		},
+	}
+
+	// Cache pre-population happens within newWalletRegistry.
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"loaded wallets count",
+		1,
+		len(walletRegistry.walletCache),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"loaded wallet signers count",
+		1,
+		len(walletRegistry.walletCache[walletStorageKey].signers),
+	)
+
+	// Corrupted signers are quarantined while the valid one is saved back.
+	testutils.AssertIntsEqual(
+		t,
+		"persisted signers count",
+		1,
+		len(persistenceHandle.saved),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"persisted signer directory",
+		walletStorageKey,
+		persistenceHandle.saved[0].Directory(),
+	)
+
+	savedSignerBytes, err := persistenceHandle.saved[0].Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertBytesEqual(t, signerBytes, savedSignerBytes)
+}
+
+func TestWalletRegistry_PrePopulateWalletCache_UnreadableSigner(t *testing.T) {
+	validSigner := createMockSigner(t)
+	signerBytes, err := validSigner.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletStorageKey := getWalletStorageKey(validSigner.wallet.publicKey)
+
+	persistenceHandle := &mockPersistenceHandle{
+		saved: []persistence.DataDescriptor{
+			&mockDescriptor{
+				name:      "membership_1",
+				directory: walletStorageKey,
+				content:   signerBytes,
+			},
+			&mockDescriptor{
+				name:      "membership_2",
+				directory: walletStorageKey,
+				content:   []byte{0x01, 0x02, 0x03},
+			},
+			&mockDescriptor{
+				name:      "membership_3",
+				directory: walletStorageKey,
+				err: &fs.PathError{
+					Op:   "open",
+					Path: "membership_3",
+					Err:  fs.ErrPermission,
+				},
+			},
+		},
+	}
+
+	_, err = newWalletRegistry(persistenceHandle)
+	if err == nil {
+		t.Fatal("expected registry creation error")
+	}
+
+	// Nothing is quarantined as long as any signer cannot be read.
+	testutils.AssertIntsEqual(
+		t,
+		"persisted signers count",
+		3,
+		len(persistenceHandle.saved),
+	)
+}
+
 func TestWalletRegistry_GetWalletsPublicKeys(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+
+	if err != nil {
+
+		t.Fatal(err)
+
+	}
 
 	signer := createMockSigner(t)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -246,7 +531,17 @@ func TestWalletStorage_LoadSigners(t *testing.T) {
 
 	walletStorage := newWalletStorage(persistenceHandle)
 
-	signersByWallet := walletStorage.loadSigners()
+	signersByWallet, corruptedSigners, err := walletStorage.loadSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"corrupted signers count",
+		0,
+		len(corruptedSigners),
+	)
 
 	testutils.AssertIntsEqual(
 		t,
@@ -311,17 +606,34 @@ func (mph *mockPersistenceHandle) ReadAll() (
 }
 
 func (mph *mockPersistenceHandle) Archive(directory string) error {
-	panic("not implemented")
+	var remaining []persistence.DataDescriptor
+	for _, descriptor := range mph.saved {
+		if descriptor.Directory() != directory {
+			remaining = append(remaining, descriptor)
+		}
+	}
+
+	mph.saved = remaining
+
+	return nil
 }
 
 func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
-	panic("not implemented")
+	for i, descriptor := range mph.saved {
+		if descriptor.Directory() == directory && descriptor.Name() == name {
+			mph.saved = append(mph.saved[:i], mph.saved[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("file not found")
 }
 
 type mockDescriptor struct {
 	name      string
 	directory string
 	content   []byte
+	err       error
 }
 
 func (md *mockDescriptor) Name() string {
@@ -333,5 +645,5 @@ func (md *mockDescriptor) Directory() string {
 }
 
 func (md *mockDescriptor) Content() ([]byte, error) {
-	return md.content, nil
+	return md.content, md.err
 }
@@ -0,0 +1,300 @@
+package tbtc
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+const (
+	// signersExportVersion is the version of the signers export format.
+	signersExportVersion = 1
+
+	// signersExportSaltLength is the byte length of the salt used to derive
+	// the encryption key of signers exports from the passphrase.
+	signersExportSaltLength = 32
+)
+
+// Parameters of the scrypt key derivation function used to derive the
+// encryption key of signers exports from the passphrase. Exports leave
+// the machine so a memory-hard function is used instead of a plain hash.
+const (
+	signersExportScryptN = 1 << 15
+	signersExportScryptR = 8
+	signersExportScryptP = 1
+)
+
+// signersExport is the format of signers exports.
+type signersExport struct {
+	Version    int
+	Salt       []byte
+	Ciphertext []byte
+}
+
+// ExportSigners exports all signers of the wallet with the given public key
+// hash held in the given key store. The signers are encrypted with a key
+// derived from the given passphrase. Along with the export, a function
+// archiving the wallet's signers in the key store is returned. It must be
+// called once the export is durably stored so the node no longer loads the
+// signers and the exported signers can be safely imported on another node.
+// Archived signers are not removed from the disk. The node must be stopped
+// while its signers are exported.
+func ExportSigners(
+	keyStorePersistence persistence.ProtectedHandle,
+	walletPublicKeyHash [20]byte,
+	passphrase string,
+) ([]byte, func() error, error) {
+	if len(passphrase) == 0 {
+		return nil, nil, fmt.Errorf("passphrase must not be empty")
+	}
+
+	signers, err := findSigners(keyStorePersistence, walletPublicKeyHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot find signers: [%v]", err)
+	}
+	if len(signers) == 0 {
+		return nil, nil, fmt.Errorf(
+			"no signers of wallet [0x%x] found in the key store",
+			walletPublicKeyHash,
+		)
+	}
+
+	signersBytes := make([][]byte, len(signers))
+	for i, signer := range signers {
+		signerBytes, err := signer.Marshal()
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot marshal signer: [%v]", err)
+		}
+		signersBytes[i] = signerBytes
+	}
+
+	plaintext, err := json.Marshal(signersBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot marshal signers: [%v]", err)
+	}
+
+	salt := make([]byte, signersExportSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("cannot generate salt: [%v]", err)
+	}
+
+	box, err := newSignersExportBox(passphrase, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err := box.Encrypt(plaintext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot encrypt signers: [%v]", err)
+	}
+
+	export, err := json.Marshal(&signersExport{
+		Version:    signersExportVersion,
+		Salt:       salt,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot marshal signers export: [%v]", err)
+	}
+
+	archive := func() error {
+		err := keyStorePersistence.Archive(
+			getWalletStorageKey(signers[0].wallet.publicKey),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot archive exported signers: [%v]", err)
+		}
+
+		return nil
+	}
+
+	return export, archive, nil
+}
+
+// ImportSigners imports signers from the given export, decrypting them
+// with the given passphrase, and saves them in the given key store.
+// The import is refused if the key store already holds any signer of the
+// exported wallet, i.e. the wallet is already actively used by the node,
+// or if any of the exported signers does not belong to the given operator.
+// The public key hash of the imported wallet and the number of imported
+// signers are returned.
+func ImportSigners(
+	keyStorePersistence persistence.ProtectedHandle,
+	operatorAddress chain.Address,
+	export []byte,
+	passphrase string,
+) ([20]byte, int, error) {
+	signers, err := decryptSignersExport(export, passphrase)
+	if err != nil {
+		return [20]byte{}, 0, err
+	}
+
+	if len(signers) == 0 {
+		return [20]byte{}, 0, fmt.Errorf("export does not contain signers")
+	}
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signers[0].wallet.publicKey)
+
+	for _, signer := range signers {
+		if bitcoin.PublicKeyHash(signer.wallet.publicKey) !=
+			walletPublicKeyHash {
+			return [20]byte{}, 0, fmt.Errorf(
+				"export contains signers of multiple wallets",
+			)
+		}
+
+		memberIndex := int(signer.signingGroupMemberIndex)
+		operators := signer.wallet.signingGroupOperators
+		if memberIndex < 1 || memberIndex > len(operators) {
+			return [20]byte{}, 0, fmt.Errorf(
+				"signer has invalid member index [%v]",
+				memberIndex,
+			)
+		}
+
+		if !strings.EqualFold(
+			operators[memberIndex-1].String(),
+			operatorAddress.String(),
+		) {
+			return [20]byte{}, 0, fmt.Errorf(
+				"signer with member index [%v] belongs to operator [%v] "+
+					"instead of [%v]",
+				memberIndex,
+				operators[memberIndex-1],
+				operatorAddress,
+			)
+		}
+	}
+
+	existing, err := findSigners(keyStorePersistence, walletPublicKeyHash)
+	if err != nil {
+		return [20]byte{}, 0, fmt.Errorf(
+			"cannot find existing signers: [%v]",
+			err,
+		)
+	}
+	if len(existing) > 0 {
+		return [20]byte{}, 0, fmt.Errorf(
+			"key store already holds [%v] signers of wallet [0x%x]",
+			len(existing),
+			walletPublicKeyHash,
+		)
+	}
+
+	walletStorage := newWalletStorage(keyStorePersistence)
+	for _, signer := range signers {
+		if err := walletStorage.saveSigner(signer); err != nil {
+			return [20]byte{}, 0, fmt.Errorf(
+				"cannot save signer with member index [%v]: [%v]",
+				signer.signingGroupMemberIndex,
+				err,
+			)
+		}
+	}
+
+	return walletPublicKeyHash, len(signers), nil
+}
+
+// decryptSignersExport decrypts signers from the given export using
+// the given passphrase.
+func decryptSignersExport(export []byte, passphrase string) ([]*signer, error) {
+	parsed := &signersExport{}
+	if err := json.Unmarshal(export, parsed); err != nil {
+		return nil, fmt.Errorf("cannot parse signers export: [%v]", err)
+	}
+
+	if parsed.Version != signersExportVersion {
+		return nil, fmt.Errorf(
+			"unsupported signers export version [%v]",
+			parsed.Version,
+		)
+	}
+
+	box, err := newSignersExportBox(passphrase, parsed.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := box.Decrypt(parsed.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot decrypt signers; wrong passphrase?: [%v]",
+			err,
+		)
+	}
+
+	var signersBytes [][]byte
+	if err := json.Unmarshal(plaintext, &signersBytes); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal signers: [%v]", err)
+	}
+
+	signers := make([]*signer, len(signersBytes))
+	for i, signerBytes := range signersBytes {
+		signers[i] = &signer{}
+		if err := signers[i].Unmarshal(signerBytes); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal signer: [%v]", err)
+		}
+	}
+
+	return signers, nil
+}
+
+// newSignersExportBox creates the box encrypting signers exports with a key
+// derived from the given passphrase and salt.
+func newSignersExportBox(
+	passphrase string,
+	salt []byte,
+) (encryption.Box, error) {
+	key, err := scrypt.Key(
+		[]byte(passphrase),
+		salt,
+		signersExportScryptN,
+		signersExportScryptR,
+		signersExportScryptP,
+		encryption.KeyLength,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot derive encryption key: [%v]", err)
+	}
+
+	var boxKey [encryption.KeyLength]byte
+	copy(boxKey[:], key)
+
+	return encryption.NewBox(boxKey), nil
+}
+
+// findSigners returns signers of the wallet with the given public key hash
+// held in the given key store. An error is returned if any signer in the key
+// store could not be read.
+func findSigners(
+	keyStorePersistence persistence.ProtectedHandle,
+	walletPublicKeyHash [20]byte,
+) ([]*signer, error) {
+	signersByWallet, _, err := newWalletStorage(
+		keyStorePersistence,
+	).loadSigners()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signers := range signersByWallet {
+		if len(signers) == 0 {
+			continue
+		}
+
+		if bitcoin.PublicKeyHash(signers[0].wallet.publicKey) ==
+			walletPublicKeyHash {
+			return signers, nil
+		}
+	}
+
+	return nil, nil
+}
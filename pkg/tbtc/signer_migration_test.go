@@ -0,0 +1,175 @@
+package tbtc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+func TestExportImportSigners(t *testing.T) {
+	signer := createMockSigner(t)
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+	sourceKeyStore := createMockKeyStorePersistence(t, signer)
+
+	export, archive, err := ExportSigners(
+		sourceKeyStore,
+		walletPublicKeyHash,
+		"secret",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Signers are not archived until the export is committed.
+	testutils.AssertIntsEqual(
+		t,
+		"source signers count",
+		1,
+		len(mustFindSigners(t, sourceKeyStore, walletPublicKeyHash)),
+	)
+
+	if err := archive(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exported signers must be archived on the source node.
+	testutils.AssertIntsEqual(
+		t,
+		"source signers count",
+		0,
+		len(mustFindSigners(t, sourceKeyStore, walletPublicKeyHash)),
+	)
+
+	targetKeyStore := createMockKeyStorePersistence(t)
+
+	importedWalletPublicKeyHash, count, err := ImportSigners(
+		targetKeyStore,
+		"address-1",
+		export,
+		"secret",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertBytesEqual(
+		t,
+		walletPublicKeyHash[:],
+		importedWalletPublicKeyHash[:],
+	)
+	testutils.AssertIntsEqual(t, "imported signers count", 1, count)
+
+	importedSigners := mustFindSigners(t, targetKeyStore, walletPublicKeyHash)
+	if len(importedSigners) != 1 {
+		t.Fatalf("unexpected imported signers count: [%v]", len(importedSigners))
+	}
+	if !reflect.DeepEqual(signer, importedSigners[0]) {
+		t.Errorf("imported signer does not match the exported one")
+	}
+}
+
+func TestExportSigners_UnknownWallet(t *testing.T) {
+	keyStore := createMockKeyStorePersistence(t, createMockSigner(t))
+
+	_, _, err := ExportSigners(keyStore, [20]byte{0x01}, "secret")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestImportSigners(t *testing.T) {
+	mockSigner := createMockSigner(t)
+	walletPublicKeyHash := bitcoin.PublicKeyHash(mockSigner.wallet.publicKey)
+
+	tests := map[string]struct {
+		targetSigners   []*signer
+		operatorAddress string
+		passphrase      string
+		expectedError   bool
+	}{
+		"valid import": {
+			operatorAddress: "address-1",
+			passphrase:      "secret",
+			expectedError:   false,
+		},
+		"wrong passphrase": {
+			operatorAddress: "address-1",
+			passphrase:      "wrong",
+			expectedError:   true,
+		},
+		"another operator": {
+			operatorAddress: "address-2",
+			passphrase:      "secret",
+			expectedError:   true,
+		},
+		"wallet already used by the node": {
+			targetSigners:   []*signer{mockSigner},
+			operatorAddress: "address-1",
+			passphrase:      "secret",
+			expectedError:   true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			export, _, err := ExportSigners(
+				createMockKeyStorePersistence(t, mockSigner),
+				walletPublicKeyHash,
+				"secret",
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			targetKeyStore := createMockKeyStorePersistence(
+				t,
+				test.targetSigners...,
+			)
+
+			_, _, err = ImportSigners(
+				targetKeyStore,
+				chain.Address(test.operatorAddress),
+				export,
+				test.passphrase,
+			)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error",
+				test.expectedError,
+				err != nil,
+			)
+
+			expectedSignersCount := len(test.targetSigners)
+			if !test.expectedError {
+				expectedSignersCount = 1
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"target signers count",
+				expectedSignersCount,
+				len(mustFindSigners(t, targetKeyStore, walletPublicKeyHash)),
+			)
+		})
+	}
+}
+
+func mustFindSigners(
+	t *testing.T,
+	keyStorePersistence persistence.ProtectedHandle,
+	walletPublicKeyHash [20]byte,
+) []*signer {
+	signers, err := findSigners(keyStorePersistence, walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signers
+}
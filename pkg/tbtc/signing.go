@@ -2,11 +2,14 @@ package tbtc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/announcer"
@@ -58,6 +61,18 @@ type signingExecutor struct {
 	// be made by a single signer for the given message. Once the attempts
 	// limit is hit the signer gives up.
 	signingAttemptsLimit uint
+
+	// reliabilityStore keeps track of signing group members that failed to
+	// announce their readiness for signing attempts in the past.
+	reliabilityStore *signingReliabilityStore
+
+	// misbehaviorEvidence persists evidence of signing group members
+	// misbehavior observed during signing.
+	misbehaviorEvidence *misbehaviorEvidenceStore
+
+	// metrics records outcomes of signing attempts and signings executed
+	// by this executor.
+	metrics *signingMetrics
 }
 
 func newSigningExecutor(
@@ -69,6 +84,9 @@ func newSigningExecutor(
 	getCurrentBlockFn getCurrentBlockFn,
 	waitForBlockFn waitForBlockFn,
 	signingAttemptsLimit uint,
+	reliabilityStore *signingReliabilityStore,
+	misbehaviorEvidence *misbehaviorEvidenceStore,
+	metrics *signingMetrics,
 ) *signingExecutor {
 	return &signingExecutor{
 		lock:                 semaphore.NewWeighted(1),
@@ -80,6 +98,9 @@ func newSigningExecutor(
 		getCurrentBlockFn:    getCurrentBlockFn,
 		waitForBlockFn:       waitForBlockFn,
 		signingAttemptsLimit: signingAttemptsLimit,
+		reliabilityStore:     reliabilityStore,
+		misbehaviorEvidence:  misbehaviorEvidence,
+		metrics:              metrics,
 	}
 }
 
@@ -91,6 +112,7 @@ func newSigningExecutor(
 // to the first message, and so on.
 func (se *signingExecutor) signBatch(
 	ctx context.Context,
+	actionType WalletActionType,
 	messages []*big.Int,
 	startBlock uint64,
 ) ([]*tecdsa.Signature, error) {
@@ -145,7 +167,12 @@ func (se *signingExecutor) signBatch(
 			signingStartBlock = endBlocks[i-1] + signingBatchInterludeBlocks
 		}
 
-		signature, endBlock, err := se.sign(ctx, message, signingStartBlock)
+		signature, endBlock, err := se.sign(
+			ctx,
+			actionType,
+			message,
+			signingStartBlock,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -171,6 +198,7 @@ func (se *signingExecutor) signBatch(
 // all wallet signers so can be used as a synchronization point.
 func (se *signingExecutor) sign(
 	ctx context.Context,
+	actionType WalletActionType,
 	message *big.Int,
 	startBlock uint64,
 ) (*tecdsa.Signature, uint64, error) {
@@ -186,6 +214,26 @@ func (se *signingExecutor) sign(
 		return nil, 0, fmt.Errorf("cannot marshal wallet public key: [%v]", err)
 	}
 
+	walletPublicKeyHash := bitcoin.PublicKeyHash(wallet.publicKey)
+
+	metricsKey := signingMetricsKey{
+		walletPublicKeyHash: walletPublicKeyHash,
+		actionType:          actionType,
+	}
+
+	// Capture the members reliability history once, so all controlled
+	// signers select attempt participants based on the same history.
+	reliability := newSigningMembersReliability(
+		se.reliabilityStore,
+		walletPublicKeyHash,
+		wallet.groupSize(),
+	)
+
+	evidenceRecorder := se.misbehaviorEvidence.recorder(
+		misbehaviorEvidenceProtocolSigning,
+		wallet.signingGroupOperators,
+	)
+
 	loopTimeoutBlock := startBlock +
 		uint64(se.signingAttemptsLimit*signingAttemptMaximumBlocks())
 
@@ -232,6 +280,7 @@ func (se *signingExecutor) sign(
 				wallet.signingGroupOperators,
 				se.groupParameters,
 				announcer,
+				reliability,
 				doneCheck,
 			)
 
@@ -292,13 +341,15 @@ func (se *signingExecutor) sign(
 						attempt.number,
 					)
 
+					attemptStartTime := time.Now()
+
 					result, err := signing.Execute(
 						attemptCtx,
 						signingAttemptLogger,
 						message,
 						sessionID,
 						signer.signingGroupMemberIndex,
-						signer.privateKeyShare,
+						signer.keyShareSigner(),
 						wallet.groupSize(),
 						wallet.groupDishonestThreshold(
 							se.groupParameters.HonestThreshold,
@@ -306,7 +357,23 @@ func (se *signingExecutor) sign(
 						attempt.excludedMembersIndexes,
 						se.broadcastChannel,
 						se.membershipValidator,
+						evidenceRecorder,
+					)
+
+					completedRounds := 0
+					var executionErr *signing.ExecutionError
+					if errors.As(err, &executionErr) {
+						completedRounds = executionErr.CompletedStates
+					}
+
+					se.metrics.recordAttempt(
+						metricsKey,
+						time.Since(attemptStartTime),
+						len(attempt.excludedMembersIndexes),
+						err != nil,
+						completedRounds,
 					)
+
 					if err != nil {
 						return nil, 0, err
 					}
@@ -383,8 +450,10 @@ func (se *signingExecutor) sign(
 	// signer, that means all signers failed and have not produced a signature.
 	select {
 	case outcome := <-signingOutcomeChan:
+		se.metrics.recordOutcome(metricsKey, true)
 		return outcome.signature, outcome.endBlock, nil
 	default:
+		se.metrics.recordOutcome(metricsKey, false)
 		return nil, 0, fmt.Errorf("all signers failed")
 	}
 }
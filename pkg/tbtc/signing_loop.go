@@ -33,6 +33,15 @@ const (
 	// signingAttemptCoolDownBlocks determines the duration of the cool down
 	// period that is preserved between subsequent signing attempts.
 	signingAttemptCoolDownBlocks = 5
+	// signingAdaptiveExclusionAttempts determines the number of initial
+	// signing attempts during which the members selection prefers members
+	// that reliably announced their readiness in the past. The history of
+	// announcements is recorded locally so members may see different
+	// histories, e.g. after losing the node's work directory. Such members
+	// select different attempt participants and the attempt fails, so
+	// subsequent attempts fall back to the selection based solely on the
+	// data shared by all members.
+	signingAdaptiveExclusionAttempts = 2
 )
 
 // signingAttemptMaximumBlocks returns the maximum block duration of a single
@@ -54,6 +63,21 @@ type signingAnnouncer interface {
 	) ([]group.MemberIndex, error)
 }
 
+// signingReliabilityTracker represents a component tracking how reliably
+// signing group members announce their readiness for signing attempts.
+type signingReliabilityTracker interface {
+	// recordAnnouncement records the outcome of the announcement phase of
+	// the given session.
+	recordAnnouncement(
+		sessionID string,
+		readyMembersIndexes []group.MemberIndex,
+	) error
+
+	// missedAnnouncements returns the number of announcement phases missed
+	// by the given member in the past.
+	missedAnnouncements(memberIndex group.MemberIndex) uint64
+}
+
 // signingDoneCheckStrategy is a strategy that determines the way of signaling
 // a successful signature calculation across all signing group members.
 type signingDoneCheckStrategy interface {
@@ -88,7 +112,8 @@ type signingRetryLoop struct {
 
 	groupParameters *GroupParameters
 
-	announcer signingAnnouncer
+	announcer   signingAnnouncer
+	reliability signingReliabilityTracker
 
 	attemptCounter    uint
 	attemptStartBlock uint64
@@ -105,6 +130,7 @@ func newSigningRetryLoop(
 	signingGroupOperators chain.Addresses,
 	groupParameters *GroupParameters,
 	announcer signingAnnouncer,
+	reliability signingReliabilityTracker,
 	doneCheck signingDoneCheckStrategy,
 ) *signingRetryLoop {
 	// Compute the 8-byte seed needed for the random retry algorithm. We take
@@ -121,6 +147,7 @@ func newSigningRetryLoop(
 		signingGroupOperators:   signingGroupOperators,
 		groupParameters:         groupParameters,
 		announcer:               announcer,
+		reliability:             reliability,
 		attemptCounter:          0,
 		attemptStartBlock:       initialStartBlock,
 		attemptSeed:             attemptSeed,
@@ -246,10 +273,16 @@ func (srl *signingRetryLoop) start(
 			srl.attemptCounter,
 		)
 
+		announcementSessionID := fmt.Sprintf(
+			"%v-%v",
+			srl.message,
+			srl.attemptCounter,
+		)
+
 		readyMembersIndexes, err := srl.announcer.Announce(
 			announceCtx,
 			srl.signingGroupMemberIndex,
-			fmt.Sprintf("%v-%v", srl.message, srl.attemptCounter),
+			announcementSessionID,
 		)
 		if err != nil {
 			srl.logger.Warnf(
@@ -267,6 +300,19 @@ func (srl *signingRetryLoop) start(
 			len(srl.signingGroupOperators),
 		)
 
+		err = srl.reliability.recordAnnouncement(
+			announcementSessionID,
+			readyMembersIndexes,
+		)
+		if err != nil {
+			srl.logger.Warnf(
+				"[member:%v] cannot record announcement for attempt [%v]: [%v]",
+				srl.signingGroupMemberIndex,
+				srl.attemptCounter,
+				err,
+			)
+		}
+
 		// Check the loop stop signal again. The announcement took some time
 		// and the context may be done now.
 		if ctx.Err() != nil {
@@ -530,6 +576,21 @@ func (srl *signingRetryLoop) excludedMembersIndexes(
 			includedMembersIndexes[i], includedMembersIndexes[j] =
 				includedMembersIndexes[j], includedMembersIndexes[i]
 		})
+		// During the initial attempts, move members that missed the most
+		// announcements in the past to the end of the included members
+		// slice so they are excluded first. The stable sort preserves the
+		// shuffled order of members with the same history.
+		if srl.attemptCounter <= signingAdaptiveExclusionAttempts {
+			sort.SliceStable(includedMembersIndexes, func(i, j int) bool {
+				missedI := srl.reliability.missedAnnouncements(
+					includedMembersIndexes[i],
+				)
+				missedJ := srl.reliability.missedAnnouncements(
+					includedMembersIndexes[j],
+				)
+				return missedI < missedJ
+			})
+		}
 		// Get the surplus of included members and add them to
 		// the excluded members list.
 		excludedMembersIndexes = append(
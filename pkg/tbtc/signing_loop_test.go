@@ -55,6 +55,7 @@ func TestSigningRetryLoop(t *testing.T) {
 
 	var tests = map[string]struct {
 		signingGroupMemberIndex     group.MemberIndex
+		missedAnnouncements         map[group.MemberIndex]uint64
 		ctxFn                       func() (context.Context, context.CancelFunc)
 		currentBlockFn              getCurrentBlockFn
 		incomingAnnouncementsFn     func(sessionID string) ([]group.MemberIndex, error)
@@ -559,6 +560,63 @@ func TestSigningRetryLoop(t *testing.T) {
 			// just the second announcement, the first one was skipped
 			outgoingAnnouncementsCount: 1,
 		},
+		"first attempt in the past with unreliable member": {
+			signingGroupMemberIndex: 3,
+			missedAnnouncements: map[group.MemberIndex]uint64{
+				4: 2,
+			},
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 1*time.Second)
+			},
+			currentBlockFn: func() (uint64, error) {
+				// The initial start block is 200 and the announcement takes 6
+				// blocks; we are at the end of the announcement phase so the
+				// first attempt should be skipped.
+				return 206, nil
+			},
+			incomingAnnouncementsFn: func(
+				sessionID string,
+			) ([]group.MemberIndex, error) {
+				return signingGroupMembersIndexes, nil
+			},
+			signingAttemptFn: func(
+				attempt *signingAttemptParams,
+			) (*signing.Result, uint64, error) {
+				return testResult, 260, nil // an arbitrary end block
+			},
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+				// Simulate that the done check phase determines the same
+				// end block as the executing signer.
+				return testResult, 260, nil
+			},
+			expectedOutgoingDoneChecks: []*signingDoneMessage{
+				{
+					senderID:      3,
+					message:       message,
+					attemptNumber: 2,
+					signature:     testResult.Signature,
+					endBlock:      260,
+				},
+			},
+			expectedErr: nil,
+			expectedResult: &signingRetryLoopResult{
+				result:              testResult,
+				latestEndBlock:      260, // the end block resolved by the done check phase
+				attemptTimeoutBlock: 277, // start block of the second attempt + 30
+			},
+			// Same as above but member 4 missed announcements in the past.
+			// The additional exclusion round that trims the included members
+			// list to the honest threshold size adds member 4 instead of
+			// member 9 to the final excluded members list.
+			expectedLastExecutedAttempt: &signingAttemptParams{
+				number:                 2,
+				startBlock:             247, // 206 + 1 * (6 + 30 + 5)
+				timeoutBlock:           277, // start block of the second attempt + 30
+				excludedMembersIndexes: []group.MemberIndex{1, 2, 4, 5},
+			},
+			// just the second announcement, the first one was skipped
+			outgoingAnnouncementsCount: 1,
+		},
 	}
 
 	for testName, test := range tests {
@@ -572,6 +630,10 @@ func TestSigningRetryLoop(t *testing.T) {
 				waitUntilAllDoneOutcomeFn: test.waitUntilAllDoneOutcomeFn,
 			}
 
+			reliability := &mockSigningReliabilityTracker{
+				missed: test.missedAnnouncements,
+			}
+
 			retryLoop := newSigningRetryLoop(
 				&testutils.MockLogger{},
 				message,
@@ -580,6 +642,7 @@ func TestSigningRetryLoop(t *testing.T) {
 				signingGroupOperators,
 				groupParameters,
 				announcer,
+				reliability,
 				doneCheck,
 			)
 
@@ -690,6 +753,23 @@ func (msa *mockSigningAnnouncer) Announce(
 	return msa.incomingAnnouncementsFn(sessionID)
 }
 
+type mockSigningReliabilityTracker struct {
+	missed map[group.MemberIndex]uint64
+}
+
+func (msrt *mockSigningReliabilityTracker) recordAnnouncement(
+	sessionID string,
+	readyMembersIndexes []group.MemberIndex,
+) error {
+	return nil
+}
+
+func (msrt *mockSigningReliabilityTracker) missedAnnouncements(
+	memberIndex group.MemberIndex,
+) uint64 {
+	return msrt.missed[memberIndex]
+}
+
 type mockSigningDoneCheck struct {
 	outgoingDoneChecks        []*signingDoneMessage
 	currentAttemptNumber      uint64
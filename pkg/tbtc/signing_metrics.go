@@ -0,0 +1,183 @@
+package tbtc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+// signingMetricsKey identifies signing metrics of the given action type
+// performed by the given wallet.
+type signingMetricsKey struct {
+	walletPublicKeyHash [20]byte
+	actionType          WalletActionType
+}
+
+func (smk signingMetricsKey) String() string {
+	return fmt.Sprintf("0x%x/%s", smk.walletPublicKeyHash, smk.actionType)
+}
+
+// signingMetricsEntry holds signing metrics of a single wallet and action
+// type.
+type signingMetricsEntry struct {
+	// attemptsCount is the number of executed signing attempts.
+	attemptsCount uint64
+	// failedAttemptsCount is the number of failed signing attempts.
+	failedAttemptsCount uint64
+	// totalAttemptsDuration is the total duration of all signing attempts.
+	totalAttemptsDuration time.Duration
+	// lastAttemptDuration is the duration of the most recent attempt.
+	lastAttemptDuration time.Duration
+	// lastAttemptExcludedMembers is the number of members excluded from
+	// the most recent attempt.
+	lastAttemptExcludedMembers int
+	// lastFailedAttemptCompletedRounds is the number of protocol rounds
+	// completed by the member during the most recent failed attempt.
+	lastFailedAttemptCompletedRounds int
+	// successesCount is the number of messages signed successfully.
+	successesCount uint64
+	// failuresCount is the number of messages that could not be signed.
+	failuresCount uint64
+}
+
+// averageAttemptDuration returns the average duration of an attempt or zero
+// if no attempt was executed.
+func (sme *signingMetricsEntry) averageAttemptDuration() time.Duration {
+	if sme.attemptsCount == 0 {
+		return 0
+	}
+
+	return sme.totalAttemptsDuration / time.Duration(sme.attemptsCount)
+}
+
+// signingMetrics records outcomes of signing attempts and signings performed
+// by the node's signing executors. It is safe for concurrent use.
+type signingMetrics struct {
+	mutex   sync.Mutex
+	entries map[signingMetricsKey]*signingMetricsEntry
+}
+
+func newSigningMetrics() *signingMetrics {
+	return &signingMetrics{
+		entries: make(map[signingMetricsKey]*signingMetricsEntry),
+	}
+}
+
+// entry returns the entry for the given key, creating it if it does not
+// exist. Must be called with the mutex locked.
+func (sm *signingMetrics) entry(key signingMetricsKey) *signingMetricsEntry {
+	entry, ok := sm.entries[key]
+	if !ok {
+		entry = &signingMetricsEntry{}
+		sm.entries[key] = entry
+	}
+
+	return entry
+}
+
+// recordAttempt records a single signing attempt. Completed rounds are only
+// meaningful for failed attempts.
+func (sm *signingMetrics) recordAttempt(
+	key signingMetricsKey,
+	duration time.Duration,
+	excludedMembers int,
+	failed bool,
+	completedRounds int,
+) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	entry := sm.entry(key)
+
+	entry.attemptsCount++
+	entry.totalAttemptsDuration += duration
+	entry.lastAttemptDuration = duration
+	entry.lastAttemptExcludedMembers = excludedMembers
+
+	if failed {
+		entry.failedAttemptsCount++
+		entry.lastFailedAttemptCompletedRounds = completedRounds
+	}
+}
+
+// recordOutcome records the final outcome of signing a single message.
+func (sm *signingMetrics) recordOutcome(key signingMetricsKey, success bool) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	entry := sm.entry(key)
+
+	if success {
+		entry.successesCount++
+	} else {
+		entry.failuresCount++
+	}
+}
+
+// total returns metrics summed over all wallets and action types. Fields
+// describing the last attempt are not meaningful for the total and are left
+// empty.
+func (sm *signingMetrics) total() *signingMetricsEntry {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	total := &signingMetricsEntry{}
+	for _, entry := range sm.entries {
+		total.attemptsCount += entry.attemptsCount
+		total.failedAttemptsCount += entry.failedAttemptsCount
+		total.totalAttemptsDuration += entry.totalAttemptsDuration
+		total.successesCount += entry.successesCount
+		total.failuresCount += entry.failuresCount
+	}
+
+	return total
+}
+
+// sources returns metrics sources exposing signing metrics summed over all
+// wallets and action types. The metrics registry identifies metrics by name
+// so the per-wallet and per-action breakdown is exposed by info instead.
+func (sm *signingMetrics) sources() map[string]clientinfo.Source {
+	return map[string]clientinfo.Source{
+		"signing_attempts_count": func() float64 {
+			return float64(sm.total().attemptsCount)
+		},
+		"signing_failed_attempts_count": func() float64 {
+			return float64(sm.total().failedAttemptsCount)
+		},
+		"signing_average_attempt_duration_seconds": func() float64 {
+			return sm.total().averageAttemptDuration().Seconds()
+		},
+		"signing_successes_count": func() float64 {
+			return float64(sm.total().successesCount)
+		},
+		"signing_failures_count": func() float64 {
+			return float64(sm.total().failuresCount)
+		},
+	}
+}
+
+// info returns signing metrics of all wallets and action types, keyed in
+// the `0x<wallet-public-key-hash>/<action>` format.
+func (sm *signingMetrics) info() clientinfo.ApplicationInfo {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	info := clientinfo.ApplicationInfo{}
+
+	for key, entry := range sm.entries {
+		info[key.String()] = map[string]interface{}{
+			"attempts_count":                       entry.attemptsCount,
+			"failed_attempts_count":                entry.failedAttemptsCount,
+			"average_attempt_duration_seconds":     entry.averageAttemptDuration().Seconds(),
+			"last_attempt_duration_seconds":        entry.lastAttemptDuration.Seconds(),
+			"last_attempt_excluded_members":        entry.lastAttemptExcludedMembers,
+			"last_failed_attempt_completed_rounds": entry.lastFailedAttemptCompletedRounds,
+			"successes_count":                      entry.successesCount,
+			"failures_count":                       entry.failuresCount,
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,80 @@
+package tbtc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+func TestSigningMetrics(t *testing.T) {
+	metrics := newSigningMetrics()
+
+	sweepKey := signingMetricsKey{
+		walletPublicKeyHash: [20]byte{0x01},
+		actionType:          ActionDepositSweep,
+	}
+	heartbeatKey := signingMetricsKey{
+		walletPublicKeyHash: [20]byte{0x01},
+		actionType:          ActionHeartbeat,
+	}
+
+	metrics.recordAttempt(sweepKey, 4*time.Second, 0, true, 3)
+	metrics.recordAttempt(sweepKey, 2*time.Second, 2, false, 0)
+	metrics.recordOutcome(sweepKey, true)
+
+	metrics.recordAttempt(heartbeatKey, 6*time.Second, 1, true, 5)
+	metrics.recordOutcome(heartbeatKey, false)
+
+	total := metrics.total()
+	testutils.AssertUintsEqual(t, "attempts count", 3, total.attemptsCount)
+	testutils.AssertUintsEqual(
+		t,
+		"failed attempts count",
+		2,
+		total.failedAttemptsCount,
+	)
+	testutils.AssertUintsEqual(t, "successes count", 1, total.successesCount)
+	testutils.AssertUintsEqual(t, "failures count", 1, total.failuresCount)
+
+	if total.averageAttemptDuration() != 4*time.Second {
+		t.Errorf(
+			"unexpected average attempt duration\nexpected: [%v]\nactual:   [%v]",
+			4*time.Second,
+			total.averageAttemptDuration(),
+		)
+	}
+
+	expectedInfo := clientinfo.ApplicationInfo{
+		"0x0100000000000000000000000000000000000000/DepositSweep": map[string]interface{}{
+			"attempts_count":                       uint64(2),
+			"failed_attempts_count":                uint64(1),
+			"average_attempt_duration_seconds":     float64(3),
+			"last_attempt_duration_seconds":        float64(2),
+			"last_attempt_excluded_members":        2,
+			"last_failed_attempt_completed_rounds": 3,
+			"successes_count":                      uint64(1),
+			"failures_count":                       uint64(0),
+		},
+		"0x0100000000000000000000000000000000000000/Heartbeat": map[string]interface{}{
+			"attempts_count":                       uint64(1),
+			"failed_attempts_count":                uint64(1),
+			"average_attempt_duration_seconds":     float64(6),
+			"last_attempt_duration_seconds":        float64(6),
+			"last_attempt_excluded_members":        1,
+			"last_failed_attempt_completed_rounds": 5,
+			"successes_count":                      uint64(0),
+			"failures_count":                       uint64(1),
+		},
+	}
+
+	if info := metrics.info(); !reflect.DeepEqual(expectedInfo, info) {
+		t.Errorf(
+			"unexpected info\nexpected: [%v]\nactual:   [%v]",
+			expectedInfo,
+			info,
+		)
+	}
+}
@@ -0,0 +1,249 @@
+package tbtc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+	"github.com/keep-network/keep-core/pkg/protocol/announcer"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+const (
+	// signingReliabilityDirectory is the name of the directory the signing
+	// reliability store keeps its entries in.
+	signingReliabilityDirectory = "signing_reliability"
+
+	// signingReliabilityWindow determines the number of announcements after
+	// which the recorded history of a wallet is halved. This way, members
+	// that were unreliable in the past but behave correctly now are not
+	// penalized forever.
+	signingReliabilityWindow = 100
+)
+
+// walletSigningReliability is the history of signing announcements of
+// a single wallet.
+type walletSigningReliability struct {
+	// ObservedAnnouncements is the number of recorded announcement phases.
+	ObservedAnnouncements uint64
+	// MissedAnnouncements holds the number of recorded announcement phases
+	// missed by the given signing group member.
+	MissedAnnouncements map[group.MemberIndex]uint64
+}
+
+// signingReliabilityStore keeps track of signing group members that
+// historically failed to announce their readiness for signing attempts of
+// the given wallet. The history is persisted so it survives node restarts.
+type signingReliabilityStore struct {
+	mutex sync.Mutex
+
+	persistence persistence.BasicHandle
+	wallets     map[string]*walletSigningReliability
+}
+
+// newSigningReliabilityStore creates a new store backed by the given
+// persistence handle and loads all histories persisted so far. Histories
+// that cannot be read are logged and skipped.
+func newSigningReliabilityStore(
+	persistence persistence.BasicHandle,
+) *signingReliabilityStore {
+	store := &signingReliabilityStore{
+		persistence: persistence,
+		wallets:     make(map[string]*walletSigningReliability),
+	}
+
+	store.load()
+
+	return store
+}
+
+func (srs *signingReliabilityStore) load() {
+	persistenceutil.ReadAll(
+		srs.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != signingReliabilityDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read signing reliability from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			reliability := &walletSigningReliability{}
+			if err := json.Unmarshal(content, reliability); err != nil {
+				logger.Errorf(
+					"could not parse signing reliability from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			srs.wallets[descriptor.Name()] = reliability
+		},
+		func(err error) {
+			logger.Errorf(
+				"could not load signing reliability from disk: [%v]",
+				err,
+			)
+		},
+	)
+}
+
+// recordAnnouncement records the outcome of an announcement phase of the
+// given wallet. Members not present in the ready members list are considered
+// as those who missed the announcement.
+func (srs *signingReliabilityStore) recordAnnouncement(
+	walletPublicKeyHash [20]byte,
+	groupSize int,
+	readyMembersIndexes []group.MemberIndex,
+) error {
+	srs.mutex.Lock()
+	defer srs.mutex.Unlock()
+
+	name := hex.EncodeToString(walletPublicKeyHash[:])
+
+	reliability, ok := srs.wallets[name]
+	if !ok {
+		reliability = &walletSigningReliability{
+			MissedAnnouncements: make(map[group.MemberIndex]uint64),
+		}
+	}
+
+	if reliability.ObservedAnnouncements >= signingReliabilityWindow {
+		reliability.ObservedAnnouncements /= 2
+		for memberIndex, missed := range reliability.MissedAnnouncements {
+			if missed/2 == 0 {
+				delete(reliability.MissedAnnouncements, memberIndex)
+			} else {
+				reliability.MissedAnnouncements[memberIndex] = missed / 2
+			}
+		}
+	}
+
+	reliability.ObservedAnnouncements++
+	for _, memberIndex := range announcer.UnreadyMembers(
+		readyMembersIndexes,
+		groupSize,
+	) {
+		reliability.MissedAnnouncements[memberIndex]++
+	}
+
+	content, err := json.Marshal(reliability)
+	if err != nil {
+		return fmt.Errorf("cannot marshal signing reliability: [%v]", err)
+	}
+
+	if err := srs.persistence.Save(
+		content,
+		signingReliabilityDirectory,
+		name,
+	); err != nil {
+		return fmt.Errorf("cannot save signing reliability: [%v]", err)
+	}
+
+	srs.wallets[name] = reliability
+
+	return nil
+}
+
+// missedAnnouncements returns the number of recorded announcement phases
+// missed by signing group members of the given wallet. Members that never
+// missed an announcement are not included.
+func (srs *signingReliabilityStore) missedAnnouncements(
+	walletPublicKeyHash [20]byte,
+) map[group.MemberIndex]uint64 {
+	srs.mutex.Lock()
+	defer srs.mutex.Unlock()
+
+	result := make(map[group.MemberIndex]uint64)
+
+	reliability, ok := srs.wallets[hex.EncodeToString(walletPublicKeyHash[:])]
+	if !ok {
+		return result
+	}
+
+	for memberIndex, missed := range reliability.MissedAnnouncements {
+		result[memberIndex] = missed
+	}
+
+	return result
+}
+
+// signingMembersReliability is a view of the signing reliability history of
+// a single wallet used by a single signing. The history is captured at the
+// moment the view is created so all signers controlled by the node base
+// their members selection on the same history during the whole signing.
+// Announcements recorded through the view are deduplicated as all signers
+// controlled by the node observe the same announcement phases.
+type signingMembersReliability struct {
+	mutex sync.Mutex
+
+	store               *signingReliabilityStore
+	walletPublicKeyHash [20]byte
+	groupSize           int
+
+	missed           map[group.MemberIndex]uint64
+	recordedSessions map[string]bool
+}
+
+// newSigningMembersReliability creates a view of the signing reliability
+// history of the given wallet.
+func newSigningMembersReliability(
+	store *signingReliabilityStore,
+	walletPublicKeyHash [20]byte,
+	groupSize int,
+) *signingMembersReliability {
+	return &signingMembersReliability{
+		store:               store,
+		walletPublicKeyHash: walletPublicKeyHash,
+		groupSize:           groupSize,
+		missed:              store.missedAnnouncements(walletPublicKeyHash),
+		recordedSessions:    make(map[string]bool),
+	}
+}
+
+// recordAnnouncement records the outcome of the announcement phase of the
+// given session unless it has already been recorded.
+func (smr *signingMembersReliability) recordAnnouncement(
+	sessionID string,
+	readyMembersIndexes []group.MemberIndex,
+) error {
+	smr.mutex.Lock()
+	defer smr.mutex.Unlock()
+
+	if smr.recordedSessions[sessionID] {
+		return nil
+	}
+
+	err := smr.store.recordAnnouncement(
+		smr.walletPublicKeyHash,
+		smr.groupSize,
+		readyMembersIndexes,
+	)
+	if err != nil {
+		return err
+	}
+
+	smr.recordedSessions[sessionID] = true
+
+	return nil
+}
+
+// missedAnnouncements returns the number of announcement phases missed by
+// the given member, according to the history captured at the moment the
+// view was created.
+func (smr *signingMembersReliability) missedAnnouncements(
+	memberIndex group.MemberIndex,
+) uint64 {
+	return smr.missed[memberIndex]
+}
@@ -0,0 +1,171 @@
+package tbtc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestSigningReliabilityStore(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	walletPublicKeyHash := [20]byte{0x01}
+	otherWalletPublicKeyHash := [20]byte{0x02}
+
+	store := newSigningReliabilityStore(persistenceHandle)
+
+	err := store.recordAnnouncement(
+		walletPublicKeyHash,
+		5,
+		[]group.MemberIndex{1, 2, 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.recordAnnouncement(
+		walletPublicKeyHash,
+		5,
+		[]group.MemberIndex{1, 2, 3, 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The history should be loaded by a store created after a restart.
+	restartedStore := newSigningReliabilityStore(persistenceHandle)
+
+	assertMissedAnnouncements(
+		t,
+		map[group.MemberIndex]uint64{4: 1, 5: 2},
+		restartedStore.missedAnnouncements(walletPublicKeyHash),
+	)
+	assertMissedAnnouncements(
+		t,
+		map[group.MemberIndex]uint64{},
+		restartedStore.missedAnnouncements(otherWalletPublicKeyHash),
+	)
+}
+
+func TestSigningReliabilityStore_Window(t *testing.T) {
+	store := newSigningReliabilityStore(&mockPersistenceHandle{})
+
+	walletPublicKeyHash := [20]byte{0x01}
+
+	for i := 0; i < signingReliabilityWindow; i++ {
+		readyMembersIndexes := []group.MemberIndex{1, 2, 3, 4}
+		// Member 4 misses just the first announcement.
+		if i == 0 {
+			readyMembersIndexes = []group.MemberIndex{1, 2, 3}
+		}
+
+		err := store.recordAnnouncement(
+			walletPublicKeyHash,
+			5,
+			readyMembersIndexes,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertMissedAnnouncements(
+		t,
+		map[group.MemberIndex]uint64{
+			4: 1,
+			5: signingReliabilityWindow,
+		},
+		store.missedAnnouncements(walletPublicKeyHash),
+	)
+
+	// Once the window is full, the history is halved before recording the
+	// next announcement.
+	err := store.recordAnnouncement(
+		walletPublicKeyHash,
+		5,
+		[]group.MemberIndex{1, 2, 3, 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertMissedAnnouncements(
+		t,
+		map[group.MemberIndex]uint64{
+			5: signingReliabilityWindow/2 + 1,
+		},
+		store.missedAnnouncements(walletPublicKeyHash),
+	)
+}
+
+func TestSigningMembersReliability(t *testing.T) {
+	store := newSigningReliabilityStore(&mockPersistenceHandle{})
+
+	walletPublicKeyHash := [20]byte{0x01}
+
+	err := store.recordAnnouncement(
+		walletPublicKeyHash,
+		3,
+		[]group.MemberIndex{1, 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reliability := newSigningMembersReliability(store, walletPublicKeyHash, 3)
+
+	// Multiple signers controlled by the node record the same session.
+	for i := 0; i < 2; i++ {
+		err := reliability.recordAnnouncement(
+			"100-1",
+			[]group.MemberIndex{1, 3},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertMissedAnnouncements(
+		t,
+		map[group.MemberIndex]uint64{2: 1, 3: 1},
+		store.missedAnnouncements(walletPublicKeyHash),
+	)
+
+	// The view uses the history captured at the moment of its creation.
+	for memberIndex, expectedMissed := range map[group.MemberIndex]uint64{
+		1: 0,
+		2: 0,
+		3: 1,
+	} {
+		testutils.AssertUintsEqual(
+			t,
+			fmt.Sprintf("missed announcements of member [%v]", memberIndex),
+			expectedMissed,
+			reliability.missedAnnouncements(memberIndex),
+		)
+	}
+}
+
+func assertMissedAnnouncements(
+	t *testing.T,
+	expected map[group.MemberIndex]uint64,
+	actual map[group.MemberIndex]uint64,
+) {
+	testutils.AssertIntsEqual(
+		t,
+		"members with missed announcements count",
+		len(expected),
+		len(actual),
+	)
+
+	for memberIndex, expectedMissed := range expected {
+		testutils.AssertUintsEqual(
+			t,
+			fmt.Sprintf("missed announcements of member [%v]", memberIndex),
+			expectedMissed,
+			actual[memberIndex],
+		)
+	}
+}
@@ -27,7 +27,12 @@ func TestSigningExecutor_Sign(t *testing.T) {
 	message := big.NewInt(100)
 	startBlock := uint64(0)
 
-	signature, endBlock, err := executor.sign(ctx, message, startBlock)
+	signature, endBlock, err := executor.sign(
+		ctx,
+		ActionHeartbeat,
+		message,
+		startBlock,
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -59,13 +64,23 @@ func TestSigningExecutor_Sign_Busy(t *testing.T) {
 
 	errChan := make(chan error, 1)
 	go func() {
-		_, _, err := executor.sign(ctx, message, startBlock)
+		_, _, err := executor.sign(
+			ctx,
+			ActionHeartbeat,
+			message,
+			startBlock,
+		)
 		errChan <- err
 	}()
 
 	time.Sleep(100 * time.Millisecond)
 
-	_, _, err := executor.sign(ctx, message, startBlock)
+	_, _, err := executor.sign(
+		ctx,
+		ActionHeartbeat,
+		message,
+		startBlock,
+	)
 	testutils.AssertErrorsSame(t, errSigningExecutorBusy, err)
 
 	err = <-errChan
@@ -87,7 +102,12 @@ func TestSigningExecutor_SignBatch(t *testing.T) {
 	}
 	startBlock := uint64(0)
 
-	signatures, err := executor.signBatch(ctx, messages, startBlock)
+	signatures, err := executor.signBatch(
+		ctx,
+		ActionHeartbeat,
+		messages,
+		startBlock,
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,298 @@
+package tbtc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+const (
+	// walletStorageSchemaDirectory is the name of the key store directory
+	// holding the version of the wallet storage schema. It is not a wallet
+	// directory and is skipped when signers are loaded.
+	walletStorageSchemaDirectory = "schema"
+	// walletStorageSchemaVersionName is the name of the file holding the
+	// version of the wallet storage schema.
+	walletStorageSchemaVersionName = "version"
+)
+
+// CurrentWalletStorageSchemaVersion is the version of the wallet storage
+// schema used by this client. Key stores created before the schema was
+// versioned have no version recorded and are considered to be in version 0.
+//
+// Version history:
+//   - 1: signers are kept in directories named after their wallet storage
+//     keys and the schema version is recorded in the key store.
+const CurrentWalletStorageSchemaVersion = 1
+
+// walletStorageMigration upgrades the wallet storage to the given schema
+// version from the directly preceding one.
+type walletStorageMigration struct {
+	toVersion   int
+	description string
+	migrate     func(keyStorePersistence persistence.ProtectedHandle) error
+}
+
+// walletStorageMigrations are all wallet storage migrations ordered by
+// the schema version they upgrade to. A new migration must be added here
+// along with every change of the wallet storage format.
+var walletStorageMigrations = []*walletStorageMigration{
+	{
+		toVersion:   1,
+		description: "move signers to canonical wallet directories",
+		migrate:     migrateToCanonicalWalletDirectories,
+	},
+}
+
+// MigrateWalletStorage upgrades the wallet storage kept in the given key
+// store to the current schema version by running all migrations from
+// the recorded version on. The recorded version is updated after each
+// migration so an interrupted upgrade can be resumed by running the
+// function again. Key stores recorded with a version newer than the current
+// one are refused as they were written by a newer client. The version the
+// storage was upgraded from is returned. The client must not be running
+// during the migration.
+func MigrateWalletStorage(
+	keyStorePersistence persistence.ProtectedHandle,
+) (int, error) {
+	version, err := readWalletStorageSchemaVersion(keyStorePersistence)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"cannot read wallet storage schema version: [%w]",
+			err,
+		)
+	}
+
+	if version > CurrentWalletStorageSchemaVersion {
+		return version, fmt.Errorf(
+			"wallet storage schema version [%v] is newer than the "+
+				"supported version [%v]; upgrade the client",
+			version,
+			CurrentWalletStorageSchemaVersion,
+		)
+	}
+
+	fromVersion := version
+
+	for _, migration := range walletStorageMigrations {
+		if migration.toVersion <= version {
+			continue
+		}
+
+		logger.Infof(
+			"migrating wallet storage to schema version [%v]: %s",
+			migration.toVersion,
+			migration.description,
+		)
+
+		if err := migration.migrate(keyStorePersistence); err != nil {
+			return fromVersion, fmt.Errorf(
+				"cannot migrate wallet storage to schema version [%v]: [%w]",
+				migration.toVersion,
+				err,
+			)
+		}
+
+		if err := writeWalletStorageSchemaVersion(
+			keyStorePersistence,
+			migration.toVersion,
+		); err != nil {
+			return fromVersion, fmt.Errorf(
+				"cannot record wallet storage schema version [%v]: [%w]",
+				migration.toVersion,
+				err,
+			)
+		}
+
+		version = migration.toVersion
+	}
+
+	return fromVersion, nil
+}
+
+// readWalletStorageSchemaVersion reads the wallet storage schema version
+// recorded in the given key store. Version 0 is returned if no version is
+// recorded.
+func readWalletStorageSchemaVersion(
+	keyStorePersistence persistence.ProtectedHandle,
+) (int, error) {
+	var version int
+	var versionErr error
+	var readErrs []error
+
+	persistenceutil.ReadAll(
+		keyStorePersistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != walletStorageSchemaDirectory ||
+				descriptor.Name() != walletStorageSchemaVersionName {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				versionErr = err
+				return
+			}
+
+			version, err = strconv.Atoi(string(content))
+			if err != nil {
+				versionErr = fmt.Errorf("invalid version: [%w]", err)
+			}
+		},
+		func(err error) {
+			readErrs = append(readErrs, err)
+		},
+	)
+
+	if versionErr != nil {
+		return 0, versionErr
+	}
+
+	// Errors of other files do not matter as long as the version could
+	// be read. They are handled when signers are loaded.
+	if len(readErrs) > 0 {
+		logger.Warnf(
+			"errors occurred while reading the key store: [%v]",
+			readErrs,
+		)
+	}
+
+	return version, nil
+}
+
+func writeWalletStorageSchemaVersion(
+	keyStorePersistence persistence.ProtectedHandle,
+	version int,
+) error {
+	return keyStorePersistence.Save(
+		[]byte(strconv.Itoa(version)),
+		walletStorageSchemaDirectory,
+		walletStorageSchemaVersionName,
+	)
+}
+
+// migrateToCanonicalWalletDirectories moves signers kept in directories
+// other than the ones named after their wallet storage keys to the latter.
+// Signers are saved in the canonical directories first and only then the
+// original directories are archived, so no signer is lost if the migration
+// is interrupted. Directories holding files that cannot be read as signers
+// are left untouched; such files are reported once signers are loaded.
+func migrateToCanonicalWalletDirectories(
+	keyStorePersistence persistence.ProtectedHandle,
+) error {
+	signersByDirectory, unreadableDirectories, err := readSignersByDirectory(
+		keyStorePersistence,
+	)
+	if err != nil {
+		return err
+	}
+
+	walletStorage := newWalletStorage(keyStorePersistence)
+
+	for directory, signers := range signersByDirectory {
+		if unreadableDirectories[directory] {
+			logger.Warnf(
+				"directory [%v] holds unreadable files; leaving it untouched",
+				directory,
+			)
+			continue
+		}
+
+		canonicalCount := 0
+		for _, signer := range signers {
+			if getWalletStorageKey(signer.wallet.publicKey) == directory {
+				canonicalCount++
+			}
+		}
+
+		if canonicalCount == len(signers) {
+			continue
+		}
+
+		// The directory cannot be archived without archiving the signers
+		// it is canonical for.
+		if canonicalCount > 0 {
+			logger.Warnf(
+				"directory [%v] mixes signers of different wallets; "+
+					"leaving it untouched",
+				directory,
+			)
+			continue
+		}
+
+		for _, signer := range signers {
+			if err := walletStorage.saveSigner(signer); err != nil {
+				return fmt.Errorf(
+					"cannot save signer from directory [%v]: [%w]",
+					directory,
+					err,
+				)
+			}
+		}
+
+		if err := keyStorePersistence.Archive(directory); err != nil {
+			return fmt.Errorf(
+				"cannot archive directory [%v]: [%w]",
+				directory,
+				err,
+			)
+		}
+
+		logger.Infof(
+			"[%v] signers moved from directory [%v] to their wallet "+
+				"directories",
+			len(signers),
+			directory,
+		)
+	}
+
+	return nil
+}
+
+// readSignersByDirectory reads all signers kept in the given key store and
+// groups them by their directories. Directories holding files that cannot
+// be read as signers are returned as unreadable.
+func readSignersByDirectory(
+	keyStorePersistence persistence.ProtectedHandle,
+) (map[string][]*signer, map[string]bool, error) {
+	signersByDirectory := make(map[string][]*signer)
+	unreadableDirectories := make(map[string]bool)
+	var readErrs []error
+
+	persistenceutil.ReadAll(
+		keyStorePersistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() == walletStorageSchemaDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				unreadableDirectories[descriptor.Directory()] = true
+				return
+			}
+
+			signer := &signer{}
+			if err := signer.Unmarshal(content); err != nil {
+				unreadableDirectories[descriptor.Directory()] = true
+				return
+			}
+
+			signersByDirectory[descriptor.Directory()] = append(
+				signersByDirectory[descriptor.Directory()],
+				signer,
+			)
+		},
+		func(err error) {
+			readErrs = append(readErrs, err)
+		},
+	)
+
+	if len(readErrs) > 0 {
+		return nil, nil, fmt.Errorf("cannot read key store: [%v]", readErrs)
+	}
+
+	return signersByDirectory, unreadableDirectories, nil
+}
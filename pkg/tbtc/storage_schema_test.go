@@ -0,0 +1,132 @@
+package tbtc
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestMigrateWalletStorage(t *testing.T) {
+	signer := createMockSigner(t)
+	signerBytes, err := signer.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletStorageKey := getWalletStorageKey(signer.wallet.publicKey)
+
+	// Unversioned key store holding the signer in a non-canonical directory.
+	persistenceHandle := &mockPersistenceHandle{
+		saved: []persistence.DataDescriptor{
+			&mockDescriptor{
+				name:      "membership_1",
+				directory: "wallet_1",
+				content:   signerBytes,
+			},
+		},
+	}
+
+	fromVersion, err := MigrateWalletStorage(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "from version", 0, fromVersion)
+
+	version, err := readWalletStorageSchemaVersion(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"recorded version",
+		CurrentWalletStorageSchemaVersion,
+		version,
+	)
+
+	directories := make(map[string]int)
+	for _, descriptor := range persistenceHandle.saved {
+		directories[descriptor.Directory()]++
+	}
+	testutils.AssertIntsEqual(t, "directories count", 2, len(directories))
+	testutils.AssertIntsEqual(
+		t,
+		"signers in canonical directory",
+		1,
+		directories[walletStorageKey],
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"schema version files",
+		1,
+		directories[walletStorageSchemaDirectory],
+	)
+
+	// Migrating the storage again is a no-op.
+	fromVersion, err = MigrateWalletStorage(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"from version",
+		CurrentWalletStorageSchemaVersion,
+		fromVersion,
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"persisted files count",
+		2,
+		len(persistenceHandle.saved),
+	)
+
+	// The schema version is not taken for a signer.
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"loaded wallets count",
+		1,
+		len(walletRegistry.walletCache),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"persisted files count after load",
+		2,
+		len(persistenceHandle.saved),
+	)
+}
+
+func TestMigrateWalletStorage_NewerVersion(t *testing.T) {
+	newerVersion := CurrentWalletStorageSchemaVersion + 1
+
+	persistenceHandle := &mockPersistenceHandle{
+		saved: []persistence.DataDescriptor{
+			&mockDescriptor{
+				name:      walletStorageSchemaVersionName,
+				directory: walletStorageSchemaDirectory,
+				content:   []byte(strconv.Itoa(newerVersion)),
+			},
+		},
+	}
+
+	_, err := MigrateWalletStorage(persistenceHandle)
+
+	expectedErr := fmt.Sprintf(
+		"wallet storage schema version [%v] is newer than the "+
+			"supported version [%v]; upgrade the client",
+		newerVersion,
+		CurrentWalletStorageSchemaVersion,
+	)
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			expectedErr,
+			err,
+		)
+	}
+}
@@ -45,10 +45,11 @@ func (gp *GroupParameters) DishonestThreshold() int {
 }
 
 const (
-	DefaultPreParamsPoolSize              = 1000
-	DefaultPreParamsGenerationTimeout     = 2 * time.Minute
-	DefaultPreParamsGenerationDelay       = 10 * time.Second
-	DefaultPreParamsGenerationConcurrency = 1
+	DefaultPreParamsPoolSize                = 1000
+	DefaultPreParamsGenerationTimeout       = 2 * time.Minute
+	DefaultPreParamsGenerationDelay         = 10 * time.Second
+	DefaultPreParamsGenerationConcurrency   = 1
+	DefaultPreParamsGenerationLoadThreshold = generator.DefaultLoadThreshold
 )
 
 var DefaultKeyGenerationConcurrency = runtime.GOMAXPROCS(0)
@@ -61,12 +62,62 @@ type Config struct {
 	PreParamsGenerationTimeout time.Duration
 	// The delay between generating new pre-params for tECDSA.
 	PreParamsGenerationDelay time.Duration
-	// Concurrency level for pre-parameters generation for tECDSA.
+	// Maximum concurrency level for pre-parameters generation for tECDSA.
+	// The actual level adapts to the CPU capacity left idle by other
+	// processes of the machine.
 	PreParamsGenerationConcurrency int
+	// CPU utilization of other processes of the machine, as a fraction of its
+	// total CPU capacity, at and above which the pre-parameters generation
+	// for tECDSA is stopped.
+	PreParamsGenerationLoadThreshold float64
 	// Concurrency level for key-generation for tECDSA.
 	KeyGenerationConcurrency int
 }
 
+// InitializeOption allows to customize the TBTC initialization.
+type InitializeOption func(node *node)
+
+// WithSignerRegisteredHandler registers a handler that is invoked every time
+// the node registers a new wallet signer upon successful DKG. It allows
+// external systems, e.g. monitoring or backup tools, to react immediately.
+// The handler is invoked in a separate goroutine.
+func WithSignerRegisteredHandler(
+	handler func(event *SignerRegisteredEvent),
+) InitializeOption {
+	return func(node *node) {
+		_ = node.walletRegistry.onSignerRegistered(handler)
+	}
+}
+
+// PreParamsPool allows inspecting and adjusting the ECDSA DKG pre-parameters
+// pool at runtime.
+type PreParamsPool interface {
+	// ParametersCount returns the number of pre-parameters in the pool.
+	ParametersCount() int
+	// TargetSize returns the number of pre-parameters the pool is filled
+	// up to.
+	TargetSize() int
+	// SetTargetSize changes the number of pre-parameters the pool is filled
+	// up to. The pre-parameters generation grows or shrinks accordingly.
+	SetTargetSize(targetSize int) error
+	// Metrics returns the current metrics of the pool.
+	Metrics() *generator.PoolMetrics
+	// Flush saves all generated pre-parameters buffered by the pool.
+	Flush() error
+}
+
+// WithPreParamsPoolHandler registers a handler that is invoked once with the
+// ECDSA DKG pre-parameters pool of the node. It allows external systems,
+// e.g. the admin API, to adjust the pool target size at runtime or flush
+// the pool on shutdown.
+func WithPreParamsPoolHandler(
+	handler func(pool PreParamsPool),
+) InitializeOption {
+	return func(node *node) {
+		handler(node.dkgExecutor.preParamsPool())
+	}
+}
+
 // Initialize kicks off the TBTC by initializing internal state, ensuring
 // preconditions like staking are met, and then kicking off the internal TBTC
 // implementation. Returns an error if this failed.
@@ -78,9 +129,11 @@ func Initialize(
 	keyStorePersistence persistence.ProtectedHandle,
 	workPersistence persistence.BasicHandle,
 	scheduler *generator.Scheduler,
+	sortitionPoolsMonitor *sortition.PoolsMonitor,
 	proposalGenerator CoordinationProposalGenerator,
 	config Config,
 	clientInfo *clientinfo.Registry,
+	options ...InitializeOption,
 ) error {
 	groupParameters := &GroupParameters{
 		GroupSize:       100,
@@ -88,6 +141,18 @@ func Initialize(
 		HonestThreshold: 51,
 	}
 
+	fromVersion, err := MigrateWalletStorage(keyStorePersistence)
+	if err != nil {
+		return fmt.Errorf("cannot migrate wallet storage: [%w]", err)
+	}
+	if fromVersion != CurrentWalletStorageSchemaVersion {
+		logger.Infof(
+			"wallet storage migrated from schema version [%v] to [%v]",
+			fromVersion,
+			CurrentWalletStorageSchemaVersion,
+		)
+	}
+
 	node, err := newNode(
 		groupParameters,
 		chain,
@@ -103,6 +168,10 @@ func Initialize(
 		return fmt.Errorf("cannot set up TBTC node: [%v]", err)
 	}
 
+	for _, option := range options {
+		option(node)
+	}
+
 	err = node.runCoordinationLayer(ctx)
 	if err != nil {
 		return fmt.Errorf("cannot run coordination layer: [%w]", err)
@@ -118,20 +187,69 @@ func Initialize(
 				"pre_params_count": func() float64 {
 					return float64(node.dkgExecutor.preParamsCount())
 				},
+				"pre_params_target_count": func() float64 {
+					return float64(node.dkgExecutor.preParamsTargetCount())
+				},
+				"pre_params_generated_count": func() float64 {
+					stats := node.dkgExecutor.preParamsPoolStats()
+					return float64(stats.GeneratedCount)
+				},
+				"pre_params_generation_failures_count": func() float64 {
+					stats := node.dkgExecutor.preParamsPoolStats()
+					return float64(stats.FailuresCount)
+				},
+				"pre_params_generation_timeouts_count": func() float64 {
+					stats := node.dkgExecutor.preParamsPoolStats()
+					return float64(stats.TimeoutsCount)
+				},
+				"pre_params_generation_rate_per_hour": func() float64 {
+					stats := node.dkgExecutor.preParamsPoolStats()
+					return stats.GenerationRate()
+				},
+				"pre_params_refill_estimate_seconds": func() float64 {
+					stats := node.dkgExecutor.preParamsPoolStats()
+					return stats.RefillEstimate().Seconds()
+				},
+				"dkg_missed_count": func() float64 {
+					return float64(node.dkgExecutor.missedDkgs())
+				},
 			},
 		)
+
+		clientInfo.ObserveApplicationSource(
+			"tbtc",
+			node.signingMetrics.sources(),
+		)
+
+		clientInfo.ObserveParameterPool(
+			"tbtc_pre_params",
+			node.dkgExecutor.preParamsPool(),
+		)
+
+		clientInfo.RegisterApplicationSource(
+			"tbtc_signing",
+			node.signingMetrics.info,
+		)
+
+		clientInfo.RegisterApplicationSource(
+			"tbtc_wallets",
+			node.walletsInfo,
+		)
+
+		clientInfo.RegisterApplicationSource(
+			"tbtc_pre_params_storage",
+			node.dkgExecutor.preParamsStorageInfo,
+		)
 	}
 
-	err = sortition.MonitorPool(
-		ctx,
+	sortitionMonitor, err := sortitionPoolsMonitor.Register(
+		"tbtc",
 		logger,
 		chain,
-		sortition.DefaultStatusCheckTick,
 		sortition.NewConjunctionPolicy(
 			sortition.NewBetaOperatorPolicy(chain, logger),
 			&enoughPreParamsInPoolPolicy{
-				node:   node,
-				config: config,
+				node: node,
 			},
 		),
 	)
@@ -142,6 +260,30 @@ func Initialize(
 		)
 	}
 
+	err = sortitionPoolsMonitor.TrackProtocols("tbtc", node.protocolLatch)
+	if err != nil {
+		return fmt.Errorf("could not track tbtc protocols: [%v]", err)
+	}
+
+	if clientInfo != nil {
+		// only if client info endpoint is configured
+		clientInfo.ObserveApplicationSource(
+			"tbtc",
+			map[string]clientinfo.Source{
+				"sortition_pool_monitor_healthy": func() float64 {
+					if sortitionMonitor.Health().Healthy {
+						return 1
+					}
+					return 0
+				},
+				"sortition_pool_monitor_consecutive_failures": func() float64 {
+					health := sortitionMonitor.Health()
+					return float64(health.ConsecutiveFailures)
+				},
+			},
+		)
+	}
+
 	_ = chain.OnDKGStarted(func(event *DKGStartedEvent) {
 		go func() {
 			if ok := deduplicator.notifyDKGStarted(
@@ -214,6 +356,15 @@ func Initialize(
 				// the announcements and the state machine. Here we ensure
 				// a proper start point by delaying the execution by the
 				// confirmation period length.
+				if sortitionPoolsMonitor.IsExitRequested() {
+					logger.Warnf(
+						"operator exit requested; not joining DKG "+
+							"with seed [0x%x]",
+						lastEvent.Seed,
+					)
+					return
+				}
+
 				node.joinDKGIfEligible(
 					lastEvent.Seed,
 					lastEvent.BlockNumber,
@@ -264,18 +415,23 @@ func Initialize(
 		}()
 	})
 
+	go node.resumeDKGApprovals()
+
+	go node.recoverMissedDKG(deduplicator)
+
+	go node.monitorWalletLifecycle(ctx, walletLifecycleCheckTick)
+
 	return nil
 }
 
 // enoughPreParamsInPoolPolicy is a policy that enforces the sufficient size
 // of the DKG pre-parameters pool before joining the sortition pool.
 type enoughPreParamsInPoolPolicy struct {
-	node   *node
-	config Config
+	node *node
 }
 
 func (eppip *enoughPreParamsInPoolPolicy) ShouldJoin() bool {
 	paramsInPool := eppip.node.dkgExecutor.preParamsCount()
-	poolSize := eppip.config.PreParamsPoolSize
+	poolSize := eppip.node.dkgExecutor.preParamsTargetCount()
 	return paramsInPool >= poolSize
 }
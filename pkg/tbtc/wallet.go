@@ -17,6 +17,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"github.com/keep-network/keep-core/pkg/tecdsa/keyshare"
 	"go.uber.org/zap"
 )
 
@@ -83,6 +84,14 @@ type walletAction interface {
 	actionType() WalletActionType
 }
 
+// proposalWalletAction is a walletAction executing a coordination proposal.
+type proposalWalletAction interface {
+	walletAction
+
+	// executedProposal returns the proposal executed by the walletAction.
+	executedProposal() CoordinationProposal
+}
+
 // WalletState represents the state of a wallet.
 type WalletState uint8
 
@@ -126,11 +135,19 @@ type walletDispatcher struct {
 	// given wallet. The mapping key is the uncompressed public key
 	// (with 04 prefix) of the wallet.
 	actions map[string]WalletActionType
+
+	// lastActions keeps track of the last action executed by each wallet.
+	lastActions *walletLastActionStore
+
+	// actionSucceededHandler is invoked after each successfully executed
+	// action. Can be nil.
+	actionSucceededHandler func(action walletAction)
 }
 
-func newWalletDispatcher() *walletDispatcher {
+func newWalletDispatcher(lastActions *walletLastActionStore) *walletDispatcher {
 	return &walletDispatcher{
-		actions: make(map[string]WalletActionType),
+		actions:     make(map[string]WalletActionType),
+		lastActions: lastActions,
 	}
 }
 
@@ -167,7 +184,28 @@ func (wd *walletDispatcher) dispatch(action walletAction) error {
 
 		walletActionLogger.Infof("starting action execution")
 
+		lastAction := &WalletLastAction{
+			Type:      action.actionType().String(),
+			StartedAt: time.Now(),
+		}
+
 		err := action.execute()
+
+		lastAction.CompletedAt = time.Now()
+		if err != nil {
+			lastAction.Error = err.Error()
+		}
+
+		if recordErr := wd.lastActions.record(
+			bitcoin.PublicKeyHash(action.wallet().publicKey),
+			lastAction,
+		); recordErr != nil {
+			walletActionLogger.Errorf(
+				"cannot record action as the last one: [%v]",
+				recordErr,
+			)
+		}
+
 		if err != nil {
 			walletActionLogger.Errorf(
 				"action execution terminated with error: [%v]",
@@ -177,16 +215,40 @@ func (wd *walletDispatcher) dispatch(action walletAction) error {
 		}
 
 		walletActionLogger.Infof("action execution terminated with success")
+
+		if wd.actionSucceededHandler != nil {
+			wd.actionSucceededHandler(action)
+		}
 	}()
 
 	return nil
 }
 
+// currentAction returns the type of the action currently executed by the
+// given wallet. The second return value is false if the wallet is not
+// executing any action.
+func (wd *walletDispatcher) currentAction(
+	walletPublicKey *ecdsa.PublicKey,
+) (WalletActionType, bool) {
+	wd.actionsMutex.Lock()
+	defer wd.actionsMutex.Unlock()
+
+	walletPublicKeyBytes, err := marshalPublicKey(walletPublicKey)
+	if err != nil {
+		return 0, false
+	}
+
+	actionType, ok := wd.actions[hex.EncodeToString(walletPublicKeyBytes)]
+
+	return actionType, ok
+}
+
 // walletSigningExecutor is an interface meant to decouple the specific
 // implementation of the signing executor from the wallet transaction executor.
 type walletSigningExecutor interface {
 	signBatch(
 		ctx context.Context,
+		actionType WalletActionType,
 		messages []*big.Int,
 		startBlock uint64,
 	) ([]*tecdsa.Signature, error)
@@ -222,6 +284,7 @@ func newWalletTransactionExecutor(
 // Bitcoin network.
 func (wte *walletTransactionExecutor) signTransaction(
 	signTxLogger log.StandardLogger,
+	actionType WalletActionType,
 	unsignedTx *bitcoin.TransactionBuilder,
 	signingStartBlock uint64,
 	signingTimeoutBlock uint64,
@@ -247,6 +310,7 @@ func (wte *walletTransactionExecutor) signTransaction(
 
 	signatures, err := wte.signingExecutor.signBatch(
 		signingCtx,
+		actionType,
 		sigHashes,
 		signingStartBlock,
 	)
@@ -283,7 +347,10 @@ func (wte *walletTransactionExecutor) signTransaction(
 
 // broadcastTransaction broadcasts a signed Bitcoin transaction until
 // the transaction lands in the Bitcoin mempool or the provided timeout
-// is hit, whichever comes first.
+// is hit, whichever comes first. After each broadcast attempt, the check
+// whether the transaction is known on the Bitcoin chain is done once the
+// chain notifies about the transaction or the provided check delay elapses,
+// whichever comes first.
 func (wte *walletTransactionExecutor) broadcastTransaction(
 	broadcastTxLogger log.StandardLogger,
 	tx *bitcoin.Transaction,
@@ -298,6 +365,22 @@ func (wte *walletTransactionExecutor) broadcastTransaction(
 	)
 	defer cancelBroadcastCtx()
 
+	// The transaction is known once it is in the mempool, that is, once it
+	// has at least zero confirmations.
+	knownChan, err := bitcoin.WatchConfirmations(
+		broadcastCtx,
+		wte.btcChain,
+		tx,
+		0,
+	)
+	if err != nil {
+		// Not a big deal, the check is done after the check delay anyway.
+		broadcastTxLogger.Warnf(
+			"cannot watch the transaction on Bitcoin chain: [%v]",
+			err,
+		)
+	}
+
 	broadcastAttempt := 0
 
 	for {
@@ -312,7 +395,9 @@ func (wte *walletTransactionExecutor) broadcastTransaction(
 				broadcastAttempt,
 			)
 
-			err := wte.btcChain.BroadcastTransaction(tx)
+			// Broadcast to all backends at once to propagate the
+			// transaction as fast as possible.
+			results, err := bitcoin.BroadcastAll(wte.btcChain, tx)
 			if err != nil {
 				broadcastTxLogger.Warnf(
 					"broadcasting failed: [%v]; transaction could be "+
@@ -320,16 +405,38 @@ func (wte *walletTransactionExecutor) broadcastTransaction(
 					err,
 				)
 			} else {
-				broadcastTxLogger.Infof("broadcasting completed")
+				acceptedBy := make([]string, 0, len(results))
+				for _, result := range results {
+					if result.Accepted() {
+						acceptedBy = append(acceptedBy, result.Backend)
+					}
+				}
+
+				broadcastTxLogger.Infof(
+					"broadcasting completed; transaction accepted by "+
+						"[%v] of [%v] backends: %v",
+					len(acceptedBy),
+					len(results),
+					acceptedBy,
+				)
 			}
 
 			broadcastTxLogger.Infof(
-				"waiting [%v] before checking whether the "+
+				"waiting up to [%v] before checking whether the "+
 					"transaction is known on Bitcoin chain",
 				checkDelay,
 			)
 
 			select {
+			case _, ok := <-knownChan:
+				// The channel is closed without receiving anything only
+				// once the broadcast context is done.
+				if !ok {
+					return fmt.Errorf("broadcast timeout exceeded")
+				}
+
+				broadcastTxLogger.Infof("transaction is known on Bitcoin chain")
+				return nil
 			case <-time.After(checkDelay):
 			case <-broadcastCtx.Done():
 				return fmt.Errorf("broadcast timeout exceeded")
@@ -341,6 +448,16 @@ func (wte *walletTransactionExecutor) broadcastTransaction(
 
 			_, err = wte.btcChain.GetTransactionConfirmations(txHash)
 			if err != nil {
+				// The chain may not know about unconfirmed transactions.
+				// Ask the mempool directly if the chain is able to.
+				if inMempool := wte.isTransactionInMempool(
+					broadcastTxLogger,
+					txHash,
+				); inMempool {
+					broadcastTxLogger.Infof("transaction is in the mempool")
+					return nil
+				}
+
 				broadcastTxLogger.Warnf(
 					"cannot say whether the transaction is known "+
 						"on Bitcoin chain; check returned an error: [%v]",
@@ -355,6 +472,29 @@ func (wte *walletTransactionExecutor) broadcastTransaction(
 	}
 }
 
+// isTransactionInMempool returns true if the Bitcoin chain implements
+// bitcoin.MempoolChecker and reports the given transaction is in the mempool.
+func (wte *walletTransactionExecutor) isTransactionInMempool(
+	broadcastTxLogger log.StandardLogger,
+	txHash bitcoin.Hash,
+) bool {
+	mempoolChecker, ok := wte.btcChain.(bitcoin.MempoolChecker)
+	if !ok {
+		return false
+	}
+
+	inMempool, err := mempoolChecker.IsTransactionInMempool(txHash)
+	if err != nil {
+		broadcastTxLogger.Warnf(
+			"cannot check whether the transaction is in the mempool: [%v]",
+			err,
+		)
+		return false
+	}
+
+	return inMempool
+}
+
 // wallet represents a tBTC wallet. A wallet is one of the basic building
 // blocks of the system that takes BTC under custody during the deposit
 // process and gives that BTC back during redemptions.
@@ -419,6 +559,10 @@ func (w *wallet) String() string {
 	return fmt.Sprintf("public key [0x%x]", publicKey)
 }
 
+// mainUtxoHistoryPageSize is the number of wallet transactions fetched at
+// once while looking for the wallet main UTXO.
+const mainUtxoHistoryPageSize = 10
+
 // DetermineWalletMainUtxo determines the plain-text wallet main UTXO
 // currently registered in the Bridge on-chain contract. The returned
 // main UTXO can be nil if the wallet does not have a main UTXO registered
@@ -447,15 +591,8 @@ func DetermineWalletMainUtxo(
 	// the actual latest BTC transaction and the registered main UTXO in
 	// the Bridge may be even wider. To cover the worst possible cases, we
 	// must rely on the full transaction history. Due to performance reasons,
-	// we are first taking just the transactions hashes (fast call) and then
-	// fetch full transaction data (time-consuming calls) starting from
-	// the most recent transactions as there is a high chance the main UTXO
-	// comes from there.
-	txHashes, err := btcChain.GetTxHashesForPublicKeyHash(walletPublicKeyHash)
-	if err != nil {
-		return nil, fmt.Errorf("cannot get transactions history for wallet: [%v]", err)
-	}
-
+	// we are walking the history page by page, starting from the most recent
+	// transactions as there is a high chance the main UTXO comes from there.
 	walletP2PKH, err := bitcoin.PayToPublicKeyHash(walletPublicKeyHash)
 	if err != nil {
 		return nil, fmt.Errorf("cannot construct P2PKH for wallet: [%v]", err)
@@ -465,45 +602,59 @@ func DetermineWalletMainUtxo(
 		return nil, fmt.Errorf("cannot construct P2WPKH for wallet: [%v]", err)
 	}
 
-	// Start iterating from the latest transaction as the chance it matches
-	// the wallet main UTXO is the highest.
-	for i := len(txHashes) - 1; i >= 0; i-- {
-		txHash := txHashes[i]
+	query := &bitcoin.TransactionHistoryQuery{
+		PageSize: mainUtxoHistoryPageSize,
+	}
 
-		transaction, err := btcChain.GetTransaction(txHash)
+	for {
+		page, err := btcChain.GetTransactionsPageForPublicKeyHash(
+			walletPublicKeyHash,
+			query,
+		)
 		if err != nil {
 			return nil, fmt.Errorf(
-				"cannot get transaction with hash [%s]: [%v]",
-				txHash.String(),
+				"cannot get transactions history for wallet: [%v]",
 				err,
 			)
 		}
 
-		// Iterate over transaction's outputs and find the one that targets
-		// the wallet public key hash.
-		for outputIndex, output := range transaction.Outputs {
-			script := output.PublicKeyScript
-			matchesWallet := bytes.Equal(script, walletP2PKH) ||
-				bytes.Equal(script, walletP2WPKH)
-
-			// Once the right output is found, check whether their hash
-			// matches the main UTXO hash stored on-chain. If so, this
-			// UTXO is the one we are looking for.
-			if matchesWallet {
-				utxo := &bitcoin.UnspentTransactionOutput{
-					Outpoint: &bitcoin.TransactionOutpoint{
-						TransactionHash: transaction.Hash(),
-						OutputIndex:     uint32(outputIndex),
-					},
-					Value: output.Value,
-				}
-
-				if bridgeChain.ComputeMainUtxoHash(utxo) ==
-					walletChainData.MainUtxoHash {
-					return utxo, nil
+		// Start iterating from the latest transaction as the chance it
+		// matches the wallet main UTXO is the highest.
+		for i := len(page.Transactions) - 1; i >= 0; i-- {
+			transaction := page.Transactions[i]
+
+			// Iterate over transaction's outputs and find the one that targets
+			// the wallet public key hash.
+			for outputIndex, output := range transaction.Outputs {
+				script := output.PublicKeyScript
+				matchesWallet := bytes.Equal(script, walletP2PKH) ||
+					bytes.Equal(script, walletP2WPKH)
+
+				// Once the right output is found, check whether their hash
+				// matches the main UTXO hash stored on-chain. If so, this
+				// UTXO is the one we are looking for.
+				if matchesWallet {
+					utxo := &bitcoin.UnspentTransactionOutput{
+						Outpoint: &bitcoin.TransactionOutpoint{
+							TransactionHash: transaction.Hash(),
+							OutputIndex:     uint32(outputIndex),
+						},
+						Value: output.Value,
+					}
+
+					if bridgeChain.ComputeMainUtxoHash(utxo) ==
+						walletChainData.MainUtxoHash {
+						return utxo, nil
+					}
 				}
 			}
 		}
+
+		if page.NextCursor == nil {
+			break
+		}
+
+		query.Cursor = page.NextCursor
 	}
 
 	return nil, fmt.Errorf("main UTXO not found")
@@ -680,3 +831,10 @@ func (s *signer) String() string {
 		&s.wallet,
 	)
 }
+
+// keyShareSigner returns the signer performing operations requiring the
+// private key share. Shares persisted in the node's key store are used
+// locally.
+func (s *signer) keyShareSigner() keyshare.Signer {
+	return keyshare.NewLocalSigner(s.privateKeyShare)
+}
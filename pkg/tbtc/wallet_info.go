@@ -0,0 +1,447 @@
+package tbtc
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+const (
+	// walletLastActionDirectory is the name of the directory the wallet last
+	// action store keeps its entries in.
+	walletLastActionDirectory = "wallet_last_action"
+	// walletRecentActionsLimit is the maximum number of recent actions kept
+	// for each wallet by the wallet last action store.
+	walletRecentActionsLimit = 10
+	// walletStateCachePeriod is the time the on-chain state of a wallet is
+	// cached for by the wallets diagnostics source. Wallet states change
+	// rarely so they are not fetched every time the diagnostics are
+	// requested.
+	walletStateCachePeriod = 10 * time.Minute
+)
+
+// WalletLastAction describes the last action executed by a wallet.
+type WalletLastAction struct {
+	// Type is the type of the action.
+	Type string
+	// StartedAt is the time the action execution started.
+	StartedAt time.Time
+	// CompletedAt is the time the action execution completed.
+	CompletedAt time.Time
+	// Error is the error the action execution terminated with. It is empty
+	// if the action succeeded.
+	Error string
+}
+
+// walletLastActionStore keeps track of the last action executed by each
+// wallet. The actions are persisted so they can be inspected while the node
+// is not running. The store also keeps a short history of recent actions of
+// each wallet. The history is held in memory only.
+type walletLastActionStore struct {
+	mutex sync.Mutex
+
+	persistence persistence.BasicHandle
+	actions     map[string]*WalletLastAction
+	recent      map[string][]*WalletLastAction
+}
+
+// newWalletLastActionStore creates a new store backed by the given persistence
+// handle and loads all actions persisted so far. Actions that cannot be read
+// are logged and skipped.
+func newWalletLastActionStore(
+	persistence persistence.BasicHandle,
+) *walletLastActionStore {
+	store := &walletLastActionStore{
+		persistence: persistence,
+		actions:     make(map[string]*WalletLastAction),
+		recent:      make(map[string][]*WalletLastAction),
+	}
+
+	store.load()
+
+	return store
+}
+
+func (wlas *walletLastActionStore) load() {
+	persistenceutil.ReadAll(
+		wlas.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != walletLastActionDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read wallet last action from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			action := &WalletLastAction{}
+			if err := json.Unmarshal(content, action); err != nil {
+				logger.Errorf(
+					"could not parse wallet last action from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			wlas.actions[descriptor.Name()] = action
+			wlas.recent[descriptor.Name()] = []*WalletLastAction{action}
+		},
+		func(err error) {
+			logger.Errorf(
+				"could not load wallet last action from disk: [%v]",
+				err,
+			)
+		},
+	)
+}
+
+// record records the given action as the last action of the given wallet.
+func (wlas *walletLastActionStore) record(
+	walletPublicKeyHash [20]byte,
+	action *WalletLastAction,
+) error {
+	wlas.mutex.Lock()
+	defer wlas.mutex.Unlock()
+
+	content, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("cannot marshal wallet last action: [%v]", err)
+	}
+
+	name := hex.EncodeToString(walletPublicKeyHash[:])
+
+	if err := wlas.persistence.Save(
+		content,
+		walletLastActionDirectory,
+		name,
+	); err != nil {
+		return fmt.Errorf("cannot save wallet last action: [%v]", err)
+	}
+
+	wlas.actions[name] = action
+
+	recent := append(wlas.recent[name], action)
+	if len(recent) > walletRecentActionsLimit {
+		recent = recent[len(recent)-walletRecentActionsLimit:]
+	}
+	wlas.recent[name] = recent
+
+	return nil
+}
+
+// get returns the last action of the given wallet. The second return value
+// is false if no action was recorded for the wallet.
+func (wlas *walletLastActionStore) get(
+	walletPublicKeyHash [20]byte,
+) (*WalletLastAction, bool) {
+	wlas.mutex.Lock()
+	defer wlas.mutex.Unlock()
+
+	action, ok := wlas.actions[hex.EncodeToString(walletPublicKeyHash[:])]
+
+	return action, ok
+}
+
+// getRecent returns up to walletRecentActionsLimit recent actions of the given
+// wallet, ordered from the oldest to the newest one. Actions executed before
+// the node was started are not included, except for the last one.
+func (wlas *walletLastActionStore) getRecent(
+	walletPublicKeyHash [20]byte,
+) []*WalletLastAction {
+	wlas.mutex.Lock()
+	defer wlas.mutex.Unlock()
+
+	recent := wlas.recent[hex.EncodeToString(walletPublicKeyHash[:])]
+
+	result := make([]*WalletLastAction, len(recent))
+	copy(result, recent)
+
+	return result
+}
+
+// WalletInfo describes a wallet the node holds signers for.
+type WalletInfo struct {
+	// WalletPublicKeyHash is the 20-byte wallet public key hash.
+	WalletPublicKeyHash [20]byte
+	// MembersIndexes are indexes of signing group members controlled by
+	// the node.
+	MembersIndexes []group.MemberIndex
+	// State is the on-chain state of the wallet. It is StateUnknown if the
+	// state could not be determined.
+	State WalletState
+	// LastAction is the last action executed by the wallet. It is nil if
+	// the wallet has not executed any action yet.
+	LastAction *WalletLastAction
+	// KeyMaterialLocation is the path of the directory holding signers of
+	// the wallet, relative to the tbtc key store directory.
+	KeyMaterialLocation string
+}
+
+// ListWallets returns information about all wallets the node holds signers
+// for in the given key store. The last actions are read from the given work
+// persistence and the on-chain states are fetched from the given chain.
+// Wallets are sorted by their public key hashes. An error is returned if any
+// signer in the key store could not be read.
+func ListWallets(
+	keyStorePersistence persistence.ProtectedHandle,
+	workPersistence persistence.BasicHandle,
+	chain BridgeChain,
+) ([]*WalletInfo, error) {
+	signersByWallet, _, err := newWalletStorage(
+		keyStorePersistence,
+	).loadSigners()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load signers: [%w]", err)
+	}
+
+	var walletsSigners [][]*signer
+	for _, signers := range signersByWallet {
+		walletsSigners = append(walletsSigners, signers)
+	}
+
+	return walletsInfo(
+		walletsSigners,
+		newWalletLastActionStore(workPersistence),
+		func(walletPublicKeyHash [20]byte) WalletState {
+			return fetchWalletState(chain, walletPublicKeyHash)
+		},
+	), nil
+}
+
+// walletsInfo returns information about wallets of the given signers. Each
+// element of the signers slice must hold signers of one wallet. Wallets
+// without signers are skipped. On-chain states of wallets are determined
+// using the given function.
+func walletsInfo(
+	walletsSigners [][]*signer,
+	lastActions *walletLastActionStore,
+	walletStateFn func(walletPublicKeyHash [20]byte) WalletState,
+) []*WalletInfo {
+	result := make([]*WalletInfo, 0, len(walletsSigners))
+
+	for _, signers := range walletsSigners {
+		if len(signers) == 0 {
+			continue
+		}
+
+		// All signers belong to one wallet. Take that wallet from the
+		// first signer.
+		walletPublicKey := signers[0].wallet.publicKey
+		walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+
+		membersIndexes := make([]group.MemberIndex, len(signers))
+		for i, signer := range signers {
+			membersIndexes[i] = signer.signingGroupMemberIndex
+		}
+		sort.Slice(membersIndexes, func(i, j int) bool {
+			return membersIndexes[i] < membersIndexes[j]
+		})
+
+		state := walletStateFn(walletPublicKeyHash)
+
+		lastAction, _ := lastActions.get(walletPublicKeyHash)
+
+		result = append(result, &WalletInfo{
+			WalletPublicKeyHash: walletPublicKeyHash,
+			MembersIndexes:      membersIndexes,
+			State:               state,
+			LastAction:          lastAction,
+			KeyMaterialLocation: fmt.Sprintf(
+				"current/%s",
+				getWalletStorageKey(walletPublicKey),
+			),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return hex.EncodeToString(result[i].WalletPublicKeyHash[:]) <
+			hex.EncodeToString(result[j].WalletPublicKeyHash[:])
+	})
+
+	return result
+}
+
+// fetchWalletState returns the on-chain state of the given wallet. Returns
+// StateUnknown if the state could not be fetched.
+func fetchWalletState(
+	chain BridgeChain,
+	walletPublicKeyHash [20]byte,
+) WalletState {
+	walletChainData, err := chain.GetWallet(walletPublicKeyHash)
+	if err != nil {
+		logger.Warnf(
+			"cannot get on-chain data of wallet [0x%x]: [%v]",
+			walletPublicKeyHash,
+			err,
+		)
+		return StateUnknown
+	}
+
+	return walletChainData.State
+}
+
+// walletStateCache caches on-chain states of wallets for the given period.
+// States that could not be fetched are not cached.
+type walletStateCache struct {
+	chain  BridgeChain
+	period time.Duration
+
+	mutex  sync.Mutex
+	states map[[20]byte]*cachedWalletState
+}
+
+type cachedWalletState struct {
+	state     WalletState
+	fetchedAt time.Time
+}
+
+func newWalletStateCache(
+	chain BridgeChain,
+	period time.Duration,
+) *walletStateCache {
+	return &walletStateCache{
+		chain:  chain,
+		period: period,
+		states: make(map[[20]byte]*cachedWalletState),
+	}
+}
+
+// get returns the on-chain state of the given wallet. The state is fetched
+// from the chain if it is not cached or was cached longer than the cache
+// period ago. Returns StateUnknown if the state could not be fetched.
+func (wsc *walletStateCache) get(walletPublicKeyHash [20]byte) WalletState {
+	wsc.mutex.Lock()
+	cached, ok := wsc.states[walletPublicKeyHash]
+	wsc.mutex.Unlock()
+
+	if ok && time.Since(cached.fetchedAt) < wsc.period {
+		return cached.state
+	}
+
+	state := fetchWalletState(wsc.chain, walletPublicKeyHash)
+	if state == StateUnknown {
+		return state
+	}
+
+	wsc.mutex.Lock()
+	wsc.states[walletPublicKeyHash] = &cachedWalletState{
+		state:     state,
+		fetchedAt: time.Now(),
+	}
+	wsc.mutex.Unlock()
+
+	return state
+}
+
+// walletsInfo returns information about all wallets the node controls,
+// keyed in the `0x<wallet-public-key-hash>` format. Besides the data
+// described by WalletInfo, each wallet entry holds the action currently
+// executed by the wallet, the recent action outcomes, and the coordination
+// proposals whose validity period has not ended yet. The information is
+// meant to drive operator dashboards.
+func (n *node) walletsInfo() clientinfo.ApplicationInfo {
+	var walletsSigners [][]*signer
+	walletsPublicKeys := make(map[[20]byte]*ecdsa.PublicKey)
+	for _, walletPublicKey := range n.walletRegistry.getWalletsPublicKeys() {
+		walletsSigners = append(
+			walletsSigners,
+			n.walletRegistry.getSigners(walletPublicKey),
+		)
+		walletsPublicKeys[bitcoin.PublicKeyHash(walletPublicKey)] =
+			walletPublicKey
+	}
+
+	// Without the current block, expired proposals cannot be told apart
+	// from pending ones so none of them are reported.
+	var currentBlock uint64
+	blockCounter, err := n.chain.BlockCounter()
+	if err == nil {
+		currentBlock, err = blockCounter.CurrentBlock()
+	}
+	if err != nil {
+		logger.Warnf("cannot get current block: [%v]", err)
+		currentBlock = math.MaxUint64
+	}
+
+	info := clientinfo.ApplicationInfo{}
+
+	for _, wallet := range walletsInfo(
+		walletsSigners,
+		n.walletDispatcher.lastActions,
+		n.walletStates.get,
+	) {
+		var lastAction interface{}
+		if wallet.LastAction != nil {
+			lastAction = walletActionInfo(wallet.LastAction)
+		}
+
+		recentActions := make([]interface{}, 0)
+		for _, action := range n.walletDispatcher.lastActions.getRecent(
+			wallet.WalletPublicKeyHash,
+		) {
+			recentActions = append(recentActions, walletActionInfo(action))
+		}
+
+		var currentAction interface{}
+		if actionType, ok := n.walletDispatcher.currentAction(
+			walletsPublicKeys[wallet.WalletPublicKeyHash],
+		); ok {
+			currentAction = actionType.String()
+		}
+
+		pendingProposals := make([]interface{}, 0)
+		for _, proposal := range n.pendingProposals.get(
+			wallet.WalletPublicKeyHash,
+			currentBlock,
+		) {
+			pendingProposals = append(pendingProposals, map[string]interface{}{
+				"action_type":        proposal.ActionType.String(),
+				"coordination_block": proposal.CoordinationBlock,
+				"start_block":        proposal.StartBlock,
+				"expiry_block":       proposal.ExpiryBlock,
+			})
+		}
+
+		key := fmt.Sprintf("0x%x", wallet.WalletPublicKeyHash)
+		info[key] = map[string]interface{}{
+			"members_indexes":       wallet.MembersIndexes,
+			"state":                 wallet.State.String(),
+			"current_action":        currentAction,
+			"last_action":           lastAction,
+			"recent_actions":        recentActions,
+			"pending_proposals":     pendingProposals,
+			"key_material_location": wallet.KeyMaterialLocation,
+		}
+	}
+
+	return info
+}
+
+// walletActionInfo converts the given wallet action to the diagnostics
+// format.
+func walletActionInfo(action *WalletLastAction) map[string]interface{} {
+	return map[string]interface{}{
+		"type":         action.Type,
+		"started_at":   action.StartedAt.Unix(),
+		"completed_at": action.CompletedAt.Unix(),
+		"error":        action.Error,
+	}
+}
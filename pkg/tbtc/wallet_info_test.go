@@ -0,0 +1,238 @@
+package tbtc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestWalletLastActionStore(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	store := newWalletLastActionStore(persistenceHandle)
+
+	walletPublicKeyHash := [20]byte{0x01}
+
+	if _, ok := store.get(walletPublicKeyHash); ok {
+		t.Fatal("no last action should be recorded")
+	}
+
+	startedAt := time.Unix(1000, 0).UTC()
+	completedAt := time.Unix(2000, 0).UTC()
+
+	action := &WalletLastAction{
+		Type:        ActionDepositSweep.String(),
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+		Error:       "unexpected error",
+	}
+
+	if err := store.record(walletPublicKeyHash, action); err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the store from scratch to make sure the action is persisted.
+	reloadedStore := newWalletLastActionStore(persistenceHandle)
+
+	reloadedAction, ok := reloadedStore.get(walletPublicKeyHash)
+	if !ok {
+		t.Fatal("last action should be recorded")
+	}
+
+	if !reflect.DeepEqual(action, reloadedAction) {
+		t.Errorf(
+			"unexpected last action\nexpected: [%+v]\nactual:   [%+v]",
+			action,
+			reloadedAction,
+		)
+	}
+}
+
+func TestWalletLastActionStore_RecentActions(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	store := newWalletLastActionStore(persistenceHandle)
+
+	walletPublicKeyHash := [20]byte{0x01}
+
+	var actions []*WalletLastAction
+	for i := 0; i < walletRecentActionsLimit+2; i++ {
+		action := &WalletLastAction{
+			Type:        ActionHeartbeat.String(),
+			StartedAt:   time.Unix(int64(1000*i), 0).UTC(),
+			CompletedAt: time.Unix(int64(1000*i+500), 0).UTC(),
+		}
+
+		if err := store.record(walletPublicKeyHash, action); err != nil {
+			t.Fatal(err)
+		}
+
+		actions = append(actions, action)
+	}
+
+	// Only the most recent actions are kept.
+	expectedActions := actions[2:]
+	recentActions := store.getRecent(walletPublicKeyHash)
+	if !reflect.DeepEqual(expectedActions, recentActions) {
+		t.Errorf(
+			"unexpected recent actions\nexpected: [%v]\nactual:   [%v]",
+			expectedActions,
+			recentActions,
+		)
+	}
+
+	// The history is not persisted. Only the last action is loaded.
+	reloadedStore := newWalletLastActionStore(&mockPersistenceHandle{
+		saved: persistenceHandle.saved[len(persistenceHandle.saved)-1:],
+	})
+
+	expectedActions = actions[len(actions)-1:]
+	recentActions = reloadedStore.getRecent(walletPublicKeyHash)
+	if !reflect.DeepEqual(expectedActions, recentActions) {
+		t.Errorf(
+			"unexpected recent actions of reloaded store\n"+
+				"expected: [%v]\nactual:   [%v]",
+			expectedActions,
+			recentActions,
+		)
+	}
+}
+
+func TestListWallets(t *testing.T) {
+	signer := createMockSigner(t)
+
+	keyStorePersistence := createMockKeyStorePersistence(t, signer)
+	workPersistence := &mockPersistenceHandle{}
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+	action := &WalletLastAction{
+		Type:        ActionHeartbeat.String(),
+		StartedAt:   time.Unix(1000, 0).UTC(),
+		CompletedAt: time.Unix(2000, 0).UTC(),
+	}
+
+	err := newWalletLastActionStore(workPersistence).record(
+		walletPublicKeyHash,
+		action,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	localChain := Connect()
+
+	wallets, err := ListWallets(keyStorePersistence, workPersistence, localChain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "wallets count", 1, len(wallets))
+
+	// The wallet is not known to the chain so its state is unknown.
+	testutils.AssertStringsEqual(
+		t,
+		"state",
+		StateUnknown.String(),
+		wallets[0].State.String(),
+	)
+
+	localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+		State: StateLive,
+	})
+
+	wallets, err = ListWallets(keyStorePersistence, workPersistence, localChain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "wallets count", 1, len(wallets))
+
+	expectedWallet := &WalletInfo{
+		WalletPublicKeyHash: walletPublicKeyHash,
+		MembersIndexes: []group.MemberIndex{
+			signer.signingGroupMemberIndex,
+		},
+		State:      StateLive,
+		LastAction: action,
+		KeyMaterialLocation: fmt.Sprintf(
+			"current/%s",
+			getWalletStorageKey(signer.wallet.publicKey),
+		),
+	}
+
+	if !reflect.DeepEqual(expectedWallet, wallets[0]) {
+		t.Errorf(
+			"unexpected wallet\nexpected: [%+v]\nactual:   [%+v]",
+			expectedWallet,
+			wallets[0],
+		)
+	}
+}
+
+func TestWalletStateCache(t *testing.T) {
+	walletPublicKeyHash := [20]byte{0x01}
+
+	localChain := Connect()
+
+	cache := newWalletStateCache(localChain, time.Hour)
+
+	// The state of a wallet unknown to the chain is not cached.
+	testutils.AssertStringsEqual(
+		t,
+		"state",
+		StateUnknown.String(),
+		cache.get(walletPublicKeyHash).String(),
+	)
+
+	localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+		State: StateLive,
+	})
+
+	testutils.AssertStringsEqual(
+		t,
+		"state",
+		StateLive.String(),
+		cache.get(walletPublicKeyHash).String(),
+	)
+
+	localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+		State: StateMovingFunds,
+	})
+
+	// The state is cached for the cache period.
+	testutils.AssertStringsEqual(
+		t,
+		"cached state",
+		StateLive.String(),
+		cache.get(walletPublicKeyHash).String(),
+	)
+
+	// The state is fetched again once the cache period passes.
+	cache.period = 0
+	testutils.AssertStringsEqual(
+		t,
+		"refreshed state",
+		StateMovingFunds.String(),
+		cache.get(walletPublicKeyHash).String(),
+	)
+}
+
+func TestWalletsInfo_NoSigners(t *testing.T) {
+	walletSigner := createMockSigner(t)
+
+	wallets := walletsInfo(
+		[][]*signer{{}, {walletSigner}},
+		newWalletLastActionStore(&mockPersistenceHandle{}),
+		func(walletPublicKeyHash [20]byte) WalletState {
+			return StateLive
+		},
+	)
+
+	testutils.AssertIntsEqual(t, "wallets count", 1, len(wallets))
+}
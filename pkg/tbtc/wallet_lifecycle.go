@@ -0,0 +1,99 @@
+package tbtc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// walletLifecycleCheckTick determines how often the node checks the on-chain
+// state of its wallets to archive the ones that are no longer active. Wallets
+// are closed rarely so there is no need to check them more often.
+const walletLifecycleCheckTick = 6 * time.Hour
+
+// monitorWalletLifecycle periodically archives wallets that reached the
+// Closed or Terminated state on-chain. The first check is made immediately.
+// The function blocks until the given context is done.
+func (n *node) monitorWalletLifecycle(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		n.archiveClosedWallets()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// archiveClosedWallets archives all wallets controlled by the node that
+// reached the Closed or Terminated state on-chain. Signers of such wallets
+// can no longer be used so they are moved to the archive of the key store
+// and removed from the wallet registry. Executors of archived wallets are
+// released as well so the wallets no longer consume node's resources.
+func (n *node) archiveClosedWallets() {
+	for _, walletPublicKey := range n.walletRegistry.getWalletsPublicKeys() {
+		walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+
+		walletChainData, err := n.chain.GetWallet(walletPublicKeyHash)
+		if err != nil {
+			logger.Errorf(
+				"cannot get on-chain data of wallet [0x%x]: [%v]",
+				walletPublicKeyHash,
+				err,
+			)
+			continue
+		}
+
+		if walletChainData.State != StateClosed &&
+			walletChainData.State != StateTerminated {
+			continue
+		}
+
+		logger.Infof(
+			"wallet [0x%x] is in the [%v] state; archiving its signers",
+			walletPublicKeyHash,
+			walletChainData.State,
+		)
+
+		if err := n.walletRegistry.archiveWallet(walletPublicKeyHash); err != nil {
+			logger.Errorf(
+				"cannot archive wallet [0x%x]: [%v]",
+				walletPublicKeyHash,
+				err,
+			)
+			continue
+		}
+
+		n.removeWalletExecutors(walletPublicKey)
+		n.pendingProposals.remove(walletPublicKeyHash)
+
+		logger.Infof("wallet [0x%x] archived", walletPublicKeyHash)
+	}
+}
+
+// removeWalletExecutors removes signing and coordination executors of the
+// given wallet from the node's caches.
+func (n *node) removeWalletExecutors(walletPublicKey *ecdsa.PublicKey) {
+	walletPublicKeyBytes, err := marshalPublicKey(walletPublicKey)
+	if err != nil {
+		logger.Errorf("cannot marshal wallet public key: [%v]", err)
+		return
+	}
+
+	executorKey := hex.EncodeToString(walletPublicKeyBytes)
+
+	n.signingExecutorsMutex.Lock()
+	delete(n.signingExecutors, executorKey)
+	n.signingExecutorsMutex.Unlock()
+
+	n.coordinationExecutorsMutex.Lock()
+	delete(n.coordinationExecutors, executorKey)
+	n.coordinationExecutorsMutex.Unlock()
+}
@@ -0,0 +1,95 @@
+package tbtc
+
+import (
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/generator"
+	"github.com/keep-network/keep-core/pkg/net/local"
+)
+
+func TestNode_ArchiveClosedWallets(t *testing.T) {
+	groupParameters := &GroupParameters{
+		GroupSize:       5,
+		GroupQuorum:     4,
+		HonestThreshold: 3,
+	}
+
+	localChain := Connect()
+	localProvider := local.Connect()
+
+	signer := createMockSigner(t)
+
+	keyStorePersistence := createMockKeyStorePersistence(t, signer)
+
+	node, err := newNode(
+		groupParameters,
+		localChain,
+		newLocalBitcoinChain(),
+		localProvider,
+		keyStorePersistence,
+		&mockPersistenceHandle{},
+		generator.StartScheduler(),
+		&mockCoordinationProposalGenerator{},
+		Config{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletPublicKey := signer.wallet.publicKey
+	walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+
+	_, ok, err := node.getSigningExecutor(walletPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("node is supposed to control wallet signers")
+	}
+
+	localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+		State: StateLive,
+	})
+
+	node.archiveClosedWallets()
+
+	testutils.AssertIntsEqual(
+		t,
+		"signers count of live wallet",
+		1,
+		len(node.walletRegistry.getSigners(walletPublicKey)),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"signing executors count of live wallet",
+		1,
+		len(node.signingExecutors),
+	)
+
+	localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+		State: StateClosed,
+	})
+
+	node.archiveClosedWallets()
+
+	testutils.AssertIntsEqual(
+		t,
+		"signers count of closed wallet",
+		0,
+		len(node.walletRegistry.getSigners(walletPublicKey)),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"signing executors count of closed wallet",
+		0,
+		len(node.signingExecutors),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"persisted signers count of closed wallet",
+		0,
+		len(keyStorePersistence.saved),
+	)
+}
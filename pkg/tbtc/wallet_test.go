@@ -81,7 +81,9 @@ func TestParseWalletActionType(t *testing.T) {
 }
 
 func TestWalletDispatcher_Dispatch(t *testing.T) {
-	walletDispatcher := newWalletDispatcher()
+	walletDispatcher := newWalletDispatcher(
+		newWalletLastActionStore(&mockPersistenceHandle{}),
+	)
 
 	wallet1 := generateWallet(big.NewInt(100))
 	wallet2 := generateWallet(big.NewInt(101))
@@ -170,6 +172,52 @@ func TestWalletDispatcher_Dispatch(t *testing.T) {
 	}
 }
 
+func TestWalletDispatcher_Dispatch_ActionSucceededHandler(t *testing.T) {
+	walletDispatcher := newWalletDispatcher(
+		newWalletLastActionStore(&mockPersistenceHandle{}),
+	)
+
+	succeededActions := make(chan walletAction, 2)
+	walletDispatcher.actionSucceededHandler = func(action walletAction) {
+		succeededActions <- action
+	}
+
+	failingAction := &mockWalletAction{
+		executeFn: func() error {
+			return fmt.Errorf("unexpected error")
+		},
+		actionWallet: generateWallet(big.NewInt(100)),
+	}
+	succeedingAction := &mockWalletAction{
+		executeFn: func() error {
+			return nil
+		},
+		actionWallet: generateWallet(big.NewInt(101)),
+	}
+
+	if err := walletDispatcher.dispatch(failingAction); err != nil {
+		t.Fatal(err)
+	}
+	if err := walletDispatcher.dispatch(succeedingAction); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case action := <-succeededActions:
+		if action != succeedingAction {
+			t.Errorf("handler invoked for unexpected action")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler not invoked")
+	}
+
+	select {
+	case <-succeededActions:
+		t.Errorf("handler invoked for failed action")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func TestDetermineWalletMainUtxo(t *testing.T) {
 	// In this scenario, we are using e6f9d74726b19b75f16fe1e9feaec048aa4fa1d0
 	// as the wallet public key hash. This PKH translates to two testnet addresses:
@@ -429,6 +477,7 @@ func newMockWalletSigningExecutor() *mockWalletSigningExecutor {
 
 func (mwse *mockWalletSigningExecutor) signBatch(
 	ctx context.Context,
+	actionType WalletActionType,
 	messages []*big.Int,
 	startBlock uint64,
 ) ([]*tecdsa.Signature, error) {
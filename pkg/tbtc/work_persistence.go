@@ -0,0 +1,93 @@
+package tbtc
+
+import (
+	"fmt"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+// snapshotReadHandle is a work persistence handle whose ReadAll replays the
+// data read from the wrapped handle once, when the snapshotReadHandle was
+// created. Stores of the node load their state with ReadAll once they are
+// created so sharing the snapshotReadHandle between them makes the work
+// directory scanned only once on the node start instead of once per store.
+// Data persisted after the snapshotReadHandle was created are not returned by
+// ReadAll so the handle must not be used to read data after the start. All
+// other calls are passed to the wrapped handle.
+type snapshotReadHandle struct {
+	persistence.BasicHandle
+
+	descriptors []persistence.DataDescriptor
+	errors      []error
+}
+
+// newSnapshotReadHandle wraps the given handle and reads all the data
+// persisted in it.
+func newSnapshotReadHandle(
+	handle persistence.BasicHandle,
+) *snapshotReadHandle {
+	srh := &snapshotReadHandle{
+		BasicHandle: handle,
+		descriptors: make([]persistence.DataDescriptor, 0),
+		errors:      make([]error, 0),
+	}
+
+	persistenceutil.ReadAll(
+		handle,
+		func(descriptor persistence.DataDescriptor) {
+			srh.descriptors = append(srh.descriptors, descriptor)
+		},
+		func(err error) {
+			srh.errors = append(srh.errors, err)
+		},
+	)
+
+	return srh
+}
+
+// ReadAll returns the data read when the snapshotReadHandle was created.
+// Contents of the data are read from the wrapped handle on demand.
+func (srh *snapshotReadHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	descriptorsChan := make(chan persistence.DataDescriptor, len(srh.descriptors))
+	errorsChan := make(chan error, len(srh.errors))
+
+	for _, descriptor := range srh.descriptors {
+		descriptorsChan <- descriptor
+	}
+
+	for _, err := range srh.errors {
+		errorsChan <- err
+	}
+
+	close(descriptorsChan)
+	close(errorsChan)
+
+	return descriptorsChan, errorsChan
+}
+
+// rawMover is implemented by persistence handles able to move an entry
+// between directories without reading its content.
+type rawMover interface {
+	Move(fromDirectory string, name string, toDirectory string) error
+}
+
+// Move moves the entry with the wrapped handle if the wrapped handle supports
+// moving entries. Embedding only persistence.BasicHandle would hide the Move
+// function of the wrapped handle from stores checking for it, e.g. the
+// tECDSA pre-parameters storage quarantining unreadable entries.
+func (srh *snapshotReadHandle) Move(
+	fromDirectory string,
+	name string,
+	toDirectory string,
+) error {
+	mover, ok := srh.BasicHandle.(rawMover)
+	if !ok {
+		return fmt.Errorf("persistence does not support moving entries")
+	}
+
+	return mover.Move(fromDirectory, name, toDirectory)
+}
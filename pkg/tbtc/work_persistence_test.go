@@ -0,0 +1,123 @@
+package tbtc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/generator"
+	"github.com/keep-network/keep-core/pkg/net/local"
+	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
+)
+
+func TestSnapshotReadHandle(t *testing.T) {
+	handle := &readCountingPersistenceHandle{
+		mockPersistenceHandle: &mockPersistenceHandle{},
+	}
+
+	if err := handle.Save([]byte{0x01}, "dir1", "file1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := handle.Save([]byte{0x02}, "dir2", "file2"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotHandle := newSnapshotReadHandle(handle)
+
+	for i := 0; i < 3; i++ {
+		descriptorsChan, errorsChan := snapshotHandle.ReadAll()
+
+		descriptors := make([]persistence.DataDescriptor, 0)
+		for descriptor := range descriptorsChan {
+			descriptors = append(descriptors, descriptor)
+		}
+		for err := range errorsChan {
+			t.Fatal(err)
+		}
+
+		testutils.AssertIntsEqual(t, "descriptors count", 2, len(descriptors))
+	}
+
+	testutils.AssertIntsEqual(t, "read all count", 1, handle.readAllCount)
+
+	// Other calls are passed to the wrapped handle.
+	if err := snapshotHandle.Save([]byte{0x03}, "dir3", "file3"); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(t, "saved count", 3, len(handle.saved))
+}
+
+type readCountingPersistenceHandle struct {
+	*mockPersistenceHandle
+	readAllCount int
+}
+
+func (rcph *readCountingPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	rcph.readAllCount++
+	return rcph.mockPersistenceHandle.ReadAll()
+}
+
+// TestNewNode_QuarantinesUnreadablePreParams ensures the work persistence
+// handle wrapped by the node still lets the tECDSA pre-parameters storage
+// move unreadable entries to the quarantine.
+func TestNewNode_QuarantinesUnreadablePreParams(t *testing.T) {
+	workPersistence := &movingPersistenceHandle{
+		mockPersistenceHandle: &mockPersistenceHandle{
+			saved: []persistence.DataDescriptor{
+				&mockDescriptor{
+					name:      "unreadable",
+					directory: dkg.PreParamsDirName,
+					err:       fmt.Errorf("unreadable"),
+				},
+			},
+		},
+	}
+
+	_, err := newNode(
+		&GroupParameters{
+			GroupSize:       5,
+			GroupQuorum:     4,
+			HonestThreshold: 3,
+		},
+		Connect(),
+		newLocalBitcoinChain(),
+		local.Connect(),
+		&mockPersistenceHandle{},
+		workPersistence,
+		generator.StartScheduler(),
+		&mockCoordinationProposalGenerator{},
+		Config{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMoved := []string{dkg.PreParamsDirName + "/unreadable"}
+	if !reflect.DeepEqual(expectedMoved, workPersistence.moved) {
+		t.Errorf(
+			"unexpected moved entries\nexpected: %v\nactual:   %v",
+			expectedMoved,
+			workPersistence.moved,
+		)
+	}
+}
+
+type movingPersistenceHandle struct {
+	*mockPersistenceHandle
+	moved []string
+}
+
+func (mph *movingPersistenceHandle) Move(
+	fromDirectory string,
+	name string,
+	toDirectory string,
+) error {
+	mph.moved = append(mph.moved, fromDirectory+"/"+name)
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"math/big"
 	"sort"
+	"time"
 
 	"github.com/ipfs/go-log/v2"
 	"go.uber.org/zap"
@@ -22,6 +23,10 @@ const depositScriptByteSize = 126
 type DepositSweepTask struct {
 	chain    Chain
 	btcChain bitcoin.Chain
+
+	// stateStore is an optional store of deposits already included in
+	// recent proposals. If nil, all unswept deposits are proposed.
+	stateStore *ProposalStateStore
 }
 
 func NewDepositSweepTask(
@@ -66,6 +71,20 @@ func (dst *DepositSweepTask) Run(request *tbtc.CoordinationProposalRequest) (
 		)
 	}
 
+	if dst.stateStore != nil {
+		deposits, err = dst.skipConfirmingDeposits(
+			taskLogger,
+			walletPublicKeyHash,
+			deposits,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf(
+				"cannot check proposal state: [%w]",
+				err,
+			)
+		}
+	}
+
 	if len(deposits) == 0 {
 		taskLogger.Info("no deposits to sweep")
 		return nil, false, nil
@@ -87,6 +106,63 @@ func (dst *DepositSweepTask) Run(request *tbtc.CoordinationProposalRequest) (
 	return proposal, true, nil
 }
 
+// skipConfirmingDeposits refreshes the proposal state of the given wallet
+// and returns the given deposits without those that were recently proposed
+// and whose sweep is still being confirmed.
+func (dst *DepositSweepTask) skipConfirmingDeposits(
+	taskLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	deposits []*DepositReference,
+) ([]*DepositReference, error) {
+	now := time.Now()
+
+	err := dst.stateStore.Refresh(
+		ProposedDeposit,
+		walletPublicKeyHash,
+		func(key []byte) (bool, error) {
+			fundingTxHash, fundingOutputIndex, err := parseDepositItemKey(key)
+			if err != nil {
+				return false, err
+			}
+
+			depositRequest, found, err := dst.chain.GetDepositRequest(
+				fundingTxHash,
+				fundingOutputIndex,
+			)
+			if err != nil {
+				return false, err
+			}
+
+			return found && !depositRequest.SweptAt.IsZero(), nil
+		},
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*DepositReference, 0, len(deposits))
+	for _, deposit := range deposits {
+		if dst.stateStore.IsConfirming(
+			ProposedDeposit,
+			walletPublicKeyHash,
+			depositItemKey(deposit.FundingTxHash, deposit.FundingOutputIndex),
+			now,
+		) {
+			taskLogger.Infof(
+				"skipping deposit [%s:%d] as it was recently proposed",
+				deposit.FundingTxHash.Hex(bitcoin.ReversedByteOrder),
+				deposit.FundingOutputIndex,
+			)
+			continue
+		}
+
+		result = append(result, deposit)
+	}
+
+	return result, nil
+}
+
 func (dst *DepositSweepTask) ActionType() tbtc.WalletActionType {
 	return tbtc.ActionDepositSweep
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/tbtc"
 	"github.com/keep-network/keep-core/pkg/tbtcpg"
 	"github.com/keep-network/keep-core/pkg/tbtcpg/internal/test"
+	"github.com/keep-network/keep-core/pkg/tbtcpg/tbtcpgtest"
 )
 
 func TestDepositSweepTask_FindDepositsToSweep(t *testing.T) {
@@ -26,8 +27,8 @@ func TestDepositSweepTask_FindDepositsToSweep(t *testing.T) {
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.Title, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
-			btcChain := tbtcpg.NewLocalBitcoinChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
+			btcChain := tbtcpgtest.NewLocalBitcoinChain()
 
 			// Chain setup.
 			for _, deposit := range scenario.Deposits {
@@ -92,8 +93,8 @@ func TestDepositSweepTask_ProposeDepositsSweep(t *testing.T) {
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.Title, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
-			btcChain := tbtcpg.NewLocalBitcoinChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
+			btcChain := tbtcpgtest.NewLocalBitcoinChain()
 
 			// Chain setup.
 			tbtcChain.SetDepositParameters(0, 0, scenario.DepositTxMaxFee, 0)
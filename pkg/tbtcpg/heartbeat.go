@@ -5,18 +5,45 @@ import (
 	"encoding/binary"
 	"fmt"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
+// HeartbeatMessageFn derives the message that should be signed by the wallet
+// with the given public key hash as part of the heartbeat action. The
+// derived message is always validated against the chain before being used
+// in a proposal.
+type HeartbeatMessageFn func(walletPublicKeyHash [20]byte) ([16]byte, error)
+
+// HeartbeatTaskOption allows to customize the heartbeat task.
+type HeartbeatTaskOption func(task *HeartbeatTask)
+
+// WithHeartbeatMessageFn sets the function used by the heartbeat task to
+// derive the heartbeat message. If not set, the message is derived using
+// HostChainBlockHeartbeatMessage.
+func WithHeartbeatMessageFn(messageFn HeartbeatMessageFn) HeartbeatTaskOption {
+	return func(task *HeartbeatTask) {
+		task.messageFn = messageFn
+	}
+}
+
 // HeartbeatTask is a task that may produce a heartbeat proposal.
 type HeartbeatTask struct {
-	chain Chain
+	chain     Chain
+	messageFn HeartbeatMessageFn
 }
 
-func NewHeartbeatTask(chain Chain) *HeartbeatTask {
-	return &HeartbeatTask{
-		chain: chain,
+func NewHeartbeatTask(chain Chain, options ...HeartbeatTaskOption) *HeartbeatTask {
+	task := &HeartbeatTask{
+		chain:     chain,
+		messageFn: HostChainBlockHeartbeatMessage(chain),
 	}
+
+	for _, option := range options {
+		option(task)
+	}
+
+	return task
 }
 
 func (ht *HeartbeatTask) Run(request *tbtc.CoordinationProposalRequest) (
@@ -26,23 +53,12 @@ func (ht *HeartbeatTask) Run(request *tbtc.CoordinationProposalRequest) (
 ) {
 	walletPublicKeyHash := request.WalletPublicKeyHash
 
-	blockCounter, err := ht.chain.BlockCounter()
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to get block counter: [%v]", err)
-	}
-
-	block, err := blockCounter.CurrentBlock()
+	message, err := ht.messageFn(walletPublicKeyHash)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to get current block: [%v]", err)
-	}
-	blockBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(blockBytes, block)
-
-	hash := sha256.Sum256(append(walletPublicKeyHash[:], blockBytes...))
-
-	message := [16]byte{
-		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
-		hash[0], hash[1], hash[2], hash[3], hash[4], hash[5], hash[6], hash[7],
+		return nil, false, fmt.Errorf(
+			"failed to derive heartbeat message: [%v]",
+			err,
+		)
 	}
 
 	proposal := &tbtc.HeartbeatProposal{
@@ -65,3 +81,69 @@ func (ht *HeartbeatTask) Run(request *tbtc.CoordinationProposalRequest) (
 func (ht *HeartbeatTask) ActionType() tbtc.WalletActionType {
 	return tbtc.ActionHeartbeat
 }
+
+// HostChainBlockHeartbeatMessage returns a heartbeat message function that
+// binds the message to the current block of the host chain.
+func HostChainBlockHeartbeatMessage(chain Chain) HeartbeatMessageFn {
+	return func(walletPublicKeyHash [20]byte) ([16]byte, error) {
+		blockCounter, err := chain.BlockCounter()
+		if err != nil {
+			return [16]byte{}, fmt.Errorf(
+				"failed to get block counter: [%v]",
+				err,
+			)
+		}
+
+		block, err := blockCounter.CurrentBlock()
+		if err != nil {
+			return [16]byte{}, fmt.Errorf(
+				"failed to get current block: [%v]",
+				err,
+			)
+		}
+		blockBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(blockBytes, block)
+
+		return newHeartbeatMessage(walletPublicKeyHash, blockBytes), nil
+	}
+}
+
+// BitcoinBlockHeartbeatMessage returns a heartbeat message function that
+// binds the message to the hash of the latest Bitcoin block. Such a message
+// proves the wallet was alive after the given Bitcoin block was mined.
+func BitcoinBlockHeartbeatMessage(btcChain bitcoin.Chain) HeartbeatMessageFn {
+	return func(walletPublicKeyHash [20]byte) ([16]byte, error) {
+		latestBlockHeight, err := btcChain.GetLatestBlockHeight()
+		if err != nil {
+			return [16]byte{}, fmt.Errorf(
+				"failed to get latest Bitcoin block height: [%v]",
+				err,
+			)
+		}
+
+		blockHeader, err := btcChain.GetBlockHeader(latestBlockHeight)
+		if err != nil {
+			return [16]byte{}, fmt.Errorf(
+				"failed to get Bitcoin block header: [%v]",
+				err,
+			)
+		}
+
+		blockHash := blockHeader.Hash()
+
+		return newHeartbeatMessage(walletPublicKeyHash, blockHash[:]), nil
+	}
+}
+
+// newHeartbeatMessage builds a heartbeat message for the given wallet using
+// the given seed. The message is prefixed with 8 bytes of 0xff, as required
+// by the Bridge, followed by the first 8 bytes of the
+// sha256(walletPublicKeyHash | seed) hash.
+func newHeartbeatMessage(walletPublicKeyHash [20]byte, seed []byte) [16]byte {
+	hash := sha256.Sum256(append(walletPublicKeyHash[:], seed...))
+
+	return [16]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		hash[0], hash[1], hash[2], hash[3], hash[4], hash[5], hash[6], hash[7],
+	}
+}
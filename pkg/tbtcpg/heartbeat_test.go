@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tbtcpg/tbtcpgtest"
 )
 
 func TestHeartbeatTask_Run(t *testing.T) {
@@ -39,8 +41,8 @@ func TestHeartbeatTask_Run(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := NewLocalChain()
-			blockCounter := NewMockBlockCounter()
+			tbtcChain := tbtcpgtest.NewLocalChain()
+			blockCounter := tbtcpgtest.NewMockBlockCounter()
 
 			blockCounter.SetCurrentBlock(900)
 			tbtcChain.SetBlockCounter(blockCounter)
@@ -86,3 +88,54 @@ func TestHeartbeatTask_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestHeartbeatTask_Run_BitcoinBlockHeartbeatMessage(t *testing.T) {
+	expectedProposal := &tbtc.HeartbeatProposal{
+		Message: [16]byte{
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0xeb, 0xe3, 0xa3, 0xd4, 0x17, 0x29, 0x89, 0x57,
+		},
+	}
+
+	tbtcChain := tbtcpgtest.NewLocalChain()
+	btcChain := tbtcpgtest.NewLocalBitcoinChain()
+
+	btcChain.SetLatestBlockHeight(800000)
+	btcChain.SetBlockHeader(800000, &bitcoin.BlockHeader{
+		Version:                 0x20000000,
+		PreviousBlockHeaderHash: bitcoin.Hash{0x01},
+		MerkleRootHash:          bitcoin.Hash{0x02},
+		Time:                    1690000000,
+		Bits:                    0x17053894,
+		Nonce:                   1234,
+	})
+
+	tbtcChain.SetHeartbeatProposalValidationResult(expectedProposal, true)
+
+	walletPublicKeyHash := [20]byte{0x01, 0x02}
+
+	task := NewHeartbeatTask(
+		tbtcChain,
+		WithHeartbeatMessageFn(BitcoinBlockHeartbeatMessage(btcChain)),
+	)
+
+	proposal, ok, err := task.Run(
+		&tbtc.CoordinationProposalRequest{
+			// Set only relevant fields.
+			WalletPublicKeyHash: walletPublicKeyHash,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertBoolsEqual(t, "boolean flag", true, ok)
+
+	if !reflect.DeepEqual(expectedProposal, proposal) {
+		t.Errorf(
+			"unexpected proposal\nexpected: [%v]\nactual:   [%v]",
+			expectedProposal,
+			proposal,
+		)
+	}
+}
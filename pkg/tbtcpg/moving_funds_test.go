@@ -13,6 +13,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 	"github.com/keep-network/keep-core/pkg/tbtcpg"
+	"github.com/keep-network/keep-core/pkg/tbtcpg/tbtcpgtest"
 )
 
 func TestMovingFundsAction_FindTargetWallets_CommitmentNotSubmittedYet(t *testing.T) {
@@ -126,7 +127,7 @@ func TestMovingFundsAction_FindTargetWallets_CommitmentNotSubmittedYet(t *testin
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
 			tbtcChain.SetWalletParameters(
 				0,
 				0,
@@ -255,11 +256,11 @@ func TestMovingFundsAction_FindTargetWallets_CommitmentAlreadySubmitted(t *testi
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
 
 			tbtcChain.SetAverageBlockTime(averageBlockTime)
 
-			blockCounter := tbtcpg.NewMockBlockCounter()
+			blockCounter := tbtcpgtest.NewMockBlockCounter()
 			blockCounter.SetCurrentBlock(currentBlock)
 			tbtcChain.SetBlockCounter(blockCounter)
 
@@ -364,7 +365,7 @@ func TestMovingFundsAction_GetWalletMembersInfo(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
 
 			task := tbtcpg.NewMovingFundsTask(tbtcChain, nil)
 
@@ -456,7 +457,7 @@ func TestMovingFundsAction_SubmitMovingFundsCommitment(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
 
 			tbtcChain.SetWallet(
 				walletPublicKeyHash,
@@ -465,7 +466,7 @@ func TestMovingFundsAction_SubmitMovingFundsCommitment(t *testing.T) {
 				},
 			)
 
-			blockCounter := tbtcpg.NewMockBlockCounter()
+			blockCounter := tbtcpgtest.NewMockBlockCounter()
 			blockCounter.SetCurrentBlock(currentBlock)
 			tbtcChain.SetBlockCounter(blockCounter)
 
@@ -576,8 +577,8 @@ func TestMovingFundsAction_ProposeMovingFunds(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
-			btcChain := tbtcpg.NewLocalBitcoinChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
+			btcChain := tbtcpgtest.NewLocalBitcoinChain()
 
 			btcChain.SetEstimateSatPerVByteFee(1, 25)
 
@@ -645,7 +646,7 @@ func TestEstimateMovingFundsFee(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			btcChain := tbtcpg.NewLocalBitcoinChain()
+			btcChain := tbtcpgtest.NewLocalBitcoinChain()
 			btcChain.SetEstimateSatPerVByteFee(1, 16)
 
 			targetWalletsCount := 4
@@ -18,6 +18,11 @@ import (
 type RedemptionTask struct {
 	chain    Chain
 	btcChain bitcoin.Chain
+
+	// stateStore is an optional store of redemption requests already
+	// included in recent proposals. If nil, all pending redemption requests
+	// are proposed.
+	stateStore *ProposalStateStore
 }
 
 func NewRedemptionTask(
@@ -62,6 +67,20 @@ func (rt *RedemptionTask) Run(request *tbtc.CoordinationProposalRequest) (
 		)
 	}
 
+	if rt.stateStore != nil {
+		redeemersOutputScripts, err = rt.skipConfirmingRedemptions(
+			taskLogger,
+			walletPublicKeyHash,
+			redeemersOutputScripts,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf(
+				"cannot check proposal state: [%w]",
+				err,
+			)
+		}
+	}
+
 	if len(redeemersOutputScripts) == 0 {
 		taskLogger.Info("no pending redemption requests")
 		return nil, false, nil
@@ -83,6 +102,57 @@ func (rt *RedemptionTask) Run(request *tbtc.CoordinationProposalRequest) (
 	return proposal, true, nil
 }
 
+// skipConfirmingRedemptions refreshes the proposal state of the given wallet
+// and returns the given redeemers output scripts without those whose
+// redemption requests were recently proposed and are still being confirmed.
+func (rt *RedemptionTask) skipConfirmingRedemptions(
+	taskLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	redeemersOutputScripts []bitcoin.Script,
+) ([]bitcoin.Script, error) {
+	now := time.Now()
+
+	err := rt.stateStore.Refresh(
+		ProposedRedemption,
+		walletPublicKeyHash,
+		func(key []byte) (bool, error) {
+			_, found, err := rt.chain.GetPendingRedemptionRequest(
+				walletPublicKeyHash,
+				key,
+			)
+			if err != nil {
+				return false, err
+			}
+
+			return !found, nil
+		},
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bitcoin.Script, 0, len(redeemersOutputScripts))
+	for _, script := range redeemersOutputScripts {
+		if rt.stateStore.IsConfirming(
+			ProposedRedemption,
+			walletPublicKeyHash,
+			script,
+			now,
+		) {
+			taskLogger.Infof(
+				"skipping redemption request [0x%x] as it was recently proposed",
+				script,
+			)
+			continue
+		}
+
+		result = append(result, script)
+	}
+
+	return result, nil
+}
+
 func (rt *RedemptionTask) ActionType() tbtc.WalletActionType {
 	return tbtc.ActionRedemption
 }
@@ -428,6 +498,8 @@ func EstimateRedemptionFee(
 			sizeEstimator.AddScriptHashOutputs(1, false)
 		case bitcoin.P2WSHScript:
 			sizeEstimator.AddScriptHashOutputs(1, true)
+		case bitcoin.P2TRScript:
+			sizeEstimator.AddTaprootOutputs(1)
 		default:
 			return 0, fmt.Errorf("non-standard redeemer output script type")
 		}
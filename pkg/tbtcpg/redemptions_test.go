@@ -11,6 +11,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/tbtc"
 	"github.com/keep-network/keep-core/pkg/tbtcpg"
 	"github.com/keep-network/keep-core/pkg/tbtcpg/internal/test"
+	"github.com/keep-network/keep-core/pkg/tbtcpg/tbtcpgtest"
 )
 
 // Test based on example testnet redemption transaction:
@@ -24,7 +25,7 @@ func TestEstimateRedemptionFee(t *testing.T) {
 		return bytes
 	}
 
-	btcChain := tbtcpg.NewLocalBitcoinChain()
+	btcChain := tbtcpgtest.NewLocalBitcoinChain()
 	btcChain.SetEstimateSatPerVByteFee(1, 16)
 
 	redeemersOutputScripts := []bitcoin.Script{
@@ -51,7 +52,7 @@ func TestRedemptionAction_FindPendingRedemptions(t *testing.T) {
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.Title, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
 
 			// Set the average block time enforced by the scenario.
 			tbtcChain.SetAverageBlockTime(scenario.ChainParameters.AverageBlockTime)
@@ -59,7 +60,7 @@ func TestRedemptionAction_FindPendingRedemptions(t *testing.T) {
 			// Set the scenario's current block using a mock block counter.
 			// This is needed to build a proper filter for the
 			// `PastRedemptionRequestedEvents` call.
-			blockCounter := tbtcpg.NewMockBlockCounter()
+			blockCounter := tbtcpgtest.NewMockBlockCounter()
 			blockCounter.SetCurrentBlock(scenario.ChainParameters.CurrentBlock)
 			tbtcChain.SetBlockCounter(blockCounter)
 
@@ -168,8 +169,8 @@ func TestRedemptionAction_ProposeRedemption(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			tbtcChain := tbtcpg.NewLocalChain()
-			btcChain := tbtcpg.NewLocalBitcoinChain()
+			tbtcChain := tbtcpgtest.NewLocalChain()
+			btcChain := tbtcpgtest.NewLocalBitcoinChain()
 
 			btcChain.SetEstimateSatPerVByteFee(1, 25)
 
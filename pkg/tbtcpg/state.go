@@ -0,0 +1,405 @@
+package tbtcpg
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/persistenceutil"
+)
+
+// proposalStateDirectory is the name of the directory the proposal state
+// store keeps its entries in.
+const proposalStateDirectory = "proposals"
+
+// DefaultProposalStateCooldown is the default period during which work
+// included in a proposal is not proposed again, unless it is known that the
+// proposal was not executed.
+const DefaultProposalStateCooldown = 6 * time.Hour
+
+// DefaultProposalStateRetention is the default period after which items
+// whose state was not updated are pruned from the proposal state store.
+const DefaultProposalStateRetention = 7 * 24 * time.Hour
+
+// ProposedItemType represents the type of work item included in a proposal.
+type ProposedItemType string
+
+const (
+	ProposedDeposit    ProposedItemType = "deposit"
+	ProposedRedemption ProposedItemType = "redemption"
+)
+
+// ProposalOutcome represents the known outcome of a proposal that included
+// the given work item.
+type ProposalOutcome string
+
+const (
+	// OutcomePending means the item was proposed and its processing has not
+	// been confirmed on-chain yet.
+	OutcomePending ProposalOutcome = "pending"
+	// OutcomeCompleted means the item was processed on-chain.
+	OutcomeCompleted ProposalOutcome = "completed"
+	// OutcomeExpired means the item was not processed on-chain within the
+	// cooldown period and is eligible to be proposed again.
+	OutcomeExpired ProposalOutcome = "expired"
+)
+
+// ProposedItem is a single work item, i.e. a deposit or a redemption request,
+// included in a proposal executed by a wallet controlled by the node.
+type ProposedItem struct {
+	Type ProposedItemType
+	// WalletPublicKeyHash is the hex-encoded public key hash of the wallet
+	// the proposal was generated for.
+	WalletPublicKeyHash string
+	// Key is the hex-encoded identifier of the item. For deposits, it is
+	// the funding transaction hash followed by the 4-byte big-endian funding
+	// output index. For redemptions, it is the redeemer output script.
+	Key        string
+	ProposedAt time.Time
+	Outcome    ProposalOutcome
+	UpdatedAt  time.Time
+}
+
+// ProposalStateStore keeps track of deposits and redemption requests that
+// were already included in proposals executed by wallets controlled by this
+// node. The state is persisted so a restarted leader does not immediately
+// re-propose work whose processing is still being confirmed.
+type ProposalStateStore struct {
+	mutex sync.Mutex
+
+	persistence persistence.BasicHandle
+	cooldown    time.Duration
+	items       map[string]*ProposedItem
+}
+
+// NewProposalStateStore creates a new proposal state store backed by the
+// given persistence handle and loads all entries persisted so far. Entries
+// that cannot be read are logged and skipped.
+func NewProposalStateStore(
+	persistence persistence.BasicHandle,
+	cooldown time.Duration,
+) *ProposalStateStore {
+	store := &ProposalStateStore{
+		persistence: persistence,
+		cooldown:    cooldown,
+		items:       make(map[string]*ProposedItem),
+	}
+
+	store.load()
+
+	return store
+}
+
+func (pss *ProposalStateStore) load() {
+	persistenceutil.ReadAll(
+		pss.persistence,
+		func(descriptor persistence.DataDescriptor) {
+			if descriptor.Directory() != proposalStateDirectory {
+				return
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"could not read proposal state from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			item := &ProposedItem{}
+			if err := json.Unmarshal(content, item); err != nil {
+				logger.Errorf(
+					"could not unmarshal proposal state from file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				return
+			}
+
+			pss.items[descriptor.Name()] = item
+		},
+		func(err error) {
+			logger.Errorf("could not load proposal state from disk: [%v]", err)
+		},
+	)
+}
+
+// RecordProposed records the given items of the given wallet as included
+// in a proposal executed at the given time.
+func (pss *ProposalStateStore) RecordProposed(
+	itemType ProposedItemType,
+	walletPublicKeyHash [20]byte,
+	keys [][]byte,
+	proposedAt time.Time,
+) error {
+	pss.mutex.Lock()
+	defer pss.mutex.Unlock()
+
+	for _, key := range keys {
+		item := &ProposedItem{
+			Type:                itemType,
+			WalletPublicKeyHash: hex.EncodeToString(walletPublicKeyHash[:]),
+			Key:                 hex.EncodeToString(key),
+			ProposedAt:          proposedAt,
+			Outcome:             OutcomePending,
+			UpdatedAt:           proposedAt,
+		}
+
+		if err := pss.save(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetOutcome sets the outcome of the given item. It is a no-op if the item
+// is not known to the store.
+func (pss *ProposalStateStore) SetOutcome(
+	itemType ProposedItemType,
+	walletPublicKeyHash [20]byte,
+	key []byte,
+	outcome ProposalOutcome,
+	updatedAt time.Time,
+) error {
+	pss.mutex.Lock()
+	defer pss.mutex.Unlock()
+
+	item, ok := pss.items[itemName(itemType, walletPublicKeyHash, key)]
+	if !ok {
+		return nil
+	}
+
+	updatedItem := *item
+	updatedItem.Outcome = outcome
+	updatedItem.UpdatedAt = updatedAt
+
+	return pss.save(&updatedItem)
+}
+
+// IsConfirming returns true if the given item was proposed within the
+// cooldown period and its processing has not been confirmed on-chain yet.
+// Such an item should not be proposed again.
+func (pss *ProposalStateStore) IsConfirming(
+	itemType ProposedItemType,
+	walletPublicKeyHash [20]byte,
+	key []byte,
+	now time.Time,
+) bool {
+	pss.mutex.Lock()
+	defer pss.mutex.Unlock()
+
+	item, ok := pss.items[itemName(itemType, walletPublicKeyHash, key)]
+	if !ok {
+		return false
+	}
+
+	return item.Outcome == OutcomePending &&
+		now.Before(item.ProposedAt.Add(pss.cooldown))
+}
+
+// Pending returns all items of the given type and wallet whose outcome
+// is still pending.
+func (pss *ProposalStateStore) Pending(
+	itemType ProposedItemType,
+	walletPublicKeyHash [20]byte,
+) []*ProposedItem {
+	pss.mutex.Lock()
+	defer pss.mutex.Unlock()
+
+	walletPublicKeyHashHex := hex.EncodeToString(walletPublicKeyHash[:])
+
+	pending := make([]*ProposedItem, 0)
+	for _, item := range pss.items {
+		if item.Type == itemType &&
+			item.WalletPublicKeyHash == walletPublicKeyHashHex &&
+			item.Outcome == OutcomePending {
+			itemCopy := *item
+			pending = append(pending, &itemCopy)
+		}
+	}
+
+	sortProposedItems(pending)
+
+	return pending
+}
+
+// Refresh updates the outcomes of pending items of the given type and wallet.
+// Items reported as processed by isProcessedFn are marked as completed.
+// Items that are not processed and whose cooldown period elapsed are marked
+// as expired.
+func (pss *ProposalStateStore) Refresh(
+	itemType ProposedItemType,
+	walletPublicKeyHash [20]byte,
+	isProcessedFn func(key []byte) (bool, error),
+	now time.Time,
+) error {
+	for _, item := range pss.Pending(itemType, walletPublicKeyHash) {
+		key, err := hex.DecodeString(item.Key)
+		if err != nil {
+			return fmt.Errorf("cannot decode item key: [%v]", err)
+		}
+
+		processed, err := isProcessedFn(key)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot check if item [%s] was processed: [%v]",
+				item.Key,
+				err,
+			)
+		}
+
+		var outcome ProposalOutcome
+		if processed {
+			outcome = OutcomeCompleted
+		} else if !now.Before(item.ProposedAt.Add(pss.cooldown)) {
+			outcome = OutcomeExpired
+		} else {
+			continue
+		}
+
+		if err := pss.SetOutcome(
+			itemType,
+			walletPublicKeyHash,
+			key,
+			outcome,
+			now,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Items returns all items kept by the store, sorted by the proposal time.
+func (pss *ProposalStateStore) Items() []*ProposedItem {
+	pss.mutex.Lock()
+	defer pss.mutex.Unlock()
+
+	items := make([]*ProposedItem, 0, len(pss.items))
+	for _, item := range pss.items {
+		itemCopy := *item
+		items = append(items, &itemCopy)
+	}
+
+	sortProposedItems(items)
+
+	return items
+}
+
+// Prune removes items that were updated before the given time.
+func (pss *ProposalStateStore) Prune(updatedBefore time.Time) error {
+	pss.mutex.Lock()
+	defer pss.mutex.Unlock()
+
+	for name, item := range pss.items {
+		if !item.UpdatedAt.Before(updatedBefore) {
+			continue
+		}
+
+		if err := pss.persistence.Delete(proposalStateDirectory, name); err != nil {
+			return fmt.Errorf(
+				"cannot delete proposal state [%s]: [%v]",
+				name,
+				err,
+			)
+		}
+
+		delete(pss.items, name)
+	}
+
+	return nil
+}
+
+func (pss *ProposalStateStore) save(item *ProposedItem) error {
+	key, err := hex.DecodeString(item.Key)
+	if err != nil {
+		return fmt.Errorf("cannot decode item key: [%v]", err)
+	}
+
+	walletPublicKeyHashBytes, err := hex.DecodeString(item.WalletPublicKeyHash)
+	if err != nil {
+		return fmt.Errorf("cannot decode wallet public key hash: [%v]", err)
+	}
+
+	var walletPublicKeyHash [20]byte
+	copy(walletPublicKeyHash[:], walletPublicKeyHashBytes)
+
+	content, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("cannot marshal proposal state: [%v]", err)
+	}
+
+	name := itemName(item.Type, walletPublicKeyHash, key)
+
+	if err := pss.persistence.Save(
+		content,
+		proposalStateDirectory,
+		name,
+	); err != nil {
+		return fmt.Errorf("cannot save proposal state [%s]: [%v]", name, err)
+	}
+
+	pss.items[name] = item
+
+	return nil
+}
+
+func itemName(
+	itemType ProposedItemType,
+	walletPublicKeyHash [20]byte,
+	key []byte,
+) string {
+	return fmt.Sprintf(
+		"%s_%s_%s",
+		itemType,
+		hex.EncodeToString(walletPublicKeyHash[:]),
+		hex.EncodeToString(key),
+	)
+}
+
+func sortProposedItems(items []*ProposedItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ProposedAt.Equal(items[j].ProposedAt) {
+			return items[i].Key < items[j].Key
+		}
+		return items[i].ProposedAt.Before(items[j].ProposedAt)
+	})
+}
+
+// depositItemKey builds the proposal state key of the given deposit.
+func depositItemKey(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) []byte {
+	key := make([]byte, len(fundingTxHash)+4)
+	copy(key, fundingTxHash[:])
+	binary.BigEndian.PutUint32(key[len(fundingTxHash):], fundingOutputIndex)
+	return key
+}
+
+// parseDepositItemKey extracts the funding transaction hash and output
+// index from the given deposit proposal state key.
+func parseDepositItemKey(key []byte) (bitcoin.Hash, uint32, error) {
+	if len(key) != bitcoin.HashByteLength+4 {
+		return bitcoin.Hash{}, 0, fmt.Errorf(
+			"wrong deposit key length: [%v]",
+			len(key),
+		)
+	}
+
+	var fundingTxHash bitcoin.Hash
+	copy(fundingTxHash[:], key[:bitcoin.HashByteLength])
+
+	return fundingTxHash, binary.BigEndian.Uint32(key[bitcoin.HashByteLength:]), nil
+}
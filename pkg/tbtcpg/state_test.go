@@ -0,0 +1,227 @@
+package tbtcpg
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestProposalStateStore(t *testing.T) {
+	handle := newMockPersistenceHandle()
+	cooldown := 1 * time.Hour
+	now := time.Unix(1700000000, 0)
+
+	walletPublicKeyHash := [20]byte{0x01}
+	depositKey1 := depositItemKey(bitcoin.Hash{0x01}, 0)
+	depositKey2 := depositItemKey(bitcoin.Hash{0x02}, 1)
+
+	store := NewProposalStateStore(handle, cooldown)
+
+	err := store.RecordProposed(
+		ProposedDeposit,
+		walletPublicKeyHash,
+		[][]byte{depositKey1, depositKey2},
+		now,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the store to make sure the state is persisted.
+	store = NewProposalStateStore(handle, cooldown)
+
+	testutils.AssertIntsEqual(t, "items count", 2, len(store.Items()))
+	testutils.AssertBoolsEqual(
+		t,
+		"first deposit confirming",
+		true,
+		store.IsConfirming(
+			ProposedDeposit,
+			walletPublicKeyHash,
+			depositKey1,
+			now.Add(time.Minute),
+		),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"first deposit confirming as redemption",
+		false,
+		store.IsConfirming(
+			ProposedRedemption,
+			walletPublicKeyHash,
+			depositKey1,
+			now.Add(time.Minute),
+		),
+	)
+
+	// The first deposit was swept, the second was not.
+	err = store.Refresh(
+		ProposedDeposit,
+		walletPublicKeyHash,
+		func(key []byte) (bool, error) {
+			fundingTxHash, _, err := parseDepositItemKey(key)
+			if err != nil {
+				return false, err
+			}
+
+			return fundingTxHash == bitcoin.Hash{0x01}, nil
+		},
+		now.Add(2*time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outcomes := make(map[string]ProposalOutcome)
+	for _, item := range store.Items() {
+		outcomes[item.Key] = item.Outcome
+	}
+
+	depositKey1Hex := "0100000000000000000000000000000000000000000000000000000000000000" +
+		"00000000"
+	depositKey2Hex := "0200000000000000000000000000000000000000000000000000000000000000" +
+		"00000001"
+
+	testutils.AssertStringsEqual(
+		t,
+		"first deposit outcome",
+		string(OutcomeCompleted),
+		string(outcomes[depositKey1Hex]),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"second deposit outcome",
+		string(OutcomeExpired),
+		string(outcomes[depositKey2Hex]),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"second deposit confirming",
+		false,
+		store.IsConfirming(
+			ProposedDeposit,
+			walletPublicKeyHash,
+			depositKey2,
+			now.Add(2*time.Hour),
+		),
+	)
+
+	err = store.Prune(now.Add(3 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "items count", 0, len(store.Items()))
+	testutils.AssertIntsEqual(t, "persisted items count", 0, len(handle.data))
+}
+
+func TestProposalGenerator_ProposalExecuted(t *testing.T) {
+	store := NewProposalStateStore(newMockPersistenceHandle(), time.Hour)
+	generator := &ProposalGenerator{stateStore: store}
+
+	walletPublicKeyHash := [20]byte{0x01}
+	redeemerOutputScript := bitcoin.Script{0x00, 0x14, 0x01}
+
+	generator.ProposalExecuted(walletPublicKeyHash, &tbtc.NoopProposal{})
+	testutils.AssertIntsEqual(t, "items count", 0, len(store.Items()))
+
+	generator.ProposalExecuted(
+		walletPublicKeyHash,
+		&tbtc.RedemptionProposal{
+			RedeemersOutputScripts: []bitcoin.Script{redeemerOutputScript},
+		},
+	)
+
+	testutils.AssertBoolsEqual(
+		t,
+		"redemption confirming",
+		true,
+		store.IsConfirming(
+			ProposedRedemption,
+			walletPublicKeyHash,
+			redeemerOutputScript,
+			time.Now(),
+		),
+	)
+}
+
+type mockPersistenceHandle struct {
+	mutex sync.Mutex
+	data  map[string]*mockDescriptor
+}
+
+func newMockPersistenceHandle() *mockPersistenceHandle {
+	return &mockPersistenceHandle{
+		data: make(map[string]*mockDescriptor),
+	}
+}
+
+func (mph *mockPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	mph.data[directory+"/"+name] = &mockDescriptor{
+		name:      name,
+		directory: directory,
+		content:   data,
+	}
+
+	return nil
+}
+
+func (mph *mockPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	outputData := make(chan persistence.DataDescriptor, len(mph.data))
+	outputErrors := make(chan error)
+
+	for _, descriptor := range mph.data {
+		outputData <- descriptor
+	}
+
+	close(outputData)
+	close(outputErrors)
+
+	return outputData, outputErrors
+}
+
+func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	delete(mph.data, directory+"/"+name)
+
+	return nil
+}
+
+type mockDescriptor struct {
+	name      string
+	directory string
+	content   []byte
+}
+
+func (md *mockDescriptor) Name() string {
+	return md.name
+}
+
+func (md *mockDescriptor) Directory() string {
+	return md.directory
+}
+
+func (md *mockDescriptor) Content() ([]byte, error) {
+	return md.content, nil
+}
@@ -1,7 +1,9 @@
 package tbtcpg
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
@@ -12,6 +14,10 @@ import (
 
 var logger = log.Logger("keep-tbtcpg")
 
+// DefaultGenerationConcurrency is the default maximum number of wallets for
+// which proposals are generated concurrently.
+const DefaultGenerationConcurrency = 4
+
 // ProposalTask encapsulates logic used to generate an action proposal
 // of the given type.
 type ProposalTask interface {
@@ -28,26 +34,70 @@ type ProposalTask interface {
 // ProposalGenerator is a component responsible for generating coordination
 // proposals for tbtc wallets.
 type ProposalGenerator struct {
-	tasks []ProposalTask
+	tasks       []ProposalTask
+	concurrency int
+	stateStore  *ProposalStateStore
+
+	// workerSlots bounds the number of concurrent Generate calls. The
+	// coordination layer runs a separate procedure for each wallet so
+	// proposals for many wallets may be requested at the same time.
+	workerSlots chan struct{}
+}
+
+// ProposalGeneratorOption allows to customize the proposal generator.
+type ProposalGeneratorOption func(generator *ProposalGenerator)
+
+// WithGenerationConcurrency sets the maximum number of wallets for which
+// proposals are generated concurrently. Values lower than 1 are ignored.
+func WithGenerationConcurrency(concurrency int) ProposalGeneratorOption {
+	return func(generator *ProposalGenerator) {
+		if concurrency > 0 {
+			generator.concurrency = concurrency
+		}
+	}
+}
+
+// WithProposalStateStore sets the store used to track deposits and
+// redemption requests included in recently executed proposals. Work tracked
+// by the store as still being confirmed is not proposed again.
+func WithProposalStateStore(stateStore *ProposalStateStore) ProposalGeneratorOption {
+	return func(generator *ProposalGenerator) {
+		generator.stateStore = stateStore
+	}
 }
 
 // NewProposalGenerator returns a new proposal generator.
 func NewProposalGenerator(
 	chain Chain,
 	btcChain bitcoin.Chain,
+	options ...ProposalGeneratorOption,
 ) *ProposalGenerator {
-	tasks := []ProposalTask{
-		NewDepositSweepTask(chain, btcChain),
-		NewRedemptionTask(chain, btcChain),
+	generator := &ProposalGenerator{
+		concurrency: DefaultGenerationConcurrency,
+	}
+
+	for _, option := range options {
+		option(generator)
+	}
+
+	generator.workerSlots = make(chan struct{}, generator.concurrency)
+
+	depositSweepTask := NewDepositSweepTask(chain, btcChain)
+	depositSweepTask.stateStore = generator.stateStore
+
+	redemptionTask := NewRedemptionTask(chain, btcChain)
+	redemptionTask.stateStore = generator.stateStore
+
+	generator.tasks = []ProposalTask{
+		depositSweepTask,
+		redemptionTask,
 		NewHeartbeatTask(chain),
 		NewMovingFundsTask(chain, btcChain),
 		// TODO: Uncomment when moving funds support is implemented.
 		// newMovedFundsSweepTask(),
 	}
 
-	return &ProposalGenerator{
-		tasks: tasks,
-	}
+	return generator
 }
 
 // Generate generates a coordination proposal based on the given checklist
@@ -55,8 +105,12 @@ func NewProposalGenerator(
 // should be checked for the given coordination window. This function returns
 // a proposal for the first action from the checklist that is valid for the
 // given wallet's state. If none of the actions are valid, the generator
-// returns a no-op proposal.
+// returns a no-op proposal. At most the configured number of calls generate
+// proposals at the same time; the remaining ones wait for a free slot until
+// the given context is done. If no slot frees up by then, the generation
+// is skipped and an error is returned.
 func (pg *ProposalGenerator) Generate(
+	ctx context.Context,
 	request *tbtc.CoordinationProposalRequest,
 ) (tbtc.CoordinationProposal, error) {
 	walletLogger := logger.With(
@@ -66,6 +120,23 @@ func (pg *ProposalGenerator) Generate(
 		),
 	)
 
+	if pg.workerSlots != nil {
+		select {
+		case pg.workerSlots <- struct{}{}:
+			defer func() { <-pg.workerSlots }()
+		case <-ctx.Done():
+			walletLogger.Warnf(
+				"skipping proposal generation; no free generation slot "+
+					"of [%d] before the proposal was no longer needed",
+				cap(pg.workerSlots),
+			)
+			return nil, fmt.Errorf(
+				"no free proposal generation slot: [%w]",
+				ctx.Err(),
+			)
+		}
+	}
+
 	walletLogger.Info(
 		"starting proposal generation with tasks checklist [%v]",
 		request.ActionsChecklist,
@@ -115,3 +186,51 @@ func (pg *ProposalGenerator) Generate(
 
 	return &tbtc.NoopProposal{}, nil
 }
+
+// ProposalExecuted records deposits and redemption requests included in the
+// given proposal, successfully executed by the given wallet, in the proposal
+// state store. Work included in proposals that were not executed is never
+// recorded so it can be proposed again right away.
+func (pg *ProposalGenerator) ProposalExecuted(
+	walletPublicKeyHash [20]byte,
+	proposal tbtc.CoordinationProposal,
+) {
+	if pg.stateStore == nil {
+		return
+	}
+
+	var itemType ProposedItemType
+	var keys [][]byte
+
+	switch p := proposal.(type) {
+	case *tbtc.DepositSweepProposal:
+		itemType = ProposedDeposit
+		for _, depositKey := range p.DepositsKeys {
+			keys = append(keys, depositItemKey(
+				depositKey.FundingTxHash,
+				depositKey.FundingOutputIndex,
+			))
+		}
+	case *tbtc.RedemptionProposal:
+		itemType = ProposedRedemption
+		for _, script := range p.RedeemersOutputScripts {
+			keys = append(keys, script)
+		}
+	default:
+		return
+	}
+
+	if err := pg.stateStore.RecordProposed(
+		itemType,
+		walletPublicKeyHash,
+		keys,
+		time.Now(),
+	); err != nil {
+		logger.Errorf(
+			"cannot record executed [%s] proposal of wallet [0x%x]: [%v]",
+			proposal.ActionType(),
+			walletPublicKeyHash,
+			err,
+		)
+	}
+}
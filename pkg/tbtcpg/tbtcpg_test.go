@@ -1,10 +1,14 @@
 package tbtcpg
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
+	"github.com/keep-network/keep-core/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
@@ -132,6 +136,7 @@ func TestProposalGenerator_Generate(t *testing.T) {
 			}
 
 			proposal, err := generator.Generate(
+				context.Background(),
 				&tbtc.CoordinationProposalRequest{
 					WalletPublicKeyHash: walletPublicKeyHash,
 					WalletOperators:     nil,
@@ -158,6 +163,139 @@ func TestProposalGenerator_Generate(t *testing.T) {
 	}
 }
 
+func TestProposalGenerator_Generate_Concurrency(t *testing.T) {
+	task := newBlockingProposalTask()
+
+	generator := &ProposalGenerator{
+		tasks:       []ProposalTask{task},
+		workerSlots: make(chan struct{}, 2),
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, err := generator.Generate(
+				context.Background(),
+				&tbtc.CoordinationProposalRequest{
+					WalletPublicKeyHash: [20]byte{byte(i)},
+					ActionsChecklist: []tbtc.WalletActionType{
+						tbtc.ActionRedemption,
+					},
+				},
+			)
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+
+	// Wait until all slots are taken.
+	<-task.started
+	<-task.started
+	close(task.release)
+	wg.Wait()
+
+	testutils.AssertIntsEqual(
+		t,
+		"maximum concurrent tasks",
+		2,
+		task.maxRunning,
+	)
+}
+
+func TestProposalGenerator_Generate_NoFreeSlot(t *testing.T) {
+	task := newBlockingProposalTask()
+
+	generator := &ProposalGenerator{
+		tasks:       []ProposalTask{task},
+		workerSlots: make(chan struct{}, 1),
+	}
+
+	request := func(i int) *tbtc.CoordinationProposalRequest {
+		return &tbtc.CoordinationProposalRequest{
+			WalletPublicKeyHash: [20]byte{byte(i)},
+			ActionsChecklist:    []tbtc.WalletActionType{tbtc.ActionRedemption},
+		}
+	}
+
+	blockingDone := make(chan error, 1)
+	go func() {
+		_, err := generator.Generate(context.Background(), request(0))
+		blockingDone <- err
+	}()
+
+	// Wait until the only slot is taken.
+	<-task.started
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+
+	_, err := generator.Generate(ctx, request(1))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v",
+			context.Canceled,
+			err,
+		)
+	}
+
+	close(task.release)
+	if err := <-blockingDone; err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "started tasks", 0, len(task.started))
+}
+
+type blockingProposalTask struct {
+	// started receives a value every time the task starts running.
+	started chan struct{}
+	release chan struct{}
+
+	mutex      sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (bpt *blockingProposalTask) Run(
+	request *tbtc.CoordinationProposalRequest,
+) (
+	tbtc.CoordinationProposal,
+	bool,
+	error,
+) {
+	bpt.mutex.Lock()
+	bpt.running++
+	if bpt.running > bpt.maxRunning {
+		bpt.maxRunning = bpt.running
+	}
+	bpt.mutex.Unlock()
+
+	bpt.started <- struct{}{}
+	<-bpt.release
+
+	bpt.mutex.Lock()
+	bpt.running--
+	bpt.mutex.Unlock()
+
+	return nil, false, nil
+}
+
+func newBlockingProposalTask() *blockingProposalTask {
+	return &blockingProposalTask{
+		// Large enough to never block the tasks run by tests.
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (bpt *blockingProposalTask) ActionType() tbtc.WalletActionType {
+	return tbtc.ActionRedemption
+}
+
 type mockProposalTaskResult uint8
 
 const (
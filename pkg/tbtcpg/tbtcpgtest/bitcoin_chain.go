@@ -0,0 +1,436 @@
+package tbtcpgtest
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// LocalBitcoinChain is an in-memory implementation of the bitcoin.Chain
+// interface. Transactions are kept in the order they were set which allows
+// to deterministically reproduce the history of the given wallet.
+type LocalBitcoinChain struct {
+	mutex sync.Mutex
+
+	transactions              map[bitcoin.Hash]*bitcoin.Transaction
+	transactionsOrder         []bitcoin.Hash
+	transactionsConfirmations map[bitcoin.Hash]uint
+	transactionsMerkleProofs  map[bitcoin.Hash]*bitcoin.TransactionMerkleProof
+	mempool                   []*bitcoin.Transaction
+	utxos                     map[[20]byte][]*bitcoin.UnspentTransactionOutput
+	mempoolUtxos              map[[20]byte][]*bitcoin.UnspentTransactionOutput
+	blockHeaders              map[uint]*bitcoin.BlockHeader
+	coinbaseTxHashes          map[uint]bitcoin.Hash
+	latestBlockHeight         uint
+	broadcastTransactions     []*bitcoin.Transaction
+	satPerVByteFeeEstimation  map[uint32]int64
+}
+
+// NewLocalBitcoinChain creates a new, empty instance of the LocalBitcoinChain.
+func NewLocalBitcoinChain() *LocalBitcoinChain {
+	return &LocalBitcoinChain{
+		transactions:              make(map[bitcoin.Hash]*bitcoin.Transaction),
+		transactionsOrder:         make([]bitcoin.Hash, 0),
+		transactionsConfirmations: make(map[bitcoin.Hash]uint),
+		transactionsMerkleProofs:  make(map[bitcoin.Hash]*bitcoin.TransactionMerkleProof),
+		mempool:                   make([]*bitcoin.Transaction, 0),
+		utxos:                     make(map[[20]byte][]*bitcoin.UnspentTransactionOutput),
+		mempoolUtxos:              make(map[[20]byte][]*bitcoin.UnspentTransactionOutput),
+		blockHeaders:              make(map[uint]*bitcoin.BlockHeader),
+		coinbaseTxHashes:          make(map[uint]bitcoin.Hash),
+		broadcastTransactions:     make([]*bitcoin.Transaction, 0),
+		satPerVByteFeeEstimation:  make(map[uint32]int64),
+	}
+}
+
+func (lbc *LocalBitcoinChain) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	transaction, ok := lbc.transactions[transactionHash]
+	if !ok {
+		return nil, fmt.Errorf("transaction not found")
+	}
+	return transaction, nil
+}
+
+// SetTransaction sets the transaction under the given hash. Transactions
+// set for the first time are appended to the end of the chain history.
+func (lbc *LocalBitcoinChain) SetTransaction(
+	transactionHash bitcoin.Hash,
+	transaction *bitcoin.Transaction,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	if _, ok := lbc.transactions[transactionHash]; !ok {
+		lbc.transactionsOrder = append(lbc.transactionsOrder, transactionHash)
+	}
+
+	lbc.transactions[transactionHash] = transaction
+}
+
+func (lbc *LocalBitcoinChain) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	if confirmations, ok := lbc.transactionsConfirmations[transactionHash]; ok {
+		return confirmations, nil
+	}
+
+	return 0, fmt.Errorf("transaction not found")
+}
+
+func (lbc *LocalBitcoinChain) SetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+	confirmations uint,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.transactionsConfirmations[transactionHash] = confirmations
+}
+
+// BroadcastTransaction records the given transaction. Recorded transactions
+// can be obtained using BroadcastTransactions.
+func (lbc *LocalBitcoinChain) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.broadcastTransactions = append(lbc.broadcastTransactions, transaction)
+
+	return nil
+}
+
+// BroadcastTransactions returns all transactions broadcast so far, in the
+// order of broadcasting.
+func (lbc *LocalBitcoinChain) BroadcastTransactions() []*bitcoin.Transaction {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return lbc.broadcastTransactions
+}
+
+func (lbc *LocalBitcoinChain) GetLatestBlockHeight() (uint, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return lbc.latestBlockHeight, nil
+}
+
+func (lbc *LocalBitcoinChain) SetLatestBlockHeight(blockHeight uint) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.latestBlockHeight = blockHeight
+}
+
+func (lbc *LocalBitcoinChain) GetBlockHeader(
+	blockNumber uint,
+) (*bitcoin.BlockHeader, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	blockHeader, ok := lbc.blockHeaders[blockNumber]
+	if !ok {
+		return nil, fmt.Errorf("block header not found")
+	}
+
+	return blockHeader, nil
+}
+
+func (lbc *LocalBitcoinChain) SetBlockHeader(
+	blockNumber uint,
+	blockHeader *bitcoin.BlockHeader,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.blockHeaders[blockNumber] = blockHeader
+}
+
+func (lbc *LocalBitcoinChain) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	proof, ok := lbc.transactionsMerkleProofs[transactionHash]
+	if !ok || proof.BlockHeight != blockHeight {
+		return nil, fmt.Errorf("transaction merkle proof not found")
+	}
+
+	return proof, nil
+}
+
+func (lbc *LocalBitcoinChain) SetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	proof *bitcoin.TransactionMerkleProof,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.transactionsMerkleProofs[transactionHash] = proof
+}
+
+// GetTransactionsForPublicKeyHash returns at most limit of the latest
+// transactions from the chain history that have at least one P2PKH or P2WPKH
+// output locked on the given public key hash.
+func (lbc *LocalBitcoinChain) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	history := make([]*bitcoin.Transaction, 0)
+	for _, transactionHash := range lbc.transactionsOrder {
+		history = append(history, lbc.transactions[transactionHash])
+	}
+
+	matchingTransactions, err := filterTransactions(history, publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matchingTransactions) > limit {
+		return matchingTransactions[len(matchingTransactions)-limit:], nil
+	}
+
+	return matchingTransactions, nil
+}
+
+// GetTransactionsPageForPublicKeyHash returns a page of transactions from
+// the chain history that have at least one P2PKH or P2WPKH output locked on
+// the given public key hash. Each transaction of the history is considered
+// to be included in a separate block, according to the history order.
+func (lbc *LocalBitcoinChain) GetTransactionsPageForPublicKeyHash(
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	history := make([]*bitcoin.Transaction, 0)
+	for _, transactionHash := range lbc.transactionsOrder {
+		history = append(history, lbc.transactions[transactionHash])
+	}
+
+	return selectTransactionsPage(history, publicKeyHash, query)
+}
+
+func (lbc *LocalBitcoinChain) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	history := make([]*bitcoin.Transaction, 0)
+	for _, transactionHash := range lbc.transactionsOrder {
+		history = append(history, lbc.transactions[transactionHash])
+	}
+
+	matchingTransactions, err := filterTransactions(history, publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingTxHashes := make([]bitcoin.Hash, len(matchingTransactions))
+	for i, transaction := range matchingTransactions {
+		matchingTxHashes[i] = transaction.Hash()
+	}
+
+	return matchingTxHashes, nil
+}
+
+func (lbc *LocalBitcoinChain) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return filterTransactions(lbc.mempool, publicKeyHash)
+}
+
+// AddMempoolTransaction adds the given transaction to the mempool.
+func (lbc *LocalBitcoinChain) AddMempoolTransaction(
+	transaction *bitcoin.Transaction,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.mempool = append(lbc.mempool, transaction)
+}
+
+func (lbc *LocalBitcoinChain) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return lbc.utxos[publicKeyHash], nil
+}
+
+func (lbc *LocalBitcoinChain) SetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+	utxos []*bitcoin.UnspentTransactionOutput,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.utxos[publicKeyHash] = utxos
+}
+
+func (lbc *LocalBitcoinChain) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return lbc.mempoolUtxos[publicKeyHash], nil
+}
+
+func (lbc *LocalBitcoinChain) SetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+	utxos []*bitcoin.UnspentTransactionOutput,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.mempoolUtxos[publicKeyHash] = utxos
+}
+
+func (lbc *LocalBitcoinChain) EstimateSatPerVByteFee(
+	blocks uint32,
+) (int64, error) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return lbc.satPerVByteFeeEstimation[blocks], nil
+}
+
+func (lbc *LocalBitcoinChain) GetCoinbaseTxHash(blockHeight uint) (
+	bitcoin.Hash,
+	error,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	coinbaseTxHash, ok := lbc.coinbaseTxHashes[blockHeight]
+	if !ok {
+		return bitcoin.Hash{}, fmt.Errorf("coinbase transaction not found")
+	}
+
+	return coinbaseTxHash, nil
+}
+
+func (lbc *LocalBitcoinChain) SetCoinbaseTxHash(
+	blockHeight uint,
+	coinbaseTxHash bitcoin.Hash,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.coinbaseTxHashes[blockHeight] = coinbaseTxHash
+}
+
+func (lbc *LocalBitcoinChain) SetEstimateSatPerVByteFee(
+	blocks uint32,
+	fee int64,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.satPerVByteFeeEstimation[blocks] = fee
+}
+
+// filterTransactions returns transactions that have at least one P2PKH or
+// P2WPKH output locked on the given public key hash. The order of the
+// transactions is preserved.
+func filterTransactions(
+	transactions []*bitcoin.Transaction,
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingTransactions := make([]*bitcoin.Transaction, 0)
+
+	for _, transaction := range transactions {
+		for _, output := range transaction.Outputs {
+			script := output.PublicKeyScript
+			if bytes.Equal(script, p2pkh) || bytes.Equal(script, p2wpkh) {
+				matchingTransactions = append(matchingTransactions, transaction)
+				break
+			}
+		}
+	}
+
+	return matchingTransactions, nil
+}
+
+// selectTransactionsPage selects the page of transactions matching the given
+// public key hash and query from the given history. Each transaction of the
+// history is considered to be included in a separate block, according to the
+// history order.
+func selectTransactionsPage(
+	history []*bitcoin.Transaction,
+	publicKeyHash [20]byte,
+	query *bitcoin.TransactionHistoryQuery,
+) (*bitcoin.TransactionHistoryPage, error) {
+	matchingTransactions, err := filterTransactions(history, publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHeights := make(map[bitcoin.Hash]uint, len(history))
+	for i, transaction := range history {
+		blockHeights[transaction.Hash()] = uint(i + 1)
+	}
+
+	transactions := make(map[bitcoin.Hash]*bitcoin.Transaction)
+	items := make([]*bitcoin.TransactionHistoryItem, len(matchingTransactions))
+	for i, transaction := range matchingTransactions {
+		transactionHash := transaction.Hash()
+		transactions[transactionHash] = transaction
+		items[i] = &bitcoin.TransactionHistoryItem{
+			TransactionHash: transactionHash,
+			BlockHeight:     blockHeights[transactionHash],
+		}
+	}
+
+	selected, nextCursor, err := bitcoin.SelectTransactionHistoryPage(
+		items,
+		uint(len(history)),
+		query,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &bitcoin.TransactionHistoryPage{
+		Transactions: make([]*bitcoin.Transaction, len(selected)),
+		NextCursor:   nextCursor,
+	}
+	for i, item := range selected {
+		page.Transactions[i] = transactions[item.TransactionHash]
+	}
+
+	return page, nil
+}
@@ -0,0 +1,81 @@
+package tbtcpgtest
+
+import (
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestLocalBitcoinChain_GetTransactionsForPublicKeyHash(t *testing.T) {
+	publicKeyHash := [20]byte{0x01}
+	otherPublicKeyHash := [20]byte{0x02}
+
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherP2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(otherPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTransaction := func(locktime uint32, script bitcoin.Script) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Outputs: []*bitcoin.TransactionOutput{
+				{Value: 1000, PublicKeyScript: script},
+			},
+			Locktime: locktime,
+		}
+	}
+
+	transactions := []*bitcoin.Transaction{
+		newTransaction(1, p2pkh),
+		newTransaction(2, otherP2wpkh),
+		newTransaction(3, p2wpkh),
+		newTransaction(4, p2wpkh),
+	}
+
+	btcChain := NewLocalBitcoinChain()
+	for _, transaction := range transactions {
+		btcChain.SetTransaction(transaction.Hash(), transaction)
+	}
+
+	history, err := btcChain.GetTransactionsForPublicKeyHash(publicKeyHash, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "history length", 2, len(history))
+	testutils.AssertUintsEqual(
+		t,
+		"first transaction locktime",
+		3,
+		uint64(history[0].Locktime),
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"second transaction locktime",
+		4,
+		uint64(history[1].Locktime),
+	)
+
+	txHashes, err := btcChain.GetTxHashesForPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "tx hashes length", 3, len(txHashes))
+	for i, index := range []int{0, 2, 3} {
+		if txHashes[i] != transactions[index].Hash() {
+			t.Errorf("unexpected transaction hash at position [%v]", i)
+		}
+	}
+}
@@ -1,4 +1,7 @@
-package tbtcpg
+// Package tbtcpgtest provides deterministic, in-memory implementations of the
+// host and Bitcoin chains consumed by the tbtcpg package. They are meant to
+// be used to test proposal tasks without any real network connection.
+package tbtcpgtest
 
 import (
 	"bytes"
@@ -59,7 +62,9 @@ type movingFundsParameters = struct {
 	sweepTimeoutNotifierRewardMultiplier uint32
 }
 
-type movingFundsCommitmentSubmission struct {
+// MovingFundsCommitmentSubmission holds the data of a moving funds commitment
+// submitted using LocalChain.SubmitMovingFundsCommitment.
+type MovingFundsCommitmentSubmission struct {
 	WalletPublicKeyHash [20]byte
 	WalletMainUtxo      *bitcoin.UnspentTransactionOutput
 	WalletMembersIDs    []uint32
@@ -67,6 +72,9 @@ type movingFundsCommitmentSubmission struct {
 	TargetWallets       [][20]byte
 }
 
+// LocalChain is an in-memory implementation of the tbtcpg.Chain interface.
+// All the chain state returned by LocalChain must be explicitly set up by
+// the test using the corresponding setters.
 type LocalChain struct {
 	mutex sync.Mutex
 
@@ -88,10 +96,13 @@ type LocalChain struct {
 	movingFundsParameters                    movingFundsParameters
 	pastMovingFundsCommitmentSubmittedEvents map[[32]byte][]*tbtc.MovingFundsCommitmentSubmittedEvent
 	movingFundsProposalValidations           map[[32]byte]bool
-	movingFundsCommitmentSubmissions         []*movingFundsCommitmentSubmission
+	movingFundsCommitmentSubmissions         []*MovingFundsCommitmentSubmission
 	operatorIDs                              map[chain.Address]uint32
+	redemptionMaxSize                        uint16
+	depositSweepMaxSize                      uint16
 }
 
+// NewLocalChain creates a new, empty instance of the LocalChain.
 func NewLocalChain() *LocalChain {
 	return &LocalChain{
 		depositRequests:                          make(map[[32]byte]*tbtc.DepositChainRequest),
@@ -105,7 +116,7 @@ func NewLocalChain() *LocalChain {
 		heartbeatProposalValidations:             make(map[[16]byte]bool),
 		pastMovingFundsCommitmentSubmittedEvents: make(map[[32]byte][]*tbtc.MovingFundsCommitmentSubmittedEvent),
 		movingFundsProposalValidations:           make(map[[32]byte]bool),
-		movingFundsCommitmentSubmissions:         make([]*movingFundsCommitmentSubmission, 0),
+		movingFundsCommitmentSubmissions:         make([]*MovingFundsCommitmentSubmission, 0),
 		operatorIDs:                              make(map[chain.Address]uint32),
 	}
 }
@@ -471,6 +482,23 @@ func (lc *LocalChain) SetPendingRedemptionRequest(
 	lc.pendingRedemptionRequests[requestKey] = request
 }
 
+// RemovePendingRedemptionRequest removes the given pending redemption
+// request, for example to simulate its processing by the Bridge.
+func (lc *LocalChain) RemovePendingRedemptionRequest(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	requestKey := buildRedemptionRequestKey(
+		walletPublicKeyHash,
+		redeemerOutputScript,
+	)
+
+	delete(lc.pendingRedemptionRequests, requestKey)
+}
+
 func (lc *LocalChain) SetDepositParameters(
 	dustThreshold uint64,
 	treasuryFeeDivisor uint64,
@@ -842,7 +870,17 @@ func buildRedemptionProposalValidationKey(
 }
 
 func (lc *LocalChain) GetRedemptionMaxSize() (uint16, error) {
-	panic("unsupported")
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.redemptionMaxSize, nil
+}
+
+func (lc *LocalChain) SetRedemptionMaxSize(redemptionMaxSize uint16) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.redemptionMaxSize = redemptionMaxSize
 }
 
 func (lc *LocalChain) GetRedemptionRequestMinAge() (uint32, error) {
@@ -860,7 +898,17 @@ func (lc *LocalChain) SetRedemptionRequestMinAge(redemptionRequestMinAge uint32)
 }
 
 func (lc *LocalChain) GetDepositSweepMaxSize() (uint16, error) {
-	panic("unsupported")
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.depositSweepMaxSize, nil
+}
+
+func (lc *LocalChain) SetDepositSweepMaxSize(depositSweepMaxSize uint16) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.depositSweepMaxSize = depositSweepMaxSize
 }
 
 func (lc *LocalChain) BlockCounter() (chain.BlockCounter, error) {
@@ -929,7 +977,6 @@ func (lc *LocalChain) GetWallet(walletPublicKeyHash [20]byte) (
 
 	data, ok := lc.walletChainData[walletPublicKeyHash]
 	if !ok {
-		fmt.Println("Not found")
 		return nil, fmt.Errorf("wallet chain data not found")
 	}
 
@@ -946,6 +993,25 @@ func (lc *LocalChain) SetWallet(
 	lc.walletChainData[walletPublicKeyHash] = data
 }
 
+// SetWalletState changes the state of the wallet set previously using
+// SetWallet. It returns an error if the wallet is not known.
+func (lc *LocalChain) SetWalletState(
+	walletPublicKeyHash [20]byte,
+	state tbtc.WalletState,
+) error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	data, ok := lc.walletChainData[walletPublicKeyHash]
+	if !ok {
+		return fmt.Errorf("wallet chain data not found")
+	}
+
+	data.State = state
+
+	return nil
+}
+
 func (lc *LocalChain) GetWalletParameters() (
 	creationPeriod uint32,
 	creationMinBtcBalance uint64,
@@ -992,12 +1058,37 @@ func (lc *LocalChain) SetWalletParameters(
 	}
 }
 
+// GetLiveWalletsCount returns the number of wallets set using SetWallet
+// that are in the Live state.
 func (lc *LocalChain) GetLiveWalletsCount() (uint32, error) {
-	panic("unsupported")
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	count := uint32(0)
+	for _, data := range lc.walletChainData {
+		if data.State == tbtc.StateLive {
+			count++
+		}
+	}
+
+	return count, nil
 }
 
 func (lc *LocalChain) ComputeMainUtxoHash(mainUtxo *bitcoin.UnspentTransactionOutput) [32]byte {
-	panic("unsupported")
+	outputIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(outputIndexBytes, mainUtxo.Outpoint.OutputIndex)
+
+	valueBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(valueBytes, uint64(mainUtxo.Value))
+
+	return crypto.Keccak256Hash(
+		append(
+			append(
+				mainUtxo.Outpoint.TransactionHash[:],
+				outputIndexBytes...,
+			), valueBytes...,
+		),
+	)
 }
 
 func (lc *LocalChain) ComputeMovingFundsCommitmentHash(targetWallets [][20]byte) [32]byte {
@@ -1068,7 +1159,7 @@ func (lc *LocalChain) SubmitMovingFundsCommitment(
 
 	lc.movingFundsCommitmentSubmissions = append(
 		lc.movingFundsCommitmentSubmissions,
-		&movingFundsCommitmentSubmission{
+		&MovingFundsCommitmentSubmission{
 			WalletPublicKeyHash: walletPublicKeyHash,
 			WalletMainUtxo:      &walletMainUtxo,
 			WalletMembersIDs:    walletMembersIDs,
@@ -1080,18 +1171,21 @@ func (lc *LocalChain) SubmitMovingFundsCommitment(
 	return nil
 }
 
-func (lc *LocalChain) GetMovingFundsSubmissions() []*movingFundsCommitmentSubmission {
+func (lc *LocalChain) GetMovingFundsSubmissions() []*MovingFundsCommitmentSubmission {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
 
 	return lc.movingFundsCommitmentSubmissions
 }
 
+// MockBlockCounter is a chain.BlockCounter implementation whose current
+// block is controlled by the test.
 type MockBlockCounter struct {
 	mutex        sync.Mutex
 	currentBlock uint64
 }
 
+// NewMockBlockCounter creates a new MockBlockCounter set to block 0.
 func NewMockBlockCounter() *MockBlockCounter {
 	return &MockBlockCounter{}
 }
@@ -3,11 +3,15 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/crypto/ephemeral"
+	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 )
 
@@ -223,3 +227,169 @@ func AggregateTssMessages(
 
 	return broadcastPayload, peersPayload, nil
 }
+
+// Evidence is a proof of misbehavior of a group member observed during the
+// execution of a tECDSA protocol.
+type Evidence struct {
+	// SessionID is the identifier of the protocol session the misbehavior
+	// was observed in.
+	SessionID string
+	// ReporterMemberIndex is the index of the member that observed the
+	// misbehavior.
+	ReporterMemberIndex group.MemberIndex
+	// AccusedMemberIndex is the index of the misbehaving member.
+	AccusedMemberIndex group.MemberIndex
+	// Reason describes the observed misbehavior.
+	Reason string
+	// MessageType is the type of the message proving the misbehavior.
+	MessageType string
+	// Message is the marshaled message sent by the accused member that
+	// proves the misbehavior.
+	Message []byte
+	// SignedEnvelope is the marshaled network envelope carrying the message,
+	// signed by the accused member. It proves the accused member sent the
+	// message. Nil if the envelope is not available.
+	SignedEnvelope []byte
+}
+
+// EvidenceRecorder records evidence of group members misbehavior.
+// Implementations must be safe for concurrent use.
+type EvidenceRecorder interface {
+	// RecordEvidence records the given evidence.
+	RecordEvidence(evidence *Evidence)
+}
+
+// WithSignedEnvelopes returns a recorder attaching sender-signed envelopes
+// to the evidence recorded with the given recorder. Envelopes of messages
+// received from the given channel are kept until the context is done. Nil
+// is returned if the given recorder is nil.
+func WithSignedEnvelopes(
+	ctx context.Context,
+	recorder EvidenceRecorder,
+	channel net.BroadcastChannel,
+) EvidenceRecorder {
+	if recorder == nil {
+		return nil
+	}
+
+	ser := &signedEnvelopeRecorder{
+		EvidenceRecorder: recorder,
+		messages:         make(map[EvidenceMessage]net.SignedMessage),
+	}
+
+	channel.Recv(ctx, func(message net.Message) {
+		signedMessage, ok := message.(net.SignedMessage)
+		if !ok {
+			return
+		}
+
+		// All evidence messages have pointer receivers so the payload
+		// identifies the received message.
+		payload, ok := message.Payload().(EvidenceMessage)
+		if !ok {
+			return
+		}
+
+		ser.mutex.Lock()
+		ser.messages[payload] = signedMessage
+		ser.mutex.Unlock()
+	})
+
+	go func() {
+		<-ctx.Done()
+
+		ser.mutex.Lock()
+		ser.messages = make(map[EvidenceMessage]net.SignedMessage)
+		ser.mutex.Unlock()
+	}()
+
+	return ser
+}
+
+// signedEnvelopeRecorder is an EvidenceRecorder keeping received messages
+// along with envelopes signed by their senders.
+type signedEnvelopeRecorder struct {
+	EvidenceRecorder
+
+	mutex    sync.Mutex
+	messages map[EvidenceMessage]net.SignedMessage
+}
+
+// signedEnvelope returns the envelope signed by the sender of the given
+// message. Returns nil if the message was not received with a signed
+// envelope.
+func (ser *signedEnvelopeRecorder) signedEnvelope(
+	message EvidenceMessage,
+) ([]byte, error) {
+	ser.mutex.Lock()
+	signedMessage, ok := ser.messages[message]
+	ser.mutex.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return signedMessage.SignedEnvelope()
+}
+
+// EvidenceMessage is a protocol message that can be recorded as evidence of
+// its sender's misbehavior.
+type EvidenceMessage interface {
+	SenderID() group.MemberIndex
+	Type() string
+	Marshal() ([]byte, error)
+}
+
+// RecordEvidence records the given message as evidence of misbehavior of its
+// sender using the given recorder. Nothing is recorded if the recorder is
+// nil. Problems with marshaling the message are logged as the evidence is
+// gathered on a best-effort basis and should not affect the protocol.
+func RecordEvidence(
+	logger log.StandardLogger,
+	recorder EvidenceRecorder,
+	sessionID string,
+	reporterMemberIndex group.MemberIndex,
+	message EvidenceMessage,
+	reason string,
+) {
+	if recorder == nil {
+		return
+	}
+
+	messageBytes, err := message.Marshal()
+	if err != nil {
+		logger.Errorf(
+			"[member:%v] cannot marshal evidence message from member "+
+				"[%v]: [%v]",
+			reporterMemberIndex,
+			message.SenderID(),
+			err,
+		)
+		return
+	}
+
+	var signedEnvelope []byte
+	if ser, ok := recorder.(*signedEnvelopeRecorder); ok {
+		signedEnvelope, err = ser.signedEnvelope(message)
+		if err != nil {
+			// The evidence is still valuable without the envelope.
+			logger.Errorf(
+				"[member:%v] cannot marshal signed envelope of evidence "+
+					"message from member [%v]: [%v]",
+				reporterMemberIndex,
+				message.SenderID(),
+				err,
+			)
+		}
+	}
+
+	recorder.RecordEvidence(&Evidence{
+		SessionID:           sessionID,
+		ReporterMemberIndex: reporterMemberIndex,
+		AccusedMemberIndex:  message.SenderID(),
+		Reason:              reason,
+		MessageType:         message.Type(),
+		Message:             messageBytes,
+		SignedEnvelope:      signedEnvelope,
+	})
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/keep-network/keep-core/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/crypto/ephemeral"
+	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 )
 
@@ -431,3 +433,161 @@ func (mic *mockIdentityConverter) TssPartyIDToMemberIndex(
 ) group.MemberIndex {
 	return group.MemberIndex(partyID.KeyInt().Int64())
 }
+
+func TestRecordEvidence(t *testing.T) {
+	recorder := &mockEvidenceRecorder{}
+
+	RecordEvidence(
+		&testutils.MockLogger{},
+		recorder,
+		"session-1",
+		group.MemberIndex(1),
+		&mockEvidenceMessage{senderID: 2, payload: []byte{0x01}},
+		"invalid message",
+	)
+
+	// Nothing should be recorded if the message cannot be marshaled.
+	RecordEvidence(
+		&testutils.MockLogger{},
+		recorder,
+		"session-1",
+		group.MemberIndex(1),
+		&mockEvidenceMessage{senderID: 3, marshalErr: fmt.Errorf("oops")},
+		"invalid message",
+	)
+
+	// Nothing should be recorded and no panic should occur if there is
+	// no recorder.
+	RecordEvidence(
+		&testutils.MockLogger{},
+		nil,
+		"session-1",
+		group.MemberIndex(1),
+		&mockEvidenceMessage{senderID: 4, payload: []byte{0x02}},
+		"invalid message",
+	)
+
+	expectedEvidence := []*Evidence{
+		{
+			SessionID:           "session-1",
+			ReporterMemberIndex: 1,
+			AccusedMemberIndex:  2,
+			Reason:              "invalid message",
+			MessageType:         "common/mock_evidence_message",
+			Message:             []byte{0x01},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedEvidence, recorder.evidence) {
+		t.Errorf(
+			"unexpected evidence\nexpected: [%+v]\nactual:   [%+v]",
+			expectedEvidence,
+			recorder.evidence,
+		)
+	}
+}
+
+type mockEvidenceRecorder struct {
+	evidence []*Evidence
+}
+
+func (mer *mockEvidenceRecorder) RecordEvidence(evidence *Evidence) {
+	mer.evidence = append(mer.evidence, evidence)
+}
+
+type mockEvidenceMessage struct {
+	senderID   group.MemberIndex
+	payload    []byte
+	marshalErr error
+}
+
+func (mem *mockEvidenceMessage) SenderID() group.MemberIndex {
+	return mem.senderID
+}
+
+func (mem *mockEvidenceMessage) Type() string {
+	return "common/mock_evidence_message"
+}
+
+func (mem *mockEvidenceMessage) Marshal() ([]byte, error) {
+	return mem.payload, mem.marshalErr
+}
+
+func TestRecordEvidence_SignedEnvelope(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	recorder := &mockEvidenceRecorder{}
+	channel := &mockBroadcastChannel{}
+
+	envelopeRecorder := WithSignedEnvelopes(ctx, recorder, channel)
+
+	signedMessage := &mockEvidenceMessage{senderID: 2, payload: []byte{0x01}}
+	unsignedMessage := &mockEvidenceMessage{senderID: 3, payload: []byte{0x02}}
+
+	channel.handler(&mockSignedMessage{
+		payload:  signedMessage,
+		envelope: []byte{0xAA},
+	})
+
+	RecordEvidence(
+		&testutils.MockLogger{},
+		envelopeRecorder,
+		"session-1",
+		group.MemberIndex(1),
+		signedMessage,
+		"invalid message",
+	)
+	RecordEvidence(
+		&testutils.MockLogger{},
+		envelopeRecorder,
+		"session-1",
+		group.MemberIndex(1),
+		unsignedMessage,
+		"invalid message",
+	)
+
+	testutils.AssertIntsEqual(t, "evidence count", 2, len(recorder.evidence))
+	testutils.AssertBytesEqual(
+		t,
+		[]byte{0xAA},
+		recorder.evidence[0].SignedEnvelope,
+	)
+	if recorder.evidence[1].SignedEnvelope != nil {
+		t.Errorf(
+			"unexpected signed envelope: [%x]",
+			recorder.evidence[1].SignedEnvelope,
+		)
+	}
+
+	// Nil should be returned if there is no recorder.
+	if WithSignedEnvelopes(ctx, nil, channel) != nil {
+		t.Error("expected nil recorder")
+	}
+}
+
+type mockBroadcastChannel struct {
+	net.BroadcastChannel
+	handler func(m net.Message)
+}
+
+func (mbc *mockBroadcastChannel) Recv(
+	ctx context.Context,
+	handler func(m net.Message),
+) {
+	mbc.handler = handler
+}
+
+type mockSignedMessage struct {
+	net.Message
+	payload  interface{}
+	envelope []byte
+}
+
+func (msm *mockSignedMessage) Payload() interface{} {
+	return msm.payload
+}
+
+func (msm *mockSignedMessage) SignedEnvelope() ([]byte, error) {
+	return msm.envelope, nil
+}
@@ -13,8 +13,15 @@ import (
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/protocol/state"
+	"github.com/keep-network/keep-core/pkg/tecdsa/common"
 )
 
+// preParamsWaitTimeout is the maximum time a member waits for the
+// pre-parameters pool to deliver pre-parameters when the pool is empty.
+// The wait allows the member to join the key generation if the pool is
+// being refilled at the moment instead of failing immediately.
+const preParamsWaitTimeout = 30 * time.Second
+
 // Executor represents an ECDSA distributed key generation process executor.
 type Executor struct {
 	tssPreParamsPool         *tssPreParamsPool
@@ -69,9 +76,30 @@ func (e *Executor) Execute(
 	excludedMembersIndexes []group.MemberIndex,
 	channel net.BroadcastChannel,
 	membershipValidator *group.MembershipValidator,
+	evidenceRecorder common.EvidenceRecorder,
 ) (*Result, error) {
 	logger.Debugf("[member:%v] initializing member", memberIndex)
 
+	// Keep envelopes signed by senders of the received messages so they
+	// can be recorded along with the evidence of misbehavior.
+	evidenceRecorder = common.WithSignedEnvelopes(
+		ctx,
+		evidenceRecorder,
+		channel,
+	)
+
+	preParamsFn := func() (*PreParams, error) {
+		waitCtx, cancelWaitCtx := context.WithTimeout(ctx, preParamsWaitTimeout)
+		defer cancelWaitCtx()
+
+		// Active key generation may take pre-parameters reserved for
+		// high-priority consumers.
+		return e.tssPreParamsPool.GetWithPriority(
+			waitCtx,
+			generator.HighPriority,
+		)
+	}
+
 	member := newMember(
 		logger,
 		seed,
@@ -80,8 +108,9 @@ func (e *Executor) Execute(
 		dishonestThreshold,
 		membershipValidator,
 		sessionID,
-		e.tssPreParamsPool.GetNow,
+		preParamsFn,
 		e.keyGenerationConcurrency,
+		evidenceRecorder,
 	)
 
 	// Mark excluded members as disqualified in order to not exchange messages
@@ -118,6 +147,44 @@ func (e *Executor) PreParamsCount() int {
 	return e.tssPreParamsPool.ParametersCount()
 }
 
+// PreParamsPoolTargetSize returns the current target size of the DKG
+// pre-parameters pool.
+func (e *Executor) PreParamsPoolTargetSize() int {
+	return e.tssPreParamsPool.TargetSize()
+}
+
+// SetPreParamsPoolTargetSize changes the target size of the DKG
+// pre-parameters pool. The pre-parameters generation grows or shrinks
+// accordingly.
+func (e *Executor) SetPreParamsPoolTargetSize(targetSize int) error {
+	return e.tssPreParamsPool.SetTargetSize(targetSize)
+}
+
+// PreParamsPoolStats returns the current statistics of the DKG
+// pre-parameters pool.
+func (e *Executor) PreParamsPoolStats() *PreParamsPoolStats {
+	return e.tssPreParamsPool.Stats()
+}
+
+// PreParamsPoolMetrics returns the current metrics of the DKG
+// pre-parameters pool.
+func (e *Executor) PreParamsPoolMetrics() *generator.PoolMetrics {
+	return e.tssPreParamsPool.Metrics()
+}
+
+// FlushPreParams saves all generated DKG pre-parameters buffered by the
+// pool. It should be called before the client shuts down.
+func (e *Executor) FlushPreParams() error {
+	return e.tssPreParamsPool.Flush()
+}
+
+// PreParamsStorageReport returns the report of reading the persisted DKG
+// pre-parameters on the executor start. The report lists entries discarded
+// as corrupted or duplicated.
+func (e *Executor) PreParamsStorageReport() *PreParamsStorageReport {
+	return e.tssPreParamsPool.StorageReport()
+}
+
 // SignedResult represents information pertaining to the process of signing
 // a DKG result: the public key used during signing, the resulting signature and
 // the hash of the DKG result that was used during signing.
@@ -162,7 +229,14 @@ func Publish(
 	resultSigner ResultSigner,
 	resultSubmitter ResultSubmitter,
 	result *Result,
+	evidenceRecorder common.EvidenceRecorder,
 ) error {
+	evidenceRecorder = common.WithSignedEnvelopes(
+		ctx,
+		evidenceRecorder,
+		channel,
+	)
+
 	initialState := &resultSigningState{
 		BaseAsyncState:  state.NewBaseAsyncState(),
 		channel:         channel,
@@ -174,6 +248,7 @@ func Publish(
 			result.Group,
 			membershipValidator,
 			sessionID,
+			evidenceRecorder,
 		),
 		result: result,
 	}
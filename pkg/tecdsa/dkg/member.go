@@ -32,6 +32,8 @@ type member struct {
 	keyGenerationConcurrency int
 	// Instance of the member identity converter.
 	identityConverter *identityConverter
+	// Recorder of evidence of other members misbehavior.
+	evidenceRecorder common.EvidenceRecorder
 }
 
 // newMember creates a new member in an initial state
@@ -45,6 +47,7 @@ func newMember(
 	sessionID string,
 	preParamsFn func() (*PreParams, error),
 	keyGenerationConcurrency int,
+	evidenceRecorder common.EvidenceRecorder,
 ) *member {
 	return &member{
 		logger:                   logger,
@@ -55,6 +58,7 @@ func newMember(
 		preParamsFn:              preParamsFn,
 		keyGenerationConcurrency: keyGenerationConcurrency,
 		identityConverter:        &identityConverter{seed: seed},
+		evidenceRecorder:         evidenceRecorder,
 	}
 }
 
@@ -79,6 +83,19 @@ func (m *member) shouldAcceptMessage(
 	return !isMessageFromSelf && isSenderValid && isSenderAccepted
 }
 
+// recordEvidence records the given message as evidence of misbehavior of
+// its sender.
+func (m *member) recordEvidence(message common.EvidenceMessage, reason string) {
+	common.RecordEvidence(
+		m.logger,
+		m.evidenceRecorder,
+		m.sessionID,
+		m.id,
+		message,
+		reason,
+	)
+}
+
 // initializeEphemeralKeysGeneration performs a transition of a member state
 // from the initial state to the first phase of the protocol.
 func (m *member) initializeEphemeralKeysGeneration() *ephemeralKeyPairGeneratingMember {
@@ -245,6 +262,8 @@ type signingMember struct {
 	preferredDKGResultHash ResultSignatureHash
 	// Signature over preferredDKGResultHash calculated by the member.
 	selfDKGResultSignature []byte
+	// Recorder of evidence of other members misbehavior.
+	evidenceRecorder common.EvidenceRecorder
 }
 
 // newSigningMember creates a new signingMember in the initial state.
@@ -254,6 +273,7 @@ func newSigningMember(
 	group *group.Group,
 	membershipValidator *group.MembershipValidator,
 	sessionID string,
+	evidenceRecorder common.EvidenceRecorder,
 ) *signingMember {
 	return &signingMember{
 		logger:              logger,
@@ -261,6 +281,7 @@ func newSigningMember(
 		group:               group,
 		membershipValidator: membershipValidator,
 		sessionID:           sessionID,
+		evidenceRecorder:    evidenceRecorder,
 	}
 }
 
@@ -280,6 +301,22 @@ func (sm *signingMember) shouldAcceptMessage(
 	return !isMessageFromSelf && isSenderValid && isSenderAccepted
 }
 
+// recordEvidence records the given message as evidence of misbehavior of
+// its sender.
+func (sm *signingMember) recordEvidence(
+	message common.EvidenceMessage,
+	reason string,
+) {
+	common.RecordEvidence(
+		sm.logger,
+		sm.evidenceRecorder,
+		sm.sessionID,
+		sm.memberIndex,
+		message,
+		reason,
+	)
+}
+
 // initializeSubmittingMember performs a transition of a member state to the
 // next phase of the protocol.
 func (sm *signingMember) initializeSubmittingMember() *submittingMember {
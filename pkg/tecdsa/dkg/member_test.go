@@ -97,6 +97,7 @@ func TestShouldAcceptMessage(t *testing.T) {
 					}, nil
 				},
 				1,
+				nil,
 			)
 
 			filter := member.inactiveMemberFilter()
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"sort"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/keep-network/keep-common/pkg/persistence"
 	"github.com/keep-network/keep-core/pkg/generator"
+	"github.com/keep-network/keep-core/pkg/storage"
 )
 
 // PreParams represents tECDSA DKG pre-parameters that were not yet consumed
@@ -31,12 +33,109 @@ func newPreParams(data *keygen.LocalPreParams) *PreParams {
 	return &PreParams{data, time.Now().UTC()}
 }
 
+// PreParamsPoolStats holds statistics of the pre-parameters pool.
+type PreParamsPoolStats struct {
+	// Count is the current number of pre-parameters in the pool.
+	Count int
+	// TargetCount is the number of pre-parameters the pool is filled up to.
+	TargetCount int
+	// GeneratedCount is the number of pre-parameters generated since the
+	// pool was created.
+	GeneratedCount uint64
+	// FailuresCount is the number of failed generations since the pool
+	// was created. Generations interrupted by the pool or timed out are not
+	// counted.
+	FailuresCount uint64
+	// TimeoutsCount is the number of generations timed out since the pool
+	// was created.
+	TimeoutsCount uint64
+	// AverageGenerationDuration is the average duration of a successful
+	// generation. It is zero if nothing was generated yet.
+	AverageGenerationDuration time.Duration
+	// GenerationDelay is the delay preserved between subsequent generations.
+	GenerationDelay time.Duration
+}
+
+// GenerationRate returns the number of pre-parameters generated per hour,
+// estimated based on the average generation duration and the delay between
+// generations. It is zero if nothing was generated yet.
+func (ppps *PreParamsPoolStats) GenerationRate() float64 {
+	if ppps.AverageGenerationDuration == 0 {
+		return 0
+	}
+
+	cycle := ppps.AverageGenerationDuration + ppps.GenerationDelay
+
+	return float64(time.Hour) / float64(cycle)
+}
+
+// RefillEstimate returns the estimated time needed to fill the pool up to
+// the target count, assuming pre-parameters are generated one after another
+// at the current generation rate. It is zero if the pool is full or nothing
+// was generated yet.
+func (ppps *PreParamsPoolStats) RefillEstimate() time.Duration {
+	if ppps.Count >= ppps.TargetCount ||
+		ppps.AverageGenerationDuration == 0 {
+		return 0
+	}
+
+	missing := time.Duration(ppps.TargetCount - ppps.Count)
+	cycle := ppps.AverageGenerationDuration + ppps.GenerationDelay
+
+	return missing * cycle
+}
+
+// preParamsGenerationStats tracks outcomes of pre-parameters generations.
+type preParamsGenerationStats struct {
+	mutex sync.Mutex
+
+	generatedCount          uint64
+	failuresCount           uint64
+	timeoutsCount           uint64
+	totalGenerationDuration time.Duration
+}
+
+func (ppgs *preParamsGenerationStats) recordGenerated(duration time.Duration) {
+	ppgs.mutex.Lock()
+	defer ppgs.mutex.Unlock()
+
+	ppgs.generatedCount++
+	ppgs.totalGenerationDuration += duration
+}
+
+func (ppgs *preParamsGenerationStats) recordFailure() {
+	ppgs.mutex.Lock()
+	defer ppgs.mutex.Unlock()
+
+	ppgs.failuresCount++
+}
+
+func (ppgs *preParamsGenerationStats) recordTimeout() {
+	ppgs.mutex.Lock()
+	defer ppgs.mutex.Unlock()
+
+	ppgs.timeoutsCount++
+}
+
+const (
+	// preParamsPersistenceBatchSize is the number of generated pre-parameters
+	// saved to the storage at once.
+	preParamsPersistenceBatchSize = 4
+	// preParamsPersistenceFlushInterval is the maximum time generated
+	// pre-parameters are buffered before they are saved to the storage.
+	preParamsPersistenceFlushInterval = 5 * time.Minute
+)
+
 // tssPreParamsPool is a pool holding TSS pre parameters. It autogenerates
 // entries up to the pool size. When an entry is pulled from the pool it
 // will generate a new entry.
 type tssPreParamsPool struct {
 	*generator.ParameterPool[PreParams]
 	logger log.StandardLogger
+
+	generationDelay time.Duration
+	stats           *preParamsGenerationStats
+	storage         *preParamsStorage
 }
 
 // newTssPreParamsPool initializes a new TSS pre-parameters pool.
@@ -51,41 +150,66 @@ func newTssPreParamsPool(
 ) *tssPreParamsPool {
 	logger.Infof(
 		"TSS pre-parameters target pool size is [%d], generation timeout is [%s] "+
-			"generation delay is [%v], and concurrency level is [%d]",
+			"generation delay is [%v], and maximum concurrency level is [%d]",
 		poolSize,
 		generationTimeout,
 		generationDelay,
 		generationConcurrency,
 	)
 
-	newPreParamsFn := func(ctx context.Context) *PreParams {
+	stats := &preParamsGenerationStats{}
+
+	newPreParamsFn := func(ctx context.Context) (*PreParams, error) {
 		timingOutCtx, cancel := context.WithTimeout(ctx, generationTimeout)
 		defer cancel()
 
+		start := time.Now()
+
+		// The concurrency level adapts to the CPU capacity left idle by other
+		// processes so the generation does not compete with them.
+		concurrency := scheduler.Concurrency(generationConcurrency)
+
+		logger.Debugf(
+			"generating TSS pre-params with concurrency level [%d]",
+			concurrency,
+		)
+
 		preParams, err := keygen.GeneratePreParamsWithContext(
 			timingOutCtx,
-			generationConcurrency,
+			concurrency,
 		)
 		// tss-lib returns generic errors saying "timeout or error while ...".
 		// There are three possibilities:
 		// 1. Pool canceled the parent `ctx`. This is normal and we should not
 		//    log anything in this case.
 		// 2. `timingOutCtx` timed out. It means the machine is not fast enough
-		//    or that it was just unlucky. We should log a warning.
-		// 3. There is some error from tss-lib generator. We log it as a warning
-		//    because we'll re-attempt to generate parameters again.
-		if err != nil && ctx.Err() == nil {
-			logger.Warnf("failed to generate TSS pre-params: [%v]", err)
+		//    or that it was just unlucky. The pool logs a warning and
+		//    re-attempts to generate parameters right away.
+		// 3. There is some error from tss-lib generator. The pool logs it
+		//    and re-attempts to generate parameters after a backoff.
+		if err != nil && ctx.Err() == nil && timingOutCtx.Err() != nil {
+			stats.recordTimeout()
+
+			return nil, fmt.Errorf(
+				"TSS pre-params generation timed out: [%w]",
+				timingOutCtx.Err(),
+			)
 		}
 
-		// If the context is done, GeneratePreParamsWithContext that got
-		// interrupted returns nil result. We do not want to wrap nil inside
-		// PreParams structure, so we return nil here.
-		if preParams == nil {
-			return nil
+		if err != nil {
+			if ctx.Err() == nil {
+				stats.recordFailure()
+			}
+
+			return nil, fmt.Errorf(
+				"failed to generate TSS pre-params: [%w]",
+				err,
+			)
 		}
 
-		return newPreParams(preParams)
+		stats.recordGenerated(time.Since(start))
+
+		return newPreParams(preParams), nil
 	}
 
 	tssPreParamsPersistance := newPreParamsStorage(persistence, logger)
@@ -98,15 +222,100 @@ func newTssPreParamsPool(
 			poolSize,
 			newPreParamsFn,
 			generationDelay,
+			generator.WithPoolName("tecdsa-dkg-pre-params"),
+			generator.WithBatchedPersistence(
+				preParamsPersistenceBatchSize,
+				preParamsPersistenceFlushInterval,
+			),
 		),
 		logger,
+		generationDelay,
+		stats,
+		&tssPreParamsPersistance,
 	}
 }
 
+// Stats returns the current statistics of the pool.
+func (tppp *tssPreParamsPool) Stats() *PreParamsPoolStats {
+	tppp.stats.mutex.Lock()
+	defer tppp.stats.mutex.Unlock()
+
+	var averageGenerationDuration time.Duration
+	if tppp.stats.generatedCount > 0 {
+		averageGenerationDuration = tppp.stats.totalGenerationDuration /
+			time.Duration(tppp.stats.generatedCount)
+	}
+
+	return &PreParamsPoolStats{
+		Count:                     tppp.ParametersCount(),
+		TargetCount:               tppp.TargetSize(),
+		GeneratedCount:            tppp.stats.generatedCount,
+		FailuresCount:             tppp.stats.failuresCount,
+		TimeoutsCount:             tppp.stats.timeoutsCount,
+		AverageGenerationDuration: averageGenerationDuration,
+		GenerationDelay:           tppp.generationDelay,
+	}
+}
+
+// StorageReport returns the report of reading the persisted pre-parameters
+// on the pool start, including entries discarded as corrupted or duplicated.
+func (tppp *tssPreParamsPool) StorageReport() *PreParamsStorageReport {
+	return tppp.storage.Report()
+}
+
 const (
-	dirName = "preparams"
+	// PreParamsDirName is the name of the directory of the work persistence
+	// PreParams are stored in.
+	PreParamsDirName = "preparams"
+	// quarantineDirName is the name of the directory corrupted PreParams are
+	// moved to. Entries of the directory are never read by the storage.
+	quarantineDirName = PreParamsDirName + storage.QuarantineDirSuffix
 )
 
+// PreParamsStorageReport describes the outcome of reading the persisted
+// pre-parameters on the pool start.
+type PreParamsStorageReport struct {
+	// Loaded is the number of pre-parameters read from the storage.
+	Loaded int `json:"loaded"`
+	// Discarded holds entries of the storage that were not loaded.
+	Discarded []*DiscardedPreParams `json:"discarded"`
+}
+
+// DiscardedPreParams describes a persisted entry that was not loaded from
+// the storage because it was corrupted or duplicated.
+type DiscardedPreParams struct {
+	// ID is the name of the file holding the entry.
+	ID string `json:"id"`
+	// Reason describes why the entry was discarded.
+	Reason string `json:"reason"`
+	// Quarantined is true if the entry was moved or copied to the quarantine
+	// directory.
+	Quarantined bool `json:"quarantined"`
+	// Removed is true if the entry was deleted from the storage directory.
+	// Entries that could not be removed are discarded again on the next read.
+	Removed bool `json:"removed"`
+}
+
+// String returns a human-readable description of the discarded entry.
+func (dpp *DiscardedPreParams) String() string {
+	return fmt.Sprintf(
+		"%s (%s, quarantined: %t, removed: %t)",
+		dpp.ID,
+		dpp.Reason,
+		dpp.Quarantined,
+		dpp.Removed,
+	)
+}
+
+// corruptedPreParams is a persisted entry that could not be read,
+// unmarshaled, or validated. The content is nil if the entry could not be
+// read.
+type corruptedPreParams struct {
+	id      string
+	content []byte
+	reason  string
+}
+
 // PersistedPreParams is an alias for Persisted PreParams used in generator.Persistence
 // interface implementation.
 type PersistedPreParams = generator.Persisted[PreParams]
@@ -118,6 +327,8 @@ type preParamsStorage struct {
 
 	persistence persistence.BasicHandle
 	logger      log.StandardLogger
+	// report is the outcome of the last ReadAll call.
+	report *PreParamsStorageReport
 }
 
 func newPreParamsStorage(
@@ -135,6 +346,47 @@ func (p *preParamsStorage) Save(pp *PreParams) (*PersistedPreParams, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
+	return p.save(pp)
+}
+
+// SaveBatch saves provided PreParams to the storage at once. If any of them
+// cannot be saved, the ones saved so far are deleted so that none of them
+// is persisted.
+func (p *preParamsStorage) SaveBatch(
+	pps []*PreParams,
+) ([]*PersistedPreParams, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	persisted := make([]*PersistedPreParams, 0, len(pps))
+	for _, pp := range pps {
+		persistedPreParams, err := p.save(pp)
+		if err != nil {
+			for _, saved := range persisted {
+				if err := p.persistence.Delete(
+					PreParamsDirName,
+					saved.ID,
+				); err != nil {
+					p.logger.Errorf(
+						"could not delete preparams [%s] of failed batch: [%v]",
+						saved.ID,
+						err,
+					)
+				}
+			}
+
+			return nil, err
+		}
+
+		persisted = append(persisted, persistedPreParams)
+	}
+
+	return persisted, nil
+}
+
+// save saves provided PreParams to the storage. Must be called with the
+// mutex held.
+func (p *preParamsStorage) save(pp *PreParams) (*PersistedPreParams, error) {
 	ppBytes, err := pp.Marshal()
 	if err != nil {
 		return nil, fmt.Errorf("marshalling of the preparams failed: [%v]", err)
@@ -153,7 +405,7 @@ func (p *preParamsStorage) Save(pp *PreParams) (*PersistedPreParams, error) {
 
 	if err := p.persistence.Save(
 		ppBytes,
-		dirName,
+		PreParamsDirName,
 		fileName,
 	); err != nil {
 		return nil, fmt.Errorf("saving preparams failed: [%w]", err)
@@ -162,6 +414,37 @@ func (p *preParamsStorage) Save(pp *PreParams) (*PersistedPreParams, error) {
 	return &PersistedPreParams{Data: *pp, ID: fileName}, nil
 }
 
+// IsPlaintextPreParams returns true if the given content is valid PreParams
+// persisted without encryption. It is used to migrate such entries to the
+// encrypted storage.
+func IsPlaintextPreParams(content []byte) bool {
+	preParams := &PreParams{}
+	if err := preParams.Unmarshal(content); err != nil {
+		return false
+	}
+
+	if !preParams.data.ValidateWithProof() {
+		return false
+	}
+
+	// Unmarshal sets missing numbers to zero so empty or unrelated content
+	// could pass the validation above.
+	for _, number := range []*big.Int{
+		preParams.data.PaillierSK.N,
+		preParams.data.NTildei,
+		preParams.data.H1i,
+		preParams.data.H2i,
+		preParams.data.P,
+		preParams.data.Q,
+	} {
+		if number.Sign() <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Deletes provided PreParams from the storage.
 func (p *preParamsStorage) Delete(pp *PersistedPreParams) error {
 	p.mutex.Lock()
@@ -169,16 +452,22 @@ func (p *preParamsStorage) Delete(pp *PersistedPreParams) error {
 
 	p.logger.Debugf("deleting preparams [%s]...", pp.ID)
 
-	return p.persistence.Delete(dirName, pp.ID)
+	return p.persistence.Delete(PreParamsDirName, pp.ID)
 }
 
 // ReadAll reads all the PreParams stored in the storage and returns them as a
-// slice.
+// slice. Corrupted entries are moved to the quarantine directory and
+// duplicated entries are removed from the storage. The outcome is available
+// as the storage report.
 func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
 	allPreParams := make([]*PersistedPreParams, 0)
+	contentHashes := make(map[string][sha256.Size]byte)
+	// Corrupted entries are quarantined once all descriptors are read so
+	// that the storage directory is not modified while it is being read.
+	corrupted := make([]*corruptedPreParams, 0)
 
 	descriptorsChan, errorsChan := p.persistence.ReadAll()
 
@@ -193,8 +482,8 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 
 	go func() {
 		for descriptor := range descriptorsChan {
-			// Read only the files located in the `dirName` subdirectory.
-			if descriptor.Directory() != dirName {
+			// Read only the files located in the `PreParamsDirName` subdirectory.
+			if descriptor.Directory() != PreParamsDirName {
 				continue
 			}
 
@@ -206,6 +495,10 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 					descriptor.Directory(),
 					err,
 				)
+				corrupted = append(corrupted, &corruptedPreParams{
+					id:     descriptor.Name(),
+					reason: fmt.Sprintf("could not read file: [%v]", err),
+				})
 				continue
 			}
 
@@ -217,6 +510,11 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 					descriptor.Directory(),
 					err,
 				)
+				corrupted = append(corrupted, &corruptedPreParams{
+					id:      descriptor.Name(),
+					content: content,
+					reason:  fmt.Sprintf("could not unmarshal file: [%v]", err),
+				})
 				continue
 			}
 			// Validate recovered PreParams with the same function that is used
@@ -228,10 +526,16 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 					descriptor.Name(),
 					descriptor.Directory(),
 				)
+				corrupted = append(corrupted, &corruptedPreParams{
+					id:      descriptor.Name(),
+					content: content,
+					reason:  "validation failed",
+				})
 				continue
 			}
 
 			persistedPreParams.ID = descriptor.Name()
+			contentHashes[persistedPreParams.ID] = sha256.Sum256(content)
 
 			allPreParams = append(allPreParams, persistedPreParams)
 		}
@@ -257,5 +561,161 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 
 	wg.Wait()
 
+	report := &PreParamsStorageReport{
+		Discarded: make([]*DiscardedPreParams, 0),
+	}
+
+	for _, entry := range corrupted {
+		report.Discarded = append(report.Discarded, p.quarantine(entry))
+	}
+
+	allPreParams, duplicates := p.compact(allPreParams, contentHashes)
+	report.Discarded = append(report.Discarded, duplicates...)
+
+	report.Loaded = len(allPreParams)
+
+	for _, discarded := range report.Discarded {
+		p.logger.Warnf("discarded persisted preparams [%v]", discarded)
+	}
+
+	if len(report.Discarded) > 0 {
+		p.logger.Warnf(
+			"discarded [%d] persisted preparams; loaded [%d] preparams",
+			len(report.Discarded),
+			report.Loaded,
+		)
+	}
+
+	p.report = report
+
 	return allPreParams, nil
 }
+
+// rawMover is implemented by persistence handles able to move an entry
+// between directories without reading its content.
+type rawMover interface {
+	Move(fromDirectory string, name string, toDirectory string) error
+}
+
+// quarantine moves the corrupted entry from the storage directory to the
+// quarantine directory so that it is not read again but can still be
+// inspected. Entries whose content could not be read are moved as-is if the
+// persistence handle supports it. Otherwise, they are left in place.
+func (p *preParamsStorage) quarantine(
+	entry *corruptedPreParams,
+) *DiscardedPreParams {
+	discarded := &DiscardedPreParams{
+		ID:     entry.id,
+		Reason: entry.reason,
+	}
+
+	if entry.content == nil {
+		mover, ok := p.persistence.(rawMover)
+		if !ok {
+			p.logger.Errorf(
+				"could not quarantine unreadable preparams [%s]; "+
+					"persistence does not support moving entries",
+				entry.id,
+			)
+			return discarded
+		}
+
+		if err := mover.Move(
+			PreParamsDirName,
+			entry.id,
+			quarantineDirName,
+		); err != nil {
+			p.logger.Errorf(
+				"could not quarantine preparams [%s]: [%v]",
+				entry.id,
+				err,
+			)
+			return discarded
+		}
+
+		discarded.Quarantined = true
+		discarded.Removed = true
+
+		return discarded
+	}
+
+	if err := p.persistence.Save(
+		entry.content,
+		quarantineDirName,
+		entry.id,
+	); err != nil {
+		p.logger.Errorf(
+			"could not quarantine preparams [%s]: [%v]",
+			entry.id,
+			err,
+		)
+		return discarded
+	}
+
+	discarded.Quarantined = true
+
+	if err := p.persistence.Delete(PreParamsDirName, entry.id); err != nil {
+		p.logger.Errorf(
+			"could not delete corrupted preparams [%s]: [%v]",
+			entry.id,
+			err,
+		)
+		return discarded
+	}
+
+	discarded.Removed = true
+
+	return discarded
+}
+
+// compact deletes duplicated entries from the storage directory. The
+// earliest entry of the duplicated content is kept. The preParams slice must
+// be sorted by the creation timestamp. Returns the remaining entries and the
+// discarded duplicates.
+func (p *preParamsStorage) compact(
+	preParams []*PersistedPreParams,
+	contentHashes map[string][sha256.Size]byte,
+) ([]*PersistedPreParams, []*DiscardedPreParams) {
+	remaining := make([]*PersistedPreParams, 0, len(preParams))
+	duplicates := make([]*DiscardedPreParams, 0)
+	kept := make(map[[sha256.Size]byte]string)
+
+	for _, pp := range preParams {
+		hash := contentHashes[pp.ID]
+
+		original, ok := kept[hash]
+		if !ok {
+			kept[hash] = pp.ID
+			remaining = append(remaining, pp)
+			continue
+		}
+
+		discarded := &DiscardedPreParams{
+			ID:     pp.ID,
+			Reason: fmt.Sprintf("duplicate of [%s]", original),
+		}
+
+		if err := p.persistence.Delete(PreParamsDirName, pp.ID); err != nil {
+			p.logger.Errorf(
+				"could not delete duplicated preparams [%s]: [%v]",
+				pp.ID,
+				err,
+			)
+		} else {
+			discarded.Removed = true
+		}
+
+		duplicates = append(duplicates, discarded)
+	}
+
+	return remaining, duplicates
+}
+
+// Report returns the report of the last ReadAll call. Nil if the storage
+// was not read yet.
+func (p *preParamsStorage) Report() *PreParamsStorageReport {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.report
+}
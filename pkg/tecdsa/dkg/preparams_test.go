@@ -0,0 +1,446 @@
+package dkg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+)
+
+func TestPreParamsPoolStats(t *testing.T) {
+	var tests = map[string]struct {
+		stats                  *PreParamsPoolStats
+		expectedGenerationRate float64
+		expectedRefillEstimate time.Duration
+	}{
+		"nothing generated yet": {
+			stats: &PreParamsPoolStats{
+				Count:           0,
+				TargetCount:     10,
+				GenerationDelay: 10 * time.Second,
+			},
+			expectedGenerationRate: 0,
+			expectedRefillEstimate: 0,
+		},
+		"pool being refilled": {
+			stats: &PreParamsPoolStats{
+				Count:                     7,
+				TargetCount:               10,
+				GeneratedCount:            20,
+				AverageGenerationDuration: 50 * time.Second,
+				GenerationDelay:           10 * time.Second,
+			},
+			expectedGenerationRate: 60,
+			expectedRefillEstimate: 3 * time.Minute,
+		},
+		"pool full": {
+			stats: &PreParamsPoolStats{
+				Count:                     10,
+				TargetCount:               10,
+				GeneratedCount:            10,
+				AverageGenerationDuration: 20 * time.Minute,
+			},
+			expectedGenerationRate: 3,
+			expectedRefillEstimate: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			if test.expectedGenerationRate != test.stats.GenerationRate() {
+				t.Errorf(
+					"unexpected generation rate\nexpected: %v\nactual:   %v",
+					test.expectedGenerationRate,
+					test.stats.GenerationRate(),
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"refill estimate",
+				int(test.expectedRefillEstimate),
+				int(test.stats.RefillEstimate()),
+			)
+		})
+	}
+}
+
+func TestIsPlaintextPreParams(t *testing.T) {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	preParamsBytes, err := newPreParams(&testData[0].LocalPreParams).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		content  []byte
+		expected bool
+	}{
+		"valid pre-params": {
+			content:  preParamsBytes,
+			expected: true,
+		},
+		"empty content": {
+			content:  []byte{},
+			expected: false,
+		},
+		"malformed content": {
+			content:  []byte{0xff},
+			expected: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testutils.AssertBoolsEqual(
+				t,
+				"plaintext pre-params",
+				test.expected,
+				IsPlaintextPreParams(test.content),
+			)
+		})
+	}
+}
+
+func TestPreParamsStorage_ReadAll(t *testing.T) {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(2)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	handle := &mockPersistenceHandle{}
+	storage := newPreParamsStorage(handle, &testutils.MockLogger{})
+
+	first := newPreParams(&testData[0].LocalPreParams)
+	second := newPreParams(&testData[1].LocalPreParams)
+	second.creationTimestamp = first.creationTimestamp.Add(time.Second)
+
+	persistedFirst, err := storage.Save(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	persistedSecond, err := storage.Save(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstBytes, err := first.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same pre-parameters persisted twice, e.g. by a copied directory.
+	handle.add(&mockDescriptor{
+		name:      "pp_duplicate",
+		directory: PreParamsDirName,
+		content:   firstBytes,
+	})
+	handle.add(&mockDescriptor{
+		name:      "pp_malformed",
+		directory: PreParamsDirName,
+		content:   []byte{0xff},
+	})
+	handle.add(&mockDescriptor{
+		name:       "pp_unreadable",
+		directory:  PreParamsDirName,
+		contentErr: fmt.Errorf("permission denied"),
+	})
+	// Entries of other directories are not read.
+	handle.add(&mockDescriptor{
+		name:      "other",
+		directory: "other",
+		content:   []byte{0xff},
+	})
+
+	loaded, err := storage.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadedIDs := make([]string, 0)
+	for _, pp := range loaded {
+		loadedIDs = append(loadedIDs, pp.ID)
+	}
+	expectedLoadedIDs := []string{persistedFirst.ID, persistedSecond.ID}
+	if !reflect.DeepEqual(expectedLoadedIDs, loadedIDs) {
+		t.Errorf(
+			"unexpected loaded preparams\nexpected: %v\nactual:   %v",
+			expectedLoadedIDs,
+			loadedIDs,
+		)
+	}
+
+	report := storage.Report()
+
+	testutils.AssertIntsEqual(t, "loaded preparams", 2, report.Loaded)
+
+	if len(report.Discarded) != 3 {
+		t.Fatalf(
+			"unexpected number of discarded preparams: [%v]",
+			len(report.Discarded),
+		)
+	}
+
+	// The protobuf library deliberately makes its error messages unstable so
+	// only the prefix of the unmarshaling failure reason is compared.
+	malformedReasonPrefix := "could not unmarshal file: [failed to unmarshal pre params: "
+	if !strings.HasPrefix(report.Discarded[0].Reason, malformedReasonPrefix) {
+		t.Errorf(
+			"unexpected reason of discarding malformed preparams: [%v]",
+			report.Discarded[0].Reason,
+		)
+	}
+	report.Discarded[0].Reason = ""
+
+	expectedDiscarded := []*DiscardedPreParams{
+		{
+			ID:          "pp_malformed",
+			Quarantined: true,
+			Removed:     true,
+		},
+		{
+			ID:          "pp_unreadable",
+			Reason:      "could not read file: [permission denied]",
+			Quarantined: true,
+			Removed:     true,
+		},
+		{
+			ID:      "pp_duplicate",
+			Reason:  fmt.Sprintf("duplicate of [%s]", persistedFirst.ID),
+			Removed: true,
+		},
+	}
+	if !reflect.DeepEqual(expectedDiscarded, report.Discarded) {
+		t.Errorf(
+			"unexpected discarded preparams\nexpected: %v\nactual:   %v",
+			expectedDiscarded,
+			report.Discarded,
+		)
+	}
+
+	remaining := make(map[string][]string)
+	for _, descriptor := range handle.descriptors {
+		remaining[descriptor.Directory()] = append(
+			remaining[descriptor.Directory()],
+			descriptor.Name(),
+		)
+	}
+	expectedRemaining := map[string][]string{
+		PreParamsDirName:  {persistedFirst.ID, persistedSecond.ID},
+		quarantineDirName: {"pp_unreadable", "pp_malformed"},
+		"other":           {"other"},
+	}
+	if !reflect.DeepEqual(expectedRemaining, remaining) {
+		t.Errorf(
+			"unexpected storage content\nexpected: %v\nactual:   %v",
+			expectedRemaining,
+			remaining,
+		)
+	}
+
+	// Discarded entries are removed so the next read is clean.
+	if _, err := storage.ReadAll(); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"discarded preparams on the second read",
+		0,
+		len(storage.Report().Discarded),
+	)
+}
+
+func TestPreParamsStorage_ReadAll_UnreadableWithoutMoveSupport(t *testing.T) {
+	handle := &mockPersistenceHandle{}
+	handle.add(&mockDescriptor{
+		name:       "pp_unreadable",
+		directory:  PreParamsDirName,
+		contentErr: fmt.Errorf("permission denied"),
+	})
+
+	// The wrapper hides the Move method of the mock handle.
+	storage := newPreParamsStorage(
+		struct{ persistence.BasicHandle }{handle},
+		&testutils.MockLogger{},
+	)
+
+	if _, err := storage.ReadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiscarded := []*DiscardedPreParams{
+		{
+			ID:     "pp_unreadable",
+			Reason: "could not read file: [permission denied]",
+		},
+	}
+	if !reflect.DeepEqual(expectedDiscarded, storage.Report().Discarded) {
+		t.Errorf(
+			"unexpected discarded preparams\nexpected: %v\nactual:   %v",
+			expectedDiscarded,
+			storage.Report().Discarded,
+		)
+	}
+
+	// The unreadable entry is left in place rather than deleted.
+	testutils.AssertIntsEqual(t, "stored entries", 1, len(handle.descriptors))
+}
+
+func TestPreParamsStorage_SaveBatch(t *testing.T) {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(2)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	first := newPreParams(&testData[0].LocalPreParams)
+	second := newPreParams(&testData[1].LocalPreParams)
+	second.creationTimestamp = first.creationTimestamp.Add(time.Second)
+
+	handle := &mockPersistenceHandle{}
+	storage := newPreParamsStorage(handle, &testutils.MockLogger{})
+
+	persisted, err := storage.SaveBatch([]*PreParams{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "persisted preparams", 2, len(persisted))
+	testutils.AssertIntsEqual(t, "stored entries", 2, len(handle.descriptors))
+
+	// None of the batch is persisted if any of its entries fails to save.
+	failingHandle := &failingSaveHandle{
+		mockPersistenceHandle: &mockPersistenceHandle{},
+		failAt:                2,
+	}
+	storage = newPreParamsStorage(failingHandle, &testutils.MockLogger{})
+
+	if _, err := storage.SaveBatch([]*PreParams{first, second}); err == nil {
+		t.Fatal("expected batch save error")
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"stored entries",
+		0,
+		len(failingHandle.descriptors),
+	)
+}
+
+// failingSaveHandle fails the save with the given sequence number, counting
+// from one.
+type failingSaveHandle struct {
+	*mockPersistenceHandle
+
+	failAt int
+	saves  int
+}
+
+func (fsh *failingSaveHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	fsh.saves++
+	if fsh.saves == fsh.failAt {
+		return fmt.Errorf("disk full")
+	}
+
+	return fsh.mockPersistenceHandle.Save(data, directory, name)
+}
+
+type mockPersistenceHandle struct {
+	descriptors []*mockDescriptor
+}
+
+func (mph *mockPersistenceHandle) add(descriptor *mockDescriptor) {
+	mph.descriptors = append(mph.descriptors, descriptor)
+}
+
+func (mph *mockPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	mph.add(&mockDescriptor{
+		name:      name,
+		directory: directory,
+		content:   data,
+	})
+
+	return nil
+}
+
+func (mph *mockPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	outputData := make(chan persistence.DataDescriptor, len(mph.descriptors))
+	outputErrors := make(chan error)
+
+	for _, descriptor := range mph.descriptors {
+		outputData <- descriptor
+	}
+
+	close(outputData)
+	close(outputErrors)
+
+	return outputData, outputErrors
+}
+
+func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
+	for i, descriptor := range mph.descriptors {
+		if descriptor.Directory() == directory && descriptor.Name() == name {
+			mph.descriptors = append(
+				mph.descriptors[:i],
+				mph.descriptors[i+1:]...,
+			)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("file not found")
+}
+
+func (mph *mockPersistenceHandle) Move(
+	fromDirectory string,
+	name string,
+	toDirectory string,
+) error {
+	for _, descriptor := range mph.descriptors {
+		if descriptor.Directory() == fromDirectory && descriptor.Name() == name {
+			descriptor.directory = toDirectory
+			return nil
+		}
+	}
+
+	return fmt.Errorf("file not found")
+}
+
+type mockDescriptor struct {
+	name       string
+	directory  string
+	content    []byte
+	contentErr error
+}
+
+func (md *mockDescriptor) Name() string {
+	return md.name
+}
+
+func (md *mockDescriptor) Directory() string {
+	return md.directory
+}
+
+func (md *mockDescriptor) Content() ([]byte, error) {
+	return md.content, md.contentErr
+}
@@ -57,6 +57,11 @@ func (skgm *symmetricKeyGeneratingMember) generateSymmetricKeys(
 		otherMember := ephemeralPubKeyMessage.senderID
 
 		if !skgm.isValidEphemeralPublicKeyMessage(ephemeralPubKeyMessage) {
+			skgm.recordEvidence(
+				ephemeralPubKeyMessage,
+				"message does not contain ephemeral public keys for all "+
+					"group members",
+			)
 			return fmt.Errorf(
 				"member [%v] sent invalid ephemeral public key message",
 				otherMember,
@@ -178,6 +183,7 @@ func (trtm *tssRoundTwoMember) tssRoundTwo(
 			true,
 		)
 		if tssErr != nil {
+			trtm.recordEvidence(tssRoundOneMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round one message "+
 					"from member [%v]: [%v]",
@@ -259,6 +265,7 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 			true,
 		)
 		if tssErr != nil {
+			trtm.recordEvidence(tssRoundTwoMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using the broadcast part of the "+
 					"TSS round two message from member [%v]: [%v]",
@@ -271,6 +278,10 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 		// for this member.
 		encryptedPeerPayload, ok := tssRoundTwoMessage.peersPayload[trtm.id]
 		if !ok {
+			trtm.recordEvidence(
+				tssRoundTwoMessage,
+				"message does not contain the P2P part for the reporter",
+			)
 			return nil, fmt.Errorf(
 				"no P2P part in the TSS round two message from member [%v]",
 				senderID,
@@ -288,6 +299,7 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 		// Decrypt the P2P part of the TSS round two message.
 		peerPayload, err := symmetricKey.Decrypt(encryptedPeerPayload)
 		if err != nil {
+			trtm.recordEvidence(tssRoundTwoMessage, err.Error())
 			return nil, fmt.Errorf(
 				"cannot decrypt P2P part of the TSS round two "+
 					"message from member [%v]: [%v]",
@@ -303,6 +315,7 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 			false,
 		)
 		if tssErr != nil {
+			trtm.recordEvidence(tssRoundTwoMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using the P2P part of the TSS round "+
 					"two message from member [%v]: [%v]",
@@ -355,6 +368,7 @@ func (fm *finalizingMember) tssFinalize(
 			true,
 		)
 		if tssErr != nil {
+			fm.recordEvidence(tssRoundThreeMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round three message "+
 					"from member [%v]: [%v]",
@@ -439,6 +453,7 @@ func (sm *signingMember) verifyDKGResultSignatures(
 			},
 		)
 		if err != nil {
+			sm.recordEvidence(message, err.Error())
 			sm.logger.Infof(
 				"[member:%v] verification of signature "+
 					"from sender [%d] failed: [%v]",
@@ -449,6 +464,7 @@ func (sm *signingMember) verifyDKGResultSignatures(
 			continue
 		}
 		if !isValid {
+			sm.recordEvidence(message, "invalid DKG result signature")
 			sm.logger.Infof(
 				"[member:%v] sender [%d] provided invalid signature",
 				sm.memberIndex,
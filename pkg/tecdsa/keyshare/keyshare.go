@@ -0,0 +1,84 @@
+// Package keyshare abstracts the usage of tECDSA private key shares so that
+// a share does not have to be held by the node itself. Implementations of
+// the Signer interface can keep the share in an external signer service or
+// a hardware security module (HSM) and expose only operations requiring it.
+// The local implementation, backed by a share persisted in the node's key
+// store, is the default one.
+package keyshare
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	tsslibcommon "github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+)
+
+// Signer performs operations requiring a tECDSA private key share without
+// exposing the share itself.
+type Signer interface {
+	// PublicKey returns the ECDSA public key of the signing group the
+	// private key share belongs to.
+	PublicKey() *ecdsa.PublicKey
+	// PartyKeys returns TSS party keys of all signing group members, in the
+	// order of their member indexes. Party keys are public.
+	PartyKeys() []*big.Int
+	// NewSigningParty returns a TSS party computing the partial signature
+	// of the given message with the private key share. The party uses the
+	// given parameters, sends its outgoing messages to the given out channel,
+	// and sends the final signature to the given end channel. In case of an
+	// external signer, the returned party relays messages to and from it.
+	NewSigningParty(
+		message *big.Int,
+		parameters *tss.Parameters,
+		out chan<- tss.Message,
+		end chan<- tsslibcommon.SignatureData,
+	) (tss.Party, error)
+}
+
+// LocalSigner is the Signer implementation using a private key share held
+// by the node, e.g. loaded from the node's key store.
+type LocalSigner struct {
+	privateKeyShare *tecdsa.PrivateKeyShare
+}
+
+// NewLocalSigner creates a new signer using the given private key share.
+func NewLocalSigner(privateKeyShare *tecdsa.PrivateKeyShare) *LocalSigner {
+	return &LocalSigner{privateKeyShare: privateKeyShare}
+}
+
+// PrivateKeyShare returns the private key share used by the signer.
+func (ls *LocalSigner) PrivateKeyShare() *tecdsa.PrivateKeyShare {
+	return ls.privateKeyShare
+}
+
+// PublicKey returns the ECDSA public key of the signing group the private
+// key share belongs to.
+func (ls *LocalSigner) PublicKey() *ecdsa.PublicKey {
+	return ls.privateKeyShare.PublicKey()
+}
+
+// PartyKeys returns TSS party keys of all signing group members.
+func (ls *LocalSigner) PartyKeys() []*big.Int {
+	return ls.privateKeyShare.Data().Ks
+}
+
+// NewSigningParty returns a local TSS signing party using the private key
+// share.
+func (ls *LocalSigner) NewSigningParty(
+	message *big.Int,
+	parameters *tss.Parameters,
+	out chan<- tss.Message,
+	end chan<- tsslibcommon.SignatureData,
+) (tss.Party, error) {
+	return signing.NewLocalParty(
+		message,
+		parameters,
+		ls.privateKeyShare.Data(),
+		out,
+		end,
+	), nil
+}
@@ -0,0 +1,78 @@
+package keyshare
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	tsslibcommon "github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+)
+
+const (
+	groupSize = 5
+	threshold = 2
+)
+
+func TestLocalSigner(t *testing.T) {
+	signers := loadLocalSigners(t)
+
+	signer := signers[0]
+
+	if !reflect.DeepEqual(
+		signer.PrivateKeyShare().PublicKey(),
+		signer.PublicKey(),
+	) {
+		t.Errorf("unexpected public key")
+	}
+
+	if !reflect.DeepEqual(
+		signer.PrivateKeyShare().Data().Ks,
+		signer.PartyKeys(),
+	) {
+		t.Errorf("unexpected party keys")
+	}
+
+	partyIDs := make(tss.UnSortedPartyIDs, len(signer.PartyKeys()))
+	for i, partyKey := range signer.PartyKeys() {
+		partyIDs[i] = tss.NewPartyID(partyKey.Text(10), "", partyKey)
+	}
+	sortedPartyIDs := tss.SortPartyIDs(partyIDs)
+
+	party, err := signer.NewSigningParty(
+		big.NewInt(100),
+		tss.NewParameters(
+			tecdsa.Curve,
+			tss.NewPeerContext(sortedPartyIDs),
+			sortedPartyIDs.FindByKey(signer.PartyKeys()[0]),
+			groupSize,
+			threshold,
+		),
+		make(chan tss.Message, groupSize),
+		make(chan tsslibcommon.SignatureData, 1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if party == nil {
+		t.Fatal("expected a signing party")
+	}
+}
+
+func loadLocalSigners(t *testing.T) []*LocalSigner {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(groupSize)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	signers := make([]*LocalSigner, len(testData))
+	for i := range testData {
+		signers[i] = NewLocalSigner(tecdsa.NewPrivateKeyShare(testData[i]))
+	}
+
+	return signers
+}
@@ -5,13 +5,13 @@ import (
 	"math/big"
 
 	tsslibcommon "github.com/bnb-chain/tss-lib/common"
-	"github.com/bnb-chain/tss-lib/ecdsa/signing"
 	"github.com/bnb-chain/tss-lib/tss"
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/crypto/ephemeral"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
 	"github.com/keep-network/keep-core/pkg/tecdsa/common"
+	"github.com/keep-network/keep-core/pkg/tecdsa/keyshare"
 	"golang.org/x/exp/slices"
 )
 
@@ -30,10 +30,13 @@ type member struct {
 	sessionID string
 	// Message that is the subject of the signing process.
 	message *big.Int
-	// tECDSA private key share of the member.
-	privateKeyShare *tecdsa.PrivateKeyShare
+	// Signer performing operations requiring the tECDSA private key share
+	// of the member.
+	keyShareSigner keyshare.Signer
 	// Instance of the member identity converter.
 	identityConverter *identityConverter
+	// Recorder of evidence of other members misbehavior.
+	evidenceRecorder common.EvidenceRecorder
 }
 
 // newMember creates a new member in an initial state
@@ -45,7 +48,8 @@ func newMember(
 	membershipValidator *group.MembershipValidator,
 	sessionID string,
 	message *big.Int,
-	privateKeyShare *tecdsa.PrivateKeyShare,
+	keyShareSigner keyshare.Signer,
+	evidenceRecorder common.EvidenceRecorder,
 ) *member {
 	return &member{
 		logger:              logger,
@@ -54,8 +58,9 @@ func newMember(
 		membershipValidator: membershipValidator,
 		sessionID:           sessionID,
 		message:             message,
-		privateKeyShare:     privateKeyShare,
-		identityConverter:   &identityConverter{keys: privateKeyShare.Data().Ks},
+		keyShareSigner:      keyShareSigner,
+		identityConverter:   &identityConverter{keys: keyShareSigner.PartyKeys()},
+		evidenceRecorder:    evidenceRecorder,
 	}
 }
 
@@ -64,6 +69,19 @@ func (m *member) inactiveMemberFilter() *group.InactiveMemberFilter {
 	return group.NewInactiveMemberFilter(m.logger, m.id, m.group)
 }
 
+// recordEvidence records the given message as evidence of misbehavior of
+// its sender.
+func (m *member) recordEvidence(message common.EvidenceMessage, reason string) {
+	common.RecordEvidence(
+		m.logger,
+		m.evidenceRecorder,
+		m.sessionID,
+		m.id,
+		message,
+		reason,
+	)
+}
+
 // shouldAcceptMessage indicates whether the given member should accept
 // a message from the given sender.
 func (m *member) shouldAcceptMessage(
@@ -123,7 +141,10 @@ type symmetricKeyGeneratingMember struct {
 }
 
 // initializeTssRoundOne returns a member to perform next protocol operations.
-func (skgm *symmetricKeyGeneratingMember) initializeTssRoundOne() *tssRoundOneMember {
+func (skgm *symmetricKeyGeneratingMember) initializeTssRoundOne() (
+	*tssRoundOneMember,
+	error,
+) {
 	// Set up the local TSS party using only operating members. This effectively
 	// removes all excluded members who were marked as disqualified at the
 	// beginning of the protocol.
@@ -144,13 +165,15 @@ func (skgm *symmetricKeyGeneratingMember) initializeTssRoundOne() *tssRoundOneMe
 	tssOutgoingMessagesChan := make(chan tss.Message, len(groupTssPartiesIDs))
 	tssResultChan := make(chan tsslibcommon.SignatureData, 1)
 
-	tssParty := signing.NewLocalParty(
+	tssParty, err := skgm.keyShareSigner.NewSigningParty(
 		skgm.message,
 		tssParameters,
-		skgm.privateKeyShare.Data(),
 		tssOutgoingMessagesChan,
 		tssResultChan,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create TSS signing party: [%w]", err)
+	}
 
 	return &tssRoundOneMember{
 		symmetricKeyGeneratingMember: skgm,
@@ -158,7 +181,7 @@ func (skgm *symmetricKeyGeneratingMember) initializeTssRoundOne() *tssRoundOneMe
 		tssParameters:                tssParameters,
 		tssOutgoingMessagesChan:      tssOutgoingMessagesChan,
 		tssResultChan:                tssResultChan,
-	}
+	}, nil
 }
 
 // tssRoundOneMember represents one member in a signing group performing the
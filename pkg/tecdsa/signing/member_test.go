@@ -14,6 +14,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/operator"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"github.com/keep-network/keep-core/pkg/tecdsa/keyshare"
 )
 
 func TestShouldAcceptMessage(t *testing.T) {
@@ -96,7 +97,8 @@ func TestShouldAcceptMessage(t *testing.T) {
 				membershipValdator,
 				"1",
 				big.NewInt(100),
-				tecdsa.NewPrivateKeyShare(testData[0]),
+				keyshare.NewLocalSigner(tecdsa.NewPrivateKeyShare(testData[0])),
+				nil,
 			)
 
 			filter := member.inactiveMemberFilter()
@@ -57,6 +57,11 @@ func (skgm *symmetricKeyGeneratingMember) generateSymmetricKeys(
 		otherMember := ephemeralPubKeyMessage.senderID
 
 		if !skgm.isValidEphemeralPublicKeyMessage(ephemeralPubKeyMessage) {
+			skgm.recordEvidence(
+				ephemeralPubKeyMessage,
+				"message does not contain ephemeral public keys for all "+
+					"group members",
+			)
 			return fmt.Errorf(
 				"member [%v] sent invalid ephemeral public key message",
 				otherMember,
@@ -202,6 +207,7 @@ func (trtm *tssRoundTwoMember) tssRoundTwo(
 			true,
 		)
 		if tssErr != nil {
+			trtm.recordEvidence(tssRoundOneMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using the broadcast part of the "+
 					"TSS round one message from member [%v]: [%v]",
@@ -214,6 +220,10 @@ func (trtm *tssRoundTwoMember) tssRoundTwo(
 		// for this member.
 		encryptedPeerPayload, ok := tssRoundOneMessage.peersPayload[trtm.id]
 		if !ok {
+			trtm.recordEvidence(
+				tssRoundOneMessage,
+				"message does not contain the P2P part for the reporter",
+			)
 			return nil, fmt.Errorf(
 				"no P2P part in the TSS round one message from member [%v]",
 				senderID,
@@ -231,6 +241,7 @@ func (trtm *tssRoundTwoMember) tssRoundTwo(
 		// Decrypt the P2P part of the TSS round one message.
 		peerPayload, err := symmetricKey.Decrypt(encryptedPeerPayload)
 		if err != nil {
+			trtm.recordEvidence(tssRoundOneMessage, err.Error())
 			return nil, fmt.Errorf(
 				"cannot decrypt P2P part of the TSS round one "+
 					"message from member [%v]: [%v]",
@@ -246,6 +257,7 @@ func (trtm *tssRoundTwoMember) tssRoundTwo(
 			false,
 		)
 		if tssErr != nil {
+			trtm.recordEvidence(tssRoundOneMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using the P2P part of the TSS round "+
 					"one message from member [%v]: [%v]",
@@ -322,6 +334,10 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 		// for this member.
 		encryptedPeerPayload, ok := tssRoundTwoMessage.peersPayload[trtm.id]
 		if !ok {
+			trtm.recordEvidence(
+				tssRoundTwoMessage,
+				"message does not contain the P2P part for the reporter",
+			)
 			return nil, fmt.Errorf(
 				"no P2P part in the TSS round two message from member [%v]",
 				senderID,
@@ -339,6 +355,7 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 		// Decrypt the P2P part of the TSS round two message.
 		peerPayload, err := symmetricKey.Decrypt(encryptedPeerPayload)
 		if err != nil {
+			trtm.recordEvidence(tssRoundTwoMessage, err.Error())
 			return nil, fmt.Errorf(
 				"cannot decrypt P2P part of the TSS round two "+
 					"message from member [%v]: [%v]",
@@ -354,6 +371,7 @@ func (trtm *tssRoundThreeMember) tssRoundThree(
 			false,
 		)
 		if tssErr != nil {
+			trtm.recordEvidence(tssRoundTwoMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using the P2P part of the TSS round "+
 					"two message from member [%v]: [%v]",
@@ -407,6 +425,7 @@ func (trfm *tssRoundFourMember) tssRoundFour(
 			true,
 		)
 		if tssErr != nil {
+			trfm.recordEvidence(tssRoundThreeMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round three message "+
 					"from member [%v]: [%v]",
@@ -460,6 +479,7 @@ func (trfm *tssRoundFiveMember) tssRoundFive(
 			true,
 		)
 		if tssErr != nil {
+			trfm.recordEvidence(tssRoundFourMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round four message "+
 					"from member [%v]: [%v]",
@@ -513,6 +533,7 @@ func (trsm *tssRoundSixMember) tssRoundSix(
 			true,
 		)
 		if tssErr != nil {
+			trsm.recordEvidence(tssRoundFiveMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round five message "+
 					"from member [%v]: [%v]",
@@ -566,6 +587,7 @@ func (trsm *tssRoundSevenMember) tssRoundSeven(
 			true,
 		)
 		if tssErr != nil {
+			trsm.recordEvidence(tssRoundSixMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round six message "+
 					"from member [%v]: [%v]",
@@ -619,6 +641,7 @@ func (trem *tssRoundEightMember) tssRoundEight(
 			true,
 		)
 		if tssErr != nil {
+			trem.recordEvidence(tssRoundSevenMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round seven message "+
 					"from member [%v]: [%v]",
@@ -672,6 +695,7 @@ func (trnm *tssRoundNineMember) tssRoundNine(
 			true,
 		)
 		if tssErr != nil {
+			trnm.recordEvidence(tssRoundEightMessage, tssErr.Error())
 			return nil, fmt.Errorf(
 				"cannot update using TSS round eight message "+
 					"from member [%v]: [%v]",
@@ -724,6 +748,7 @@ func (fm *finalizingMember) tssFinalize(
 			true,
 		)
 		if tssErr != nil {
+			fm.recordEvidence(tssRoundNineMessage, tssErr.Error())
 			return fmt.Errorf(
 				"cannot update using TSS round nine message "+
 					"from member [%v]: [%v]",
@@ -19,6 +19,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"github.com/keep-network/keep-core/pkg/tecdsa/keyshare"
 )
 
 // TODO: This file contains unit tests that stress each protocol phase
@@ -2160,7 +2161,7 @@ func TestTssFinalize(t *testing.T) {
 	}
 
 	message := members[0].message
-	publicKey := members[0].privateKeyShare.PublicKey()
+	publicKey := members[0].keyShareSigner.PublicKey()
 	signatures := make(map[string]bool)
 
 	// Assert that each member has a correct state.
@@ -2437,7 +2438,7 @@ func initializeEphemeralKeyPairGeneratingMembersGroup(
 				group:             signingGroup,
 				sessionID:         sessionID,
 				message:           big.NewInt(100),
-				privateKeyShare:   tecdsa.NewPrivateKeyShare(testData[i-1]),
+				keyShareSigner:    keyshare.NewLocalSigner(tecdsa.NewPrivateKeyShare(testData[i-1])),
 				identityConverter: &identityConverter{keys: testData[i-1].Ks},
 			},
 			ephemeralKeyPairs: make(map[group.MemberIndex]*ephemeral.KeyPair),
@@ -2528,10 +2529,16 @@ func initializeTssRoundOneMembersGroup(
 			)
 		}
 
-		tssRoundOneMembers = append(
-			tssRoundOneMembers,
-			member.initializeTssRoundOne(),
-		)
+		tssRoundOneMember, err := member.initializeTssRoundOne()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot initialize TSS round one for member [%v]: [%v]",
+				member.id,
+				err,
+			)
+		}
+
+		tssRoundOneMembers = append(tssRoundOneMembers, tssRoundOneMember)
 	}
 
 	return tssRoundOneMembers, nil
@@ -10,13 +10,30 @@ import (
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
-	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"github.com/keep-network/keep-core/pkg/tecdsa/common"
+	"github.com/keep-network/keep-core/pkg/tecdsa/keyshare"
 )
 
+// ExecutionError is returned by Execute if the signing protocol execution
+// fails. It holds the number of protocol states completed by the member
+// before the failure which helps to determine where the execution stalled.
+type ExecutionError struct {
+	CompletedStates int
+	Err             error
+}
+
+func (ee *ExecutionError) Error() string {
+	return ee.Err.Error()
+}
+
+func (ee *ExecutionError) Unwrap() error {
+	return ee.Err
+}
+
 // Execute runs the tECDSA signing protocol, given a message to sign,
 // broadcast channel to mediate with, a block counter used for time tracking,
-// a member index to use in the group, private key share, dishonest threshold,
-// and block height when signing protocol should start.
+// a member index to use in the group, signer of the private key share,
+// dishonest threshold, and block height when signing protocol should start.
 //
 // This function also supports signing execution with a subset of the signing
 // group by passing a non-empty excludedMembers slice holding the members that
@@ -27,15 +44,24 @@ func Execute(
 	message *big.Int,
 	sessionID string,
 	memberIndex group.MemberIndex,
-	privateKeyShare *tecdsa.PrivateKeyShare,
+	keyShareSigner keyshare.Signer,
 	groupSize int,
 	dishonestThreshold int,
 	excludedMembersIndexes []group.MemberIndex,
 	channel net.BroadcastChannel,
 	membershipValidator *group.MembershipValidator,
+	evidenceRecorder common.EvidenceRecorder,
 ) (*Result, error) {
 	logger.Debugf("[member:%v] initializing member", memberIndex)
 
+	// Keep envelopes signed by senders of the received messages so they
+	// can be recorded along with the evidence of misbehavior.
+	evidenceRecorder = common.WithSignedEnvelopes(
+		ctx,
+		evidenceRecorder,
+		channel,
+	)
+
 	member := newMember(
 		logger,
 		memberIndex,
@@ -44,7 +70,8 @@ func Execute(
 		membershipValidator,
 		sessionID,
 		message,
-		privateKeyShare,
+		keyShareSigner,
+		evidenceRecorder,
 	)
 
 	// Mark excluded members as disqualified in order to not exchange messages
@@ -65,12 +92,18 @@ func Execute(
 
 	lastState, err := stateMachine.Execute()
 	if err != nil {
-		return nil, err
+		return nil, &ExecutionError{
+			CompletedStates: stateMachine.CompletedStates(),
+			Err:             err,
+		}
 	}
 
 	finalizationState, ok := lastState.(*finalizationState)
 	if !ok {
-		return nil, fmt.Errorf("execution ended on state: %T", lastState)
+		return nil, &ExecutionError{
+			CompletedStates: stateMachine.CompletedStates(),
+			Err:             fmt.Errorf("execution ended on state: %T", lastState),
+		}
 	}
 
 	return finalizationState.result(), nil